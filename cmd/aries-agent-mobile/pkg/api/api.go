@@ -39,6 +39,9 @@ type AriesController interface {
 	// GetKMSController returns an implementation of KMSController
 	GetKMSController() (KMSController, error)
 
+	// GetVCWalletController returns an implementation of VCWalletController
+	GetVCWalletController() (VCWalletController, error)
+
 	// RegisterHandler registers handler for handling notifications
 	RegisterHandler(h Handler, topics string) string
 
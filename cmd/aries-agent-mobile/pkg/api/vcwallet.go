@@ -0,0 +1,53 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import "github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+
+// VCWalletController defines methods for the verifiable credential wallet controller.
+type VCWalletController interface {
+
+	// CreateProfile creates new wallet profile for given user.
+	CreateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// UpdateProfile updates an existing wallet profile for given user.
+	UpdateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Open unlocks given user's wallet and returns a token for subsequent use of wallet features.
+	Open(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Close locks given user's wallet.
+	Close(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Add adds given data model to wallet content store.
+	Add(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Remove deletes given content from wallet content store.
+	Remove(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Get returns wallet content by ID from wallet content store.
+	Get(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetAll gets all wallet content from wallet content store for given type.
+	GetAll(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Query runs credential queries against wallet credential contents and
+	// returns presentation containing credential results.
+	Query(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Issue adds proof to a Verifiable Credential using keys of the wallet.
+	Issue(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Prove produces a Verifiable Presentation using keys of the wallet.
+	Prove(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Verify takes a Verifiable Credential or Verifiable Presentation as input and verifies it.
+	Verify(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Derive derives a credential by selective disclosure and returns the derived credential.
+	Derive(request *models.RequestEnvelope) *models.ResponseEnvelope
+}
@@ -51,4 +51,10 @@ type VerifiableController interface {
 
 	// RemovePresentationByName will remove a VP that matches the specified name from the verifiable store.
 	RemovePresentationByName(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyCredential verifies the verifiable credential and returns a structured verification report.
+	VerifyCredential(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyPresentation verifies the verifiable presentation and returns a structured verification report.
+	VerifyPresentation(request *models.RequestEnvelope) *models.ResponseEnvelope
 }
@@ -0,0 +1,125 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+)
+
+// VCWallet contains necessary fields to support its operations.
+type VCWallet struct {
+	handlers map[string]command.Exec
+}
+
+// CreateProfile creates new wallet profile for given user.
+func (v *VCWallet) CreateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.CreateOrUpdateProfileRequest{}
+
+	return v.execute(vcwallet.CreateProfileMethod, request, &args)
+}
+
+// UpdateProfile updates an existing wallet profile for given user.
+func (v *VCWallet) UpdateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.CreateOrUpdateProfileRequest{}
+
+	return v.execute(vcwallet.UpdateProfileMethod, request, &args)
+}
+
+// Open unlocks given user's wallet and returns a token for subsequent use of wallet features.
+func (v *VCWallet) Open(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.UnlockWalletRequest{}
+
+	return v.execute(vcwallet.OpenMethod, request, &args)
+}
+
+// Close locks given user's wallet.
+func (v *VCWallet) Close(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.LockWalletRequest{}
+
+	return v.execute(vcwallet.CloseMethod, request, &args)
+}
+
+// Add adds given data model to wallet content store.
+func (v *VCWallet) Add(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.AddContentRequest{}
+
+	return v.execute(vcwallet.AddMethod, request, &args)
+}
+
+// Remove deletes given content from wallet content store.
+func (v *VCWallet) Remove(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.RemoveContentRequest{}
+
+	return v.execute(vcwallet.RemoveMethod, request, &args)
+}
+
+// Get returns wallet content by ID from wallet content store.
+func (v *VCWallet) Get(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.GetContentRequest{}
+
+	return v.execute(vcwallet.GetMethod, request, &args)
+}
+
+// GetAll gets all wallet content from wallet content store for given type.
+func (v *VCWallet) GetAll(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.GetAllContentRequest{}
+
+	return v.execute(vcwallet.GetAllMethod, request, &args)
+}
+
+// Query runs credential queries against wallet credential contents and
+// returns presentation containing credential results.
+func (v *VCWallet) Query(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.ContentQueryRequest{}
+
+	return v.execute(vcwallet.QueryMethod, request, &args)
+}
+
+// Issue adds proof to a Verifiable Credential using keys of the wallet.
+func (v *VCWallet) Issue(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.IssueRequest{}
+
+	return v.execute(vcwallet.IssueMethod, request, &args)
+}
+
+// Prove produces a Verifiable Presentation using keys of the wallet.
+func (v *VCWallet) Prove(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.ProveRequest{}
+
+	return v.execute(vcwallet.ProveMethod, request, &args)
+}
+
+// Verify takes a Verifiable Credential or Verifiable Presentation as input and verifies it.
+func (v *VCWallet) Verify(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.VerifyRequest{}
+
+	return v.execute(vcwallet.VerifyMethod, request, &args)
+}
+
+// Derive derives a credential by selective disclosure and returns the derived credential.
+func (v *VCWallet) Derive(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := vcwallet.DeriveRequest{}
+
+	return v.execute(vcwallet.DeriveMethod, request, &args)
+}
+
+func (v *VCWallet) execute(method string, request *models.RequestEnvelope, args interface{}) *models.ResponseEnvelope {
+	if err := json.Unmarshal(request.Payload, args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(v.handlers[method], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+)
+
+func getVCWalletController(t *testing.T) *VCWallet {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetVCWalletController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	v, ok := controller.(*VCWallet)
+	require.Equal(t, ok, true)
+
+	return v
+}
+
+func TestVCWallet_Operations(t *testing.T) {
+	controller := getVCWalletController(t)
+
+	tests := []struct {
+		method  string
+		payload string
+		call    func(*models.RequestEnvelope) *models.ResponseEnvelope
+	}{
+		{vcwallet.CreateProfileMethod, `{"userID":"user1","localKMSPassphrase":"pass"}`, controller.CreateProfile},
+		{vcwallet.UpdateProfileMethod, `{"userID":"user1","keyStoreURL":"url"}`, controller.UpdateProfile},
+		{vcwallet.OpenMethod, `{"userID":"user1","localKMSPassphrase":"pass"}`, controller.Open},
+		{vcwallet.CloseMethod, `{"userID":"user1"}`, controller.Close},
+		{vcwallet.AddMethod, `{"userID":"user1","auth":"token","contentType":"metadata","content":{}}`, controller.Add},
+		{vcwallet.RemoveMethod, `{"userID":"user1","auth":"token","contentType":"metadata","contentID":"id"}`,
+			controller.Remove},
+		{vcwallet.GetMethod, `{"userID":"user1","auth":"token","contentType":"metadata","contentID":"id"}`,
+			controller.Get},
+		{vcwallet.GetAllMethod, `{"userID":"user1","auth":"token","contentType":"metadata"}`, controller.GetAll},
+		{vcwallet.QueryMethod, `{"userID":"user1","auth":"token","query":[]}`, controller.Query},
+		{vcwallet.IssueMethod, `{"userID":"user1","auth":"token","credential":{}}`, controller.Issue},
+		{vcwallet.ProveMethod, `{"userID":"user1","auth":"token","storedCredentials":["id"]}`, controller.Prove},
+		{vcwallet.VerifyMethod, `{"userID":"user1","auth":"token","storedCredentialID":"id"}`, controller.Verify},
+		{vcwallet.DeriveMethod, `{"userID":"user1","auth":"token","storedCredentialID":"id"}`, controller.Derive},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" success", func(t *testing.T) {
+			mockResponse := `{"result":"` + tc.method + `"}`
+
+			fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+			controller.handlers[tc.method] = fakeHandler.exec
+
+			resp := tc.call(&models.RequestEnvelope{Payload: []byte(tc.payload)})
+			require.NotNil(t, resp)
+			require.Nil(t, resp.Error)
+			require.Equal(t, mockResponse, string(resp.Payload))
+		})
+
+		t.Run(tc.method+" invalid payload", func(t *testing.T) {
+			resp := tc.call(&models.RequestEnvelope{Payload: []byte("--")})
+			require.NotNil(t, resp)
+			require.NotNil(t, resp.Error)
+			require.Empty(t, resp.Payload)
+		})
+	}
+}
@@ -214,3 +214,35 @@ func (v *Verifiable) RemovePresentationByName(request *models.RequestEnvelope) *
 
 	return &models.ResponseEnvelope{Payload: response}
 }
+
+// VerifyCredential verifies the verifiable credential and returns a structured verification report.
+func (v *Verifiable) VerifyCredential(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdverifiable.Credential{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(v.handlers[cmdverifiable.VerifyCredentialCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// VerifyPresentation verifies the verifiable presentation and returns a structured verification report.
+func (v *Verifiable) VerifyPresentation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := cmdverifiable.Presentation{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(v.handlers[cmdverifiable.VerifyPresentationCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
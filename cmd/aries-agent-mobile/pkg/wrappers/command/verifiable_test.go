@@ -377,3 +377,43 @@ func TestVerifiable_RemovePresentationByName(t *testing.T) {
 			string(resp.Payload))
 	})
 }
+
+func TestVerifiable_VerifyCredential(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		v := getVerifiableController(t)
+
+		mockResponse := `{"report":{"verified":true}}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		v.handlers[cmdverifiable.VerifyCredentialCommandMethod] = fakeHandler.exec
+
+		payload := fmt.Sprintf(`{"verifiableCredential":%q}`, mockVC)
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := v.VerifyCredential(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
+
+func TestVerifiable_VerifyPresentation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		v := getVerifiableController(t)
+
+		mockResponse := `{"report":{"verified":true}}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		v.handlers[cmdverifiable.VerifyPresentationCommandMethod] = fakeHandler.exec
+
+		payload := mockVP
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := v.VerifyPresentation(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
@@ -17,6 +17,7 @@ import (
 	cmdmessaging "github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
 	cmdoob "github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	cmdpresproof "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	cmdvcwallet "github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
 	cmdvdr "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	cmdverifiable "github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	opdidexch "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
@@ -27,6 +28,7 @@ import (
 	opmessaging "github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
 	opoob "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	oppresproof "github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	opvcwallet "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vcwallet"
 	opvdr "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	opverifiable "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 )
@@ -50,6 +52,7 @@ func getControllerEndpoints() map[string]map[string]*endpoint {
 	allEndpoints[opmessaging.MsgServiceOperationID] = getMessagingEndpoints()
 	allEndpoints[opoob.OperationID] = getOutOfBandEndpoints()
 	allEndpoints[opkms.KmsOperationID] = getKMSEndpoints()
+	allEndpoints[opvcwallet.OperationID] = getVCWalletEndpoints()
 
 	return allEndpoints
 }
@@ -157,6 +160,14 @@ func getVerifiableEndpoints() map[string]*endpoint {
 			Path:   opverifiable.RemovePresentationByNamePath,
 			Method: http.MethodPost,
 		},
+		cmdverifiable.VerifyCredentialCommandMethod: {
+			Path:   opverifiable.VerifyCredentialPath,
+			Method: http.MethodPost,
+		},
+		cmdverifiable.VerifyPresentationCommandMethod: {
+			Path:   opverifiable.VerifyPresentationPath,
+			Method: http.MethodPost,
+		},
 	}
 }
 
@@ -435,3 +446,60 @@ func getKMSEndpoints() map[string]*endpoint {
 		},
 	}
 }
+
+func getVCWalletEndpoints() map[string]*endpoint {
+	return map[string]*endpoint{
+		cmdvcwallet.CreateProfileMethod: {
+			Path:   opvcwallet.CreateProfilePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.UpdateProfileMethod: {
+			Path:   opvcwallet.UpdateProfilePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.OpenMethod: {
+			Path:   opvcwallet.OpenPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.CloseMethod: {
+			Path:   opvcwallet.ClosePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.AddMethod: {
+			Path:   opvcwallet.AddPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.RemoveMethod: {
+			Path:   opvcwallet.RemovePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.GetMethod: {
+			Path:   opvcwallet.GetPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.GetAllMethod: {
+			Path:   opvcwallet.GetAllPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.QueryMethod: {
+			Path:   opvcwallet.QueryPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.IssueMethod: {
+			Path:   opvcwallet.IssuePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.ProveMethod: {
+			Path:   opvcwallet.ProvePath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.VerifyMethod: {
+			Path:   opvcwallet.VerifyPath,
+			Method: http.MethodPost,
+		},
+		cmdvcwallet.DeriveMethod: {
+			Path:   opvcwallet.DerivePath,
+			Method: http.MethodPost,
+		},
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+)
+
+// VCWallet contains necessary fields to support its operations.
+type VCWallet struct {
+	httpClient httpClient
+	endpoints  map[string]*endpoint
+
+	URL   string
+	Token string
+}
+
+// CreateProfile creates new wallet profile for given user.
+func (v *VCWallet) CreateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.CreateProfileMethod)
+}
+
+// UpdateProfile updates an existing wallet profile for given user.
+func (v *VCWallet) UpdateProfile(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.UpdateProfileMethod)
+}
+
+// Open unlocks given user's wallet and returns a token for subsequent use of wallet features.
+func (v *VCWallet) Open(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.OpenMethod)
+}
+
+// Close locks given user's wallet.
+func (v *VCWallet) Close(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.CloseMethod)
+}
+
+// Add adds given data model to wallet content store.
+func (v *VCWallet) Add(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.AddMethod)
+}
+
+// Remove deletes given content from wallet content store.
+func (v *VCWallet) Remove(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.RemoveMethod)
+}
+
+// Get returns wallet content by ID from wallet content store.
+func (v *VCWallet) Get(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.GetMethod)
+}
+
+// GetAll gets all wallet content from wallet content store for given type.
+func (v *VCWallet) GetAll(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.GetAllMethod)
+}
+
+// Query runs credential queries against wallet credential contents and
+// returns presentation containing credential results.
+func (v *VCWallet) Query(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.QueryMethod)
+}
+
+// Issue adds proof to a Verifiable Credential using keys of the wallet.
+func (v *VCWallet) Issue(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.IssueMethod)
+}
+
+// Prove produces a Verifiable Presentation using keys of the wallet.
+func (v *VCWallet) Prove(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.ProveMethod)
+}
+
+// Verify takes a Verifiable Credential or Verifiable Presentation as input and verifies it.
+func (v *VCWallet) Verify(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.VerifyMethod)
+}
+
+// Derive derives a credential by selective disclosure and returns the derived credential.
+func (v *VCWallet) Derive(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return v.createRespEnvelope(request, vcwallet.DeriveMethod)
+}
+
+func (v *VCWallet) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
+	return exec(&restOperation{
+		url:        v.URL,
+		token:      v.Token,
+		httpClient: v.httpClient,
+		endpoint:   v.endpoints[endpoint],
+		request:    request,
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/vcwallet"
+)
+
+func getVCWalletController(t *testing.T) *VCWallet {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetVCWalletController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	v, ok := controller.(*VCWallet)
+	require.Equal(t, ok, true)
+
+	return v
+}
+
+func TestVCWallet_Operations(t *testing.T) {
+	controller := getVCWalletController(t)
+
+	tests := []struct {
+		path    string
+		payload string
+		call    func(*models.RequestEnvelope) *models.ResponseEnvelope
+	}{
+		{vcwallet.CreateProfilePath, `{"userID":"user1","localKMSPassphrase":"pass"}`, controller.CreateProfile},
+		{vcwallet.UpdateProfilePath, `{"userID":"user1","keyStoreURL":"url"}`, controller.UpdateProfile},
+		{vcwallet.OpenPath, `{"userID":"user1","localKMSPassphrase":"pass"}`, controller.Open},
+		{vcwallet.ClosePath, `{"userID":"user1"}`, controller.Close},
+		{vcwallet.AddPath, `{"userID":"user1","auth":"token","contentType":"metadata","content":{}}`, controller.Add},
+		{vcwallet.RemovePath, `{"userID":"user1","auth":"token","contentType":"metadata","contentID":"id"}`,
+			controller.Remove},
+		{vcwallet.GetPath, `{"userID":"user1","auth":"token","contentType":"metadata","contentID":"id"}`,
+			controller.Get},
+		{vcwallet.GetAllPath, `{"userID":"user1","auth":"token","contentType":"metadata"}`, controller.GetAll},
+		{vcwallet.QueryPath, `{"userID":"user1","auth":"token","query":[]}`, controller.Query},
+		{vcwallet.IssuePath, `{"userID":"user1","auth":"token","credential":{}}`, controller.Issue},
+		{vcwallet.ProvePath, `{"userID":"user1","auth":"token","storedCredentials":["id"]}`, controller.Prove},
+		{vcwallet.VerifyPath, `{"userID":"user1","auth":"token","storedCredentialID":"id"}`, controller.Verify},
+		{vcwallet.DerivePath, `{"userID":"user1","auth":"token","storedCredentialID":"id"}`, controller.Derive},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			mockResponse := `{"result":"` + tc.path + `"}`
+
+			controller.httpClient = &mockHTTPClient{
+				data:   mockResponse,
+				method: http.MethodPost, url: mockAgentURL + tc.path,
+			}
+
+			resp := tc.call(&models.RequestEnvelope{Payload: []byte(tc.payload)})
+			require.NotNil(t, resp)
+			require.Nil(t, resp.Error)
+			require.Equal(t, mockResponse, string(resp.Payload))
+		})
+	}
+}
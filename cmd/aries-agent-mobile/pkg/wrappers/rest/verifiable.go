@@ -85,6 +85,16 @@ func (vr *Verifiable) RemovePresentationByName(request *models.RequestEnvelope)
 	return vr.createRespEnvelope(request, cmdverifiable.RemovePresentationByNameCommandMethod)
 }
 
+// VerifyCredential verifies the verifiable credential and returns a structured verification report.
+func (vr *Verifiable) VerifyCredential(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return vr.createRespEnvelope(request, cmdverifiable.VerifyCredentialCommandMethod)
+}
+
+// VerifyPresentation verifies the verifiable presentation and returns a structured verification report.
+func (vr *Verifiable) VerifyPresentation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return vr.createRespEnvelope(request, cmdverifiable.VerifyPresentationCommandMethod)
+}
+
 func (vr *Verifiable) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
 	return exec(&restOperation{
 		url:        vr.URL,
@@ -388,3 +388,45 @@ func TestVerifiable_RemovePresentationByName(t *testing.T) {
 		require.Equal(t, mockResponse, string(resp.Payload))
 	})
 }
+
+func TestVerifiable_VerifyCredential(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		v := getVerifiableController(t)
+
+		mockResponse := `{"report":{"verified":true}}`
+		reqData := `{"verifiableCredential":"{}"}`
+
+		mockURL, err := parseURL(mockAgentURL, opverifiable.VerifyCredentialPath, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		v.httpClient = &mockHTTPClient{data: mockResponse, method: http.MethodPost, url: mockURL}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := v.VerifyCredential(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestVerifiable_VerifyPresentation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		v := getVerifiableController(t)
+
+		mockResponse := `{"report":{"verified":true}}`
+		reqData := `{"verifiablePresentation":{}}`
+
+		mockURL, err := parseURL(mockAgentURL, opverifiable.VerifyPresentationPath, reqData)
+		require.NoError(t, err, "failed to parse test url")
+
+		v.httpClient = &mockHTTPClient{data: mockResponse, method: http.MethodPost, url: mockURL}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := v.VerifyPresentation(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
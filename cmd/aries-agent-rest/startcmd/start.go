@@ -7,10 +7,13 @@ package startcmd
 
 import (
 	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -22,9 +25,11 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/component/storage/leveldb"
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	outofbandrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/msghandler"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
@@ -149,6 +154,22 @@ const (
 		" Possible values [true] [false]. Defaults to false if not set." +
 		" Alternatively, this can be set with the following environment variable: " + agentAutoAcceptEnvKey
 
+	// auto accept rules flag.
+	agentAutoAcceptRulesFlagName  = "auto-accept-rules"
+	agentAutoAcceptRulesEnvKey    = "ARIESD_AUTO_ACCEPT_RULES"
+	agentAutoAcceptRulesFlagUsage = "Path to a JSON file with the auto-accept rules," +
+		" e.g {\"rules\":[{\"id\":\"trusted\",\"protocol\":\"issue-credential\",\"issuer_dids\":[\"did:example:issuer\"]}]}." +
+		" The rules are evaluated in order against the protocol action events, the first matching rule wins." +
+		" Alternatively, this can be set with the following environment variable: " + agentAutoAcceptRulesEnvKey
+
+	// out-of-band invitation base URL flag.
+	agentOOBInvitationBaseURLFlagName  = "oob-invitation-base-url"
+	agentOOBInvitationBaseURLEnvKey    = "ARIESD_OOB_INVITATION_BASE_URL"
+	agentOOBInvitationBaseURLFlagUsage = "Public URL of the agent the out-of-band invitations are served at," +
+		" e.g https://example.com serves the invitations at https://example.com/ssi?id=<invitation id>." +
+		" The shortened invitation URLs are public, they are not protected by the API token." +
+		" Alternatively, this can be set with the following environment variable: " + agentOOBInvitationBaseURLEnvKey
+
 	// transport return route option flag.
 	agentTransportReturnRouteFlagName  = "transport-return-route"
 	agentTransportReturnRouteEnvKey    = "ARIESD_TRANSPORT_RETURN_ROUTE"
@@ -173,9 +194,11 @@ type agentParameters struct {
 	host, defaultLabel, transportReturnRoute       string
 	tlsCertFile, tlsKeyFile                        string
 	token                                          string
+	oobInvitationBaseURL                           string
 	webhookURLs, httpResolvers, outboundTransports []string
 	inboundHostInternals, inboundHostExternals     []string
 	autoAccept                                     bool
+	autoAcceptRules                                []autoaccept.Rule
 	msgHandler                                     command.MessageHandler
 	dbParam                                        *dbParam
 }
@@ -274,6 +297,11 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 				return err
 			}
 
+			autoAcceptRules, err := getAutoAcceptRules(cmd)
+			if err != nil {
+				return err
+			}
+
 			webhookURLs, err := getUserSetVars(cmd, agentWebhookFlagName, agentWebhookEnvKey, autoAccept)
 			if err != nil {
 				return err
@@ -296,6 +324,12 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 				return err
 			}
 
+			oobInvitationBaseURL, err := getUserSetVar(cmd, agentOOBInvitationBaseURLFlagName,
+				agentOOBInvitationBaseURLEnvKey, true)
+			if err != nil {
+				return err
+			}
+
 			tlsCertFile, err := getUserSetVar(cmd, agentTLSCertFileFlagName, agentTLSCertFileEnvKey, true)
 			if err != nil {
 				return err
@@ -318,7 +352,9 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 				httpResolvers:        httpResolvers,
 				outboundTransports:   outboundTransports,
 				autoAccept:           autoAccept,
+				autoAcceptRules:      autoAcceptRules,
 				transportReturnRoute: transportReturnRoute,
+				oobInvitationBaseURL: oobInvitationBaseURL,
 				tlsCertFile:          tlsCertFile,
 				tlsKeyFile:           tlsKeyFile,
 			}
@@ -375,6 +411,37 @@ func getAutoAcceptValue(cmd *cobra.Command) (bool, error) {
 	return strconv.ParseBool(v)
 }
 
+func getAutoAcceptRules(cmd *cobra.Command) ([]autoaccept.Rule, error) {
+	path, err := getUserSetVar(cmd, agentAutoAcceptRulesFlagName, agentAutoAcceptRulesEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return nil, nil
+	}
+
+	src, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auto-accept rules: %w", err)
+	}
+
+	var config struct {
+		Rules []autoaccept.Rule `json:"rules"`
+	}
+
+	if err = json.Unmarshal(src, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse auto-accept rules: %w", err)
+	}
+
+	// an empty list removes the stored rules
+	if config.Rules == nil {
+		config.Rules = []autoaccept.Rule{}
+	}
+
+	return config.Rules, nil
+}
+
 func createFlags(startCmd *cobra.Command) {
 	// agent host flag
 	startCmd.Flags().StringP(agentHostFlagName, agentHostFlagShorthand, "", agentHostFlagUsage)
@@ -417,6 +484,12 @@ func createFlags(startCmd *cobra.Command) {
 	// auto accept flag
 	startCmd.Flags().StringP(agentAutoAcceptFlagName, "", "", agentAutoAcceptFlagUsage)
 
+	// auto accept rules flag
+	startCmd.Flags().StringP(agentAutoAcceptRulesFlagName, "", "", agentAutoAcceptRulesFlagUsage)
+
+	// out-of-band invitation base URL flag
+	startCmd.Flags().StringP(agentOOBInvitationBaseURLFlagName, "", "", agentOOBInvitationBaseURLFlagUsage)
+
 	// transport return route option flag
 	startCmd.Flags().StringP(agentTransportReturnRouteFlagName, "", "", agentTransportReturnRouteFlagUsage)
 
@@ -633,7 +706,8 @@ func startAgent(parameters *agentParameters) error {
 	// get all HTTP REST API handlers available for controller API
 	handlers, err := controller.GetRESTHandlers(ctx, controller.WithWebhookURLs(parameters.webhookURLs...),
 		controller.WithDefaultLabel(parameters.defaultLabel), controller.WithAutoAccept(parameters.autoAccept),
-		controller.WithMessageHandler(parameters.msgHandler))
+		controller.WithAutoAcceptRules(parameters.autoAcceptRules...), controller.WithMessageHandler(parameters.msgHandler),
+		controller.WithInvitationBaseURL(parameters.oobInvitationBaseURL))
 	if err != nil {
 		return fmt.Errorf("failed to start aries agent rest on port [%s], failed to get rest service api :  %w",
 			parameters.host, err)
@@ -641,12 +715,23 @@ func startAgent(parameters *agentParameters) error {
 
 	router := mux.NewRouter()
 
+	// the shortened invitation URLs are public, the routes are matched before the protected ones
+	for _, handler := range handlers {
+		if handler.Path() == outofbandrest.ShortInvitation {
+			router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+		}
+	}
+
+	apiRouter := router.NewRoute().Subrouter()
+
 	if parameters.token != "" {
-		router.Use(authorizationMiddleware(parameters.token))
+		apiRouter.Use(authorizationMiddleware(parameters.token))
 	}
 
 	for _, handler := range handlers {
-		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+		if handler.Path() != outofbandrest.ShortInvitation {
+			apiRouter.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+		}
 	}
 
 	logger.Infof("Starting aries agent rest on host [%s]", parameters.host)
@@ -431,6 +431,83 @@ func TestStartCmdWithLogLevel(t *testing.T) {
 	})
 }
 
+func TestStartCmdWithAutoAcceptRules(t *testing.T) {
+	newArgs := func(rulesPath string) []string {
+		return []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + agentInboundHostExternalFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + databaseTypeFlagName,
+			databaseTypeMemOption,
+			"--" + agentWebhookFlagName,
+			"",
+			"--" + agentAutoAcceptRulesFlagName,
+			rulesPath,
+		}
+	}
+
+	writeRules := func(t *testing.T, content string) string {
+		t.Helper()
+
+		file, err := ioutil.TempFile("", "rules*.json")
+		require.NoError(t, err)
+
+		t.Cleanup(func() { require.NoError(t, os.Remove(file.Name())) })
+
+		_, err = file.WriteString(content)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		return file.Name()
+	}
+
+	t.Run("success", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(newArgs(writeRules(t, `{"rules":[{"id":"trusted","protocol":"issue-credential",
+			"msg_types":["offer-credential"],"issuer_dids":["did:example:issuer"]}]}`)))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(newArgs("invalid.json"))
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read auto-accept rules")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(newArgs(writeRules(t, "{")))
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse auto-accept rules")
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(newArgs(writeRules(t, `{"rules":[{"id":"1","protocol":"unknown"}]}`)))
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "set auto-accept rules")
+	})
+}
+
 func TestStartCmdWithoutWebhookURLAndAutoAccept(t *testing.T) {
 	startCmd, err := Cmd(&mockServer{})
 	require.NoError(t, err)
@@ -470,6 +547,8 @@ func TestStartCmdValidArgs(t *testing.T) {
 		"agent",
 		"--" + agentWebhookFlagName,
 		"",
+		"--" + agentOOBInvitationBaseURLFlagName,
+		"https://example.com",
 	}
 	startCmd.SetArgs(args)
 
@@ -734,6 +813,41 @@ func TestStartAriesWithAuthorization(t *testing.T) {
 	})
 }
 
+func TestStartAriesWithShortInvitationURL(t *testing.T) {
+	testHostURL := randomURL()
+	testInboundHostURL := randomURL()
+
+	go func() {
+		parameters := &agentParameters{
+			server:               &HTTPServer{},
+			host:                 testHostURL,
+			token:                "ABCD",
+			inboundHostInternals: []string{httpProtocol + "@" + testInboundHostURL},
+			dbParam:              &dbParam{dbType: databaseTypeMemOption},
+			defaultLabel:         "x",
+			oobInvitationBaseURL: "https://example.com",
+		}
+
+		err := startAgent(parameters)
+		require.NoError(t, err)
+		require.FailNow(t, agentUnexpectedExitErrMsg+": "+err.Error())
+	}()
+
+	waitForServerToStart(t, testHostURL, testInboundHostURL)
+
+	// the shortened invitation URL is served without the authorization token
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/ssi?id=unknown", testHostURL), nil)
+	require.NoError(t, err)
+
+	runRequestTests(t, []requestTestParams{{
+		name:           "get unknown invitation",
+		r:              req,
+		expectedStatus: http.StatusNotFound,
+	}})
+
+	validateUnauthorized(t, testHostURL, "")
+}
+
 func TestStoreProvider(t *testing.T) {
 	t.Run("test invalid database type", func(t *testing.T) {
 		_, err := createAriesAgent(&agentParameters{dbParam: &dbParam{dbType: "data1"}})
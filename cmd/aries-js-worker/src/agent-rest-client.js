@@ -41,6 +41,11 @@ const pkgs = {
             method: "POST",
             pathParam:"id"
         },
+        SetConnectionMetadata: {
+            path: "/connections/{id}/metadata",
+            method: "POST",
+            pathParam:"id"
+        },
         QueryConnectionByID: {
             path: "/connections/{id}",
             method: "GET",
@@ -184,6 +189,14 @@ const pkgs = {
             path: "/verifiable/presentations",
             method: "GET",
         },
+        VerifyCredential: {
+            path: "/verifiable/credential/verify",
+            method: "POST"
+        },
+        VerifyPresentation: {
+            path: "/verifiable/presentation/verify",
+            method: "POST"
+        },
     },
     introduce:{
         Actions: {
@@ -199,6 +212,11 @@ const pkgs = {
             method: "POST",
             pathParam:"piid"
         },
+        RecipientStatuses: {
+            path: "/introduce/{piid}/recipients",
+            method: "GET",
+            pathParam:"piid"
+        },
         SendProposalWithOOBInvitation: {
             path: "/introduce/send-proposal-with-oob-invitation",
             method: "POST",
@@ -261,6 +279,28 @@ const pkgs = {
             path: "/outofband/accept-invitation",
             method: "POST",
         },
+        InvitationURL: {
+            path: "/outofband/invitation-url",
+            method: "POST",
+        },
+        ResolveInvitationURL: {
+            path: "/outofband/resolve-invitation-url",
+            method: "POST",
+        },
+        Invitations: {
+            path: "/outofband/invitations",
+            method: "GET",
+        },
+        GetInvitation: {
+            path: "/outofband/invitations/{invitation_id}",
+            method: "GET",
+            pathParam: "invitation_id"
+        },
+        RevokeInvitation: {
+            path: "/outofband/invitations/{invitation_id}/revoke",
+            method: "POST",
+            pathParam: "invitation_id"
+        },
     },
     issuecredential: {
         Actions: {
@@ -392,8 +432,82 @@ const pkgs = {
         ImportKey: {
             path: "/kms/import",
             method: "POST",
+        },
+        ListKeys: {
+            path: "/kms/keys?keyType={keyType}&label={label}&purpose={purpose}&did={did}&createdBefore={createdBefore}",
+            method: "GET",
+            queryStrings: ["keyType", "label", "purpose", "did", "createdBefore"]
+        },
+        DeleteKey: {
+            path: "/kms/keys/{keyID}",
+            method: "DELETE",
+            pathParam:"keyID"
+        },
+        SetKeyMetadata: {
+            path: "/kms/keys/{keyID}/metadata",
+            method: "POST",
+            pathParam:"keyID"
+        },
+        GetKeyMetadata: {
+            path: "/kms/keys/{keyID}/metadata",
+            method: "GET",
+            pathParam:"keyID"
         }
     },
+    vcwallet: {
+        CreateProfile: {
+            path: "/vcwallet/create-profile",
+            method: "POST",
+        },
+        UpdateProfile: {
+            path: "/vcwallet/update-profile",
+            method: "POST",
+        },
+        Open: {
+            path: "/vcwallet/open",
+            method: "POST",
+        },
+        Close: {
+            path: "/vcwallet/close",
+            method: "POST",
+        },
+        Add: {
+            path: "/vcwallet/add",
+            method: "POST",
+        },
+        Remove: {
+            path: "/vcwallet/remove",
+            method: "POST",
+        },
+        Get: {
+            path: "/vcwallet/get",
+            method: "POST",
+        },
+        GetAll: {
+            path: "/vcwallet/getall",
+            method: "POST",
+        },
+        Query: {
+            path: "/vcwallet/query",
+            method: "POST",
+        },
+        Issue: {
+            path: "/vcwallet/issue",
+            method: "POST",
+        },
+        Prove: {
+            path: "/vcwallet/prove",
+            method: "POST",
+        },
+        Verify: {
+            path: "/vcwallet/verify",
+            method: "POST",
+        },
+        Derive: {
+            path: "/vcwallet/derive",
+            method: "POST",
+        },
+    },
 }
 
 /**
@@ -318,6 +318,16 @@ const Aries = function (opts) {
              */
             declineRequest: function (req) {
                 return invoke(aw, pending, this.pkgname, "DeclineRequest", req, "timeout while declining a request")
+            },
+
+            /**
+             * RecipientStatuses returns the state of the introduction per recipient of the proposals.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            recipientStatuses: function (req) {
+                return invoke(aw, pending, this.pkgname, "RecipientStatuses", req, "timeout while getting recipient statuses")
             }
         },
 
@@ -375,6 +385,55 @@ const Aries = function (opts) {
             acceptInvitation: async function (req) {
                 return invoke(aw, pending, this.pkgname, "AcceptInvitation", req, "timeout while accepting an invitation")
             },
+
+            /**
+             * InvitationURL returns the URL, the shortened URL and the QR code payload of the invitation created by the agent.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            invitationURL: async function (req) {
+                return invoke(aw, pending, this.pkgname, "InvitationURL", req, "timeout while getting an invitation url")
+            },
+
+            /**
+             * ResolveInvitationURL returns the invitation of the invitation URL or the shortened invitation URL.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            resolveInvitationURL: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ResolveInvitationURL", req, "timeout while resolving an invitation url")
+            },
+
+            /**
+             * Invitations returns the records of the invitations created by the agent.
+             *
+             * @returns {Promise<Object>}
+             */
+            invitations: async function () {
+                return invoke(aw, pending, this.pkgname, "Invitations", null, "timeout while getting invitations")
+            },
+
+            /**
+             * GetInvitation returns the record of the invitation created by the agent.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            getInvitation: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetInvitation", req, "timeout while getting an invitation")
+            },
+
+            /**
+             * RevokeInvitation revokes the invitation created by the agent.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            revokeInvitation: async function (req) {
+                return invoke(aw, pending, this.pkgname, "RevokeInvitation", req, "timeout while revoking an invitation")
+            },
         },
 
         /**
@@ -698,6 +757,16 @@ const Aries = function (opts) {
                 return invoke(aw, pending, this.pkgname, "RemoveConnection", req, "timeout while removing invitation")
             },
 
+            /**
+             * Replaces the metadata of a connection.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            setConnectionMetadata: async function (req) {
+                return invoke(aw, pending, this.pkgname, "SetConnectionMetadata", req, "timeout while setting connection metadata")
+            },
+
             /**
              * Retrieves a connection by ID.
              *
@@ -1044,6 +1113,26 @@ const Aries = function (opts) {
             getPresentations: async function () {
                 return invoke(aw, pending, this.pkgname, "GetPresentations", {}, "timeout while retrieving presentations")
             },
+
+            /**
+             * Verifies a verifiable credential and returns the verification report.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyCredential: async function (req) {
+                return invoke(aw, pending, this.pkgname, "VerifyCredential", req, "timeout while verifying credential")
+            },
+
+            /**
+             * Verifies a verifiable presentation and its credentials and returns the verification report.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyPresentation: async function (req) {
+                return invoke(aw, pending, this.pkgname, "VerifyPresentation", req, "timeout while verifying presentation")
+            },
         },
 
         /**
@@ -1070,6 +1159,180 @@ const Aries = function (opts) {
             importKey: async function (req) {
                 return invoke(aw, pending, this.pkgname, "ImportKey", req, "timeout while importing key")
             },
+
+            /**
+             * List the metadata of the keys matching the filter (keyType, label, purpose, did, createdBefore).
+             *
+             * @returns {Promise<Object>}
+             */
+            listKeys: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ListKeys", req, "timeout while listing keys")
+            },
+
+            /**
+             * Delete key.
+             *
+             * @returns {Promise<Object>}
+             */
+            deleteKey: async function (req) {
+                return invoke(aw, pending, this.pkgname, "DeleteKey", req, "timeout while deleting key")
+            },
+
+            /**
+             * Set key metadata (label, purpose, did and usage).
+             *
+             * @returns {Promise<Object>}
+             */
+            setKeyMetadata: async function (req) {
+                return invoke(aw, pending, this.pkgname, "SetKeyMetadata", req, "timeout while setting key metadata")
+            },
+
+            /**
+             * Get key metadata.
+             *
+             * @returns {Promise<Object>}
+             */
+            getKeyMetadata: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetKeyMetadata", req, "timeout while getting key metadata")
+            },
+        },
+
+        /**
+         * Verifiable Credential Wallet - Refer to [OpenAPI spec](docs/rest/openapi_spec.md#generate-openapi-spec) for
+         * input params and output return json values.
+         */
+        vcwallet: {
+            pkgname: "vcwallet",
+
+            /**
+             * Creates new wallet profile for given user.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            createProfile: async function (req) {
+                return invoke(aw, pending, this.pkgname, "CreateProfile", req, "timeout while creating wallet profile")
+            },
+
+            /**
+             * Updates an existing wallet profile for given user.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            updateProfile: async function (req) {
+                return invoke(aw, pending, this.pkgname, "UpdateProfile", req, "timeout while updating wallet profile")
+            },
+
+            /**
+             * Unlocks given user's wallet and returns a token for subsequent use of wallet features.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            open: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Open", req, "timeout while opening wallet")
+            },
+
+            /**
+             * Locks given user's wallet.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            close: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Close", req, "timeout while closing wallet")
+            },
+
+            /**
+             * Adds given data model to wallet content store.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            add: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Add", req, "timeout while adding content to wallet")
+            },
+
+            /**
+             * Removes given content from wallet content store.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            remove: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Remove", req, "timeout while removing content from wallet")
+            },
+
+            /**
+             * Gets a wallet content by ID from wallet content store.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            get: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Get", req, "timeout while getting content from wallet")
+            },
+
+            /**
+             * Gets all wallet contents from wallet content store for given content type.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            getAll: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetAll", req, "timeout while getting all contents from wallet")
+            },
+
+            /**
+             * Runs credential queries against wallet credential contents and returns presentation containing credential results.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            query: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Query", req, "timeout while querying wallet")
+            },
+
+            /**
+             * Adds proof to a Verifiable Credential using keys of the wallet.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            issue: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Issue", req, "timeout while issuing credential from wallet")
+            },
+
+            /**
+             * Produces a Verifiable Presentation using keys of the wallet.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            prove: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Prove", req, "timeout while producing presentation from wallet")
+            },
+
+            /**
+             * Verifies a Verifiable Credential or Verifiable Presentation.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verify: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Verify", req, "timeout while verifying credential/presentation from wallet")
+            },
+
+            /**
+             * Derives a credential by selective disclosure and returns the derived credential.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            derive: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Derive", req, "timeout while deriving credential from wallet")
+            },
         },
     }
 
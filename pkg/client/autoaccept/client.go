@@ -0,0 +1,359 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// StoreName is the name of the store keeping the rules and the consents.
+	StoreName = "autoaccept"
+
+	rulesKey   = "rules"
+	consentKey = "consent_"
+
+	mimeTypeApplicationLdJSON = "application/ld+json"
+	ed25519Signature2018      = "Ed25519Signature2018"
+	authenticationPurpose     = "authentication"
+)
+
+var logger = log.New("aries-framework/client/autoaccept")
+
+// Provider contains dependencies for the auto-accept client and is typically created by using aries.Context().
+type Provider interface {
+	StorageProvider() storage.Provider
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	VDRegistry() vdrapi.Registry
+}
+
+// Client evaluates the auto-accept rules against the action events.
+type Client struct {
+	store  storage.Store
+	kms    kms.KeyManager
+	crypto crypto.Crypto
+	vdr    vdrapi.Registry
+	rules  []Rule
+	mu     sync.RWMutex
+}
+
+// New returns new instance of the auto-accept client, the rules are loaded from the store.
+func New(ctx Provider) (*Client, error) {
+	store, err := ctx.StorageProvider().OpenStore(StoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	err = ctx.StorageProvider().SetStoreConfig(StoreName, storage.StoreConfiguration{TagNames: []string{consentKey}})
+	if err != nil {
+		return nil, fmt.Errorf("set store config: %w", err)
+	}
+
+	client := &Client{
+		store:  store,
+		kms:    ctx.KMS(),
+		crypto: ctx.Crypto(),
+		vdr:    ctx.VDRegistry(),
+	}
+
+	src, err := store.Get(rulesKey)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return client, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get rules: %w", err)
+	}
+
+	if err = json.Unmarshal(src, &client.rules); err != nil {
+		return nil, fmt.Errorf("unmarshal rules: %w", err)
+	}
+
+	return client, nil
+}
+
+// SetRules validates and persists the rules, the given rules replace the existing ones.
+func (c *Client) SetRules(rules []Rule) error {
+	ids := make(map[string]struct{}, len(rules))
+
+	for i := range rules {
+		if err := rules[i].validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		if _, ok := ids[rules[i].ID]; ok {
+			return fmt.Errorf("rule %d: duplicate ID %q", i, rules[i].ID)
+		}
+
+		ids[rules[i].ID] = struct{}{}
+	}
+
+	src, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("marshal rules: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.store.Put(rulesKey, src); err != nil {
+		return fmt.Errorf("save rules: %w", err)
+	}
+
+	c.rules = append([]Rule(nil), rules...)
+
+	return nil
+}
+
+// Rules returns the rules in the order they are evaluated.
+func (c *Client) Rules() []Rule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]Rule(nil), c.rules...)
+}
+
+// SaveConsent saves the consent to present the given credentials
+// for the requests of the given presentation definition.
+func (c *Client) SaveConsent(consent *Consent) error {
+	if err := consent.validate(); err != nil {
+		return fmt.Errorf("validate consent: %w", err)
+	}
+
+	src, err := json.Marshal(consent)
+	if err != nil {
+		return fmt.Errorf("marshal consent: %w", err)
+	}
+
+	return c.store.Put(consentKey+consent.ID, src, storage.Tag{Name: consentKey})
+}
+
+// RemoveConsent removes the consent by ID.
+func (c *Client) RemoveConsent(id string) error {
+	return c.store.Delete(consentKey + id)
+}
+
+// Consents returns all saved consents.
+func (c *Client) Consents() ([]Consent, error) {
+	records, err := c.store.Query(consentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the store: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var consents []Consent
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, errValue := records.Value()
+		if errValue != nil {
+			return nil, fmt.Errorf("failed to get value: %w", errValue)
+		}
+
+		var consent Consent
+		if errUnmarshal := json.Unmarshal(value, &consent); errUnmarshal != nil {
+			return nil, fmt.Errorf("unmarshal: %w", errUnmarshal)
+		}
+
+		consents = append(consents, consent)
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return consents, nil
+}
+
+// Filter handles the action events matching the rules, the rest of the events are passed to the returned channel.
+func (c *Client) Filter(actions <-chan service.DIDCommAction) <-chan service.DIDCommAction {
+	events := make(chan service.DIDCommAction)
+
+	go func() {
+		defer close(events)
+
+		for action := range actions {
+			if !c.Handle(action) {
+				events <- action
+			}
+		}
+	}()
+
+	return events
+}
+
+// Handle continues or stops the action event according to the first matching rule.
+// It returns false if none of the rules matches the event.
+func (c *Client) Handle(action service.DIDCommAction) bool {
+	event := newActionEvent(action)
+
+	c.mu.RLock()
+	rules := c.rules
+	c.mu.RUnlock()
+
+	for i := range rules {
+		if !rules[i].match(event) {
+			continue
+		}
+
+		if rules[i].Reject {
+			logger.Debugf("rule %s: stop %s", rules[i].ID, event.msgType)
+
+			action.Stop(fmt.Errorf("rejected by the auto-accept rule %s", rules[i].ID))
+
+			return true
+		}
+
+		if !rules[i].RequireConsent {
+			logger.Debugf("rule %s: continue %s", rules[i].ID, event.msgType)
+
+			action.Continue(&service.Empty{})
+
+			return true
+		}
+
+		consent, err := c.consent(event)
+		if err != nil {
+			logger.Warnf("rule %s: consent: %s", rules[i].ID, err)
+
+			continue
+		}
+
+		if consent != nil {
+			logger.Debugf("rule %s: present %s", rules[i].ID, event.request.definitionID)
+
+			action.Continue(presentproof.WithMultiOptions(
+				presentproof.WithPresentation(consent.presentation()),
+				presentproof.WithAddProofFn(c.addProofFn(consent.VerificationMethod, event.request)),
+			))
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// consent returns the consent matching the presentation request of the event.
+func (c *Client) consent(event *actionEvent) (*Consent, error) {
+	if event.request == nil {
+		return nil, nil
+	}
+
+	consents, err := c.Consents()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range consents {
+		if consents[i].match(event) {
+			return &consents[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// presentation returns the present-proof message attaching the consented credentials,
+// the presentation-exchange middleware creates the verifiable presentation of the requested ones.
+func (c *Consent) presentation() *presentproof.Presentation {
+	presentation := &presentproof.Presentation{}
+
+	for i := range c.Credentials {
+		presentation.PresentationsAttach = append(presentation.PresentationsAttach, decorator.Attachment{
+			ID:       uuid.New().String(),
+			MimeType: mimeTypeApplicationLdJSON,
+			Data:     decorator.AttachmentData{JSON: c.Credentials[i]},
+		})
+	}
+
+	return presentation
+}
+
+// addProofFn returns the function signing the presentation with the given Ed25519 holder key,
+// the proof is bound to the challenge and the domain of the request so that it cannot be replayed to other verifiers.
+func (c *Client) addProofFn(verificationMethod string,
+	request *presentationRequest) func(*verifiable.Presentation) error {
+	return func(presentation *verifiable.Presentation) error {
+		holder := strings.Split(verificationMethod, "#")[0]
+
+		kh, err := c.keyHandle(holder, verificationMethod)
+		if err != nil {
+			return err
+		}
+
+		presentation.Holder = holder
+
+		return presentation.AddLinkedDataProof(&verifiable.LinkedDataProofContext{
+			SignatureType:           ed25519Signature2018,
+			Suite:                   ed25519signature2018.New(suite.WithSigner(suite.NewCryptoSigner(c.crypto, kh))),
+			SignatureRepresentation: verifiable.SignatureJWS,
+			VerificationMethod:      verificationMethod,
+			Purpose:                 authenticationPurpose,
+			Challenge:               request.challenge,
+			Domain:                  request.domain,
+		}, jsonld.WithDocumentLoader(presexch.CachingJSONLDLoader()))
+	}
+}
+
+// keyHandle returns the KMS key handle of the holder verification method.
+func (c *Client) keyHandle(holder, verificationMethod string) (interface{}, error) {
+	docResolution, err := c.vdr.Resolve(holder)
+	if err != nil {
+		return nil, fmt.Errorf("resolve holder DID: %w", err)
+	}
+
+	doc := docResolution.DIDDocument
+
+	for i := range doc.VerificationMethod {
+		id := doc.VerificationMethod[i].ID
+		if strings.HasPrefix(id, "#") {
+			id = doc.ID + id
+		}
+
+		if id != verificationMethod {
+			continue
+		}
+
+		kid, err := localkms.CreateKID(doc.VerificationMethod[i].Value, kms.ED25519Type)
+		if err != nil {
+			return nil, fmt.Errorf("create kid: %w", err)
+		}
+
+		return c.kms.Get(kid)
+	}
+
+	return nil, fmt.Errorf("verification method %s not found", verificationMethod)
+}
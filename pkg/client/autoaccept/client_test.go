@@ -0,0 +1,469 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+const (
+	issuerDID   = "did:example:issuer"
+	verifierDID = "did:example:verifier"
+	holderDID   = "did:example:holder"
+
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+)
+
+type props struct{ theirDID string }
+
+func (p props) TheirDID() string { return p.theirDID }
+
+func (p props) All() map[string]interface{} { return map[string]interface{}{"theirDID": p.theirDID} }
+
+type result struct {
+	args interface{}
+	err  error
+}
+
+func newAction(protocol string, msg interface{}, theirDID string) (service.DIDCommAction, chan result) {
+	done := make(chan result, 1)
+
+	return service.DIDCommAction{
+		ProtocolName: protocol,
+		Message:      service.NewDIDCommMsgMap(msg),
+		Continue: func(args interface{}) {
+			done <- result{args: args}
+		},
+		Stop: func(err error) {
+			done <- result{err: err}
+		},
+		Properties: props{theirDID: theirDID},
+	}, done
+}
+
+func offer(types ...interface{}) *issuecredential.OfferCredential {
+	return &issuecredential.OfferCredential{
+		Type: issuecredential.OfferCredentialMsgType,
+		OffersAttach: []decorator.Attachment{{
+			Data: decorator.AttachmentData{JSON: map[string]interface{}{"type": types}},
+		}},
+	}
+}
+
+func requestPresentation(definitionID string) *presentproof.RequestPresentation {
+	return &presentproof.RequestPresentation{
+		Type:    presentproof.RequestPresentationMsgType,
+		Formats: []presentproof.Format{{AttachID: "pd", Format: peDefinitionFormat}},
+		RequestPresentationsAttach: []decorator.Attachment{{
+			ID: "pd",
+			Data: decorator.AttachmentData{JSON: map[string]interface{}{
+				"challenge":               "challenge",
+				"domain":                  "domain",
+				"presentation_definition": map[string]interface{}{"id": definitionID},
+			}},
+		}},
+	}
+}
+
+func newConsent(id, definitionID string, theirDIDs ...string) *Consent {
+	return &Consent{
+		ID:                       id,
+		PresentationDefinitionID: definitionID,
+		TheirDIDs:                theirDIDs,
+		Credentials:              []json.RawMessage{json.RawMessage(`{"type":"VerifiableCredential"}`)},
+		VerificationMethod:       holderDID + "#key-1",
+	}
+}
+
+func newClient(t *testing.T) *Client {
+	t.Helper()
+
+	client, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Success (rules are loaded)", func(t *testing.T) {
+		provider := &mockprovider.Provider{StorageProviderValue: mem.NewProvider()}
+
+		client, err := New(provider)
+		require.NoError(t, err)
+		require.Empty(t, client.Rules())
+
+		rules := []Rule{{ID: "1", Protocol: didexchange.DIDExchange}}
+		require.NoError(t, client.SetRules(rules))
+
+		client, err = New(provider)
+		require.NoError(t, err)
+		require.Equal(t, rules, client.Rules())
+	})
+
+	t.Run("Error open store", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{StorageProviderValue: &mockstore.MockStoreProvider{
+			ErrOpenStoreHandle: errors.New("test error"),
+		}})
+		require.EqualError(t, err, "open store: test error")
+	})
+
+	t.Run("Error get rules", func(t *testing.T) {
+		provider := mockstore.NewMockStoreProvider()
+		provider.Store.ErrGet = errors.New("test error")
+
+		_, err := New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.EqualError(t, err, "get rules: test error")
+	})
+
+	t.Run("Error unmarshal rules", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		store, err := provider.OpenStore(StoreName)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(rulesKey, []byte("{")))
+
+		_, err = New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.Contains(t, err.Error(), "unmarshal rules")
+	})
+}
+
+func TestClient_SetRules(t *testing.T) {
+	client := newClient(t)
+
+	tests := []struct {
+		name string
+		rule Rule
+		err  string
+	}{
+		{name: "empty ID", rule: Rule{Protocol: didexchange.DIDExchange}, err: "rule 0: empty ID"},
+		{name: "unsupported protocol", rule: Rule{ID: "1", Protocol: "routing"}, err: `unsupported protocol "routing"`},
+		{
+			name: "credential types",
+			rule: Rule{ID: "1", Protocol: presentproof.Name, CredentialTypes: []string{"type"}},
+			err:  "credential types are supported by the issue-credential protocol only",
+		},
+		{
+			name: "consent",
+			rule: Rule{ID: "1", Protocol: issuecredential.Name, RequireConsent: true},
+			err:  "consent is supported by the present-proof protocol only",
+		},
+		{
+			name: "consent rejected",
+			rule: Rule{ID: "1", Protocol: presentproof.Name, RequireConsent: true, Reject: true},
+			err:  "consent cannot be required by a reject rule",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := client.SetRules([]Rule{tc.rule})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	t.Run("duplicate ID", func(t *testing.T) {
+		err := client.SetRules([]Rule{
+			{ID: "1", Protocol: didexchange.DIDExchange},
+			{ID: "1", Protocol: issuecredential.Name},
+		})
+		require.EqualError(t, err, `rule 1: duplicate ID "1"`)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		provider := mockstore.NewMockStoreProvider()
+		provider.Store.ErrPut = errors.New("test error")
+
+		c, err := New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.NoError(t, err)
+
+		err = c.SetRules([]Rule{{ID: "1", Protocol: didexchange.DIDExchange}})
+		require.EqualError(t, err, "save rules: test error")
+		require.Empty(t, c.Rules())
+	})
+}
+
+func TestClient_Consents(t *testing.T) {
+	client := newClient(t)
+
+	require.EqualError(t, client.SaveConsent(&Consent{}), "validate consent: empty ID")
+	require.EqualError(t, client.SaveConsent(&Consent{ID: "1"}),
+		"validate consent: empty presentation definition ID")
+	require.EqualError(t, client.SaveConsent(&Consent{ID: "1", PresentationDefinitionID: "def"}),
+		"validate consent: empty credentials")
+
+	consent := *newConsent("1", "def")
+	consent.VerificationMethod = ""
+	require.EqualError(t, client.SaveConsent(&consent), "validate consent: empty verification method")
+
+	consent = *newConsent("1", "def")
+	require.NoError(t, client.SaveConsent(&consent))
+
+	consents, err := client.Consents()
+	require.NoError(t, err)
+	require.Equal(t, []Consent{consent}, consents)
+
+	require.NoError(t, client.RemoveConsent(consent.ID))
+
+	consents, err = client.Consents()
+	require.NoError(t, err)
+	require.Empty(t, consents)
+}
+
+func TestClient_Handle(t *testing.T) {
+	t.Run("Credential offer", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.SetRules([]Rule{{
+			ID:              "trusted",
+			Protocol:        issuecredential.Name,
+			MsgTypes:        []string{"offer-credential"},
+			TheirDIDs:       []string{issuerDID},
+			CredentialTypes: []string{"UniversityDegreeCredential"},
+		}, {
+			ID:       "reject",
+			Protocol: issuecredential.Name,
+			Reject:   true,
+		}}))
+
+		action, done := newAction(issuecredential.Name,
+			offer("VerifiableCredential", "UniversityDegreeCredential"), issuerDID)
+		require.True(t, client.Handle(action))
+		require.Equal(t, result{args: &service.Empty{}}, <-done)
+
+		action, done = newAction(issuecredential.Name, offer("VerifiableCredential", "Other"), issuerDID)
+		require.True(t, client.Handle(action))
+		require.EqualError(t, (<-done).err, "rejected by the auto-accept rule reject")
+
+		action, done = newAction(issuecredential.Name,
+			offer("VerifiableCredential", "UniversityDegreeCredential"), "did:example:other")
+		require.True(t, client.Handle(action))
+		require.Error(t, (<-done).err)
+	})
+
+	t.Run("Credential issuer", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.SetRules([]Rule{{
+			ID:         "trusted",
+			Protocol:   issuecredential.Name,
+			IssuerDIDs: []string{issuerDID},
+		}}))
+
+		msg := offer("UniversityDegreeCredential")
+		msg.OffersAttach = append(msg.OffersAttach, decorator.Attachment{
+			Data: decorator.AttachmentData{JSON: map[string]interface{}{
+				"credential": map[string]interface{}{"issuer": map[string]interface{}{"id": issuerDID}},
+			}},
+		})
+		msg.OffersAttach[0].Data.JSON = map[string]interface{}{"issuer": issuerDID}
+
+		// the issuer of the credentials is matched, not the DID of the connection
+		action, done := newAction(issuecredential.Name, msg, "did:example:other")
+		require.True(t, client.Handle(action))
+		require.Equal(t, result{args: &service.Empty{}}, <-done)
+
+		msg.OffersAttach[1].Data.JSON = map[string]interface{}{"issuer": "did:example:other"}
+
+		action, _ = newAction(issuecredential.Name, msg, issuerDID)
+		require.False(t, client.Handle(action))
+
+		action, _ = newAction(issuecredential.Name, offer(), issuerDID)
+		require.False(t, client.Handle(action))
+	})
+
+	t.Run("ld-proof-vc-detail offer", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.SetRules([]Rule{{
+			ID:              "trusted",
+			Protocol:        issuecredential.Name,
+			CredentialTypes: []string{"UniversityDegreeCredential"},
+		}}))
+
+		msg := offer()
+		msg.OffersAttach[0].Data.JSON = map[string]interface{}{
+			"credential": map[string]interface{}{"type": "UniversityDegreeCredential"},
+		}
+
+		action, done := newAction(issuecredential.Name, msg, issuerDID)
+		require.True(t, client.Handle(action))
+		require.NoError(t, (<-done).err)
+
+		action, _ = newAction(issuecredential.Name, &issuecredential.OfferCredential{
+			Type: issuecredential.OfferCredentialMsgType,
+		}, issuerDID)
+		require.False(t, client.Handle(action))
+	})
+
+	t.Run("Presentation consent", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.SetRules([]Rule{{
+			ID:             "consent",
+			Protocol:       presentproof.Name,
+			RequireConsent: true,
+		}}))
+
+		require.NoError(t, client.SaveConsent(newConsent("1", "def", verifierDID)))
+
+		action, done := newAction(presentproof.Name, requestPresentation("def"), verifierDID)
+		require.True(t, client.Handle(action))
+
+		opt, ok := (<-done).args.(presentproof.Opt)
+		require.True(t, ok)
+		require.NotNil(t, opt)
+
+		action, _ = newAction(presentproof.Name, requestPresentation("other"), verifierDID)
+		require.False(t, client.Handle(action))
+
+		action, _ = newAction(presentproof.Name, requestPresentation("def"), "did:example:other")
+		require.False(t, client.Handle(action))
+
+		// the request is not bound to the presentation-exchange format
+		msg := requestPresentation("def")
+		msg.Formats = nil
+
+		action, _ = newAction(presentproof.Name, msg, verifierDID)
+		require.False(t, client.Handle(action))
+	})
+
+	t.Run("DID exchange request", func(t *testing.T) {
+		client := newClient(t)
+		require.NoError(t, client.SetRules([]Rule{{
+			ID:        "known",
+			Protocol:  didexchange.DIDExchange,
+			TheirDIDs: []string{"did:example:alice"},
+		}}))
+
+		action := service.DIDCommAction{
+			ProtocolName: didexchange.DIDExchange,
+			Message: service.NewDIDCommMsgMap(&didexchange.Request{
+				Type: didexchange.RequestMsgType,
+				DID:  "did:example:alice",
+			}),
+			Continue: func(interface{}) {},
+		}
+		require.True(t, client.Handle(action))
+	})
+}
+
+func TestClient_Filter(t *testing.T) {
+	client := newClient(t)
+	require.NoError(t, client.SetRules([]Rule{{
+		ID:       "accept",
+		Protocol: issuecredential.Name,
+		MsgTypes: []string{issuecredential.OfferCredentialMsgType},
+	}}))
+
+	actions := make(chan service.DIDCommAction)
+	events := client.Filter(actions)
+
+	accepted, done := newAction(issuecredential.Name, offer(), issuerDID)
+	actions <- accepted
+
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	unhandled, _ := newAction(presentproof.Name, requestPresentation("def"), verifierDID)
+	actions <- unhandled
+
+	select {
+	case event := <-events:
+		require.Equal(t, presentproof.Name, event.ProtocolName)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	close(actions)
+
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestClient_addProofFn(t *testing.T) {
+	km, err := localkms.New("local-lock://custom/master/key/",
+		mockkms.NewProviderForKMS(mockstore.NewMockStoreProvider(), &noop.NoLock{}))
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	_, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.NoError(t, err)
+
+	vdr := &mockvdr.MockVDRegistry{ResolveValue: &did.Doc{
+		ID: holderDID,
+		VerificationMethod: []did.VerificationMethod{
+			*did.NewVerificationMethodFromBytes("#key-1", ed25519VerificationKey2018, holderDID, pubKey),
+		},
+	}}
+
+	client, err := New(&mockprovider.Provider{
+		StorageProviderValue: mem.NewProvider(),
+		KMSValue:             km,
+		CryptoValue:          cr,
+		VDRegistryValue:      vdr,
+	})
+	require.NoError(t, err)
+
+	request := &presentationRequest{definitionID: "def", challenge: "challenge", domain: "domain"}
+
+	t.Run("Success", func(t *testing.T) {
+		presentation, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		require.NoError(t, client.addProofFn(holderDID+"#key-1", request)(presentation))
+		require.Equal(t, holderDID, presentation.Holder)
+		require.Len(t, presentation.Proofs, 1)
+		require.Equal(t, "challenge", presentation.Proofs[0]["challenge"])
+		require.Equal(t, "domain", presentation.Proofs[0]["domain"])
+		require.Equal(t, holderDID+"#key-1", presentation.Proofs[0]["verificationMethod"])
+	})
+
+	t.Run("Unknown verification method", func(t *testing.T) {
+		presentation, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		require.EqualError(t, client.addProofFn(holderDID+"#key-2", request)(presentation),
+			"verification method did:example:holder#key-2 not found")
+	})
+
+	t.Run("Resolve error", func(t *testing.T) {
+		vdr.ResolveErr = errors.New("test error")
+		defer func() { vdr.ResolveErr = nil }()
+
+		presentation, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		require.EqualError(t, client.addProofFn(holderDID+"#key-1", request)(presentation),
+			"resolve holder DID: test error")
+	})
+}
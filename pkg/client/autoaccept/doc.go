@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package autoaccept provides declarative rules deciding which action events are continued (or stopped)
+// automatically. Rules are evaluated in order against the action events of the didexchange, issue-credential,
+// present-proof, introduce and outofband protocols, the first matching rule wins. The events no rule matches
+// are left for the user.
+// The example below shows how to use the client.
+// 	client, err := autoaccept.New(ctx)
+// 	// auto-accepts the offers of the trusted issuer for the given credential type
+// 	err = client.SetRules([]autoaccept.Rule{{
+// 	  ID:              "trusted-issuer",
+// 	  Protocol:        "issue-credential",
+// 	  MsgTypes:        []string{"offer-credential"},
+// 	  IssuerDIDs:      []string{"did:example:issuer"},
+// 	  CredentialTypes: []string{"UniversityDegreeCredential"},
+// 	}})
+// 	// events which are not handled by the rules
+// 	events := client.Filter(actions)
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create client
+//  3) Set rules (and consents for the present-proof requests)
+//  4) Pass the action events through the Filter function
+//
+package autoaccept
@@ -0,0 +1,430 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+)
+
+const (
+	vcType = "VerifiableCredential"
+
+	peDefinitionFormat = "dif/presentation-exchange/definitions@v1.0"
+)
+
+// Rule decides whether the matching action events are continued or stopped automatically.
+// Empty criteria match any action event of the protocol.
+type Rule struct {
+	// ID of the rule.
+	ID string `json:"id"`
+	// Protocol name the rule is applied to (e.g didexchange, issue-credential, present-proof,
+	// introduce or out-of-band).
+	Protocol string `json:"protocol"`
+	// MsgTypes matches the message types of the events, either the full type
+	// (e.g https://didcomm.org/issue-credential/2.0/offer-credential) or its name (e.g offer-credential).
+	MsgTypes []string `json:"msg_types,omitempty"`
+	// TheirDIDs matches the DID of the other agent (the DID of the connection).
+	TheirDIDs []string `json:"their_dids,omitempty"`
+	// IssuerDIDs (issue-credential only) matches the messages where every credential is issued by one of the given DIDs.
+	IssuerDIDs []string `json:"issuer_dids,omitempty"`
+	// CredentialTypes (issue-credential only) matches the messages where every credential has one of the given types.
+	CredentialTypes []string `json:"credential_types,omitempty"`
+	// RequireConsent (present-proof only) matches the requests for which a consent was saved,
+	// a presentation of the consented credentials is created and sent to the verifier.
+	RequireConsent bool `json:"require_consent,omitempty"`
+	// Reject stops the matching action events instead of continuing them.
+	Reject bool `json:"reject,omitempty"`
+}
+
+func (r *Rule) validate() error {
+	if r.ID == "" {
+		return errors.New("empty ID")
+	}
+
+	switch r.Protocol {
+	case didexchange.DIDExchange, issuecredential.Name, presentproof.Name, introduce.Introduce, outofband.Name:
+	default:
+		return fmt.Errorf("unsupported protocol %q", r.Protocol)
+	}
+
+	if len(r.CredentialTypes) > 0 && r.Protocol != issuecredential.Name {
+		return fmt.Errorf("credential types are supported by the %s protocol only", issuecredential.Name)
+	}
+
+	if len(r.IssuerDIDs) > 0 && r.Protocol != issuecredential.Name {
+		return fmt.Errorf("issuer DIDs are supported by the %s protocol only", issuecredential.Name)
+	}
+
+	if r.RequireConsent && r.Protocol != presentproof.Name {
+		return fmt.Errorf("consent is supported by the %s protocol only", presentproof.Name)
+	}
+
+	if r.RequireConsent && r.Reject {
+		return errors.New("consent cannot be required by a reject rule")
+	}
+
+	return nil
+}
+
+func (r *Rule) match(e *actionEvent) bool {
+	if r.Protocol != e.protocol {
+		return false
+	}
+
+	if len(r.MsgTypes) > 0 && !matchMsgType(r.MsgTypes, e.msgType) {
+		return false
+	}
+
+	if len(r.TheirDIDs) > 0 && !contains(r.TheirDIDs, e.theirDID) {
+		return false
+	}
+
+	if len(r.IssuerDIDs) > 0 && !matchIssuers(r.IssuerDIDs, e.credentials) {
+		return false
+	}
+
+	return len(r.CredentialTypes) == 0 || matchTypes(r.CredentialTypes, e.credentials)
+}
+
+func matchIssuers(issuers []string, credentials []credential) bool {
+	if len(credentials) == 0 {
+		return false
+	}
+
+	for i := range credentials {
+		if !contains(issuers, credentials[i].issuer) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchTypes(types []string, credentials []credential) bool {
+	if len(credentials) == 0 {
+		return false
+	}
+
+	for i := range credentials {
+		if !containsAny(types, credentials[i].types) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Consent allows presenting the given credentials for the requests of the presentation definition.
+// A presentation is created for every request and signed by the holder key, the proof is bound to the challenge
+// and the domain of the request.
+type Consent struct {
+	// ID of the consent.
+	ID string `json:"id"`
+	// PresentationDefinitionID is the ID of the requested presentation definition.
+	PresentationDefinitionID string `json:"presentation_definition_id"`
+	// TheirDIDs narrows the consent down to the given verifiers (any verifier if empty).
+	TheirDIDs []string `json:"their_dids,omitempty"`
+	// Credentials the presentation is created from.
+	Credentials []json.RawMessage `json:"credentials"`
+	// VerificationMethod is the DID URL of the Ed25519 holder key the presentation is signed with.
+	VerificationMethod string `json:"verification_method"`
+}
+
+func (c *Consent) validate() error {
+	if c.ID == "" {
+		return errors.New("empty ID")
+	}
+
+	if c.PresentationDefinitionID == "" {
+		return errors.New("empty presentation definition ID")
+	}
+
+	if len(c.Credentials) == 0 {
+		return errors.New("empty credentials")
+	}
+
+	if c.VerificationMethod == "" {
+		return errors.New("empty verification method")
+	}
+
+	return nil
+}
+
+func (c *Consent) match(e *actionEvent) bool {
+	if e.request == nil || c.PresentationDefinitionID != e.request.definitionID {
+		return false
+	}
+
+	return len(c.TheirDIDs) == 0 || contains(c.TheirDIDs, e.theirDID)
+}
+
+// actionEvent keeps the action event details the rules are evaluated against.
+type actionEvent struct {
+	protocol    string
+	msgType     string
+	theirDID    string
+	credentials []credential
+	request     *presentationRequest
+}
+
+// credential keeps the details of a credential found in the issue-credential message attachments.
+type credential struct {
+	types  []string
+	issuer string
+}
+
+// presentationRequest keeps the details of the presentation definition requested by the present-proof message.
+type presentationRequest struct {
+	definitionID string
+	challenge    string
+	domain       string
+}
+
+func newActionEvent(action service.DIDCommAction) *actionEvent {
+	event := &actionEvent{
+		protocol: action.ProtocolName,
+		msgType:  action.Message.Type(),
+	}
+
+	if props, ok := action.Properties.(interface{ TheirDID() string }); ok {
+		event.theirDID = props.TheirDID()
+	}
+
+	var err error
+
+	switch event.protocol {
+	case didexchange.DIDExchange:
+		event.theirDID, err = requesterDID(action.Message, event.theirDID)
+	case issuecredential.Name:
+		event.credentials, err = credentials(action.Message)
+	case presentproof.Name:
+		event.request, err = requestedPresentation(action.Message)
+	}
+
+	if err != nil {
+		logger.Warnf("%s: %s", event.msgType, err)
+	}
+
+	return event
+}
+
+func requesterDID(msg service.DIDCommMsg, theirDID string) (string, error) {
+	if theirDID != "" || msg.Type() != didexchange.RequestMsgType {
+		return theirDID, nil
+	}
+
+	request := &didexchange.Request{}
+	if err := msg.Decode(request); err != nil {
+		return "", fmt.Errorf("decode request: %w", err)
+	}
+
+	return request.DID, nil
+}
+
+// credentials returns the types and the issuer of every credential found in the issue-credential message attachments.
+func credentials(msg service.DIDCommMsg) ([]credential, error) { // nolint: gocyclo
+	var (
+		attachments []decorator.Attachment
+		err         error
+	)
+
+	switch msgName(msg.Type()) {
+	case "propose-credential":
+		var propose *issuecredential.ProposeCredential
+
+		propose, err = issuecredential.DecodeProposeCredential(msg)
+		if propose != nil {
+			attachments = propose.FiltersAttach
+		}
+	case "offer-credential":
+		var offer *issuecredential.OfferCredential
+
+		offer, err = issuecredential.DecodeOfferCredential(msg)
+		if offer != nil {
+			attachments = offer.OffersAttach
+		}
+	case "request-credential":
+		var request *issuecredential.RequestCredential
+
+		request, err = issuecredential.DecodeRequestCredential(msg)
+		if request != nil {
+			attachments = request.RequestsAttach
+		}
+	case "issue-credential":
+		var issue *issuecredential.IssueCredential
+
+		issue, err = issuecredential.DecodeIssueCredential(msg)
+		if issue != nil {
+			attachments = issue.CredentialsAttach
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	var result []credential
+
+	for i := range attachments {
+		src, err := attachments[i].Data.Fetch()
+		if err != nil {
+			continue
+		}
+
+		var payload struct {
+			Type       interface{}     `json:"type"`
+			Issuer     interface{}     `json:"issuer"`
+			Credential json.RawMessage `json:"credential"`
+		}
+
+		if err = json.Unmarshal(src, &payload); err != nil {
+			continue
+		}
+
+		// ld-proof-vc-detail attachments keep the credential in the 'credential' field
+		if len(payload.Credential) > 0 {
+			if err = json.Unmarshal(payload.Credential, &payload); err != nil {
+				continue
+			}
+		}
+
+		c := credential{types: stringArray(payload.Type), issuer: issuerID(payload.Issuer)}
+
+		if len(c.types) > 0 || c.issuer != "" {
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
+
+// issuerID returns the ID of the credential issuer, the issuer is either a string or an object with the 'id' field.
+func issuerID(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		id, _ := t["id"].(string)
+
+		return id
+	}
+
+	return ""
+}
+
+// requestedPresentation returns the presentation definition (presentation-exchange format) requested
+// by the present-proof message.
+func requestedPresentation(msg service.DIDCommMsg) (*presentationRequest, error) {
+	if msgName(msg.Type()) != "request-presentation" {
+		return nil, nil
+	}
+
+	request, err := presentproof.DecodeRequestPresentation(msg)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	for _, format := range request.Formats {
+		if format.Format != peDefinitionFormat {
+			continue
+		}
+
+		for i := range request.RequestPresentationsAttach {
+			if request.RequestPresentationsAttach[i].ID != format.AttachID {
+				continue
+			}
+
+			src, err := request.RequestPresentationsAttach[i].Data.Fetch()
+			if err != nil {
+				continue
+			}
+
+			var payload struct {
+				Challenge              string `json:"challenge"`
+				Domain                 string `json:"domain"`
+				PresentationDefinition struct {
+					ID string `json:"id"`
+				} `json:"presentation_definition"`
+			}
+
+			if err = json.Unmarshal(src, &payload); err == nil && payload.PresentationDefinition.ID != "" {
+				return &presentationRequest{
+					definitionID: payload.PresentationDefinition.ID,
+					challenge:    payload.Challenge,
+					domain:       payload.Domain,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func stringArray(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var result []string
+
+		for _, e := range t {
+			if s, ok := e.(string); ok && s != vcType {
+				result = append(result, s)
+			}
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// msgName returns the last segment of the message type, e.g offer-credential.
+func msgName(msgType string) string {
+	return msgType[strings.LastIndex(msgType, "/")+1:]
+}
+
+func matchMsgType(types []string, msgType string) bool {
+	for _, t := range types {
+		if t == msgType || t == msgName(msgType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, e := range values {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAny(values, items []string) bool {
+	for _, item := range items {
+		if contains(values, item) {
+			return true
+		}
+	}
+
+	return false
+}
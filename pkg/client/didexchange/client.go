@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
@@ -35,6 +36,8 @@ const (
 // ErrConnectionNotFound is returned when connection not found.
 var ErrConnectionNotFound = errors.New("connection not found")
 
+var logger = log.New("aries-framework/client/didexchange")
+
 type options struct {
 	routerConnections  []string
 	routerConnectionID string
@@ -163,11 +166,16 @@ func (c *Client) CreateInvitation(label string, args ...InvOpt) (*Invitation, er
 
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 	//  with connection record
-	_, sigPubKey, err := c.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	kid, sigPubKey, err := c.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		return nil, fmt.Errorf("createInvitation: failed to extract public SigningKey bytes from handle:%w", err)
 	}
 
+	// tagging the key is best effort, the KMS may not support metadata
+	if err = kms.TagKey(c.kms, kid, kms.KeyPurposeInvitation, ""); err != nil {
+		logger.Warnf("createInvitation: failed to set SigningKey metadata: %v", err)
+	}
+
 	didKey, _ := fingerprint.CreateDIDKey(sigPubKey)
 
 	var (
@@ -287,10 +295,19 @@ func (c *Client) CreateImplicitInvitationWithDID(inviter, invitee *DIDInfo) (str
 }
 
 // QueryConnections queries connections matching given criteria(parameters).
-func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connection, error) { //nolint: gocyclo
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 - query all connections from all criteria and
-	//  also results needs to be paged.
-	records, err := c.connectionStore.QueryConnectionRecords()
+func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connection, error) {
+	records, err := c.connectionStore.QueryConnections(&connection.QueryParams{
+		State:          request.State,
+		MyDID:          request.MyDID,
+		TheirDID:       request.TheirDID,
+		InvitationID:   request.InvitationID,
+		ParentThreadID: request.ParentThreadID,
+		Metadata:       request.Metadata,
+		SortBy:         request.SortBy,
+		Descending:     request.Descending,
+		Offset:         request.Offset,
+		Limit:          request.Limit,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed query connections: %w", err)
 	}
@@ -298,26 +315,6 @@ func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connectio
 	var result []*Connection
 
 	for _, record := range records {
-		if request.State != "" && request.State != record.State {
-			continue
-		}
-
-		if request.InvitationID != "" && request.InvitationID != record.InvitationID {
-			continue
-		}
-
-		if request.ParentThreadID != "" && request.ParentThreadID != record.ParentThreadID {
-			continue
-		}
-
-		if request.MyDID != "" && request.MyDID != record.MyDID {
-			continue
-		}
-
-		if request.TheirDID != "" && request.TheirDID != record.TheirDID {
-			continue
-		}
-
 		result = append(result, &Connection{Record: record})
 	}
 
@@ -397,6 +394,20 @@ func (c *Client) RemoveConnection(connectionID string) error {
 	return nil
 }
 
+// SetConnectionMetadata replaces the metadata of the connection for given id.
+func (c *Client) SetConnectionMetadata(connectionID string, metadata map[string]string) error {
+	err := c.connectionStore.SaveConnectionMetadata(connectionID, metadata)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return ErrConnectionNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot save connection metadata: err=%w", err)
+	}
+
+	return nil
+}
+
 // ConnectionOption allows you to customize details of the connection record.
 type ConnectionOption func(*Connection)
 
@@ -196,6 +196,30 @@ func TestClient_CreateInvitation(t *testing.T) {
 		require.Contains(t, err.Error(), "createKeyErr")
 	})
 
+	t.Run("test error from set signing key metadata is ignored", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, svc)
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+			KMSValue: &mockkms.KeyManager{SetMetadataErr: fmt.Errorf("setMetadataErr")},
+		})
+		require.NoError(t, err)
+		inv, err := c.CreateInvitation("agent")
+		require.NoError(t, err)
+		require.NotNil(t, inv)
+	})
+
 	t.Run("test error from save record", func(t *testing.T) {
 		store := &mockstore.MockStore{
 			Store:  make(map[string]mockstore.DBEntry),
@@ -1100,6 +1124,102 @@ func TestClient_QueryConnectionsByParams(t *testing.T) { // nolint: gocyclo
 		require.Error(t, err)
 		require.Empty(t, results)
 	})
+
+	t.Run("test get connections by metadata sorted and paged", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		c, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			StorageProviderValue:              mem.NewProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				mediator.Coordination:   &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: fmt.Sprint(i),
+				State:        connection.StateNameCompleted,
+				CreatedAt:    time.Date(2021, 1, 1+i, 0, 0, 0, 0, time.UTC),
+				Metadata:     map[string]string{"group": strconv.Itoa(i % 2)},
+			}))
+		}
+
+		results, err := c.QueryConnections(&QueryConnectionsParams{
+			Metadata:   map[string]string{"group": "0"},
+			SortBy:     connection.SortByCreatedAt,
+			Descending: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, "2", results[0].ConnectionID)
+		require.Equal(t, "0", results[1].ConnectionID)
+
+		results, err = c.QueryConnections(&QueryConnectionsParams{SortBy: connection.SortByCreatedAt, Offset: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, "1", results[0].ConnectionID)
+		require.Equal(t, "2", results[1].ConnectionID)
+
+		_, err = c.QueryConnections(&QueryConnectionsParams{SortBy: "unknown"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported sort field")
+	})
+}
+
+func TestClient_SetConnectionMetadata(t *testing.T) {
+	svc, err := didexchange.New(&mockprotocol.MockProvider{
+		ServiceMap: map[string]interface{}{
+			mediator.Coordination: &mockroute.MockMediatorSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	c, err := New(&mockprovider.Provider{
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+		StorageProviderValue:              mem.NewProvider(),
+		ServiceMap: map[string]interface{}{
+			didexchange.DIDExchange: svc,
+			mediator.Coordination:   &mockroute.MockMediatorSvc{},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "id1",
+		ThreadID:     "thid1",
+		State:        connection.StateNameCompleted,
+	}))
+
+	t.Run("test success", func(t *testing.T) {
+		require.NoError(t, c.SetConnectionMetadata("id1", map[string]string{"label": "alice"}))
+
+		conn, err := c.GetConnection("id1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"label": "alice"}, conn.Metadata)
+
+		results, err := c.QueryConnections(&QueryConnectionsParams{Metadata: map[string]string{"label": "alice"}})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("test connection not found", func(t *testing.T) {
+		err := c.SetConnectionMetadata("unknown", map[string]string{"label": "alice"})
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test invalid metadata", func(t *testing.T) {
+		err := c.SetConnectionMetadata("id1", map[string]string{"": "alice"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "metadata key cannot be empty")
+	})
 }
 
 func TestServiceEvents(t *testing.T) {
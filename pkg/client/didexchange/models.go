@@ -43,6 +43,21 @@ type QueryConnectionsParams struct {
 
 	// TheirRole is other party's role
 	TheirRole string `json:"their_role,omitempty"`
+
+	// Metadata entries the connection must have, an empty value matches any value of the key
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// SortBy is the field to sort the connections by: created_at, updated_at or last_message_at
+	SortBy string `json:"sort_by,omitempty"`
+
+	// Descending sorts the connections in the descending order
+	Descending bool `json:"descending,omitempty"`
+
+	// Offset is the number of the connections to skip
+	Offset int `json:"offset,omitempty"`
+
+	// Limit is the maximum number of the connections to return
+	Limit int `json:"limit,omitempty"`
 }
 
 // Connection model
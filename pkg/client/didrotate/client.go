@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+)
+
+// Provider contains dependencies for the DID rotate client and is typically created by using aries.Context().
+type Provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// ProtocolService defines the DID rotate service.
+type ProtocolService interface {
+	RotateDID(connectionID string, opts ...didrotate.Opt) (string, error)
+}
+
+// Client enables access to the DID rotate API.
+type Client struct {
+	service ProtocolService
+}
+
+// Opt represents an option for the DID rotation.
+type Opt didrotate.Opt
+
+// WithNewDID rotates to the given DID instead of creating a new peer DID.
+func WithNewDID(newDID string) Opt {
+	return Opt(didrotate.WithNewDID(newDID))
+}
+
+// WithRouterConnections registers the keys of the new DID with the given routers.
+func WithRouterConnections(conns ...string) Opt {
+	return Opt(didrotate.WithRouterConnections(conns...))
+}
+
+// WithGracePeriod sets the period the messages addressed to the prior DID are still accepted.
+func WithGracePeriod(gracePeriod time.Duration) Opt {
+	return Opt(didrotate.WithGracePeriod(gracePeriod))
+}
+
+// New returns new instance of the DID rotate client.
+func New(ctx Provider) (*Client, error) {
+	svc, err := ctx.Service(didrotate.DIDRotate)
+	if err != nil {
+		return nil, err
+	}
+
+	rotateSvc, ok := svc.(ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to DID Rotate Service failed")
+	}
+
+	return &Client{service: rotateSvc}, nil
+}
+
+// RotateDID rotates the DID used by the given connection and notifies the other party.
+// Returns the new DID.
+func (c *Client) RotateDID(connectionID string, opts ...Opt) (string, error) {
+	svcOpts := make([]didrotate.Opt, len(opts))
+	for i, opt := range opts {
+		svcOpts[i] = didrotate.Opt(opt)
+	}
+
+	return c.service.RotateDID(connectionID, svcOpts...)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{didrotate.DIDRotate: &mockService{}},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("get service error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.EqualError(t, err, "service error")
+	})
+
+	t.Run("cast service error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: "invalid"})
+		require.EqualError(t, err, "cast service to DID Rotate Service failed")
+	})
+}
+
+func TestClient_RotateDID(t *testing.T) {
+	svc := &mockService{newDID: "did:peer:new"}
+
+	client, err := New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{didrotate.DIDRotate: svc},
+	})
+	require.NoError(t, err)
+
+	newDID, err := client.RotateDID("connection-id",
+		WithNewDID("did:peer:new"), WithRouterConnections("router"), WithGracePeriod(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "did:peer:new", newDID)
+	require.Equal(t, "connection-id", svc.connectionID)
+	require.Len(t, svc.opts, 3)
+
+	svc.err = errors.New("rotate error")
+
+	_, err = client.RotateDID("connection-id")
+	require.EqualError(t, err, "rotate error")
+}
+
+type mockService struct {
+	newDID       string
+	err          error
+	connectionID string
+	opts         []didrotate.Opt
+}
+
+func (m *mockService) RotateDID(connectionID string, opts ...didrotate.Opt) (string, error) {
+	m.connectionID = connectionID
+	m.opts = opts
+
+	return m.newDID, m.err
+}
@@ -0,0 +1,17 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package didrotate enables rotating the DID used by an existing connection.
+// The other party of the connection is notified by a rotate message signed with the key of the prior DID,
+// the messages addressed to the prior DID are still accepted during the grace period.
+//
+// 	client, err := didrotate.New(ctx)
+// 	if err != nil {
+// 	  panic(err)
+// 	}
+//
+// 	newDID, err := client.RotateDID(connectionID, didrotate.WithGracePeriod(time.Hour))
+package didrotate
@@ -29,6 +29,8 @@ type (
 	Recipient introduce.Recipient
 	// Action contains helpful information about action.
 	Action introduce.Action
+	// RecipientStatus describes the state of the introduction for a recipient of the proposal.
+	RecipientStatus introduce.RecipientStatus
 )
 
 // Provider contains dependencies for the introduce protocol and is typically created by using aries.Context().
@@ -42,6 +44,7 @@ type ProtocolService interface {
 	Actions() ([]introduce.Action, error)
 	ActionContinue(piID string, opt introduce.Opt) error
 	ActionStop(piID string, err error) error
+	RecipientStatuses(piID string) ([]*introduce.RecipientStatus, error)
 }
 
 // Client enable access to introduce API.
@@ -69,21 +72,32 @@ func New(ctx Provider) (*Client, error) {
 }
 
 // SendProposal sends a proposal to the introducees (the client has not published an out-of-band message).
-func (c *Client) SendProposal(recipient1, recipient2 *Recipient) (string, error) {
-	_recipient1 := introduce.Recipient(*recipient1)
-	_recipient2 := introduce.Recipient(*recipient2)
+// The first recipient is introduced to each of the other recipients, every recipient responds independently
+// and the introduction succeeds for the recipients who approved it.
+func (c *Client) SendProposal(recipient1, recipient2 *Recipient, recipients ...*Recipient) (string, error) {
+	all := append([]*Recipient{recipient1, recipient2}, recipients...)
+	proposals := make([]service.DIDCommMsg, len(all))
+
+	for i, recipient := range all {
+		_recipient := introduce.Recipient(*recipient)
+		proposals[i] = introduce.CreateProposal(&_recipient)
+	}
 
-	proposal1 := introduce.CreateProposal(&_recipient1)
-	proposal2 := introduce.CreateProposal(&_recipient2)
+	introduce.WrapWithMetadataPIID(proposals...)
 
-	introduce.WrapWithMetadataPIID(proposal1, proposal2)
+	var (
+		piID string
+		err  error
+	)
 
-	_, err := c.service.HandleOutbound(proposal1, recipient1.MyDID, recipient1.TheirDID)
-	if err != nil {
-		return "", fmt.Errorf("handle outbound: %w", err)
+	for i, recipient := range all {
+		piID, err = c.service.HandleOutbound(proposals[i], recipient.MyDID, recipient.TheirDID)
+		if err != nil {
+			return "", fmt.Errorf("handle outbound: %w", err)
+		}
 	}
 
-	return c.service.HandleOutbound(proposal2, recipient2.MyDID, recipient2.TheirDID)
+	return piID, nil
 }
 
 // SendProposalWithOOBInvitation sends a proposal to the introducee (the client has published an out-of-band request).
@@ -128,8 +142,9 @@ func (c *Client) AcceptRequestWithPublicOOBInvitation(piID string, inv *outofban
 // AcceptRequestWithRecipients is used when the introducer does not have a published out-of-band message on hand
 // but he is willing to introduce agents to each other.
 // Introducer can provide recipients only after receiving RequestMsgType.
-func (c *Client) AcceptRequestWithRecipients(piID string, to *To, recipient *Recipient) error {
-	return c.service.ActionContinue(piID, WithRecipients(to, recipient))
+// The requester is introduced to each of the given recipients.
+func (c *Client) AcceptRequestWithRecipients(piID string, to *To, recipient *Recipient, recipients ...*Recipient) error {
+	return c.service.ActionContinue(piID, WithRecipients(to, recipient, recipients...))
 }
 
 // DeclineProposal is used to reject the proposal.
@@ -164,15 +179,37 @@ func (c *Client) Actions() ([]Action, error) {
 	return result, nil
 }
 
+// RecipientStatuses returns the state of the introduction per recipient (introducer only).
+// The recipients are ordered as the proposals were sent.
+func (c *Client) RecipientStatuses(piID string) ([]*RecipientStatus, error) {
+	statuses, err := c.service.RecipientStatuses(piID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*RecipientStatus, len(statuses))
+	for i, status := range statuses {
+		result[i] = (*RecipientStatus)(status)
+	}
+
+	return result, nil
+}
+
 // WithRecipients is used when the introducer does not have a published out-of-band message on hand
 // but he is willing to introduce agents to each other.
 // NOTE: Introducer can provide recipients only after receiving RequestMsgType.
 // USAGE: event.Continue(WithRecipients(to, recipient)).
-func WithRecipients(to *To, recipient *Recipient) introduce.Opt {
+func WithRecipients(to *To, recipient *Recipient, recipients ...*Recipient) introduce.Opt {
 	_to := introduce.To(*to)
 	_recipient := introduce.Recipient(*recipient)
 
-	return introduce.WithRecipients(&_to, &_recipient)
+	_recipients := make([]*introduce.Recipient, len(recipients))
+	for i, r := range recipients {
+		_r := introduce.Recipient(*r)
+		_recipients[i] = &_r
+	}
+
+	return introduce.WithRecipients(&_to, &_recipient, _recipients...)
 }
 
 // WithPublicOOBInvitation is used when introducer wants to provide a published out-of-band request.
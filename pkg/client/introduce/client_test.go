@@ -104,6 +104,35 @@ func TestClient_SendProposal(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Success with multiple recipients", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		var piIDs []interface{}
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().
+			HandleOutbound(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+				require.Equal(t, msg.Type(), introduce.ProposalMsgType)
+				piIDs = append(piIDs, msg.Metadata()["introduce_pi_id"])
+
+				return expectedPIID, nil
+			}).Times(3)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		piid, err := client.SendProposal(&Recipient{}, &Recipient{}, &Recipient{})
+		require.Equal(t, expectedPIID, piid)
+		require.NoError(t, err)
+
+		require.Len(t, piIDs, 3)
+		require.NotEmpty(t, piIDs[0])
+		require.Equal(t, piIDs[0], piIDs[1])
+		require.Equal(t, piIDs[0], piIDs[2])
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		const errMsg = "test error"
 
@@ -306,3 +335,46 @@ func TestClient_AcceptProblemReport(t *testing.T) {
 
 	require.NoError(t, client.AcceptProblemReport("PIID"))
 }
+
+func TestClient_RecipientStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().RecipientStatuses(expectedPIID).Return([]*introduce.RecipientStatus{
+			{MyDID: "firstMyDID", TheirDID: "firstTheirDID", State: introduce.RecipientStateIntroduced},
+			{MyDID: "secondMyDID", TheirDID: "secondTheirDID", State: introduce.RecipientStateDeclined},
+		}, nil)
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		statuses, err := client.RecipientStatuses(expectedPIID)
+		require.NoError(t, err)
+		require.Equal(t, []*RecipientStatus{
+			{MyDID: "firstMyDID", TheirDID: "firstTheirDID", State: introduce.RecipientStateIntroduced},
+			{MyDID: "secondMyDID", TheirDID: "secondTheirDID", State: introduce.RecipientStateDeclined},
+		}, statuses)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		const errMsg = "test error"
+
+		provider := mocksintroduce.NewMockProvider(ctrl)
+
+		svc := mocksintroduce.NewMockProtocolService(ctrl)
+		svc.EXPECT().RecipientStatuses(expectedPIID).Return(nil, errors.New(errMsg))
+
+		provider.EXPECT().Service(gomock.Any()).Return(svc, nil)
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		statuses, err := client.RecipientStatuses(expectedPIID)
+		require.EqualError(t, err, errMsg)
+		require.Nil(t, statuses)
+	})
+}
@@ -8,9 +8,22 @@ package issuecredential
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// V2 is the issue-credential 2.0 protocol version (DIDComm V1 connections).
+	V2 Version = "v2"
+	// V3 is the issue-credential 3.0 protocol version (DIDComm V2 connections).
+	V3 Version = "v3"
 )
 
 var (
@@ -40,11 +53,44 @@ type (
 	Action issuecredential.Action
 )
 
+// Version is the issue-credential protocol version.
+type Version string
+
 // Provider contains dependencies for the issuecredential protocol and is typically created by using aries.Context().
+// If the provider also gives access to the storage (see connectionProvider) the protocol version
+// is selected by the DIDComm version of the connection.
 type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// connectionProvider is an optional part of the Provider which allows looking up connections.
+type connectionProvider interface {
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// connectionlessProvider is an optional part of the Provider which allows creating offers
+// sent without a connection (see CreateConnectionlessOffer).
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+}
+
+// SendOpt represents an option for the Send* functions.
+type SendOpt func(opts *sendOpts)
+
+type sendOpts struct {
+	version Version
+}
+
+// WithVersion allows providing the protocol version of the message.
+// By default, the version is selected by the DIDComm version of the connection between myDID and theirDID.
+func WithVersion(version Version) SendOpt {
+	return func(opts *sendOpts) {
+		opts.version = version
+	}
+}
+
 // ProtocolService defines the issuecredential service.
 type ProtocolService interface {
 	service.DIDComm
@@ -56,7 +102,10 @@ type ProtocolService interface {
 // Client enable access to issuecredential API.
 type Client struct {
 	service.Event
-	service ProtocolService
+	service         ProtocolService
+	connections     *connection.Lookup
+	kms             kms.KeyManager
+	serviceEndpoint string
 }
 
 // New return new instance of the issuecredential client.
@@ -71,10 +120,24 @@ func New(ctx Provider) (*Client, error) {
 		return nil, errors.New("cast service to issuecredential service failed")
 	}
 
-	return &Client{
+	client := &Client{
 		Event:   svc,
 		service: svc,
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionProvider); ok {
+		client.connections, err = connection.NewLookup(p)
+		if err != nil {
+			return nil, fmt.Errorf("new connection lookup: %w", err)
+		}
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		client.kms = p.KMS()
+		client.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return client, nil
 }
 
 // Actions returns unfinished actions for the async usage.
@@ -93,36 +156,106 @@ func (c *Client) Actions() ([]Action, error) {
 }
 
 // SendOffer is used by the Issuer to send an offer.
-func (c *Client) SendOffer(offer *OfferCredential, myDID, theirDID string) (string, error) {
+func (c *Client) SendOffer(offer *OfferCredential, myDID, theirDID string, opts ...SendOpt) (string, error) {
 	if offer == nil {
 		return "", errEmptyOffer
 	}
 
 	offer.Type = issuecredential.OfferCredentialMsgType
 
-	return c.service.HandleOutbound(service.NewDIDCommMsgMap(offer), myDID, theirDID)
+	msg := service.NewDIDCommMsgMap(offer)
+	if c.version(myDID, theirDID, opts) == V3 {
+		msg = service.NewDIDCommMsgMap((*issuecredential.OfferCredential)(offer).AsV3())
+	}
+
+	return c.service.HandleOutbound(msg, myDID, theirDID)
+}
+
+// CreateConnectionlessOffer is used by the Issuer to create an offer sent without a connection,
+// e.g. attached to an out-of-band invitation without handshake protocols.
+// The offer carries the ~service decorator the Holder replies to.
+// It returns the threadID of the new instance of the protocol and the offer to deliver.
+func (c *Client) CreateConnectionlessOffer(offer *OfferCredential) (string, service.DIDCommMsgMap, error) {
+	if offer == nil {
+		return "", nil, errEmptyOffer
+	}
+
+	if c.kms == nil {
+		return "", nil, errors.New("connectionless offers are not supported by the provider")
+	}
+
+	svc, err := service.NewConnectionlessService(c.kms, c.serviceEndpoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("connectionless offer: %w", err)
+	}
+
+	offer.Type = issuecredential.OfferCredentialMsgType
+
+	msg := service.NewDIDCommMsgMap(offer)
+	msg.SetServiceDecorator(svc)
+
+	// the ID becomes the threadID of the protocol, the Holder replies to the delivered offer
+	if err = msg.SetID(uuid.New().String()); err != nil {
+		return "", nil, err
+	}
+
+	piID, err := c.service.HandleOutbound(msg, "", "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return piID, msg, nil
 }
 
 // SendProposal is used by the Holder to send a proposal.
-func (c *Client) SendProposal(proposal *ProposeCredential, myDID, theirDID string) (string, error) {
+func (c *Client) SendProposal(proposal *ProposeCredential, myDID, theirDID string, opts ...SendOpt) (string, error) {
 	if proposal == nil {
 		return "", errEmptyProposal
 	}
 
 	proposal.Type = issuecredential.ProposeCredentialMsgType
 
-	return c.service.HandleOutbound(service.NewDIDCommMsgMap(proposal), myDID, theirDID)
+	msg := service.NewDIDCommMsgMap(proposal)
+	if c.version(myDID, theirDID, opts) == V3 {
+		msg = service.NewDIDCommMsgMap((*issuecredential.ProposeCredential)(proposal).AsV3())
+	}
+
+	return c.service.HandleOutbound(msg, myDID, theirDID)
 }
 
 // SendRequest is used by the Holder to send a request.
-func (c *Client) SendRequest(request *RequestCredential, myDID, theirDID string) (string, error) {
+func (c *Client) SendRequest(request *RequestCredential, myDID, theirDID string, opts ...SendOpt) (string, error) {
 	if request == nil {
 		return "", errEmptyRequest
 	}
 
 	request.Type = issuecredential.RequestCredentialMsgType
 
-	return c.service.HandleOutbound(service.NewDIDCommMsgMap(request), myDID, theirDID)
+	msg := service.NewDIDCommMsgMap(request)
+	if c.version(myDID, theirDID, opts) == V3 {
+		msg = service.NewDIDCommMsgMap((*issuecredential.RequestCredential)(request).AsV3())
+	}
+
+	return c.service.HandleOutbound(msg, myDID, theirDID)
+}
+
+// version returns the protocol version provided by the options or
+// the version that corresponds to the DIDComm version of the connection.
+func (c *Client) version(myDID, theirDID string, opts []SendOpt) Version {
+	options := &sendOpts{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.version != "" {
+		return options.version
+	}
+
+	if c.connections != nil && c.connections.GetDIDCommVersionByDIDs(myDID, theirDID) == connection.DIDCommV2 {
+		return V3
+	}
+
+	return V2
 }
 
 // AcceptProposal is used when the Issuer is willing to accept the proposal.
@@ -7,15 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package issuecredential
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/issuecredential"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -44,6 +50,79 @@ func TestNew(t *testing.T) {
 		_, err := New(provider)
 		require.EqualError(t, err, "cast service to issuecredential service failed")
 	})
+
+	t.Run("connection lookup error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				issuecredential.Name: mocks.NewMockProtocolService(ctrl),
+			},
+			StorageProviderValue: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New(errMsg)},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestClient_Version(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := &mockprovider.Provider{
+		StorageProviderValue:              mem.NewProvider(),
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+	}
+
+	recorder, err := connection.NewRecorder(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "v2",
+		State:        connection.StateNameCompleted,
+		MyDID:        Alice,
+		TheirDID:     Bob,
+		MediaTypes:   []string{"application/didcomm-encrypted+json"},
+	}))
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "v1",
+		State:        connection.StateNameCompleted,
+		MyDID:        Bob,
+		TheirDID:     Alice,
+	}))
+
+	expected := map[string]string{
+		Alice: issuecredential.OfferCredentialMsgTypeV3,
+		Bob:   issuecredential.OfferCredentialMsgType,
+	}
+
+	svc := mocks.NewMockProtocolService(ctrl)
+	svc.EXPECT().HandleOutbound(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(msg service.DIDCommMsg, myDID, _ string) (string, error) {
+			require.Equal(t, expected[myDID], msg.Type())
+
+			return expectedPiid, nil
+		}).Times(4)
+
+	provider.ServiceMap = map[string]interface{}{issuecredential.Name: svc}
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	_, err = client.SendOffer(&OfferCredential{}, Alice, Bob)
+	require.NoError(t, err)
+
+	_, err = client.SendOffer(&OfferCredential{}, Bob, Alice)
+	require.NoError(t, err)
+
+	expected[Alice] = issuecredential.OfferCredentialMsgType
+
+	_, err = client.SendOffer(&OfferCredential{}, Alice, Bob, WithVersion(V2))
+	require.NoError(t, err)
+
+	expected[Bob] = issuecredential.OfferCredentialMsgTypeV3
+
+	_, err = client.SendOffer(&OfferCredential{}, Bob, "unknown", WithVersion(V3))
+	require.NoError(t, err)
 }
 
 func TestClient_SendOffer(t *testing.T) {
@@ -83,6 +162,75 @@ func TestClient_SendOffer(t *testing.T) {
 	})
 }
 
+func TestClient_CreateConnectionlessOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newProvider := func(svc ProtocolService, km *mockkms.KeyManager) *mockprovider.Provider {
+		return &mockprovider.Provider{
+			ServiceMap:                        map[string]interface{}{issuecredential.Name: svc},
+			StorageProviderValue:              mem.NewProvider(),
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			KMSValue:                          km,
+			ServiceEndpointValue:              "https://issuer.example.com",
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().HandleOutbound(gomock.Any(), "", "").
+			DoAndReturn(func(msg service.DIDCommMsg, _, _ string) (string, error) {
+				require.Equal(t, msg.Type(), issuecredential.OfferCredentialMsgType)
+
+				return msg.ID(), nil
+			})
+
+		client, err := New(newProvider(svc,
+			&mockkms.KeyManager{CrAndExportPubKeyValue: make([]byte, ed25519.PublicKeySize)}))
+		require.NoError(t, err)
+
+		piid, offer, err := client.CreateConnectionlessOffer(&OfferCredential{})
+		require.NoError(t, err)
+		require.Equal(t, offer.ID(), piid)
+
+		svcDecorator, err := offer.ServiceDecorator()
+		require.NoError(t, err)
+		require.Len(t, svcDecorator.RecipientKeys, 1)
+		require.Equal(t, "https://issuer.example.com", svcDecorator.ServiceEndpoint)
+	})
+
+	t.Run("Create key error", func(t *testing.T) {
+		client, err := New(newProvider(mocks.NewMockProtocolService(ctrl),
+			&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New("test")}))
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessOffer(&OfferCredential{})
+		require.EqualError(t, err, "connectionless offer: create connectionless key: test")
+	})
+
+	t.Run("Not supported by the provider", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessOffer(&OfferCredential{})
+		require.EqualError(t, err, "connectionless offers are not supported by the provider")
+	})
+
+	t.Run("Empty offer", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessOffer(nil)
+		require.EqualError(t, err, errEmptyOffer.Error())
+	})
+}
+
 func TestClient_SendProposal(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
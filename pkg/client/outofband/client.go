@@ -7,11 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package outofband
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
@@ -27,17 +33,27 @@ type (
 	Invitation outofband.Invitation
 	// Action contains helpful information about action.
 	Action outofband.Action
+	// InvitationRecord keeps track of an invitation created by the agent.
+	InvitationRecord outofband.InvitationRecord
 )
 
 const (
 	// InvitationMsgType is the '@type' for the invitation message.
 	InvitationMsgType = outofband.InvitationMsgType
+	// InvitationMsgTypeV11 is the '@type' for the Out-of-Band 1.1 invitation message.
+	InvitationMsgTypeV11 = outofband.InvitationMsgTypeV11
 	// HandshakeReuseMsgType is the '@type' for the handshake reuse message.
 	HandshakeReuseMsgType = outofband.HandshakeReuseMsgType
 	// HandshakeReuseAcceptedMsgType is the '@type' for the handshake reuse accepted message.
 	HandshakeReuseAcceptedMsgType = outofband.HandshakeReuseAcceptedMsgType
+
+	// maxQRPayloadLength is the length of the invitation URL above which the shortened URL is used in QR codes.
+	maxQRPayloadLength = 512
+	httpTimeout        = 10 * time.Second
 )
 
+var logger = log.New("aries-framework/client/outofband")
+
 // EventOptions are is a container of options that you can pass to an event's
 // Continue function to customize the reaction to incoming out-of-band messages.
 type EventOptions struct {
@@ -88,10 +104,13 @@ type message struct {
 	RouterConnections  []string
 	Service            []interface{}
 	HandshakeProtocols []string
+	NoHandshake        bool
 	Attachments        []*decorator.Attachment
 	Accept             []string
 	ReuseAnyConnection bool
 	ReuseConnection    string
+	MaxUses            int
+	ExpiresAt          *time.Time
 }
 
 func (m *message) RouterConnection() string {
@@ -106,7 +125,10 @@ func (m *message) RouterConnection() string {
 type OobService interface {
 	service.Event
 	AcceptInvitation(*outofband.Invitation, outofband.Options) (string, error)
-	SaveInvitation(*outofband.Invitation) error
+	SaveInvitation(*outofband.Invitation, ...outofband.InvitationOpt) error
+	GetInvitationRecord(string) (*outofband.InvitationRecord, error)
+	InvitationRecords() ([]*outofband.InvitationRecord, error)
+	RevokeInvitation(string) error
 	Actions() ([]outofband.Action, error)
 	ActionContinue(string, outofband.Options) error
 	ActionStop(string, error) error
@@ -125,6 +147,7 @@ type Client struct {
 	service.Event
 	didDocSvcFunc func(routerConnID string) (*did.Service, error)
 	oobService    OobService
+	httpClient    *http.Client
 }
 
 // New returns a new Client for the Out-Of-Band protocol.
@@ -143,13 +166,20 @@ func New(p Provider) (*Client, error) {
 		Event:         oobSvc,
 		didDocSvcFunc: didServiceBlockFunc(p),
 		oobService:    oobSvc,
+		httpClient: &http.Client{
+			Timeout: httpTimeout,
+			// the shortened invitation URL redirects to the invitation URL
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
 	}, nil
 }
 
 // CreateInvitation creates and saves an out-of-band invitation.
 // Services are required in the RFC, but optional in this implementation. If not provided, a default will be assigned.
 // TODO HandShakeProtocols are optional in the RFC and as arguments to this function.
-//  However, if not provided, a default will be assigned for you.
+//  However, if not provided, a default will be assigned for you unless WithoutHandshake is used.
 func (c *Client) CreateInvitation(services []interface{}, opts ...MessageOption) (*Invitation, error) {
 	msg := &message{}
 
@@ -157,6 +187,10 @@ func (c *Client) CreateInvitation(services []interface{}, opts ...MessageOption)
 		opt(msg)
 	}
 
+	if msg.NoHandshake && (len(msg.HandshakeProtocols) > 0 || len(msg.Attachments) == 0) {
+		return nil, errors.New("an invitation without handshake requires attachments and no handshake protocols")
+	}
+
 	inv := &Invitation{
 		ID:        uuid.New().String(),
 		Type:      InvitationMsgType,
@@ -183,7 +217,7 @@ func (c *Client) CreateInvitation(services []interface{}, opts ...MessageOption)
 		}
 	}
 
-	if len(inv.Protocols) == 0 {
+	if len(inv.Protocols) == 0 && !msg.NoHandshake {
 		// TODO should be injected into client
 		//  https://github.com/hyperledger/aries-framework-go/issues/1691
 		inv.Protocols = []string{didexchange.PIURI}
@@ -191,7 +225,17 @@ func (c *Client) CreateInvitation(services []interface{}, opts ...MessageOption)
 
 	cast := outofband.Invitation(*inv)
 
-	err := c.oobService.SaveInvitation(&cast)
+	var invOpts []outofband.InvitationOpt
+
+	if msg.MaxUses > 0 {
+		invOpts = append(invOpts, outofband.WithMaxUses(msg.MaxUses))
+	}
+
+	if msg.ExpiresAt != nil {
+		invOpts = append(invOpts, outofband.WithExpiry(*msg.ExpiresAt))
+	}
+
+	err := c.oobService.SaveInvitation(&cast, invOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save outofband invitation : %w", err)
 	}
@@ -199,6 +243,110 @@ func (c *Client) CreateInvitation(services []interface{}, opts ...MessageOption)
 	return inv, nil
 }
 
+// GetInvitationRecord returns the record of the invitation created by the agent.
+func (c *Client) GetInvitationRecord(invitationID string) (*InvitationRecord, error) {
+	record, err := c.oobService.GetInvitationRecord(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return (*InvitationRecord)(record), nil
+}
+
+// InvitationRecords returns the records of the invitations created by the agent.
+func (c *Client) InvitationRecords() ([]*InvitationRecord, error) {
+	records, err := c.oobService.InvitationRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*InvitationRecord, len(records))
+	for i, record := range records {
+		result[i] = (*InvitationRecord)(record)
+	}
+
+	return result, nil
+}
+
+// RevokeInvitation revokes the invitation created by the agent, the connection requests referencing it are rejected.
+func (c *Client) RevokeInvitation(invitationID string) error {
+	return c.oobService.RevokeInvitation(invitationID)
+}
+
+// InvitationURL encodes the invitation into the URL, e.g https://example.com?oob=eyJAdHlwZSI6...
+func (c *Client) InvitationURL(baseURL string, inv *Invitation) (string, error) {
+	return outofband.EncodeInvitationURL(baseURL, (*outofband.Invitation)(inv))
+}
+
+// ShortInvitationURL returns the shortened URL of the invitation created by the agent,
+// e.g https://example.com/ssi?id=1234. The agent serving the invitations must be reachable at baseURL.
+func (c *Client) ShortInvitationURL(baseURL, invitationID string) (string, error) {
+	return outofband.ShortInvitationURL(baseURL, invitationID)
+}
+
+// InvitationQRPayload returns the payload of the QR code of the invitation created by the agent.
+// The invitation URL is used unless it is too long to be scanned, the shortened URL is used instead.
+func (c *Client) InvitationQRPayload(baseURL string, inv *Invitation) (string, error) {
+	invURL, err := c.InvitationURL(baseURL, inv)
+	if err != nil {
+		return "", err
+	}
+
+	if len(invURL) <= maxQRPayloadLength {
+		return invURL, nil
+	}
+
+	return c.ShortInvitationURL(baseURL, inv.ID)
+}
+
+// ResolveInvitationURL returns the invitation of the invitation URL or the shortened invitation URL.
+// The shortened URL is resolved by an HTTP request, the invitation is either returned or redirected to.
+func (c *Client) ResolveInvitationURL(invitationURL string) (*Invitation, error) {
+	inv, err := outofband.DecodeInvitationURL(invitationURL)
+	if err == nil {
+		return (*Invitation)(inv), nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, invitationURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolve invitation url: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve invitation url: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, errRead := ioutil.ReadAll(resp.Body)
+		if errRead != nil {
+			return nil, fmt.Errorf("resolve invitation url: read response: %w", errRead)
+		}
+
+		result := &Invitation{}
+
+		if err = json.Unmarshal(body, result); err != nil {
+			return nil, fmt.Errorf("resolve invitation url: unmarshal invitation: %w", err)
+		}
+
+		return result, nil
+	case http.StatusFound, http.StatusMovedPermanently, http.StatusSeeOther, http.StatusTemporaryRedirect:
+		inv, err = outofband.DecodeInvitationURL(resp.Header.Get("Location"))
+		if err != nil {
+			return nil, fmt.Errorf("resolve invitation url: %w", err)
+		}
+
+		return (*Invitation)(inv), nil
+	default:
+		return nil, fmt.Errorf("resolve invitation url: unexpected status %d", resp.StatusCode)
+	}
+}
+
 // Actions returns unfinished actions for the async usage.
 func (c *Client) Actions() ([]Action, error) {
 	actions, err := c.oobService.Actions()
@@ -295,6 +443,14 @@ func WithHandshakeProtocols(proto ...string) MessageOption {
 	}
 }
 
+// WithoutHandshake creates an invitation without handshake protocols. The attached requests are exchanged
+// without a connection, the messages carry the ~service decorator instead. Attachments are required.
+func WithoutHandshake() MessageOption {
+	return func(m *message) {
+		m.NoHandshake = true
+	}
+}
+
 // WithAttachments allows you to include attachments in the Invitation.
 func WithAttachments(a ...*decorator.Attachment) MessageOption {
 	return func(m *message) {
@@ -302,6 +458,21 @@ func WithAttachments(a ...*decorator.Attachment) MessageOption {
 	}
 }
 
+// WithMaxUses limits the number of connections the created invitation can be used for,
+// e.g 1 for a single-use invitation. By default, the invitation can be used an unlimited number of times.
+func WithMaxUses(maxUses int) MessageOption {
+	return func(m *message) {
+		m.MaxUses = maxUses
+	}
+}
+
+// WithExpiry sets the time the created invitation expires at. By default, the invitation never expires.
+func WithExpiry(expiresAt time.Time) MessageOption {
+	return func(m *message) {
+		m.ExpiresAt = &expiresAt
+	}
+}
+
 // WithAccept will set the given media types in the Invitation's `accept` property.
 func WithAccept(a ...string) MessageOption {
 	return func(m *message) {
@@ -329,6 +500,12 @@ func ReuseConnection(theirDID string) MessageOption {
 	}
 }
 
+func closeResponseBody(respBody io.Closer) {
+	if err := respBody.Close(); err != nil {
+		logger.Errorf("failed to close response body: %s", err)
+	}
+}
+
 func validateServices(svcs ...interface{}) error {
 	for i := range svcs {
 		switch svc := svcs[i].(type) {
@@ -10,6 +10,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -191,6 +194,192 @@ func TestCreateInvitation(t *testing.T) {
 		require.NoError(t, err)
 		require.Contains(t, inv.Requests, expected)
 	})
+	t.Run("WithoutHandshake", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+		expected := dummyAttachment(t)
+		inv, err := c.CreateInvitation(
+			nil,
+			WithAttachments(expected),
+			WithoutHandshake(),
+		)
+		require.NoError(t, err)
+		require.Empty(t, inv.Protocols)
+		require.Contains(t, inv.Requests, expected)
+	})
+	t.Run("WithoutHandshake requires attachments", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+		_, err = c.CreateInvitation(nil, WithoutHandshake())
+		require.EqualError(t, err, "an invitation without handshake requires attachments and no handshake protocols")
+		_, err = c.CreateInvitation(nil, WithoutHandshake(), WithAttachments(dummyAttachment(t)),
+			WithHandshakeProtocols(didexchange.PIURI))
+		require.Error(t, err)
+	})
+}
+
+func TestClient_InvitationLifecycle(t *testing.T) {
+	t.Run("create invitation with usage limit and expiry", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			saveInvFunc: func(i *outofband.Invitation, opts ...outofband.InvitationOpt) error {
+				record := &outofband.InvitationRecord{}
+				for _, opt := range opts {
+					opt(record)
+				}
+
+				require.Equal(t, 1, record.MaxUses)
+				require.Equal(t, expiresAt, *record.ExpiresAt)
+
+				return nil
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation(nil, WithMaxUses(1), WithExpiry(expiresAt))
+		require.NoError(t, err)
+	})
+
+	t.Run("invitation records", func(t *testing.T) {
+		expected := &outofband.InvitationRecord{
+			Invitation: &outofband.Invitation{ID: "123"},
+			State:      outofband.InvitationStateActive,
+		}
+
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			getInvRecordFunc: func(id string) (*outofband.InvitationRecord, error) {
+				if id != expected.Invitation.ID {
+					return nil, errors.New("not found")
+				}
+
+				return expected, nil
+			},
+			invRecordsFunc: func() ([]*outofband.InvitationRecord, error) {
+				return []*outofband.InvitationRecord{expected}, nil
+			},
+			revokeInvFunc: func(id string) error {
+				require.Equal(t, expected.Invitation.ID, id)
+
+				return nil
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		record, err := c.GetInvitationRecord("123")
+		require.NoError(t, err)
+		require.Equal(t, expected.Invitation, record.Invitation)
+
+		_, err = c.GetInvitationRecord("unknown")
+		require.EqualError(t, err, "not found")
+
+		records, err := c.InvitationRecords()
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, outofband.InvitationStateActive, records[0].State)
+
+		require.NoError(t, c.RevokeInvitation("123"))
+	})
+
+	t.Run("invitation records error", func(t *testing.T) {
+		provider := withTestProvider()
+		provider.ServiceMap[outofband.Name] = &stubOOBService{
+			invRecordsFunc: func() ([]*outofband.InvitationRecord, error) {
+				return nil, errors.New("test")
+			},
+		}
+
+		c, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = c.InvitationRecords()
+		require.EqualError(t, err, "test")
+	})
+}
+
+func TestClient_InvitationURL(t *testing.T) {
+	c, err := New(withTestProvider())
+	require.NoError(t, err)
+
+	inv, err := c.CreateInvitation(nil, WithLabel("label"))
+	require.NoError(t, err)
+
+	t.Run("encode and resolve invitation url", func(t *testing.T) {
+		invURL, err := c.InvitationURL("https://example.com", inv)
+		require.NoError(t, err)
+
+		result, err := c.ResolveInvitationURL(invURL)
+		require.NoError(t, err)
+		require.Equal(t, inv.ID, result.ID)
+		require.Equal(t, "label", result.Label)
+	})
+
+	t.Run("qr payload", func(t *testing.T) {
+		payload, err := c.InvitationQRPayload("https://example.com", inv)
+		require.NoError(t, err)
+		require.Contains(t, payload, "oob=")
+
+		large := *inv
+		large.Goal = strings.Repeat("a", maxQRPayloadLength)
+
+		payload, err = c.InvitationQRPayload("https://example.com", &large)
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/ssi?id="+inv.ID, payload)
+
+		_, err = c.InvitationQRPayload(":", inv)
+		require.Error(t, err)
+	})
+
+	t.Run("resolve shortened invitation url", func(t *testing.T) {
+		invURL, err := c.InvitationURL("https://example.com", inv)
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			switch req.URL.Query().Get("id") {
+			case "json":
+				require.Equal(t, "application/json", req.Header.Get("Accept"))
+				require.NoError(t, json.NewEncoder(rw).Encode(inv))
+			case "redirect":
+				http.Redirect(rw, req, invURL, http.StatusFound)
+			case "invalid-redirect":
+				http.Redirect(rw, req, "https://example.com", http.StatusFound)
+			case "invalid-json":
+				_, err := rw.Write([]byte("{"))
+				require.NoError(t, err)
+			default:
+				rw.WriteHeader(http.StatusGone)
+			}
+		}))
+		defer srv.Close()
+
+		result, err := c.ResolveInvitationURL(srv.URL + "/ssi?id=json")
+		require.NoError(t, err)
+		require.Equal(t, inv.ID, result.ID)
+
+		result, err = c.ResolveInvitationURL(srv.URL + "/ssi?id=redirect")
+		require.NoError(t, err)
+		require.Equal(t, inv.ID, result.ID)
+
+		_, err = c.ResolveInvitationURL(srv.URL + "/ssi?id=invalid-redirect")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no oob parameter")
+
+		_, err = c.ResolveInvitationURL(srv.URL + "/ssi?id=invalid-json")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+
+		_, err = c.ResolveInvitationURL(srv.URL + "/ssi?id=revoked")
+		require.EqualError(t, err, "resolve invitation url: unexpected status 410")
+
+		_, err = c.ResolveInvitationURL("http://[::1]:namedport")
+		require.Error(t, err)
+	})
 }
 
 func TestClient_ActionContinue(t *testing.T) {
@@ -360,7 +549,10 @@ func withTestProvider() *mockprovider.Provider {
 type stubOOBService struct {
 	service.Event
 	acceptInvFunc      func(*outofband.Invitation, outofband.Options) (string, error)
-	saveInvFunc        func(*outofband.Invitation) error
+	saveInvFunc        func(*outofband.Invitation, ...outofband.InvitationOpt) error
+	getInvRecordFunc   func(string) (*outofband.InvitationRecord, error)
+	invRecordsFunc     func() ([]*outofband.InvitationRecord, error)
+	revokeInvFunc      func(string) error
 	actionsFunc        func() ([]outofband.Action, error)
 	actionContinueFunc func(string, outofband.Options) error
 	actionStopFunc     func(piid string, err error) error
@@ -374,9 +566,33 @@ func (s *stubOOBService) AcceptInvitation(i *outofband.Invitation, o outofband.O
 	return "", nil
 }
 
-func (s *stubOOBService) SaveInvitation(i *outofband.Invitation) error {
+func (s *stubOOBService) SaveInvitation(i *outofband.Invitation, opts ...outofband.InvitationOpt) error {
 	if s.saveInvFunc != nil {
-		return s.saveInvFunc(i)
+		return s.saveInvFunc(i, opts...)
+	}
+
+	return nil
+}
+
+func (s *stubOOBService) GetInvitationRecord(id string) (*outofband.InvitationRecord, error) {
+	if s.getInvRecordFunc != nil {
+		return s.getInvRecordFunc(id)
+	}
+
+	return nil, nil
+}
+
+func (s *stubOOBService) InvitationRecords() ([]*outofband.InvitationRecord, error) {
+	if s.invRecordsFunc != nil {
+		return s.invRecordsFunc()
+	}
+
+	return nil, nil
+}
+
+func (s *stubOOBService) RevokeInvitation(id string) error {
+	if s.revokeInvFunc != nil {
+		return s.revokeInvFunc(id)
 	}
 
 	return nil
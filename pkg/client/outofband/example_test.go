@@ -210,7 +210,7 @@ func getContext(agent string) *mockprovider.Provider {
 
 					return "xyz", nil
 				},
-				saveInvFunc: func(*outofband.Invitation) error { return nil },
+				saveInvFunc: func(*outofband.Invitation, ...outofband.InvitationOpt) error { return nil },
 			},
 			didsvc.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{},
 			routesvc.Coordination: &mockroute.MockMediatorSvc{
@@ -8,10 +8,23 @@ package presentproof
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// V2 is the present-proof 2.0 protocol version (DIDComm V1 connections).
+	V2 Version = "v2"
+	// V3 is the present-proof 3.0 protocol version (DIDComm V2 connections).
+	V3 Version = "v3"
 )
 
 type (
@@ -32,11 +45,44 @@ var (
 	errEmptyProposePresentation = errors.New("propose presentation message is empty")
 )
 
+// Version is the present-proof protocol version.
+type Version string
+
 // Provider contains dependencies for the protocol and is typically created by using aries.Context().
+// If the provider also gives access to the storage (see connectionProvider) the protocol version
+// is selected by the DIDComm version of the connection.
 type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// connectionProvider is an optional part of the Provider which allows looking up connections.
+type connectionProvider interface {
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// connectionlessProvider is an optional part of the Provider which allows creating requests
+// sent without a connection (see CreateConnectionlessRequestPresentation).
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+}
+
+// SendOpt represents an option for the Send* functions.
+type SendOpt func(opts *sendOpts)
+
+type sendOpts struct {
+	version Version
+}
+
+// WithVersion allows providing the protocol version of the message.
+// By default, the version is selected by the DIDComm version of the connection between myDID and theirDID.
+func WithVersion(version Version) SendOpt {
+	return func(opts *sendOpts) {
+		opts.version = version
+	}
+}
+
 // ProtocolService defines the presentproof service.
 type ProtocolService interface {
 	service.DIDComm
@@ -49,7 +95,10 @@ type ProtocolService interface {
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0037-present-proof
 type Client struct {
 	service.Event
-	service ProtocolService
+	service         ProtocolService
+	connections     *connection.Lookup
+	kms             kms.KeyManager
+	serviceEndpoint string
 }
 
 // New returns new instance of the presentproof client.
@@ -64,10 +113,24 @@ func New(ctx Provider) (*Client, error) {
 		return nil, errors.New("cast service to presentproof service failed")
 	}
 
-	return &Client{
+	client := &Client{
 		Event:   svc,
 		service: svc,
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionProvider); ok {
+		client.connections, err = connection.NewLookup(p)
+		if err != nil {
+			return nil, fmt.Errorf("new connection lookup: %w", err)
+		}
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		client.kms = p.KMS()
+		client.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return client, nil
 }
 
 // Actions returns pending actions that have yet to be executed or cancelled.
@@ -87,14 +150,57 @@ func (c *Client) Actions() ([]Action, error) {
 
 // SendRequestPresentation is used by the Verifier to send a request presentation.
 // It returns the threadID of the new instance of the protocol.
-func (c *Client) SendRequestPresentation(msg *RequestPresentation, myDID, theirDID string) (string, error) {
+func (c *Client) SendRequestPresentation(msg *RequestPresentation, myDID, theirDID string,
+	opts ...SendOpt) (string, error) {
 	if msg == nil {
 		return "", errEmptyRequestPresentation
 	}
 
 	msg.Type = presentproof.RequestPresentationMsgType
 
-	return c.service.HandleInbound(service.NewDIDCommMsgMap(msg), service.NewDIDCommContext(myDID, theirDID, nil))
+	request := service.NewDIDCommMsgMap(msg)
+	if c.version(myDID, theirDID, opts) == V3 {
+		request = service.NewDIDCommMsgMap((*presentproof.RequestPresentation)(msg).AsV3())
+	}
+
+	return c.service.HandleInbound(request, service.NewDIDCommContext(myDID, theirDID, nil))
+}
+
+// CreateConnectionlessRequestPresentation is used by the Verifier to create a request presentation sent without
+// a connection, e.g. attached to an out-of-band invitation without handshake protocols.
+// The request carries the ~service decorator the Prover replies to.
+// It returns the threadID of the new instance of the protocol and the request to deliver.
+func (c *Client) CreateConnectionlessRequestPresentation(msg *RequestPresentation) (string, service.DIDCommMsgMap,
+	error) {
+	if msg == nil {
+		return "", nil, errEmptyRequestPresentation
+	}
+
+	if c.kms == nil {
+		return "", nil, errors.New("connectionless requests are not supported by the provider")
+	}
+
+	svc, err := service.NewConnectionlessService(c.kms, c.serviceEndpoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("connectionless request presentation: %w", err)
+	}
+
+	msg.Type = presentproof.RequestPresentationMsgType
+
+	request := service.NewDIDCommMsgMap(msg)
+	request.SetServiceDecorator(svc)
+
+	// the ID becomes the threadID of the protocol, the Prover replies to the delivered request
+	if err = request.SetID(uuid.New().String()); err != nil {
+		return "", nil, err
+	}
+
+	piID, err := c.service.HandleInbound(request, service.NewDIDCommContext("", "", nil))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return piID, request, nil
 }
 
 type addProof func(presentation *verifiable.Presentation) error
@@ -116,14 +222,39 @@ func (c *Client) DeclineRequestPresentation(piID, reason string) error {
 
 // SendProposePresentation is used by the Prover to send a propose presentation.
 // It returns the threadID of the new instance of the protocol.
-func (c *Client) SendProposePresentation(msg *ProposePresentation, myDID, theirDID string) (string, error) {
+func (c *Client) SendProposePresentation(msg *ProposePresentation, myDID, theirDID string,
+	opts ...SendOpt) (string, error) {
 	if msg == nil {
 		return "", errEmptyProposePresentation
 	}
 
 	msg.Type = presentproof.ProposePresentationMsgType
 
-	return c.service.HandleInbound(service.NewDIDCommMsgMap(msg), service.NewDIDCommContext(myDID, theirDID, nil))
+	proposal := service.NewDIDCommMsgMap(msg)
+	if c.version(myDID, theirDID, opts) == V3 {
+		proposal = service.NewDIDCommMsgMap((*presentproof.ProposePresentation)(msg).AsV3())
+	}
+
+	return c.service.HandleInbound(proposal, service.NewDIDCommContext(myDID, theirDID, nil))
+}
+
+// version returns the protocol version provided by the options or
+// the version that corresponds to the DIDComm version of the connection.
+func (c *Client) version(myDID, theirDID string, opts []SendOpt) Version {
+	options := &sendOpts{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.version != "" {
+		return options.version
+	}
+
+	if c.connections != nil && c.connections.GetDIDCommVersionByDIDs(myDID, theirDID) == connection.DIDCommV2 {
+		return V3
+	}
+
+	return V2
 }
 
 // AcceptProposePresentation is used when the Verifier is willing to accept the propose presentation.
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package presentproof
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"testing"
 
@@ -14,9 +15,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/presentproof"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -43,6 +49,65 @@ func TestNew(t *testing.T) {
 		_, err := New(provider)
 		require.EqualError(t, err, "cast service to presentproof service failed")
 	})
+
+	t.Run("connection lookup error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				presentproof.Name: mocks.NewMockProtocolService(ctrl),
+			},
+			StorageProviderValue: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New(errMsg)},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestClient_Version(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := &mockprovider.Provider{
+		StorageProviderValue:              mem.NewProvider(),
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+	}
+
+	recorder, err := connection.NewRecorder(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "v2",
+		State:        connection.StateNameCompleted,
+		MyDID:        Alice,
+		TheirDID:     Bob,
+		MediaTypes:   []string{"application/didcomm-encrypted+json"},
+	}))
+
+	var expected string
+
+	svc := mocks.NewMockProtocolService(ctrl)
+	svc.EXPECT().HandleInbound(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(msg service.DIDCommMsg, _ service.DIDCommContext) (string, error) {
+			require.Equal(t, expected, msg.Type())
+
+			return "thid", nil
+		}).Times(3)
+
+	provider.ServiceMap = map[string]interface{}{presentproof.Name: svc}
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	expected = presentproof.RequestPresentationMsgTypeV3
+	_, err = client.SendRequestPresentation(&RequestPresentation{}, Alice, Bob)
+	require.NoError(t, err)
+
+	expected = presentproof.ProposePresentationMsgType
+	_, err = client.SendProposePresentation(&ProposePresentation{}, Bob, Alice)
+	require.NoError(t, err)
+
+	expected = presentproof.ProposePresentationMsgTypeV3
+	_, err = client.SendProposePresentation(&ProposePresentation{}, Bob, Alice, WithVersion(V3))
+	require.NoError(t, err)
 }
 
 func TestClient_SendRequestPresentation(t *testing.T) {
@@ -82,6 +147,75 @@ func TestClient_SendRequestPresentation(t *testing.T) {
 	})
 }
 
+func TestClient_CreateConnectionlessRequestPresentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newProvider := func(svc ProtocolService, km *mockkms.KeyManager) *mockprovider.Provider {
+		return &mockprovider.Provider{
+			ServiceMap:                        map[string]interface{}{presentproof.Name: svc},
+			StorageProviderValue:              mem.NewProvider(),
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			KMSValue:                          km,
+			ServiceEndpointValue:              "https://verifier.example.com",
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().HandleInbound(gomock.Any(), service.NewDIDCommContext("", "", nil)).
+			DoAndReturn(func(msg service.DIDCommMsg, ctx service.DIDCommContext) (string, error) {
+				require.Equal(t, msg.Type(), presentproof.RequestPresentationMsgType)
+
+				return msg.ID(), nil
+			})
+
+		client, err := New(newProvider(svc,
+			&mockkms.KeyManager{CrAndExportPubKeyValue: make([]byte, ed25519.PublicKeySize)}))
+		require.NoError(t, err)
+
+		thid, request, err := client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.NoError(t, err)
+		require.Equal(t, request.ID(), thid)
+
+		svcDecorator, err := request.ServiceDecorator()
+		require.NoError(t, err)
+		require.Len(t, svcDecorator.RecipientKeys, 1)
+		require.Equal(t, "https://verifier.example.com", svcDecorator.ServiceEndpoint)
+	})
+
+	t.Run("Create key error", func(t *testing.T) {
+		client, err := New(newProvider(mocks.NewMockProtocolService(ctrl),
+			&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New("test")}))
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.EqualError(t, err, "connectionless request presentation: create connectionless key: test")
+	})
+
+	t.Run("Not supported by the provider", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.EqualError(t, err, "connectionless requests are not supported by the provider")
+	})
+
+	t.Run("Empty Request Presentation", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConnectionlessRequestPresentation(nil)
+		require.EqualError(t, err, errEmptyRequestPresentation.Error())
+	})
+}
+
 func TestClient_SendProposePresentation(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -9,12 +9,15 @@ package vcwallet
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
+	"time"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/wallet"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
@@ -30,6 +33,10 @@ type provider interface {
 	StorageProvider() storage.Provider
 	VDRegistry() vdr.Registry
 	Crypto() crypto.Crypto
+	Service(id string) (interface{}, error)
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+	ProtocolStateStorageProvider() storage.Provider
 }
 
 // walletAuth is auth function which returns wallet unlock token.
@@ -92,6 +99,13 @@ func UpdateProfile(userID string, ctx provider, options ...wallet.ProfileKeyMana
 	return wallet.UpdateProfile(userID, ctx, options...)
 }
 
+// RecoverProfile recovers verifiable credential wallet profile of given user from recovery shares created by
+// 'Client.CreateRecoveryShares()' and locks it with the new passphrase or secret lock service provided.
+// Keys of the profile remain accessible.
+func RecoverProfile(userID string, ctx provider, shares []string, options ...wallet.ProfileKeyManagerOptions) error {
+	return wallet.RecoverProfile(userID, ctx, shares, options...)
+}
+
 // Open unlocks wallet client's key manager instance and returns a token for subsequent use of wallet features.
 //
 //	Args:
@@ -119,11 +133,62 @@ func (c *Client) Close() bool {
 	return c.wallet.Close()
 }
 
+// CreateSession creates a new wallet token with given scope, which can be handed over to a party
+// needing restricted access to this wallet. Requires wallet client to be opened.
+//
+//	Args:
+//		- scope of the new token (read, sign or admin).
+//		- expiry of the new token.
+//
+//	Returns new token which can be revoked independently of wallet client's own token.
+func (c *Client) CreateSession(scope wallet.TokenScope, expiry time.Duration) (string, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return "", err
+	}
+
+	return c.wallet.CreateSession(auth, scope, expiry)
+}
+
+// Sessions returns all active sessions of this wallet.
+func (c *Client) Sessions() ([]*wallet.Session, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wallet.Sessions(auth)
+}
+
+// RevokeSession revokes given wallet token.
+func (c *Client) RevokeSession(token string) error {
+	auth, err := c.auth()
+	if err != nil {
+		return err
+	}
+
+	return c.wallet.RevokeSession(auth, token)
+}
+
+// CreateRecoveryShares splits the master key of this wallet profile into recovery shares to be handed over to
+// guardians, any threshold number of which can recover the profile using 'RecoverProfile()'.
+//
+//	Args:
+//		- number of shares to be created.
+//		- threshold number of shares required to recover the profile.
+//		- unlock options (passphrase or secret lock service of the profile) for accessing the master key.
+//
+//	Returns recovery shares as printable strings.
+func (c *Client) CreateRecoveryShares(shares, threshold int, options ...wallet.UnlockOptions) ([]string, error) {
+	return c.wallet.CreateRecoveryShares(shares, threshold, options...)
+}
+
 // Export produces a serialized exported wallet representation.
-// Only ciphertext wallet contents can be exported.
+// All wallet contents are exported as a Universal Wallet 'EncryptedWallet' locked by key derived from passphrase
+// or by secret lock service supplied in options.
 //
 //	Args:
-//		- auth: token to be used to lock the wallet before exporting.
+//		- options for locking exported wallet.
 //
 //	Returns exported locked wallet.
 //
@@ -133,10 +198,15 @@ func (c *Client) Close() bool {
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#DIDResolutionResponse
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-func (c *Client) Export(auth string) (json.RawMessage, error) {
-	// TODO to be added #2433
-	return nil, fmt.Errorf("to be implemented")
+func (c *Client) Export(options ...wallet.ExportOptions) (json.RawMessage, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wallet.Export(auth, options...)
 }
 
 // Import Takes a serialized exported wallet representation as input
@@ -144,7 +214,7 @@ func (c *Client) Export(auth string) (json.RawMessage, error) {
 //
 //	Args:
 //		- contents: wallet content to be imported.
-//		- auth: token used while exporting the wallet.
+//		- options for unlocking imported wallet and for handling contents already present in wallet.
 //
 // Supported data models:
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
@@ -154,9 +224,13 @@ func (c *Client) Export(auth string) (json.RawMessage, error) {
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-func (c *Client) Import(auth string, contents json.RawMessage) error {
-	// TODO to be added #2433
-	return fmt.Errorf("to be implemented")
+func (c *Client) Import(contents json.RawMessage, options ...wallet.ImportOptions) error {
+	auth, err := c.auth()
+	if err != nil {
+		return err
+	}
+
+	return c.wallet.Import(auth, contents, options...)
 }
 
 // Add adds given data model to wallet contents store.
@@ -169,14 +243,15 @@ func (c *Client) Import(auth string, contents json.RawMessage) error {
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-// TODO: (#2433) support for correlation between wallet contents (ex: credentials to a profile/collection).
-func (c *Client) Add(contentType wallet.ContentType, content json.RawMessage) error {
+// Contents can be added to one or more collections by using 'wallet.AddByCollection()' option.
+func (c *Client) Add(contentType wallet.ContentType, content json.RawMessage,
+	options ...wallet.AddContentOptions) error {
 	auth, err := c.auth()
 	if err != nil {
 		return err
 	}
 
-	return c.wallet.Add(auth, contentType, content)
+	return c.wallet.Add(auth, contentType, content, options...)
 }
 
 // Remove removes wallet content by content ID.
@@ -192,6 +267,19 @@ func (c *Client) Remove(contentType wallet.ContentType, contentID string) error
 	return c.wallet.Remove(contentType, contentID)
 }
 
+// AddToCollection adds an existing wallet content to given collections, collections should already exist in wallet.
+//
+// Supported data models:
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Credential
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#DIDResolutionResponse
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
+//
+func (c *Client) AddToCollection(contentType wallet.ContentType, contentID string, collectionIDs ...string) error {
+	return c.wallet.AddToCollection(contentType, contentID, collectionIDs...)
+}
+
 // Get fetches a wallet content by content ID.
 //
 // Supported data models:
@@ -206,6 +294,7 @@ func (c *Client) Get(contentType wallet.ContentType, contentID string) (json.Raw
 }
 
 // GetAll fetches all wallet contents of given type.
+// Contents of a collection can be listed by using 'wallet.WithCollection()' option.
 //
 // Supported data models:
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
@@ -214,8 +303,9 @@ func (c *Client) Get(contentType wallet.ContentType, contentID string) (json.Raw
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //
-func (c *Client) GetAll(contentType wallet.ContentType) (map[string]json.RawMessage, error) {
-	return c.wallet.GetAll(contentType)
+func (c *Client) GetAll(contentType wallet.ContentType,
+	options ...wallet.GetAllContentsOptions) (map[string]json.RawMessage, error) {
+	return c.wallet.GetAll(contentType, options...)
 }
 
 // Query runs query against wallet credential contents and returns presentation containing credential results.
@@ -231,6 +321,25 @@ func (c *Client) Query(params ...*wallet.QueryParams) ([]*verifiable.Presentatio
 	return c.wallet.Query(params...)
 }
 
+// QueryMatch runs 'PresentationExchange' queries against wallet credential contents and returns the candidate
+// credentials of each input descriptor of the presentation definitions, grouped by their submission requirements.
+//
+// The result can be used to let user choose among alternative credentials before creating a presentation using Prove.
+//
+func (c *Client) QueryMatch(params ...*wallet.QueryParams) ([]*wallet.QueryMatch, error) {
+	return c.wallet.QueryMatch(params...)
+}
+
+// MatchSubmissionRequirement matches wallet credentials against given presentation definition and returns
+// the candidate credentials for each input descriptor, grouped by the submission requirements of the definition.
+//
+// The result can be used to let user choose among alternative credentials before creating a presentation using Prove.
+//
+func (c *Client) MatchSubmissionRequirement(
+	presentationDefinition json.RawMessage) ([]*presexch.MatchedSubmissionRequirement, error) {
+	return c.wallet.MatchSubmissionRequirement(presentationDefinition)
+}
+
 // Issue adds proof to a Verifiable Credential.
 //
 //	Args:
@@ -273,6 +382,17 @@ func (c *Client) Verify(option wallet.VerificationOption) (bool, error) {
 	return c.wallet.Verify(option)
 }
 
+// VerificationReport verifies a Verifiable Credential or Verifiable Presentation and returns the result
+// of each verification check.
+//
+//	Args:
+//		- verification option for sending different models (stored credential ID, raw credential, raw presentation).
+//
+// Returns: verification report, and an error if verification couldn't be performed.
+func (c *Client) VerificationReport(option wallet.VerificationOption) (*verifiable.VerificationReport, error) {
+	return c.wallet.VerificationReport(option)
+}
+
 // Derive derives a credential and returns response credential.
 //
 //	Args:
@@ -282,3 +402,67 @@ func (c *Client) Verify(option wallet.VerificationOption) (bool, error) {
 func (c *Client) Derive(credential wallet.CredentialToDerive, options *wallet.DeriveOptions) (*verifiable.Credential, error) { //nolint: lll
 	return c.wallet.Derive(credential, options)
 }
+
+// Connect accepts out-of-band invitation and performs DID exchange.
+//
+//	Args:
+//		- invitation: out-of-band invitation from the other agent.
+//		- options: options for accepting invitation and connecting to the other agent.
+//
+// Returns: ID of the connection established, and an error if operation fails.
+func (c *Client) Connect(invitation *outofband.Invitation, options ...wallet.ConnectOptions) (string, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return "", err
+	}
+
+	return c.wallet.Connect(auth, invitation, options...)
+}
+
+// ProposePresentation accepts out-of-band invitation from a verifier, sends propose presentation message
+// and returns request presentation received along with matching credentials from wallet.
+//
+//	Args:
+//		- invitation: out-of-band invitation from the verifier.
+//		- options: options for connecting to the verifier and for waiting for the request presentation.
+//
+func (c *Client) ProposePresentation(invitation *outofband.Invitation,
+	options ...wallet.InitiateInteractionOption) (*wallet.PresentationRequest, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wallet.ProposePresentation(auth, invitation, options...)
+}
+
+// PresentProof sends given presentation as a response to the request presentation of given thread.
+//
+//	Args:
+//		- thID: thread ID of the present proof interaction.
+//		- presentation: presentation to be sent.
+//
+func (c *Client) PresentProof(thID string, presentation *verifiable.Presentation) error {
+	auth, err := c.auth()
+	if err != nil {
+		return err
+	}
+
+	return c.wallet.PresentProof(auth, thID, presentation)
+}
+
+// AcceptCredentialOffer accepts credential offer of given thread and saves the issued credential(s) to wallet.
+//
+//	Args:
+//		- thID: thread ID of the issue credential interaction.
+//		- options: options for saving credentials received and for waiting for the credential to be issued.
+//
+func (c *Client) AcceptCredentialOffer(thID string,
+	options ...wallet.ConcludeInteractionOptions) ([]*verifiable.Credential, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wallet.AcceptCredentialOffer(auth, thID, options...)
+}
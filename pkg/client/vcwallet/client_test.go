@@ -21,6 +21,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
@@ -44,7 +45,6 @@ const (
 	sampleRemoteKMSAuth = "sample-auth-token"
 	sampleKeyServerURL  = "sample/keyserver/test"
 	sampleUserID        = "sample-user01"
-	toBeImplementedErr  = "to be implemented"
 	sampleClientErr     = "sample client err"
 	sampleDIDKey        = "did:key:z6MknC1wwS6DEYwtGbZZo2QvjQjkh2qSBjb4GYmbye8dv4S5"
 	sampleDIDKey2       = "did:key:z6MkwFKUCsf8wvn6eSSu1WFAKatN1yexiDM7bf7pZLSFjdz6"
@@ -726,18 +726,68 @@ func TestClient_OpenClose(t *testing.T) {
 }
 
 func TestClient_Export(t *testing.T) {
-	mockctx := newMockProvider()
-	err := CreateProfile(sampleUserID, mockctx, wallet.WithKeyServerURL(sampleKeyServerURL))
+	sampleCrypto, err := tinkcrypto.New()
 	require.NoError(t, err)
 
+	mockctx := newMockProvider()
+	mockctx.CryptoValue = sampleCrypto
+
+	createSampleProfile(t, mockctx)
+
+	t.Run("export locked wallet - failure", func(t *testing.T) {
+		vcWalletClient, err := New(sampleUserID, mockctx)
+		require.NotEmpty(t, vcWalletClient)
+		require.NoError(t, err)
+
+		result, err := vcWalletClient.Export(wallet.WithExportPassphrase(samplePassPhrase))
+		require.Empty(t, result)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+
+	t.Run("export and import wallet - success", func(t *testing.T) {
+		vcWalletClient, err := New(sampleUserID, mockctx, wallet.WithUnlockByPassphrase(samplePassPhrase))
+		require.NotEmpty(t, vcWalletClient)
+		require.NoError(t, err)
+
+		defer vcWalletClient.Close()
+
+		require.NoError(t, vcWalletClient.Add(wallet.Credential, []byte(sampleUDCVC)))
+		require.NoError(t, vcWalletClient.Add(wallet.Key, []byte(sampleKeyContentBase58)))
+
+		result, err := vcWalletClient.Export(wallet.WithExportPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+		require.NotEmpty(t, result)
+
+		require.NoError(t, vcWalletClient.Remove(wallet.Credential, "http://example.edu/credentials/1872"))
+		require.NoError(t, vcWalletClient.Import(result, wallet.WithImportPassphrase(samplePassPhrase),
+			wallet.WithImportConflict(wallet.ImportConflictSkip)))
+
+		vc, err := vcWalletClient.Get(wallet.Credential, "http://example.edu/credentials/1872")
+		require.NoError(t, err)
+		require.JSONEq(t, sampleUDCVC, string(vc))
+	})
+}
+
+func TestClient_RecoverProfile(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
 	vcWalletClient, err := New(sampleUserID, mockctx)
-	require.NotEmpty(t, vcWalletClient)
 	require.NoError(t, err)
 
-	result, err := vcWalletClient.Export("")
-	require.Empty(t, result)
-	require.Error(t, err)
-	require.EqualError(t, err, toBeImplementedErr)
+	shares, err := vcWalletClient.CreateRecoveryShares(3, 2, wallet.WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+
+	err = RecoverProfile(sampleUserID, mockctx, shares[1:], wallet.WithPassphrase("new-passphrase"))
+	require.NoError(t, err)
+
+	vcWalletClient, err = New(sampleUserID, mockctx, wallet.WithUnlockByPassphrase("new-passphrase"))
+	require.NoError(t, err)
+	require.True(t, vcWalletClient.Close())
+
+	err = RecoverProfile(sampleUserID, mockctx, shares[:1], wallet.WithPassphrase("new-passphrase"))
+	require.EqualError(t, err, "failed to recover wallet master key: not enough recovery shares: 1 provided, 2 required")
 }
 
 func TestClient_Import(t *testing.T) {
@@ -749,9 +799,17 @@ func TestClient_Import(t *testing.T) {
 	require.NotEmpty(t, vcWalletClient)
 	require.NoError(t, err)
 
-	err = vcWalletClient.Import("", nil)
+	err = vcWalletClient.Import(nil)
+	require.True(t, errors.Is(err, ErrWalletLocked))
+
+	err = vcWalletClient.Open(wallet.WithUnlockByAuthorizationToken(sampleRemoteKMSAuth))
+	require.NoError(t, err)
+
+	defer vcWalletClient.Close()
+
+	err = vcWalletClient.Import([]byte(sampleUDCVC), wallet.WithImportPassphrase(samplePassPhrase))
 	require.Error(t, err)
-	require.EqualError(t, err, toBeImplementedErr)
+	require.Contains(t, err.Error(), "type 'EncryptedWallet' is required")
 }
 
 func TestClient_Add(t *testing.T) {
@@ -829,6 +887,31 @@ func TestClient_GetAll(t *testing.T) {
 	require.Len(t, vcs, count)
 }
 
+func TestClient_AddToCollection(t *testing.T) {
+	const collectionID = "did:example:collection:relying-party"
+
+	mockctx := newMockProvider()
+	err := CreateProfile(sampleUserID, mockctx, wallet.WithKeyServerURL(sampleKeyServerURL))
+	require.NoError(t, err)
+
+	vcWalletClient, err := New(sampleUserID, mockctx, wallet.WithUnlockByPassphrase(samplePassPhrase))
+	require.NotEmpty(t, vcWalletClient)
+	require.NoError(t, err)
+
+	require.NoError(t, vcWalletClient.Add(wallet.Collection, []byte(fmt.Sprintf(
+		`{"@context": ["https://w3id.org/wallet/v1"], "id": "%s", "type": "Collection"}`, collectionID))))
+	require.NoError(t, vcWalletClient.Add(wallet.Metadata, []byte(sampleContentValid)))
+
+	require.NoError(t, vcWalletClient.AddToCollection(wallet.Metadata, "did:example:123456789abcdefghi", collectionID))
+
+	members, err := vcWalletClient.GetAll(wallet.Metadata, wallet.WithCollection(collectionID))
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	err = vcWalletClient.AddToCollection(wallet.Metadata, "did:example:unknown", collectionID)
+	require.Error(t, err)
+}
+
 func TestClient_Remove(t *testing.T) {
 	mockctx := newMockProvider()
 	err := CreateProfile(sampleUserID, mockctx, wallet.WithKeyServerURL(sampleKeyServerURL))
@@ -1030,6 +1113,74 @@ func TestClient_Query(t *testing.T) {
 	})
 }
 
+func TestClient_MatchSubmissionRequirement(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, wallet.WithPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	vcWalletClient, err := New(sampleUserID, mockctx, wallet.WithUnlockByPassphrase(samplePassPhrase))
+	require.NotEmpty(t, vcWalletClient)
+	require.NoError(t, err)
+
+	defer vcWalletClient.Close()
+
+	vc, err := (&verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		Types:   []string{verifiable.VCType},
+		ID:      "http://example.edu/credentials/9999",
+		Schemas: []verifiable.TypedID{{
+			ID:   schemaURI,
+			Type: "JsonSchemaValidator2018",
+		}},
+		CustomFields: map[string]interface{}{
+			"first_name": "Jesse",
+		},
+		Issued: &util.TimeWithTrailingZeroMsec{
+			Time: time.Now(),
+		},
+		Issuer: verifiable.Issuer{
+			ID: "did:example:76e12ec712ebc6f1c221ebfeb1f",
+		},
+		Subject: uuid.New().String(),
+	}).MarshalJSON()
+	require.NoError(t, err)
+
+	require.NoError(t, vcWalletClient.Add(wallet.Credential, vc))
+
+	pdJSON, err := json.Marshal(&presexch.PresentationDefinition{
+		ID: uuid.New().String(),
+		InputDescriptors: []*presexch.InputDescriptor{{
+			ID: uuid.New().String(),
+			Schema: []*presexch.Schema{{
+				URI: schemaURI,
+			}},
+			Constraints: &presexch.Constraints{
+				Fields: []*presexch.Field{{
+					Path: []string{"$.first_name"},
+				}},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	requirements, err := vcWalletClient.MatchSubmissionRequirement(pdJSON)
+	require.NoError(t, err)
+	require.Len(t, requirements, 1)
+	require.True(t, requirements[0].Satisfied())
+	require.Len(t, requirements[0].Descriptors, 1)
+	require.Len(t, requirements[0].Descriptors[0].MatchedVCs, 1)
+	require.Equal(t, "http://example.edu/credentials/9999", requirements[0].Descriptors[0].MatchedVCs[0].ID)
+
+	matches, err := vcWalletClient.QueryMatch(&wallet.QueryParams{
+		Type:  "PresentationExchange",
+		Query: []json.RawMessage{pdJSON},
+	})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Equal(t, requirements, matches[0].Requirements)
+}
+
 func TestClient_Issue(t *testing.T) {
 	customVDR := &mockvdr.MockVDRegistry{
 		ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
@@ -1294,6 +1445,31 @@ func TestClient_Verify(t *testing.T) {
 	})
 }
 
+func TestClient_VerificationReport(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, wallet.WithPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	vcWalletClient, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, vcWalletClient)
+	require.NoError(t, err)
+
+	t.Run("Test VC wallet verification report - malformed credential", func(t *testing.T) {
+		report, err := vcWalletClient.VerificationReport(wallet.WithRawCredentialToVerify([]byte(`{"id":`)))
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.False(t, report.Verified)
+	})
+
+	t.Run("Test VC wallet verification report - invalid request", func(t *testing.T) {
+		report, err := vcWalletClient.VerificationReport(wallet.WithStoredCredentialToVerify(""))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid verify request")
+		require.Nil(t, report)
+	})
+}
+
 func TestWallet_Derive(t *testing.T) {
 	customVDR := &mockvdr.MockVDRegistry{
 		ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
@@ -1424,6 +1600,83 @@ func TestWallet_Derive(t *testing.T) {
 	})
 }
 
+func TestClient_Sessions(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
+	t.Run("test wallet sessions - success", func(t *testing.T) {
+		vcWalletClient, err := New(sampleUserID, mockctx, wallet.WithUnlockByPassphrase(samplePassPhrase))
+		require.NotEmpty(t, vcWalletClient)
+		require.NoError(t, err)
+
+		defer vcWalletClient.Close()
+
+		token, err := vcWalletClient.CreateSession(wallet.ReadScope, time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+
+		sessions, err := vcWalletClient.Sessions()
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		require.NoError(t, vcWalletClient.RevokeSession(token))
+
+		sessions, err = vcWalletClient.Sessions()
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		require.Equal(t, wallet.AdminScope, sessions[0].Scope)
+	})
+
+	t.Run("test wallet sessions - wallet locked", func(t *testing.T) {
+		vcWalletClient, err := New(sampleUserID, mockctx)
+		require.NotEmpty(t, vcWalletClient)
+		require.NoError(t, err)
+
+		token, err := vcWalletClient.CreateSession(wallet.ReadScope, time.Minute)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, token)
+
+		sessions, err := vcWalletClient.Sessions()
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, sessions)
+
+		err = vcWalletClient.RevokeSession(uuid.New().String())
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+}
+
+func TestClient_DIDComm(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
+	vcWalletClient, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, vcWalletClient)
+	require.NoError(t, err)
+
+	t.Run("test connect - wallet locked", func(t *testing.T) {
+		connID, err := vcWalletClient.Connect(&outofband.Invitation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, connID)
+	})
+
+	t.Run("test propose presentation - wallet locked", func(t *testing.T) {
+		request, err := vcWalletClient.ProposePresentation(&outofband.Invitation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, request)
+	})
+
+	t.Run("test present proof - wallet locked", func(t *testing.T) {
+		err := vcWalletClient.PresentProof(uuid.New().String(), &verifiable.Presentation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+
+	t.Run("test accept credential offer - wallet locked", func(t *testing.T) {
+		credentials, err := vcWalletClient.AcceptCredentialOffer(uuid.New().String())
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, credentials)
+	})
+}
+
 func newMockProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{StorageProviderValue: mockstorage.NewMockStoreProvider()}
 }
@@ -9,6 +9,7 @@ package command
 import (
 	"io"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 )
 
@@ -40,3 +41,18 @@ type MessageHandler interface {
 type Notifier interface {
 	Notify(topic string, message []byte) error
 }
+
+// ActionFilter handles some of the action events (e.g auto-accept rules),
+// the events which are not handled are passed to the returned channel.
+type ActionFilter interface {
+	Filter(actions <-chan service.DIDCommAction) <-chan service.DIDCommAction
+}
+
+// FilterActions passes the action events through the given filters in order.
+func FilterActions(actions <-chan service.DIDCommAction, filters ...ActionFilter) <-chan service.DIDCommAction {
+	for _, filter := range filters {
+		actions = filter.Filter(actions)
+	}
+
+	return actions
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/controller/autoaccept")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.AutoAccept)
+	// SetRulesErrorCode is for failures in set rules command.
+	SetRulesErrorCode
+	// SaveConsentErrorCode is for failures in save consent command.
+	SaveConsentErrorCode
+	// RemoveConsentErrorCode is for failures in remove consent command.
+	RemoveConsentErrorCode
+	// GetConsentsErrorCode is for failures in get consents command.
+	GetConsentsErrorCode
+)
+
+// constants for the auto-accept commands.
+const (
+	// command name.
+	CommandName = "autoaccept"
+
+	// command methods.
+	SetRules      = "SetRules"
+	GetRules      = "GetRules"
+	SaveConsent   = "SaveConsent"
+	RemoveConsent = "RemoveConsent"
+	GetConsents   = "GetConsents"
+
+	// error messages.
+	errEmptyID = "empty ID"
+)
+
+// Command is controller command for the auto-accept rules.
+type Command struct {
+	client *autoaccept.Client
+}
+
+// New returns new auto-accept controller command instance.
+// The given client has to be the one the protocol action events are filtered with.
+func New(client *autoaccept.Client) *Command {
+	return &Command{client: client}
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, SetRules, c.SetRules),
+		cmdutil.NewCommandHandler(CommandName, GetRules, c.GetRules),
+		cmdutil.NewCommandHandler(CommandName, SaveConsent, c.SaveConsent),
+		cmdutil.NewCommandHandler(CommandName, RemoveConsent, c.RemoveConsent),
+		cmdutil.NewCommandHandler(CommandName, GetConsents, c.GetConsents),
+	}
+}
+
+// SetRules replaces the auto-accept rules.
+func (c *Command) SetRules(rw io.Writer, req io.Reader) command.Error {
+	var args RulesArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, SetRules, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if err := c.client.SetRules(args.Rules); err != nil {
+		logutil.LogError(logger, CommandName, SetRules, err.Error())
+
+		return command.NewExecuteError(SetRulesErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, SetRules, "success")
+
+	return nil
+}
+
+// GetRules returns the auto-accept rules.
+func (c *Command) GetRules(rw io.Writer, _ io.Reader) command.Error {
+	command.WriteNillableResponse(rw, &RulesArgs{Rules: c.client.Rules()}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetRules, "success")
+
+	return nil
+}
+
+// SaveConsent saves the consent to present the given presentation for the requests of the presentation definition.
+func (c *Command) SaveConsent(rw io.Writer, req io.Reader) command.Error {
+	var args SaveConsentArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, SaveConsent, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if err := c.client.SaveConsent(&args.Consent); err != nil {
+		logutil.LogError(logger, CommandName, SaveConsent, err.Error())
+
+		return command.NewExecuteError(SaveConsentErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, SaveConsent, "success")
+
+	return nil
+}
+
+// RemoveConsent removes the consent.
+func (c *Command) RemoveConsent(rw io.Writer, req io.Reader) command.Error {
+	var args RemoveConsentArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, RemoveConsent, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ID == "" {
+		logutil.LogDebug(logger, CommandName, RemoveConsent, errEmptyID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyID))
+	}
+
+	if err := c.client.RemoveConsent(args.ID); err != nil {
+		logutil.LogError(logger, CommandName, RemoveConsent, err.Error())
+
+		return command.NewExecuteError(RemoveConsentErrorCode, fmt.Errorf("remove consent: %w", err))
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, RemoveConsent, "success")
+
+	return nil
+}
+
+// GetConsents returns the saved consents.
+func (c *Command) GetConsents(rw io.Writer, _ io.Reader) command.Error {
+	consents, err := c.client.Consents()
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetConsents, err.Error())
+
+		return command.NewExecuteError(GetConsentsErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ConsentsResponse{Consents: consents}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetConsents, "success")
+
+	return nil
+}
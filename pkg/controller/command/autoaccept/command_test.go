@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func newCommand(t *testing.T) *Command {
+	t.Helper()
+
+	client, err := autoaccept.New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NoError(t, err)
+
+	return New(client)
+}
+
+func TestNew(t *testing.T) {
+	cmd := newCommand(t)
+	require.NotNil(t, cmd)
+	require.Len(t, cmd.GetHandlers(), 5)
+}
+
+func TestCommand_Rules(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		args := RulesArgs{Rules: []autoaccept.Rule{{
+			ID:         "trusted",
+			Protocol:   "issue-credential",
+			IssuerDIDs: []string{"did:example:issuer"},
+		}}}
+
+		src, err := json.Marshal(args)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.SetRules(&b, bytes.NewBuffer(src)))
+
+		b.Reset()
+		require.NoError(t, cmd.GetRules(&b, nil))
+
+		var res RulesArgs
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, args, res)
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		cmdErr := newCommand(t).SetRules(&bytes.Buffer{}, bytes.NewBufferString("{"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		cmdErr := newCommand(t).SetRules(&bytes.Buffer{}, bytes.NewBufferString(`{"rules":[{"id":"1"}]}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SetRulesErrorCode, cmdErr.Code())
+	})
+}
+
+func TestCommand_Consents(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		consent := `{"id":"1","presentation_definition_id":"def","credentials":[{"id":"vc"}],` +
+			`"verification_method":"did:example:holder#key-1"}`
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.SaveConsent(&b, bytes.NewBufferString(consent)))
+
+		b.Reset()
+		require.NoError(t, cmd.GetConsents(&b, nil))
+
+		var res ConsentsResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Len(t, res.Consents, 1)
+		require.JSONEq(t, `{"id":"vc"}`, string(res.Consents[0].Credentials[0]))
+
+		require.NoError(t, cmd.RemoveConsent(&b, bytes.NewBufferString(`{"id":"1"}`)))
+
+		b.Reset()
+		require.NoError(t, cmd.GetConsents(&b, nil))
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Empty(t, res.Consents)
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		cmd := newCommand(t)
+
+		cmdErr := cmd.SaveConsent(&bytes.Buffer{}, bytes.NewBufferString("{"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.SaveConsent(&bytes.Buffer{}, bytes.NewBufferString(`{"id":"1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SaveConsentErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.RemoveConsent(&bytes.Buffer{}, bytes.NewBufferString("{"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.RemoveConsent(&bytes.Buffer{}, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, errEmptyID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		provider := mockstore.NewMockStoreProvider()
+
+		client, err := autoaccept.New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.NoError(t, err)
+
+		cmd := New(client)
+
+		provider.Store.ErrQuery = errors.New("query error")
+		cmdErr := cmd.GetConsents(&bytes.Buffer{}, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, GetConsentsErrorCode, cmdErr.Code())
+
+		provider.Store.ErrDelete = errors.New("delete error")
+		cmdErr = cmd.RemoveConsent(&bytes.Buffer{}, bytes.NewBufferString(`{"id":"1"}`))
+		require.EqualError(t, cmdErr, "remove consent: delete error")
+		require.Equal(t, RemoveConsentErrorCode, cmdErr.Code())
+	})
+}
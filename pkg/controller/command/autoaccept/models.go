@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+)
+
+// RulesArgs model
+//
+// This is used for setting and getting the auto-accept rules.
+//
+type RulesArgs struct {
+	// Rules are evaluated in order, the first matching rule wins
+	Rules []autoaccept.Rule `json:"rules"`
+}
+
+// SaveConsentArgs model
+//
+// This is used for saving the consent to present the given presentation automatically.
+//
+type SaveConsentArgs struct {
+	autoaccept.Consent
+}
+
+// RemoveConsentArgs model
+//
+// This is used for removing the consent.
+//
+type RemoveConsentArgs struct {
+	// ID of the consent
+	ID string `json:"id"`
+}
+
+// ConsentsResponse model
+//
+// Represents a GetConsents response message.
+//
+type ConsentsResponse struct {
+	Consents []autoaccept.Consent `json:"consents"`
+}
@@ -44,6 +44,7 @@ const (
 	ReceiveInvitationCommandMethod        = "ReceiveInvitation"
 	CreateConnectionCommandMethod         = "CreateConnection"
 	RemoveConnectionCommandMethod         = "RemoveConnection"
+	SetConnectionMetadataCommandMethod    = "SetConnectionMetadata"
 
 	// log constants.
 	connectionIDString = "connectionID"
@@ -80,6 +81,9 @@ const (
 	// CreateConnectionErrorCode is for failures in create connection command.
 	CreateConnectionErrorCode
 
+	// SetConnectionMetadataErrorCode is for failures in set connection metadata command.
+	SetConnectionMetadataErrorCode
+
 	_actions = "_actions"
 	_states  = "_states"
 )
@@ -94,7 +98,9 @@ type provider interface {
 }
 
 // New returns new DID Exchange controller command instance.
-func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccept bool) (*Command, error) {
+// The action events are passed through the given filters (e.g auto-accept rules) before being published.
+func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccept bool,
+	filters ...command.ActionFilter) (*Command, error) {
 	didExchange, err := didexchange.New(ctx)
 	if err != nil {
 		return nil, err
@@ -125,7 +131,7 @@ func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccep
 	}
 
 	go func() {
-		for action := range actions {
+		for action := range command.FilterActions(actions, filters...) {
 			for i := range subscribers {
 				action.Message = action.Message.Clone()
 				subscribers[i] <- action
@@ -163,6 +169,7 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, AcceptInvitationCommandMethod, c.AcceptInvitation),
 		cmdutil.NewCommandHandler(CommandName, CreateConnectionCommandMethod, c.CreateConnection),
 		cmdutil.NewCommandHandler(CommandName, RemoveConnectionCommandMethod, c.RemoveConnection),
+		cmdutil.NewCommandHandler(CommandName, SetConnectionMetadataCommandMethod, c.SetConnectionMetadata),
 		cmdutil.NewCommandHandler(CommandName, QueryConnectionByIDCommandMethod, c.QueryConnectionByID),
 		cmdutil.NewCommandHandler(CommandName, QueryConnectionsCommandMethod, c.QueryConnections),
 		cmdutil.NewCommandHandler(CommandName, AcceptExchangeRequestCommandMethod, c.AcceptExchangeRequest),
@@ -493,3 +500,34 @@ func (c *Command) RemoveConnection(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// SetConnectionMetadata replaces the metadata of the given connection record.
+func (c *Command) SetConnectionMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request SetConnectionMetadataArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, SetConnectionMetadataCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ID == "" {
+		logutil.LogDebug(logger, CommandName, SetConnectionMetadataCommandMethod, errEmptyConnID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyConnID))
+	}
+
+	err = c.client.SetConnectionMetadata(request.ID, request.Metadata)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SetConnectionMetadataCommandMethod, err.Error(),
+			logutil.CreateKeyValueString(connectionIDString, request.ID))
+
+		return command.NewExecuteError(SetConnectionMetadataErrorCode, err)
+	}
+
+	logutil.LogDebug(logger, CommandName, SetConnectionMetadataCommandMethod, successString,
+		logutil.CreateKeyValueString(connectionIDString, request.ID))
+
+	return nil
+}
@@ -976,6 +976,60 @@ func TestCommand_RemoveConnection(t *testing.T) {
 	})
 }
 
+func TestCommand_SetConnectionMetadata(t *testing.T) {
+	t.Run("test set connection metadata", func(t *testing.T) {
+		prov := mockProvider()
+		prov.StorageProviderValue = mem.NewProvider()
+
+		recorder, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+
+		connRec := &connection.Record{ConnectionID: "1234", ThreadID: "th1234", State: connection.StateNameCompleted}
+		require.NoError(t, recorder.SaveConnectionRecord(connRec))
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.SetConnectionMetadata(&b, bytes.NewBufferString(`{"id":"1234","metadata":{"label":"alice"}}`))
+		require.NoError(t, cmdErr)
+
+		b.Reset()
+
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(`{"metadata":{"label":"alice"}}`))
+		require.NoError(t, cmdErr)
+
+		var response QueryConnectionsResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Len(t, response.Results, 1)
+		require.Equal(t, connRec.ConnectionID, response.Results[0].ConnectionID)
+		require.Equal(t, map[string]string{"label": "alice"}, response.Results[0].Metadata)
+	})
+
+	t.Run("test set connection metadata errors", func(t *testing.T) {
+		cmd, err := New(mockProvider(), mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.SetConnectionMetadata(&b, bytes.NewBufferString(`{"id":""}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyConnID)
+
+		cmdErr = cmd.SetConnectionMetadata(&b, bytes.NewBufferString(`--`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.SetConnectionMetadata(&b, bytes.NewBufferString(`{"id":"unknown","metadata":{"label":"alice"}}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SetConnectionMetadataErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
 func mockProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{
 		ProtocolStateStorageProviderValue: mem.NewProvider(),
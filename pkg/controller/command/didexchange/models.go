@@ -312,6 +312,18 @@ type ConnectionIDArg struct {
 	ID string `json:"id"`
 }
 
+// SetConnectionMetadataArgs model
+//
+// This is used for replacing the metadata of the connection.
+//
+type SetConnectionMetadataArgs struct {
+	// Connection ID
+	ID string `json:"id"`
+
+	// Metadata of the connection, the metadata can be used to query the connections
+	Metadata map[string]string `json:"metadata"`
+}
+
 // CreateConnectionRequest model
 //
 // This is used for creating connection request.
@@ -64,6 +64,12 @@ const (
 
 	// Outofband error group for outofband command errors.
 	Outofband = 11000
+
+	// VCWallet error group for verifiable credential wallet command errors.
+	VCWallet = 12000
+
+	// AutoAccept error group for auto-accept rules command errors.
+	AutoAccept = 13000
 )
 
 // Error is the  interface for representing an command error condition, with the nil value representing no error.
@@ -50,11 +50,13 @@ const (
 	ActionsErrorCode
 	// AcceptProblemReportErrorCode is for failures in accept problem report command.
 	AcceptProblemReportErrorCode
+	// RecipientStatusesErrorCode failures in recipient statuses command.
+	RecipientStatusesErrorCode
 )
 
 // constants for command introduce.
 const (
-	maxRecipients = 2
+	minRecipients = 2
 
 	CommandName = "introduce"
 
@@ -69,8 +71,9 @@ const (
 	DeclineProposal                      = "DeclineProposal"
 	DeclineRequest                       = "DeclineRequest"
 	AcceptProblemReport                  = "AcceptProblemReport"
+	RecipientStatuses                    = "RecipientStatuses"
 	// error messages.
-	errTwoRecipients          = "two recipients must be specified"
+	errTwoRecipients          = "at least two recipients must be specified"
 	errEmptyInvitation        = "empty invitation"
 	errEmptyRecipient         = "empty recipient"
 	errEmptyMyDID             = "empty my_did"
@@ -91,7 +94,8 @@ type Command struct {
 }
 
 // New returns new introduce controller command instance.
-func New(ctx introduce.Provider, notifier command.Notifier) (*Command, error) {
+// The action events are passed through the given filters (e.g auto-accept rules) before being published.
+func New(ctx introduce.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Command, error) {
 	client, err := introduce.New(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a client: %w", err)
@@ -112,7 +116,7 @@ func New(ctx introduce.Provider, notifier command.Notifier) (*Command, error) {
 	}
 
 	obs := webnotifier.NewObserver(notifier)
-	obs.RegisterAction(protocol.Introduce+_actions, actions)
+	obs.RegisterAction(protocol.Introduce+_actions, command.FilterActions(actions, filters...))
 	obs.RegisterStateMsg(protocol.Introduce+_states, states)
 
 	return &Command{client: client}, nil
@@ -132,6 +136,7 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, DeclineProposal, c.DeclineProposal),
 		cmdutil.NewCommandHandler(CommandName, DeclineRequest, c.DeclineRequest),
 		cmdutil.NewCommandHandler(CommandName, AcceptProblemReport, c.AcceptProblemReport),
+		cmdutil.NewCommandHandler(CommandName, RecipientStatuses, c.RecipientStatuses),
 	}
 }
 
@@ -153,6 +158,7 @@ func (c *Command) Actions(rw io.Writer, _ io.Reader) command.Error {
 }
 
 // SendProposal sends a proposal to the introducees (the client has not published an out-of-band message).
+// The first recipient is introduced to each of the other recipients.
 func (c *Command) SendProposal(rw io.Writer, req io.Reader) command.Error {
 	var args SendProposalArgs
 
@@ -161,12 +167,12 @@ func (c *Command) SendProposal(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	if len(args.Recipients) != maxRecipients {
+	if len(args.Recipients) < minRecipients {
 		logutil.LogDebug(logger, CommandName, SendProposal, errTwoRecipients)
 		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errTwoRecipients))
 	}
 
-	piid, err := c.client.SendProposal(args.Recipients[0], args.Recipients[1])
+	piid, err := c.client.SendProposal(args.Recipients[0], args.Recipients[1], args.Recipients[minRecipients:]...)
 	if err != nil {
 		logutil.LogError(logger, CommandName, SendProposal, err.Error())
 		return command.NewExecuteError(SendProposalErrorCode, err)
@@ -368,7 +374,7 @@ func (c *Command) AcceptRequestWithRecipients(rw io.Writer, req io.Reader) comma
 		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyTo))
 	}
 
-	if err := c.client.AcceptRequestWithRecipients(args.PIID, args.To, args.Recipient); err != nil {
+	if err := c.client.AcceptRequestWithRecipients(args.PIID, args.To, args.Recipient, args.Recipients...); err != nil {
 		logutil.LogError(logger, CommandName, AcceptRequestWithRecipients, err.Error())
 		return command.NewExecuteError(AcceptRequestWithRecipientsErrorCode, err)
 	}
@@ -457,3 +463,32 @@ func (c *Command) AcceptProblemReport(rw io.Writer, req io.Reader) command.Error
 
 	return nil
 }
+
+// RecipientStatuses returns the state of the introduction per recipient of the proposals (introducer only).
+func (c *Command) RecipientStatuses(rw io.Writer, req io.Reader) command.Error {
+	var args RecipientStatusesArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, RecipientStatuses, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, RecipientStatuses, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	result, err := c.client.RecipientStatuses(args.PIID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RecipientStatuses, err.Error())
+		return command.NewExecuteError(RecipientStatusesErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &RecipientStatusesResponse{
+		Recipients: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, RecipientStatuses, successString)
+
+	return nil
+}
@@ -200,6 +200,22 @@ func TestCommand_SendProposal(t *testing.T) {
 		var b bytes.Buffer
 		require.NoError(t, cmd.SendProposal(&b, bytes.NewBufferString(`{"recipients":[{},{}]}`)))
 	})
+	t.Run("Success with three recipients", func(t *testing.T) {
+		service := mocks.NewMockProtocolService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().HandleOutbound(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.SendProposal(&b, bytes.NewBufferString(`{"recipients":[{},{},{}]}`)))
+	})
 }
 
 func TestCommand_SendProposalWithOOBInvitation(t *testing.T) {
@@ -1051,6 +1067,82 @@ func TestCommand_AcceptProblemReport(t *testing.T) {
 	})
 }
 
+func TestCommand_RecipientStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockProtocolService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	t.Run("Decode error", func(t *testing.T) {
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.RecipientStatuses(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty PIID", func(t *testing.T) {
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.RecipientStatuses(&b, bytes.NewBufferString("{}"))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errEmptyPIID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("RecipientStatuses (error)", func(t *testing.T) {
+		service.EXPECT().RecipientStatuses("piid").Return(nil, errors.New("some error message"))
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.RecipientStatuses(&b, bytes.NewBufferString(`{"piid":"piid"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "some error message")
+		require.Equal(t, RecipientStatusesErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().RecipientStatuses("piid").Return([]*protocol.RecipientStatus{
+			{MyDID: "my_did_1", TheirDID: "their_did_1", State: protocol.RecipientStateIntroduced},
+			{MyDID: "my_did_2", TheirDID: "their_did_2", State: protocol.RecipientStateDeclined},
+		}, nil)
+
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.RecipientStatuses(&b, bytes.NewBufferString(`{"piid":"piid"}`)))
+
+		response := RecipientStatusesResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, RecipientStatusesResponse{Recipients: []*introduce.RecipientStatus{
+			{MyDID: "my_did_1", TheirDID: "their_did_1", State: protocol.RecipientStateIntroduced},
+			{MyDID: "my_did_2", TheirDID: "their_did_2", State: protocol.RecipientStateDeclined},
+		}}, response)
+	})
+}
+
 func toProtocolActions(actions []introduce.Action) []protocol.Action {
 	res := make([]protocol.Action, len(actions))
 	for i, action := range actions {
@@ -24,7 +24,8 @@ type ActionsResponse struct {
 // This is used for sending a proposal.
 //
 type SendProposalArgs struct {
-	// Recipients specifies to whom proposal will be sent
+	// Recipients specifies to whom proposal will be sent (at least two).
+	// The first recipient is introduced to each of the other recipients.
 	Recipients []*introduce.Recipient `json:"recipients"`
 }
 
@@ -124,6 +125,8 @@ type AcceptRequestWithRecipientsArgs struct {
 	PIID string `json:"piid"`
 	// Recipient specifies to whom proposal will be sent
 	Recipient *introduce.Recipient `json:"recipient"`
+	// Recipients specifies additional recipients the requester will be introduced to
+	Recipients []*introduce.Recipient `json:"recipients,omitempty"`
 	// To keeps information about the introduction
 	To *introduce.To `json:"to"`
 }
@@ -197,3 +200,21 @@ type AcceptProblemReportArgs struct {
 // Represents a AcceptProblemReport response message.
 //
 type AcceptProblemReportResponse struct{}
+
+// RecipientStatusesArgs model
+//
+// This is used for getting the state of the introduction per recipient.
+//
+type RecipientStatusesArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
+
+// RecipientStatusesResponse model
+//
+// Represents a RecipientStatuses response message.
+//
+type RecipientStatusesResponse struct {
+	// Recipients of the proposals ordered as the proposals were sent
+	Recipients []*introduce.RecipientStatus `json:"recipients"`
+}
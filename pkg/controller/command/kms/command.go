@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
@@ -31,6 +32,14 @@ const (
 	CreateKeySetError
 	// ImportKeyError is for failures while importing key.
 	ImportKeyError
+	// ListKeysError is for failures while listing keys.
+	ListKeysError
+	// DeleteKeyError is for failures while deleting key.
+	DeleteKeyError
+	// SetKeyMetadataError is for failures while setting key metadata.
+	SetKeyMetadataError
+	// GetKeyMetadataError is for failures while getting key metadata.
+	GetKeyMetadataError
 )
 
 // constants for KMS commands.
@@ -39,8 +48,12 @@ const (
 	CommandName = "kms"
 
 	// command methods.
-	CreateKeySetCommandMethod = "CreateKeySet"
-	ImportKeyCommandMethod    = "ImportKey"
+	CreateKeySetCommandMethod   = "CreateKeySet"
+	ImportKeyCommandMethod      = "ImportKey"
+	ListKeysCommandMethod       = "ListKeys"
+	DeleteKeyCommandMethod      = "DeleteKey"
+	SetKeyMetadataCommandMethod = "SetKeyMetadata"
+	GetKeyMetadataCommandMethod = "GetKeyMetadata"
 
 	// error messages.
 	errEmptyKeyType = "key type is mandatory"
@@ -75,6 +88,10 @@ func (o *Command) GetHandlers() []command.Handler {
 	return []command.Handler{
 		cmdutil.NewCommandHandler(CommandName, CreateKeySetCommandMethod, o.CreateKeySet),
 		cmdutil.NewCommandHandler(CommandName, ImportKeyCommandMethod, o.ImportKey),
+		cmdutil.NewCommandHandler(CommandName, ListKeysCommandMethod, o.ListKeys),
+		cmdutil.NewCommandHandler(CommandName, DeleteKeyCommandMethod, o.DeleteKey),
+		cmdutil.NewCommandHandler(CommandName, SetKeyMetadataCommandMethod, o.SetKeyMetadata),
+		cmdutil.NewCommandHandler(CommandName, GetKeyMetadataCommandMethod, o.GetKeyMetadata),
 	}
 }
 
@@ -154,3 +171,144 @@ func (o *Command) ImportKey(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// ListKeys lists the metadata of the keys matching the request filter.
+func (o *Command) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	var request ListKeysRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, ListKeysCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	filter := &kms.KeyFilter{
+		KeyType: kms.KeyType(request.KeyType),
+		Label:   request.Label,
+		Purpose: request.Purpose,
+		DID:     request.DID,
+	}
+
+	if request.CreatedBefore != "" {
+		createdBefore, errParse := time.Parse(time.RFC3339, request.CreatedBefore)
+		if errParse != nil {
+			logutil.LogInfo(logger, CommandName, ListKeysCommandMethod, errParse.Error())
+			return command.NewValidationError(InvalidRequestErrorCode,
+				fmt.Errorf("invalid createdBefore : %w", errParse))
+		}
+
+		filter.CreatedBefore = &createdBefore
+	}
+
+	keys, err := o.ctx.KMS().List(filter)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ListKeysCommandMethod, err.Error())
+		return command.NewExecuteError(ListKeysError, err)
+	}
+
+	if keys == nil {
+		keys = []*kms.KeyMetadata{}
+	}
+
+	command.WriteNillableResponse(rw, &ListKeysResponse{Keys: keys}, logger)
+
+	logutil.LogDebug(logger, CommandName, ListKeysCommandMethod, "success")
+
+	return nil
+}
+
+// DeleteKey deletes a key.
+func (o *Command) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	var request DeleteKeyRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, DeleteKeyCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, CommandName, DeleteKeyCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	err = o.ctx.KMS().Delete(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeleteKeyCommandMethod, err.Error())
+		return command.NewExecuteError(DeleteKeyError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, DeleteKeyCommandMethod, "success")
+
+	return nil
+}
+
+// SetKeyMetadata sets the label, purpose, owning DID and usage constraint of a key.
+func (o *Command) SetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request SetKeyMetadataRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, SetKeyMetadataCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, CommandName, SetKeyMetadataCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	switch kms.KeyUsage(request.Usage) {
+	case kms.KeyUsageAny, kms.KeyUsageSign, kms.KeyUsageKeyAgreement, kms.KeyUsageEncrypt:
+	default:
+		return command.NewValidationError(InvalidRequestErrorCode,
+			fmt.Errorf("key usage not supported %s", request.Usage))
+	}
+
+	err = o.ctx.KMS().SetMetadata(request.KeyID, &kms.KeyMetadata{
+		Label:   request.Label,
+		Purpose: request.Purpose,
+		DID:     request.DID,
+		Usage:   kms.KeyUsage(request.Usage),
+	})
+	if err != nil {
+		logutil.LogError(logger, CommandName, SetKeyMetadataCommandMethod, err.Error())
+		return command.NewExecuteError(SetKeyMetadataError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, SetKeyMetadataCommandMethod, "success")
+
+	return nil
+}
+
+// GetKeyMetadata returns the metadata of a key.
+func (o *Command) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request GetKeyMetadataRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, GetKeyMetadataCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, CommandName, GetKeyMetadataCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	md, err := o.ctx.KMS().GetMetadata(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetKeyMetadataCommandMethod, err.Error())
+		return command.NewExecuteError(GetKeyMetadataError, err)
+	}
+
+	command.WriteNillableResponse(rw, &GetKeyMetadataResponse{Metadata: md}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetKeyMetadataCommandMethod, "success")
+
+	return nil
+}
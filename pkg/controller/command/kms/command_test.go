@@ -34,7 +34,7 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, cmd)
 
 		handlers := cmd.GetHandlers()
-		require.Equal(t, 2, len(handlers))
+		require.Equal(t, 6, len(handlers))
 	})
 
 	t.Run("test new command - error from import key", func(t *testing.T) {
@@ -257,3 +257,206 @@ func TestImportKey(t *testing.T) {
 		require.Contains(t, err.Error(), "failed request decode")
 	})
 }
+
+func TestListKeys(t *testing.T) {
+	t.Run("test list keys - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListValue: []*kms.KeyMetadata{
+				{KeyID: "k1", KeyType: kms.ED25519Type, Label: "signing"},
+			}},
+		})
+		require.NotNil(t, cmd)
+
+		req := ListKeysRequest{Label: "signing", CreatedBefore: "2021-01-01T00:00:00Z"}
+		reqBytes, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.ListKeys(&getRW, bytes.NewBuffer(reqBytes))
+		require.NoError(t, cmdErr)
+
+		response := ListKeysResponse{}
+		err = json.NewDecoder(&getRW).Decode(&response)
+		require.NoError(t, err)
+
+		require.Len(t, response.Keys, 1)
+		require.Equal(t, "k1", response.Keys[0].KeyID)
+		require.Equal(t, "signing", response.Keys[0].Label)
+	})
+
+	t.Run("test list keys - no keys", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.ListKeys(&getRW, bytes.NewBufferString("{}"))
+		require.NoError(t, cmdErr)
+		require.JSONEq(t, `{"keys":[]}`, getRW.String())
+	})
+
+	t.Run("test list keys - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.ListKeys(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.ListKeys(&b, bytes.NewBufferString(`{"createdBefore":"yesterday"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "invalid createdBefore")
+	})
+
+	t.Run("test list keys - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListErr: fmt.Errorf("error list keys")},
+		})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.ListKeys(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, ListKeysError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error list keys")
+	})
+}
+
+func TestDeleteKey(t *testing.T) {
+	t.Run("test delete key - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"k1"}`))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("test delete key - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.DeleteKey(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyKeyID)
+	})
+
+	t.Run("test delete key - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("error delete key")},
+		})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"k1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeleteKeyError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error delete key")
+	})
+}
+
+func TestSetKeyMetadata(t *testing.T) {
+	t.Run("test set key metadata - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		req := SetKeyMetadataRequest{KeyID: "k1", Label: "signing", Usage: string(kms.KeyUsageSign)}
+		reqBytes, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.SetKeyMetadata(&b, bytes.NewBuffer(reqBytes))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("test set key metadata - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.SetKeyMetadata(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.SetKeyMetadata(&b, bytes.NewBufferString(`{"label":"signing"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyKeyID)
+
+		cmdErr = cmd.SetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"k1","usage":"decrypt"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "key usage not supported decrypt")
+	})
+
+	t.Run("test set key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{SetMetadataErr: fmt.Errorf("error set metadata")},
+		})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.SetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"k1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SetKeyMetadataError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error set metadata")
+	})
+}
+
+func TestGetKeyMetadata(t *testing.T) {
+	t.Run("test get key metadata - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{GetMetadataValue: &kms.KeyMetadata{
+				KeyID: "k1", KeyType: kms.ED25519Type, Usage: kms.KeyUsageSign,
+			}},
+		})
+		require.NotNil(t, cmd)
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&getRW, bytes.NewBufferString(`{"keyID":"k1"}`))
+		require.NoError(t, cmdErr)
+
+		response := GetKeyMetadataResponse{}
+		err := json.NewDecoder(&getRW).Decode(&response)
+		require.NoError(t, err)
+
+		require.Equal(t, "k1", response.Metadata.KeyID)
+		require.Equal(t, kms.ED25519Type, response.Metadata.KeyType)
+		require.Equal(t, kms.KeyUsageSign, response.Metadata.Usage)
+	})
+
+	t.Run("test get key metadata - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.GetKeyMetadata(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyKeyID)
+	})
+
+	t.Run("test get key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{GetMetadataErr: fmt.Errorf("error get metadata")},
+		})
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"k1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, GetKeyMetadataError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error get metadata")
+	})
+}
@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package kms
 
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
 // CreateKeySetRequest is model for createKeySey request.
 type CreateKeySetRequest struct {
 	KeyType string `json:"keyType,omitempty"`
@@ -30,3 +34,43 @@ type JSONWebKey struct {
 	Y   string `json:"y,omitempty"`
 	D   string `json:"d,omitempty"`
 }
+
+// ListKeysRequest is model for listKeys request. Empty fields match any key.
+type ListKeysRequest struct {
+	KeyType string `json:"keyType,omitempty"`
+	Label   string `json:"label,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+	DID     string `json:"did,omitempty"`
+	// CreatedBefore matches the keys created before this time (RFC3339 format)
+	CreatedBefore string `json:"createdBefore,omitempty"`
+}
+
+// ListKeysResponse for returning the metadata of the listed keys.
+type ListKeysResponse struct {
+	Keys []*kms.KeyMetadata `json:"keys"`
+}
+
+// DeleteKeyRequest is model for deleteKey request.
+type DeleteKeyRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// SetKeyMetadataRequest is model for setKeyMetadata request.
+type SetKeyMetadataRequest struct {
+	KeyID   string `json:"keyID,omitempty"`
+	Label   string `json:"label,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+	DID     string `json:"did,omitempty"`
+	// Usage restricts the key to a usage: "sign", "key_agreement" or "encrypt" (empty means no restriction)
+	Usage string `json:"usage,omitempty"`
+}
+
+// GetKeyMetadataRequest is model for getKeyMetadata request.
+type GetKeyMetadataRequest struct {
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// GetKeyMetadataResponse for returning the metadata of a key.
+type GetKeyMetadataResponse struct {
+	Metadata *kms.KeyMetadata `json:"metadata,omitempty"`
+}
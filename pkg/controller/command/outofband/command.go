@@ -37,6 +37,16 @@ const (
 	ActionsErrorCode
 	// ActionContinueErrorCode is for failures in action continue command.
 	ActionContinueErrorCode
+	// InvitationURLErrorCode is for failures in invitation URL command.
+	InvitationURLErrorCode
+	// ResolveInvitationURLErrorCode is for failures in resolve invitation URL command.
+	ResolveInvitationURLErrorCode
+	// InvitationsErrorCode is for failures in invitations command.
+	InvitationsErrorCode
+	// GetInvitationErrorCode is for failures in get invitation command.
+	GetInvitationErrorCode
+	// RevokeInvitationErrorCode is for failures in revoke invitation command.
+	RevokeInvitationErrorCode
 )
 
 // constants for out-of-band.
@@ -49,10 +59,19 @@ const (
 	Actions          = "Actions"
 	ActionContinue   = "ActionContinue"
 
+	InvitationURL        = "InvitationURL"
+	ResolveInvitationURL = "ResolveInvitationURL"
+	Invitations          = "Invitations"
+	GetInvitation        = "GetInvitation"
+	RevokeInvitation     = "RevokeInvitation"
+
 	// error messages.
-	errEmptyRequest = "request was not provided"
-	errEmptyMyLabel = "my_label was not provided"
-	errEmptyPIID    = "piid was not provided"
+	errEmptyRequest      = "request was not provided"
+	errEmptyMyLabel      = "my_label was not provided"
+	errEmptyPIID         = "piid was not provided"
+	errEmptyInvitationID = "invitation_id was not provided"
+	errEmptyBaseURL      = "base_url was not provided"
+	errEmptyURL          = "url was not provided"
 	// log constants.
 	successString = "success"
 
@@ -68,7 +87,8 @@ type Command struct {
 }
 
 // New returns new outofband controller command instance.
-func New(ctx outofband.Provider, notifier command.Notifier) (*Command, error) {
+// The action events are passed through the given filters (e.g auto-accept rules) before being published.
+func New(ctx outofband.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Command, error) {
 	client, err := outofband.New(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a client: %w", err)
@@ -89,7 +109,7 @@ func New(ctx outofband.Provider, notifier command.Notifier) (*Command, error) {
 	}
 
 	obs := webnotifier.NewObserver(notifier)
-	obs.RegisterAction(protocol.Name+_actions, actions)
+	obs.RegisterAction(protocol.Name+_actions, command.FilterActions(actions, filters...))
 	obs.RegisterStateMsg(protocol.Name+_states, states)
 
 	return &Command{client: client}, nil
@@ -103,6 +123,11 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, Actions, c.Actions),
 		cmdutil.NewCommandHandler(CommandName, ActionContinue, c.ActionContinue),
 		cmdutil.NewCommandHandler(CommandName, ActionStop, c.ActionStop),
+		cmdutil.NewCommandHandler(CommandName, InvitationURL, c.InvitationURL),
+		cmdutil.NewCommandHandler(CommandName, ResolveInvitationURL, c.ResolveInvitationURL),
+		cmdutil.NewCommandHandler(CommandName, Invitations, c.Invitations),
+		cmdutil.NewCommandHandler(CommandName, GetInvitation, c.GetInvitation),
+		cmdutil.NewCommandHandler(CommandName, RevokeInvitation, c.RevokeInvitation),
 	}
 }
 
@@ -116,13 +141,24 @@ func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	invitation, err := c.client.CreateInvitation(
-		args.Service,
+	options := []outofband.MessageOption{
 		outofband.WithGoal(args.Goal, args.GoalCode),
 		outofband.WithLabel(args.Label),
 		outofband.WithHandshakeProtocols(args.Protocols...),
 		outofband.WithRouterConnections(args.RouterConnectionID),
-	)
+		outofband.WithMaxUses(args.MaxUses),
+		outofband.WithAttachments(args.Attachments...),
+	}
+
+	if args.NoHandshake {
+		options = append(options, outofband.WithoutHandshake())
+	}
+
+	if args.ExpiresAt != nil {
+		options = append(options, outofband.WithExpiry(*args.ExpiresAt))
+	}
+
+	invitation, err := c.client.CreateInvitation(args.Service, options...)
 	if err != nil {
 		logutil.LogError(logger, CommandName, CreateInvitation, err.Error())
 		return command.NewExecuteError(CreateInvitationErrorCode, err)
@@ -249,3 +285,168 @@ func (c *Command) ActionStop(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// InvitationURL returns the URL, the shortened URL and the QR code payload of the invitation created by the agent.
+func (c *Command) InvitationURL(rw io.Writer, req io.Reader) command.Error {
+	var args InvitationURLArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, InvitationURL, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.InvitationID == "" {
+		logutil.LogDebug(logger, CommandName, InvitationURL, errEmptyInvitationID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyInvitationID))
+	}
+
+	if args.BaseURL == "" {
+		logutil.LogDebug(logger, CommandName, InvitationURL, errEmptyBaseURL)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyBaseURL))
+	}
+
+	response, err := c.invitationURL(args.InvitationID, args.BaseURL)
+	if err != nil {
+		logutil.LogError(logger, CommandName, InvitationURL, err.Error(),
+			logutil.CreateKeyValueString("invitationID", args.InvitationID))
+		return command.NewExecuteError(InvitationURLErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, response, logger)
+
+	logutil.LogDebug(logger, CommandName, InvitationURL, successString,
+		logutil.CreateKeyValueString("invitationID", args.InvitationID))
+
+	return nil
+}
+
+func (c *Command) invitationURL(invitationID, baseURL string) (*InvitationURLResponse, error) {
+	record, err := c.client.GetInvitationRecord(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := (*outofband.Invitation)(record.Invitation)
+
+	invURL, err := c.client.InvitationURL(baseURL, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	shortURL, err := c.client.ShortInvitationURL(baseURL, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	qrPayload, err := c.client.InvitationQRPayload(baseURL, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvitationURLResponse{URL: invURL, ShortURL: shortURL, QRPayload: qrPayload}, nil
+}
+
+// ResolveInvitationURL returns the invitation of the invitation URL or the shortened invitation URL.
+func (c *Command) ResolveInvitationURL(rw io.Writer, req io.Reader) command.Error {
+	var args ResolveInvitationURLArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, ResolveInvitationURL, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.URL == "" {
+		logutil.LogDebug(logger, CommandName, ResolveInvitationURL, errEmptyURL)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyURL))
+	}
+
+	invitation, err := c.client.ResolveInvitationURL(args.URL)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ResolveInvitationURL, err.Error())
+		return command.NewExecuteError(ResolveInvitationURLErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ResolveInvitationURLResponse{
+		Invitation: invitation,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, ResolveInvitationURL, successString)
+
+	return nil
+}
+
+// Invitations returns the records of the invitations created by the agent.
+func (c *Command) Invitations(rw io.Writer, _ io.Reader) command.Error {
+	records, err := c.client.InvitationRecords()
+	if err != nil {
+		logutil.LogError(logger, CommandName, Invitations, err.Error())
+		return command.NewExecuteError(InvitationsErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &InvitationsResponse{
+		Invitations: records,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, Invitations, successString)
+
+	return nil
+}
+
+// GetInvitation returns the record of the invitation created by the agent.
+func (c *Command) GetInvitation(rw io.Writer, req io.Reader) command.Error {
+	var args InvitationIDArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, GetInvitation, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.InvitationID == "" {
+		logutil.LogDebug(logger, CommandName, GetInvitation, errEmptyInvitationID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyInvitationID))
+	}
+
+	record, err := c.client.GetInvitationRecord(args.InvitationID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetInvitation, err.Error(),
+			logutil.CreateKeyValueString("invitationID", args.InvitationID))
+		return command.NewExecuteError(GetInvitationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &GetInvitationResponse{
+		InvitationRecord: record,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetInvitation, successString,
+		logutil.CreateKeyValueString("invitationID", args.InvitationID))
+
+	return nil
+}
+
+// RevokeInvitation revokes the invitation created by the agent, the connection requests referencing it are rejected.
+func (c *Command) RevokeInvitation(rw io.Writer, req io.Reader) command.Error {
+	var args InvitationIDArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, RevokeInvitation, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.InvitationID == "" {
+		logutil.LogDebug(logger, CommandName, RevokeInvitation, errEmptyInvitationID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyInvitationID))
+	}
+
+	if err := c.client.RevokeInvitation(args.InvitationID); err != nil {
+		logutil.LogError(logger, CommandName, RevokeInvitation, err.Error(),
+			logutil.CreateKeyValueString("invitationID", args.InvitationID))
+		return command.NewExecuteError(RevokeInvitationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &RevokeInvitationResponse{}, logger)
+
+	logutil.LogDebug(logger, CommandName, RevokeInvitation, successString,
+		logutil.CreateKeyValueString("invitationID", args.InvitationID))
+
+	return nil
+}
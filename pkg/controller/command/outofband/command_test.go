@@ -17,6 +17,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
@@ -160,6 +161,39 @@ func TestCommand_CreateInvitation(t *testing.T) {
 		require.Equal(t, expected.Service, res.Invitation.Services)
 		require.Equal(t, expected.Protocols, res.Invitation.Protocols)
 	})
+
+	t.Run("Success without handshake", func(t *testing.T) {
+		service := mocks.NewMockOobService(ctrl)
+		service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
+		service.EXPECT().SaveInvitation(gomock.Any()).Return(nil)
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+		cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		var b bytes.Buffer
+
+		expected := CreateInvitationArgs{
+			Service: []interface{}{"did:example:123"},
+			Attachments: []*decorator.Attachment{{
+				ID:   "attachment",
+				Data: decorator.AttachmentData{JSON: map[string]interface{}{"@id": "123"}},
+			}},
+			NoHandshake: true,
+		}
+		args, err := json.Marshal(expected)
+		require.NoError(t, err)
+		require.NoError(t, cmd.CreateInvitation(&b, bytes.NewBuffer(args)))
+		res := CreateInvitationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+
+		require.Empty(t, res.Invitation.Protocols)
+		require.Len(t, res.Invitation.Requests, 1)
+		require.Equal(t, "attachment", res.Invitation.Requests[0].ID)
+	})
 }
 
 func TestCommand_AcceptInvitation(t *testing.T) {
@@ -464,6 +498,299 @@ func TestCommand_ActionContinue(t *testing.T) {
 	})
 }
 
+func TestCommand_InvitationURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.InvitationURL(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty invitation ID", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.InvitationURL(&b, bytes.NewBufferString(`{"base_url":"https://example.com"}`))
+
+		require.EqualError(t, cmdErr, errEmptyInvitationID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty base URL", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.InvitationURL(&b, bytes.NewBufferString(`{"invitation_id":"id"}`))
+
+		require.EqualError(t, cmdErr, errEmptyBaseURL)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Get invitation record (error)", func(t *testing.T) {
+		service.EXPECT().GetInvitationRecord("id").Return(nil, errors.New("error message"))
+
+		var b bytes.Buffer
+		cmdErr := cmd.InvitationURL(&b, bytes.NewBufferString(`{"invitation_id":"id","base_url":"https://example.com"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "error message")
+		require.Equal(t, InvitationURLErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		inv := &protocol.Invitation{ID: "id", Type: protocol.InvitationMsgType, Label: label}
+		service.EXPECT().GetInvitationRecord("id").Return(&protocol.InvitationRecord{Invitation: inv}, nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.InvitationURL(&b,
+			bytes.NewBufferString(`{"invitation_id":"id","base_url":"https://example.com"}`)))
+
+		res := InvitationURLResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, "https://example.com/ssi?id=id", res.ShortURL)
+		require.Equal(t, res.URL, res.QRPayload)
+
+		decoded, err := protocol.DecodeInvitationURL(res.URL)
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded)
+	})
+}
+
+func TestCommand_ResolveInvitationURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.ResolveInvitationURL(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty URL", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.ResolveInvitationURL(&b, bytes.NewBufferString(`{}`))
+
+		require.EqualError(t, cmdErr, errEmptyURL)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Resolve (error)", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.ResolveInvitationURL(&b, bytes.NewBufferString(`{"url":"https://example.com?oob=%%%"}`))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, ResolveInvitationURLErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		inv := &protocol.Invitation{ID: "id", Type: protocol.InvitationMsgType, Label: label}
+
+		invURL, err := protocol.EncodeInvitationURL("https://example.com", inv)
+		require.NoError(t, err)
+
+		args, err := json.Marshal(ResolveInvitationURLArgs{URL: invURL})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.ResolveInvitationURL(&b, bytes.NewBuffer(args)))
+
+		res := ResolveInvitationURLResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, inv.ID, res.Invitation.ID)
+		require.Equal(t, inv.Label, res.Invitation.Label)
+	})
+}
+
+func TestCommand_Invitations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Success", func(t *testing.T) {
+		records := []*protocol.InvitationRecord{{
+			Invitation: &protocol.Invitation{ID: "id"},
+			State:      protocol.InvitationStateActive,
+		}}
+		service.EXPECT().InvitationRecords().Return(records, nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.Invitations(&b, nil))
+
+		res := InvitationsResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Len(t, res.Invitations, 1)
+		require.Equal(t, "id", res.Invitations[0].Invitation.ID)
+		require.Equal(t, protocol.InvitationStateActive, res.Invitations[0].State)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		service.EXPECT().InvitationRecords().Return(nil, errors.New("error message"))
+
+		var b bytes.Buffer
+		cmdErr := cmd.Invitations(&b, nil)
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "error message")
+		require.Equal(t, InvitationsErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_GetInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.GetInvitation(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty invitation ID", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.GetInvitation(&b, bytes.NewBufferString(`{}`))
+
+		require.EqualError(t, cmdErr, errEmptyInvitationID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		service.EXPECT().GetInvitationRecord("id").Return(nil, errors.New("error message"))
+
+		var b bytes.Buffer
+		cmdErr := cmd.GetInvitation(&b, bytes.NewBufferString(`{"invitation_id":"id"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "error message")
+		require.Equal(t, GetInvitationErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().GetInvitationRecord("id").Return(&protocol.InvitationRecord{
+			Invitation: &protocol.Invitation{ID: "id"},
+			State:      protocol.InvitationStateRevoked,
+		}, nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.GetInvitation(&b, bytes.NewBufferString(`{"invitation_id":"id"}`)))
+
+		res := GetInvitationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+		require.Equal(t, "id", res.InvitationRecord.Invitation.ID)
+		require.Equal(t, protocol.InvitationStateRevoked, res.InvitationRecord.State)
+	})
+}
+
+func TestCommand_RevokeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.RevokeInvitation(&b, bytes.NewBufferString("}"))
+
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Empty invitation ID", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.RevokeInvitation(&b, bytes.NewBufferString(`{}`))
+
+		require.EqualError(t, cmdErr, errEmptyInvitationID)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		service.EXPECT().RevokeInvitation("id").Return(errors.New("error message"))
+
+		var b bytes.Buffer
+		cmdErr := cmd.RevokeInvitation(&b, bytes.NewBufferString(`{"invitation_id":"id"}`))
+
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "error message")
+		require.Equal(t, RevokeInvitationErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		service.EXPECT().RevokeInvitation("id").Return(nil)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.RevokeInvitation(&b, bytes.NewBufferString(`{"invitation_id":"id"}`)))
+	})
+}
+
 func TestCommand_GetHandlers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -476,7 +803,7 @@ func TestCommand_GetHandlers(t *testing.T) {
 	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
 	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
 	require.NoError(t, err)
-	require.Equal(t, 5, len(cmd.GetHandlers()))
+	require.Equal(t, 10, len(cmd.GetHandlers()))
 }
 
 func toProtocolActions(actions []outofband.Action) []protocol.Action {
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package outofband
 
 import (
+	"time"
+
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 )
@@ -24,6 +26,13 @@ type CreateInvitationArgs struct {
 	RouterConnectionID string        `json:"router_connection_id"`
 	// Attachments is intended to provide the possibility to include files, links or even JSON payload to the message.
 	Attachments []*decorator.Attachment `json:"attachments"`
+	// MaxUses is the number of connections the invitation can be used for, zero means unlimited.
+	MaxUses int `json:"max_uses,omitempty"`
+	// ExpiresAt is the time the invitation expires at, the invitation never expires if not set.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// NoHandshake creates an invitation without handshake protocols, the attachments are exchanged
+	// without a connection.
+	NoHandshake bool `json:"no_handshake,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -95,3 +104,73 @@ type ActionContinueArgs struct {
 // Represents a ActionContinue response message.
 //
 type ActionContinueResponse struct{}
+
+// InvitationURLArgs model
+//
+// This is used for getting the URL of an invitation created by the agent.
+//
+type InvitationURLArgs struct {
+	InvitationID string `json:"invitation_id"`
+	// BaseURL is the URL the invitation is appended to, the agent must serve the shortened invitations at this URL.
+	BaseURL string `json:"base_url"`
+}
+
+// InvitationURLResponse model
+//
+// Represents an InvitationURL response message.
+//
+type InvitationURLResponse struct {
+	// URL is the invitation URL, e.g https://example.com?oob=eyJAdHlwZSI6...
+	URL string `json:"url"`
+	// ShortURL is the shortened invitation URL, e.g https://example.com/ssi?id=1234
+	ShortURL string `json:"short_url"`
+	// QRPayload is the payload of the QR code, either URL or ShortURL depending on the size.
+	QRPayload string `json:"qr_payload"`
+}
+
+// ResolveInvitationURLArgs model
+//
+// This is used for resolving an invitation URL.
+//
+type ResolveInvitationURLArgs struct {
+	// URL is the invitation URL or the shortened invitation URL.
+	URL string `json:"url"`
+}
+
+// ResolveInvitationURLResponse model
+//
+// Represents a ResolveInvitationURL response message.
+//
+type ResolveInvitationURLResponse struct {
+	Invitation *outofband.Invitation `json:"invitation"`
+}
+
+// InvitationsResponse model
+//
+// Represents an Invitations response message.
+//
+type InvitationsResponse struct {
+	Invitations []*outofband.InvitationRecord `json:"invitations"`
+}
+
+// InvitationIDArgs model
+//
+// This is used for operations on an invitation created by the agent.
+//
+type InvitationIDArgs struct {
+	InvitationID string `json:"invitation_id"`
+}
+
+// GetInvitationResponse model
+//
+// Represents a GetInvitation response message.
+//
+type GetInvitationResponse struct {
+	InvitationRecord *outofband.InvitationRecord `json:"invitation_record"`
+}
+
+// RevokeInvitationResponse model
+//
+// Represents a RevokeInvitation response message.
+//
+type RevokeInvitationResponse struct{}
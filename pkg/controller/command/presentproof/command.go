@@ -92,7 +92,8 @@ type Command struct {
 }
 
 // New returns new present proof controller command instance.
-func New(ctx presentproof.Provider, notifier command.Notifier) (*Command, error) {
+// The action events are passed through the given filters (e.g auto-accept rules) before being published.
+func New(ctx presentproof.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Command, error) {
 	client, err := presentproof.New(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a client: %w", err)
@@ -113,7 +114,7 @@ func New(ctx presentproof.Provider, notifier command.Notifier) (*Command, error)
 	}
 
 	obs := webnotifier.NewObserver(notifier)
-	obs.RegisterAction(protocol.Name+_actions, actions)
+	obs.RegisterAction(protocol.Name+_actions, command.FilterActions(actions, filters...))
 	obs.RegisterStateMsg(protocol.Name+_states, states)
 
 	return &Command{client: client}, nil
@@ -0,0 +1,690 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/wallet"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/command/vcwallet")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.VCWallet)
+
+	// CreateProfileErrorCode for errors while creating wallet profile.
+	CreateProfileErrorCode
+
+	// UpdateProfileErrorCode for errors while updating wallet profile.
+	UpdateProfileErrorCode
+
+	// OpenWalletErrorCode for errors while opening wallet.
+	OpenWalletErrorCode
+
+	// AddToWalletErrorCode for errors while adding contents to wallet.
+	AddToWalletErrorCode
+
+	// RemoveFromWalletErrorCode for errors while removing contents from wallet.
+	RemoveFromWalletErrorCode
+
+	// GetFromWalletErrorCode for errors while retrieving contents from wallet.
+	GetFromWalletErrorCode
+
+	// GetAllFromWalletErrorCode for errors while retrieving all contents from wallet.
+	GetAllFromWalletErrorCode
+
+	// QueryWalletErrorCode for errors while querying credentials from wallet.
+	QueryWalletErrorCode
+
+	// IssueFromWalletErrorCode for errors while issuing a credential from wallet.
+	IssueFromWalletErrorCode
+
+	// ProveFromWalletErrorCode for errors while producing a presentation from wallet.
+	ProveFromWalletErrorCode
+
+	// VerifyFromWalletErrorCode for errors while verifying a presentation or credential from wallet.
+	VerifyFromWalletErrorCode
+
+	// DeriveFromWalletErrorCode for errors while deriving a credential from wallet.
+	DeriveFromWalletErrorCode
+
+	// CloseWalletErrorCode for errors while closing wallet.
+	CloseWalletErrorCode
+)
+
+// All command operations.
+const (
+	CommandName = "vcwallet"
+
+	// command methods.
+	CreateProfileMethod = "CreateProfile"
+	UpdateProfileMethod = "UpdateProfile"
+	OpenMethod          = "Open"
+	CloseMethod         = "Close"
+	AddMethod           = "Add"
+	RemoveMethod        = "Remove"
+	GetMethod           = "Get"
+	GetAllMethod        = "GetAll"
+	QueryMethod         = "Query"
+	IssueMethod         = "Issue"
+	ProveMethod         = "Prove"
+	VerifyMethod        = "Verify"
+	DeriveMethod        = "Derive"
+
+	// error messages.
+	errEmptyUserID      = "userID is mandatory"
+	errEmptyContentType = "contentType is mandatory"
+	errEmptyContentID   = "contentID is mandatory"
+
+	// log constants.
+	logUserIDKey = "userID"
+)
+
+// provider contains dependencies for the verifiable credential wallet command
+// and is typically created by using aries.Context().
+type provider interface {
+	StorageProvider() storage.Provider
+	VDRegistry() vdrapi.Registry
+	Crypto() crypto.Crypto
+	Service(id string) (interface{}, error)
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// Command contains operations provided by verifiable credential wallet controller.
+type Command struct {
+	ctx      provider
+	notifier command.Notifier
+}
+
+// New returns new verifiable credential wallet controller command instance.
+// Wallet lock events of wallets opened by this command will be published to given notifier, if provided.
+func New(p provider, notifier command.Notifier) *Command {
+	return &Command{ctx: p, notifier: notifier}
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (o *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, CreateProfileMethod, o.CreateProfile),
+		cmdutil.NewCommandHandler(CommandName, UpdateProfileMethod, o.UpdateProfile),
+		cmdutil.NewCommandHandler(CommandName, OpenMethod, o.Open),
+		cmdutil.NewCommandHandler(CommandName, CloseMethod, o.Close),
+		cmdutil.NewCommandHandler(CommandName, AddMethod, o.Add),
+		cmdutil.NewCommandHandler(CommandName, RemoveMethod, o.Remove),
+		cmdutil.NewCommandHandler(CommandName, GetMethod, o.Get),
+		cmdutil.NewCommandHandler(CommandName, GetAllMethod, o.GetAll),
+		cmdutil.NewCommandHandler(CommandName, QueryMethod, o.Query),
+		cmdutil.NewCommandHandler(CommandName, IssueMethod, o.Issue),
+		cmdutil.NewCommandHandler(CommandName, ProveMethod, o.Prove),
+		cmdutil.NewCommandHandler(CommandName, VerifyMethod, o.Verify),
+		cmdutil.NewCommandHandler(CommandName, DeriveMethod, o.Derive),
+	}
+}
+
+// CreateProfile creates new wallet profile for given user.
+func (o *Command) CreateProfile(rw io.Writer, req io.Reader) command.Error {
+	return o.createOrUpdateProfile(rw, req, false)
+}
+
+// UpdateProfile updates an existing wallet profile for given user.
+func (o *Command) UpdateProfile(rw io.Writer, req io.Reader) command.Error {
+	return o.createOrUpdateProfile(rw, req, true)
+}
+
+func (o *Command) createOrUpdateProfile(rw io.Writer, req io.Reader, update bool) command.Error {
+	method, code, action := CreateProfileMethod, CreateProfileErrorCode, wallet.CreateProfile
+	if update {
+		method, code, action = UpdateProfileMethod, UpdateProfileErrorCode, wallet.UpdateProfile
+	}
+
+	request := &CreateOrUpdateProfileRequest{}
+
+	if err := decodeRequest(req, request, method); err != nil {
+		return err
+	}
+
+	if request.UserID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyUserID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyUserID))
+	}
+
+	var opts []wallet.ProfileKeyManagerOptions
+
+	if request.LocalKMSPassphrase != "" {
+		opts = append(opts, wallet.WithPassphrase(request.LocalKMSPassphrase))
+	}
+
+	if request.KeyStoreURL != "" {
+		opts = append(opts, wallet.WithKeyServerURL(request.KeyStoreURL))
+	}
+
+	err := action(request.UserID, o.ctx, opts...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error())
+
+		return command.NewExecuteError(code, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, method, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Open unlocks given user's wallet and returns a token for subsequent use of wallet features.
+func (o *Command) Open(rw io.Writer, req io.Reader) command.Error {
+	request := &UnlockWalletRequest{}
+
+	if err := decodeRequest(req, request, OpenMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, OpenMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	options := []wallet.UnlockOptions{
+		wallet.WithUnlockByPassphrase(request.LocalKMSPassphrase),
+		wallet.WithUnlockByAuthorizationToken(request.WebKMSAuth),
+		wallet.WithUnlockExpiry(request.Expiry),
+	}
+
+	if o.notifier != nil {
+		options = append(options, wallet.WithUnlockNotifier(o.notifier))
+	}
+
+	token, err := vcWallet.Open(options...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, OpenMethod, err.Error())
+
+		return command.NewExecuteError(OpenWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &UnlockWalletResponse{Token: token}, logger)
+
+	logutil.LogDebug(logger, CommandName, OpenMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Close locks given user's wallet.
+func (o *Command) Close(rw io.Writer, req io.Reader) command.Error {
+	request := &LockWalletRequest{}
+
+	if err := decodeRequest(req, request, CloseMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, CloseMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	// closing wallet ends all sessions of the user.
+	if cmdErr = authorize(vcWallet, request.Auth, wallet.AdminScope, CloseWalletErrorCode, CloseMethod); cmdErr != nil {
+		return cmdErr
+	}
+
+	command.WriteNillableResponse(rw, &LockWalletResponse{Closed: vcWallet.Close()}, logger)
+
+	logutil.LogDebug(logger, CommandName, CloseMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Add adds given data model to wallet content store.
+//
+// Supported data models:
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Credential
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#DIDResolutionResponse
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
+func (o *Command) Add(rw io.Writer, req io.Reader) command.Error {
+	request := &AddContentRequest{}
+
+	if err := decodeRequest(req, request, AddMethod); err != nil {
+		return err
+	}
+
+	if request.ContentType == "" {
+		logutil.LogDebug(logger, CommandName, AddMethod, errEmptyContentType)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyContentType))
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, AddMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	if cmdErr = authorize(vcWallet, request.Auth, wallet.SignScope, AddToWalletErrorCode, AddMethod); cmdErr != nil {
+		return cmdErr
+	}
+
+	var opts []wallet.AddContentOptions
+
+	if request.CollectionID != "" {
+		opts = append(opts, wallet.AddByCollection(request.CollectionID))
+	}
+
+	err := vcWallet.Add(request.Auth, request.ContentType, request.Content, opts...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, AddMethod, err.Error())
+
+		return command.NewExecuteError(AddToWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, AddMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Remove deletes given content from wallet content store.
+func (o *Command) Remove(rw io.Writer, req io.Reader) command.Error {
+	request := &RemoveContentRequest{}
+
+	if err := decodeRequest(req, request, RemoveMethod); err != nil {
+		return err
+	}
+
+	if err := validateContentRequest(request.ContentType, request.ContentID, RemoveMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, RemoveMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	cmdErr = authorize(vcWallet, request.Auth, wallet.SignScope, RemoveFromWalletErrorCode, RemoveMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	err := vcWallet.Remove(request.ContentType, request.ContentID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RemoveMethod, err.Error())
+
+		return command.NewExecuteError(RemoveFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, RemoveMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Get returns wallet content by ID from wallet content store.
+func (o *Command) Get(rw io.Writer, req io.Reader) command.Error {
+	request := &GetContentRequest{}
+
+	if err := decodeRequest(req, request, GetMethod); err != nil {
+		return err
+	}
+
+	if err := validateContentRequest(request.ContentType, request.ContentID, GetMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, GetMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	if cmdErr = authorize(vcWallet, request.Auth, wallet.ReadScope, GetFromWalletErrorCode, GetMethod); cmdErr != nil {
+		return cmdErr
+	}
+
+	content, err := vcWallet.Get(request.ContentType, request.ContentID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetMethod, err.Error())
+
+		return command.NewExecuteError(GetFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &GetContentResponse{Content: content}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// GetAll gets all wallet content from wallet content store for given type,
+// optionally limited to contents of given collection.
+func (o *Command) GetAll(rw io.Writer, req io.Reader) command.Error {
+	request := &GetAllContentRequest{}
+
+	if err := decodeRequest(req, request, GetAllMethod); err != nil {
+		return err
+	}
+
+	if request.ContentType == "" {
+		logutil.LogDebug(logger, CommandName, GetAllMethod, errEmptyContentType)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyContentType))
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, GetAllMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	cmdErr = authorize(vcWallet, request.Auth, wallet.ReadScope, GetAllFromWalletErrorCode, GetAllMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	var opts []wallet.GetAllContentsOptions
+
+	if request.CollectionID != "" {
+		opts = append(opts, wallet.WithCollection(request.CollectionID))
+	}
+
+	contents, err := vcWallet.GetAll(request.ContentType, opts...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetAllMethod, err.Error())
+
+		return command.NewExecuteError(GetAllFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &GetAllContentResponse{Contents: contents}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetAllMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Query runs credential queries against wallet credential contents and
+// returns presentation containing credential results.
+//
+// https://w3c-ccg.github.io/universal-wallet-interop-spec/#query
+//
+// Supported Query Types:
+// 	- https://www.w3.org/TR/json-ld11-framing
+// 	- https://identity.foundation/presentation-exchange
+// 	- https://w3c-ccg.github.io/vp-request-spec/#query-by-example
+func (o *Command) Query(rw io.Writer, req io.Reader) command.Error {
+	request := &ContentQueryRequest{}
+
+	if err := decodeRequest(req, request, QueryMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, QueryMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	if cmdErr = authorize(vcWallet, request.Auth, wallet.ReadScope, QueryWalletErrorCode, QueryMethod); cmdErr != nil {
+		return cmdErr
+	}
+
+	presentations, err := vcWallet.Query(request.Query...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, QueryMethod, err.Error())
+
+		return command.NewExecuteError(QueryWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ContentQueryResponse{Results: presentations}, logger)
+
+	logutil.LogDebug(logger, CommandName, QueryMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Issue adds proof to a Verifiable Credential using keys of the wallet.
+func (o *Command) Issue(rw io.Writer, req io.Reader) command.Error {
+	request := &IssueRequest{}
+
+	if err := decodeRequest(req, request, IssueMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, IssueMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	credential, err := vcWallet.Issue(request.Auth, request.Credential, proofOptions(request.ProofOptions))
+	if err != nil {
+		logutil.LogError(logger, CommandName, IssueMethod, err.Error())
+
+		return command.NewExecuteError(IssueFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &IssueResponse{Credential: credential}, logger)
+
+	logutil.LogDebug(logger, CommandName, IssueMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Prove produces a Verifiable Presentation using keys of the wallet.
+func (o *Command) Prove(rw io.Writer, req io.Reader) command.Error {
+	request := &ProveRequest{}
+
+	if err := decodeRequest(req, request, ProveMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, ProveMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	opts := []wallet.ProveOptions{
+		wallet.WithStoredCredentialsToPresent(request.StoredCredentials...),
+		wallet.WithRawCredentialsToPresent(request.RawCredentials...),
+	}
+
+	if len(request.Presentation) > 0 {
+		vp, err := verifiable.ParsePresentation(request.Presentation, verifiable.WithPresDisabledProofCheck())
+		if err != nil {
+			logutil.LogInfo(logger, CommandName, ProveMethod, err.Error())
+
+			return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed to parse presentation : %w", err))
+		}
+
+		opts = append(opts, wallet.WithPresentation(vp))
+	}
+
+	presentation, err := vcWallet.Prove(request.Auth, proofOptions(request.ProofOptions), opts...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ProveMethod, err.Error())
+
+		return command.NewExecuteError(ProveFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ProveResponse{Presentation: presentation}, logger)
+
+	logutil.LogDebug(logger, CommandName, ProveMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Verify takes a Verifiable Credential or Verifiable Presentation as input and verifies it.
+func (o *Command) Verify(rw io.Writer, req io.Reader) command.Error {
+	request := &VerifyRequest{}
+
+	if err := decodeRequest(req, request, VerifyMethod); err != nil {
+		return err
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, VerifyMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	var opt wallet.VerificationOption
+
+	switch {
+	case request.StoredCredentialID != "":
+		opt = wallet.WithStoredCredentialToVerify(request.StoredCredentialID)
+	case len(request.RawCredential) > 0:
+		opt = wallet.WithRawCredentialToVerify(request.RawCredential)
+	case len(request.Presentation) > 0:
+		opt = wallet.WithRawPresentationToVerify(request.Presentation)
+	default:
+		logutil.LogDebug(logger, CommandName, VerifyMethod, "invalid option")
+
+		return command.NewValidationError(InvalidRequestErrorCode,
+			errors.New("invalid option, provide any one of storedCredentialID, rawCredential or presentation"))
+	}
+
+	response := &VerifyResponse{}
+
+	verified, err := vcWallet.Verify(opt)
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	response.Verified = verified
+
+	command.WriteNillableResponse(rw, response, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// Derive derives a credential by selective disclosure and returns the derived credential.
+func (o *Command) Derive(rw io.Writer, req io.Reader) command.Error {
+	request := &DeriveRequest{}
+
+	if err := decodeRequest(req, request, DeriveMethod); err != nil {
+		return err
+	}
+
+	var credential wallet.CredentialToDerive
+
+	switch {
+	case request.StoredCredentialID != "":
+		credential = wallet.FromStoredCredential(request.StoredCredentialID)
+	case len(request.RawCredential) > 0:
+		credential = wallet.FromRawCredential(request.RawCredential)
+	default:
+		logutil.LogDebug(logger, CommandName, DeriveMethod, "invalid option")
+
+		return command.NewValidationError(InvalidRequestErrorCode,
+			errors.New("invalid option, provide any one of storedCredentialID or rawCredential"))
+	}
+
+	vcWallet, cmdErr := o.newWallet(request.UserID, DeriveMethod)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	deriveOpts := request.DeriveOptions
+	if deriveOpts == nil {
+		deriveOpts = &wallet.DeriveOptions{}
+	}
+
+	derived, err := vcWallet.Derive(credential, deriveOpts)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeriveMethod, err.Error())
+
+		return command.NewExecuteError(DeriveFromWalletErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &DeriveResponse{Credential: derived}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeriveMethod, "success", logutil.CreateKeyValueString(logUserIDKey, request.UserID))
+
+	return nil
+}
+
+// newWallet returns wallet instance of given user.
+func (o *Command) newWallet(userID, method string) (*wallet.Wallet, command.Error) {
+	if userID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyUserID)
+
+		return nil, command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyUserID))
+	}
+
+	vcWallet, err := wallet.New(userID, o.ctx)
+	if err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error())
+
+		return nil, command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	return vcWallet, nil
+}
+
+// authorize checks if given token is a valid unlock token of the wallet with given scope, contents of the wallet are
+// not accessible without a valid token.
+func authorize(vcWallet *wallet.Wallet, auth string, scope wallet.TokenScope, code command.Code,
+	method string) command.Error {
+	if err := vcWallet.Authorize(auth, scope); err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error())
+
+		return command.NewExecuteError(code, err)
+	}
+
+	return nil
+}
+
+func decodeRequest(req io.Reader, request interface{}, method string) command.Error {
+	err := json.NewDecoder(req).Decode(request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	return nil
+}
+
+func validateContentRequest(contentType wallet.ContentType, contentID, method string) command.Error {
+	if contentType == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyContentType)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyContentType))
+	}
+
+	if contentID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyContentID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyContentID))
+	}
+
+	return nil
+}
+
+func proofOptions(opts *wallet.ProofOptions) *wallet.ProofOptions {
+	if opts == nil {
+		return &wallet.ProofOptions{}
+	}
+
+	return opts
+}
@@ -0,0 +1,560 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/wallet"
+)
+
+const (
+	sampleUserID     = "sample-user01"
+	samplePassPhrase = "fakepassphrase"
+	sampleCollection = `{
+		"@context": ["https://w3id.org/wallet/v1"],
+		"id": "did:example:acme123456789abcdefghi",
+		"type": "Organization",
+		"name": "Acme Corp.",
+		"description" : "A software company."
+	}`
+	sampleMetadata = `{
+		"@context": ["https://w3id.org/wallet/v1"],
+		"id": "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		"type": "Metadata",
+		"name": "Ropsten Testnet HD Accounts"
+	}`
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new command - success", func(t *testing.T) {
+		cmd := New(newMockProvider(), nil)
+		require.NotNil(t, cmd)
+
+		handlers := cmd.GetHandlers()
+		require.Equal(t, 13, len(handlers))
+	})
+}
+
+func TestCommand_CreateAndUpdateProfile(t *testing.T) {
+	t.Run("create and update profile - success", func(t *testing.T) {
+		cmd := New(newMockProvider(), nil)
+
+		var b bytes.Buffer
+		cmdErr := cmd.CreateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		}))
+		require.NoError(t, cmdErr)
+
+		b.Reset()
+		cmdErr = cmd.UpdateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{
+			UserID:      sampleUserID,
+			KeyStoreURL: "sample/keyserver/test",
+		}))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("create profile - failures", func(t *testing.T) {
+		cmd := New(newMockProvider(), nil)
+
+		var b bytes.Buffer
+		cmdErr := cmd.CreateProfile(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.CreateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+
+		cmdErr = cmd.CreateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{UserID: sampleUserID}))
+		validateError(t, cmdErr, command.ExecuteError, CreateProfileErrorCode, "invalid create profile options")
+
+		cmdErr = cmd.UpdateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		}))
+		validateError(t, cmdErr, command.ExecuteError, UpdateProfileErrorCode, "")
+	})
+}
+
+func TestCommand_OpenAndClose(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleUserProfile(t, mockctx)
+
+	cmd := New(mockctx, nil)
+
+	t.Run("open and close wallet - success", func(t *testing.T) {
+		token := getUnlockToken(t, cmd)
+		require.NotEmpty(t, token)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID, Auth: token}))
+		require.NoError(t, cmdErr)
+
+		var response LockWalletResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.True(t, response.Closed)
+
+		b.Reset()
+		cmdErr = cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID, Auth: token}))
+		validateError(t, cmdErr, command.ExecuteError, CloseWalletErrorCode, "wallet locked")
+		require.Empty(t, b.Bytes())
+	})
+
+	t.Run("close wallet without valid token - failure", func(t *testing.T) {
+		token := getUnlockToken(t, cmd)
+		defer closeWallet(t, cmd, token)
+
+		var b bytes.Buffer
+		cmdErr := cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID}))
+		validateError(t, cmdErr, command.ExecuteError, CloseWalletErrorCode, "wallet locked")
+
+		cmdErr = cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID, Auth: "invalid"}))
+		validateError(t, cmdErr, command.ExecuteError, CloseWalletErrorCode, "wallet locked")
+
+		vcWallet, err := wallet.New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		readToken, err := vcWallet.CreateSession(token, wallet.ReadScope, 0)
+		require.NoError(t, err)
+
+		cmdErr = cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID, Auth: readToken}))
+		validateError(t, cmdErr, command.ExecuteError, CloseWalletErrorCode, "scope is required")
+		require.Empty(t, b.Bytes())
+
+		// wallet remains open.
+		require.NoError(t, vcWallet.Authorize(token, wallet.AdminScope))
+	})
+
+	t.Run("open and close wallet - failures", func(t *testing.T) {
+		var b bytes.Buffer
+		cmdErr := cmd.Open(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Open(&b, getReader(t, &UnlockWalletRequest{}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+
+		cmdErr = cmd.Open(&b, getReader(t, &UnlockWalletRequest{UserID: "invalid-user"}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "profile does not exist")
+
+		cmdErr = cmd.Open(&b, getReader(t, &UnlockWalletRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: "invalid",
+		}))
+		validateError(t, cmdErr, command.ExecuteError, OpenWalletErrorCode, "")
+
+		cmdErr = cmd.Close(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Close(&b, getReader(t, &LockWalletRequest{}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+	})
+}
+
+func TestCommand_AddGetRemove(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleUserProfile(t, mockctx)
+
+	cmd := New(mockctx, nil)
+
+	t.Run("add, get, get all and remove contents - success", func(t *testing.T) {
+		token := getUnlockToken(t, cmd)
+		defer closeWallet(t, cmd, token)
+
+		auth := WalletAuth{UserID: sampleUserID, Auth: token}
+
+		var b bytes.Buffer
+		cmdErr := cmd.Add(&b, getReader(t, &AddContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Collection,
+			Content:     []byte(sampleCollection),
+		}))
+		require.NoError(t, cmdErr)
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{
+			WalletAuth:   auth,
+			ContentType:  wallet.Metadata,
+			Content:      []byte(sampleMetadata),
+			CollectionID: "did:example:acme123456789abcdefghi",
+		}))
+		require.NoError(t, cmdErr)
+
+		b.Reset()
+		cmdErr = cmd.Get(&b, getReader(t, &GetContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		}))
+		require.NoError(t, cmdErr)
+
+		var getResponse GetContentResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&getResponse))
+		require.NotEmpty(t, getResponse.Content)
+
+		b.Reset()
+		cmdErr = cmd.GetAll(&b, getReader(t, &GetAllContentRequest{
+			WalletAuth:   auth,
+			ContentType:  wallet.Metadata,
+			CollectionID: "did:example:acme123456789abcdefghi",
+		}))
+		require.NoError(t, cmdErr)
+
+		var getAllResponse GetAllContentResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&getAllResponse))
+		require.Len(t, getAllResponse.Contents, 1)
+
+		b.Reset()
+		cmdErr = cmd.Remove(&b, getReader(t, &RemoveContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		}))
+		require.NoError(t, cmdErr)
+
+		b.Reset()
+		cmdErr = cmd.GetAll(&b, getReader(t, &GetAllContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+		}))
+		require.NoError(t, cmdErr)
+
+		var getAllAfterRemove GetAllContentResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&getAllAfterRemove))
+		require.Empty(t, getAllAfterRemove.Contents)
+	})
+
+	t.Run("add, get, get all and remove contents - failures", func(t *testing.T) {
+		token := getUnlockToken(t, cmd)
+		defer closeWallet(t, cmd, token)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Add(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{WalletAuth: WalletAuth{UserID: sampleUserID}}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyContentType)
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{ContentType: wallet.Metadata}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{
+			WalletAuth:  WalletAuth{UserID: sampleUserID, Auth: token},
+			ContentType: "invalid",
+			Content:     []byte(sampleMetadata),
+		}))
+		validateError(t, cmdErr, command.ExecuteError, AddToWalletErrorCode, "")
+
+		cmdErr = cmd.Get(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Get(&b, getReader(t, &GetContentRequest{ContentType: wallet.Metadata}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyContentID)
+
+		cmdErr = cmd.Get(&b, getReader(t, &GetContentRequest{
+			WalletAuth:  WalletAuth{UserID: sampleUserID, Auth: token},
+			ContentType: wallet.Metadata,
+			ContentID:   "invalid",
+		}))
+		validateError(t, cmdErr, command.ExecuteError, GetFromWalletErrorCode, "data not found")
+
+		cmdErr = cmd.GetAll(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.GetAll(&b, getReader(t, &GetAllContentRequest{}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyContentType)
+
+		cmdErr = cmd.GetAll(&b, getReader(t, &GetAllContentRequest{ContentType: wallet.Metadata}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+
+		cmdErr = cmd.Remove(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Remove(&b, getReader(t, &RemoveContentRequest{}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyContentType)
+
+		cmdErr = cmd.Remove(&b, getReader(t, &RemoveContentRequest{ContentType: wallet.Metadata, ContentID: "id"}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, errEmptyUserID)
+	})
+}
+
+func TestCommand_ContentsAuthorization(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleUserProfile(t, mockctx)
+
+	cmd := New(mockctx, nil)
+
+	token := getUnlockToken(t, cmd)
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.Add(&b, getReader(t, &AddContentRequest{
+		WalletAuth:  WalletAuth{UserID: sampleUserID, Auth: token},
+		ContentType: wallet.Metadata,
+		Content:     []byte(sampleMetadata),
+	})))
+
+	closeWallet(t, cmd, token)
+
+	validateUnauthorized := func(t *testing.T, auth WalletAuth) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Get(&b, getReader(t, &GetContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		}))
+		validateError(t, cmdErr, command.ExecuteError, GetFromWalletErrorCode, "wallet locked")
+
+		cmdErr = cmd.GetAll(&b, getReader(t, &GetAllContentRequest{WalletAuth: auth, ContentType: wallet.Metadata}))
+		validateError(t, cmdErr, command.ExecuteError, GetAllFromWalletErrorCode, "wallet locked")
+
+		cmdErr = cmd.Query(&b, getReader(t, &ContentQueryRequest{WalletAuth: auth}))
+		validateError(t, cmdErr, command.ExecuteError, QueryWalletErrorCode, "wallet locked")
+
+		cmdErr = cmd.Remove(&b, getReader(t, &RemoveContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		}))
+		validateError(t, cmdErr, command.ExecuteError, RemoveFromWalletErrorCode, "wallet locked")
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			Content:     []byte(sampleMetadata),
+		}))
+		validateError(t, cmdErr, command.ExecuteError, AddToWalletErrorCode, "wallet locked")
+
+		require.Empty(t, b.Bytes())
+	}
+
+	t.Run("access contents without token - failure", func(t *testing.T) {
+		validateUnauthorized(t, WalletAuth{UserID: sampleUserID})
+	})
+
+	t.Run("access contents with invalid token - failure", func(t *testing.T) {
+		validateUnauthorized(t, WalletAuth{UserID: sampleUserID, Auth: "invalid"})
+	})
+
+	t.Run("access contents with closed wallet token - failure", func(t *testing.T) {
+		validateUnauthorized(t, WalletAuth{UserID: sampleUserID, Auth: token})
+	})
+
+	t.Run("access contents with expired token - failure", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := cmd.Open(&b, getReader(t, &UnlockWalletRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+			Expiry:             50 * time.Millisecond,
+		}))
+		require.NoError(t, cmdErr)
+
+		var response UnlockWalletResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+
+		time.Sleep(100 * time.Millisecond)
+
+		validateUnauthorized(t, WalletAuth{UserID: sampleUserID, Auth: response.Token})
+	})
+
+	t.Run("access contents with token of other wallet - failure", func(t *testing.T) {
+		const otherUserID = "other-user"
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.CreateProfile(&b, getReader(t, &CreateOrUpdateProfileRequest{
+			UserID:             otherUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		})))
+
+		b.Reset()
+
+		require.NoError(t, cmd.Open(&b, getReader(t, &UnlockWalletRequest{
+			UserID:             otherUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		})))
+
+		var response UnlockWalletResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.NotEmpty(t, response.Token)
+
+		defer func() {
+			require.NoError(t, cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: otherUserID, Auth: response.Token})))
+		}()
+
+		validateUnauthorized(t, WalletAuth{UserID: sampleUserID, Auth: response.Token})
+	})
+
+	t.Run("add and remove contents with read scope token - failure", func(t *testing.T) {
+		token := getUnlockToken(t, cmd)
+		defer closeWallet(t, cmd, token)
+
+		vcWallet, err := wallet.New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		readToken, err := vcWallet.CreateSession(token, wallet.ReadScope, 0)
+		require.NoError(t, err)
+
+		auth := WalletAuth{UserID: sampleUserID, Auth: readToken}
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Remove(&b, getReader(t, &RemoveContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		}))
+		validateError(t, cmdErr, command.ExecuteError, RemoveFromWalletErrorCode, "scope is required")
+
+		cmdErr = cmd.Add(&b, getReader(t, &AddContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Collection,
+			Content:     []byte(sampleCollection),
+		}))
+		validateError(t, cmdErr, command.ExecuteError, AddToWalletErrorCode, "scope is required")
+
+		require.Empty(t, b.Bytes())
+
+		// content is still readable, but not removed.
+		require.NoError(t, cmd.Get(&b, getReader(t, &GetContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		})))
+	})
+}
+
+func TestCommand_CredentialOperations(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleUserProfile(t, mockctx)
+
+	cmd := New(mockctx, nil)
+
+	t.Run("query, issue, prove, verify and derive - failures", func(t *testing.T) {
+		auth := WalletAuth{UserID: sampleUserID, Auth: "invalid"}
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Query(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Query(&b, getReader(t, &ContentQueryRequest{WalletAuth: auth}))
+		validateError(t, cmdErr, command.ExecuteError, QueryWalletErrorCode, "")
+
+		cmdErr = cmd.Issue(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Issue(&b, getReader(t, &IssueRequest{WalletAuth: auth, Credential: []byte("{}")}))
+		validateError(t, cmdErr, command.ExecuteError, IssueFromWalletErrorCode, "")
+
+		cmdErr = cmd.Prove(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Prove(&b, getReader(t, &ProveRequest{WalletAuth: auth, Presentation: []byte(`"--"`)}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "failed to parse presentation")
+
+		cmdErr = cmd.Prove(&b, getReader(t, &ProveRequest{WalletAuth: auth, StoredCredentials: []string{"invalid"}}))
+		validateError(t, cmdErr, command.ExecuteError, ProveFromWalletErrorCode, "")
+
+		cmdErr = cmd.Verify(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Verify(&b, getReader(t, &VerifyRequest{WalletAuth: auth}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "invalid option")
+
+		cmdErr = cmd.Derive(&b, bytes.NewBufferString("--"))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "request decode")
+
+		cmdErr = cmd.Derive(&b, getReader(t, &DeriveRequest{WalletAuth: auth}))
+		validateError(t, cmdErr, command.ValidationError, InvalidRequestErrorCode, "invalid option")
+
+		cmdErr = cmd.Derive(&b, getReader(t, &DeriveRequest{WalletAuth: auth, StoredCredentialID: "invalid"}))
+		validateError(t, cmdErr, command.ExecuteError, DeriveFromWalletErrorCode, "")
+	})
+
+	t.Run("verify stored credential - not found", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := cmd.Verify(&b, getReader(t, &VerifyRequest{
+			WalletAuth:         WalletAuth{UserID: sampleUserID},
+			StoredCredentialID: "invalid",
+		}))
+		require.NoError(t, cmdErr)
+
+		var response VerifyResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.False(t, response.Verified)
+		require.Contains(t, response.Error, "failed to get credential")
+	})
+}
+
+func newMockProvider() *mockprovider.Provider {
+	return &mockprovider.Provider{StorageProviderValue: mockstorage.NewMockStoreProvider()}
+}
+
+func createSampleUserProfile(t *testing.T, ctx *mockprovider.Provider) {
+	t.Helper()
+
+	require.NoError(t, wallet.CreateProfile(sampleUserID, ctx, wallet.WithPassphrase(samplePassPhrase)))
+}
+
+func getUnlockToken(t *testing.T, cmd *Command) string {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	cmdErr := cmd.Open(&b, getReader(t, &UnlockWalletRequest{
+		UserID:             sampleUserID,
+		LocalKMSPassphrase: samplePassPhrase,
+	}))
+	require.NoError(t, cmdErr)
+
+	var response UnlockWalletResponse
+	require.NoError(t, json.NewDecoder(&b).Decode(&response))
+
+	return response.Token
+}
+
+func closeWallet(t *testing.T, cmd *Command, token string) {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	require.NoError(t, cmd.Close(&b, getReader(t, &LockWalletRequest{UserID: sampleUserID, Auth: token})))
+}
+
+func getReader(t *testing.T, v interface{}) *bytes.Reader {
+	t.Helper()
+
+	vcReqBytes, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return bytes.NewReader(vcReqBytes)
+}
+
+func validateError(t *testing.T, err command.Error, expectedType command.Type, expectedCode command.Code, contains string) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.Equal(t, expectedType, err.Type(), fmt.Sprintf("unexpected error: %v", err))
+	require.Equal(t, expectedCode, err.Code())
+
+	if contains != "" {
+		require.Contains(t, err.Error(), contains)
+	}
+}
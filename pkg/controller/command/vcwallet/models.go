@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/wallet"
+)
+
+// CreateOrUpdateProfileRequest is request model for
+// creating a new wallet profile or updating an existing wallet profile.
+type CreateOrUpdateProfileRequest struct {
+	// Unique identifier to identify wallet user.
+	UserID string `json:"userID"`
+
+	// passphrase for local kms for key operations.
+	// Optional, if this option is provided then wallet for this profile will use local KMS for key operations.
+	LocalKMSPassphrase string `json:"localKMSPassphrase,omitempty"`
+
+	// passphrase for web/remote kms for key operations.
+	// Optional, if this option is provided then wallet for this profile will use web/remote KMS for key operations.
+	KeyStoreURL string `json:"keyStoreURL,omitempty"`
+}
+
+// UnlockWalletRequest contains different options for unlocking wallet.
+type UnlockWalletRequest struct {
+	// user ID of the wallet to be unlocked.
+	UserID string `json:"userID"`
+
+	// passphrase for local kms for key operations.
+	// Optional, to be used if profile for this wallet user is setup with local KMS.
+	LocalKMSPassphrase string `json:"localKMSPassphrase,omitempty"`
+
+	// WebKMSAuth for authorizing access to web/remote kms.
+	// Optional, to be used if profile for this wallet user is setup with web/remote KMS.
+	WebKMSAuth string `json:"webKMSAuth,omitempty"`
+
+	// Expiry time for which wallet token is valid.
+	// Optional, by default wallet token will expire in 10 minutes.
+	Expiry time.Duration `json:"expiry,omitempty"`
+}
+
+// UnlockWalletResponse contains response for wallet unlock operation.
+type UnlockWalletResponse struct {
+	// Token for granting access to wallet for subsequent wallet operations.
+	Token string `json:"token,omitempty"`
+}
+
+// LockWalletRequest contains options for locking wallet.
+type LockWalletRequest struct {
+	// user ID of the wallet to be locked.
+	UserID string `json:"userID"`
+
+	// admin token of the wallet to be locked.
+	Auth string `json:"auth"`
+}
+
+// LockWalletResponse contains response for wallet lock operation.
+type LockWalletResponse struct {
+	// Closed status of the wallet lock operation.
+	// if true, wallet is closed successfully
+	// if false, wallet is already closed or never unlocked.
+	Closed bool `json:"closed"`
+}
+
+// WalletAuth contains wallet auth parameters for performing wallet operations.
+type WalletAuth struct {
+	// Authorization token for performing wallet operations.
+	Auth string `json:"auth"`
+
+	// ID of wallet user.
+	UserID string `json:"userID"`
+}
+
+// AddContentRequest is request for adding a content to wallet.
+type AddContentRequest struct {
+	WalletAuth
+
+	// type of the content to be added to the wallet.
+	// supported types: collection, credential, didResolutionResponse, metadata, connection, key
+	ContentType wallet.ContentType `json:"contentType"`
+
+	// content to be added to wallet content store.
+	Content json.RawMessage `json:"content"`
+
+	// ID of the wallet collection to which this content should belong.
+	// Optional.
+	CollectionID string `json:"collectionID,omitempty"`
+}
+
+// RemoveContentRequest is request for removing a content from wallet.
+type RemoveContentRequest struct {
+	WalletAuth
+
+	// type of the content to be removed from the wallet.
+	// supported types: collection, credential, didResolutionResponse, metadata, connection
+	ContentType wallet.ContentType `json:"contentType"`
+
+	// ID of the content to be removed from wallet
+	ContentID string `json:"contentID"`
+}
+
+// GetContentRequest is request for getting a content from wallet.
+type GetContentRequest struct {
+	WalletAuth
+
+	// type of the content to be returned from wallet.
+	// supported types: collection, credential, didResolutionResponse, metadata, connection
+	ContentType wallet.ContentType `json:"contentType"`
+
+	// ID of the content to be returned from wallet
+	ContentID string `json:"contentID"`
+}
+
+// GetContentResponse response for get content from wallet operation.
+type GetContentResponse struct {
+	// content retrieved from wallet content store.
+	Content json.RawMessage `json:"content"`
+}
+
+// GetAllContentRequest is request for getting all contents from wallet for given content type.
+type GetAllContentRequest struct {
+	WalletAuth
+
+	// type of the contents to be returned from wallet.
+	// supported types: collection, credential, didResolutionResponse, metadata, connection
+	ContentType wallet.ContentType `json:"contentType"`
+
+	// ID of the collection on which the response contents to be filtered.
+	// Optional.
+	CollectionID string `json:"collectionID,omitempty"`
+}
+
+// GetAllContentResponse response for get all content by content type wallet operation.
+type GetAllContentResponse struct {
+	// contents retrieved from wallet content store.
+	// map of content ID to content.
+	Contents map[string]json.RawMessage `json:"contents"`
+}
+
+// ContentQueryRequest is request model for querying wallet contents.
+type ContentQueryRequest struct {
+	WalletAuth
+
+	// credential query(s) for querying wallet contents.
+	Query []*wallet.QueryParams `json:"query"`
+}
+
+// ContentQueryResponse response for wallet content query.
+type ContentQueryResponse struct {
+	// response presentation(s) containing query results.
+	Results []*verifiable.Presentation `json:"results"`
+}
+
+// IssueRequest is request model for issuing credential from wallet.
+type IssueRequest struct {
+	WalletAuth
+
+	// raw credential to be issued from wallet.
+	Credential json.RawMessage `json:"credential"`
+
+	// proof options for issuing credential
+	ProofOptions *wallet.ProofOptions `json:"proofOptions,omitempty"`
+}
+
+// IssueResponse is response model from wallet issue operation.
+type IssueResponse struct {
+	// credential issued.
+	Credential *verifiable.Credential `json:"credential"`
+}
+
+// ProveRequest for producing verifiable presentation from wallet.
+// Contains options for proofs and credential. Any combination of credential option can be mixed.
+type ProveRequest struct {
+	WalletAuth
+
+	// IDs of credentials already saved in wallet content store.
+	StoredCredentials []string `json:"storedCredentials,omitempty"`
+
+	// List of raw credentials to be presented.
+	RawCredentials []json.RawMessage `json:"rawCredentials,omitempty"`
+
+	// Presentation to be proved (optional).
+	Presentation json.RawMessage `json:"presentation,omitempty"`
+
+	// proof options
+	ProofOptions *wallet.ProofOptions `json:"proofOptions,omitempty"`
+}
+
+// ProveResponse contains response presentation from prove operation.
+type ProveResponse struct {
+	// presentation response from prove operation.
+	Presentation *verifiable.Presentation `json:"presentation,omitempty"`
+}
+
+// VerifyRequest request for verifying a credential or presentation from wallet.
+// Any one of the credential option should be used.
+type VerifyRequest struct {
+	WalletAuth
+
+	// ID of the credential already saved in wallet content store.
+	// optional, if provided then this option takes precedence over other options.
+	StoredCredentialID string `json:"storedCredentialID,omitempty"`
+
+	// List of raw credential to be presented.
+	// optional, if provided then this option takes precedence over presentation options.
+	RawCredential json.RawMessage `json:"rawCredential,omitempty"`
+
+	// Presentation to be proved.
+	// optional, will be used only if other options are not provided.
+	Presentation json.RawMessage `json:"presentation,omitempty"`
+}
+
+// VerifyResponse is response model for wallet verify operation.
+type VerifyResponse struct {
+	// if true then verification is successful.
+	Verified bool `json:"verified"`
+
+	// error details if verified is false.
+	Error string `json:"error,omitempty"`
+}
+
+// DeriveRequest is request model for deriving a credential from wallet.
+type DeriveRequest struct {
+	WalletAuth
+
+	// ID of the credential already saved in wallet content store.
+	// optional, if provided then this option takes precedence.
+	StoredCredentialID string `json:"storedCredentialID,omitempty"`
+
+	// List of raw credential to be presented.
+	// optional, will be used only if other options is not provided.
+	RawCredential json.RawMessage `json:"rawCredential,omitempty"`
+
+	// DeriveOptions options for deriving a credential
+	*wallet.DeriveOptions
+}
+
+// DeriveResponse is response for derived credential operation.
+type DeriveResponse struct {
+	// credential derived.
+	Credential *verifiable.Credential `json:"credential"`
+}
@@ -85,6 +85,12 @@ const (
 
 	// DeriveCredentialErrorCode for derive credential error.
 	DeriveCredentialErrorCode
+
+	// VerifyCredentialErrorCode for verify credential error.
+	VerifyCredentialErrorCode
+
+	// VerifyPresentationErrorCode for verify presentation error.
+	VerifyPresentationErrorCode
 )
 
 // constants for the Verifiable protocol.
@@ -106,6 +112,8 @@ const (
 	GeneratePresentationByIDCommandMethod = "GeneratePresentationByID"
 	RemoveCredentialByNameCommandMethod   = "RemoveCredentialByName"
 	RemovePresentationByNameCommandMethod = "RemovePresentationByName"
+	VerifyCredentialCommandMethod         = "VerifyCredential"
+	VerifyPresentationCommandMethod       = "VerifyPresentation"
 
 	// error messages.
 	errEmptyCredentialName   = "credential name is mandatory"
@@ -115,6 +123,7 @@ const (
 	errEmptyDID              = "did is mandatory"
 	errEmptyCredential       = "credential is mandatory is mandatory"
 	errEmptyFrame            = "frame is mandatory is mandatory"
+	errEmptyPresentation     = "presentation is mandatory"
 
 	// log constants.
 	vcID   = "vcID"
@@ -271,6 +280,8 @@ func (o *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, GetPresentationsCommandMethod, o.GetPresentations),
 		cmdutil.NewCommandHandler(CommandName, RemoveCredentialByNameCommandMethod, o.RemoveCredentialByName),
 		cmdutil.NewCommandHandler(CommandName, RemovePresentationByNameCommandMethod, o.RemovePresentationByName),
+		cmdutil.NewCommandHandler(CommandName, VerifyCredentialCommandMethod, o.VerifyCredential),
+		cmdutil.NewCommandHandler(CommandName, VerifyPresentationCommandMethod, o.VerifyPresentation),
 	}
 }
 
@@ -531,6 +542,65 @@ func (o *Command) DeriveCredential(rw io.Writer, req io.Reader) command.Error {
 	return nil
 }
 
+// VerifyCredential verifies the verifiable credential and returns a structured verification report.
+// Failed verification checks are reported in the response, an error is returned only for invalid requests.
+func (o *Command) VerifyCredential(rw io.Writer, req io.Reader) command.Error {
+	request := &Credential{}
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, VerifyCredentialCommandMethod, "request decode : "+err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	if request.VerifiableCredential == "" {
+		logutil.LogDebug(logger, CommandName, VerifyCredentialCommandMethod, errEmptyCredential)
+		return command.NewValidationError(VerifyCredentialErrorCode, fmt.Errorf(errEmptyCredential))
+	}
+
+	report := verifiable.VerifyCredential([]byte(request.VerifiableCredential),
+		verifiable.WithPublicKeyFetcher(o.resolver.PublicKeyFetcher()),
+		verifiable.WithJSONLDDocumentLoader(o.docLoader),
+	)
+
+	command.WriteNillableResponse(rw, &VerificationReportResponse{Report: report}, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyCredentialCommandMethod, "success")
+
+	return nil
+}
+
+// VerifyPresentation verifies the verifiable presentation (including its credentials) and returns
+// a structured verification report.
+// Failed verification checks are reported in the response, an error is returned only for invalid requests.
+func (o *Command) VerifyPresentation(rw io.Writer, req io.Reader) command.Error {
+	request := &Presentation{}
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, CommandName, VerifyPresentationCommandMethod, "request decode : "+err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	if len(request.VerifiablePresentation) == 0 {
+		logutil.LogDebug(logger, CommandName, VerifyPresentationCommandMethod, errEmptyPresentation)
+		return command.NewValidationError(VerifyPresentationErrorCode, fmt.Errorf(errEmptyPresentation))
+	}
+
+	report := verifiable.VerifyPresentation(request.VerifiablePresentation,
+		verifiable.WithPresPublicKeyFetcher(o.resolver.PublicKeyFetcher()),
+		verifiable.WithPresJSONLDDocumentLoader(o.docLoader),
+	)
+
+	command.WriteNillableResponse(rw, &VerificationReportResponse{Report: report}, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyPresentationCommandMethod, "success")
+
+	return nil
+}
+
 // GetPresentation retrieves the verifiable presentation from the store.
 func (o *Command) GetPresentation(rw io.Writer, req io.Reader) command.Error {
 	var request IDArg
@@ -419,7 +419,7 @@ func TestNew(t *testing.T) {
 		require.NoError(t, err)
 
 		handlers := cmd.GetHandlers()
-		require.Equal(t, 14, len(handlers))
+		require.Equal(t, 16, len(handlers))
 	})
 
 	t.Run("test new command - vc store error", func(t *testing.T) {
@@ -2675,3 +2675,98 @@ func (s *bbsSigner) textToLines(txt string) [][]byte {
 
 	return linesBytes
 }
+
+func TestCommand_VerifyCredential(t *testing.T) {
+	cmd, err := New(&mockprovider.Provider{
+		StorageProviderValue: mockstore.NewMockStoreProvider(),
+		VDRegistryValue:      &mockvdr.MockVDRegistry{},
+	})
+	require.NotNil(t, cmd)
+	require.NoError(t, err)
+
+	t.Run("test verify vc - success", func(t *testing.T) {
+		vcReqBytes, err := json.Marshal(Credential{VerifiableCredential: vc})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		err = cmd.VerifyCredential(&b, bytes.NewBuffer(vcReqBytes))
+		require.NoError(t, err)
+
+		var response VerificationReportResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.NotNil(t, response.Report)
+		require.Equal(t, sampleVCID, response.Report.ID)
+		require.NotEmpty(t, response.Report.Checks)
+	})
+
+	t.Run("test verify vc - malformed credential is reported", func(t *testing.T) {
+		vcReqBytes, err := json.Marshal(Credential{VerifiableCredential: "{"})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		err = cmd.VerifyCredential(&b, bytes.NewBuffer(vcReqBytes))
+		require.NoError(t, err)
+
+		var response VerificationReportResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.False(t, response.Report.Verified)
+		require.Equal(t, verifiable.SyntaxCheck, response.Report.Checks[0].Check)
+		require.Equal(t, verifiable.CheckFailed, response.Report.Checks[0].Status)
+	})
+
+	t.Run("test verify vc - invalid request", func(t *testing.T) {
+		var b bytes.Buffer
+
+		err := cmd.VerifyCredential(&b, bytes.NewBufferString("--"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "request decode")
+	})
+
+	t.Run("test verify vc - empty credential", func(t *testing.T) {
+		var b bytes.Buffer
+
+		err := cmd.VerifyCredential(&b, bytes.NewBufferString("{}"))
+		require.Error(t, err)
+		require.Equal(t, VerifyCredentialErrorCode, err.Code())
+	})
+}
+
+func TestCommand_VerifyPresentation(t *testing.T) {
+	cmd, err := New(&mockprovider.Provider{
+		StorageProviderValue: mockstore.NewMockStoreProvider(),
+		VDRegistryValue:      &mockvdr.MockVDRegistry{},
+	})
+	require.NotNil(t, cmd)
+	require.NoError(t, err)
+
+	t.Run("test verify vp - success", func(t *testing.T) {
+		vpReqBytes, err := json.Marshal(Presentation{VerifiablePresentation: []byte(udVerifiablePresentation)})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		err = cmd.VerifyPresentation(&b, bytes.NewBuffer(vpReqBytes))
+		require.NoError(t, err)
+
+		var response VerificationReportResponse
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.NotNil(t, response.Report)
+		require.NotEmpty(t, response.Report.Checks)
+		require.Len(t, response.Report.Credentials, 1)
+	})
+
+	t.Run("test verify vp - invalid request", func(t *testing.T) {
+		var b bytes.Buffer
+
+		err := cmd.VerifyPresentation(&b, bytes.NewBufferString("--"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "request decode")
+	})
+
+	t.Run("test verify vp - empty presentation", func(t *testing.T) {
+		var b bytes.Buffer
+
+		err := cmd.VerifyPresentation(&b, bytes.NewBufferString("{}"))
+		require.Error(t, err)
+		require.Equal(t, VerifyPresentationErrorCode, err.Code())
+	})
+}
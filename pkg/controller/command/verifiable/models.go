@@ -115,6 +115,12 @@ type Presentation struct {
 	VerifiablePresentation json.RawMessage `json:"verifiablePresentation,omitempty"`
 }
 
+// VerificationReportResponse is a response model for verifying a vc or vp.
+type VerificationReportResponse struct {
+	// Report contains the result of each verification check.
+	Report *docverifiable.VerificationReport `json:"report,omitempty"`
+}
+
 // RemoveCredentialByNameResponse is a response model for removing a vc by name
 // from the verifiable store.
 type RemoveCredentialByNameResponse struct{}
@@ -9,7 +9,9 @@ package controller
 import (
 	"fmt"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	autoacceptcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/autoaccept"
 	didexchangecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	introducecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	issuecredentialcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -18,9 +20,11 @@ import (
 	messagingcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
 	outofbandcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	presentproofcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	vcwalletcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
 	vdrcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	autoacceptrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/autoaccept"
 	didexchangerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	introducerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	issuecredentialrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -29,6 +33,7 @@ import (
 	messagingrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
 	outofbandrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	presentproofrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	vcwalletrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vcwallet"
 	vdrrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	verifiablerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
@@ -36,11 +41,13 @@ import (
 )
 
 type allOpts struct {
-	webhookURLs  []string
-	defaultLabel string
-	autoAccept   bool
-	msgHandler   command.MessageHandler
-	notifier     command.Notifier
+	webhookURLs     []string
+	defaultLabel    string
+	autoAccept      bool
+	autoAcceptRules []autoaccept.Rule
+	msgHandler      command.MessageHandler
+	notifier        command.Notifier
+	invBaseURL      string
 }
 
 const wsPath = "/ws"
@@ -76,6 +83,13 @@ func WithAutoAccept(autoAccept bool) Opt {
 	}
 }
 
+// WithAutoAcceptRules is an option allowing for the auto-accept rules to be set, the rules replace the stored ones.
+func WithAutoAcceptRules(rules ...autoaccept.Rule) Opt {
+	return func(opts *allOpts) {
+		opts.autoAcceptRules = rules
+	}
+}
+
 // WithMessageHandler is an option allowing for the message handler to be set.
 func WithMessageHandler(handler command.MessageHandler) Opt {
 	return func(opts *allOpts) {
@@ -83,6 +97,14 @@ func WithMessageHandler(handler command.MessageHandler) Opt {
 	}
 }
 
+// WithInvitationBaseURL is an option allowing for the out-of-band invitations to be served at the shortened
+// invitation URLs, the baseURL is the public URL of the agent.
+func WithInvitationBaseURL(baseURL string) Opt {
+	return func(opts *allOpts) {
+		opts.invBaseURL = baseURL
+	}
+}
+
 // GetRESTHandlers returns all REST handlers provided by controller.
 func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error) { // nolint: funlen,gocyclo
 	restAPIOpts := &allOpts{}
@@ -96,9 +118,15 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 		notifier = webnotifier.New(wsPath, restAPIOpts.webhookURLs)
 	}
 
+	// auto-accept rules the protocol action events are filtered with
+	autoAccept, filters, err := newAutoAccept(ctx, restAPIOpts.autoAcceptRules)
+	if err != nil {
+		return nil, err
+	}
+
 	// DID Exchange REST operation
 	exchangeOp, err := didexchangerest.New(ctx, notifier, restAPIOpts.defaultLabel,
-		restAPIOpts.autoAccept)
+		restAPIOpts.autoAccept, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -128,25 +156,25 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	}
 
 	// issuecredential REST operation
-	issuecredentialOp, err := issuecredentialrest.New(ctx, notifier)
+	issuecredentialOp, err := issuecredentialrest.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create issue-credential rest command : %w", err)
 	}
 
 	// presentproof REST operation
-	presentproofOp, err := presentproofrest.New(ctx, notifier)
+	presentproofOp, err := presentproofrest.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create present-proof rest command : %w", err)
 	}
 
 	// introduce REST operation
-	introduceOp, err := introducerest.New(ctx, notifier)
+	introduceOp, err := introducerest.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create introduce rest command : %w", err)
 	}
 
 	// outofband REST operation
-	outofbandOp, err := outofbandrest.New(ctx, notifier)
+	outofbandOp, err := outofbandrest.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create outofband rest command : %w", err)
 	}
@@ -154,6 +182,9 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	// kms command operation
 	kmscmd := kmsrest.New(ctx)
 
+	// vcwallet command controller
+	wallet := vcwalletrest.New(ctx, notifier)
+
 	// creat handlers from all operations
 	var allHandlers []rest.Handler
 	allHandlers = append(allHandlers, exchangeOp.GetRESTHandlers()...)
@@ -166,6 +197,20 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	allHandlers = append(allHandlers, introduceOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, outofbandOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, kmscmd.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, wallet.GetRESTHandlers()...)
+
+	if autoAccept != nil {
+		allHandlers = append(allHandlers, autoacceptrest.New(autoAccept).GetRESTHandlers()...)
+	}
+
+	if restAPIOpts.invBaseURL != "" {
+		shortInvitation, errHandler := outofbandrest.NewShortInvitationHandler(ctx, restAPIOpts.invBaseURL)
+		if errHandler != nil {
+			return nil, fmt.Errorf("create short invitation handler : %w", errHandler)
+		}
+
+		allHandlers = append(allHandlers, shortInvitation)
+	}
 
 	nhp, ok := notifier.(handlerProvider)
 	if ok {
@@ -192,9 +237,15 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 		notifier = webnotifier.New(wsPath, cmdOpts.webhookURLs)
 	}
 
+	// auto-accept rules the protocol action events are filtered with
+	autoAccept, filters, err := newAutoAccept(ctx, cmdOpts.autoAcceptRules)
+	if err != nil {
+		return nil, err
+	}
+
 	// did exchange command operation
 	didexcmd, err := didexchangecmd.New(ctx, notifier, cmdOpts.defaultLabel,
-		cmdOpts.autoAccept)
+		cmdOpts.autoAccept, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("failed initialized didexchange command: %w", err)
 	}
@@ -224,25 +275,25 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	}
 
 	// issuecredential command operation
-	issuecredential, err := issuecredentialcmd.New(ctx, notifier)
+	issuecredential, err := issuecredentialcmd.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create issue-credential command : %w", err)
 	}
 
 	// presentproof command operation
-	presentproof, err := presentproofcmd.New(ctx, notifier)
+	presentproof, err := presentproofcmd.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create present-proof command : %w", err)
 	}
 
 	// introduce command operation
-	introduce, err := introducecmd.New(ctx, notifier)
+	introduce, err := introducecmd.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create introduce command : %w", err)
 	}
 
 	// outofband command operation
-	outofband, err := outofbandcmd.New(ctx, notifier)
+	outofband, err := outofbandcmd.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("create outofband command : %w", err)
 	}
@@ -250,6 +301,9 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	// kms command operation
 	kmscmd := kms.New(ctx)
 
+	// vcwallet command controller
+	wallet := vcwalletcmd.New(ctx, notifier)
+
 	var allHandlers []command.Handler
 	allHandlers = append(allHandlers, didexcmd.GetHandlers()...)
 	allHandlers = append(allHandlers, vcmd.GetHandlers()...)
@@ -261,6 +315,32 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	allHandlers = append(allHandlers, presentproof.GetHandlers()...)
 	allHandlers = append(allHandlers, introduce.GetHandlers()...)
 	allHandlers = append(allHandlers, outofband.GetHandlers()...)
+	allHandlers = append(allHandlers, wallet.GetHandlers()...)
+
+	if autoAccept != nil {
+		allHandlers = append(allHandlers, autoacceptcmd.New(autoAccept).GetHandlers()...)
+	}
 
 	return allHandlers, nil
 }
+
+// newAutoAccept creates the auto-accept client and sets the given rules (if any).
+// Nothing is created if the context has no storage, the rules cannot be kept in that case.
+func newAutoAccept(ctx *context.Provider, rules []autoaccept.Rule) (*autoaccept.Client, []command.ActionFilter, error) {
+	if ctx.StorageProvider() == nil {
+		return nil, nil, nil
+	}
+
+	client, err := autoaccept.New(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create auto-accept client : %w", err)
+	}
+
+	if rules != nil {
+		if err = client.SetRules(rules); err != nil {
+			return nil, nil, fmt.Errorf("set auto-accept rules : %w", err)
+		}
+	}
+
+	return client, []command.ActionFilter{client}, nil
+}
@@ -12,7 +12,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/mocks/webhook"
+	outofbandrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/defaults"
@@ -67,10 +69,28 @@ func TestGetCommandHandlers_Success(t *testing.T) {
 
 		handlers, err := GetCommandHandlers(ctx, WithMessageHandler(msghandler.NewMockMsgServiceProvider()),
 			WithAutoAccept(true), WithDefaultLabel("sample-label"),
-			WithWebhookURLs("sample-wh-url"), WithNotifier(webhook.NewMockWebhookNotifier()))
+			WithWebhookURLs("sample-wh-url"), WithNotifier(webhook.NewMockWebhookNotifier()),
+			WithAutoAcceptRules(autoaccept.Rule{ID: "1", Protocol: "didexchange"}))
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
 	})
+
+	t.Run("Invalid auto-accept rules", func(t *testing.T) {
+		framework, err := aries.New(defaults.WithInboundHTTPAddr(":"+
+			strconv.Itoa(transportutil.GetRandomPort(3)), "", "", ""))
+		require.NoError(t, err)
+		require.NotNil(t, framework)
+
+		defer func() { require.NoError(t, framework.Close()) }()
+
+		ctx, err := framework.Context()
+		require.NoError(t, err)
+		require.NotNil(t, ctx)
+
+		_, err = GetCommandHandlers(ctx, WithAutoAcceptRules(autoaccept.Rule{ID: "1"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "set auto-accept rules")
+	})
 }
 
 func TestGetRESTHandlers_Success(t *testing.T) {
@@ -104,9 +124,19 @@ func TestGetRESTHandlers_Success(t *testing.T) {
 
 		handlers, err := GetRESTHandlers(ctx, WithMessageHandler(msghandler.NewMockMsgServiceProvider()),
 			WithAutoAccept(true), WithDefaultLabel("sample-label"),
-			WithWebhookURLs("sample-wh-url"))
+			WithWebhookURLs("sample-wh-url"), WithInvitationBaseURL("https://example.com"))
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
+
+		var shortInvitation bool
+
+		for _, h := range handlers {
+			if h.Path() == outofbandrest.ShortInvitation {
+				shortInvitation = true
+			}
+		}
+
+		require.True(t, shortInvitation)
 	})
 }
 
@@ -142,6 +172,18 @@ func TestWithAutoAcceptOption(t *testing.T) {
 	require.Equal(t, true, controllerOpts.autoAccept)
 }
 
+func TestWithAutoAcceptRulesOption(t *testing.T) {
+	controllerOpts := &allOpts{}
+
+	rules := []autoaccept.Rule{{ID: "1", Protocol: "didexchange"}}
+
+	opt := WithAutoAcceptRules(rules...)
+
+	opt(controllerOpts)
+
+	require.Equal(t, rules, controllerOpts.autoAcceptRules)
+}
+
 func TestWithMessageHandler(t *testing.T) {
 	controllerOpts := &allOpts{}
 
@@ -151,3 +193,13 @@ func TestWithMessageHandler(t *testing.T) {
 
 	require.NotNil(t, controllerOpts.msgHandler)
 }
+
+func TestWithInvitationBaseURL(t *testing.T) {
+	controllerOpts := &allOpts{}
+
+	opt := WithInvitationBaseURL("https://example.com")
+
+	opt(controllerOpts)
+
+	require.Equal(t, "https://example.com", controllerOpts.invBaseURL)
+}
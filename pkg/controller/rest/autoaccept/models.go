@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+)
+
+// autoacceptSetRulesRequest model
+//
+// Represents a SetRules request message.
+//
+// swagger:parameters autoacceptSetRules
+type autoacceptSetRulesRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Rules []autoaccept.Rule `json:"rules"`
+	}
+}
+
+// autoacceptRulesResponse model
+//
+// Represents a GetRules response message.
+//
+// swagger:response autoacceptRulesResponse
+type autoacceptRulesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Rules []autoaccept.Rule `json:"rules"`
+	}
+}
+
+// autoacceptSaveConsentRequest model
+//
+// Represents a SaveConsent request message.
+//
+// swagger:parameters autoacceptSaveConsent
+type autoacceptSaveConsentRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{ autoaccept.Consent }
+}
+
+// autoacceptConsentsResponse model
+//
+// Represents a GetConsents response message.
+//
+// swagger:response autoacceptConsentsResponse
+type autoacceptConsentsResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Consents []autoaccept.Consent `json:"consents"`
+	}
+}
+
+// autoacceptRemoveConsentRequest model
+//
+// Represents a RemoveConsent request message.
+//
+// swagger:parameters autoacceptRemoveConsent
+type autoacceptRemoveConsentRequest struct { // nolint: unused,deadcode
+	// The ID of the consent
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
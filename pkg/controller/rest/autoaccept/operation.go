@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	client "github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/autoaccept"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+)
+
+// constants for the auto-accept operations.
+const (
+	OperationID       = "/autoaccept"
+	RulesPath         = OperationID + "/rules"
+	ConsentsPath      = OperationID + "/consents"
+	RemoveConsentPath = ConsentsPath + "/{id}"
+)
+
+// Operation is controller REST service controller for the auto-accept rules.
+type Operation struct {
+	command  *autoaccept.Command
+	handlers []rest.Handler
+}
+
+// New returns new auto-accept rest client protocol instance.
+func New(c *client.Client) *Operation {
+	o := &Operation{command: autoaccept.New(c)}
+	o.registerHandler()
+
+	return o
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service.
+func (c *Operation) GetRESTHandlers() []rest.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (c *Operation) registerHandler() {
+	c.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(RulesPath, http.MethodPost, c.SetRules),
+		cmdutil.NewHTTPHandler(RulesPath, http.MethodGet, c.GetRules),
+		cmdutil.NewHTTPHandler(ConsentsPath, http.MethodPost, c.SaveConsent),
+		cmdutil.NewHTTPHandler(ConsentsPath, http.MethodGet, c.GetConsents),
+		cmdutil.NewHTTPHandler(RemoveConsentPath, http.MethodDelete, c.RemoveConsent),
+	}
+}
+
+// SetRules swagger:route POST /autoaccept/rules autoaccept autoacceptSetRules
+//
+// Replaces the auto-accept rules.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) SetRules(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SetRules, rw, req.Body)
+}
+
+// GetRules swagger:route GET /autoaccept/rules autoaccept autoacceptGetRules
+//
+// Returns the auto-accept rules.
+//
+// Responses:
+//    default: genericError
+//        200: autoacceptRulesResponse
+func (c *Operation) GetRules(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetRules, rw, req.Body)
+}
+
+// SaveConsent swagger:route POST /autoaccept/consents autoaccept autoacceptSaveConsent
+//
+// Saves the consent to present the given presentation automatically.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) SaveConsent(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SaveConsent, rw, req.Body)
+}
+
+// GetConsents swagger:route GET /autoaccept/consents autoaccept autoacceptGetConsents
+//
+// Returns the saved consents.
+//
+// Responses:
+//    default: genericError
+//        200: autoacceptConsentsResponse
+func (c *Operation) GetConsents(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetConsents, rw, req.Body)
+}
+
+// RemoveConsent swagger:route DELETE /autoaccept/consents/{id} autoaccept autoacceptRemoveConsent
+//
+// Removes the consent.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) RemoveConsent(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RemoveConsent, rw, bytes.NewBufferString(fmt.Sprintf(`{"id":%q}`, mux.Vars(req)["id"])))
+}
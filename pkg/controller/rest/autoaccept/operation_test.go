@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package autoaccept
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	client "github.com/hyperledger/aries-framework-go/pkg/client/autoaccept"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/autoaccept"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func newOperation(t *testing.T) *Operation {
+	t.Helper()
+
+	c, err := client.New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NoError(t, err)
+
+	return New(c)
+}
+
+func TestOperation_Rules(t *testing.T) {
+	operation := newOperation(t)
+	require.Len(t, operation.GetRESTHandlers(), 5)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, RulesPath, http.MethodPost),
+		bytes.NewBufferString(`{"rules":[{"id":"1","protocol":"didexchange"}]}`),
+		RulesPath,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	b, code, err := sendRequestToHandler(handlerLookup(t, operation, RulesPath, http.MethodGet), nil, RulesPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	var res autoaccept.RulesArgs
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, []client.Rule{{ID: "1", Protocol: "didexchange"}}, res.Rules)
+}
+
+func TestOperation_Consents(t *testing.T) {
+	operation := newOperation(t)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, ConsentsPath, http.MethodPost),
+		bytes.NewBufferString(`{"id":"1","presentation_definition_id":"def","credentials":[{}],"verification_method":"did:example:holder#key-1"}`),
+		ConsentsPath,
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	b, code, err := sendRequestToHandler(handlerLookup(t, operation, ConsentsPath, http.MethodGet), nil, ConsentsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	var res autoaccept.ConsentsResponse
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Len(t, res.Consents, 1)
+
+	_, code, err = sendRequestToHandler(
+		handlerLookup(t, operation, RemoveConsentPath, http.MethodDelete),
+		nil,
+		strings.Replace(RemoveConsentPath, "{id}", "1", 1),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func handlerLookup(t *testing.T, op *Operation, lookup, method string) rest.Handler {
+	t.Helper()
+
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == lookup && h.Method() == method {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
@@ -155,6 +155,25 @@ type queryConnections struct { // nolint: unused,deadcode
 	didexchangeSvc.QueryConnectionsParams
 }
 
+// setConnectionMetadataRequest model
+//
+// This is used for replacing the metadata of the connection
+//
+// swagger:parameters setConnectionMetadata
+type setConnectionMetadataRequest struct { // nolint: unused,deadcode
+	// The ID of the connection record
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// in: body
+	Body struct {
+		// Metadata of the connection, query the connections by the metadata with `metadata.<key>=<value>`
+		Metadata map[string]string `json:"metadata"`
+	}
+}
+
 // queryConnectionResponse model
 //
 // This is used for returning query connection result for single record search
@@ -12,6 +12,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -35,6 +37,10 @@ const (
 	AcceptExchangeRequest        = OperationID + "/{id}/accept-request"
 	CreateConnection             = OperationID + "/create"
 	RemoveConnection             = OperationID + "/{id}/remove"
+	ConnectionMetadata           = OperationID + "/{id}/metadata"
+
+	// metadataQueryPrefix prefixes the query parameters filtering the connections by the metadata.
+	metadataQueryPrefix = "metadata."
 )
 
 // provider contains dependencies for the Exchange protocol and is typically created by using aries.Context().
@@ -47,8 +53,9 @@ type provider interface {
 }
 
 // New returns new DID Exchange rest client protocol instance.
-func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccept bool) (*Operation, error) {
-	dxcmd, err := didexchange.New(ctx, notifier, defaultLabel, autoAccept)
+func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccept bool,
+	filters ...command.ActionFilter) (*Operation, error) {
+	dxcmd, err := didexchange.New(ctx, notifier, defaultLabel, autoAccept, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize did-exchange command : %w", err)
 	}
@@ -83,6 +90,7 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(AcceptExchangeRequest, http.MethodPost, c.AcceptExchangeRequest),
 		cmdutil.NewHTTPHandler(CreateConnection, http.MethodPost, c.CreateConnection),
 		cmdutil.NewHTTPHandler(RemoveConnection, http.MethodPost, c.RemoveConnection),
+		cmdutil.NewHTTPHandler(ConnectionMetadata, http.MethodPost, c.SetConnectionMetadata),
 	}
 }
 
@@ -177,7 +185,7 @@ func (c *Operation) AcceptExchangeRequest(rw http.ResponseWriter, req *http.Requ
 //    default: genericError
 //        200: queryConnectionsResponse
 func (c *Operation) QueryConnections(rw http.ResponseWriter, req *http.Request) {
-	reqBytes, err := queryValuesAsJSON(req.URL.Query())
+	reqBytes, err := queryConnectionsAsJSON(req.URL.Query())
 	if err != nil {
 		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
 		return
@@ -233,6 +241,76 @@ func (c *Operation) RemoveConnection(rw http.ResponseWriter, req *http.Request)
 	rest.Execute(c.command.RemoveConnection, rw, bytes.NewBufferString(request))
 }
 
+// SetConnectionMetadata swagger:route POST /connections/{id}/metadata did-exchange setConnectionMetadata
+//
+// Replaces the metadata of the connection record.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) SetConnectionMetadata(rw http.ResponseWriter, req *http.Request) {
+	id, found := getIDFromRequest(rw, req)
+	if !found {
+		return
+	}
+
+	var request didexchange.SetConnectionMetadataArgs
+
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
+		return
+	}
+
+	request.ID = id
+
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
+		return
+	}
+
+	rest.Execute(c.command.SetConnectionMetadata, rw, bytes.NewReader(reqBytes))
+}
+
+// queryConnectionsAsJSON converts the query connections query strings to JSON bytes,
+// `metadata.<key>` query strings are collected into the metadata criteria.
+func queryConnectionsAsJSON(vals url.Values) ([]byte, error) {
+	args := make(map[string]interface{})
+	metadata := make(map[string]string)
+
+	for k, v := range vals {
+		if len(v) == 0 {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(k, metadataQueryPrefix):
+			metadata[strings.TrimPrefix(k, metadataQueryPrefix)] = v[0]
+		case k == "offset" || k == "limit":
+			n, err := strconv.Atoi(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", k, err)
+			}
+
+			args[k] = n
+		case k == "descending":
+			b, err := strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", k, err)
+			}
+
+			args[k] = b
+		default:
+			args[k] = v[0]
+		}
+	}
+
+	if len(metadata) > 0 {
+		args["metadata"] = metadata
+	}
+
+	return json.Marshal(args)
+}
+
 // queryValuesAsJSON converts query strings to `map[string]string`
 // and marshals them to JSON bytes.
 func queryValuesAsJSON(vals url.Values) ([]byte, error) {
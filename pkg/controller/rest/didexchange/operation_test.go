@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/google/uuid"
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	didexchangeSvc "github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
@@ -403,6 +405,109 @@ func TestOperation_RemoveConnection(t *testing.T) {
 	})
 }
 
+func TestOperation_SetConnectionMetadata(t *testing.T) {
+	t.Run("test set connection metadata and query by it", func(t *testing.T) {
+		prov := &mockprovider.Provider{
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			StorageProviderValue:              mem.NewProvider(),
+			ServiceMap: map[string]interface{}{
+				didexsvc.DIDExchange:  &mockdidexchange.MockDIDExchangeSvc{},
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		}
+
+		recorder, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+
+		for _, id := range []string{"1", "2"} {
+			require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+				ConnectionID: id,
+				ThreadID:     "th" + id,
+				State:        connection.StateNameCompleted,
+			}))
+		}
+
+		op, err := New(prov, webnotifier.NewHTTPNotifier(nil), "", false)
+		require.NoError(t, err)
+
+		buf, err := getSuccessResponseFromHandler(handlerLookup(t, op, ConnectionMetadata),
+			bytes.NewBufferString(`{"metadata":{"label":"alice"}}`), OperationID+"/2/metadata")
+		require.NoError(t, err)
+		require.Empty(t, buf.Bytes())
+
+		buf, err = getSuccessResponseFromHandler(handlerLookup(t, op, Connections), nil,
+			OperationID+"?metadata.label=alice&sort_by=updated_at&limit=1")
+		require.NoError(t, err)
+
+		response := didexchange.QueryConnectionsResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Len(t, response.Results, 1)
+		require.Equal(t, "2", response.Results[0].ConnectionID)
+		require.Equal(t, map[string]string{"label": "alice"}, response.Results[0].Metadata)
+	})
+
+	t.Run("test set connection metadata invalid request", func(t *testing.T) {
+		handler := getHandler(t, ConnectionMetadata)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`--`), OperationID+"/1234/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, didexchange.InvalidRequestErrorCode, buf.Bytes())
+	})
+
+	t.Run("test set connection metadata unknown connection", func(t *testing.T) {
+		handler := getHandler(t, ConnectionMetadata)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"metadata":{"label":"alice"}}`),
+			OperationID+"/unknown/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyRESTError(t, didexchange.SetConnectionMetadataErrorCode, buf.Bytes())
+	})
+}
+
+func TestQueryConnectionsAsJSON(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		b, err := queryConnectionsAsJSON(url.Values{
+			"state":          {"completed"},
+			"metadata.label": {"alice"},
+			"sort_by":        {"created_at"},
+			"descending":     {"true"},
+			"offset":         {"1"},
+			"limit":          {"2"},
+			"empty":          {},
+		})
+		require.NoError(t, err)
+
+		var params didexchangeSvc.QueryConnectionsParams
+		require.NoError(t, json.Unmarshal(b, &params))
+		require.Equal(t, didexchangeSvc.QueryConnectionsParams{
+			State:      "completed",
+			Metadata:   map[string]string{"label": "alice"},
+			SortBy:     "created_at",
+			Descending: true,
+			Offset:     1,
+			Limit:      2,
+		}, params)
+	})
+
+	t.Run("test invalid values", func(t *testing.T) {
+		_, err := queryConnectionsAsJSON(url.Values{"limit": {"ten"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid limit")
+
+		_, err = queryConnectionsAsJSON(url.Values{"descending": {"maybe"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid descending")
+	})
+
+	t.Run("test invalid query request", func(t *testing.T) {
+		handler := getHandler(t, Connections)
+		buf, code, err := sendRequestToHandler(handler, nil, OperationID+"?offset=-")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, didexchange.InvalidRequestErrorCode, buf.Bytes())
+	})
+}
+
 func TestGetIDFromRequest(t *testing.T) {
 	id, found := getIDFromRequest(httptest.NewRecorder(), &http.Request{})
 	require.False(t, found)
@@ -429,6 +534,7 @@ func TestEmptyID(t *testing.T) {
 
 	restHandlers := []http.HandlerFunc{
 		op.AcceptInvitation, op.AcceptExchangeRequest, op.QueryConnectionByID, op.RemoveConnection,
+		op.SetConnectionMetadata,
 	}
 	for _, handler := range restHandlers {
 		rw := httptest.NewRecorder()
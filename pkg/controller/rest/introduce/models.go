@@ -158,6 +158,8 @@ type introduceAcceptRequestWithRecipients struct { // nolint: unused,deadcode
 	Body struct {
 		// Recipient specifies to whom proposal will be sent
 		Recipient struct{ *protocol.Recipient } `json:"recipient"`
+		// Recipients specifies additional recipients the requester will be introduced to
+		Recipients []struct{ *protocol.Recipient } `json:"recipients,omitempty"`
 		// To keeps information about the introduction
 		To struct{ *protocol.To } `json:"to"`
 	}
@@ -303,3 +305,28 @@ type introduceAcceptProblemReportResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct{}
 }
+
+// introduceRecipientStatusesRequest model
+//
+// This is used for operation to get the state of the introduction per recipient.
+//
+// swagger:parameters introduceRecipientStatuses
+type introduceRecipientStatusesRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
+
+// introduceRecipientStatusesResponse model
+//
+// Represents a RecipientStatuses response message.
+//
+// swagger:response introduceRecipientStatusesResponse
+type introduceRecipientStatusesResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Recipients []struct{ *protocol.RecipientStatus } `json:"recipients"`
+	}
+}
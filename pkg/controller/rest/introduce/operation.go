@@ -38,6 +38,7 @@ const (
 	DeclineProposal                      = OperationID + "/{piid}/decline-proposal"
 	DeclineRequest                       = OperationID + "/{piid}/decline-request"
 	AcceptProblemReport                  = OperationID + "/{piid}/accept-problem-report"
+	RecipientStatuses                    = OperationID + "/{piid}/recipients"
 )
 
 // Operation is controller REST service controller for the introduce.
@@ -47,8 +48,8 @@ type Operation struct {
 }
 
 // New returns new introduce rest client protocol instance.
-func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
-	cmd, err := introduce.New(ctx, notifier)
+func New(ctx client.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Operation, error) {
+	cmd, err := introduce.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("introduce command : %w", err)
 	}
@@ -79,6 +80,7 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(DeclineProposal, http.MethodPost, c.DeclineProposal),
 		cmdutil.NewHTTPHandler(DeclineRequest, http.MethodPost, c.DeclineRequest),
 		cmdutil.NewHTTPHandler(AcceptProblemReport, http.MethodPost, c.AcceptProblemReport),
+		cmdutil.NewHTTPHandler(RecipientStatuses, http.MethodGet, c.RecipientStatuses),
 	}
 }
 
@@ -218,6 +220,19 @@ func (c *Operation) AcceptProblemReport(rw http.ResponseWriter, req *http.Reques
 	}`, mux.Vars(req)["piid"])))
 }
 
+// RecipientStatuses swagger:route GET /introduce/{piid}/recipients introduce introduceRecipientStatuses
+//
+// Returns the state of the introduction per recipient of the proposals.
+//
+// Responses:
+//    default: genericError
+//        200: introduceRecipientStatusesResponse
+func (c *Operation) RecipientStatuses(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RecipientStatuses, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"piid":%q
+	}`, mux.Vars(req)["piid"])))
+}
+
 func toCommandRequest(rw http.ResponseWriter, req *http.Request) (bool, io.Reader) {
 	var buf bytes.Buffer
 
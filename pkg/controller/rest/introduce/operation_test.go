@@ -32,6 +32,7 @@ func provider(ctrl *gomock.Controller) client.Provider {
 	service.EXPECT().ActionStop(gomock.Any(), gomock.Any()).AnyTimes()
 	service.EXPECT().HandleOutbound(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	service.EXPECT().Actions().AnyTimes()
+	service.EXPECT().RecipientStatuses(gomock.Any()).AnyTimes()
 
 	provider := mocks.NewMockProvider(ctrl)
 	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
@@ -305,6 +306,25 @@ func TestOperation_AcceptProblemReport(t *testing.T) {
 	})
 }
 
+func TestOperation_RecipientStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		_, code, err := sendRequestToHandler(
+			handlerLookup(t, operation, RecipientStatuses),
+			nil,
+			strings.Replace(RecipientStatuses, `{piid}`, "1234", 1),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+}
+
 func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	t.Helper()
 
@@ -50,8 +50,8 @@ type Operation struct {
 }
 
 // New returns new issue credential rest client protocol instance.
-func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
-	cmd, err := issuecredential.New(ctx, notifier)
+func New(ctx client.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Operation, error) {
+	cmd, err := issuecredential.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("issue credential command : %w", err)
 	}
@@ -43,3 +43,106 @@ type importKeyReq struct {
 	// in: body
 	kms.JSONWebKey
 }
+
+// listKeysReq model
+//
+// This is used for listKeys request. Empty parameters match any key.
+//
+// swagger:parameters listKeys
+type listKeysReq struct { // nolint: unused,deadcode
+	// Key type
+	//
+	// in: query
+	KeyType string `json:"keyType"`
+
+	// Key label
+	//
+	// in: query
+	Label string `json:"label"`
+
+	// Key purpose
+	//
+	// in: query
+	Purpose string `json:"purpose"`
+
+	// DID owning the key
+	//
+	// in: query
+	DID string `json:"did"`
+
+	// Matches the keys created before this time (RFC3339 format)
+	//
+	// in: query
+	CreatedBefore string `json:"createdBefore"`
+}
+
+// listKeysRes model
+//
+// This is used for returning the metadata of the listed keys
+//
+// swagger:response listKeysRes
+type listKeysRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.ListKeysResponse
+}
+
+// deleteKeyReq model
+//
+// This is used for deleteKey request.
+//
+// swagger:parameters deleteKey
+type deleteKeyReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
+
+// setKeyMetadataReq model
+//
+// This is used for setKeyMetadata request.
+//
+// swagger:parameters setKeyMetadata
+type setKeyMetadataReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+
+	// Params for setKeyMetadata
+	//
+	// in: body
+	Params struct {
+		Label   string `json:"label,omitempty"`
+		Purpose string `json:"purpose,omitempty"`
+		DID     string `json:"did,omitempty"`
+		Usage   string `json:"usage,omitempty"`
+	}
+}
+
+// getKeyMetadataReq model
+//
+// This is used for getKeyMetadata request.
+//
+// swagger:parameters getKeyMetadata
+type getKeyMetadataReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
+
+// getKeyMetadataRes model
+//
+// This is used for returning the metadata of a key
+//
+// swagger:response getKeyMetadataRes
+type getKeyMetadataRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.GetKeyMetadataResponse
+}
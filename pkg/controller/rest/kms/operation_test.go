@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	kmsapi "github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
 )
@@ -31,7 +32,7 @@ func TestNew(t *testing.T) {
 			KMSValue: &mockkms.KeyManager{},
 		})
 		require.NotNil(t, cmd)
-		require.Equal(t, 2, len(cmd.GetRESTHandlers()))
+		require.Equal(t, 6, len(cmd.GetRESTHandlers()))
 	})
 }
 
@@ -102,14 +103,155 @@ func TestImportKey(t *testing.T) {
 	})
 }
 
+func TestListKeys(t *testing.T) {
+	t.Run("test list keys - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListValue: []*kmsapi.KeyMetadata{{KeyID: "k1", Label: "signing"}}},
+		})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandlerWithMethod(t, cmd, KeysPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KeysPath+"?label=signing&createdBefore=2021-01-01T00:00:00Z")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		response := kms.ListKeysResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Len(t, response.Keys, 1)
+		require.Equal(t, "k1", response.Keys[0].KeyID)
+	})
+
+	t.Run("test list keys - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{})
+		cmd.command = &mockKMSCommand{listKeysError: command.NewExecuteError(kms.ListKeysError,
+			fmt.Errorf("failed to list keys"))}
+
+		handler := lookupHandlerWithMethod(t, cmd, KeysPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KeysPath)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.ListKeysError, "failed to list keys", buf.Bytes())
+	})
+}
+
+func TestDeleteKey(t *testing.T) {
+	t.Run("test delete key - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandlerWithMethod(t, cmd, KeyPath, http.MethodDelete)
+
+		_, code, err := sendRequestToHandler(handler, nil, KeysPath+"/k1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test delete key - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("failed to delete key")},
+		})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandlerWithMethod(t, cmd, KeyPath, http.MethodDelete)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KeysPath+"/k1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.DeleteKeyError, "failed to delete key", buf.Bytes())
+	})
+}
+
+func TestSetKeyMetadata(t *testing.T) {
+	t.Run("test set key metadata - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandler(t, cmd, KeyMetadataPath)
+
+		_, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"label":"signing","usage":"sign"}`),
+			KeysPath+"/k1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test set key metadata - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandler(t, cmd, KeyMetadataPath)
+
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString("--"), KeysPath+"/k1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, kms.InvalidRequestErrorCode, "failed request decode", buf.Bytes())
+	})
+
+	t.Run("test set key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{SetMetadataErr: fmt.Errorf("failed to set metadata")},
+		})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandler(t, cmd, KeyMetadataPath)
+
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"label":"signing"}`),
+			KeysPath+"/k1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.SetKeyMetadataError, "failed to set metadata", buf.Bytes())
+	})
+}
+
+func TestGetKeyMetadata(t *testing.T) {
+	t.Run("test get key metadata - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{GetMetadataValue: &kmsapi.KeyMetadata{KeyID: "k1", Label: "signing"}},
+		})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandlerWithMethod(t, cmd, KeyMetadataPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KeysPath+"/k1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		response := kms.GetKeyMetadataResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, "k1", response.Metadata.KeyID)
+		require.Equal(t, "signing", response.Metadata.Label)
+	})
+
+	t.Run("test get key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{GetMetadataErr: fmt.Errorf("failed to get metadata")},
+		})
+		require.NotNil(t, cmd)
+
+		handler := lookupHandlerWithMethod(t, cmd, KeyMetadataPath, http.MethodGet)
+
+		buf, code, err := sendRequestToHandler(handler, nil, KeysPath+"/k1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.GetKeyMetadataError, "failed to get metadata", buf.Bytes())
+	})
+}
+
 func lookupHandler(t *testing.T, op *Operation, path string) rest.Handler {
 	t.Helper()
 
+	return lookupHandlerWithMethod(t, op, path, http.MethodPost)
+}
+
+func lookupHandlerWithMethod(t *testing.T, op *Operation, path, method string) rest.Handler {
+	t.Helper()
+
 	handlers := op.GetRESTHandlers()
 	require.NotEmpty(t, handlers)
 
 	for _, h := range handlers {
-		if h.Path() == path && h.Method() == http.MethodPost {
+		if h.Path() == path && h.Method() == method {
 			return h
 		}
 	}
@@ -176,6 +318,7 @@ func verifyError(t *testing.T, expectedCode command.Code, expectedMsg string, da
 
 type mockKMSCommand struct {
 	importKeyError command.Error
+	listKeysError  command.Error
 }
 
 func (m *mockKMSCommand) CreateKeySet(rw io.Writer, req io.Reader) command.Error {
@@ -185,3 +328,19 @@ func (m *mockKMSCommand) CreateKeySet(rw io.Writer, req io.Reader) command.Error
 func (m *mockKMSCommand) ImportKey(rw io.Writer, req io.Reader) command.Error {
 	return m.importKeyError
 }
+
+func (m *mockKMSCommand) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	return m.listKeysError
+}
+
+func (m *mockKMSCommand) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) SetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package outofband
 
 import (
+	"time"
+
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 )
@@ -28,6 +30,13 @@ type outofbandCreateInvitationRequest struct { // nolint: unused,deadcode
 		// Attachments is intended to provide the possibility to include files, links or even JSON payload to the message.
 		// required: true
 		Attachments []*decorator.Attachment `json:"attachments"`
+		// MaxUses is the number of connections the invitation can be used for, zero means unlimited.
+		MaxUses int `json:"max_uses,omitempty"`
+		// ExpiresAt is the time the invitation expires at, the invitation never expires if not set.
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+		// NoHandshake creates an invitation without handshake protocols, the attachments are exchanged
+		// without a connection.
+		NoHandshake bool `json:"no_handshake,omitempty"`
 	}
 }
 
@@ -141,3 +150,148 @@ type outofbandActionStopResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct{}
 }
+
+// outofbandInvitationURLRequest model
+//
+// This is used for operation to get the URL of an invitation created by the agent.
+//
+// swagger:parameters outofbandInvitationURL
+type outofbandInvitationURLRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// required: true
+		InvitationID string `json:"invitation_id"`
+		// BaseURL is the URL the invitation is appended to.
+		// required: true
+		BaseURL string `json:"base_url"`
+	}
+}
+
+// outofbandInvitationURLResponse model
+//
+// Represents an InvitationURL response message.
+//
+// swagger:response outofbandInvitationURLResponse
+type outofbandInvitationURLResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		URL       string `json:"url"`
+		ShortURL  string `json:"short_url"`
+		QRPayload string `json:"qr_payload"`
+	}
+}
+
+// outofbandResolveInvitationURLRequest model
+//
+// This is used for operation to resolve an invitation URL or a shortened invitation URL.
+//
+// swagger:parameters outofbandResolveInvitationURL
+type outofbandResolveInvitationURLRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// required: true
+		URL string `json:"url"`
+	}
+}
+
+// outofbandResolveInvitationURLResponse model
+//
+// Represents a ResolveInvitationURL response message.
+//
+// swagger:response outofbandResolveInvitationURLResponse
+type outofbandResolveInvitationURLResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Invitation struct{ *protocol.Invitation } `json:"invitation"`
+	}
+}
+
+// outofbandInvitationsRequest model
+//
+// Returns the records of the invitations created by the agent.
+//
+// swagger:parameters outofbandInvitations
+type outofbandInvitationsRequest struct{} // nolint: unused,deadcode
+
+// outofbandInvitationsResponse model
+//
+// Represents an Invitations response message.
+//
+// swagger:response outofbandInvitationsResponse
+type outofbandInvitationsResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Invitations []struct{ *protocol.InvitationRecord } `json:"invitations"`
+	}
+}
+
+// outofbandGetInvitationRequest model
+//
+// Returns the record of the invitation created by the agent.
+//
+// swagger:parameters outofbandGetInvitation
+type outofbandGetInvitationRequest struct { // nolint: unused,deadcode
+	// Invitation ID
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// outofbandGetInvitationResponse model
+//
+// Represents a GetInvitation response message.
+//
+// swagger:response outofbandGetInvitationResponse
+type outofbandGetInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		InvitationRecord struct{ *protocol.InvitationRecord } `json:"invitation_record"`
+	}
+}
+
+// outofbandRevokeInvitationRequest model
+//
+// Revokes the invitation created by the agent.
+//
+// swagger:parameters outofbandRevokeInvitation
+type outofbandRevokeInvitationRequest struct { // nolint: unused,deadcode
+	// Invitation ID
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// outofbandRevokeInvitationResponse model
+//
+// Represents a RevokeInvitation response message.
+//
+// swagger:response outofbandRevokeInvitationResponse
+type outofbandRevokeInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{}
+}
+
+// outofbandShortInvitationRequest model
+//
+// Resolves the shortened invitation URL.
+//
+// swagger:parameters outofbandShortInvitation
+type outofbandShortInvitationRequest struct { // nolint: unused,deadcode
+	// Invitation ID
+	//
+	// in: query
+	// required: true
+	ID string `json:"id"`
+}
+
+// outofbandShortInvitationResponse model
+//
+// Represents the invitation served at the shortened invitation URL (Accept: application/json).
+//
+// swagger:response outofbandShortInvitationResponse
+type outofbandShortInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct{ *protocol.Invitation }
+}
@@ -29,6 +29,12 @@ const (
 	Actions          = OperationID + "/actions"
 	ActionContinue   = OperationID + "/{piid}/action-continue"
 	ActionStop       = OperationID + "/{piid}/action-stop"
+
+	InvitationURL        = OperationID + "/invitation-url"
+	ResolveInvitationURL = OperationID + "/resolve-invitation-url"
+	Invitations          = OperationID + "/invitations"
+	GetInvitation        = Invitations + "/{id}"
+	RevokeInvitation     = Invitations + "/{id}/revoke"
 )
 
 // Operation is controller REST service controller for outofband.
@@ -38,8 +44,8 @@ type Operation struct {
 }
 
 // New returns new outofband rest client protocol instance.
-func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
-	cmd, err := outofband.New(ctx, notifier)
+func New(ctx client.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Operation, error) {
+	cmd, err := outofband.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("outofband command : %w", err)
 	}
@@ -64,6 +70,11 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(Actions, http.MethodGet, c.Actions),
 		cmdutil.NewHTTPHandler(ActionContinue, http.MethodPost, c.ActionContinue),
 		cmdutil.NewHTTPHandler(ActionStop, http.MethodPost, c.ActionStop),
+		cmdutil.NewHTTPHandler(InvitationURL, http.MethodPost, c.InvitationURL),
+		cmdutil.NewHTTPHandler(ResolveInvitationURL, http.MethodPost, c.ResolveInvitationURL),
+		cmdutil.NewHTTPHandler(Invitations, http.MethodGet, c.Invitations),
+		cmdutil.NewHTTPHandler(GetInvitation, http.MethodGet, c.GetInvitation),
+		cmdutil.NewHTTPHandler(RevokeInvitation, http.MethodPost, c.RevokeInvitation),
 	}
 }
 
@@ -128,3 +139,62 @@ func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request)
 func (c *Operation) AcceptInvitation(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(c.command.AcceptInvitation, rw, req.Body)
 }
+
+// InvitationURL swagger:route POST /outofband/invitation-url outofband outofbandInvitationURL
+//
+// Returns the URL, the shortened URL and the QR code payload of the invitation created by the agent.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandInvitationURLResponse
+func (c *Operation) InvitationURL(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.InvitationURL, rw, req.Body)
+}
+
+// ResolveInvitationURL swagger:route POST /outofband/resolve-invitation-url outofband outofbandResolveInvitationURL
+//
+// Resolves the invitation of an invitation URL or a shortened invitation URL.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandResolveInvitationURLResponse
+func (c *Operation) ResolveInvitationURL(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ResolveInvitationURL, rw, req.Body)
+}
+
+// Invitations swagger:route GET /outofband/invitations outofband outofbandInvitations
+//
+// Returns the records of the invitations created by the agent.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandInvitationsResponse
+func (c *Operation) Invitations(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.Invitations, rw, nil)
+}
+
+// GetInvitation swagger:route GET /outofband/invitations/{id} outofband outofbandGetInvitation
+//
+// Returns the record of the invitation created by the agent.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandGetInvitationResponse
+func (c *Operation) GetInvitation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetInvitation, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"invitation_id":%q
+	}`, mux.Vars(req)["id"])))
+}
+
+// RevokeInvitation swagger:route POST /outofband/invitations/{id}/revoke outofband outofbandRevokeInvitation
+//
+// Revokes the invitation created by the agent.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandRevokeInvitationResponse
+func (c *Operation) RevokeInvitation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RevokeInvitation, rw, bytes.NewBufferString(fmt.Sprintf(`{
+		"invitation_id":%q
+	}`, mux.Vars(req)["id"])))
+}
@@ -22,6 +22,7 @@ import (
 
 	client "github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
 )
@@ -30,6 +31,8 @@ const (
 	piid   = "1234"
 	label  = "label"
 	reason = "reason"
+
+	invitationID = "5678"
 )
 
 func provider(ctrl *gomock.Controller) client.Provider {
@@ -41,6 +44,12 @@ func provider(ctrl *gomock.Controller) client.Provider {
 	service.EXPECT().ActionContinue(piid, &client.EventOptions{Label: label}).AnyTimes()
 	service.EXPECT().ActionStop(piid, errors.New(reason)).AnyTimes()
 	service.EXPECT().Actions().AnyTimes()
+	service.EXPECT().GetInvitationRecord(invitationID).Return(&protocol.InvitationRecord{
+		Invitation: &protocol.Invitation{ID: invitationID, Type: protocol.InvitationMsgType},
+		State:      protocol.InvitationStateActive,
+	}, nil).AnyTimes()
+	service.EXPECT().InvitationRecords().AnyTimes()
+	service.EXPECT().RevokeInvitation(invitationID).Return(nil).AnyTimes()
 
 	provider := mocks.NewMockProvider(ctrl)
 	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
@@ -159,6 +168,116 @@ func TestOperation_ActionStop(t *testing.T) {
 	require.Equal(t, http.StatusOK, code)
 }
 
+func TestOperation_InvitationURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	b, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, InvitationURL),
+		bytes.NewBufferString(`{
+			"invitation_id":"`+invitationID+`",
+			"base_url":"https://example.com"
+		}`),
+		InvitationURL,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	res := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.NotEmpty(t, res["url"])
+	require.Equal(t, "https://example.com/ssi?id="+invitationID, res["short_url"])
+	require.Equal(t, res["url"], res["qr_payload"])
+}
+
+func TestOperation_ResolveInvitationURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	invitationURL, err := protocol.EncodeInvitationURL("https://example.com",
+		&protocol.Invitation{ID: invitationID, Type: protocol.InvitationMsgType})
+	require.NoError(t, err)
+
+	b, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, ResolveInvitationURL),
+		bytes.NewBufferString(`{"url":"`+invitationURL+`"}`),
+		ResolveInvitationURL,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	res := struct {
+		Invitation *protocol.Invitation `json:"invitation"`
+	}{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, invitationID, res.Invitation.ID)
+}
+
+func TestOperation_Invitations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, Invitations),
+		nil,
+		Invitations,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_GetInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	b, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, GetInvitation),
+		nil,
+		strings.Replace(GetInvitation, `{id}`, invitationID, 1),
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	res := struct {
+		InvitationRecord *protocol.InvitationRecord `json:"invitation_record"`
+	}{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, invitationID, res.InvitationRecord.Invitation.ID)
+}
+
+func TestOperation_RevokeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	_, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, RevokeInvitation),
+		nil,
+		strings.Replace(RevokeInvitation, `{id}`, invitationID, 1),
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
 func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	t.Helper()
 
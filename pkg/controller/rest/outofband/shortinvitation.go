@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	client "github.com/hyperledger/aries-framework-go/pkg/client/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/rest/outofband")
+
+// ShortInvitation is the path the shortened invitation URLs are served at.
+const ShortInvitation = protocol.ShortURLPath
+
+// NewShortInvitationHandler returns the handler serving the invitations created by the agent
+// at the shortened invitation URLs, e.g https://example.com/ssi?id=1234.
+// The handler is meant to be public, the invitation is either returned as JSON (Accept: application/json)
+// or the request is redirected to the invitation URL built from the baseURL.
+func NewShortInvitationHandler(ctx client.Provider, baseURL string) (rest.Handler, error) {
+	c, err := client.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("outofband client : %w", err)
+	}
+
+	h := &shortInvitationHandler{client: c, baseURL: baseURL}
+
+	return cmdutil.NewHTTPHandler(ShortInvitation, http.MethodGet, h.handle), nil
+}
+
+type shortInvitationHandler struct {
+	client  *client.Client
+	baseURL string
+}
+
+// handle swagger:route GET /ssi outofband outofbandShortInvitation
+//
+// Resolves the shortened invitation URL.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandShortInvitationResponse
+//        302: description: redirect to the invitation URL
+func (h *shortInvitationHandler) handle(rw http.ResponseWriter, req *http.Request) {
+	invitationID := req.URL.Query().Get(protocol.ShortURLParam)
+	if invitationID == "" {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, outofband.InvalidRequestErrorCode,
+			errors.New("invitation id was not provided"))
+
+		return
+	}
+
+	record, err := h.client.GetInvitationRecord(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		rest.SendHTTPStatusError(rw, http.StatusNotFound, outofband.GetInvitationErrorCode,
+			fmt.Errorf("invitation %s not found", invitationID))
+
+		return
+	}
+
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusInternalServerError, outofband.GetInvitationErrorCode, err)
+
+		return
+	}
+
+	if record.State != protocol.InvitationStateActive {
+		rest.SendHTTPStatusError(rw, http.StatusGone, outofband.GetInvitationErrorCode,
+			fmt.Errorf("invitation %s is %s", invitationID, record.State))
+
+		return
+	}
+
+	if strings.Contains(req.Header.Get("Accept"), "application/json") {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if err = json.NewEncoder(rw).Encode(record.Invitation); err != nil {
+			logger.Errorf("Unable to send invitation: %v", err)
+		}
+
+		return
+	}
+
+	invitationURL, err := protocol.EncodeInvitationURL(h.baseURL, record.Invitation)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusInternalServerError, outofband.InvitationURLErrorCode, err)
+
+		return
+	}
+
+	http.Redirect(rw, req, invitationURL, http.StatusFound)
+}
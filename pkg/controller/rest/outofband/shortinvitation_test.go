@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestNewShortInvitationHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := NewShortInvitationHandler(provider, "https://example.com")
+		require.EqualError(t, err, "outofband client : failed to look up service out-of-band : error")
+	})
+
+	inv := &protocol.Invitation{ID: invitationID, Type: protocol.InvitationMsgType, Label: label}
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().GetInvitationRecord(invitationID).Return(&protocol.InvitationRecord{
+		Invitation: inv,
+		State:      protocol.InvitationStateActive,
+	}, nil).AnyTimes()
+	service.EXPECT().GetInvitationRecord("revoked").Return(&protocol.InvitationRecord{
+		Invitation: &protocol.Invitation{ID: "revoked"},
+		State:      protocol.InvitationStateRevoked,
+	}, nil).AnyTimes()
+	service.EXPECT().GetInvitationRecord("unknown").
+		Return(nil, fmt.Errorf("get invitation record: %w", storage.ErrDataNotFound)).AnyTimes()
+	service.EXPECT().GetInvitationRecord("broken").Return(nil, errors.New("error")).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	handler, err := NewShortInvitationHandler(provider, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, ShortInvitation, handler.Path())
+	require.Equal(t, http.MethodGet, handler.Method())
+
+	serve := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+
+		rr := httptest.NewRecorder()
+		handler.Handle()(rr, req)
+
+		return rr
+	}
+
+	t.Run("Redirect to invitation URL", func(t *testing.T) {
+		rr := serve(ShortInvitation+"?id="+invitationID, "text/html")
+		require.Equal(t, http.StatusFound, rr.Code)
+
+		decoded, err := protocol.DecodeInvitationURL(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded)
+	})
+
+	t.Run("Invitation as JSON", func(t *testing.T) {
+		rr := serve(ShortInvitation+"?id="+invitationID, "application/json")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		res := &protocol.Invitation{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), res))
+		require.Equal(t, inv, res)
+	})
+
+	t.Run("No invitation ID", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, serve(ShortInvitation, "").Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, serve(ShortInvitation+"?id=unknown", "").Code)
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		require.Equal(t, http.StatusGone, serve(ShortInvitation+"?id=revoked", "").Code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		require.Equal(t, http.StatusInternalServerError, serve(ShortInvitation+"?id=broken", "").Code)
+	})
+}
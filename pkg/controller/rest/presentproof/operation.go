@@ -47,8 +47,8 @@ type Operation struct {
 }
 
 // New returns new present proof rest client protocol instance.
-func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
-	cmd, err := presentproof.New(ctx, notifier)
+func New(ctx client.Provider, notifier command.Notifier, filters ...command.ActionFilter) (*Operation, error) {
+	cmd, err := presentproof.New(ctx, notifier, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("present proof command : %w", err)
 	}
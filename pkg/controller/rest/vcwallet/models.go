@@ -0,0 +1,275 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+)
+
+// createProfileReq model
+//
+// This is used for creating new wallet profile.
+//
+// swagger:parameters createProfileReq
+type createProfileReq struct { // nolint: unused,deadcode
+	// Params for creating new wallet profile.
+	//
+	// in: body
+	Params vcwallet.CreateOrUpdateProfileRequest
+}
+
+// updateProfileReq model
+//
+// This is used for updating an existing wallet profile.
+//
+// swagger:parameters updateProfileReq
+type updateProfileReq struct { // nolint: unused,deadcode
+	// Params for updating an existing wallet profile.
+	//
+	// in: body
+	Params vcwallet.CreateOrUpdateProfileRequest
+}
+
+// unlockWalletReq model
+//
+// This is used for unlocking a wallet.
+//
+// swagger:parameters unlockWalletReq
+type unlockWalletReq struct { // nolint: unused,deadcode
+	// Params for unlocking a wallet.
+	//
+	// in: body
+	Params vcwallet.UnlockWalletRequest
+}
+
+// unlockWalletRes model
+//
+// This is used for returning response of wallet unlock operation.
+//
+// swagger:response unlockWalletRes
+type unlockWalletRes struct { // nolint: unused,deadcode
+	// Wallet unlock response containing authorization token.
+	//
+	// in: body
+	vcwallet.UnlockWalletResponse
+}
+
+// lockWalletReq model
+//
+// This is used for locking a wallet.
+//
+// swagger:parameters lockWalletReq
+type lockWalletReq struct { // nolint: unused,deadcode
+	// Params for locking a wallet.
+	//
+	// in: body
+	Params vcwallet.LockWalletRequest
+}
+
+// lockWalletRes model
+//
+// This is used for returning response of wallet lock operation.
+//
+// swagger:response lockWalletRes
+type lockWalletRes struct { // nolint: unused,deadcode
+	// Wallet lock response.
+	//
+	// in: body
+	vcwallet.LockWalletResponse
+}
+
+// addContentReq model
+//
+// This is used for adding a content to wallet.
+//
+// swagger:parameters addContentReq
+type addContentReq struct { // nolint: unused,deadcode
+	// Params for adding content to wallet.
+	//
+	// in: body
+	Params vcwallet.AddContentRequest
+}
+
+// removeContentReq model
+//
+// This is used for removing a content from wallet.
+//
+// swagger:parameters removeContentReq
+type removeContentReq struct { // nolint: unused,deadcode
+	// Params for removing content from wallet.
+	//
+	// in: body
+	Params vcwallet.RemoveContentRequest
+}
+
+// getContentReq model
+//
+// This is used for getting a content from wallet.
+//
+// swagger:parameters getContentReq
+type getContentReq struct { // nolint: unused,deadcode
+	// Params for getting content from wallet.
+	//
+	// in: body
+	Params vcwallet.GetContentRequest
+}
+
+// getContentRes model
+//
+// This is used for returning get content response.
+//
+// swagger:response getContentRes
+type getContentRes struct { // nolint: unused,deadcode
+	// Get content response.
+	//
+	// in: body
+	vcwallet.GetContentResponse
+}
+
+// getAllContentReq model
+//
+// This is used for getting all contents of given content type from wallet.
+//
+// swagger:parameters getAllContentReq
+type getAllContentReq struct { // nolint: unused,deadcode
+	// Params for getting all contents from wallet.
+	//
+	// in: body
+	Params vcwallet.GetAllContentRequest
+}
+
+// getAllContentRes model
+//
+// This is used for returning get all content response.
+//
+// swagger:response getAllContentRes
+type getAllContentRes struct { // nolint: unused,deadcode
+	// Get all content response.
+	//
+	// in: body
+	vcwallet.GetAllContentResponse
+}
+
+// contentQueryReq model
+//
+// This is used for querying credentials from wallet.
+//
+// swagger:parameters contentQueryReq
+type contentQueryReq struct { // nolint: unused,deadcode
+	// Params for querying credentials from wallet.
+	//
+	// in: body
+	Params vcwallet.ContentQueryRequest
+}
+
+// contentQueryRes model
+//
+// This is used for returning query results.
+//
+// swagger:response contentQueryRes
+type contentQueryRes struct { // nolint: unused,deadcode
+	// Query results.
+	//
+	// in: body
+	vcwallet.ContentQueryResponse
+}
+
+// issueReq model
+//
+// This is used for issuing a credential from wallet.
+//
+// swagger:parameters issueReq
+type issueReq struct { // nolint: unused,deadcode
+	// Params for issuing credential from wallet.
+	//
+	// in: body
+	Params vcwallet.IssueRequest
+}
+
+// issueRes model
+//
+// This is used for returning issued credential.
+//
+// swagger:response issueRes
+type issueRes struct { // nolint: unused,deadcode
+	// Issued credential.
+	//
+	// in: body
+	vcwallet.IssueResponse
+}
+
+// proveReq model
+//
+// This is used for producing a presentation from wallet.
+//
+// swagger:parameters proveReq
+type proveReq struct { // nolint: unused,deadcode
+	// Params for producing verifiable presentation from wallet.
+	//
+	// in: body
+	Params vcwallet.ProveRequest
+}
+
+// proveRes model
+//
+// This is used for returning presentation from wallet prove operation.
+//
+// swagger:response proveRes
+type proveRes struct { // nolint: unused,deadcode
+	// Presentation produced by wallet.
+	//
+	// in: body
+	vcwallet.ProveResponse
+}
+
+// verifyReq model
+//
+// This is used for verifying a credential or presentation from wallet.
+//
+// swagger:parameters verifyReq
+type verifyReq struct { // nolint: unused,deadcode
+	// Params for verifying a credential or presentation from wallet.
+	//
+	// in: body
+	Params vcwallet.VerifyRequest
+}
+
+// verifyRes model
+//
+// This is used for returning verification result.
+//
+// swagger:response verifyRes
+type verifyRes struct { // nolint: unused,deadcode
+	// Verification result.
+	//
+	// in: body
+	vcwallet.VerifyResponse
+}
+
+// deriveReq model
+//
+// This is used for deriving a credential from wallet.
+//
+// swagger:parameters deriveReq
+type deriveReq struct { // nolint: unused,deadcode
+	// Params for deriving a credential from wallet.
+	//
+	// in: body
+	Params vcwallet.DeriveRequest
+}
+
+// deriveRes model
+//
+// This is used for returning derived credential.
+//
+// swagger:response deriveRes
+type deriveRes struct { // nolint: unused,deadcode
+	// Derived credential.
+	//
+	// in: body
+	vcwallet.DeriveResponse
+}
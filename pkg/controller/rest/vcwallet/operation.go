@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// All command operations.
+const (
+	OperationID = "/vcwallet"
+
+	// command Paths.
+	CreateProfilePath = OperationID + "/create-profile"
+	UpdateProfilePath = OperationID + "/update-profile"
+	OpenPath          = OperationID + "/open"
+	ClosePath         = OperationID + "/close"
+	AddPath           = OperationID + "/add"
+	RemovePath        = OperationID + "/remove"
+	GetPath           = OperationID + "/get"
+	GetAllPath        = OperationID + "/getall"
+	QueryPath         = OperationID + "/query"
+	IssuePath         = OperationID + "/issue"
+	ProvePath         = OperationID + "/prove"
+	VerifyPath        = OperationID + "/verify"
+	DerivePath        = OperationID + "/derive"
+)
+
+// provider contains dependencies for the verifiable credential wallet command
+// and is typically created by using aries.Context().
+type provider interface {
+	StorageProvider() storage.Provider
+	VDRegistry() vdrapi.Registry
+	Crypto() crypto.Crypto
+	Service(id string) (interface{}, error)
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// Operation contains REST operations provided by verifiable credential wallet.
+type Operation struct {
+	handlers []rest.Handler
+	command  *vcwallet.Command
+}
+
+// New returns new verifiable credential wallet REST controller.
+func New(p provider, notifier command.Notifier) *Operation {
+	cmd := vcwallet.New(p, notifier)
+
+	o := &Operation{command: cmd}
+
+	o.registerHandler()
+
+	return o
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []rest.Handler {
+	return o.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (o *Operation) registerHandler() {
+	o.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(CreateProfilePath, http.MethodPost, o.CreateProfile),
+		cmdutil.NewHTTPHandler(UpdateProfilePath, http.MethodPost, o.UpdateProfile),
+		cmdutil.NewHTTPHandler(OpenPath, http.MethodPost, o.Open),
+		cmdutil.NewHTTPHandler(ClosePath, http.MethodPost, o.Close),
+		cmdutil.NewHTTPHandler(AddPath, http.MethodPost, o.Add),
+		cmdutil.NewHTTPHandler(RemovePath, http.MethodPost, o.Remove),
+		cmdutil.NewHTTPHandler(GetPath, http.MethodPost, o.Get),
+		cmdutil.NewHTTPHandler(GetAllPath, http.MethodPost, o.GetAll),
+		cmdutil.NewHTTPHandler(QueryPath, http.MethodPost, o.Query),
+		cmdutil.NewHTTPHandler(IssuePath, http.MethodPost, o.Issue),
+		cmdutil.NewHTTPHandler(ProvePath, http.MethodPost, o.Prove),
+		cmdutil.NewHTTPHandler(VerifyPath, http.MethodPost, o.Verify),
+		cmdutil.NewHTTPHandler(DerivePath, http.MethodPost, o.Derive),
+	}
+}
+
+// CreateProfile swagger:route POST /vcwallet/create-profile vcwallet createProfileReq
+//
+// Creates new wallet profile and returns error if wallet profile is already created.
+//
+// Responses:
+//    default: genericError
+//        200: emptyRes
+func (o *Operation) CreateProfile(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.CreateProfile, rw, req.Body)
+}
+
+// UpdateProfile swagger:route POST /vcwallet/update-profile vcwallet updateProfileReq
+//
+// Updates an existing wallet profile and returns error if profile doesn't exists.
+//
+// Responses:
+//    default: genericError
+//        200: emptyRes
+func (o *Operation) UpdateProfile(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.UpdateProfile, rw, req.Body)
+}
+
+// Open swagger:route POST /vcwallet/open vcwallet unlockWalletReq
+//
+// Unlocks given wallet's key manager instance & content store and
+// returns a authorization token to be used for performing wallet operations.
+//
+// Responses:
+//    default: genericError
+//        200: unlockWalletRes
+func (o *Operation) Open(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Open, rw, req.Body)
+}
+
+// Close swagger:route POST /vcwallet/close vcwallet lockWalletReq
+//
+// Expires token issued to this VC wallet, removes the key manager instance and closes wallet content store.
+//
+// returns response containing bool flag false if token is not found or already expired for this wallet user.
+//
+// Responses:
+//    default: genericError
+//        200: lockWalletRes
+func (o *Operation) Close(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Close, rw, req.Body)
+}
+
+// Add swagger:route POST /vcwallet/add vcwallet addContentReq
+//
+// adds given data model to wallet content store.
+//
+// Supported data models:
+//    - Collection
+//    - Credential
+//    - DIDResolutionResponse
+//    - Metadata
+//    - Connection
+//    - Key
+//
+// Responses:
+//    default: genericError
+//        200: emptyRes
+func (o *Operation) Add(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Add, rw, req.Body)
+}
+
+// Remove swagger:route POST /vcwallet/remove vcwallet removeContentReq
+//
+// removes given content from wallet content store.
+//
+// Responses:
+//    default: genericError
+//        200: emptyRes
+func (o *Operation) Remove(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Remove, rw, req.Body)
+}
+
+// Get swagger:route POST /vcwallet/get vcwallet getContentReq
+//
+// gets content from wallet content store.
+//
+// Responses:
+//    default: genericError
+//        200: getContentRes
+func (o *Operation) Get(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Get, rw, req.Body)
+}
+
+// GetAll swagger:route POST /vcwallet/getall vcwallet getAllContentReq
+//
+// gets all contents from wallet content store for given content type.
+//
+// Responses:
+//    default: genericError
+//        200: getAllContentRes
+func (o *Operation) GetAll(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.GetAll, rw, req.Body)
+}
+
+// Query swagger:route POST /vcwallet/query vcwallet contentQueryReq
+//
+// runs query against wallet credential contents and returns presentation containing credential results.
+//
+// This function may return multiple presentations as a result based on combination of query types used.
+//
+// https://w3c-ccg.github.io/universal-wallet-interop-spec/#query
+//
+// Supported Query Types:
+//    - https://www.w3.org/TR/json-ld11-framing
+//    - https://identity.foundation/presentation-exchange
+//    - https://w3c-ccg.github.io/vp-request-spec/#query-by-example
+//    - https://w3c-ccg.github.io/vp-request-spec/#did-authentication-request
+//
+// Responses:
+//    default: genericError
+//        200: contentQueryRes
+func (o *Operation) Query(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Query, rw, req.Body)
+}
+
+// Issue swagger:route POST /vcwallet/issue vcwallet issueReq
+//
+// issues a credential by adding a proof using keys of the wallet.
+//
+// Responses:
+//    default: genericError
+//        200: issueRes
+func (o *Operation) Issue(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Issue, rw, req.Body)
+}
+
+// Prove swagger:route POST /vcwallet/prove vcwallet proveReq
+//
+// produces a Verifiable Presentation from wallet.
+//
+// Responses:
+//    default: genericError
+//        200: proveRes
+func (o *Operation) Prove(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Prove, rw, req.Body)
+}
+
+// Verify swagger:route POST /vcwallet/verify vcwallet verifyReq
+//
+// verifies credential/presentation from wallet.
+//
+// Responses:
+//    default: genericError
+//        200: verifyRes
+func (o *Operation) Verify(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Verify, rw, req.Body)
+}
+
+// Derive swagger:route POST /vcwallet/derive vcwallet deriveReq
+//
+// derives a credential from wallet.
+//
+// Responses:
+//    default: genericError
+//        200: deriveRes
+func (o *Operation) Derive(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Derive, rw, req.Body)
+}
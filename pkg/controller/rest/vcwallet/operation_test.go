@@ -0,0 +1,227 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcwallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vcwallet"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/wallet"
+)
+
+const (
+	sampleUserID     = "sample-user01"
+	samplePassPhrase = "fakepassphrase"
+	sampleMetadata   = `{
+		"@context": ["https://w3id.org/wallet/v1"],
+		"id": "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		"type": "Metadata",
+		"name": "Ropsten Testnet HD Accounts"
+	}`
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new command - success", func(t *testing.T) {
+		op := New(newMockProvider(), nil)
+		require.NotNil(t, op)
+		require.Len(t, op.GetRESTHandlers(), 13)
+	})
+}
+
+func TestOperation_WalletLifecycle(t *testing.T) {
+	op := New(newMockProvider(), nil)
+
+	t.Run("create profile, open, add, get, get all, remove and close", func(t *testing.T) {
+		buf, code := sendRequest(t, op, CreateProfilePath, &vcwallet.CreateOrUpdateProfileRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		buf, code = sendRequest(t, op, OpenPath, &vcwallet.UnlockWalletRequest{
+			UserID:             sampleUserID,
+			LocalKMSPassphrase: samplePassPhrase,
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		var unlockResponse vcwallet.UnlockWalletResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &unlockResponse))
+		require.NotEmpty(t, unlockResponse.Token)
+
+		auth := vcwallet.WalletAuth{UserID: sampleUserID, Auth: unlockResponse.Token}
+
+		buf, code = sendRequest(t, op, AddPath, &vcwallet.AddContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			Content:     []byte(sampleMetadata),
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		buf, code = sendRequest(t, op, GetPath, &vcwallet.GetContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		var getResponse vcwallet.GetContentResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &getResponse))
+		require.NotEmpty(t, getResponse.Content)
+
+		buf, code = sendRequest(t, op, GetAllPath, &vcwallet.GetAllContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		var getAllResponse vcwallet.GetAllContentResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &getAllResponse))
+		require.Len(t, getAllResponse.Contents, 1)
+
+		buf, code = sendRequest(t, op, RemovePath, &vcwallet.RemoveContentRequest{
+			WalletAuth:  auth,
+			ContentType: wallet.Metadata,
+			ContentID:   "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002",
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		buf, code = sendRequest(t, op, ClosePath, &vcwallet.LockWalletRequest{UserID: sampleUserID, Auth: auth.Auth})
+		require.Equal(t, http.StatusOK, code, buf.String())
+
+		var lockResponse vcwallet.LockWalletResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &lockResponse))
+		require.True(t, lockResponse.Closed)
+	})
+
+	t.Run("update profile", func(t *testing.T) {
+		buf, code := sendRequest(t, op, UpdateProfilePath, &vcwallet.CreateOrUpdateProfileRequest{
+			UserID:      sampleUserID,
+			KeyStoreURL: "sample/keyserver/test",
+		})
+		require.Equal(t, http.StatusOK, code, buf.String())
+	})
+}
+
+func TestOperation_Failures(t *testing.T) {
+	op := New(newMockProvider(), nil)
+
+	for _, path := range []string{
+		CreateProfilePath, UpdateProfilePath, OpenPath, ClosePath, AddPath, RemovePath, GetPath, GetAllPath,
+		QueryPath, IssuePath, ProvePath, VerifyPath, DerivePath,
+	} {
+		buf, code := sendRequest(t, op, path, "--")
+		require.Equal(t, http.StatusBadRequest, code, path)
+		verifyError(t, vcwallet.InvalidRequestErrorCode, "", buf.Bytes())
+	}
+
+	buf, code := sendRequest(t, op, OpenPath, &vcwallet.UnlockWalletRequest{UserID: sampleUserID})
+	require.Equal(t, http.StatusBadRequest, code)
+	verifyError(t, vcwallet.InvalidRequestErrorCode, "profile does not exist", buf.Bytes())
+
+	buf, code = sendRequest(t, op, QueryPath, &vcwallet.ContentQueryRequest{
+		WalletAuth: vcwallet.WalletAuth{UserID: sampleUserID},
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	verifyError(t, vcwallet.InvalidRequestErrorCode, "profile does not exist", buf.Bytes())
+}
+
+func newMockProvider() *mockprovider.Provider {
+	return &mockprovider.Provider{StorageProviderValue: mockstorage.NewMockStoreProvider()}
+}
+
+func sendRequest(t *testing.T, op *Operation, path string, request interface{}) (*bytes.Buffer, int) {
+	t.Helper()
+
+	handler := lookupHandler(t, op, path)
+
+	var body []byte
+
+	if raw, ok := request.(string); ok {
+		body = []byte(raw)
+	} else {
+		var err error
+
+		body, err = json.Marshal(request)
+		require.NoError(t, err)
+	}
+
+	buf, code, err := sendRequestToHandler(handler, bytes.NewBuffer(body), path)
+	require.NoError(t, err)
+
+	return buf, code
+}
+
+func lookupHandler(t *testing.T, op *Operation, path string) rest.Handler {
+	t.Helper()
+
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == path && h.Method() == http.MethodPost {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
+
+func verifyError(t *testing.T, expectedCode command.Code, expectedMsg string, data []byte) {
+	t.Helper()
+
+	// Parser generic error response
+	errResponse := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	err := json.Unmarshal(data, &errResponse)
+	require.NoError(t, err)
+
+	// verify response
+	require.EqualValues(t, expectedCode, errResponse.Code)
+	require.NotEmpty(t, errResponse.Message)
+
+	if expectedMsg != "" {
+		require.Contains(t, errResponse.Message, expectedMsg)
+	}
+}
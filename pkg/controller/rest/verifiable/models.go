@@ -235,3 +235,38 @@ type deriveCredentialRes struct {
 	// in: body
 	verifiable.Credential
 }
+
+// verifyCredentialReq model
+//
+// This is used to verify the verifiable credential.
+//
+// swagger:parameters verifyCredentialReq
+type verifyCredentialReq struct { // nolint: unused,deadcode
+	// Params for verifying the verifiable credential (pass the vc document as a string)
+	//
+	// in: body
+	Params verifiable.Credential
+}
+
+// verifyPresentationReq model
+//
+// This is used to verify the verifiable presentation.
+//
+// swagger:parameters verifyPresentationReq
+type verifyPresentationReq struct { // nolint: unused,deadcode
+	// Params for verifying the verifiable presentation
+	//
+	// in: body
+	Params verifiable.Presentation
+}
+
+// verificationReportRes model
+//
+// This is used for returning the verification report of a credential or a presentation.
+//
+// swagger:response verificationReportRes
+type verificationReportRes struct { // nolint: unused,deadcode
+
+	// in: body
+	verifiable.VerificationReportResponse
+}
@@ -39,6 +39,7 @@ const (
 	SignCredentialsPath        = VerifiableOperationID + "/signcredential"
 	DeriveCredentialPath       = VerifiableOperationID + "/derivecredential"
 	RemoveCredentialByNamePath = verifiableCredentialPath + "/remove/name" + "/{name}"
+	VerifyCredentialPath       = verifiableCredentialPath + "/verify"
 
 	// presentation paths.
 	GeneratePresentationPath     = verifiablePresentationPath + "/generate"
@@ -47,6 +48,7 @@ const (
 	GetPresentationPath          = verifiablePresentationPath + "/{id}"
 	GetPresentationsPath         = VerifiableOperationID + "/presentations"
 	RemovePresentationByNamePath = verifiablePresentationPath + "/remove/name" + "/{name}"
+	VerifyPresentationPath       = verifiablePresentationPath + "/verify"
 )
 
 // provider contains dependencies for the verifiable command and is typically created by using aries.Context().
@@ -98,6 +100,8 @@ func (o *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(GetPresentationsPath, http.MethodGet, o.GetPresentations),
 		cmdutil.NewHTTPHandler(RemoveCredentialByNamePath, http.MethodPost, o.RemoveCredentialByName),
 		cmdutil.NewHTTPHandler(RemovePresentationByNamePath, http.MethodPost, o.RemovePresentationByName),
+		cmdutil.NewHTTPHandler(VerifyCredentialPath, http.MethodPost, o.VerifyCredential),
+		cmdutil.NewHTTPHandler(VerifyPresentationPath, http.MethodPost, o.VerifyPresentation),
 	}
 }
 
@@ -224,6 +228,28 @@ func (o *Operation) DeriveCredential(rw http.ResponseWriter, req *http.Request)
 	rest.Execute(o.command.DeriveCredential, rw, req.Body)
 }
 
+// VerifyCredential swagger:route POST /verifiable/credential/verify verifiable verifyCredentialReq
+//
+// Verifies the verifiable credential and returns the result of each verification check.
+//
+// Responses:
+//    default: genericError
+//        200: verificationReportRes
+func (o *Operation) VerifyCredential(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.VerifyCredential, rw, req.Body)
+}
+
+// VerifyPresentation swagger:route POST /verifiable/presentation/verify verifiable verifyPresentationReq
+//
+// Verifies the verifiable presentation and its credentials and returns the result of each verification check.
+//
+// Responses:
+//    default: genericError
+//        200: verificationReportRes
+func (o *Operation) VerifyPresentation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.VerifyPresentation, rw, req.Body)
+}
+
 // GetPresentations swagger:route GET /verifiable/presentations verifiable
 //
 // Retrieves the verifiable credentials.
@@ -277,7 +277,7 @@ func TestNew(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
-		require.Equal(t, 14, len(cmd.GetRESTHandlers()))
+		require.Equal(t, 16, len(cmd.GetRESTHandlers()))
 	})
 
 	t.Run("test new command - error", func(t *testing.T) {
@@ -1466,3 +1466,71 @@ func (s *bbsSigner) textToLines(txt string) [][]byte {
 
 	return linesBytes
 }
+
+func TestVerifyVC(t *testing.T) {
+	cmd, err := New(&mockprovider.Provider{
+		StorageProviderValue: mockstore.NewMockStoreProvider(),
+		VDRegistryValue:      &mockvdr.MockVDRegistry{},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("test verify vc - success", func(t *testing.T) {
+		jsonStr, err := json.Marshal(verifiable.Credential{VerifiableCredential: vc})
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, cmd, VerifyCredentialPath, http.MethodPost)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBuffer(jsonStr), handler.Path())
+		require.NoError(t, err)
+
+		response := verificationReportRes{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotNil(t, response.Report)
+		require.NotEmpty(t, response.Report.Checks)
+	})
+
+	t.Run("test verify vc - error", func(t *testing.T) {
+		handler := lookupHandler(t, cmd, VerifyCredentialPath, http.MethodPost)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{}`), handler.Path())
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, verifiable.VerifyCredentialErrorCode, "credential is mandatory", buf.Bytes())
+	})
+}
+
+func TestVerifyVP(t *testing.T) {
+	cmd, err := New(&mockprovider.Provider{
+		StorageProviderValue: mockstore.NewMockStoreProvider(),
+		VDRegistryValue:      &mockvdr.MockVDRegistry{},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	t.Run("test verify vp - success", func(t *testing.T) {
+		jsonStr, err := json.Marshal(verifiable.Presentation{VerifiablePresentation: []byte(udVerifiablePresentation)})
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, cmd, VerifyPresentationPath, http.MethodPost)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBuffer(jsonStr), handler.Path())
+		require.NoError(t, err)
+
+		response := verificationReportRes{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotNil(t, response.Report)
+		require.NotEmpty(t, response.Report.Checks)
+	})
+
+	t.Run("test verify vp - error", func(t *testing.T) {
+		handler := lookupHandler(t, cmd, VerifyPresentationPath, http.MethodPost)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{}`), handler.Path())
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, verifiable.VerifyPresentationErrorCode, "presentation is mandatory", buf.Bytes())
+	})
+}
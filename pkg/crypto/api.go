@@ -82,6 +82,31 @@ type Crypto interface {
 	// 		signature proof in []byte
 	//		error in case of errors
 	DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int, kh interface{}) ([]byte, error)
+	// DeriveSharedSecret derives a secret shared with the owner of theirPubKey using ECDH key agreement with the
+	// private key in kh followed by HKDF-SHA256 of the ECDH shared secret, without exporting the private key.
+	// 'opts' allows setting the HKDF salt and info using WithSalt() and WithInfo() options and the size of the derived
+	// secret using WithKeySize() option.
+	// returns:
+	// 		derived secret in []byte
+	//		error in case of errors
+	DeriveSharedSecret(theirPubKey *PublicKey, kh interface{}, opts ...KeyAgreementOpts) ([]byte, error)
+	// Seal encrypts plaintext with aad for the recipient public key 'recPubKey' using HPKE single-shot encryption
+	// (https://www.rfc-editor.org/rfc/rfc9180) with X25519 or P-256 keys.
+	// 'opts' allows setting the HPKE info with WithInfo(), a pre-shared key with WithPSK() (psk mode), a sender key
+	// handle with WithAuthSender() (auth mode) and the AEAD with WithAEAD(). The absence of these options uses the base
+	// mode with AES-256-GCM.
+	// returns:
+	// 		encapsulated key in []byte, to be sent to the recipient along with the ciphertext
+	// 		ciphertext in []byte
+	//		error in case of errors
+	Seal(plaintext, aad []byte, recPubKey *PublicKey, opts ...KeyAgreementOpts) ([]byte, []byte, error)
+	// Open decrypts ciphertext with aad and the encapsulated key enc using HPKE single-shot decryption with the
+	// recipient private key in kh. The options used by Seal() must be set here as well, except WithAuthSender() which
+	// must be set with the sender public key.
+	// returns:
+	// 		plaintext in []byte
+	//		error in case of errors
+	Open(enc, ciphertext, aad []byte, kh interface{}, opts ...KeyAgreementOpts) ([]byte, error)
 }
 
 // DefKeySize is the default key size for crypto primitives.
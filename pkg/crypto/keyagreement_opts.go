@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+)
+
+type keyAgreementOpts struct {
+	salt      []byte
+	info      []byte
+	keySize   int
+	psk       []byte
+	pskID     []byte
+	senderKey interface{}
+	aead      hpke.AEADID
+}
+
+// NewKeyAgreementOpt creates a new key agreement option set to default values: a DefKeySize derived secret and the
+// AES-256-GCM HPKE AEAD.
+// Not to be used directly. It's intended for implementations of Crypto interface.
+// Use the key agreement option functions below instead.
+func NewKeyAgreementOpt() *keyAgreementOpts { // nolint // unexported type doesn't need to be used outside of package
+	return &keyAgreementOpts{
+		keySize: DefKeySize,
+		aead:    hpke.AES256GCM,
+	}
+}
+
+// Salt gets the HKDF salt used by DeriveSharedSecret().
+func (ko *keyAgreementOpts) Salt() []byte {
+	return ko.salt
+}
+
+// Info gets the HKDF info used by DeriveSharedSecret() or the HPKE info used by Seal() and Open().
+func (ko *keyAgreementOpts) Info() []byte {
+	return ko.info
+}
+
+// KeySize gets the size of the secret derived by DeriveSharedSecret().
+func (ko *keyAgreementOpts) KeySize() int {
+	return ko.keySize
+}
+
+// PSK gets the HPKE pre-shared key and its identifier used by Seal() and Open() (psk and auth_psk modes).
+func (ko *keyAgreementOpts) PSK() ([]byte, []byte) {
+	return ko.psk, ko.pskID
+}
+
+// SenderKey gets the HPKE sender key used by Seal() and Open() (auth and auth_psk modes).
+func (ko *keyAgreementOpts) SenderKey() interface{} {
+	return ko.senderKey
+}
+
+// AEAD gets the HPKE AEAD used by Seal() and Open().
+func (ko *keyAgreementOpts) AEAD() hpke.AEADID {
+	return ko.aead
+}
+
+// KeyAgreementOpts are the crypto.DeriveSharedSecret(), crypto.Seal() and crypto.Open() options.
+type KeyAgreementOpts func(opts *keyAgreementOpts)
+
+// WithSalt option sets the HKDF salt of the secret derived by DeriveSharedSecret(). The absence of this option uses
+// no salt.
+func WithSalt(salt []byte) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.salt = salt
+	}
+}
+
+// WithInfo option sets the application specific information binding the derived secret to its context: the HKDF info
+// for DeriveSharedSecret() or the HPKE info for Seal() and Open(). The same info must be used by both parties.
+func WithInfo(info []byte) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.info = info
+	}
+}
+
+// WithKeySize option sets the size of the secret derived by DeriveSharedSecret(). The absence of this option derives a
+// DefKeySize secret.
+func WithKeySize(size int) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.keySize = size
+	}
+}
+
+// WithPSK option sets a pre-shared key and its identifier for Seal() and Open() using the HPKE psk mode (or auth_psk
+// mode along with WithAuthSender()).
+func WithPSK(psk, pskID []byte) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.psk = psk
+		opts.pskID = pskID
+	}
+}
+
+// WithAuthSender option sets a sender key for Seal() and Open() using the HPKE auth mode (or auth_psk mode along with
+// WithPSK()) authenticating the sender.
+// senderKey can be of the following types:
+//   - a private key handle of the sender (required for crypto.Seal())
+//   - *crypto.PublicKey of the sender (available for crypto.Open() only)
+//   - a public key handle of the sender (available for crypto.Open() only)
+func WithAuthSender(senderKey interface{}) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.senderKey = senderKey
+	}
+}
+
+// WithAEAD option sets the HPKE AEAD used by Seal() and Open(). The absence of this option uses AES-256-GCM.
+func WithAEAD(aead hpke.AEADID) KeyAgreementOpts {
+	return func(opts *keyAgreementOpts) {
+		opts.aead = aead
+	}
+}
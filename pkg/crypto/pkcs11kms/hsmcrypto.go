@@ -0,0 +1,330 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"errors"
+	"fmt"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// package pkcs11kms provides the Crypto implementation matching the PKCS#11 KMS found in pkg/kms/pkcs11kms. Signing
+// and ECDH key agreement with the token's private keys are executed by the token. Operations on Tink key handles (eg
+// public key handles created by the KMS's PubKeyBytesToHandle() or the framework's symmetric keys) are delegated to
+// tinkcrypto.
+
+// hsmKey is a key pair stored in a PKCS#11 token, it is implemented by pkg/kms/pkcs11kms.KeyHandle.
+type hsmKey interface {
+	KeyType() kms.KeyType
+	PublicKey() interface{}
+	Sign(msg []byte) ([]byte, error)
+	Verify(signature, msg []byte) error
+	DeriveSharedSecret(peerPubKey interface{}) ([]byte, error)
+}
+
+// HSMCrypto implements cryptoapi.Crypto for keys stored in a PKCS#11 token.
+type HSMCrypto struct {
+	tink *tinkcrypto.Crypto
+}
+
+// New creates a new HSMCrypto instance.
+func New() (*HSMCrypto, error) {
+	tc, err := tinkcrypto.New()
+	if err != nil {
+		return nil, fmt.Errorf("new: failed to create tink crypto: %w", err)
+	}
+
+	return &HSMCrypto{tink: tc}, nil
+}
+
+// Encrypt will encrypt msg using the Tink AEAD key handle kh, the PKCS#11 KMS does not manage symmetric keys.
+func (c *HSMCrypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
+	return c.tink.Encrypt(msg, aad, kh)
+}
+
+// Decrypt will decrypt cipher using the Tink AEAD key handle kh, the PKCS#11 KMS does not manage symmetric keys.
+func (c *HSMCrypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	return c.tink.Decrypt(cipher, aad, nonce, kh)
+}
+
+// Sign will sign msg using the private key referenced by kh in the token or a Tink private key handle.
+// returns:
+// 		signature in []byte
+//		error in case of errors
+func (c *HSMCrypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	if key, ok := resolved.(hsmKey); ok {
+		return key.Sign(msg)
+	}
+
+	return c.tink.Sign(msg, resolved)
+}
+
+// Verify will verify a signature for the given msg using the public key of kh, a key handle of the token or a Tink
+// public key handle.
+// returns:
+// 		error in case of errors or nil if signature verification was successful
+func (c *HSMCrypto) Verify(signature, msg []byte, kh interface{}) error {
+	resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if key, ok := resolved.(hsmKey); ok {
+		return key.Verify(signature, msg)
+	}
+
+	return c.tink.Verify(signature, msg, resolved)
+}
+
+// ComputeMAC computes message authentication code (MAC) for code data using the Tink MAC key handle kh.
+func (c *HSMCrypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	return c.tink.ComputeMAC(data, kh)
+}
+
+// VerifyMAC determines if mac is a correct authentication code (MAC) for data using the Tink MAC key handle kh.
+func (c *HSMCrypto) VerifyMAC(mac, data []byte, kh interface{}) error {
+	return c.tink.VerifyMAC(mac, data, kh)
+}
+
+// WrapKey will execute key wrapping of cek using apu, apv and recipient public key 'recPubKey'.
+// 'opts' allows setting the option sender key handle using WithSender() option. When the sender key is a key of the
+// token, its ECDH key agreement with the recipient key is computed by the token for ECDH-1PU key wrapping (aka
+// Authcrypt). ECDH-ES key wrapping (aka Anoncrypt) only uses ephemeral keys and is executed by tinkcrypto.
+// returns:
+// 		RecipientWrappedKey containing the wrapped cek value
+// 		error in case of errors
+func (c *HSMCrypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	pOpts := cryptoapi.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	if pOpts.SenderKey() != nil {
+		senderKH, err := kms.ResolveKeyHandle(pOpts.SenderKey(), kms.KeyUsageKeyAgreement)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: %w", err)
+		}
+
+		if senderKey, ok := senderKH.(hsmKey); ok {
+			if recPubKey == nil {
+				return nil, fmt.Errorf("wrapKey: recipient public key is required")
+			}
+
+			return wrap1PU(cek, apu, apv, senderKey, recPubKey, pOpts.UseXC20PKW())
+		}
+	}
+
+	return c.tink.WrapKey(cek, apu, apv, recPubKey, opts...)
+}
+
+// UnwrapKey unwraps a key in recWK using the recipient private key kh, a key handle of the token or a Tink private
+// key handle. The ECDH key agreements of the token's key are computed by the token.
+// 'opts' allows setting the option sender key handle using WithSender() option. It allows ECDH-1PU key unwrapping
+// (aka Authcrypt). The absence of this option uses ECDH-ES key unwrapping (aka Anoncrypt).
+// returns:
+// 		unwrapped key in raw bytes
+// 		error in case of errors
+func (c *HSMCrypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, kh interface{},
+	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	recKey, ok := resolved.(hsmKey)
+	if !ok {
+		return c.tink.UnwrapKey(recWK, resolved, opts...)
+	}
+
+	if recWK == nil {
+		return nil, fmt.Errorf("unwrapKey: RecipientWrappedKey is empty")
+	}
+
+	pOpts := cryptoapi.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	return unwrap(recWK, recKey, pOpts.SenderKey())
+}
+
+// SignMulti will create a BBS+ signature of messages using the Tink key handle kh.
+func (c *HSMCrypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	return c.tink.SignMulti(messages, kh)
+}
+
+// VerifyMulti will verify a BBS+ signature of messages using the Tink public key handle kh.
+func (c *HSMCrypto) VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error {
+	return c.tink.VerifyMulti(messages, signature, kh)
+}
+
+// VerifyProof will verify a BBS+ signature proof for revealedMessages using the Tink public key handle kh.
+func (c *HSMCrypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	return c.tink.VerifyProof(revealedMessages, proof, nonce, kh)
+}
+
+// DeriveProof will create a BBS+ signature proof for a list of revealed messages using the Tink public key handle kh.
+func (c *HSMCrypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	return c.tink.DeriveProof(messages, bbsSignature, nonce, revealedIndexes, kh)
+}
+
+// DeriveSharedSecret derives a secret shared with the owner of theirPubKey using ECDH key agreement with the private
+// key kh, a key handle of the token or a Tink private key handle, followed by HKDF-SHA256. The ECDH key agreement of
+// the token's key is computed by the token.
+// 'opts' allows setting the HKDF salt and info using WithSalt() and WithInfo() options and the size of the derived
+// secret using WithKeySize() option.
+// returns:
+// 		derived secret in []byte
+//		error in case of errors
+func (c *HSMCrypto) DeriveSharedSecret(theirPubKey *cryptoapi.PublicKey, kh interface{},
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	key, ok := resolved.(hsmKey)
+	if !ok {
+		return c.tink.DeriveSharedSecret(theirPubKey, resolved, opts...)
+	}
+
+	if theirPubKey == nil {
+		return nil, errors.New("deriveSharedSecret: public key is required")
+	}
+
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	secret, err := deriveSharedSecret(key, theirPubKey, pOpts.Salt(), pOpts.Info(), pOpts.KeySize())
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// Seal encrypts plaintext with aad for the recipient public key recPubKey using HPKE single-shot encryption.
+// 'opts' allows setting the sender key handle using WithAuthSender() option. When the sender key is a key of the
+// token, its ECDH key agreement with the recipient key is computed by the token for the HPKE auth mode. Other modes
+// only use ephemeral keys and are executed by tinkcrypto.
+// returns:
+// 		encapsulated key in []byte
+// 		ciphertext in []byte
+//		error in case of errors
+func (c *HSMCrypto) Seal(plaintext, aad []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, []byte, error) {
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	if pOpts.SenderKey() == nil {
+		return c.tink.Seal(plaintext, aad, recPubKey, opts...)
+	}
+
+	senderKH, err := kms.ResolveKeyHandle(pOpts.SenderKey(), kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: %w", err)
+	}
+
+	key, ok := senderKH.(hsmKey)
+	if !ok {
+		return c.tink.Seal(plaintext, aad, recPubKey, opts...)
+	}
+
+	if recPubKey == nil {
+		return nil, nil, errors.New("seal: recipient public key is required")
+	}
+
+	senderKey, err := newHPKEKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: sender key: %w", err)
+	}
+
+	pkR, err := hpkePublicKey(recPubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: %w", err)
+	}
+
+	params := &hpke.Params{Info: pOpts.Info(), SenderKey: senderKey}
+	params.PSK, params.PSKID = pOpts.PSK()
+
+	suite := hpke.Suite{KEM: senderKey.kem, KDF: hpke.HKDFSHA256, AEAD: pOpts.AEAD()}
+
+	enc, ct, err := hpke.Seal(suite, pkR, plaintext, aad, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: %w", err)
+	}
+
+	return enc, ct, nil
+}
+
+// Open decrypts ciphertext with aad and the encapsulated key enc using HPKE single-shot decryption with the recipient
+// private key kh, a key handle of the token or a Tink private key handle. The ECDH key agreements of the token's key
+// are computed by the token.
+// returns:
+// 		plaintext in []byte
+//		error in case of errors
+func (c *HSMCrypto) Open(enc, ciphertext, aad []byte, kh interface{},
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	key, ok := resolved.(hsmKey)
+	if !ok {
+		return c.tink.Open(enc, ciphertext, aad, resolved, opts...)
+	}
+
+	recKey, err := newHPKEKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	params := &hpke.Params{Info: pOpts.Info()}
+	params.PSK, params.PSKID = pOpts.PSK()
+
+	if pOpts.SenderKey() != nil {
+		params.SenderPublicKey, err = hpkePublicKey(pOpts.SenderKey())
+		if err != nil {
+			return nil, fmt.Errorf("open: sender key: %w", err)
+		}
+	}
+
+	suite := hpke.Suite{KEM: recKey.kem, KDF: hpke.HKDFSHA256, AEAD: pOpts.AEAD()}
+
+	pt, err := hpke.Open(suite, enc, recKey, ciphertext, aad, params)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	return pt, nil
+}
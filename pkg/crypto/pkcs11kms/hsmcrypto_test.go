@@ -0,0 +1,544 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
+	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+func TestHSMCrypto_Sign_Verify(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum")
+
+	t.Run("token key", func(t *testing.T) {
+		key := newSoftKey(t, kms.ED25519Type)
+
+		sig, err := c.Sign(msg, key)
+		require.NoError(t, err)
+		require.NoError(t, c.Verify(sig, msg, key))
+		require.Error(t, c.Verify(sig, []byte("other message"), key))
+	})
+
+	t.Run("tink key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ECDSAP256KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		require.NoError(t, c.Verify(sig, msg, pubKH))
+	})
+
+	t.Run("usage constrained key", func(t *testing.T) {
+		key := &kms.UsageConstrainedHandle{Handle: newSoftKey(t, kms.ED25519Type), Usage: kms.KeyUsageSign}
+
+		sig, err := c.Sign(msg, key)
+		require.NoError(t, err)
+		require.NoError(t, c.Verify(sig, msg, key))
+
+		key.Usage = kms.KeyUsageKeyAgreement
+
+		_, err = c.Sign(msg, key)
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+
+		err = c.Verify(sig, msg, key)
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+	})
+}
+
+func TestHSMCrypto_Wrap_Unwrap_Key(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  kms.KeyType
+		keyTempl *tinkpb.KeyTemplate
+		useXC20P bool
+	}{
+		{name: "NIST P-256 key and A256KW", keyType: kms.NISTP256ECDHKWType, keyTempl: ecdh.NISTP256ECDHKWKeyTemplate()},
+		{name: "NIST P-384 key and A256KW", keyType: kms.NISTP384ECDHKWType, keyTempl: ecdh.NISTP384ECDHKWKeyTemplate()},
+		{name: "NIST P-521 key and A256KW", keyType: kms.NISTP521ECDHKWType, keyTempl: ecdh.NISTP521ECDHKWKeyTemplate()},
+		{name: "X25519 key and A256KW", keyType: kms.X25519ECDHKWType, keyTempl: ecdh.X25519ECDHKWKeyTemplate()},
+		{
+			name:     "NIST P-256 key and XC20PKW",
+			keyType:  kms.NISTP256ECDHKWType,
+			keyTempl: ecdh.NISTP256ECDHKWKeyTemplate(),
+			useXC20P: true,
+		},
+		{
+			name:     "X25519 key and XC20PKW",
+			keyType:  kms.X25519ECDHKWType,
+			keyTempl: ecdh.X25519ECDHKWKeyTemplate(),
+			useXC20P: true,
+		},
+	}
+
+	c, err := New()
+	require.NoError(t, err)
+
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	cek := random.GetRandomBytes(uint32(cryptoapi.DefKeySize))
+	apu := random.GetRandomBytes(uint32(10))
+	apv := random.GetRandomBytes(uint32(10))
+
+	for _, tt := range tests {
+		tt := tt
+
+		var opts []cryptoapi.WrapKeyOpts
+		if tt.useXC20P {
+			opts = append(opts, cryptoapi.WithXC20PKW())
+		}
+
+		t.Run("ECDH-1PU from token key to tink key with "+tt.name, func(t *testing.T) {
+			senderKey := newSoftKey(t, tt.keyType)
+
+			recKH, err := keyset.NewHandle(tt.keyTempl)
+			require.NoError(t, err)
+
+			recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+			require.NoError(t, err)
+
+			wk, err := c.WrapKey(cek, apu, apv, recPubKey, append(opts, cryptoapi.WithSender(senderKey))...)
+			require.NoError(t, err)
+			require.Equal(t, recPubKey.Type, wk.EPK.Type)
+
+			uCEK, err := tc.UnwrapKey(wk, recKH, cryptoapi.WithSender(senderKey.publicKey(t)))
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+
+			// without apu, the apu is set from the EPK like tinkcrypto.
+			wk, err = c.WrapKey(cek, nil, apv, recPubKey, append(opts, cryptoapi.WithSender(senderKey))...)
+			require.NoError(t, err)
+			require.NotEmpty(t, wk.APU)
+
+			uCEK, err = tc.UnwrapKey(wk, recKH, cryptoapi.WithSender(senderKey.publicKey(t)))
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+		})
+
+		t.Run("ECDH-1PU from tink key to token key with "+tt.name, func(t *testing.T) {
+			recKey := newSoftKey(t, tt.keyType)
+
+			senderKH, err := keyset.NewHandle(tt.keyTempl)
+			require.NoError(t, err)
+
+			wk, err := c.WrapKey(cek, apu, apv, recKey.publicKey(t), append(opts, cryptoapi.WithSender(senderKH))...)
+			require.NoError(t, err)
+
+			senderPubKH, err := senderKH.Public()
+			require.NoError(t, err)
+
+			uCEK, err := c.UnwrapKey(wk, recKey, cryptoapi.WithSender(senderPubKH))
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+
+			_, err = c.UnwrapKey(wk, recKey)
+			require.EqualError(t, err, "unwrapKey: sender's public keyset handle option is required for '"+wk.Alg+"'")
+		})
+
+		t.Run("ECDH-1PU between token keys with "+tt.name, func(t *testing.T) {
+			senderKey := newSoftKey(t, tt.keyType)
+			recKey := newSoftKey(t, tt.keyType)
+
+			wk, err := c.WrapKey(cek, apu, apv, recKey.publicKey(t), append(opts, cryptoapi.WithSender(senderKey))...)
+			require.NoError(t, err)
+
+			uCEK, err := c.UnwrapKey(wk, recKey, cryptoapi.WithSender(senderKey))
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+
+			uCEK, err = c.UnwrapKey(wk, recKey, cryptoapi.WithSender(senderKey.publicKey(t)))
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+		})
+
+		t.Run("ECDH-ES to token key with "+tt.name, func(t *testing.T) {
+			recKey := newSoftKey(t, tt.keyType)
+
+			wk, err := c.WrapKey(cek, apu, apv, recKey.publicKey(t), opts...)
+			require.NoError(t, err)
+
+			uCEK, err := c.UnwrapKey(wk, recKey)
+			require.NoError(t, err)
+			require.Equal(t, cek, uCEK)
+		})
+	}
+
+	t.Run("tink keys", func(t *testing.T) {
+		recKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+		require.NoError(t, err)
+
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+
+		uCEK, err := c.UnwrapKey(wk, recKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, uCEK)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		senderKey := newSoftKey(t, kms.NISTP256ECDHKWType)
+		recKey := newSoftKey(t, kms.NISTP256ECDHKWType)
+
+		_, err := c.WrapKey(cek, apu, apv, nil, cryptoapi.WithSender(senderKey))
+		require.EqualError(t, err, "wrapKey: recipient public key is required")
+
+		_, err = c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{Type: "RSA"}, cryptoapi.WithSender(senderKey))
+		require.EqualError(t, err, "wrapKey: invalid recipient key type for ECDH-1PU")
+
+		_, err = c.WrapKey(cek, apu, apv, newSoftKey(t, kms.X25519ECDHKWType).publicKey(t),
+			cryptoapi.WithSender(senderKey))
+		require.EqualError(t, err, "wrapKey: peer key is not on the curve of the key")
+
+		_, err = c.WrapKey(cek, apu, apv, recKey.publicKey(t),
+			cryptoapi.WithSender(&kms.UsageConstrainedHandle{Handle: senderKey, Usage: kms.KeyUsageSign}))
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+
+		_, err = c.UnwrapKey(nil, recKey)
+		require.EqualError(t, err, "unwrapKey: RecipientWrappedKey is empty")
+
+		_, err = c.UnwrapKey(&cryptoapi.RecipientWrappedKey{}, &kms.UsageConstrainedHandle{
+			Handle: recKey,
+			Usage:  kms.KeyUsageEncrypt,
+		})
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+
+		wk, err := c.WrapKey(cek, apu, apv, recKey.publicKey(t), cryptoapi.WithSender(senderKey))
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(wk, recKey, cryptoapi.WithSender("sender"))
+		require.EqualError(t, err, "unwrapKey: unsupported sender key type string")
+
+		_, err = c.UnwrapKey(wk, newSoftKey(t, kms.NISTP256ECDHKWType), cryptoapi.WithSender(senderKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unwrapKey: failed to AES unwrap key")
+
+		badAlg := *wk
+		badAlg.Alg = "RSA-OAEP"
+
+		_, err = c.UnwrapKey(&badAlg, recKey, cryptoapi.WithSender(senderKey))
+		require.EqualError(t, err, "unwrapKey: unsupported JWE KW Alg 'RSA-OAEP'")
+
+		badEPK := *wk
+		badEPK.EPK.Type = "RSA"
+
+		_, err = c.UnwrapKey(&badEPK, recKey, cryptoapi.WithSender(senderKey))
+		require.EqualError(t, err, "unwrapKey: invalid EPK: invalid key type 'RSA'")
+	})
+}
+
+func TestHSMCrypto_DeriveSharedSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  kms.KeyType
+		keyTempl *tinkpb.KeyTemplate
+	}{
+		{name: "NIST P-256 key", keyType: kms.NISTP256ECDHKWType, keyTempl: ecdh.NISTP256ECDHKWKeyTemplate()},
+		{name: "NIST P-384 key", keyType: kms.NISTP384ECDHKWType, keyTempl: ecdh.NISTP384ECDHKWKeyTemplate()},
+		{name: "X25519 key", keyType: kms.X25519ECDHKWType, keyTempl: ecdh.X25519ECDHKWKeyTemplate()},
+	}
+
+	c, err := New()
+	require.NoError(t, err)
+
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	opts := []cryptoapi.KeyAgreementOpts{cryptoapi.WithSalt([]byte("salt")), cryptoapi.WithInfo([]byte("info"))}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run("ECDH between token key and tink key with "+tt.name, func(t *testing.T) {
+			tokenKey := newSoftKey(t, tt.keyType)
+
+			peerKH, err := keyset.NewHandle(tt.keyTempl)
+			require.NoError(t, err)
+
+			peerPubKey, err := keyio.ExtractPrimaryPublicKey(peerKH)
+			require.NoError(t, err)
+
+			secret, err := c.DeriveSharedSecret(peerPubKey, tokenKey, opts...)
+			require.NoError(t, err)
+			require.Len(t, secret, cryptoapi.DefKeySize)
+
+			peerSecret, err := tc.DeriveSharedSecret(tokenKey.publicKey(t), peerKH, opts...)
+			require.NoError(t, err)
+			require.Equal(t, peerSecret, secret)
+
+			// tink key handles are delegated to tinkcrypto.
+			peerSecret, err = c.DeriveSharedSecret(tokenKey.publicKey(t), peerKH, opts...)
+			require.NoError(t, err)
+			require.Equal(t, peerSecret, secret)
+		})
+	}
+
+	t.Run("failures", func(t *testing.T) {
+		tokenKey := newSoftKey(t, kms.NISTP256ECDHKWType)
+
+		_, err = c.DeriveSharedSecret(nil, tokenKey)
+		require.EqualError(t, err, "deriveSharedSecret: public key is required")
+
+		_, err = c.DeriveSharedSecret(tokenKey.publicKey(t), tokenKey, cryptoapi.WithKeySize(0))
+		require.EqualError(t, err, "deriveSharedSecret: invalid key size 0")
+
+		_, err = c.DeriveSharedSecret(newSoftKey(t, kms.NISTP384ECDHKWType).publicKey(t), tokenKey)
+		require.EqualError(t, err, "deriveSharedSecret: peer key is not on the curve of the key")
+
+		_, err = c.DeriveSharedSecret(tokenKey.publicKey(t), &kms.UsageConstrainedHandle{
+			Handle: tokenKey,
+			Usage:  kms.KeyUsageSign,
+		})
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+	})
+}
+
+func TestHSMCrypto_Seal_Open(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  kms.KeyType
+		keyTempl *tinkpb.KeyTemplate
+	}{
+		{name: "NIST P-256 key", keyType: kms.NISTP256ECDHKWType, keyTempl: ecdh.NISTP256ECDHKWKeyTemplate()},
+		{name: "X25519 key", keyType: kms.X25519ECDHKWType, keyTempl: ecdh.X25519ECDHKWKeyTemplate()},
+	}
+
+	c, err := New()
+	require.NoError(t, err)
+
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum")
+	aad := []byte("aad")
+	psk := cryptoapi.WithPSK([]byte("0123456789abcdef0123456789abcdef"), []byte("psk id"))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run("HPKE from tink key to token key with "+tt.name, func(t *testing.T) {
+			recKey := newSoftKey(t, tt.keyType)
+
+			senderKH, err := keyset.NewHandle(tt.keyTempl)
+			require.NoError(t, err)
+
+			senderPubKey, err := keyio.ExtractPrimaryPublicKey(senderKH)
+			require.NoError(t, err)
+
+			enc, ct, err := c.Seal(msg, aad, recKey.publicKey(t))
+			require.NoError(t, err)
+
+			pt, err := c.Open(enc, ct, aad, recKey)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			enc, ct, err = tc.Seal(msg, aad, recKey.publicKey(t), cryptoapi.WithAuthSender(senderKH), psk)
+			require.NoError(t, err)
+
+			pt, err = c.Open(enc, ct, aad, recKey, cryptoapi.WithAuthSender(senderPubKey), psk)
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			_, err = c.Open(enc, ct, aad, recKey)
+			require.Error(t, err)
+		})
+
+		t.Run("HPKE auth mode from token key to tink key with "+tt.name, func(t *testing.T) {
+			senderKey := newSoftKey(t, tt.keyType)
+
+			recKH, err := keyset.NewHandle(tt.keyTempl)
+			require.NoError(t, err)
+
+			recPubKey, err := keyio.ExtractPrimaryPublicKey(recKH)
+			require.NoError(t, err)
+
+			enc, ct, err := c.Seal(msg, aad, recPubKey, cryptoapi.WithAuthSender(senderKey))
+			require.NoError(t, err)
+
+			pt, err := tc.Open(enc, ct, aad, recKH, cryptoapi.WithAuthSender(senderKey.publicKey(t)))
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+
+			// tink key handles are delegated to tinkcrypto.
+			pt, err = c.Open(enc, ct, aad, recKH, cryptoapi.WithAuthSender(senderKey.publicKey(t)))
+			require.NoError(t, err)
+			require.Equal(t, msg, pt)
+		})
+	}
+
+	t.Run("failures", func(t *testing.T) {
+		p256Key := newSoftKey(t, kms.NISTP256ECDHKWType)
+		p384Key := newSoftKey(t, kms.NISTP384ECDHKWType)
+
+		_, _, err = c.Seal(msg, aad, nil, cryptoapi.WithAuthSender(p256Key))
+		require.EqualError(t, err, "seal: recipient public key is required")
+
+		_, _, err = c.Seal(msg, aad, p256Key.publicKey(t), cryptoapi.WithAuthSender(p384Key))
+		require.EqualError(t, err, "seal: sender key: unsupported HPKE key: not a P-256 key")
+
+		_, _, err = c.Seal(msg, aad, p256Key.publicKey(t), cryptoapi.WithAuthSender(newSoftKey(t, kms.ED25519Type)))
+		require.EqualError(t, err, "seal: sender key: unsupported HPKE key type 'ED25519'")
+
+		enc, ct, err := c.Seal(msg, aad, p256Key.publicKey(t))
+		require.NoError(t, err)
+
+		_, err = c.Open(enc, ct, aad, p384Key)
+		require.EqualError(t, err, "open: unsupported HPKE key: not a P-256 key")
+
+		_, err = c.Open(enc, ct, aad, p256Key, cryptoapi.WithAuthSender("bad sender key"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open: sender key:")
+
+		_, err = c.Open(enc, ct, aad, &kms.UsageConstrainedHandle{Handle: p256Key, Usage: kms.KeyUsageEncrypt})
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+	})
+}
+
+// softKey is an in-memory hsmKey used in place of a PKCS#11 token key.
+type softKey struct {
+	keyType kms.KeyType
+	privKey interface{}
+	pubKey  interface{}
+}
+
+func newSoftKey(t *testing.T, kt kms.KeyType) *softKey {
+	t.Helper()
+
+	var curve elliptic.Curve
+
+	switch kt {
+	case kms.ED25519Type:
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		return &softKey{keyType: kt, privKey: privKey, pubKey: pubKey}
+	case kms.X25519ECDHKWType:
+		privKey := random.GetRandomBytes(curve25519.ScalarSize)
+
+		pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+		require.NoError(t, err)
+
+		return &softKey{keyType: kt, privKey: privKey, pubKey: pubKey}
+	case kms.NISTP384ECDHKWType:
+		curve = elliptic.P384()
+	case kms.NISTP521ECDHKWType:
+		curve = elliptic.P521()
+	default:
+		curve = elliptic.P256()
+	}
+
+	privKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	return &softKey{keyType: kt, privKey: privKey, pubKey: &privKey.PublicKey}
+}
+
+func (k *softKey) KeyType() kms.KeyType {
+	return k.keyType
+}
+
+func (k *softKey) PublicKey() interface{} {
+	return k.pubKey
+}
+
+func (k *softKey) Sign(msg []byte) ([]byte, error) {
+	return ed25519.Sign(k.privKey.(ed25519.PrivateKey), msg), nil
+}
+
+func (k *softKey) Verify(signature, msg []byte) error {
+	if !ed25519.Verify(k.pubKey.(ed25519.PublicKey), msg, signature) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+func (k *softKey) DeriveSharedSecret(peerPubKey interface{}) ([]byte, error) {
+	switch privKey := k.privKey.(type) {
+	case *ecdsa.PrivateKey:
+		pk, ok := peerPubKey.(*ecdsa.PublicKey)
+		if !ok || pk.Curve != privKey.Curve {
+			return nil, errors.New("peer key is not on the curve of the key")
+		}
+
+		x, _ := privKey.Curve.ScalarMult(pk.X, pk.Y, privKey.D.Bytes())
+		z := make([]byte, (privKey.Curve.Params().BitSize+7)/8)
+
+		return x.FillBytes(z), nil
+	case []byte:
+		pk, ok := peerPubKey.([]byte)
+		if !ok {
+			return nil, errors.New("peer key is not an X25519 key")
+		}
+
+		return curve25519.X25519(privKey, pk)
+	default:
+		return nil, errors.New("not a key agreement key")
+	}
+}
+
+// publicKey returns the public key of k as exported by the KMS.
+func (k *softKey) publicKey(t *testing.T) *cryptoapi.PublicKey {
+	t.Helper()
+
+	switch pubKey := k.pubKey.(type) {
+	case *ecdsa.PublicKey:
+		curve := commonpb.EllipticCurveType_NIST_P256
+
+		switch pubKey.Curve {
+		case elliptic.P384():
+			curve = commonpb.EllipticCurveType_NIST_P384
+		case elliptic.P521():
+			curve = commonpb.EllipticCurveType_NIST_P521
+		}
+
+		return &cryptoapi.PublicKey{
+			X:     pubKey.X.Bytes(),
+			Y:     pubKey.Y.Bytes(),
+			Curve: curve.String(),
+			Type:  ecdhpb.KeyType_EC.String(),
+		}
+	case []byte:
+		return &cryptoapi.PublicKey{
+			X:     pubKey,
+			Curve: commonpb.EllipticCurveType_CURVE25519.String(),
+			Type:  ecdhpb.KeyType_OKP.String(),
+		}
+	default:
+		require.Failf(t, "not a key agreement key", "%T", k.pubKey)
+
+		return nil
+	}
+}
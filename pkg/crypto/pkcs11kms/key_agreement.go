@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// hpkeKey is the HPKE private key of a key of the token, its ECDH key agreements are computed by the token.
+type hpkeKey struct {
+	key hsmKey
+	kem hpke.KEMID
+	pub []byte
+}
+
+func newHPKEKey(key hsmKey) (*hpkeKey, error) {
+	switch pub := key.PublicKey().(type) {
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, errors.New("unsupported HPKE key: not a P-256 key")
+		}
+
+		return &hpkeKey{key: key, kem: hpke.DHKEMP256HKDFSHA256, pub: elliptic.Marshal(pub.Curve, pub.X, pub.Y)}, nil
+	case []byte:
+		return &hpkeKey{key: key, kem: hpke.DHKEMX25519HKDFSHA256, pub: pub}, nil
+	default:
+		return nil, fmt.Errorf("unsupported HPKE key type '%s'", key.KeyType())
+	}
+}
+
+func (k *hpkeKey) PublicKey() []byte {
+	return k.pub
+}
+
+func (k *hpkeKey) DH(pk []byte) ([]byte, error) {
+	if k.kem == hpke.DHKEMX25519HKDFSHA256 {
+		return k.key.DeriveSharedSecret(pk)
+	}
+
+	x, y := elliptic.Unmarshal(elliptic.P256(), pk)
+	if x == nil {
+		return nil, errors.New("invalid P-256 public key")
+	}
+
+	return k.key.DeriveSharedSecret(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y})
+}
+
+// deriveSharedSecret derives a secret from the ECDH shared secret of key and pubKey computed by the token.
+func deriveSharedSecret(key hsmKey, pubKey *cryptoapi.PublicKey, salt, info []byte, size int) ([]byte, error) {
+	if size <= 0 || size > 255*sha256.Size {
+		return nil, fmt.Errorf("invalid key size %d", size)
+	}
+
+	peerKey, err := toPeerKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := key.DeriveSharedSecret(peerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, size)
+
+	_, err = io.ReadFull(hkdf.New(sha256.New, z, salt, info), secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// hpkePublicKey returns the serialized HPKE public key of pubKey, a *crypto.PublicKey or a key handle.
+func hpkePublicKey(pubKey interface{}) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(pubKey, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, err
+	}
+
+	peerKey, err := senderPublicKey(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pk := peerKey.(type) {
+	case *ecdsa.PublicKey:
+		return elliptic.Marshal(pk.Curve, pk.X, pk.Y), nil
+	case []byte:
+		return pk, nil
+	default:
+		return nil, fmt.Errorf("unsupported HPKE key type %T", peerKey)
+	}
+}
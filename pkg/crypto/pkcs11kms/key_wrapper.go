@@ -0,0 +1,307 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
+	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+const x25519Curve = "X25519"
+
+// wrap1PU wraps cek with a kek derived from an ephemeral key and the token's senderKey (ECDH-1PU). The result is
+// compatible with tinkcrypto's UnwrapKey().
+func wrap1PU(cek, apu, apv []byte, senderKey hsmKey, recPubKey *cryptoapi.PublicKey,
+	useXC20PKW bool) (*cryptoapi.RecipientWrappedKey, error) {
+	alg := tinkcrypto.ECDH1PUA256KWAlg
+
+	if useXC20PKW {
+		alg = tinkcrypto.ECDH1PUXC20PKWAlg
+	}
+
+	var (
+		ze, zs []byte
+		epk    *cryptoapi.PublicKey
+	)
+
+	switch recPubKey.Type {
+	case ecdhpb.KeyType_EC.String():
+		curve, err := hybrid.GetCurve(recPubKey.Curve)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: failed to get curve of recipient key: %w", err)
+		}
+
+		recKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(recPubKey.X),
+			Y:     new(big.Int).SetBytes(recPubKey.Y),
+		}
+
+		ephemeralPrivKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: failed to generate EPK: %w", err)
+		}
+
+		epk = &cryptoapi.PublicKey{
+			X:     ephemeralPrivKey.PublicKey.X.Bytes(),
+			Y:     ephemeralPrivKey.PublicKey.Y.Bytes(),
+			Curve: curve.Params().Name,
+			Type:  recPubKey.Type,
+		}
+
+		apu = defaultAPU(apu, epk.X)
+		ze = josecipher.DeriveECDHES(alg, apu, apv, ephemeralPrivKey, recKey, cryptoapi.DefKeySize)
+
+		zs, err = deriveKEK(alg, apu, apv, senderKey, recKey)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: %w", err)
+		}
+	case ecdhpb.KeyType_OKP.String():
+		ephemeralPrivKey := new([chacha20poly1305.KeySize]byte)
+
+		_, err := rand.Read(ephemeralPrivKey[:])
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: failed to generate EPK: %w", err)
+		}
+
+		ephemeralPubKey, err := curve25519.X25519(ephemeralPrivKey[:], curve25519.Basepoint)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: failed to generate EPK: %w", err)
+		}
+
+		epk = &cryptoapi.PublicKey{
+			X:     ephemeralPubKey,
+			Curve: x25519Curve,
+			Type:  recPubKey.Type,
+		}
+
+		recKey := new([chacha20poly1305.KeySize]byte)
+		copy(recKey[:], recPubKey.X)
+
+		apu = defaultAPU(apu, epk.X)
+
+		ze, err = cryptoutil.Derive25519KEK([]byte(alg), apu, apv, ephemeralPrivKey, recKey)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: failed to derive 25519 kek: %w", err)
+		}
+
+		zs, err = deriveKEK(alg, apu, apv, senderKey, recPubKey.X)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: %w", err)
+		}
+	default:
+		return nil, errors.New("wrapKey: invalid recipient key type for ECDH-1PU")
+	}
+
+	wk, err := wrapRaw(alg, derive1PU(alg, ze, zs, apu, apv), cek)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	return &cryptoapi.RecipientWrappedKey{
+		KID:          recPubKey.KID,
+		EncryptedCEK: wk,
+		EPK:          *epk,
+		APU:          apu,
+		APV:          apv,
+		Alg:          alg,
+	}, nil
+}
+
+// unwrap derives the kek of recWK with the token's recKey and unwraps the cek. senderKH is the sender's public key
+// required for ECDH-1PU.
+func unwrap(recWK *cryptoapi.RecipientWrappedKey, recKey hsmKey, senderKH interface{}) ([]byte, error) {
+	epk, err := toPeerKey(&recWK.EPK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: invalid EPK: %w", err)
+	}
+
+	kek, err := deriveKEK(recWK.Alg, recWK.APU, recWK.APV, recKey, epk)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	switch recWK.Alg {
+	case tinkcrypto.ECDH1PUA256KWAlg, tinkcrypto.ECDH1PUXC20PKWAlg:
+		if senderKH == nil {
+			return nil, fmt.Errorf("unwrapKey: sender's public keyset handle option is required for '%s'",
+				recWK.Alg)
+		}
+
+		senderPubKey, err := senderPublicKey(senderKH)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapKey: %w", err)
+		}
+
+		zs, err := deriveKEK(recWK.Alg, recWK.APU, recWK.APV, recKey, senderPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapKey: %w", err)
+		}
+
+		kek = derive1PU(recWK.Alg, kek, zs, recWK.APU, recWK.APV)
+	case tinkcrypto.ECDHESA256KWAlg, tinkcrypto.ECDHESXC20PKWAlg:
+	default:
+		return nil, fmt.Errorf("unwrapKey: unsupported JWE KW Alg '%s'", recWK.Alg)
+	}
+
+	cek, err := unwrapRaw(recWK.Alg, kek, recWK.EncryptedCEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	return cek, nil
+}
+
+// deriveKEK derives a kek from the ECDH shared secret of the token's key and peerPubKey computed by the token.
+func deriveKEK(alg string, apu, apv []byte, key hsmKey, peerPubKey interface{}) ([]byte, error) {
+	z, err := key.DeriveSharedSecret(peerPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cryptoutil.DeriveKEK([]byte(alg), apu, apv, z, cryptoapi.DefKeySize), nil
+}
+
+// derive1PU derives the ECDH-1PU kek from the ephemeral (ze) and static (zs) keks.
+func derive1PU(alg string, ze, zs, apu, apv []byte) []byte {
+	round1 := make([]byte, 4)
+	binary.BigEndian.PutUint32(round1, uint32(1))
+
+	// 1PU requires round one number (0001) to be prefixed to the Z concatenation
+	z := append(round1, ze...)
+	z = append(z, zs...)
+
+	return cryptoutil.DeriveKEK([]byte(alg), apu, apv, z, cryptoapi.DefKeySize)
+}
+
+func defaultAPU(apu, ephemeralX []byte) []byte {
+	if len(apu) > 0 {
+		return apu
+	}
+
+	apu = make([]byte, base64.RawURLEncoding.EncodedLen(len(ephemeralX)))
+	base64.RawURLEncoding.Encode(apu, ephemeralX)
+
+	return apu
+}
+
+// toPeerKey converts pubKey into the peer key of an ECDH key agreement: *ecdsa.PublicKey for EC keys or the raw
+// X25519 key.
+func toPeerKey(pubKey *cryptoapi.PublicKey) (interface{}, error) {
+	switch pubKey.Type {
+	case ecdhpb.KeyType_EC.String():
+		curve, err := hybrid.GetCurve(pubKey.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(pubKey.X),
+			Y:     new(big.Int).SetBytes(pubKey.Y),
+		}, nil
+	case ecdhpb.KeyType_OKP.String():
+		return pubKey.X, nil
+	default:
+		return nil, fmt.Errorf("invalid key type '%s'", pubKey.Type)
+	}
+}
+
+func senderPublicKey(senderKH interface{}) (interface{}, error) {
+	switch sk := senderKH.(type) {
+	case *keyset.Handle:
+		pubKey, err := keyio.ExtractPrimaryPublicKey(sk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract sender public key from keyset handle: %w", err)
+		}
+
+		return toPeerKey(pubKey)
+	case *cryptoapi.PublicKey:
+		return toPeerKey(sk)
+	case hsmKey:
+		return sk.PublicKey(), nil
+	default:
+		return nil, fmt.Errorf("unsupported sender key type %T", senderKH)
+	}
+}
+
+func wrapRaw(alg string, kek, cek []byte) ([]byte, error) {
+	switch alg {
+	case tinkcrypto.ECDH1PUXC20PKWAlg:
+		aead, err := chacha20poly1305.NewX(kek)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new XC20P primitive: %w", err)
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+
+		_, err = rand.Read(nonce)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate random nonce: %w", err)
+		}
+
+		return append(nonce, aead.Seal(nil, nonce, cek, nil)...), nil
+	default:
+		block, err := aes.NewCipher(kek)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new AES Cipher: %w", err)
+		}
+
+		return josecipher.KeyWrap(block, cek)
+	}
+}
+
+func unwrapRaw(alg string, kek, encCEK []byte) ([]byte, error) {
+	switch alg {
+	case tinkcrypto.ECDHESXC20PKWAlg, tinkcrypto.ECDH1PUXC20PKWAlg:
+		aead, err := chacha20poly1305.NewX(kek)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new XC20P primitive: %w", err)
+		}
+
+		if len(encCEK) < aead.NonceSize() {
+			return nil, errors.New("failed to XC20P unwrap key: invalid key")
+		}
+
+		cek, err := aead.Open(nil, encCEK[:aead.NonceSize()], encCEK[aead.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to XC20P unwrap key: %w", err)
+		}
+
+		return cek, nil
+	default:
+		block, err := aes.NewCipher(kek)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new AES Cipher: %w", err)
+		}
+
+		cek, err := josecipher.KeyUnwrap(block, encCEK)
+		if err != nil {
+			return nil, fmt.Errorf("failed to AES unwrap key: %w", err)
+		}
+
+		return cek, nil
+	}
+}
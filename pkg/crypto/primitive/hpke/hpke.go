@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package hpke implements Hybrid Public Key Encryption (HPKE) as per https://www.rfc-editor.org/rfc/rfc9180 with
+// the DHKEM(P-256, HKDF-SHA256) and DHKEM(X25519, HKDF-SHA256) KEMs, the HKDF-SHA256 KDF and the AES-128-GCM,
+// AES-256-GCM and ChaCha20Poly1305 AEADs, in base, psk, auth and auth_psk modes.
+//
+// Private keys are used through the PrivateKey interface only, which allows the framework's KMS keys to be used
+// without exporting them. It is recommended to use HPKE with KMS keys along with the framework's Crypto service rather
+// than using this package directly.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// KDFID is the identifier of a Key Derivation Function as per https://www.rfc-editor.org/rfc/rfc9180#section-7.2.
+type KDFID uint16
+
+// HKDFSHA256 is the HKDF-SHA256 KDF, the only supported KDF.
+const HKDFSHA256 KDFID = 0x0001
+
+// AEADID is the identifier of an AEAD as per https://www.rfc-editor.org/rfc/rfc9180#section-7.3.
+type AEADID uint16
+
+// Supported AEADs.
+const (
+	AES128GCM        AEADID = 0x0001
+	AES256GCM        AEADID = 0x0002
+	ChaCha20Poly1305 AEADID = 0x0003
+)
+
+// Mode is the HPKE mode as per https://www.rfc-editor.org/rfc/rfc9180#section-5.
+type Mode byte
+
+// HPKE modes.
+const (
+	ModeBase    Mode = 0x00
+	ModePSK     Mode = 0x01
+	ModeAuth    Mode = 0x02
+	ModeAuthPSK Mode = 0x03
+)
+
+const (
+	versionLabel = "HPKE-v1"
+	// hashSize is Nh of HKDF-SHA256.
+	hashSize = sha256.Size
+	// maxSeq is the sequence number limit of a context, the nonce size of the supported AEADs being 12 bytes.
+	maxSeq = ^uint64(0)
+)
+
+var errMessageLimit = errors.New("message limit reached")
+
+// Suite is a HPKE cipher suite.
+type Suite struct {
+	KEM  KEMID
+	KDF  KDFID
+	AEAD AEADID
+}
+
+// Params are the optional parameters of the HPKE context setup, they must be the same for the sender and the
+// recipient.
+type Params struct {
+	// Info is the application supplied information.
+	Info []byte
+	// PSK and PSKID are the pre-shared key and its identifier for psk and auth_psk modes.
+	PSK   []byte
+	PSKID []byte
+	// SenderKey is the sender private key for auth and auth_psk modes on the sender side.
+	SenderKey PrivateKey
+	// SenderPublicKey is the serialized sender public key for auth and auth_psk modes on the recipient side.
+	SenderPublicKey []byte
+}
+
+// Context is a HPKE encryption context used to seal or open messages in sequence.
+type Context struct {
+	suiteID        []byte
+	aead           cipher.AEAD
+	baseNonce      []byte
+	exporterSecret []byte
+	seq            uint64
+}
+
+// SetupSender creates the sender context encrypting messages for the serialized recipient public key pkR.
+// The mode is selected from p: auth modes if p.SenderKey is set and psk modes if p.PSK is set.
+// Returns the encapsulated key to be sent to the recipient along with the context.
+func SetupSender(suite Suite, pkR []byte, p *Params) ([]byte, *Context, error) {
+	skE, err := GenerateKeyPair(suite.KEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setupSender: failed to generate ephemeral key: %w", err)
+	}
+
+	return setupSender(suite, skE, pkR, p)
+}
+
+func setupSender(suite Suite, skE PrivateKey, pkR []byte, p *Params) ([]byte, *Context, error) {
+	if p == nil {
+		p = &Params{}
+	}
+
+	if err := checkPublicKey(suite.KEM, pkR); err != nil {
+		return nil, nil, fmt.Errorf("setupSender: invalid recipient public key: %w", err)
+	}
+
+	ss, enc, err := encap(suite.KEM, skE, pkR, p.SenderKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setupSender: %w", err)
+	}
+
+	ctx, err := keySchedule(suite, mode(p.SenderKey != nil, p.PSK), ss, p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setupSender: %w", err)
+	}
+
+	return enc, ctx, nil
+}
+
+// SetupRecipient creates the recipient context decrypting messages encrypted for skR with the encapsulated key enc.
+// The mode is selected from p: auth modes if p.SenderPublicKey is set and psk modes if p.PSK is set.
+func SetupRecipient(suite Suite, enc []byte, skR PrivateKey, p *Params) (*Context, error) {
+	if p == nil {
+		p = &Params{}
+	}
+
+	if err := checkPublicKey(suite.KEM, enc); err != nil {
+		return nil, fmt.Errorf("setupRecipient: invalid encapsulated key: %w", err)
+	}
+
+	if err := checkPublicKey(suite.KEM, skR.PublicKey()); err != nil {
+		return nil, fmt.Errorf("setupRecipient: invalid recipient key: %w", err)
+	}
+
+	if len(p.SenderPublicKey) > 0 {
+		if err := checkPublicKey(suite.KEM, p.SenderPublicKey); err != nil {
+			return nil, fmt.Errorf("setupRecipient: invalid sender public key: %w", err)
+		}
+	}
+
+	ss, err := decap(suite.KEM, enc, skR, p.SenderPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("setupRecipient: %w", err)
+	}
+
+	ctx, err := keySchedule(suite, mode(len(p.SenderPublicKey) > 0, p.PSK), ss, p)
+	if err != nil {
+		return nil, fmt.Errorf("setupRecipient: %w", err)
+	}
+
+	return ctx, nil
+}
+
+// Seal is the single-shot encryption of plaintext with aad for the serialized recipient public key pkR.
+// Returns the encapsulated key and the ciphertext.
+func Seal(suite Suite, pkR, plaintext, aad []byte, p *Params) ([]byte, []byte, error) {
+	enc, ctx, err := SetupSender(suite, pkR, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ct, err := ctx.Seal(plaintext, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return enc, ct, nil
+}
+
+// Open is the single-shot decryption of ciphertext with aad for skR with the encapsulated key enc.
+func Open(suite Suite, enc []byte, skR PrivateKey, ciphertext, aad []byte, p *Params) ([]byte, error) {
+	ctx, err := SetupRecipient(suite, enc, skR, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.Open(ciphertext, aad)
+}
+
+// Seal encrypts plaintext with aad using the next sequence number of the context.
+func (c *Context) Seal(plaintext, aad []byte) ([]byte, error) {
+	if c.seq == maxSeq {
+		return nil, errMessageLimit
+	}
+
+	ct := c.aead.Seal(nil, c.computeNonce(), plaintext, aad)
+	c.seq++
+
+	return ct, nil
+}
+
+// Open decrypts ciphertext with aad using the next sequence number of the context.
+func (c *Context) Open(ciphertext, aad []byte) ([]byte, error) {
+	if c.seq == maxSeq {
+		return nil, errMessageLimit
+	}
+
+	pt, err := c.aead.Open(nil, c.computeNonce(), ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	c.seq++
+
+	return pt, nil
+}
+
+// Export derives a secret of length bytes from the context and exporterContext as per
+// https://www.rfc-editor.org/rfc/rfc9180#section-5.3.
+func (c *Context) Export(exporterContext []byte, length int) ([]byte, error) {
+	if length <= 0 || length > 255*hashSize {
+		return nil, errors.New("export: invalid length")
+	}
+
+	return labeledExpand(c.suiteID, c.exporterSecret, "sec", exporterContext, length), nil
+}
+
+func (c *Context) computeNonce() []byte {
+	nonce := make([]byte, len(c.baseNonce))
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], c.seq)
+
+	for i := range nonce {
+		nonce[i] ^= c.baseNonce[i]
+	}
+
+	return nonce
+}
+
+func mode(auth bool, psk []byte) Mode {
+	m := ModeBase
+
+	if len(psk) > 0 {
+		m = ModePSK
+	}
+
+	if auth {
+		m |= ModeAuth
+	}
+
+	return m
+}
+
+func keySchedule(suite Suite, m Mode, sharedSecret []byte, p *Params) (*Context, error) {
+	if suite.KDF != HKDFSHA256 {
+		return nil, errors.New("unsupported KDF")
+	}
+
+	if (len(p.PSK) == 0) != (len(p.PSKID) == 0) {
+		return nil, errors.New("inconsistent PSK inputs")
+	}
+
+	newAEAD, keySize, err := aeadCipher(suite.AEAD)
+	if err != nil {
+		return nil, err
+	}
+
+	suiteID := []byte{
+		'H', 'P', 'K', 'E',
+		byte(suite.KEM >> 8), byte(suite.KEM),
+		byte(suite.KDF >> 8), byte(suite.KDF),
+		byte(suite.AEAD >> 8), byte(suite.AEAD),
+	}
+
+	pskIDHash := labeledExtract(suiteID, nil, "psk_id_hash", p.PSKID)
+	infoHash := labeledExtract(suiteID, nil, "info_hash", p.Info)
+	ksContext := append(append([]byte{byte(m)}, pskIDHash...), infoHash...)
+
+	secret := labeledExtract(suiteID, sharedSecret, "secret", p.PSK)
+
+	a, err := newAEAD(labeledExpand(suiteID, secret, "key", ksContext, keySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AEAD: %w", err)
+	}
+
+	return &Context{
+		suiteID:        suiteID,
+		aead:           a,
+		baseNonce:      labeledExpand(suiteID, secret, "base_nonce", ksContext, a.NonceSize()),
+		exporterSecret: labeledExpand(suiteID, secret, "exp", ksContext, hashSize),
+	}, nil
+}
+
+func aeadCipher(id AEADID) (func(key []byte) (cipher.AEAD, error), int, error) {
+	newGCM := func(key []byte) (cipher.AEAD, error) {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(block)
+	}
+
+	switch id {
+	case AES128GCM:
+		return newGCM, 16, nil //nolint:gomnd
+	case AES256GCM:
+		return newGCM, 32, nil //nolint:gomnd
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New, chacha20poly1305.KeySize, nil
+	default:
+		return nil, 0, errors.New("unsupported AEAD")
+	}
+}
+
+func checkPublicKey(kem KEMID, pk []byte) error {
+	switch kem {
+	case DHKEMP256HKDFSHA256:
+		if len(pk) != 1+2*p256ScalarSize || pk[0] != 4 { //nolint:gomnd
+			return errors.New("not an uncompressed P-256 public key")
+		}
+	case DHKEMX25519HKDFSHA256:
+		if len(pk) != x25519KeySize {
+			return errors.New("not a X25519 public key")
+		}
+	default:
+		return errInvalidKEM
+	}
+
+	return nil
+}
+
+// labeledExtract is LabeledExtract() of https://www.rfc-editor.org/rfc/rfc9180#section-4.
+func labeledExtract(suiteID, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := append(append(append([]byte(versionLabel), suiteID...), label...), ikm...)
+
+	return hkdf.Extract(sha256.New, labeledIKM, salt)
+}
+
+// labeledExpand is LabeledExpand() of https://www.rfc-editor.org/rfc/rfc9180#section-4.
+func labeledExpand(suiteID, prk []byte, label string, info []byte, length int) []byte {
+	labeledInfo := []byte{byte(length >> 8), byte(length)}
+	labeledInfo = append(append(append(append(labeledInfo, versionLabel...), suiteID...), label...), info...)
+
+	out := make([]byte, length)
+
+	// reading less than 255 * hashSize bytes from HKDF cannot fail.
+	_, _ = io.ReadFull(hkdf.Expand(sha256.New, prk, labeledInfo), out) //nolint:errcheck
+
+	return out
+}
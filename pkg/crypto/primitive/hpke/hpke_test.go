@@ -0,0 +1,287 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hpke
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors of https://www.rfc-editor.org/rfc/rfc9180#appendix-A (base mode, AES-128-GCM, sequence number 0).
+func TestRFC9180Vectors(t *testing.T) {
+	const (
+		info = "4f6465206f6e2061204772656369616e2055726e"
+		pt   = "4265617574792069732074727574682c20747275746820626561757479"
+		aad  = "436f756e742d30"
+	)
+
+	tests := []struct {
+		name     string
+		kem      KEMID
+		ikmE     string
+		ikmR     string
+		pkRm     string
+		enc      string
+		nonce    string
+		exporter string
+		ct       string
+	}{
+		{
+			name:     "A.1.1 DHKEM(X25519, HKDF-SHA256)",
+			kem:      DHKEMX25519HKDFSHA256,
+			ikmE:     "7268600d403fce431561aef583ee1613527cff655c1343f29812e66706df3234",
+			ikmR:     "6db9df30aa07dd42ee5e8181afdb977e538f5e1fec8a06223f33f7013e525037",
+			pkRm:     "3948cfe0ad1ddb695d780e59077195da6c56506b027329794ab02bca80815c4d",
+			enc:      "37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431",
+			nonce:    "56d890e5accaaf011cff4b7d",
+			exporter: "45ff1c2e220db587171952c0592d5f5ebe103f1561a2614e38f2ffd47e99e3f8",
+			ct:       "f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a",
+		},
+		{
+			name: "A.3.1 DHKEM(P-256, HKDF-SHA256)",
+			kem:  DHKEMP256HKDFSHA256,
+			ikmE: "4270e54ffd08d79d5928020af4686d8f6b7d35dbe470265f1f5aa22816ce860e",
+			ikmR: "668b37171f1072f3cf12ea8a236a45df23fc13b82af3609ad1e354f6ef817550",
+			pkRm: "04fe8c19ce0905191ebc298a9245792531f26f0cece2460639e8bc39cb7f706a826a779b4cf969b8a0e539c7f62fb3d" +
+				"30ad6aa8f80e30f1d128aafd68a2ce72ea0",
+			enc: "04a92719c6195d5085104f469a8b9814d5838ff72b60501e2c4466e5e67b325ac98536d7b61a1af4b78e5b7f951c09" +
+				"00be863c403ce65c9bfcb9382657222d18c4",
+			nonce: "4e0bc5018beba4bf004cca59",
+			ct:    "5ad590bb8baa577f8619db35a36311226a896e7342a6d836d8b7bcd2f20b6c7f9076ac232e3ab2523f39513434",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			suite := Suite{KEM: tc.kem, KDF: HKDFSHA256, AEAD: AES128GCM}
+			p := &Params{Info: unhex(t, info)}
+
+			skE, err := DeriveKeyPair(tc.kem, unhex(t, tc.ikmE))
+			require.NoError(t, err)
+
+			skR, err := DeriveKeyPair(tc.kem, unhex(t, tc.ikmR))
+			require.NoError(t, err)
+			require.Equal(t, tc.pkRm, hex.EncodeToString(skR.PublicKey()))
+
+			enc, sender, err := setupSender(suite, skE, skR.PublicKey(), p)
+			require.NoError(t, err)
+			require.Equal(t, tc.enc, hex.EncodeToString(enc))
+			require.Equal(t, tc.nonce, hex.EncodeToString(sender.baseNonce))
+
+			if tc.exporter != "" {
+				require.Equal(t, tc.exporter, hex.EncodeToString(sender.exporterSecret))
+			}
+
+			ct, err := sender.Seal(unhex(t, pt), unhex(t, aad))
+			require.NoError(t, err)
+			require.Equal(t, tc.ct, hex.EncodeToString(ct))
+
+			recipient, err := SetupRecipient(suite, enc, skR, p)
+			require.NoError(t, err)
+
+			plaintext, err := recipient.Open(ct, unhex(t, aad))
+			require.NoError(t, err)
+			require.Equal(t, unhex(t, pt), plaintext)
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	msg := []byte("secret message")
+	aad := []byte("aad")
+
+	for _, kem := range []KEMID{DHKEMP256HKDFSHA256, DHKEMX25519HKDFSHA256} {
+		for _, aead := range []AEADID{AES128GCM, AES256GCM, ChaCha20Poly1305} {
+			suite := Suite{KEM: kem, KDF: HKDFSHA256, AEAD: aead}
+
+			skR, err := GenerateKeyPair(kem)
+			require.NoError(t, err)
+
+			skS, err := GenerateKeyPair(kem)
+			require.NoError(t, err)
+
+			psk, pskID := []byte("0123456789abcdef0123456789abcdef"), []byte("psk id")
+
+			for _, p := range []struct {
+				mode      Mode
+				sender    *Params
+				recipient *Params
+			}{
+				{ModeBase, nil, nil},
+				{ModePSK, &Params{PSK: psk, PSKID: pskID}, &Params{PSK: psk, PSKID: pskID}},
+				{ModeAuth, &Params{SenderKey: skS}, &Params{SenderPublicKey: skS.PublicKey()}},
+				{
+					ModeAuthPSK,
+					&Params{Info: []byte("info"), SenderKey: skS, PSK: psk, PSKID: pskID},
+					&Params{Info: []byte("info"), SenderPublicKey: skS.PublicKey(), PSK: psk, PSKID: pskID},
+				},
+			} {
+				enc, ct, err := Seal(suite, skR.PublicKey(), msg, aad, p.sender)
+				require.NoError(t, err)
+
+				pt, err := Open(suite, enc, skR, ct, aad, p.recipient)
+				require.NoError(t, err, "mode %d", p.mode)
+				require.Equal(t, msg, pt)
+
+				// a different recipient mode cannot open the message.
+				_, err = Open(suite, enc, skR, ct, aad, &Params{})
+				if p.mode == ModeBase {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+			}
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	suite := Suite{KEM: DHKEMX25519HKDFSHA256, KDF: HKDFSHA256, AEAD: ChaCha20Poly1305}
+
+	skR, err := GenerateKeyPair(suite.KEM)
+	require.NoError(t, err)
+
+	enc, sender, err := SetupSender(suite, skR.PublicKey(), nil)
+	require.NoError(t, err)
+
+	recipient, err := SetupRecipient(suite, enc, skR, nil)
+	require.NoError(t, err)
+
+	var cts [][]byte
+
+	for _, msg := range []string{"msg0", "msg1", "msg2"} {
+		ct, e := sender.Seal([]byte(msg), nil)
+		require.NoError(t, e)
+
+		cts = append(cts, ct)
+	}
+
+	// messages must be opened in sequence.
+	_, err = recipient.Open(cts[1], nil)
+	require.Error(t, err)
+
+	for i, msg := range []string{"msg0", "msg1", "msg2"} {
+		pt, e := recipient.Open(cts[i], nil)
+		require.NoError(t, e)
+		require.Equal(t, msg, string(pt))
+	}
+
+	exported, err := sender.Export([]byte("context"), 32)
+	require.NoError(t, err)
+
+	recipientExported, err := recipient.Export([]byte("context"), 32)
+	require.NoError(t, err)
+	require.Equal(t, exported, recipientExported)
+
+	_, err = sender.Export(nil, 255*32+1)
+	require.EqualError(t, err, "export: invalid length")
+
+	sender.seq = maxSeq
+	_, err = sender.Seal(nil, nil)
+	require.EqualError(t, err, "message limit reached")
+
+	recipient.seq = maxSeq
+	_, err = recipient.Open(nil, nil)
+	require.EqualError(t, err, "message limit reached")
+}
+
+func TestFailures(t *testing.T) {
+	suite := Suite{KEM: DHKEMP256HKDFSHA256, KDF: HKDFSHA256, AEAD: AES256GCM}
+
+	skR, err := GenerateKeyPair(suite.KEM)
+	require.NoError(t, err)
+
+	x25519Key, err := GenerateKeyPair(DHKEMX25519HKDFSHA256)
+	require.NoError(t, err)
+
+	t.Run("invalid suite", func(t *testing.T) {
+		_, _, err := Seal(Suite{KEM: 0x0011, KDF: HKDFSHA256, AEAD: AES256GCM}, skR.PublicKey(), nil, nil, nil)
+		require.EqualError(t, err, "setupSender: failed to generate ephemeral key: unsupported KEM")
+
+		_, _, err = Seal(Suite{KEM: suite.KEM, KDF: 0x0002, AEAD: AES256GCM}, skR.PublicKey(), nil, nil, nil)
+		require.EqualError(t, err, "setupSender: unsupported KDF")
+
+		_, _, err = Seal(Suite{KEM: suite.KEM, KDF: HKDFSHA256, AEAD: 0xFFFF}, skR.PublicKey(), nil, nil, nil)
+		require.EqualError(t, err, "setupSender: unsupported AEAD")
+
+		_, err = GenerateKeyPair(0x0011)
+		require.EqualError(t, err, "unsupported KEM")
+
+		_, err = DeriveKeyPair(0x0011, []byte("ikm"))
+		require.EqualError(t, err, "unsupported KEM")
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, _, err := Seal(suite, x25519Key.PublicKey(), nil, nil, nil)
+		require.EqualError(t, err, "setupSender: invalid recipient public key: not an uncompressed P-256 public key")
+
+		_, _, err = Seal(Suite{KEM: DHKEMX25519HKDFSHA256, KDF: HKDFSHA256, AEAD: AES256GCM}, skR.PublicKey(),
+			nil, nil, nil)
+		require.EqualError(t, err, "setupSender: invalid recipient public key: not a X25519 public key")
+
+		// point not on the curve.
+		invalidPoint := append([]byte{4}, make([]byte, 64)...)
+
+		_, _, err = Seal(suite, invalidPoint, nil, nil, nil)
+		require.EqualError(t, err, "setupSender: failed to compute ephemeral DH: invalid P-256 public key")
+
+		_, err = Open(suite, invalidPoint, skR, nil, nil, nil)
+		require.EqualError(t, err, "setupRecipient: failed to compute ephemeral DH: invalid P-256 public key")
+
+		_, err = Open(suite, skR.PublicKey(), x25519Key, nil, nil, nil)
+		require.EqualError(t, err, "setupRecipient: invalid recipient key: not an uncompressed P-256 public key")
+
+		_, err = Open(suite, x25519Key.PublicKey(), skR, nil, nil, nil)
+		require.EqualError(t, err, "setupRecipient: invalid encapsulated key: not an uncompressed P-256 public key")
+
+		_, err = Open(suite, skR.PublicKey(), skR, nil, nil, &Params{SenderPublicKey: x25519Key.PublicKey()})
+		require.EqualError(t, err, "setupRecipient: invalid sender public key: not an uncompressed P-256 public key")
+
+		_, err = Open(suite, skR.PublicKey(), skR, nil, nil, &Params{SenderPublicKey: invalidPoint})
+		require.EqualError(t, err, "setupRecipient: failed to compute sender DH: invalid P-256 public key")
+
+		_, _, err = Seal(Suite{KEM: DHKEMX25519HKDFSHA256, KDF: HKDFSHA256, AEAD: AES256GCM},
+			x25519Key.PublicKey(), nil, nil, &Params{SenderKey: skR})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "setupSender: failed to compute sender DH")
+
+		_, err = NewX25519PrivateKey([]byte("short"))
+		require.EqualError(t, err, "invalid X25519 private key size")
+
+		p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = NewP256PrivateKey(p384Key)
+		require.EqualError(t, err, "private key is not a P-256 key")
+	})
+
+	t.Run("inconsistent PSK", func(t *testing.T) {
+		_, _, err := Seal(suite, skR.PublicKey(), nil, nil, &Params{PSK: []byte("psk")})
+		require.EqualError(t, err, "setupSender: inconsistent PSK inputs")
+
+		enc, ct, err := Seal(suite, skR.PublicKey(), nil, nil, nil)
+		require.NoError(t, err)
+
+		_, err = Open(suite, enc, skR, ct, nil, &Params{PSKID: []byte("psk id")})
+		require.EqualError(t, err, "setupRecipient: inconsistent PSK inputs")
+	})
+}
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,226 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hpke
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// KEMID is the identifier of a Key Encapsulation Mechanism as per https://www.rfc-editor.org/rfc/rfc9180#section-7.1.
+type KEMID uint16
+
+// Supported KEMs.
+const (
+	// DHKEMP256HKDFSHA256 is DHKEM(P-256, HKDF-SHA256).
+	DHKEMP256HKDFSHA256 KEMID = 0x0010
+	// DHKEMX25519HKDFSHA256 is DHKEM(X25519, HKDF-SHA256).
+	DHKEMX25519HKDFSHA256 KEMID = 0x0020
+)
+
+const (
+	// secretSize is Nsecret of the supported KEMs.
+	secretSize = 32
+	// x25519KeySize is Npk and Nsk of DHKEM(X25519, HKDF-SHA256).
+	x25519KeySize = 32
+	// p256ScalarSize is Nsk of DHKEM(P-256, HKDF-SHA256).
+	p256ScalarSize = 32
+)
+
+var errInvalidKEM = errors.New("unsupported KEM")
+
+// PrivateKey is the private key of a KEM key pair. DH may be computed by a KMS or a HSM without exposing the private
+// key.
+type PrivateKey interface {
+	// PublicKey returns the serialized public key of the key pair: the uncompressed point for P-256 or the raw key for
+	// X25519.
+	PublicKey() []byte
+	// DH returns the ECDH shared secret of the private key and the serialized public key pk: the X coordinate of the
+	// shared point for P-256 or the X25519 function output.
+	DH(pk []byte) ([]byte, error)
+}
+
+type p256PrivateKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k *p256PrivateKey) PublicKey() []byte {
+	return elliptic.Marshal(k.key.Curve, k.key.X, k.key.Y)
+}
+
+func (k *p256PrivateKey) DH(pk []byte) ([]byte, error) {
+	return P256DH(k.key, pk)
+}
+
+type x25519PrivateKey struct {
+	key []byte
+	pub []byte
+}
+
+func (k *x25519PrivateKey) PublicKey() []byte {
+	return k.pub
+}
+
+func (k *x25519PrivateKey) DH(pk []byte) ([]byte, error) {
+	return curve25519.X25519(k.key, pk)
+}
+
+// NewP256PrivateKey returns the DHKEM(P-256, HKDF-SHA256) PrivateKey of key.
+func NewP256PrivateKey(key *ecdsa.PrivateKey) (PrivateKey, error) {
+	if key == nil || key.Curve != elliptic.P256() {
+		return nil, errors.New("private key is not a P-256 key")
+	}
+
+	return &p256PrivateKey{key: key}, nil
+}
+
+// NewX25519PrivateKey returns the DHKEM(X25519, HKDF-SHA256) PrivateKey of the raw X25519 private key.
+func NewX25519PrivateKey(key []byte) (PrivateKey, error) {
+	if len(key) != x25519KeySize {
+		return nil, errors.New("invalid X25519 private key size")
+	}
+
+	pub, err := curve25519.X25519(key, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &x25519PrivateKey{key: key, pub: pub}, nil
+}
+
+// P256DH returns the ECDH shared secret of key and the serialized P-256 public key pk.
+func P256DH(key *ecdsa.PrivateKey, pk []byte) ([]byte, error) {
+	x, y := elliptic.Unmarshal(elliptic.P256(), pk)
+	if x == nil {
+		return nil, errors.New("invalid P-256 public key")
+	}
+
+	zx, _ := elliptic.P256().ScalarMult(x, y, key.D.Bytes())
+
+	z := make([]byte, p256ScalarSize)
+
+	return zx.FillBytes(z), nil
+}
+
+// GenerateKeyPair generates a new random key pair of kem.
+func GenerateKeyPair(kem KEMID) (PrivateKey, error) {
+	switch kem {
+	case DHKEMP256HKDFSHA256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewP256PrivateKey(key)
+	case DHKEMX25519HKDFSHA256:
+		key := make([]byte, x25519KeySize)
+
+		_, err := rand.Read(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewX25519PrivateKey(key)
+	default:
+		return nil, errInvalidKEM
+	}
+}
+
+// DeriveKeyPair derives a key pair of kem from the input keying material ikm as per
+// https://www.rfc-editor.org/rfc/rfc9180#section-7.1.3.
+func DeriveKeyPair(kem KEMID, ikm []byte) (PrivateKey, error) {
+	suiteID := kemSuiteID(kem)
+	dkpPRK := labeledExtract(suiteID, nil, "dkp_prk", ikm)
+
+	switch kem {
+	case DHKEMP256HKDFSHA256:
+		order := elliptic.P256().Params().N
+
+		for counter := 0; counter < 256; counter++ {
+			candidate := labeledExpand(suiteID, dkpPRK, "candidate", []byte{byte(counter)}, p256ScalarSize)
+			d := new(big.Int).SetBytes(candidate)
+
+			if d.Sign() != 0 && d.Cmp(order) < 0 {
+				key := &ecdsa.PrivateKey{D: d}
+				key.Curve = elliptic.P256()
+				key.X, key.Y = key.Curve.ScalarBaseMult(candidate)
+
+				return NewP256PrivateKey(key)
+			}
+		}
+
+		return nil, errors.New("failed to derive key pair")
+	case DHKEMX25519HKDFSHA256:
+		return NewX25519PrivateKey(labeledExpand(suiteID, dkpPRK, "sk", nil, x25519KeySize))
+	default:
+		return nil, errInvalidKEM
+	}
+}
+
+func kemSuiteID(kem KEMID) []byte {
+	return []byte{'K', 'E', 'M', byte(kem >> 8), byte(kem)}
+}
+
+// encap returns the shared secret and the encapsulated key of a new ephemeral key skE for the recipient public key
+// pkR, the shared secret is authenticated by the sender key skS if not nil.
+func encap(kem KEMID, skE PrivateKey, pkR []byte, skS PrivateKey) ([]byte, []byte, error) {
+	dh, err := skE.DH(pkR)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute ephemeral DH: %w", err)
+	}
+
+	enc := skE.PublicKey()
+	kemContext := append(append([]byte{}, enc...), pkR...)
+
+	if skS != nil {
+		dhS, e := skS.DH(pkR)
+		if e != nil {
+			return nil, nil, fmt.Errorf("failed to compute sender DH: %w", e)
+		}
+
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, skS.PublicKey()...)
+	}
+
+	return extractAndExpand(kem, dh, kemContext), enc, nil
+}
+
+// decap returns the shared secret of the encapsulated key enc for the recipient key skR, authenticated by the sender
+// public key pkS if not empty.
+func decap(kem KEMID, enc []byte, skR PrivateKey, pkS []byte) ([]byte, error) {
+	dh, err := skR.DH(enc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute ephemeral DH: %w", err)
+	}
+
+	kemContext := append(append([]byte{}, enc...), skR.PublicKey()...)
+
+	if len(pkS) > 0 {
+		dhS, e := skR.DH(pkS)
+		if e != nil {
+			return nil, fmt.Errorf("failed to compute sender DH: %w", e)
+		}
+
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS...)
+	}
+
+	return extractAndExpand(kem, dh, kemContext), nil
+}
+
+func extractAndExpand(kem KEMID, dh, kemContext []byte) []byte {
+	suiteID := kemSuiteID(kem)
+	eaePRK := labeledExtract(suiteID, nil, "eae_prk", dh)
+
+	return labeledExpand(suiteID, eaePRK, "shared_secret", kemContext, secretSize)
+}
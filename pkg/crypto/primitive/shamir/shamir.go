@@ -0,0 +1,158 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package shamir implements Shamir's secret sharing over GF(2^8): a secret is split into parts of which any threshold
+// number of parts reconstruct the secret, while fewer parts reveal nothing about it.
+//
+// Each part is the evaluation of a random polynomial per secret byte followed by the x coordinate of the part, it is
+// one byte longer than the secret.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+const maxParts = 255
+
+// Split splits secret into parts shares, any threshold of which can reconstruct secret with Combine.
+func Split(secret []byte, parts, threshold int) ([][]byte, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, errors.New("secret is empty")
+	case parts > maxParts:
+		return nil, fmt.Errorf("parts cannot exceed %d", maxParts)
+	case threshold < 2: //nolint:gomnd
+		return nil, errors.New("threshold must be at least 2")
+	case parts < threshold:
+		return nil, errors.New("parts cannot be less than threshold")
+	}
+
+	shares := make([][]byte, parts)
+
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+
+	for idx, b := range secret {
+		// coefficients[0] is the secret byte, the others are random.
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+		}
+
+		coefficients[0] = b
+
+		for _, share := range shares {
+			share[idx] = evaluate(coefficients, share[len(secret)])
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine reconstructs the secret from parts created by Split. The result is only correct if at least the threshold
+// number of distinct parts of the same secret are provided.
+func Combine(parts [][]byte) ([]byte, error) {
+	if len(parts) < 2 { //nolint:gomnd
+		return nil, errors.New("at least two parts are required")
+	}
+
+	size := len(parts[0])
+	if size < 2 { //nolint:gomnd
+		return nil, errors.New("parts are too short")
+	}
+
+	xs := make([]byte, len(parts))
+	found := map[byte]bool{}
+
+	for i, part := range parts {
+		if len(part) != size {
+			return nil, errors.New("all parts must have the same length")
+		}
+
+		x := part[size-1]
+		if x == 0 || found[x] {
+			return nil, errors.New("invalid or duplicate part")
+		}
+
+		found[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, size-1)
+
+	for idx := range secret {
+		var s byte
+
+		for i, part := range parts {
+			s ^= mul(part[idx], lagrangeBasisAtZero(xs, i))
+		}
+
+		secret[idx] = s
+	}
+
+	return secret, nil
+}
+
+// evaluate returns the value of the polynomial with coefficients at x using Horner's method.
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+
+	return y
+}
+
+// lagrangeBasisAtZero returns the value at 0 of the Lagrange basis polynomial of xs[i].
+func lagrangeBasisAtZero(xs []byte, i int) byte {
+	basis := byte(1)
+
+	for j, x := range xs {
+		if j == i {
+			continue
+		}
+
+		// in GF(2^8), (0 - x) / (xs[i] - x) is x / (xs[i] ^ x).
+		basis = mul(basis, mul(x, inverse(xs[i]^x)))
+	}
+
+	return basis
+}
+
+// mul multiplies a and b in GF(2^8) with the AES reduction polynomial, without data dependent branches.
+func mul(a, b byte) byte {
+	var p byte
+
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		b >>= 1
+		// reduce by x^8 + x^4 + x^3 + x + 1 if the high bit of a is set.
+		a = (a << 1) ^ (0x1b & -(a >> 7)) //nolint:gomnd
+	}
+
+	return p
+}
+
+// inverse returns the multiplicative inverse of a non zero a in GF(2^8), computed as a^254.
+func inverse(a byte) byte {
+	result := byte(1)
+
+	for e := 254; e > 0; e >>= 1 { //nolint:gomnd
+		if e&1 == 1 {
+			result = mul(result, a)
+		}
+
+		a = mul(a, a)
+	}
+
+	return result
+}
@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shamir
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAndCombine(t *testing.T) {
+	secret := random.GetRandomBytes(32)
+
+	parts, err := Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, parts, 5)
+
+	for _, part := range parts {
+		require.Len(t, part, len(secret)+1)
+	}
+
+	t.Run("any threshold of parts", func(t *testing.T) {
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var selected [][]byte
+
+			for _, i := range subset {
+				selected = append(selected, parts[i])
+			}
+
+			recovered, err := Combine(selected)
+			require.NoError(t, err)
+			require.Equal(t, secret, recovered)
+		}
+	})
+
+	t.Run("less than threshold", func(t *testing.T) {
+		recovered, err := Combine(parts[:2])
+		require.NoError(t, err)
+		require.NotEqual(t, secret, recovered)
+	})
+
+	t.Run("split is randomized", func(t *testing.T) {
+		parts2, err := Split(secret, 5, 3)
+		require.NoError(t, err)
+		require.NotEqual(t, parts, parts2)
+	})
+
+	t.Run("max parts", func(t *testing.T) {
+		parts, err := Split(secret, 255, 255)
+		require.NoError(t, err)
+
+		recovered, err := Combine(parts)
+		require.NoError(t, err)
+		require.Equal(t, secret, recovered)
+	})
+}
+
+func TestSplit_Failure(t *testing.T) {
+	secret := []byte("secret")
+
+	_, err := Split(nil, 3, 2)
+	require.EqualError(t, err, "secret is empty")
+
+	_, err = Split(secret, 256, 2)
+	require.EqualError(t, err, "parts cannot exceed 255")
+
+	_, err = Split(secret, 3, 1)
+	require.EqualError(t, err, "threshold must be at least 2")
+
+	_, err = Split(secret, 2, 3)
+	require.EqualError(t, err, "parts cannot be less than threshold")
+}
+
+func TestCombine_Failure(t *testing.T) {
+	parts, err := Split([]byte("secret"), 3, 2)
+	require.NoError(t, err)
+
+	_, err = Combine(parts[:1])
+	require.EqualError(t, err, "at least two parts are required")
+
+	_, err = Combine([][]byte{{1}, {2}})
+	require.EqualError(t, err, "parts are too short")
+
+	_, err = Combine([][]byte{parts[0], parts[1][1:]})
+	require.EqualError(t, err, "all parts must have the same length")
+
+	_, err = Combine([][]byte{parts[0], parts[0]})
+	require.EqualError(t, err, "invalid or duplicate part")
+
+	_, err = Combine([][]byte{parts[0], append([]byte("secret"), 0)})
+	require.EqualError(t, err, "invalid or duplicate part")
+}
+
+func TestField(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(1), mul(byte(a), inverse(byte(a))))
+	}
+
+	// 0x53 * 0xca = 0x01 in the AES field.
+	require.Equal(t, byte(0x01), mul(0x53, 0xca))
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+}
@@ -24,6 +24,7 @@ import (
 
 	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const (
@@ -60,9 +61,9 @@ func New() (*Crypto, error) {
 
 // Encrypt will encrypt msg using the implementation's corresponding encryption key and primitive in kh of a public key.
 func (t *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageEncrypt)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	ps, err := keyHandle.Primitives()
@@ -107,9 +108,9 @@ func nonceSize(ps *primitiveset.PrimitiveSet) int {
 // Decrypt will decrypt cipher using the implementation's corresponding encryption key referenced by kh of
 // a private key.
 func (t *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageEncrypt)
+	if err != nil {
+		return nil, err
 	}
 
 	ps, err := keyHandle.Primitives()
@@ -138,9 +139,9 @@ func (t *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, err
 
 // Sign will sign msg using the implementation's corresponding signing key referenced by kh of a private key.
 func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return nil, err
 	}
 
 	signer, err := signature.NewSigner(keyHandle)
@@ -159,9 +160,9 @@ func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 // Verify will verify sig signature of msg using the implementation's corresponding signing key referenced by kh of
 // a public key.
 func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return err
 	}
 
 	verifier, err := signature.NewVerifier(keyHandle)
@@ -180,9 +181,9 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 // ComputeMAC computes message authentication code (MAC) for code data
 // using a matching MAC primitive in kh key handle.
 func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return nil, err
 	}
 
 	macPrimitive, err := mac.New(keyHandle)
@@ -196,9 +197,9 @@ func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
 // VerifyMAC determines if mac is a correct authentication code (MAC) for data
 // using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
 func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageSign)
+	if err != nil {
+		return err
 	}
 
 	macPrimitive, err := mac.New(keyHandle)
@@ -230,7 +231,12 @@ func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
 		opt(pOpts)
 	}
 
-	wk, err := t.deriveKEKAndWrap(cek, apu, apv, pOpts.SenderKey(), recPubKey, pOpts.UseXC20PKW())
+	senderKey, err := kms.ResolveKeyHandle(pOpts.SenderKey(), kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	wk, err := t.deriveKEKAndWrap(cek, apu, apv, senderKey, recPubKey, pOpts.UseXC20PKW())
 	if err != nil {
 		return nil, fmt.Errorf("wrapKey: %w", err)
 	}
@@ -269,8 +275,17 @@ func (t *Crypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, recipientKH int
 		opt(pOpts)
 	}
 
-	key, err := t.deriveKEKAndUnwrap(recWK.Alg, recWK.EncryptedCEK, recWK.APU, recWK.APV, &recWK.EPK, pOpts.SenderKey(),
-		recipientKH)
+	recKH, err := kms.ResolveKeyHandle(recipientKH, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	senderKey, err := kms.ResolveKeyHandle(pOpts.SenderKey(), kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	key, err := t.deriveKEKAndUnwrap(recWK.Alg, recWK.EncryptedCEK, recWK.APU, recWK.APV, &recWK.EPK, senderKey, recKH)
 	if err != nil {
 		return nil, fmt.Errorf("unwrapKey: %w", err)
 	}
@@ -283,9 +298,9 @@ func (t *Crypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, recipientKH int
 // 		signature in []byte
 //		error in case of errors
 func (t *Crypto) SignMulti(messages [][]byte, signerKH interface{}) ([]byte, error) {
-	keyHandle, ok := signerKH.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(signerKH, kms.KeyUsageSign)
+	if err != nil {
+		return nil, err
 	}
 
 	signer, err := bbs.NewSigner(keyHandle)
@@ -305,9 +320,9 @@ func (t *Crypto) SignMulti(messages [][]byte, signerKH interface{}) ([]byte, err
 // returns:
 // 		error in case of errors or nil if signature verification was successful
 func (t *Crypto) VerifyMulti(messages [][]byte, bbsSignature []byte, signerPubKH interface{}) error {
-	keyHandle, ok := signerPubKH.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(signerPubKH, kms.KeyUsageSign)
+	if err != nil {
+		return err
 	}
 
 	verifier, err := bbs.NewVerifier(keyHandle)
@@ -328,9 +343,9 @@ func (t *Crypto) VerifyMulti(messages [][]byte, bbsSignature []byte, signerPubKH
 // returns:
 // 		error in case of errors or nil if signature proof verification was successful
 func (t *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, signerPubKH interface{}) error {
-	keyHandle, ok := signerPubKH.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(signerPubKH, kms.KeyUsageSign)
+	if err != nil {
+		return err
 	}
 
 	verifier, err := bbs.NewVerifier(keyHandle)
@@ -353,9 +368,9 @@ func (t *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, sig
 //		error in case of errors
 func (t *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
 	signerPubKH interface{}) ([]byte, error) {
-	keyHandle, ok := signerPubKH.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := keysetHandle(signerPubKH, kms.KeyUsageSign)
+	if err != nil {
+		return nil, err
 	}
 
 	verifier, err := bbs.NewVerifier(keyHandle)
@@ -370,3 +385,19 @@ func (t *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, reve
 
 	return proof, nil
 }
+
+// keysetHandle returns the Tink keyset handle of kh. If kh is a usage constrained handle, its usage constraint must
+// allow usage.
+func keysetHandle(kh interface{}, usage kms.KeyUsage) (*keyset.Handle, error) {
+	resolved, err := kms.ResolveKeyHandle(kh, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	keyHandle, ok := resolved.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	return keyHandle, nil
+}
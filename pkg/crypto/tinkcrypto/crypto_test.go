@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
 	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const testMessage = "test message"
@@ -320,6 +321,94 @@ func TestCrypto_ECDHES_Wrap_Unwrap_Key(t *testing.T) {
 	require.EqualValues(t, cek, uCEK)
 }
 
+func TestCrypto_KeyUsageConstraints(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	msg := []byte(testMessage)
+
+	t.Run("sign-only key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		signKH := &kms.UsageConstrainedHandle{Handle: kh, Usage: kms.KeyUsageSign}
+
+		s, err := c.Sign(msg, signKH)
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		err = c.Verify(s, msg, &kms.UsageConstrainedHandle{Handle: pubKH, Usage: kms.KeyUsageSign})
+		require.NoError(t, err)
+
+		_, _, err = c.Encrypt(msg, nil, signKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+
+		_, err = c.UnwrapKey(&crypto.RecipientWrappedKey{}, signKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+
+		_, err = c.WrapKey(nil, nil, nil, &crypto.PublicKey{}, crypto.WithSender(signKH))
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+	})
+
+	t.Run("key agreement only key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+		require.NoError(t, err)
+
+		kwKH := &kms.UsageConstrainedHandle{Handle: kh, Usage: kms.KeyUsageKeyAgreement}
+
+		_, err = c.Sign(msg, kwKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+
+		_, err = c.ComputeMAC(msg, kwKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+
+		_, err = c.SignMulti([][]byte{msg}, kwKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+
+		recipientKey, err := keyio.ExtractPrimaryPublicKey(kh)
+		require.NoError(t, err)
+
+		cek := random.GetRandomBytes(uint32(crypto.DefKeySize))
+
+		wrappedKey, err := c.WrapKey(cek, nil, nil, recipientKey)
+		require.NoError(t, err)
+
+		uCEK, err := c.UnwrapKey(wrappedKey, kwKH)
+		require.NoError(t, err)
+		require.EqualValues(t, cek, uCEK)
+	})
+
+	t.Run("encrypt only key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		encKH := &kms.UsageConstrainedHandle{Handle: kh, Usage: kms.KeyUsageEncrypt}
+
+		ct, nonce, err := c.Encrypt(msg, nil, encKH)
+		require.NoError(t, err)
+
+		pt, err := c.Decrypt(ct, nil, nonce, encKH)
+		require.NoError(t, err)
+		require.EqualValues(t, msg, pt)
+
+		err = c.VerifyMAC(nil, msg, encKH)
+		require.ErrorIs(t, err, kms.ErrKeyUsageNotAllowed)
+	})
+
+	t.Run("unconstrained handle", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.Sign(msg, &kms.UsageConstrainedHandle{Handle: kh})
+		require.NoError(t, err)
+
+		_, err = c.Sign(msg, &kms.UsageConstrainedHandle{Handle: "bad", Usage: kms.KeyUsageSign})
+		require.Equal(t, errBadKeyHandleFormat, err)
+	})
+}
+
 func TestCrypto_ECDHES_Wrap_Unwrap_ForAllKeyTypes(t *testing.T) {
 	tests := []struct {
 		tcName   string
@@ -0,0 +1,284 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	hybrid "github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
+	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// DeriveSharedSecret derives a secret shared with the owner of theirPubKey using ECDH key agreement with the private
+// key in kh followed by HKDF-SHA256 (https://tools.ietf.org/html/rfc5869) of the ECDH shared secret.
+// kh must be a NIST P curve or X25519 private key handle (eg created with kms.NISTP256ECDHKWType or
+// kms.X25519ECDHKWType) and theirPubKey must be of the same type and curve.
+// 'opts' allows setting the HKDF salt and info using WithSalt() and WithInfo() options and the size of the derived
+// secret using WithKeySize() option.
+// returns:
+// 		derived secret in []byte
+//		error in case of errors
+func (t *Crypto) DeriveSharedSecret(theirPubKey *cryptoapi.PublicKey, kh interface{},
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	if theirPubKey == nil {
+		return nil, errors.New("deriveSharedSecret: public key is required")
+	}
+
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	if pOpts.KeySize() <= 0 || pOpts.KeySize() > 255*sha256.Size {
+		return nil, fmt.Errorf("deriveSharedSecret: invalid key size %d", pOpts.KeySize())
+	}
+
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	privKey, err := extractPrivKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	z, err := t.ecdh(privKey, theirPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	secret := make([]byte, pOpts.KeySize())
+
+	_, err = io.ReadFull(hkdf.New(sha256.New, z, pOpts.Salt(), pOpts.Info()), secret)
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// Seal encrypts plaintext with aad for the recipient public key recPubKey using HPKE single-shot encryption as per
+// https://www.rfc-editor.org/rfc/rfc9180#section-6.1. The HPKE KEM is DHKEM(P-256, HKDF-SHA256) or
+// DHKEM(X25519, HKDF-SHA256) based on recPubKey, the KDF is HKDF-SHA256 and the AEAD is AES-256-GCM unless set
+// with the WithAEAD() option.
+// 'opts' allows setting the HPKE info with WithInfo(), a pre-shared key with WithPSK() (psk mode) and a sender private
+// key handle with WithAuthSender() (auth mode). The absence of these options uses the base mode.
+// returns:
+// 		encapsulated key in []byte, to be sent to the recipient along with the ciphertext
+// 		ciphertext in []byte
+//		error in case of errors
+func (t *Crypto) Seal(plaintext, aad []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, []byte, error) {
+	if recPubKey == nil {
+		return nil, nil, errors.New("seal: recipient public key is required")
+	}
+
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	kem, pkR, err := t.hpkePublicKey(recPubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: %w", err)
+	}
+
+	params := &hpke.Params{Info: pOpts.Info()}
+	params.PSK, params.PSKID = pOpts.PSK()
+
+	if pOpts.SenderKey() != nil {
+		senderKH, e := keysetHandle(pOpts.SenderKey(), kms.KeyUsageKeyAgreement)
+		if e != nil {
+			return nil, nil, fmt.Errorf("seal: %w", e)
+		}
+
+		_, params.SenderKey, e = hpkePrivateKey(senderKH)
+		if e != nil {
+			return nil, nil, fmt.Errorf("seal: sender key: %w", e)
+		}
+	}
+
+	suite := hpke.Suite{KEM: kem, KDF: hpke.HKDFSHA256, AEAD: pOpts.AEAD()}
+
+	enc, ct, err := hpke.Seal(suite, pkR, plaintext, aad, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seal: %w", err)
+	}
+
+	return enc, ct, nil
+}
+
+// Open decrypts ciphertext with aad and the encapsulated key enc using HPKE single-shot decryption with the recipient
+// private key handle kh, as per https://www.rfc-editor.org/rfc/rfc9180#section-6.1.
+// The options used by Seal() must be set here as well for successful decryption, except WithAuthSender() which must
+// be set with the sender public key (*crypto.PublicKey or public key handle) instead of the sender private key.
+// returns:
+// 		plaintext in []byte
+//		error in case of errors
+func (t *Crypto) Open(enc, ciphertext, aad []byte, kh interface{},
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	pOpts := cryptoapi.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	keyHandle, err := keysetHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	kem, skR, err := hpkePrivateKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	params := &hpke.Params{Info: pOpts.Info()}
+	params.PSK, params.PSKID = pOpts.PSK()
+
+	if pOpts.SenderKey() != nil {
+		params.SenderPublicKey, err = t.hpkeSenderPublicKey(pOpts.SenderKey())
+		if err != nil {
+			return nil, fmt.Errorf("open: sender key: %w", err)
+		}
+	}
+
+	suite := hpke.Suite{KEM: kem, KDF: hpke.HKDFSHA256, AEAD: pOpts.AEAD()}
+
+	pt, err := hpke.Open(suite, enc, skR, ciphertext, aad, params)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	return pt, nil
+}
+
+// ecdh returns the ECDH shared secret of privKey, as extracted by extractPrivKey(), and pubKey.
+func (t *Crypto) ecdh(privKey interface{}, pubKey *cryptoapi.PublicKey) ([]byte, error) {
+	switch key := privKey.(type) {
+	case *hybrid.ECPrivateKey:
+		if pubKey.Type != ecdhpb.KeyType_EC.String() {
+			return nil, errors.New("public key is not an EC key")
+		}
+
+		c, err := t.ecKW.getCurve(pubKey.Curve)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get curve of public key: %w", err)
+		}
+
+		if c != key.PublicKey.Curve {
+			return nil, errors.New("public key and private key are not on the same curve")
+		}
+
+		x, y := new(big.Int).SetBytes(pubKey.X), new(big.Int).SetBytes(pubKey.Y)
+		if !c.IsOnCurve(x, y) {
+			return nil, errors.New("public key is not on the curve")
+		}
+
+		zx, _ := c.ScalarMult(x, y, key.D.Bytes())
+
+		return zx.FillBytes(make([]byte, (c.Params().BitSize+7)/8)), nil //nolint:gomnd
+	case []byte:
+		if pubKey.Type != ecdhpb.KeyType_OKP.String() {
+			return nil, errors.New("public key is not an OKP key")
+		}
+
+		return curve25519.X25519(key, pubKey.X)
+	default:
+		return nil, errors.New("unsupported private key")
+	}
+}
+
+// hpkePublicKey returns the HPKE KEM and serialized public key of pubKey.
+func (t *Crypto) hpkePublicKey(pubKey *cryptoapi.PublicKey) (hpke.KEMID, []byte, error) {
+	switch pubKey.Type {
+	case ecdhpb.KeyType_EC.String():
+		c, err := t.ecKW.getCurve(pubKey.Curve)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to get curve of public key: %w", err)
+		}
+
+		if c != elliptic.P256() {
+			return 0, nil, fmt.Errorf("unsupported HPKE curve '%s'", pubKey.Curve)
+		}
+
+		return hpke.DHKEMP256HKDFSHA256, elliptic.Marshal(c, new(big.Int).SetBytes(pubKey.X),
+			new(big.Int).SetBytes(pubKey.Y)), nil
+	case ecdhpb.KeyType_OKP.String():
+		return hpke.DHKEMX25519HKDFSHA256, pubKey.X, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported HPKE key type '%s'", pubKey.Type)
+	}
+}
+
+// hpkeSenderPublicKey returns the serialized HPKE public key of senderKey, a *crypto.PublicKey or a key handle.
+func (t *Crypto) hpkeSenderPublicKey(senderKey interface{}) ([]byte, error) {
+	resolved, err := kms.ResolveKeyHandle(senderKey, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, ok := resolved.(*cryptoapi.PublicKey)
+	if !ok {
+		keyHandle, isKH := resolved.(*keyset.Handle)
+		if !isKH {
+			return nil, errBadKeyHandleFormat
+		}
+
+		pubKey, err = keyio.ExtractPrimaryPublicKey(keyHandle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, pk, err := t.hpkePublicKey(pubKey)
+
+	return pk, err
+}
+
+// hpkePrivateKey returns the HPKE KEM and private key of kh, a P-256 or X25519 private key handle.
+func hpkePrivateKey(kh *keyset.Handle) (hpke.KEMID, hpke.PrivateKey, error) {
+	privKey, err := extractPrivKey(kh)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch key := privKey.(type) {
+	case *hybrid.ECPrivateKey:
+		ecKey, e := hpke.NewP256PrivateKey(hybridECPrivToECDSAKey(key))
+		if e != nil {
+			return 0, nil, e
+		}
+
+		return hpke.DHKEMP256HKDFSHA256, ecKey, nil
+	case []byte:
+		x25519Key, e := hpke.NewX25519PrivateKey(key)
+		if e != nil {
+			return 0, nil, e
+		}
+
+		return hpke.DHKEMX25519HKDFSHA256, x25519Key, nil
+	default:
+		return 0, nil, errors.New("unsupported private key")
+	}
+}
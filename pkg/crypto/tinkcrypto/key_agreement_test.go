@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+func TestCrypto_DeriveSharedSecret(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"P-256", ecdh.NISTP256ECDHKWKeyTemplate()},
+		{"P-384", ecdh.NISTP384ECDHKWKeyTemplate()},
+		{"P-521", ecdh.NISTP521ECDHKWKeyTemplate()},
+		{"X25519", ecdh.X25519ECDHKWKeyTemplate()},
+	} {
+		template := tc.template
+
+		t.Run(tc.name, func(t *testing.T) {
+			aliceKH, alicePubKey := newKeyAgreementKey(t, template)
+			bobKH, bobPubKey := newKeyAgreementKey(t, template)
+
+			aliceSecret, err := c.DeriveSharedSecret(bobPubKey, aliceKH)
+			require.NoError(t, err)
+			require.Len(t, aliceSecret, cryptoapi.DefKeySize)
+
+			bobSecret, err := c.DeriveSharedSecret(alicePubKey, bobKH)
+			require.NoError(t, err)
+			require.Equal(t, aliceSecret, bobSecret)
+
+			opts := []cryptoapi.KeyAgreementOpts{
+				cryptoapi.WithSalt([]byte("salt")),
+				cryptoapi.WithInfo([]byte("channel key")),
+				cryptoapi.WithKeySize(64),
+			}
+
+			aliceChannelKey, err := c.DeriveSharedSecret(bobPubKey, aliceKH, opts...)
+			require.NoError(t, err)
+			require.Len(t, aliceChannelKey, 64)
+			require.NotEqual(t, aliceSecret, aliceChannelKey[:cryptoapi.DefKeySize])
+
+			bobChannelKey, err := c.DeriveSharedSecret(alicePubKey, bobKH, opts...)
+			require.NoError(t, err)
+			require.Equal(t, aliceChannelKey, bobChannelKey)
+		})
+	}
+
+	p256KH, p256PubKey := newKeyAgreementKey(t, ecdh.NISTP256ECDHKWKeyTemplate())
+	_, p384PubKey := newKeyAgreementKey(t, ecdh.NISTP384ECDHKWKeyTemplate())
+	x25519KH, x25519PubKey := newKeyAgreementKey(t, ecdh.X25519ECDHKWKeyTemplate())
+
+	t.Run("failures", func(t *testing.T) {
+		_, err = c.DeriveSharedSecret(nil, p256KH)
+		require.EqualError(t, err, "deriveSharedSecret: public key is required")
+
+		_, err = c.DeriveSharedSecret(p256PubKey, p256KH, cryptoapi.WithKeySize(0))
+		require.EqualError(t, err, "deriveSharedSecret: invalid key size 0")
+
+		_, err = c.DeriveSharedSecret(p256PubKey, "bad key handle")
+		require.EqualError(t, err, "deriveSharedSecret: bad key handle format")
+
+		aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.DeriveSharedSecret(p256PubKey, aeadKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deriveSharedSecret: extractPrivKey: can't extract unsupported private key")
+
+		_, err = c.DeriveSharedSecret(x25519PubKey, p256KH)
+		require.EqualError(t, err, "deriveSharedSecret: public key is not an EC key")
+
+		_, err = c.DeriveSharedSecret(p256PubKey, x25519KH)
+		require.EqualError(t, err, "deriveSharedSecret: public key is not an OKP key")
+
+		_, err = c.DeriveSharedSecret(p384PubKey, p256KH)
+		require.EqualError(t, err, "deriveSharedSecret: public key and private key are not on the same curve")
+
+		_, err = c.DeriveSharedSecret(&cryptoapi.PublicKey{Type: p256PubKey.Type, Curve: "bad curve"}, p256KH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deriveSharedSecret: failed to get curve of public key")
+
+		_, err = c.DeriveSharedSecret(&cryptoapi.PublicKey{
+			Type:  p256PubKey.Type,
+			Curve: p256PubKey.Curve,
+			X:     p256PubKey.X,
+			Y:     p256PubKey.X,
+		}, p256KH)
+		require.EqualError(t, err, "deriveSharedSecret: public key is not on the curve")
+
+		_, err = c.DeriveSharedSecret(p256PubKey, &kms.UsageConstrainedHandle{Handle: p256KH, Usage: kms.KeyUsageSign})
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+	})
+}
+
+func TestCrypto_Seal_Open(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum")
+	aad := []byte("aad")
+
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"P-256", ecdh.NISTP256ECDHKWKeyTemplate()},
+		{"X25519", ecdh.X25519ECDHKWKeyTemplate()},
+	} {
+		template := tc.template
+
+		t.Run(tc.name, func(t *testing.T) {
+			recKH, recPubKey := newKeyAgreementKey(t, template)
+			senderKH, senderPubKey := newKeyAgreementKey(t, template)
+
+			senderPubKH, err := senderKH.Public()
+			require.NoError(t, err)
+
+			psk := cryptoapi.WithPSK([]byte("0123456789abcdef0123456789abcdef"), []byte("psk id"))
+			info := cryptoapi.WithInfo([]byte("info"))
+			chacha := cryptoapi.WithAEAD(hpke.ChaCha20Poly1305)
+
+			for _, mode := range []struct {
+				name     string
+				sealOpts []cryptoapi.KeyAgreementOpts
+				openOpts []cryptoapi.KeyAgreementOpts
+			}{
+				{name: "base"},
+				{
+					name:     "base with info and ChaCha20Poly1305",
+					sealOpts: []cryptoapi.KeyAgreementOpts{info, chacha},
+					openOpts: []cryptoapi.KeyAgreementOpts{info, chacha},
+				},
+				{
+					name:     "psk",
+					sealOpts: []cryptoapi.KeyAgreementOpts{psk},
+					openOpts: []cryptoapi.KeyAgreementOpts{psk},
+				},
+				{
+					name:     "auth with sender public key",
+					sealOpts: []cryptoapi.KeyAgreementOpts{cryptoapi.WithAuthSender(senderKH)},
+					openOpts: []cryptoapi.KeyAgreementOpts{cryptoapi.WithAuthSender(senderPubKey)},
+				},
+				{
+					name:     "auth_psk with sender public key handle",
+					sealOpts: []cryptoapi.KeyAgreementOpts{cryptoapi.WithAuthSender(senderKH), psk},
+					openOpts: []cryptoapi.KeyAgreementOpts{cryptoapi.WithAuthSender(senderPubKH), psk},
+				},
+			} {
+				enc, ct, err := c.Seal(msg, aad, recPubKey, mode.sealOpts...)
+				require.NoError(t, err, mode.name)
+
+				pt, err := c.Open(enc, ct, aad, recKH, mode.openOpts...)
+				require.NoError(t, err, mode.name)
+				require.Equal(t, msg, pt, mode.name)
+
+				_, err = c.Open(enc, ct, []byte("other aad"), recKH, mode.openOpts...)
+				require.Error(t, err, mode.name)
+
+				if len(mode.openOpts) > 0 {
+					_, err = c.Open(enc, ct, aad, recKH)
+					require.Error(t, err, mode.name)
+				}
+			}
+		})
+	}
+
+	p256KH, p256PubKey := newKeyAgreementKey(t, ecdh.NISTP256ECDHKWKeyTemplate())
+	p384KH, p384PubKey := newKeyAgreementKey(t, ecdh.NISTP384ECDHKWKeyTemplate())
+	x25519KH, x25519PubKey := newKeyAgreementKey(t, ecdh.X25519ECDHKWKeyTemplate())
+
+	t.Run("seal failures", func(t *testing.T) {
+		_, _, err = c.Seal(msg, aad, nil)
+		require.EqualError(t, err, "seal: recipient public key is required")
+
+		_, _, err = c.Seal(msg, aad, p384PubKey)
+		require.EqualError(t, err, "seal: unsupported HPKE curve 'NIST_P384'")
+
+		_, _, err = c.Seal(msg, aad, &cryptoapi.PublicKey{Type: "EC", Curve: "bad curve"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "seal: failed to get curve of public key")
+
+		_, _, err = c.Seal(msg, aad, &cryptoapi.PublicKey{Type: "RSA"})
+		require.EqualError(t, err, "seal: unsupported HPKE key type 'RSA'")
+
+		_, _, err = c.Seal(msg, aad, p256PubKey, cryptoapi.WithAuthSender("bad key handle"))
+		require.EqualError(t, err, "seal: bad key handle format")
+
+		_, _, err = c.Seal(msg, aad, p256PubKey, cryptoapi.WithAuthSender(p384KH))
+		require.EqualError(t, err, "seal: sender key: private key is not a P-256 key")
+
+		_, _, err = c.Seal(msg, aad, p256PubKey, cryptoapi.WithAuthSender(x25519KH))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "seal: setupSender: failed to compute sender DH")
+
+		_, _, err = c.Seal(msg, aad, p256PubKey, cryptoapi.WithAEAD(0xFFFF))
+		require.EqualError(t, err, "seal: setupSender: unsupported AEAD")
+	})
+
+	t.Run("open failures", func(t *testing.T) {
+		enc, ct, err := c.Seal(msg, aad, p256PubKey)
+		require.NoError(t, err)
+
+		_, err = c.Open(enc, ct, aad, "bad key handle")
+		require.EqualError(t, err, "open: bad key handle format")
+
+		_, err = c.Open(enc, ct, aad, p384KH)
+		require.EqualError(t, err, "open: private key is not a P-256 key")
+
+		_, err = c.Open(enc, ct, aad, x25519KH)
+		require.EqualError(t, err, "open: setupRecipient: invalid encapsulated key: not a X25519 public key")
+
+		_, err = c.Open(enc, ct, aad, p256KH, cryptoapi.WithAuthSender("bad key handle"))
+		require.EqualError(t, err, "open: sender key: bad key handle format")
+
+		_, err = c.Open(enc, ct, aad, p256KH, cryptoapi.WithAuthSender(x25519PubKey))
+		require.EqualError(t, err, "open: setupRecipient: invalid sender public key: "+
+			"not an uncompressed P-256 public key")
+
+		_, err = c.Open(enc, ct, aad, &kms.UsageConstrainedHandle{Handle: p256KH, Usage: kms.KeyUsageEncrypt})
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+	})
+}
+
+func newKeyAgreementKey(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *cryptoapi.PublicKey) {
+	t.Helper()
+
+	kh, err := keyset.NewHandle(template)
+	require.NoError(t, err)
+
+	pubKey, err := keyio.ExtractPrimaryPublicKey(kh)
+	require.NoError(t, err)
+
+	return kh, pubKey
+}
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package tinkcrypto
 
 import (
-	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ecdsa"
@@ -274,21 +273,5 @@ func derive1Pu(kwAlg string, ze, zs, apu, apv []byte, keySize int) []byte {
 	z := append(round1, ze...)
 	z = append(z, zs...)
 
-	algID := cryptoutil.LengthPrefix([]byte(kwAlg))
-	ptyUInfo := cryptoutil.LengthPrefix(apu)
-	ptyVInfo := cryptoutil.LengthPrefix(apv)
-
-	supPubLen := 4
-	supPubInfo := make([]byte, supPubLen)
-
-	byteLen := 8
-	binary.BigEndian.PutUint32(supPubInfo, uint32(keySize)*uint32(byteLen))
-
-	reader := josecipher.NewConcatKDF(crypto.SHA256, z, algID, ptyUInfo, ptyVInfo, supPubInfo, []byte{})
-
-	kek := make([]byte, keySize)
-
-	_, _ = reader.Read(kek) // nolint:errcheck // ConcatKDF's Read() never returns an error
-
-	return kek
+	return cryptoutil.DeriveKEK([]byte(kwAlg), apu, apv, z, keySize)
 }
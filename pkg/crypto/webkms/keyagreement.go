@@ -0,0 +1,303 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+)
+
+// keyAgreementReq serializable key agreement options of DeriveSharedSecret, Seal and Open requests.
+type keyAgreementReq struct {
+	Salt    string `json:"salt,omitempty"`
+	Info    string `json:"info,omitempty"`
+	KeySize int    `json:"keySize,omitempty"`
+	PSK     string `json:"psk,omitempty"`
+	PSKID   string `json:"pskID,omitempty"`
+	AEAD    uint16 `json:"aead,omitempty"`
+}
+
+// deriveSharedSecretReq serializable DeriveSharedSecret request.
+type deriveSharedSecretReq struct {
+	keyAgreementReq
+	PubKey publicKeyReq `json:"pubKey,omitempty"`
+}
+
+// deriveSharedSecretResp serializable DeriveSharedSecret response.
+type deriveSharedSecretResp struct {
+	Secret string `json:"secret,omitempty"`
+}
+
+// sealReq serializable Seal request.
+type sealReq struct {
+	keyAgreementReq
+	Message        string       `json:"message,omitempty"`
+	AdditionalData string       `json:"aad,omitempty"`
+	RecPubKey      publicKeyReq `json:"recPubKey,omitempty"`
+	SenderKID      string       `json:"senderKID,omitempty"`
+}
+
+// sealResp serializable Seal response.
+type sealResp struct {
+	Enc        string `json:"enc,omitempty"`
+	CipherText string `json:"cipherText,omitempty"`
+}
+
+// openReq serializable Open request.
+type openReq struct {
+	keyAgreementReq
+	Enc            string        `json:"enc,omitempty"`
+	CipherText     string        `json:"cipherText,omitempty"`
+	AdditionalData string        `json:"aad,omitempty"`
+	SenderKID      string        `json:"senderKID,omitempty"`
+	SenderPubKey   *publicKeyReq `json:"senderPubKey,omitempty"`
+}
+
+// openResp serializable Open response.
+type openResp struct {
+	PlainText string `json:"plainText,omitempty"`
+}
+
+// DeriveSharedSecret will remotely derive a secret shared with the owner of theirPubKey using ECDH key agreement with
+// the private key found at keyURL followed by HKDF-SHA256 of the ECDH shared secret.
+// 'opts' allows setting the HKDF salt and info using WithSalt() and WithInfo() options and the size of the derived
+// secret using WithKeySize() option.
+// returns:
+// 		derived secret in []byte
+//		error in case of errors
+func (r *RemoteCrypto) DeriveSharedSecret(theirPubKey *crypto.PublicKey, keyURL interface{},
+	opts ...crypto.KeyAgreementOpts) ([]byte, error) {
+	startDerive := time.Now()
+	destination := fmt.Sprintf("%s", keyURL) + deriveSharedSecretURI
+
+	if theirPubKey == nil {
+		return nil, errors.New("deriveSharedSecret: public key is required")
+	}
+
+	httpReqBytes, err := r.marshalFunc(&deriveSharedSecretReq{
+		keyAgreementReq: keyAgreementOptsToSerializableReq(opts...),
+		PubKey:          pubKeyToSerializableReq(theirPubKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal deriveSharedSecretReq for DeriveSharedSecret failed [%s, %w]",
+			destination, err)
+	}
+
+	respBody, err := r.postKeyAgreementRequest(destination, httpReqBytes, "DeriveSharedSecret")
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp := &deriveSharedSecretResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal deriveSharedSecretResp for DeriveSharedSecret failed [%s, %w]",
+			destination, err)
+	}
+
+	secret, err := base64.URLEncoding.DecodeString(httpResp.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("decode secret for DeriveSharedSecret failed [%s, %w]", destination, err)
+	}
+
+	// TODO switch to Debug once perf testing with remote server is done.
+	logger.Infof("overall DeriveSharedSecret duration: %s", time.Since(startDerive))
+
+	return secret, nil
+}
+
+// Seal encrypts plaintext with aad for the recipient public key recPubKey using HPKE single-shot encryption.
+// 'opts' allows setting the HPKE info with WithInfo(), a pre-shared key with WithPSK() and the AEAD with WithAEAD().
+// Sealing only requires the recipient public key, it is executed locally so that plaintext is not sent to the remote
+// server, unless the WithAuthSender() option sets the keyURL of a remote sender key for the HPKE auth mode.
+// returns:
+// 		encapsulated key in []byte, to be sent to the recipient along with the ciphertext
+// 		ciphertext in []byte
+//		error in case of errors
+func (r *RemoteCrypto) Seal(plaintext, aad []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.KeyAgreementOpts) ([]byte, []byte, error) {
+	pOpts := crypto.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	senderKID := keyIDFromURL(pOpts.SenderKey())
+	if senderKID == "" {
+		localCrypto, err := tinkcrypto.New()
+		if err != nil {
+			return nil, nil, fmt.Errorf("seal: %w", err)
+		}
+
+		return localCrypto.Seal(plaintext, aad, recPubKey, opts...)
+	}
+
+	if recPubKey == nil {
+		return nil, nil, errors.New("seal: recipient public key is required")
+	}
+
+	startSeal := time.Now()
+	destination := r.keystoreURL + sealURI
+
+	httpReqBytes, err := r.marshalFunc(&sealReq{
+		keyAgreementReq: keyAgreementOptsToSerializableReq(opts...),
+		Message:         base64.URLEncoding.EncodeToString(plaintext),
+		AdditionalData:  base64.URLEncoding.EncodeToString(aad),
+		RecPubKey:       pubKeyToSerializableReq(recPubKey),
+		SenderKID:       senderKID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal sealReq for Seal failed [%s, %w]", destination, err)
+	}
+
+	respBody, err := r.postKeyAgreementRequest(destination, httpReqBytes, "Seal")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpResp := &sealResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal sealResp for Seal failed [%s, %w]", destination, err)
+	}
+
+	enc, err := base64.URLEncoding.DecodeString(httpResp.Enc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode encapsulated key for Seal failed [%s, %w]", destination, err)
+	}
+
+	ct, err := base64.URLEncoding.DecodeString(httpResp.CipherText)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode ciphertext for Seal failed [%s, %w]", destination, err)
+	}
+
+	// TODO switch to Debug once perf testing with remote server is done.
+	logger.Infof("overall Seal duration: %s", time.Since(startSeal))
+
+	return enc, ct, nil
+}
+
+// Open will remotely decrypt ciphertext with aad and the encapsulated key enc using HPKE single-shot decryption with
+// the recipient private key found at keyURL.
+// The options used by Seal() must be set here as well for successful decryption, except WithAuthSender() which must
+// be set with the sender public key as *crypto.PublicKey or the keyURL of a remote sender key.
+// returns:
+// 		plaintext in []byte
+//		error in case of errors
+func (r *RemoteCrypto) Open(enc, ciphertext, aad []byte, keyURL interface{},
+	opts ...crypto.KeyAgreementOpts) ([]byte, error) {
+	startOpen := time.Now()
+	destination := fmt.Sprintf("%s", keyURL) + openURI
+
+	pOpts := crypto.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	oReq := &openReq{
+		keyAgreementReq: keyAgreementOptsToSerializableReq(opts...),
+		Enc:             base64.URLEncoding.EncodeToString(enc),
+		CipherText:      base64.URLEncoding.EncodeToString(ciphertext),
+		AdditionalData:  base64.URLEncoding.EncodeToString(aad),
+	}
+
+	if senderPubKey, ok := pOpts.SenderKey().(*crypto.PublicKey); ok {
+		senderPubKeyReq := pubKeyToSerializableReq(senderPubKey)
+		oReq.SenderPubKey = &senderPubKeyReq
+	} else {
+		oReq.SenderKID = keyIDFromURL(pOpts.SenderKey())
+	}
+
+	httpReqBytes, err := r.marshalFunc(oReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal openReq for Open failed [%s, %w]", destination, err)
+	}
+
+	respBody, err := r.postKeyAgreementRequest(destination, httpReqBytes, "Open")
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp := &openResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal openResp for Open failed [%s, %w]", destination, err)
+	}
+
+	plaintext, err := base64.URLEncoding.DecodeString(httpResp.PlainText)
+	if err != nil {
+		return nil, fmt.Errorf("decode plaintext for Open failed [%s, %w]", destination, err)
+	}
+
+	// TODO switch to Debug once perf testing with remote server is done.
+	logger.Infof("overall Open duration: %s", time.Since(startOpen))
+
+	return plaintext, nil
+}
+
+func (r *RemoteCrypto) postKeyAgreementRequest(destination string, httpReqBytes []byte, action string) ([]byte,
+	error) {
+	resp, err := r.postHTTPRequest(destination, httpReqBytes)
+	if err != nil {
+		return nil, fmt.Errorf("posting %s failed [%s, %w]", action, destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, logger, action)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response for %s failed [%s, %w]", action, destination, err)
+	}
+
+	return respBody, nil
+}
+
+// keyIDFromURL returns the key ID of keyURL, or an empty string if keyURL is not set.
+func keyIDFromURL(keyURL interface{}) string {
+	if keyURL == nil {
+		return ""
+	}
+
+	keyURLStr := fmt.Sprintf("%s", keyURL)
+	if keyURLStr == "" {
+		return ""
+	}
+
+	return keyURLStr[strings.LastIndex(keyURLStr, keysURI)+len(keysURI):]
+}
+
+// keyAgreementOptsToSerializableReq converts key agreement options into a serializable keyAgreementReq.
+func keyAgreementOptsToSerializableReq(opts ...crypto.KeyAgreementOpts) keyAgreementReq {
+	pOpts := crypto.NewKeyAgreementOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	psk, pskID := pOpts.PSK()
+
+	return keyAgreementReq{
+		Salt:    base64.URLEncoding.EncodeToString(pOpts.Salt()),
+		Info:    base64.URLEncoding.EncodeToString(pOpts.Info()),
+		KeySize: pOpts.KeySize(),
+		PSK:     base64.URLEncoding.EncodeToString(psk),
+		PSKID:   base64.URLEncoding.EncodeToString(pskID),
+		AEAD:    uint16(pOpts.AEAD()),
+	}
+}
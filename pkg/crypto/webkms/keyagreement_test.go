@@ -0,0 +1,335 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/hpke"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
+	webkmsimpl "github.com/hyperledger/aries-framework-go/pkg/kms/webkms"
+)
+
+func TestDeriveSharedSecret(t *testing.T) {
+	keyKH, err := keyset.NewHandle(ecdh.X25519ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	keyPubKey, err := exportPubKey(keyKH)
+	require.NoError(t, err)
+
+	peerKH, err := keyset.NewHandle(ecdh.X25519ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	peerPubKey, err := exportPubKey(peerKH)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newKeyAgreementHandler(t, nil, keyKH))
+	defer server.Close()
+
+	defaultKeystoreURL, defaultKeyURL := keyAgreementURLs(server.URL)
+	rCrypto := New(defaultKeystoreURL, server.Client())
+
+	localCrypto, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	opts := []crypto.KeyAgreementOpts{
+		crypto.WithSalt([]byte("salt")),
+		crypto.WithInfo([]byte("info")),
+		crypto.WithKeySize(48),
+	}
+
+	secret, err := rCrypto.DeriveSharedSecret(peerPubKey, defaultKeyURL, opts...)
+	require.NoError(t, err)
+	require.Len(t, secret, 48)
+
+	peerSecret, err := localCrypto.DeriveSharedSecret(keyPubKey, peerKH, opts...)
+	require.NoError(t, err)
+	require.Equal(t, peerSecret, secret)
+
+	t.Run("DeriveSharedSecret failures", func(t *testing.T) {
+		_, err = rCrypto.DeriveSharedSecret(nil, defaultKeyURL)
+		require.EqualError(t, err, "deriveSharedSecret: public key is required")
+
+		tmpCrypto := New(defaultKeystoreURL, server.Client())
+		tmpCrypto.marshalFunc = failingMarshal
+
+		_, err = tmpCrypto.DeriveSharedSecret(peerPubKey, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("marshal deriveSharedSecretReq for DeriveSharedSecret failed [%s, %w]",
+			defaultKeyURL+deriveSharedSecretURI, errFailingMarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, server.Client())
+		tmpCrypto.unmarshalFunc = failingUnmarshal
+
+		_, err = tmpCrypto.DeriveSharedSecret(peerPubKey, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("unmarshal deriveSharedSecretResp for DeriveSharedSecret failed "+
+			"[%s, %w]", defaultKeyURL+deriveSharedSecretURI, errFailingUnmarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, server.Client(), webkmsimpl.WithHeaders(mockAddHeadersFuncError))
+
+		_, err = tmpCrypto.DeriveSharedSecret(peerPubKey, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("posting DeriveSharedSecret failed [%s, add optional request "+
+			"headers error: %w]", defaultKeyURL+deriveSharedSecretURI, errAddHeadersFunc).Error())
+	})
+}
+
+func TestSealOpen(t *testing.T) {
+	msg := []byte("lorem ipsum")
+	aad := []byte("aad")
+
+	recipientKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	recipientPubKey, err := exportPubKey(recipientKH)
+	require.NoError(t, err)
+
+	senderKH, err := keyset.NewHandle(ecdh.NISTP256ECDHKWKeyTemplate())
+	require.NoError(t, err)
+
+	senderPubKey, err := exportPubKey(senderKH)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newKeyAgreementHandler(t, senderKH, recipientKH))
+	defer server.Close()
+
+	defaultKeystoreURL, defaultKeyURL := keyAgreementURLs(server.URL)
+	senderKeyURL := defaultKeystoreURL + "/keys/11111"
+	rCrypto := New(defaultKeystoreURL, server.Client())
+
+	t.Run("Seal locally and Open remotely", func(t *testing.T) {
+		// a failing client ensures sealing is not executed by the server.
+		localSealCrypto := New(defaultKeystoreURL, &http.Client{Transport: failingTransport{}})
+
+		psk := crypto.WithPSK([]byte("0123456789abcdef0123456789abcdef"), []byte("psk id"))
+		opts := []crypto.KeyAgreementOpts{crypto.WithInfo([]byte("info")), psk, crypto.WithAEAD(hpke.ChaCha20Poly1305)}
+
+		enc, ct, err := localSealCrypto.Seal(msg, aad, recipientPubKey, opts...)
+		require.NoError(t, err)
+
+		pt, err := rCrypto.Open(enc, ct, aad, defaultKeyURL, opts...)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		_, err = rCrypto.Open(enc, ct, aad, defaultKeyURL)
+		require.Error(t, err)
+	})
+
+	t.Run("Seal and Open with a remote sender key (auth mode)", func(t *testing.T) {
+		enc, ct, err := rCrypto.Seal(msg, aad, recipientPubKey, crypto.WithAuthSender(senderKeyURL))
+		require.NoError(t, err)
+
+		pt, err := rCrypto.Open(enc, ct, aad, defaultKeyURL, crypto.WithAuthSender(senderKeyURL))
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		pt, err = rCrypto.Open(enc, ct, aad, defaultKeyURL, crypto.WithAuthSender(senderPubKey))
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+	})
+
+	t.Run("Seal failures", func(t *testing.T) {
+		_, _, err = rCrypto.Seal(msg, aad, nil)
+		require.EqualError(t, err, "seal: recipient public key is required")
+
+		_, _, err = rCrypto.Seal(msg, aad, nil, crypto.WithAuthSender(senderKeyURL))
+		require.EqualError(t, err, "seal: recipient public key is required")
+
+		tmpCrypto := New(defaultKeystoreURL, server.Client())
+		tmpCrypto.marshalFunc = failingMarshal
+
+		_, _, err = tmpCrypto.Seal(msg, aad, recipientPubKey, crypto.WithAuthSender(senderKeyURL))
+		require.EqualError(t, err, fmt.Errorf("marshal sealReq for Seal failed [%s, %w]",
+			defaultKeystoreURL+sealURI, errFailingMarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, server.Client())
+		tmpCrypto.unmarshalFunc = failingUnmarshal
+
+		_, _, err = tmpCrypto.Seal(msg, aad, recipientPubKey, crypto.WithAuthSender(senderKeyURL))
+		require.EqualError(t, err, fmt.Errorf("unmarshal sealResp for Seal failed [%s, %w]",
+			defaultKeystoreURL+sealURI, errFailingUnmarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, &http.Client{Transport: failingTransport{}})
+
+		_, _, err = tmpCrypto.Seal(msg, aad, recipientPubKey, crypto.WithAuthSender(senderKeyURL))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "posting Seal failed")
+	})
+
+	t.Run("Open failures", func(t *testing.T) {
+		enc, ct, err := rCrypto.Seal(msg, aad, recipientPubKey)
+		require.NoError(t, err)
+
+		tmpCrypto := New(defaultKeystoreURL, server.Client())
+		tmpCrypto.marshalFunc = failingMarshal
+
+		_, err = tmpCrypto.Open(enc, ct, aad, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("marshal openReq for Open failed [%s, %w]",
+			defaultKeyURL+openURI, errFailingMarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, server.Client())
+		tmpCrypto.unmarshalFunc = failingUnmarshal
+
+		_, err = tmpCrypto.Open(enc, ct, aad, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("unmarshal openResp for Open failed [%s, %w]",
+			defaultKeyURL+openURI, errFailingUnmarshal).Error())
+
+		tmpCrypto = New(defaultKeystoreURL, &http.Client{Transport: failingTransport{}})
+
+		_, err = tmpCrypto.Open(enc, ct, aad, defaultKeyURL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "posting Open failed")
+	})
+}
+
+func TestKeyIDFromURL(t *testing.T) {
+	require.Empty(t, keyIDFromURL(nil))
+	require.Empty(t, keyIDFromURL(""))
+	require.Equal(t, "/123", keyIDFromURL("https://localhost/kms/keystores/456/keys/123"))
+}
+
+var errFailingTransport = errors.New("failing transport")
+
+type failingTransport struct{}
+
+func (f failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errFailingTransport
+}
+
+func keyAgreementURLs(serverURL string) (string, string) {
+	keystoreURL := fmt.Sprintf("%s/%s", strings.ReplaceAll(webkmsimpl.KeystoreEndpoint,
+		"{serverEndpoint}", serverURL), defaultKeyStoreID)
+
+	return keystoreURL, keystoreURL + "/keys/" + defaultKID
+}
+
+// newKeyAgreementHandler mocks the key server key agreement endpoints with the sender key of the keystore and the
+// key found at the default key URL.
+func newKeyAgreementHandler(t *testing.T, senderKH, keyKH *keyset.Handle) http.Handler {
+	t.Helper()
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var resp interface{}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, deriveSharedSecretURI):
+			resp = deriveSharedSecretPOSTHandle(t, reqBody, keyKH, cr)
+		case strings.HasSuffix(r.URL.Path, sealURI):
+			resp = sealPOSTHandle(t, reqBody, senderKH, cr)
+		case strings.HasSuffix(r.URL.Path, openURI):
+			resp = openPOSTHandle(t, reqBody, senderKH, keyKH, cr)
+		}
+
+		if resp == nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		mResp, err := json.Marshal(resp)
+		require.NoError(t, err)
+
+		_, err = w.Write(mResp)
+		require.NoError(t, err)
+	})
+}
+
+func deriveSharedSecretPOSTHandle(t *testing.T, reqBody []byte, keyKH *keyset.Handle,
+	cr crypto.Crypto) interface{} {
+	req := &deriveSharedSecretReq{}
+	require.NoError(t, json.Unmarshal(reqBody, req))
+
+	pubKey, err := serializableReqToPubKey(&req.PubKey)
+	require.NoError(t, err)
+
+	secret, err := cr.DeriveSharedSecret(pubKey, keyKH, serializableReqToKeyAgreementOpts(t, &req.keyAgreementReq)...)
+	require.NoError(t, err)
+
+	return &deriveSharedSecretResp{Secret: base64.URLEncoding.EncodeToString(secret)}
+}
+
+func sealPOSTHandle(t *testing.T, reqBody []byte, senderKH *keyset.Handle, cr crypto.Crypto) interface{} {
+	req := &sealReq{}
+	require.NoError(t, json.Unmarshal(reqBody, req))
+	require.NotEmpty(t, req.SenderKID)
+
+	recPubKey, err := serializableReqToPubKey(&req.RecPubKey)
+	require.NoError(t, err)
+
+	opts := append(serializableReqToKeyAgreementOpts(t, &req.keyAgreementReq), crypto.WithAuthSender(senderKH))
+
+	enc, ct, err := cr.Seal(decodeReqField(t, req.Message), decodeReqField(t, req.AdditionalData), recPubKey, opts...)
+	require.NoError(t, err)
+
+	return &sealResp{
+		Enc:        base64.URLEncoding.EncodeToString(enc),
+		CipherText: base64.URLEncoding.EncodeToString(ct),
+	}
+}
+
+func openPOSTHandle(t *testing.T, reqBody []byte, senderKH, keyKH *keyset.Handle, cr crypto.Crypto) interface{} {
+	req := &openReq{}
+	require.NoError(t, json.Unmarshal(reqBody, req))
+
+	opts := serializableReqToKeyAgreementOpts(t, &req.keyAgreementReq)
+
+	if req.SenderKID != "" {
+		senderPubKH, err := senderKH.Public()
+		require.NoError(t, err)
+
+		opts = append(opts, crypto.WithAuthSender(senderPubKH))
+	}
+
+	if req.SenderPubKey != nil {
+		senderPubKey, err := serializableReqToPubKey(req.SenderPubKey)
+		require.NoError(t, err)
+
+		opts = append(opts, crypto.WithAuthSender(senderPubKey))
+	}
+
+	pt, err := cr.Open(decodeReqField(t, req.Enc), decodeReqField(t, req.CipherText),
+		decodeReqField(t, req.AdditionalData), keyKH, opts...)
+	if err != nil {
+		return nil
+	}
+
+	return &openResp{PlainText: base64.URLEncoding.EncodeToString(pt)}
+}
+
+func serializableReqToKeyAgreementOpts(t *testing.T, req *keyAgreementReq) []crypto.KeyAgreementOpts {
+	return []crypto.KeyAgreementOpts{
+		crypto.WithSalt(decodeReqField(t, req.Salt)),
+		crypto.WithInfo(decodeReqField(t, req.Info)),
+		crypto.WithKeySize(req.KeySize),
+		crypto.WithPSK(decodeReqField(t, req.PSK), decodeReqField(t, req.PSKID)),
+		crypto.WithAEAD(hpke.AEADID(req.AEAD)),
+	}
+}
+
+func decodeReqField(t *testing.T, field string) []byte {
+	value, err := base64.URLEncoding.DecodeString(field)
+	require.NoError(t, err)
+
+	return value
+}
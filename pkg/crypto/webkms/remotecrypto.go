@@ -135,6 +135,11 @@ const (
 	verifyMultiURI = "/verifymulti"
 	deriveProofURI = "/deriveproof"
 	verifyProofURI = "/verifyproof"
+
+	// key agreement endpoints.
+	deriveSharedSecretURI = "/derivesharedsecret"
+	sealURI               = "/seal"
+	openURI               = "/open"
 )
 
 // New creates a new remoteCrypto instance using http client connecting to keystoreURL.
@@ -18,3 +18,16 @@ type ProblemReport struct {
 type Code struct {
 	Code string `json:"code"`
 }
+
+// ProblemReportV2 problem report definition (DIDComm V2).
+type ProblemReportV2 struct {
+	ID   string              `json:"id,omitempty"`
+	Type string              `json:"type,omitempty"`
+	Body ProblemReportV2Body `json:"body,omitempty"`
+}
+
+// ProblemReportV2Body represents body for ProblemReportV2.
+type ProblemReportV2Body struct {
+	Code    string `json:"code,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
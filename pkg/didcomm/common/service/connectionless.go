@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+const (
+	jsonService  = "~service"
+	didKeyPrefix = "did:key:"
+)
+
+// ServiceDecorator returns the ~service decorator of the message sent without a connection.
+// It returns nil if the message does not carry the decorator.
+func (m DIDCommMsgMap) ServiceDecorator() (*decorator.Service, error) {
+	if m == nil || m[jsonService] == nil {
+		return nil, nil
+	}
+
+	src, err := json.Marshal(m[jsonService])
+	if err != nil {
+		return nil, fmt.Errorf("marshal service decorator: %w", err)
+	}
+
+	svc := &decorator.Service{}
+
+	if err = json.Unmarshal(src, svc); err != nil {
+		return nil, fmt.Errorf("unmarshal service decorator: %w", err)
+	}
+
+	if len(svc.RecipientKeys) == 0 || svc.ServiceEndpoint == "" {
+		return nil, errors.New("service decorator: recipient keys and service endpoint are required")
+	}
+
+	return svc, nil
+}
+
+// SetServiceDecorator sets the ~service decorator of the message, the recipient replies to the given service.
+func (m DIDCommMsgMap) SetServiceDecorator(svc *decorator.Service) {
+	m[jsonService] = map[string]interface{}{
+		"recipientKeys":   svc.RecipientKeys,
+		"routingKeys":     svc.RoutingKeys,
+		"serviceEndpoint": svc.ServiceEndpoint,
+	}
+}
+
+// IsConnectionless returns true if the message is exchanged without a connection,
+// i.e there is no DID of the other agent and the message carries the ~service decorator.
+func IsConnectionless(msg DIDCommMsgMap, theirDID string) bool {
+	if theirDID != "" {
+		return false
+	}
+
+	svc, err := msg.ServiceDecorator()
+
+	return err == nil && svc != nil
+}
+
+// NewConnectionlessService creates the ~service decorator of a connectionless message with a new key.
+// The recipient of the message replies to the endpoint and packs the reply for the new key.
+func NewConnectionlessService(km kms.KeyManager, endpoint string) (*decorator.Service, error) {
+	_, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	if err != nil {
+		return nil, fmt.Errorf("create connectionless key: %w", err)
+	}
+
+	// fingerprint.CreateDIDKey does not validate the key, check it before encoding.
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("create connectionless did:key: invalid ED25519 public key size %d", len(pubKey))
+	}
+
+	didKey, _ := fingerprint.CreateDIDKey(pubKey)
+
+	return &decorator.Service{
+		RecipientKeys:   []string{didKey},
+		ServiceEndpoint: endpoint,
+	}, nil
+}
+
+// ServiceDestination returns the destination of the reply to a message carrying the ~service decorator.
+// Both did:key and raw base58 keys (used by the other frameworks) are supported.
+func ServiceDestination(svc *decorator.Service) *Destination {
+	return &Destination{
+		RecipientKeys:   toDIDKeys(svc.RecipientKeys),
+		RoutingKeys:     toDIDKeys(svc.RoutingKeys),
+		ServiceEndpoint: svc.ServiceEndpoint,
+	}
+}
+
+func toDIDKeys(keys []string) []string {
+	var didKeys []string
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, didKeyPrefix) {
+			key, _ = fingerprint.CreateDIDKey(base58.Decode(key))
+		}
+
+		didKeys = append(didKeys, key)
+	}
+
+	return didKeys
+}
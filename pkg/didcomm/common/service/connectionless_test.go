@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+func TestServiceDecorator(t *testing.T) {
+	t.Run("set and get", func(t *testing.T) {
+		svc := &decorator.Service{
+			RecipientKeys:   []string{"did:key:123"},
+			RoutingKeys:     []string{"did:key:456"},
+			ServiceEndpoint: "https://example.com",
+		}
+
+		msg := DIDCommMsgMap{jsonID: "id"}
+		msg.SetServiceDecorator(svc)
+
+		result, err := msg.ServiceDecorator()
+		require.NoError(t, err)
+		require.Equal(t, svc, result)
+		require.True(t, IsConnectionless(msg, ""))
+		require.False(t, IsConnectionless(msg, "did:example:their"))
+	})
+
+	t.Run("no decorator", func(t *testing.T) {
+		result, err := DIDCommMsgMap{jsonID: "id"}.ServiceDecorator()
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.False(t, IsConnectionless(DIDCommMsgMap{jsonID: "id"}, ""))
+	})
+
+	t.Run("invalid decorator", func(t *testing.T) {
+		_, err := DIDCommMsgMap{jsonService: "service"}.ServiceDecorator()
+		require.Contains(t, err.Error(), "unmarshal service decorator")
+
+		_, err = DIDCommMsgMap{jsonService: map[string]interface{}{}}.ServiceDecorator()
+		require.EqualError(t, err, "service decorator: recipient keys and service endpoint are required")
+	})
+}
+
+func TestNewConnectionlessService(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		pubKey := make([]byte, ed25519.PublicKeySize)
+
+		svc, err := NewConnectionlessService(&mockkms.KeyManager{CrAndExportPubKeyValue: pubKey},
+			"https://example.com")
+		require.NoError(t, err)
+
+		didKey, _ := fingerprint.CreateDIDKey(pubKey)
+		require.Equal(t, []string{didKey}, svc.RecipientKeys)
+		require.Equal(t, "https://example.com", svc.ServiceEndpoint)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := NewConnectionlessService(&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New("error")}, "")
+		require.EqualError(t, err, "create connectionless key: error")
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		_, err := NewConnectionlessService(&mockkms.KeyManager{CrAndExportPubKeyValue: []byte("key")}, "")
+		require.EqualError(t, err, "create connectionless did:key: invalid ED25519 public key size 3")
+	})
+}
+
+func TestServiceDestination(t *testing.T) {
+	didKey, _ := fingerprint.CreateDIDKey([]byte("recipient"))
+	routingKey, _ := fingerprint.CreateDIDKey([]byte("routing"))
+
+	dest := ServiceDestination(&decorator.Service{
+		// raw base58 keys are used by the other frameworks
+		RecipientKeys:   []string{base58.Encode([]byte("recipient"))},
+		RoutingKeys:     []string{routingKey},
+		ServiceEndpoint: "https://example.com",
+	})
+
+	require.Equal(t, []string{didKey}, dest.RecipientKeys)
+	require.Equal(t, []string{routingKey}, dest.RoutingKeys)
+	require.Equal(t, "https://example.com", dest.ServiceEndpoint)
+}
@@ -26,6 +26,10 @@ const (
 	jsonParentThreadID = "pthid"
 	jsonMetadata       = "_internal_metadata"
 
+	// DIDComm V2 message fields.
+	jsonIDV2   = "id"
+	jsonTypeV2 = "type"
+
 	basePIURI = "https://didcomm.org/"
 	oldPIURI  = "did:sov:BzCbsNYhMrjHiqZDTUASHg;spec/"
 )
@@ -81,7 +85,7 @@ func ParseDIDCommMsgMap(payload []byte) (DIDCommMsgMap, error) {
 
 	// Interop: accept old PIURI when it's used, as we handle backwards-compatibility at a more fine-grained level.
 	if typ := msg.Type(); typ != "" {
-		msg[jsonType] = strings.Replace(typ, oldPIURI, basePIURI, 1)
+		msg[msg.typeKey()] = strings.Replace(typ, oldPIURI, basePIURI, 1)
 	}
 
 	return msg, nil
@@ -99,8 +103,22 @@ func NewDIDCommMsgMap(v interface{}) DIDCommMsgMap {
 	return msg
 }
 
+// IsDIDCommV2 returns true if the message follows the DIDComm V2 structure
+// (the type, id, thid and pthid fields are placed at the top level of the message).
+func (m DIDCommMsgMap) IsDIDCommV2() bool {
+	if m == nil {
+		return false
+	}
+
+	_, v1 := m[jsonType]
+	_, v2 := m[jsonTypeV2]
+
+	return !v1 && v2
+}
+
 // ThreadID returns msg ~thread.thid if there is no ~thread.thid returns msg @id
 // message is invalid if ~thread.thid exist and @id is absent.
+// For DIDComm V2 messages the top-level thid and id fields are used instead.
 func (m DIDCommMsgMap) ThreadID() (string, error) {
 	if m == nil {
 		return "", ErrInvalidMessage
@@ -109,6 +127,10 @@ func (m DIDCommMsgMap) ThreadID() (string, error) {
 	msgID := m.ID()
 	thread, ok := m[jsonThread].(map[string]interface{})
 
+	if m.IsDIDCommV2() {
+		thread, ok = m, true
+	}
+
 	if ok && thread[jsonThreadID] != nil {
 		var thID string
 		if v, ok := thread[jsonThreadID].(string); ok {
@@ -149,11 +171,11 @@ func (m DIDCommMsgMap) Metadata() map[string]interface{} {
 
 // Type returns the message type.
 func (m DIDCommMsgMap) Type() string {
-	if m == nil || m[jsonType] == nil {
+	if m == nil || m[m.typeKey()] == nil {
 		return ""
 	}
 
-	res, ok := m[jsonType].(string)
+	res, ok := m[m.typeKey()].(string)
 	if !ok {
 		return ""
 	}
@@ -163,6 +185,12 @@ func (m DIDCommMsgMap) Type() string {
 
 // ParentThreadID returns the message parent threadID.
 func (m DIDCommMsgMap) ParentThreadID() string {
+	if m.IsDIDCommV2() {
+		pthID, _ := m[jsonParentThreadID].(string)
+
+		return pthID
+	}
+
 	if m == nil || m[jsonThread] == nil {
 		return ""
 	}
@@ -178,11 +206,11 @@ func (m DIDCommMsgMap) ParentThreadID() string {
 
 // ID returns the message id.
 func (m DIDCommMsgMap) ID() string {
-	if m == nil || m[jsonID] == nil {
+	if m == nil || m[m.idKey()] == nil {
 		return ""
 	}
 
-	res, ok := m[jsonID].(string)
+	res, ok := m[m.idKey()].(string)
 	if !ok {
 		return ""
 	}
@@ -196,11 +224,27 @@ func (m DIDCommMsgMap) SetID(id string) error {
 		return ErrNilMessage
 	}
 
-	m[jsonID] = id
+	m[m.idKey()] = id
 
 	return nil
 }
 
+func (m DIDCommMsgMap) idKey() string {
+	if m.IsDIDCommV2() {
+		return jsonIDV2
+	}
+
+	return jsonID
+}
+
+func (m DIDCommMsgMap) typeKey() string {
+	if m.IsDIDCommV2() {
+		return jsonTypeV2
+	}
+
+	return jsonType
+}
+
 // Decode converts message to  struct.
 func (m DIDCommMsgMap) Decode(v interface{}) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
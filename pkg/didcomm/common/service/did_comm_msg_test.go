@@ -193,6 +193,34 @@ func TestDIDCommMsgMap_ParentThreadID(t *testing.T) {
 	}
 }
 
+func TestDIDCommMsgMap_DIDCommV2(t *testing.T) {
+	require.False(t, DIDCommMsgMap(nil).IsDIDCommV2())
+	require.False(t, DIDCommMsgMap{jsonType: "type", jsonTypeV2: "type"}.IsDIDCommV2())
+
+	msg, err := ParseDIDCommMsgMap([]byte(`{"id":"ID","type":"` + oldPIURI + `protocol/1.0/msg"}`))
+	require.NoError(t, err)
+	require.True(t, msg.IsDIDCommV2())
+	require.Equal(t, "ID", msg.ID())
+	require.Equal(t, basePIURI+"protocol/1.0/msg", msg.Type())
+	require.Empty(t, msg.ParentThreadID())
+
+	thID, err := msg.ThreadID()
+	require.NoError(t, err)
+	require.Equal(t, "ID", thID)
+
+	msg[jsonThreadID] = "thID"
+	msg[jsonParentThreadID] = "pthID"
+
+	thID, err = msg.ThreadID()
+	require.NoError(t, err)
+	require.Equal(t, "thID", thID)
+	require.Equal(t, "pthID", msg.ParentThreadID())
+
+	require.NoError(t, msg.SetID("newID"))
+	require.Equal(t, "newID", msg[jsonIDV2])
+	require.Nil(t, msg[jsonID])
+}
+
 func TestDIDCommMsgMap_ToStruct(t *testing.T) {
 	type Test struct {
 		Time  time.Time
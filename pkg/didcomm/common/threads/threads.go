@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package threads keeps track of the protocol instances (threads) of the DIDComm protocol services: it queries them,
+// persists the callbacks being handled and abandons the protocol instances that stay in a state for too long.
+package threads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// CallbackKey is the key prefix and the tag of the persisted callbacks.
+const CallbackKey = "callback_"
+
+var logger = log.New("aries-framework/didcomm/threads")
+
+// Thread describes a protocol instance and the state it is currently in.
+type Thread struct {
+	// Protocol instance ID
+	PIID      string
+	StateName string
+	MyDID     string
+	TheirDID  string
+	// Msg is the last message processed by the protocol instance.
+	Msg       service.DIDCommMsgMap
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Filter narrows down the protocol instances returned by Threads.
+// Zero values are ignored, e.g an empty filter matches every protocol instance.
+type Filter struct {
+	// StateNames matches protocol instances being in one of the given states.
+	StateNames []string
+	// MyDID and TheirDID match protocol instances of the given connection.
+	MyDID    string
+	TheirDID string
+	// MinAge and MaxAge match protocol instances by the time elapsed since they were created.
+	MinAge time.Duration
+	MaxAge time.Duration
+}
+
+func (f *Filter) match(t *Thread, now time.Time) bool {
+	if len(f.StateNames) > 0 && !contains(f.StateNames, t.StateName) {
+		return false
+	}
+
+	if (f.MyDID != "" && f.MyDID != t.MyDID) || (f.TheirDID != "" && f.TheirDID != t.TheirDID) {
+		return false
+	}
+
+	age := now.Sub(t.CreatedAt)
+
+	return age >= f.MinAge && (f.MaxAge == 0 || age <= f.MaxAge)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AbandonFunc abandons the protocol instance which timed out.
+type AbandonFunc func(thread Thread)
+
+// Tracker keeps track of the protocol instances of a protocol service.
+// The protocol instances are persisted by the service under the thread key prefix followed by the protocol instance ID,
+// the record is expected to be the JSON of a Thread (it may embed other fields).
+type Tracker struct {
+	store       storage.Store
+	threadKey   string
+	abandon     AbandonFunc
+	finalStates []string
+
+	callbacksMu sync.Mutex
+
+	timeouts     map[string]time.Duration
+	deadlines    deadlines
+	timeoutsMu   sync.Mutex
+	timeoutsOnce sync.Once
+	wake         chan struct{}
+	stop         chan struct{}
+	closeOnce    sync.Once
+}
+
+// New returns the tracker of the protocol instances persisted in the given store under the thread key prefix.
+// The abandon function is called for the protocol instances which timed out,
+// protocol instances being in one of the final states never time out.
+func New(store storage.Store, threadKey string, abandon AbandonFunc, finalStates ...string) *Tracker {
+	return &Tracker{
+		store:       store,
+		threadKey:   threadKey,
+		abandon:     abandon,
+		finalStates: finalStates,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Threads returns the protocol instances matching the given filter.
+func (t *Tracker) Threads(filter Filter) ([]Thread, error) {
+	records, err := t.store.Query(t.threadKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the store: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var (
+		threads []Thread
+		now     = time.Now()
+	)
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, errValue := records.Value()
+		if errValue != nil {
+			return nil, fmt.Errorf("failed to get value: %w", errValue)
+		}
+
+		var thread Thread
+		if errUnmarshal := json.Unmarshal(value, &thread); errUnmarshal != nil {
+			return nil, fmt.Errorf("unmarshal: %w", errUnmarshal)
+		}
+
+		if filter.match(&thread, now) {
+			threads = append(threads, thread)
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return threads, nil
+}
+
+// SaveCallback persists the callback of the protocol instance,
+// that allows resuming it if the agent is restarted before it was handled.
+func (t *Tracker) SaveCallback(piID string, payload interface{}) error {
+	src, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal callback: %w", err)
+	}
+
+	t.callbacksMu.Lock()
+	defer t.callbacksMu.Unlock()
+
+	return t.store.Put(CallbackKey+piID, src, storage.Tag{Name: CallbackKey})
+}
+
+// ClaimCallback persists the callback of the protocol instance unless another callback of the protocol instance is
+// being handled. It returns false if the protocol instance is already being processed.
+func (t *Tracker) ClaimCallback(piID string, payload interface{}) (bool, error) {
+	src, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("marshal callback: %w", err)
+	}
+
+	t.callbacksMu.Lock()
+	defer t.callbacksMu.Unlock()
+
+	_, err = t.store.Get(CallbackKey + piID)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return false, fmt.Errorf("get callback: %w", err)
+	}
+
+	return true, t.store.Put(CallbackKey+piID, src, storage.Tag{Name: CallbackKey})
+}
+
+// DeleteCallback deletes the callback of the protocol instance once it was handled.
+func (t *Tracker) DeleteCallback(piID string) error {
+	t.callbacksMu.Lock()
+	defer t.callbacksMu.Unlock()
+
+	return t.store.Delete(CallbackKey + piID)
+}
+
+// PendingCallbacks passes the callbacks which were not handled before the agent was stopped to the given function.
+func (t *Tracker) PendingCallbacks(fn func(payload []byte) error) error {
+	records, err := t.store.Query(CallbackKey)
+	if err != nil {
+		return fmt.Errorf("failed to query the store: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	more, err := records.Next()
+	if err != nil {
+		return fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, errValue := records.Value()
+		if errValue != nil {
+			return fmt.Errorf("failed to get value: %w", errValue)
+		}
+
+		if err = fn(value); err != nil {
+			return err
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threads
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	threadKey = "thread_"
+	stateDone = "done"
+	stateSent = "sent"
+)
+
+func newStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	store, err := mem.NewProvider().OpenStore("threads")
+	require.NoError(t, err)
+
+	return store
+}
+
+func saveThread(t *testing.T, tracker *Tracker, store storage.Store, thread *Thread) {
+	t.Helper()
+
+	src, err := json.Marshal(thread)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(threadKey+thread.PIID, src, storage.Tag{Name: threadKey}))
+
+	tracker.Track(thread)
+}
+
+func TestTracker_Threads(t *testing.T) {
+	store := newStore(t)
+	tracker := New(store, threadKey, func(Thread) {})
+	now := time.Now()
+
+	saveThread(t, tracker, store, &Thread{PIID: "1", StateName: stateSent, MyDID: "alice", TheirDID: "bob",
+		CreatedAt: now, UpdatedAt: now})
+	saveThread(t, tracker, store, &Thread{PIID: "2", StateName: stateDone, MyDID: "bob", TheirDID: "alice",
+		CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now})
+
+	list, err := tracker.Threads(Filter{})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	list, err = tracker.Threads(Filter{StateNames: []string{stateDone}})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "2", list[0].PIID)
+
+	list, err = tracker.Threads(Filter{MyDID: "alice", TheirDID: "bob"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "1", list[0].PIID)
+
+	list, err = tracker.Threads(Filter{MinAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "2", list[0].PIID)
+
+	list, err = tracker.Threads(Filter{MaxAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "1", list[0].PIID)
+}
+
+func TestTracker_Callbacks(t *testing.T) {
+	tracker := New(newStore(t), threadKey, func(Thread) {})
+
+	require.NoError(t, tracker.SaveCallback("1", map[string]string{"state": "a"}))
+
+	claimed, err := tracker.ClaimCallback("1", map[string]string{"state": "b"})
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	claimed, err = tracker.ClaimCallback("2", map[string]string{"state": "c"})
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	var states []string
+
+	require.NoError(t, tracker.PendingCallbacks(func(payload []byte) error {
+		var p map[string]string
+		require.NoError(t, json.Unmarshal(payload, &p))
+
+		states = append(states, p["state"])
+
+		return nil
+	}))
+	require.ElementsMatch(t, []string{"a", "c"}, states)
+
+	require.NoError(t, tracker.DeleteCallback("1"))
+
+	claimed, err = tracker.ClaimCallback("1", map[string]string{"state": "b"})
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	require.EqualError(t, tracker.PendingCallbacks(func([]byte) error {
+		return errors.New("test")
+	}), "test")
+}
+
+func TestTracker_SetStateTimeouts(t *testing.T) {
+	t.Run("Abandons timed out threads", func(t *testing.T) {
+		store := newStore(t)
+		abandoned := make(chan Thread)
+		tracker := New(store, threadKey, func(thread Thread) { abandoned <- thread }, stateDone)
+
+		defer func() { require.NoError(t, tracker.Close()) }()
+
+		now := time.Now()
+
+		// persisted before the timeouts were set
+		saveThread(t, tracker, store, &Thread{PIID: "1", StateName: stateSent, CreatedAt: now, UpdatedAt: now})
+		saveThread(t, tracker, store, &Thread{PIID: "2", StateName: stateDone, CreatedAt: now, UpdatedAt: now})
+
+		tracker.SetStateTimeouts(map[string]time.Duration{stateSent: time.Millisecond, stateDone: time.Millisecond})
+
+		select {
+		case thread := <-abandoned:
+			require.Equal(t, "1", thread.PIID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		// persisted after the timeouts were set
+		saveThread(t, tracker, store, &Thread{PIID: "3", StateName: stateSent, UpdatedAt: time.Now()})
+
+		select {
+		case thread := <-abandoned:
+			require.Equal(t, "3", thread.PIID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		select {
+		case thread := <-abandoned:
+			t.Fatalf("unexpected abandoned thread %s", thread.PIID)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("Skips threads which changed their state", func(t *testing.T) {
+		store := newStore(t)
+		abandoned := make(chan Thread)
+		tracker := New(store, threadKey, func(thread Thread) { abandoned <- thread }, stateDone)
+
+		defer func() { require.NoError(t, tracker.Close()) }()
+
+		tracker.SetStateTimeouts(map[string]time.Duration{stateSent: 20 * time.Millisecond})
+
+		saveThread(t, tracker, store, &Thread{PIID: "1", StateName: stateSent, UpdatedAt: time.Now()})
+		saveThread(t, tracker, store, &Thread{PIID: "1", StateName: stateDone, UpdatedAt: time.Now()})
+
+		select {
+		case thread := <-abandoned:
+			t.Fatalf("unexpected abandoned thread %s", thread.PIID)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("Stops on close", func(t *testing.T) {
+		store := newStore(t)
+		abandoned := make(chan Thread, 1)
+		tracker := New(store, threadKey, func(thread Thread) { abandoned <- thread })
+
+		tracker.SetStateTimeouts(map[string]time.Duration{stateSent: 20 * time.Millisecond})
+
+		require.NoError(t, tracker.Close())
+		require.NoError(t, tracker.Close())
+
+		saveThread(t, tracker, store, &Thread{PIID: "1", StateName: stateSent, UpdatedAt: time.Now()})
+
+		select {
+		case thread := <-abandoned:
+			t.Fatalf("unexpected abandoned thread %s", thread.PIID)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
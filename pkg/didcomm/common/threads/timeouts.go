@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package threads
+
+import (
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// deadline is the time a protocol instance times out in the given state.
+type deadline struct {
+	piID      string
+	stateName string
+	updatedAt time.Time
+	at        time.Time
+}
+
+// deadlines is a min-heap of deadlines, the earliest deadline comes first.
+type deadlines []*deadline
+
+func (d deadlines) Len() int { return len(d) }
+
+func (d deadlines) Less(i, j int) bool { return d[i].at.Before(d[j].at) }
+
+func (d deadlines) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+func (d *deadlines) Push(x interface{}) { *d = append(*d, x.(*deadline)) }
+
+func (d *deadlines) Pop() interface{} {
+	old := *d
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*d = old[:n-1]
+
+	return item
+}
+
+// SetStateTimeouts sets the time a protocol instance may stay in the given state (e.g "request-sent").
+// When the time is over, the protocol instance is abandoned by the abandon function of the tracker.
+func (t *Tracker) SetStateTimeouts(timeouts map[string]time.Duration) {
+	// the deadlines of the protocol instances already persisted are indexed once,
+	// afterwards the deadlines are indexed by Track when the protocol instance changes its state.
+	threads, err := t.Threads(Filter{})
+	if err != nil {
+		logger.Errorf("index thread deadlines: %s", err)
+	}
+
+	t.timeoutsMu.Lock()
+	t.timeouts = make(map[string]time.Duration, len(timeouts))
+
+	for name, timeout := range timeouts {
+		t.timeouts[name] = timeout
+	}
+
+	t.deadlines = nil
+
+	for i := range threads {
+		t.push(&threads[i])
+	}
+
+	heap.Init(&t.deadlines)
+	t.timeoutsMu.Unlock()
+
+	t.timeoutsOnce.Do(func() {
+		go t.listen()
+	})
+
+	t.notify()
+}
+
+// Track indexes the deadline of the protocol instance that was persisted in the given state.
+func (t *Tracker) Track(thread *Thread) {
+	t.timeoutsMu.Lock()
+	pushed := t.push(thread)
+	t.timeoutsMu.Unlock()
+
+	if pushed {
+		t.notify()
+	}
+}
+
+// Close stops abandoning the protocol instances which timed out.
+func (t *Tracker) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.stop)
+	})
+
+	return nil
+}
+
+// push adds the deadline of the protocol instance, timeoutsMu must be held by the caller.
+func (t *Tracker) push(thread *Thread) bool {
+	if contains(t.finalStates, thread.StateName) {
+		return false
+	}
+
+	timeout, ok := t.timeouts[thread.StateName]
+	if !ok || timeout <= 0 {
+		return false
+	}
+
+	heap.Push(&t.deadlines, &deadline{
+		piID:      thread.PIID,
+		stateName: thread.StateName,
+		updatedAt: thread.UpdatedAt,
+		at:        thread.UpdatedAt.Add(timeout),
+	})
+
+	return true
+}
+
+func (t *Tracker) notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// listen abandons the protocol instances once their deadline is over.
+func (t *Tracker) listen() {
+	for {
+		for _, d := range t.expired(time.Now()) {
+			t.abandonExpired(d)
+		}
+
+		var expire <-chan time.Time
+
+		timer := t.nextTimer()
+		if timer != nil {
+			expire = timer.C
+		}
+
+		select {
+		case <-expire:
+		case <-t.wake:
+		case <-t.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (t *Tracker) nextTimer() *time.Timer {
+	t.timeoutsMu.Lock()
+	defer t.timeoutsMu.Unlock()
+
+	if len(t.deadlines) == 0 {
+		return nil
+	}
+
+	return time.NewTimer(time.Until(t.deadlines[0].at))
+}
+
+// expired pops the deadlines which are over.
+func (t *Tracker) expired(now time.Time) []*deadline {
+	t.timeoutsMu.Lock()
+	defer t.timeoutsMu.Unlock()
+
+	var result []*deadline
+
+	for len(t.deadlines) > 0 && !t.deadlines[0].at.After(now) {
+		result = append(result, heap.Pop(&t.deadlines).(*deadline))
+	}
+
+	return result
+}
+
+func (t *Tracker) abandonExpired(d *deadline) {
+	src, err := t.store.Get(t.threadKey + d.piID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return
+	}
+
+	if err != nil {
+		logger.Errorf("get thread %s: %s", d.piID, err)
+
+		return
+	}
+
+	var thread Thread
+	if err = json.Unmarshal(src, &thread); err != nil {
+		logger.Errorf("unmarshal thread %s: %s", d.piID, err)
+
+		return
+	}
+
+	// the protocol instance changed its state in the meantime, the deadline of the new state is indexed separately
+	if thread.StateName != d.stateName || !thread.UpdatedAt.Equal(d.updatedAt) {
+		return
+	}
+
+	logger.Warnf("protocol instance %s timed out in state %s", thread.PIID, thread.StateName)
+
+	t.abandon(thread)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -22,6 +23,8 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
 )
 
+var logger = log.New("aries-framework/didcomm/dispatcher")
+
 /* const (
 	legacyMediaType			 = "JWM/1.0"
 	didCommV1MediaType       = "application/didcomm-enc-env"
@@ -190,11 +193,16 @@ func (o *OutboundDispatcher) createForwardMessage(msg []byte, des *service.Desti
 	}
 
 	// create key set
-	_, senderVerKey, err := o.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	senderKID, senderVerKey, err := o.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed Create and export SigningKey: %w", err)
 	}
 
+	// the key is used once, its purpose allows to clean it up (best effort, the KMS may not support metadata)
+	if err = kms.TagKey(o.kms, senderKID, kms.KeyPurposeForward, ""); err != nil {
+		logger.Warnf("failed to set SigningKey metadata: %v", err)
+	}
+
 	// pack above message using auth crypt
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/1112 Configurable packing
 	//  algorithm(auth/anon crypt) for Forward(router) message
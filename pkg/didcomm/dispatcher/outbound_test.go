@@ -100,6 +100,25 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 			"and export SigningKey: create and export key error")
 	})
 
+	t.Run("test send with forward message - key metadata failures are ignored", func(t *testing.T) {
+		for _, km := range []*mockkms.KeyManager{
+			{SetMetadataErr: errors.New("set metadata error")},
+			{GetMetadataErr: errors.New("metadata not supported")},
+		} {
+			o := NewOutbound(&mockProvider{
+				packagerValue:           &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
+				outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
+				kms:                     km,
+			})
+
+			require.NoError(t, o.Send("data", mockdiddoc.MockDIDKey(t), &service.Destination{
+				ServiceEndpoint: "url",
+				RecipientKeys:   []string{"abc"},
+				RoutingKeys:     []string{"xyz"},
+			}))
+		}
+	})
+
 	t.Run("test send with forward message - packer error", func(t *testing.T) {
 		o := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{PackErr: errors.New("pack error")},
@@ -10,12 +10,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
@@ -24,9 +29,13 @@ const (
 	MessengerStore = "messenger_store"
 
 	jsonID             = "@id"
+	jsonIDV2           = "id"
 	jsonThread         = "~thread"
 	jsonThreadID       = "thid"
 	jsonParentThreadID = "pthid"
+
+	// connectionlessServiceKey keeps the ~service decorator the connectionless replies are sent from.
+	connectionlessServiceKey = "connectionless_service"
 )
 
 // record is an internal structure and keeps payload about inbound message.
@@ -35,6 +44,8 @@ type record struct {
 	TheirDID       string `json:"their_did,omitempty"`
 	ThreadID       string `json:"thread_id,omitempty"`
 	ParentThreadID string `json:"parent_thread_id,omitempty"`
+	// Service is the ~service decorator of the message received without a connection.
+	Service *decorator.Service `json:"service,omitempty"`
 }
 
 // Provider contains dependencies for the Messenger.
@@ -43,14 +54,37 @@ type Provider interface {
 	StorageProvider() storage.Provider
 }
 
+// connectionProvider is implemented by the providers able to track the connection activity.
+type connectionProvider interface {
+	Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// connectionlessProvider is implemented by the providers able to reply to the messages received without a connection.
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+}
+
 // Messenger describes the messenger structure.
 type Messenger struct {
 	store      storage.Store
 	dispatcher dispatcher.Outbound
+	// connections keeps the time of the last message exchanged over a connection, nil when not supported.
+	connections *connection.Recorder
+	// kms creates the key the connectionless replies are sent from, nil when not supported.
+	kms             kms.KeyManager
+	serviceEndpoint string
+	// connectionless is the ~service decorator of the connectionless replies, created once and reused.
+	connectionless   *decorator.Service
+	connectionlessMu sync.Mutex
 }
 
 var logger = log.New("aries-framework/pkg/didcomm/messenger")
 
+// ErrConnectionless is returned when a connectionless message is sent without a destination.
+var ErrConnectionless = errors.New("connectionless message is delivered out-of-band")
+
 // NewMessenger returns a new instance of the Messenger.
 func NewMessenger(ctx Provider) (*Messenger, error) {
 	store, err := ctx.StorageProvider().OpenStore(MessengerStore)
@@ -58,10 +92,24 @@ func NewMessenger(ctx Provider) (*Messenger, error) {
 		return nil, fmt.Errorf("open store: %w", err)
 	}
 
-	return &Messenger{
+	m := &Messenger{
 		store:      store,
 		dispatcher: ctx.OutboundDispatcher(),
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionProvider); ok && p.ProtocolStateStorageProvider() != nil {
+		m.connections, err = connection.NewRecorder(p)
+		if err != nil {
+			return nil, fmt.Errorf("new connection recorder: %w", err)
+		}
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		m.kms = p.KMS()
+		m.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return m, nil
 }
 
 // HandleInbound handles all inbound messages.
@@ -79,27 +127,48 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, ctx service.DIDComm
 		return fmt.Errorf("threadID: %w", err)
 	}
 
-	// saves message payload
-	return m.saveRecord(msg.ID(), record{
+	rec := record{
 		ParentThreadID: msg.ParentThreadID(),
 		MyDID:          ctx.MyDID(),
 		TheirDID:       ctx.TheirDID(),
 		ThreadID:       thID,
-	})
+	}
+
+	// the reply to a connectionless message is sent to the service provided by the message
+	if ctx.TheirDID() == "" {
+		rec.Service, err = msg.ServiceDecorator()
+		if err != nil {
+			return fmt.Errorf("service decorator: %w", err)
+		}
+	}
+
+	// saves message payload
+	err = m.saveRecord(msg.ID(), rec)
+	if err != nil {
+		return err
+	}
+
+	m.touchConnection(ctx.MyDID(), ctx.TheirDID())
+
+	return nil
 }
 
 // Send sends the message by starting a new thread.
 // Do not provide a message with ~thread decorator. It will be removed.
 // Use ReplyTo function instead. It will keep ~thread decorator automatically.
+// A connectionless message (carrying the ~service decorator, without theirDID) has no destination,
+// it must be delivered out-of-band by the caller, e.g attached to an out-of-band invitation.
 func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if service.IsConnectionless(msg, theirDID) {
+		return fmt.Errorf("connectionless message %s has no destination: %w", msg.ID(), ErrConnectionless)
+	}
+
 	// fills missing fields
 	fillIfMissing(msg)
 
-	msg[jsonThread] = map[string]interface{}{
-		jsonThreadID: msg.ID(),
-	}
+	setThread(msg, msg.ID(), "")
 
-	return m.dispatcher.SendToDID(msg, myDID, theirDID)
+	return m.sendToDID(msg, myDID, theirDID)
 }
 
 // SendToDestination sends the message to given destination by starting a new thread.
@@ -110,7 +179,7 @@ func (m *Messenger) SendToDestination(msg service.DIDCommMsgMap, sender string,
 	// fills missing fields
 	fillIfMissing(msg)
 
-	delete(msg, jsonThread)
+	clearThread(msg)
 
 	return m.dispatcher.Send(msg, sender, destination)
 }
@@ -127,19 +196,14 @@ func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
 		return fmt.Errorf("get record: %w", err)
 	}
 
-	// sets threadID
-	thread := map[string]interface{}{
-		jsonThreadID: rec.ThreadID,
-	}
+	// sets threadID and parent threadID
+	setThread(msg, rec.ThreadID, rec.ParentThreadID)
 
-	// sets parent threadID
-	if rec.ParentThreadID != "" {
-		thread[jsonParentThreadID] = rec.ParentThreadID
+	if rec.TheirDID == "" && rec.Service != nil {
+		return m.sendConnectionless(msg, rec.Service)
 	}
 
-	msg[jsonThread] = thread
-
-	return m.dispatcher.SendToDID(msg, rec.MyDID, rec.TheirDID)
+	return m.sendToDID(msg, rec.MyDID, rec.TheirDID)
 }
 
 // ReplyToMsg replies to the given message.
@@ -154,19 +218,17 @@ func (m *Messenger) ReplyToMsg(in, out service.DIDCommMsgMap, myDID, theirDID st
 		return fmt.Errorf("get threadID: %w", err)
 	}
 
-	// sets threadID
-	thread := map[string]interface{}{
-		jsonThreadID: thID,
-	}
+	// sets threadID and parent threadID
+	setThread(out, thID, in.ParentThreadID())
 
-	// sets parent threadID
-	if in.ParentThreadID() != "" {
-		thread[jsonParentThreadID] = in.ParentThreadID()
-	}
+	// the reply to a connectionless message is sent to the service provided by the message
+	if service.IsConnectionless(in, theirDID) {
+		svc, _ := in.ServiceDecorator() // validated by IsConnectionless
 
-	out[jsonThread] = thread
+		return m.sendConnectionless(out, svc)
+	}
 
-	return m.dispatcher.SendToDID(out, myDID, theirDID)
+	return m.sendToDID(out, myDID, theirDID)
 }
 
 // ReplyToNested sends the message by starting a new thread.
@@ -182,16 +244,137 @@ func (m *Messenger) ReplyToNested(msg service.DIDCommMsgMap, opts *service.Neste
 	}
 
 	// sets parent threadID
-	msg[jsonThread] = map[string]interface{}{jsonParentThreadID: opts.ThreadID}
+	setThread(msg, "", opts.ThreadID)
 
-	return m.dispatcher.SendToDID(msg, opts.MyDID, opts.TheirDID)
+	return m.sendToDID(msg, opts.MyDID, opts.TheirDID)
 }
 
 // fillIfMissing populates message with common fields such as ID.
+func (m *Messenger) sendToDID(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if err := m.dispatcher.SendToDID(msg, myDID, theirDID); err != nil {
+		return err
+	}
+
+	m.touchConnection(myDID, theirDID)
+
+	return nil
+}
+
+// sendConnectionless sends the reply to the service of a message received without a connection.
+// The reply carries the ~service decorator of the agent so the other agent can reply in turn.
+func (m *Messenger) sendConnectionless(msg service.DIDCommMsgMap, to *decorator.Service) error {
+	from, err := m.connectionlessService()
+	if err != nil {
+		return err
+	}
+
+	msg.SetServiceDecorator(from)
+
+	return m.dispatcher.Send(msg, from.RecipientKeys[0], service.ServiceDestination(to))
+}
+
+// connectionlessService returns the ~service decorator the connectionless replies are sent from.
+// The key is created once and persisted, all the replies are sent from it.
+func (m *Messenger) connectionlessService() (*decorator.Service, error) {
+	if m.kms == nil {
+		return nil, errors.New("connectionless messages are not supported")
+	}
+
+	m.connectionlessMu.Lock()
+	defer m.connectionlessMu.Unlock()
+
+	if m.connectionless != nil {
+		return m.connectionless, nil
+	}
+
+	src, err := m.store.Get(connectionlessServiceKey)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("get connectionless service: %w", err)
+	}
+
+	svc := &decorator.Service{}
+
+	if err == nil {
+		if err = json.Unmarshal(src, svc); err != nil {
+			return nil, fmt.Errorf("unmarshal connectionless service: %w", err)
+		}
+
+		svc.ServiceEndpoint = m.serviceEndpoint
+	} else {
+		svc, err = service.NewConnectionlessService(m.kms, m.serviceEndpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		src, err = json.Marshal(svc)
+		if err != nil {
+			return nil, fmt.Errorf("marshal connectionless service: %w", err)
+		}
+
+		if err = m.store.Put(connectionlessServiceKey, src); err != nil {
+			return nil, fmt.Errorf("save connectionless service: %w", err)
+		}
+	}
+
+	m.connectionless = svc
+
+	return svc, nil
+}
+
+// touchConnection updates the time of the last message exchanged over the connection between the DIDs.
+func (m *Messenger) touchConnection(myDID, theirDID string) {
+	if m.connections == nil || myDID == "" || theirDID == "" {
+		return
+	}
+
+	// connectionless messages or the messages exchanged before the connection is completed are not tracked
+	if err := m.connections.UpdateLastMessageTime(myDID, theirDID, time.Now()); err != nil {
+		logger.Debugf("update last message time: %s", err)
+	}
+}
+
 func fillIfMissing(msg service.DIDCommMsgMap) {
 	// if ID is empty we will create a new one
 	if msg.ID() == "" {
-		msg[jsonID] = uuid.New().String()
+		if msg.IsDIDCommV2() {
+			msg[jsonIDV2] = uuid.New().String()
+		} else {
+			msg[jsonID] = uuid.New().String()
+		}
+	}
+}
+
+// setThread sets the given threadID and parent threadID to the message.
+// DIDComm V2 messages keep them as top-level fields, otherwise the ~thread decorator is used.
+func setThread(msg service.DIDCommMsgMap, thID, pthID string) {
+	clearThread(msg)
+
+	thread := map[string]interface{}{}
+
+	if msg.IsDIDCommV2() {
+		thread = msg
+	}
+
+	if thID != "" {
+		thread[jsonThreadID] = thID
+	}
+
+	if pthID != "" {
+		thread[jsonParentThreadID] = pthID
+	}
+
+	if !msg.IsDIDCommV2() {
+		msg[jsonThread] = thread
+	}
+}
+
+// clearThread removes the thread information from the message.
+func clearThread(msg service.DIDCommMsgMap) {
+	delete(msg, jsonThread)
+
+	if msg.IsDIDCommV2() {
+		delete(msg, jsonThreadID)
+		delete(msg, jsonParentThreadID)
 	}
 }
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package messenger
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"testing"
@@ -14,11 +15,18 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
 	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/spi/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 const (
@@ -62,6 +70,44 @@ func TestNewMessenger(t *testing.T) {
 	})
 }
 
+func TestMessenger_LastMessageTime(t *testing.T) {
+	provider := &mockprovider.Provider{
+		StorageProviderValue:              mem.NewProvider(),
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+		OutboundDispatcherValue:           &mockdispatcher.MockOutbound{},
+	}
+
+	recorder, err := connection.NewRecorder(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: ID,
+		State:        connection.StateNameCompleted,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	}))
+
+	msgr, err := NewMessenger(provider)
+	require.NoError(t, err)
+
+	ctx := service.NewDIDCommContext(myDID, theirDID, nil)
+	require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{jsonID: msgID}, ctx))
+
+	received, err := recorder.GetConnectionRecord(ID)
+	require.NoError(t, err)
+	require.False(t, received.LastMessageAt.IsZero())
+
+	require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, myDID, theirDID))
+
+	sent, err := recorder.GetConnectionRecord(ID)
+	require.NoError(t, err)
+	require.False(t, sent.LastMessageAt.Before(received.LastMessageAt))
+	require.Equal(t, received.UpdatedAt, sent.UpdatedAt)
+
+	// messages exchanged without a connection are not tracked
+	require.NoError(t, msgr.Send(service.DIDCommMsgMap{jsonID: ID}, "unknown", theirDID))
+}
+
 func TestMessenger_HandleInbound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -391,6 +437,37 @@ func TestMessenger_ReplyToMsg(t *testing.T) {
 		}, service.DIDCommMsgMap{}, "", ""))
 	})
 
+	t.Run("success (DIDComm V2)", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+				require.NotEmpty(t, msg[jsonIDV2])
+				require.Equal(t, "thID", msg[jsonThreadID])
+				require.Equal(t, "pthID", msg[jsonParentThreadID])
+				require.Nil(t, msg[jsonThread])
+
+				return nil
+			})
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		require.NoError(t, msgr.ReplyToMsg(service.DIDCommMsgMap{
+			jsonIDV2:           "id",
+			"type":             "type",
+			jsonThreadID:       "thID",
+			jsonParentThreadID: "pthID",
+		}, service.DIDCommMsgMap{"type": "type"}, "", ""))
+	})
+
 	t.Run("invalid message", func(t *testing.T) {
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
 
@@ -410,3 +487,146 @@ func TestMessenger_ReplyToMsg(t *testing.T) {
 		}, service.DIDCommMsgMap{}, "", ""), "get threadID: invalid message")
 	})
 }
+
+func TestMessenger_Connectionless(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	theirService := &decorator.Service{
+		RecipientKeys:   []string{"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"},
+		ServiceEndpoint: "https://example.com",
+	}
+
+	connectionless := service.DIDCommMsgMap{
+		jsonID:     msgID,
+		jsonThread: map[string]interface{}{jsonThreadID: "thID"},
+	}
+	connectionless.SetServiceDecorator(theirService)
+
+	sendCheck := func(msg interface{}, sender string, des *service.Destination) error {
+		require.Equal(t, theirService.RecipientKeys, des.RecipientKeys)
+		require.Equal(t, theirService.ServiceEndpoint, des.ServiceEndpoint)
+
+		reply, ok := msg.(service.DIDCommMsgMap)
+		require.True(t, ok)
+
+		thID, err := reply.ThreadID()
+		require.NoError(t, err)
+		require.Equal(t, "thID", thID)
+
+		// the other agent replies to the key the message is sent from
+		myService, err := reply.ServiceDecorator()
+		require.NoError(t, err)
+		require.Equal(t, []string{sender}, myService.RecipientKeys)
+		require.Equal(t, "http://agent.example.com", myService.ServiceEndpoint)
+
+		return nil
+	}
+
+	newMessenger := func(outbound dispatcher.Outbound, km kms.KeyManager) *Messenger {
+		msgr, err := NewMessenger(&mockprovider.Provider{
+			StorageProviderValue:    mem.NewProvider(),
+			OutboundDispatcherValue: outbound,
+			KMSValue:                km,
+			ServiceEndpointValue:    "http://agent.example.com",
+		})
+		require.NoError(t, err)
+
+		return msgr
+	}
+
+	pubKey := make([]byte, ed25519.PublicKeySize)
+
+	t.Run("send has no destination", func(t *testing.T) {
+		msgr := newMessenger(dispatcherMocks.NewMockOutbound(ctrl), &mockkms.KeyManager{})
+
+		msg := service.DIDCommMsgMap{jsonID: ID}
+		msg.SetServiceDecorator(theirService)
+
+		err := msgr.Send(msg, "", "")
+		require.True(t, errors.Is(err, ErrConnectionless))
+	})
+
+	t.Run("reply to msg", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(sendCheck)
+
+		msgr := newMessenger(outbound, &mockkms.KeyManager{CrAndExportPubKeyValue: pubKey})
+
+		require.NoError(t, msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", ""))
+	})
+
+	t.Run("reply to", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(sendCheck)
+
+		msgr := newMessenger(outbound, &mockkms.KeyManager{CrAndExportPubKeyValue: pubKey})
+
+		require.NoError(t, msgr.HandleInbound(connectionless, service.EmptyDIDCommContext()))
+		require.NoError(t, msgr.ReplyTo(msgID, service.DIDCommMsgMap{}))
+	})
+
+	t.Run("replies are sent from the same key", func(t *testing.T) {
+		var senders []string
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(msg interface{}, sender string, des *service.Destination) error {
+				senders = append(senders, sender)
+
+				return sendCheck(msg, sender, des)
+			}).Times(3)
+
+		storeProvider := mem.NewProvider()
+		km := &mockkms.KeyManager{CrAndExportPubKeyValue: pubKey}
+
+		msgr, err := NewMessenger(&mockprovider.Provider{
+			StorageProviderValue:    storeProvider,
+			OutboundDispatcherValue: outbound,
+			KMSValue:                km,
+			ServiceEndpointValue:    "http://agent.example.com",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", ""))
+		require.NoError(t, msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", ""))
+
+		// the key is persisted, a new messenger does not create another one
+		msgr, err = NewMessenger(&mockprovider.Provider{
+			StorageProviderValue:    storeProvider,
+			OutboundDispatcherValue: outbound,
+			KMSValue:                &mockkms.KeyManager{CrAndExportPubKeyErr: errors.New(errMsg)},
+			ServiceEndpointValue:    "http://agent.example.com",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", ""))
+
+		require.Len(t, senders, 3)
+		require.Equal(t, senders[0], senders[1])
+		require.Equal(t, senders[0], senders[2])
+	})
+
+	t.Run("create key error", func(t *testing.T) {
+		msgr := newMessenger(dispatcherMocks.NewMockOutbound(ctrl),
+			&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New(errMsg)})
+
+		err := msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", "")
+		require.EqualError(t, err, "create connectionless key: "+errMsg)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		msgr := newMessenger(dispatcherMocks.NewMockOutbound(ctrl), nil)
+
+		err := msgr.ReplyToMsg(connectionless, service.DIDCommMsgMap{}, "", "")
+		require.EqualError(t, err, "connectionless messages are not supported")
+	})
+
+	t.Run("invalid service decorator", func(t *testing.T) {
+		msgr := newMessenger(dispatcherMocks.NewMockOutbound(ctrl), nil)
+
+		err := msgr.HandleInbound(service.DIDCommMsgMap{jsonID: msgID, "~service": "invalid"},
+			service.EmptyDIDCommContext())
+		require.Contains(t, fmt.Sprintf("%v", err), "service decorator")
+	})
+}
@@ -171,9 +171,9 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 			return nil, fmt.Errorf("anoncrypt Unpack: failed to get key from kms: %w", err)
 		}
 
-		keyHandle, ok := kh.(*keyset.Handle)
-		if !ok {
-			return nil, fmt.Errorf("anoncrypt Unpack: invalid keyset handle")
+		kh, err = kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+		if err != nil {
+			return nil, fmt.Errorf("anoncrypt Unpack: %w", err)
 		}
 
 		jweDecrypter := jose.NewJWEDecrypt(nil, p.cryptoService, p.kms)
@@ -184,7 +184,7 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		}
 
 		// TODO get mapped verKey for the recipient encryption key (kid)
-		ecdhesPubKeyByes, err := exportPubKeyBytes(keyHandle)
+		ecdhesPubKeyByes, err := p.exportRecipientPubKeyBytes(kid, kh)
 		if err != nil {
 			return nil, fmt.Errorf("anoncrypt Unpack: failed to export public key bytes: %w", err)
 		}
@@ -242,6 +242,16 @@ func getKID(i int, jwe *jose.JSONWebEncryption) (string, error) {
 	return kid, nil
 }
 
+// exportRecipientPubKeyBytes exports the public key of the recipient key handle kh. Key handles which are not Tink
+// keysets (eg PKCS#11 keys) are exported by the KMS.
+func (p *Packer) exportRecipientPubKeyBytes(kid string, kh interface{}) ([]byte, error) {
+	if keyHandle, ok := kh.(*keyset.Handle); ok {
+		return exportPubKeyBytes(keyHandle)
+	}
+
+	return p.kms.ExportPubKeyBytes(kid)
+}
+
 func exportPubKeyBytes(keyHandle *keyset.Handle) ([]byte, error) {
 	pubKH, err := keyHandle.Public()
 	if err != nil {
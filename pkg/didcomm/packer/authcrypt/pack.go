@@ -110,8 +110,13 @@ func (p *Packer) Pack(contentType string, payload, senderID []byte, recipientsPu
 		return nil, fmt.Errorf("authcrypt Pack: failed to get sender key from KMS: %w", err)
 	}
 
+	kh, err = kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+	if err != nil {
+		return nil, fmt.Errorf("authcrypt Pack: %w", err)
+	}
+
 	jweEncrypter, err := jose.NewJWEEncrypt(p.encAlg, p.EncodingType(), contentType, string(senderID),
-		kh.(*keyset.Handle), recECKeys, p.cryptoService)
+		kh, recECKeys, p.cryptoService)
 	if err != nil {
 		return nil, fmt.Errorf("authcrypt Pack: failed to new JWEEncrypt instance: %w", err)
 	}
@@ -213,9 +218,9 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 			return nil, fmt.Errorf("authcrypt Unpack: failed to get key from kms: %w", err)
 		}
 
-		keyHandle, ok := kh.(*keyset.Handle)
-		if !ok {
-			return nil, fmt.Errorf("authcrypt Unpack: invalid keyset handle")
+		kh, err = kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+		if err != nil {
+			return nil, fmt.Errorf("authcrypt Unpack: %w", err)
 		}
 
 		jweDecrypter := jose.NewJWEDecrypt(p.thirdPartyKS, p.cryptoService, p.kms)
@@ -226,7 +231,7 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		}
 
 		// TODO get mapped verKey for the recipient encryption key (kid)
-		ecdh1puPubKeyByes, err = exportPubKeyBytes(keyHandle)
+		ecdh1puPubKeyByes, err = p.exportRecipientPubKeyBytes(kid, kh)
 		if err != nil {
 			return nil, fmt.Errorf("authcrypt Unpack: failed to export public key bytes: %w", err)
 		}
@@ -284,6 +289,16 @@ func getKID(i int, jwe *jose.JSONWebEncryption) (string, error) {
 	return kid, nil
 }
 
+// exportRecipientPubKeyBytes exports the public key of the recipient key handle kh. Key handles which are not Tink
+// keysets (eg PKCS#11 keys) are exported by the KMS.
+func (p *Packer) exportRecipientPubKeyBytes(kid string, kh interface{}) ([]byte, error) {
+	if keyHandle, ok := kh.(*keyset.Handle); ok {
+		return exportPubKeyBytes(keyHandle)
+	}
+
+	return p.kms.ExportPubKeyBytes(kid)
+}
+
 func exportPubKeyBytes(keyHandle *keyset.Handle) ([]byte, error) {
 	pubKH, err := keyHandle.Public()
 	if err != nil {
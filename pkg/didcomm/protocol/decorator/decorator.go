@@ -49,6 +49,15 @@ type ReturnRoute struct {
 	Value string `json:"~return_route,omitempty"`
 }
 
+// Service is the ~service decorator, it provides the information needed to reply to a message sent without
+// a connection (connectionless message). The recipient keys are the keys the reply is packed for.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0056-service-decorator
+type Service struct {
+	RecipientKeys   []string `json:"recipientKeys"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint"`
+}
+
 // Attachment is intended to provide the possibility to include files, links or even JSON payload to the message.
 // To find out more please visit https://github.com/hyperledger/aries-rfcs/tree/master/concepts/0017-attachments
 type Attachment struct {
@@ -76,6 +85,27 @@ type Attachment struct {
 	Data AttachmentData `json:"data,omitempty"`
 }
 
+// AttachmentV2 is the DIDComm V2 version of the Attachment.
+// To find out more please visit https://identity.foundation/didcomm-messaging/spec/#attachments
+type AttachmentV2 struct {
+	// ID is a JSON-LD construct that uniquely identifies attached content within the scope of a given message.
+	ID string `json:"id,omitempty"`
+	// Description is an optional human-readable description of the content.
+	Description string `json:"description,omitempty"`
+	// FileName is a hint about the name that might be used if this attachment is persisted as a file.
+	FileName string `json:"filename,omitempty"`
+	// MediaType describes the MIME type of the attached content. Optional but recommended.
+	MediaType string `json:"media_type,omitempty"`
+	// Format describes the format of the attachment if the media_type is not sufficient.
+	Format string `json:"format,omitempty"`
+	// LastModTime is a hint about when the content in this attachment was last modified.
+	LastModTime time.Time `json:"lastmod_time,omitempty"`
+	// ByteCount is an optional, and mostly relevant when content is included by reference instead of by value.
+	ByteCount int64 `json:"byte_count,omitempty"`
+	// Data is a JSON object that gives access to the actual content of the attachment.
+	Data AttachmentData `json:"data,omitempty"`
+}
+
 // AttachmentData contains attachment payload.
 type AttachmentData struct {
 	// Sha256 is a hash of the content. Optional. Used as an integrity check if content is inlined.
@@ -98,6 +98,7 @@ type Service struct {
 	callbackChannel    chan *message
 	connectionRecorder *connection.Recorder
 	connectionStore    didstore.ConnectionStore
+	validateInvitation func(invitationID string) error
 }
 
 type context struct {
@@ -108,6 +109,8 @@ type context struct {
 	connectionStore    didstore.ConnectionStore
 	vdRegistry         vdrapi.Registry
 	routeSvc           mediator.ProtocolService
+	// useInvitation counts a use of the invitation referenced by an accepted request, nil when not tracked.
+	useInvitation func(invitationID, connectionID string) error
 }
 
 // opts are used to provide client properties to DID Exchange service.
@@ -495,6 +498,19 @@ func (s *Service) RespondTo(i *OOBInvitation, routerConnections []string) (strin
 	return s.HandleInbound(msg, service.EmptyDIDCommContext())
 }
 
+// SetInvitationValidator sets the function validating the invitation referenced by an inbound request,
+// the request is rejected if the function returns an error (e.g. the invitation is expired).
+func (s *Service) SetInvitationValidator(validate func(invitationID string) error) {
+	s.validateInvitation = validate
+}
+
+// SetInvitationUseHandler sets the function counting a use of the invitation referenced by an inbound request
+// once the request is accepted, the response is not sent if the function returns an error
+// (e.g. the invitation was used up in the meantime).
+func (s *Service) SetInvitationUseHandler(use func(invitationID, connectionID string) error) {
+	s.ctx.useInvitation = use
+}
+
 // SaveInvitation saves this invitation created by you.
 func (s *Service) SaveInvitation(i *OOBInvitation) error {
 	i.Type = oobMsgType
@@ -797,6 +813,12 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg, ctx service.EventProp
 		return nil, fmt.Errorf("missing parent thread ID on didexchange request with @id=%s", request.ID)
 	}
 
+	if s.validateInvitation != nil {
+		if err = s.validateInvitation(invitationID); err != nil {
+			return nil, fmt.Errorf("invalid invitation for didexchange request with @id=%s: %w", request.ID, err)
+		}
+	}
+
 	var mediaType string
 
 	mt, ok := ctx.All()[service.DIDCommContextEnvelopeMediaTypeKey].(string)
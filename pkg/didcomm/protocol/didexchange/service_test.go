@@ -630,8 +630,7 @@ func TestService_Update(t *testing.T) {
 
 	require.NoError(t, svc.update(RequestMsgType, connRecord))
 
-	cr := &connection.Record{}
-	err = json.Unmarshal(bytes, cr)
+	cr, err := connRec.GetConnectionRecordAtState(connRecord.ConnectionID, s.Name())
 	require.NoError(t, err)
 	require.Equal(t, cr, connRecord)
 }
@@ -1382,6 +1381,41 @@ func TestRequestRecord(t *testing.T) {
 		_, err = svc.requestMsgRecord(didcommMsg, service.EmptyDIDCommContext())
 		require.Error(t, err)
 	})
+
+	t.Run("fails if invitation is not valid", func(t *testing.T) {
+		svc, err := New(&protocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		var validated string
+
+		svc.SetInvitationValidator(func(invitationID string) error {
+			validated = invitationID
+
+			return errors.New("invitation expired")
+		})
+
+		didcommMsg := generateRequestMsgPayload(t, &protocol.MockProvider{}, randomString(), uuid.New().String())
+		_, err = svc.requestMsgRecord(didcommMsg, service.EmptyDIDCommContext())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invitation expired")
+		require.Equal(t, didcommMsg.ParentThreadID(), validated)
+	})
+
+	t.Run("sets the invitation use handler", func(t *testing.T) {
+		svc, err := New(&protocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		svc.SetInvitationUseHandler(func(string, string) error { return nil })
+		require.NotNil(t, svc.ctx.useInvitation)
+	})
 }
 
 func TestAcceptExchangeRequest(t *testing.T) {
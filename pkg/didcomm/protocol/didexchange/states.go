@@ -439,6 +439,13 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 		return nil, nil, fmt.Errorf("handle inbound request: %w", err)
 	}
 
+	// the request is accepted, it counts as a use of the invitation
+	if ctx.useInvitation != nil && connRec.InvitationID != "" {
+		if err = ctx.useInvitation(connRec.InvitationID, connRec.ConnectionID); err != nil {
+			return nil, nil, fmt.Errorf("use invitation: %w", err)
+		}
+	}
+
 	// send exchange response
 	return func() error {
 		return ctx.outboundDispatcher.Send(response, senderVerKey, destination)
@@ -520,7 +527,7 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 
 	newDID := &did.Doc{Service: services}
 
-	err := createNewKeyAndVerificationMethod(newDID, kms.ED25519, ctx.kms)
+	kid, err := createNewKeyAndVerificationMethod(newDID, kms.ED25519, ctx.kms)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create and export public key: %w", err)
 	}
@@ -531,6 +538,12 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 		return nil, nil, fmt.Errorf("create %s did: %w", didMethod, err)
 	}
 
+	// tagging the key is best effort, the KMS may not support metadata
+	err = kms.TagKey(ctx.kms, kid, kms.KeyPurposeDIDExchange, docResolution.DIDDocument.ID)
+	if err != nil {
+		logger.Warnf("set key metadata: %v", err)
+	}
+
 	if len(routerConnections) != 0 {
 		svc, ok := did.LookupService(docResolution.DIDDocument, didCommServiceType)
 		if ok {
@@ -559,17 +572,19 @@ func (ctx *context) getDIDDocAndConnection(pubDID string, routerConnections []st
 	return docResolution.DIDDocument, connection, nil
 }
 
-func createNewKeyAndVerificationMethod(didDoc *did.Doc, keyType kms.KeyType, keyManager kms.KeyManager) error {
+// createNewKeyAndVerificationMethod creates a new key, adds its verification method to didDoc and returns its ID.
+func createNewKeyAndVerificationMethod(didDoc *did.Doc, keyType kms.KeyType,
+	keyManager kms.KeyManager) (string, error) {
 	vmType := getVerMethodType(keyType)
 
 	kid, pubKeyBytes, err := keyManager.CreateAndExportPubKeyBytes(keyType)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	pubKeyBytes, err = convertPubKeyBytes(pubKeyBytes, keyType)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	vm := did.VerificationMethod{
@@ -585,7 +600,7 @@ func createNewKeyAndVerificationMethod(didDoc *did.Doc, keyType kms.KeyType, key
 	//  KeyAgreement is needed for envelope encryption regardless. It must be added in a future change.
 	didDoc.Authentication = append(didDoc.Authentication, *did.NewReferencedVerification(&vm, did.Authentication))
 
-	return nil
+	return kid, nil
 }
 
 /* func extractKeyTypeFromOpts(createDIDOpts *vdrapi.DIDMethodOpts) (kms.KeyType, error) {
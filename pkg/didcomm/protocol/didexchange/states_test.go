@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package didexchange
 
 import (
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
@@ -33,6 +34,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
@@ -999,6 +1001,34 @@ func TestNewResponseFromRequest(t *testing.T) {
 		require.NotNil(t, connRec.TheirDID)
 	})
 
+	t.Run("counts a use of the invitation once the request is accepted", func(t *testing.T) {
+		ctx := getContext(t, &prov)
+		request, err := createRequest(t, ctx)
+		require.NoError(t, err)
+
+		var used []string
+
+		ctx.useInvitation = func(invitationID, connectionID string) error {
+			used = append(used, invitationID, connectionID)
+
+			return nil
+		}
+
+		_, _, err = ctx.handleInboundRequest(request, &options{},
+			&connection.Record{ConnectionID: "connection", InvitationID: "invitation"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"invitation", "connection"}, used)
+
+		ctx.useInvitation = func(string, string) error {
+			return errors.New("invitation is used")
+		}
+
+		_, connRec, err := ctx.handleInboundRequest(request, &options{},
+			&connection.Record{ConnectionID: "connection", InvitationID: "invitation"})
+		require.EqualError(t, err, "use invitation: invitation is used")
+		require.Nil(t, connRec)
+	})
+
 	t.Run("unsuccessful new response from request due to get connection error", func(t *testing.T) {
 		ctx := getContext(t, &prov)
 		request, err := createRequest(t, ctx)
@@ -1223,6 +1253,28 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 		require.NotNil(t, didDoc)
 		require.NotNil(t, conn)
 		require.Equal(t, didDoc.ID, conn.DID)
+
+		keys, err := customKMS.List(&kms.KeyFilter{Purpose: kms.KeyPurposeDIDExchange})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, didDoc.ID, keys[0].DID)
+	})
+	t.Run("error setting key metadata is ignored", func(t *testing.T) {
+		didConnStore, err := didstore.NewConnectionStore(&protocol.MockProvider{})
+		require.NoError(t, err)
+		ctx := context{
+			kms: &mockkms.KeyManager{
+				CrAndExportPubKeyValue: make([]byte, ed25519.PublicKeySize),
+				SetMetadataErr:         errors.New("set metadata error"),
+			},
+			vdRegistry:      &mockvdr.MockVDRegistry{CreateValue: mockdiddoc.GetMockDIDDoc(t)},
+			connectionStore: didConnStore,
+			routeSvc:        &mockroute.MockMediatorSvc{},
+		}
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", nil)
+		require.NoError(t, err)
+		require.NotNil(t, didDoc)
+		require.NotNil(t, conn)
 	})
 	t.Run("test create did doc - router service config error", func(t *testing.T) {
 		connRec, err := connection.NewRecorder(&protocol.MockProvider{})
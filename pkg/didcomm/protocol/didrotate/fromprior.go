@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+)
+
+const (
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+	algEdDSA                   = "EdDSA"
+)
+
+// signFromPrior creates the from_prior JWT binding the new DID to the prior one.
+// The JWT is signed with the Ed25519 authentication key of the prior DID.
+func (s *Service) signFromPrior(priorDoc *did.Doc, newDID string) (string, error) {
+	var vm *did.VerificationMethod
+
+	authentications := priorDoc.VerificationMethods(did.Authentication)[did.Authentication]
+
+	for i := range authentications {
+		if authentications[i].VerificationMethod.Type == ed25519VerificationKey2018 {
+			vm = &authentications[i].VerificationMethod
+
+			break
+		}
+	}
+
+	if vm == nil {
+		return "", fmt.Errorf("no %s authentication method in did %s", ed25519VerificationKey2018, priorDoc.ID)
+	}
+
+	kid, err := localkms.CreateKID(vm.Value, kms.ED25519Type)
+	if err != nil {
+		return "", fmt.Errorf("create kid: %w", err)
+	}
+
+	kh, err := s.kms.Get(kid)
+	if err != nil {
+		return "", fmt.Errorf("get key handle: %w", err)
+	}
+
+	keyID := vm.ID
+	if strings.HasPrefix(keyID, "#") {
+		keyID = priorDoc.ID + keyID
+	}
+
+	token, err := jwt.NewSigned(&fromPriorClaims{
+		Issuer:   priorDoc.ID,
+		Subject:  newDID,
+		IssuedAt: time.Now().Unix(),
+	}, jose.Headers{jose.HeaderKeyID: keyID}, &signer{crypto: s.crypto, kh: kh})
+	if err != nil {
+		return "", fmt.Errorf("sign from_prior: %w", err)
+	}
+
+	return token.Serialize(false)
+}
+
+// verifyFromPrior checks the from_prior JWT is signed by the prior DID and binds it to the new DID.
+func (s *Service) verifyFromPrior(fromPrior, priorDID, newDID string) error {
+	token, err := jwt.Parse(fromPrior, jwt.WithSignatureVerifier(jwt.NewVerifier(jwt.KeyResolverFunc(s.resolveKey))))
+	if err != nil {
+		return err
+	}
+
+	var claims fromPriorClaims
+
+	if err = token.DecodeClaims(&claims); err != nil {
+		return fmt.Errorf("decode claims: %w", err)
+	}
+
+	if claims.Issuer != priorDID {
+		return fmt.Errorf("issued by %s instead of %s", claims.Issuer, priorDID)
+	}
+
+	if claims.Subject != newDID {
+		return fmt.Errorf("issued for %s instead of %s", claims.Subject, newDID)
+	}
+
+	return nil
+}
+
+// resolveKey resolves the authentication public key of the DID by its ID, the keys are matched by the fragment.
+// Only the authentication methods may sign from_prior, e.g a key agreement key is rejected.
+func (s *Service) resolveKey(didID, keyID string) (*verifier.PublicKey, error) {
+	docResolution, err := s.vdr.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+	}
+
+	for _, verification := range docResolution.DIDDocument.VerificationMethods(did.Authentication)[did.Authentication] {
+		if fragment(verification.VerificationMethod.ID) == fragment(keyID) {
+			return &verifier.PublicKey{
+				Type:  verification.VerificationMethod.Type,
+				Value: verification.VerificationMethod.Value,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("authentication key with KID %s is not found for DID %s", keyID, didID)
+}
+
+func fragment(keyID string) string {
+	return keyID[strings.LastIndex(keyID, "#")+1:]
+}
+
+// createNewKeyAndVerificationMethod adds a new Ed25519 key to the DID document.
+func createNewKeyAndVerificationMethod(didDoc *did.Doc, keyManager kms.KeyManager) error {
+	kid, pubKeyBytes, err := keyManager.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	if err != nil {
+		return err
+	}
+
+	vm := did.VerificationMethod{
+		ID:    "#" + kid,
+		Type:  ed25519VerificationKey2018,
+		Value: pubKeyBytes,
+	}
+
+	didDoc.VerificationMethod = append(didDoc.VerificationMethod, vm)
+	didDoc.Authentication = append(didDoc.Authentication, *did.NewReferencedVerification(&vm, did.Authentication))
+
+	return nil
+}
+
+// signer signs the from_prior JWT with the crypto.
+type signer struct {
+	crypto crypto.Crypto
+	kh     interface{}
+}
+
+func (s *signer) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.kh)
+}
+
+func (s *signer) Headers() jose.Headers {
+	return jose.Headers{jose.HeaderAlgorithm: algEdDSA}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Rotate message notifies the other party of the connection that the sender rotated its DID.
+type Rotate struct {
+	ID   string `json:"@id,omitempty"`
+	Type string `json:"@type,omitempty"`
+	// ToDID is the new DID of the sender.
+	ToDID string `json:"to_did"`
+	// DocAttach is an attachment containing the document of the new DID,
+	// it is provided if the DID cannot be resolved (e.g peer DID).
+	DocAttach *decorator.Attachment `json:"did_doc~attach,omitempty"`
+	// FromPrior is a JWT signed with the key of the prior DID (iss) binding it to the new DID (sub).
+	FromPrior string `json:"from_prior"`
+}
+
+// fromPriorClaims are the claims of the from_prior JWT.
+type fromPriorClaims struct {
+	Issuer   string `json:"iss"`
+	Subject  string `json:"sub"`
+	IssuedAt int64  `json:"iat"`
+}
@@ -0,0 +1,455 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// DIDRotate protocol name.
+	DIDRotate = "didrotate"
+	// DIDRotateSpec defines the DID rotate spec.
+	DIDRotateSpec = "https://didcomm.org/did-rotate/1.0/"
+	// RotateMsgType defines the DID rotate rotate message type.
+	RotateMsgType = DIDRotateSpec + "rotate"
+	// AckMsgType defines the DID rotate ack message type.
+	AckMsgType = DIDRotateSpec + "ack"
+
+	// DefaultGracePeriod is the period the messages addressed to the rotated DID are still accepted,
+	// unless another period is set by Service.SetGracePeriod.
+	DefaultGracePeriod = 24 * time.Hour
+
+	didMethod   = "peer"
+	ackStatusOK = "OK"
+)
+
+var logger = log.New("aries-framework/didrotate/service")
+
+// Provider contains dependencies for the DID rotate protocol and is typically created by using aries.Context().
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	VDRegistry() vdrapi.Registry
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	Service(id string) (interface{}, error)
+}
+
+// Service for DID rotate protocol.
+type Service struct {
+	messenger       service.Messenger
+	vdr             vdrapi.Registry
+	kms             kms.KeyManager
+	crypto          crypto.Crypto
+	connectionStore *connection.Recorder
+	didStore        *didstore.ConnectionStoreImpl
+	service         func(id string) (interface{}, error)
+	gracePeriod     time.Duration
+	gracePeriodMu   sync.RWMutex
+}
+
+// New returns DID rotate service.
+func New(p Provider) (*Service, error) {
+	connectionStore, err := connection.NewRecorder(p)
+	if err != nil {
+		return nil, fmt.Errorf("new connection recorder: %w", err)
+	}
+
+	didStore, err := didstore.NewConnectionStore(p)
+	if err != nil {
+		return nil, fmt.Errorf("new did connection store: %w", err)
+	}
+
+	return &Service{
+		messenger:       p.Messenger(),
+		vdr:             p.VDRegistry(),
+		kms:             p.KMS(),
+		crypto:          p.Crypto(),
+		connectionStore: connectionStore,
+		didStore:        didStore,
+		service:         p.Service,
+		gracePeriod:     DefaultGracePeriod,
+	}, nil
+}
+
+// rotateOpts keeps the options of the DID rotation.
+type rotateOpts struct {
+	newDID            string
+	routerConnections []string
+	gracePeriod       time.Duration
+}
+
+// Opt is an option for the DID rotation.
+type Opt func(opts *rotateOpts)
+
+// WithNewDID rotates to the given DID instead of creating a new peer DID.
+// The DID must be resolvable by the other party of the connection.
+func WithNewDID(newDID string) Opt {
+	return func(opts *rotateOpts) {
+		opts.newDID = newDID
+	}
+}
+
+// WithRouterConnections registers the keys of the new peer DID with the given routers.
+func WithRouterConnections(conns ...string) Opt {
+	return func(opts *rotateOpts) {
+		opts.routerConnections = conns
+	}
+}
+
+// WithGracePeriod sets the period the messages addressed to the rotated DID are still accepted.
+func WithGracePeriod(gracePeriod time.Duration) Opt {
+	return func(opts *rotateOpts) {
+		opts.gracePeriod = gracePeriod
+	}
+}
+
+// SetGracePeriod sets the period the messages addressed to a rotated DID are still accepted,
+// it applies to the DIDs rotated by the other party and to the DIDs rotated without the WithGracePeriod option.
+func (s *Service) SetGracePeriod(gracePeriod time.Duration) {
+	s.gracePeriodMu.Lock()
+	defer s.gracePeriodMu.Unlock()
+
+	s.gracePeriod = gracePeriod
+}
+
+func (s *Service) getGracePeriod() time.Duration {
+	s.gracePeriodMu.RLock()
+	defer s.gracePeriodMu.RUnlock()
+
+	return s.gracePeriod
+}
+
+// RotateDID replaces the DID used by the agent on the given connection. The other party is notified by
+// the rotate message signed with the key of the rotated DID. Returns the new DID.
+func (s *Service) RotateDID(connectionID string, opts ...Opt) (string, error) {
+	options := &rotateOpts{gracePeriod: s.getGracePeriod()}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	record, err := s.connectionStore.GetConnectionRecord(connectionID)
+	if err != nil {
+		return "", fmt.Errorf("get connection record: %w", err)
+	}
+
+	if record.State != connection.StateNameCompleted {
+		return "", fmt.Errorf("connection is not completed: state=%s", record.State)
+	}
+
+	oldDoc, err := s.vdr.Resolve(record.MyDID)
+	if err != nil {
+		return "", fmt.Errorf("resolve my did: %w", err)
+	}
+
+	newDoc, err := s.newDIDDoc(oldDoc.DIDDocument, options)
+	if err != nil {
+		return "", err
+	}
+
+	fromPrior, err := s.signFromPrior(oldDoc.DIDDocument, newDoc.ID)
+	if err != nil {
+		return "", err
+	}
+
+	rotate := &Rotate{
+		ID:        uuid.New().String(),
+		Type:      RotateMsgType,
+		ToDID:     newDoc.ID,
+		FromPrior: fromPrior,
+	}
+
+	if isPeerDID(newDoc.ID) {
+		rotate.DocAttach, err = docAttachment(newDoc)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// the messages sent to the new DID are accepted from now on
+	if err = s.didStore.SaveDIDFromDoc(newDoc); err != nil {
+		return "", fmt.Errorf("save new did: %w", err)
+	}
+
+	if err = s.messenger.Send(service.NewDIDCommMsgMap(rotate), record.MyDID, record.TheirDID); err != nil {
+		return "", fmt.Errorf("send rotate message: %w", err)
+	}
+
+	if err = s.didStore.SaveRotatedDID(record.MyDID, newDoc, options.gracePeriod); err != nil {
+		return "", fmt.Errorf("save rotated did: %w", err)
+	}
+
+	if err = s.connectionStore.UpdateConnectionDIDs(connectionID, newDoc.ID, record.TheirDID); err != nil {
+		return "", fmt.Errorf("update connection: %w", err)
+	}
+
+	logger.Debugf("rotated did: connectionID=%s old=%s new=%s", connectionID, record.MyDID, newDoc.ID)
+
+	return newDoc.ID, nil
+}
+
+// HandleInbound handles inbound DID rotate messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, ctx service.DIDCommContext) (string, error) {
+	switch msg.Type() {
+	case RotateMsgType:
+		return "", s.handleRotate(msg, ctx)
+	case AckMsgType:
+		logger.Debugf("rotated did acknowledged: myDID=%s theirDID=%s", ctx.MyDID(), ctx.TheirDID())
+
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+}
+
+// HandleOutbound is not supported, use RotateDID instead.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept msg checks the msg type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == RotateMsgType || msgType == AckMsgType
+}
+
+// Name returns service name.
+func (s *Service) Name() string {
+	return DIDRotate
+}
+
+func (s *Service) handleRotate(msg service.DIDCommMsg, ctx service.DIDCommContext) error {
+	var rotate Rotate
+
+	if err := msg.Decode(&rotate); err != nil {
+		return fmt.Errorf("decode rotate message: %w", err)
+	}
+
+	connectionID, err := s.connectionStore.GetConnectionIDByDIDs(ctx.MyDID(), ctx.TheirDID())
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+
+	// the rotation must be signed by the DID which is being rotated
+	if err = s.verifyFromPrior(rotate.FromPrior, ctx.TheirDID(), rotate.ToDID); err != nil {
+		return fmt.Errorf("verify from_prior: %w", err)
+	}
+
+	newDoc, err := s.resolveNewDID(&rotate)
+	if err != nil {
+		return err
+	}
+
+	if err = s.didStore.SaveRotatedDID(ctx.TheirDID(), newDoc, s.getGracePeriod()); err != nil {
+		return fmt.Errorf("save rotated did: %w", err)
+	}
+
+	if err = s.connectionStore.UpdateConnectionDIDs(connectionID, ctx.MyDID(), newDoc.ID); err != nil {
+		return fmt.Errorf("update connection: %w", err)
+	}
+
+	logger.Debugf("their did rotated: connectionID=%s old=%s new=%s", connectionID, ctx.TheirDID(), newDoc.ID)
+
+	ack := &model.Ack{
+		ID:     uuid.New().String(),
+		Type:   AckMsgType,
+		Status: ackStatusOK,
+	}
+
+	return s.messenger.ReplyToMsg(msg.(service.DIDCommMsgMap), service.NewDIDCommMsgMap(ack), ctx.MyDID(), newDoc.ID)
+}
+
+// resolveNewDID stores the provided peer DID document or resolves the new DID.
+func (s *Service) resolveNewDID(rotate *Rotate) (*did.Doc, error) {
+	if rotate.DocAttach == nil {
+		docResolution, err := s.vdr.Resolve(rotate.ToDID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve new did: %w", err)
+		}
+
+		return docResolution.DIDDocument, nil
+	}
+
+	docData, err := base64.StdEncoding.DecodeString(rotate.DocAttach.Data.Base64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base64 attachment data: %w", err)
+	}
+
+	doc, err := did.ParseDocument(docData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse did document: %w", err)
+	}
+
+	if doc.ID != rotate.ToDID || !isPeerDID(rotate.ToDID) {
+		return nil, fmt.Errorf("unexpected did document %s for did %s", doc.ID, rotate.ToDID)
+	}
+
+	_, err = s.vdr.Create(didMethod, doc, vdrapi.WithOption("store", true))
+	if err != nil {
+		return nil, fmt.Errorf("store new did document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// docAttachment attaches the DID document to the message.
+func docAttachment(doc *did.Doc) (*decorator.Attachment, error) {
+	docBytes, err := doc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal did document: %w", err)
+	}
+
+	return &decorator.Attachment{
+		ID:       uuid.New().String(),
+		MimeType: "application/json",
+		Data: decorator.AttachmentData{
+			Base64: base64.StdEncoding.EncodeToString(docBytes),
+		},
+	}, nil
+}
+
+// newDIDDoc creates a new peer DID having the same services as the rotated one.
+func (s *Service) newDIDDoc(oldDoc *did.Doc, opts *rotateOpts) (*did.Doc, error) {
+	if opts.newDID != "" {
+		docResolution, err := s.vdr.Resolve(opts.newDID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve new did: %w", err)
+		}
+
+		return docResolution.DIDDocument, nil
+	}
+
+	services, err := s.newServices(oldDoc, opts.routerConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc := &did.Doc{Service: services}
+
+	if err = createNewKeyAndVerificationMethod(newDoc, s.kms); err != nil {
+		return nil, fmt.Errorf("create new key: %w", err)
+	}
+
+	docResolution, err := s.vdr.Create(didMethod, newDoc)
+	if err != nil {
+		return nil, fmt.Errorf("create %s did: %w", didMethod, err)
+	}
+
+	if len(opts.routerConnections) != 0 {
+		if err = s.addKeysToRouters(docResolution.DIDDocument, opts.routerConnections); err != nil {
+			return nil, err
+		}
+	}
+
+	return docResolution.DIDDocument, nil
+}
+
+func (s *Service) newServices(oldDoc *did.Doc, routerConnections []string) ([]did.Service, error) {
+	var services []did.Service
+
+	if len(routerConnections) == 0 {
+		for _, svc := range oldDoc.Service {
+			if svc.Type != vdrapi.DIDCommServiceType {
+				continue
+			}
+
+			services = append(services, did.Service{
+				Type:            svc.Type,
+				ServiceEndpoint: svc.ServiceEndpoint,
+				RoutingKeys:     svc.RoutingKeys,
+			})
+		}
+	}
+
+	routeSvc, err := s.routeService(routerConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, connID := range routerConnections {
+		// the default service endpoint is added by the VDR
+		serviceEndpoint, routingKeys, err := mediator.GetRouterConfig(routeSvc, connID, "")
+		if err != nil {
+			return nil, fmt.Errorf("did doc - fetch router config: %w", err)
+		}
+
+		services = append(services, did.Service{ServiceEndpoint: serviceEndpoint, RoutingKeys: routingKeys})
+	}
+
+	if len(services) == 0 {
+		services = append(services, did.Service{})
+	}
+
+	return services, nil
+}
+
+func (s *Service) addKeysToRouters(doc *did.Doc, routerConnections []string) error {
+	routeSvc, err := s.routeService(routerConnections)
+	if err != nil {
+		return err
+	}
+
+	svc, ok := did.LookupService(doc, vdrapi.DIDCommServiceType)
+	if !ok {
+		return nil
+	}
+
+	for _, recKey := range svc.RecipientKeys {
+		for _, connID := range routerConnections {
+			if err := mediator.AddKeyToRouter(routeSvc, connID, recKey); err != nil {
+				return fmt.Errorf("did doc - add key to the router: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) routeService(routerConnections []string) (mediator.ProtocolService, error) {
+	if len(routerConnections) == 0 {
+		return nil, nil
+	}
+
+	svc, err := s.service(mediator.Coordination)
+	if err != nil {
+		return nil, fmt.Errorf("load the %s service: %w", mediator.Coordination, err)
+	}
+
+	routeSvc, ok := svc.(mediator.ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to route service failed")
+	}
+
+	return routeSvc, nil
+}
+
+func isPeerDID(id string) bool {
+	parsed, err := did.Parse(id)
+
+	return err == nil && parsed.Method == didMethod
+}
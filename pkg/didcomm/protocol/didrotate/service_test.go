@@ -0,0 +1,457 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const connectionID = "connection-id"
+
+func TestService_RotateDID(t *testing.T) {
+	t.Run("rotate and notify the other party", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+		oldDID := alice.did.ID
+
+		newDID, err := alice.svc.RotateDID(connectionID)
+		require.NoError(t, err)
+		require.NotEqual(t, oldDID, newDID)
+
+		// alice
+		record, err := alice.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, newDID, record.MyDID)
+		require.Equal(t, bob.did.ID, record.TheirDID)
+
+		// the messages sent to the old DID are accepted during the grace period
+		myDID, err := alice.svc.didStore.GetDID(recipientKey(t, alice.did))
+		require.NoError(t, err)
+		require.Equal(t, newDID, myDID)
+
+		require.Len(t, alice.messenger.sent, 1)
+		rotate := alice.messenger.sent[0]
+		require.Equal(t, RotateMsgType, rotate.msg.Type())
+		require.Equal(t, oldDID, rotate.myDID)
+		require.Equal(t, bob.did.ID, rotate.theirDID)
+
+		// bob
+		_, err = bob.svc.HandleInbound(rotate.msg, service.NewDIDCommContext(bob.did.ID, oldDID, nil))
+		require.NoError(t, err)
+
+		record, err = bob.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, bob.did.ID, record.MyDID)
+		require.Equal(t, newDID, record.TheirDID)
+
+		theirDID, err := bob.svc.didStore.GetDID(recipientKey(t, alice.did))
+		require.NoError(t, err)
+		require.Equal(t, newDID, theirDID)
+
+		connID, err := bob.connections.GetConnectionIDByDIDs(bob.did.ID, newDID)
+		require.NoError(t, err)
+		require.Equal(t, connectionID, connID)
+
+		require.Len(t, bob.messenger.sent, 1)
+		ack := bob.messenger.sent[0]
+		require.Equal(t, AckMsgType, ack.msg.Type())
+		require.Equal(t, newDID, ack.theirDID)
+
+		_, err = alice.svc.HandleInbound(ack.msg, service.NewDIDCommContext(newDID, bob.did.ID, nil))
+		require.NoError(t, err)
+	})
+
+	t.Run("rotate to the given DID", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+		oldDID := alice.did.ID
+		newDoc := createDID(t, alice.vdr, alice.kms)
+
+		newDID, err := alice.svc.RotateDID(connectionID, WithNewDID(newDoc.ID), WithGracePeriod(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, newDoc.ID, newDID)
+
+		_, err = bob.svc.HandleInbound(alice.messenger.sent[0].msg,
+			service.NewDIDCommContext(bob.did.ID, oldDID, nil))
+		require.NoError(t, err)
+
+		record, err := bob.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, newDID, record.TheirDID)
+	})
+
+	t.Run("rotate with router connections", func(t *testing.T) {
+		alice, _ := newConnectedAgents(t)
+		router := &mockroute.MockMediatorSvc{RoutingKeys: []string{"routing-key"}, RouterEndpoint: "http://router"}
+		alice.provider.services = map[string]interface{}{mediator.Coordination: router}
+
+		newDID, err := alice.svc.RotateDID(connectionID, WithRouterConnections("router-connection"))
+		require.NoError(t, err)
+
+		docResolution, err := alice.vdr.Resolve(newDID)
+		require.NoError(t, err)
+		require.Equal(t, "http://router", docResolution.DIDDocument.Service[0].ServiceEndpoint)
+		require.Equal(t, []string{"routing-key"}, docResolution.DIDDocument.Service[0].RoutingKeys)
+
+		alice.provider.services = nil
+
+		_, err = alice.svc.RotateDID(connectionID, WithRouterConnections("router-connection"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "load the coordinatemediation service")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		alice, _ := newConnectedAgents(t)
+		alice.messenger.err = errors.New("send error")
+
+		_, err := alice.svc.RotateDID(connectionID)
+		require.EqualError(t, err, "send rotate message: send error")
+
+		record, err := alice.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, alice.did.ID, record.MyDID)
+	})
+
+	t.Run("connection errors", func(t *testing.T) {
+		alice, _ := newConnectedAgents(t)
+
+		_, err := alice.svc.RotateDID("unknown")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		require.NoError(t, alice.connections.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "invited",
+			State:        "invited",
+		}))
+
+		_, err = alice.svc.RotateDID("invited")
+		require.EqualError(t, err, "connection is not completed: state=invited")
+	})
+
+	t.Run("unknown new DID", func(t *testing.T) {
+		alice, _ := newConnectedAgents(t)
+
+		_, err := alice.svc.RotateDID(connectionID, WithNewDID("did:peer:unknown"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve new did")
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("from_prior issued for another DID", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+		oldDID := alice.did.ID
+
+		_, err := alice.svc.RotateDID(connectionID)
+		require.NoError(t, err)
+
+		other := createDID(t, alice.vdr, alice.kms)
+
+		msg := alice.messenger.sent[0].msg
+		attachment, err := docAttachment(other)
+		require.NoError(t, err)
+
+		msg["to_did"] = other.ID
+		msg["did_doc~attach"] = attachment
+
+		_, err = bob.svc.HandleInbound(msg, service.NewDIDCommContext(bob.did.ID, oldDID, nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issued for")
+
+		record, err := bob.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, oldDID, record.TheirDID)
+	})
+
+	t.Run("from_prior issued by another DID", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+
+		_, err := alice.svc.RotateDID(connectionID)
+		require.NoError(t, err)
+
+		// bob's own DID is resolvable but it is not the DID being rotated
+		_, err = bob.svc.HandleInbound(alice.messenger.sent[0].msg,
+			service.NewDIDCommContext(bob.did.ID, bob.did.ID, nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection")
+
+		require.NoError(t, bob.connections.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "self",
+			State:        connection.StateNameCompleted,
+			MyDID:        bob.did.ID,
+			TheirDID:     bob.did.ID,
+		}))
+
+		_, err = bob.svc.HandleInbound(alice.messenger.sent[0].msg,
+			service.NewDIDCommContext(bob.did.ID, bob.did.ID, nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issued by")
+	})
+
+	t.Run("invalid from_prior", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+
+		_, err := alice.svc.RotateDID(connectionID)
+		require.NoError(t, err)
+
+		msg := alice.messenger.sent[0].msg
+		msg["from_prior"] = "invalid"
+
+		_, err = bob.svc.HandleInbound(msg, service.NewDIDCommContext(bob.did.ID, alice.did.ID, nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify from_prior")
+	})
+
+	t.Run("honors the configured grace period", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+		oldDID := alice.did.ID
+		bob.svc.SetGracePeriod(time.Nanosecond)
+
+		newDID, err := alice.svc.RotateDID(connectionID)
+		require.NoError(t, err)
+
+		_, err = bob.svc.HandleInbound(alice.messenger.sent[0].msg, service.NewDIDCommContext(bob.did.ID, oldDID, nil))
+		require.NoError(t, err)
+
+		// the grace period of the old DID is over
+		_, err = bob.svc.didStore.GetDID(recipientKey(t, alice.did))
+		require.ErrorIs(t, err, didstore.ErrNotFound)
+
+		record, err := bob.connections.GetConnectionRecord(connectionID)
+		require.NoError(t, err)
+		require.Equal(t, newDID, record.TheirDID)
+	})
+
+	t.Run("from_prior signed by a key agreement key", func(t *testing.T) {
+		alice, bob := newConnectedAgents(t)
+
+		// the only key of the DID is a key agreement key
+		doc := createDID(t, alice.vdr, alice.kms)
+		vm := doc.VerificationMethod[0]
+		doc.Authentication = nil
+		doc.AssertionMethod = nil
+		doc.KeyAgreement = []did.Verification{*did.NewReferencedVerification(&vm, did.KeyAgreement)}
+
+		_, err := bob.vdr.Create(peer.DIDMethod, doc, vdrapi.WithOption("store", true))
+		require.NoError(t, err)
+
+		_, err = bob.svc.resolveKey(doc.ID, vm.ID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "authentication key with KID")
+
+		_, err = alice.svc.signFromPrior(doc, alice.did.ID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no Ed25519VerificationKey2018 authentication method")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		alice, _ := newConnectedAgents(t)
+
+		_, err := alice.svc.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: "unknown"}), service.NewDIDCommContext("", "", nil))
+		require.EqualError(t, err, "unsupported message type unknown")
+	})
+}
+
+func TestService_Accept(t *testing.T) {
+	alice, _ := newConnectedAgents(t)
+
+	require.Equal(t, DIDRotate, alice.svc.Name())
+	require.True(t, alice.svc.Accept(RotateMsgType))
+	require.True(t, alice.svc.Accept(AckMsgType))
+	require.False(t, alice.svc.Accept("unknown"))
+
+	_, err := alice.svc.HandleOutbound(nil, "", "")
+	require.EqualError(t, err, "not implemented")
+}
+
+type agent struct {
+	svc         *Service
+	provider    *provider
+	messenger   *messenger
+	connections *connection.Recorder
+	vdr         vdrapi.Registry
+	kms         kms.KeyManager
+	did         *did.Doc
+}
+
+// newConnectedAgents returns two agents having a completed connection.
+func newConnectedAgents(t *testing.T) (*agent, *agent) {
+	t.Helper()
+
+	alice, bob := newAgent(t), newAgent(t)
+
+	for _, a := range []struct{ me, them *agent }{{alice, bob}, {bob, alice}} {
+		_, err := a.me.vdr.Create(peer.DIDMethod, a.them.did, vdrapi.WithOption("store", true))
+		require.NoError(t, err)
+
+		require.NoError(t, a.me.svc.didStore.SaveDIDFromDoc(a.me.did))
+		require.NoError(t, a.me.svc.didStore.SaveDIDFromDoc(a.them.did))
+
+		require.NoError(t, a.me.connections.SaveConnectionRecord(&connection.Record{
+			ConnectionID: connectionID,
+			State:        connection.StateNameCompleted,
+			MyDID:        a.me.did.ID,
+			TheirDID:     a.them.did.ID,
+		}))
+	}
+
+	return alice, bob
+}
+
+func newAgent(t *testing.T) *agent {
+	t.Helper()
+
+	storageProvider := mem.NewProvider()
+
+	peerVDR, err := peer.New(storageProvider)
+	require.NoError(t, err)
+
+	km, err := localkms.New("local-lock://test/key-uri/", mockkms.NewProviderForKMS(mem.NewProvider(), &noop.NoLock{}))
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	p := &provider{
+		storage:   storageProvider,
+		state:     mem.NewProvider(),
+		messenger: &messenger{},
+		vdr: vdr.New(vdr.WithVDR(peerVDR), vdr.WithDefaultServiceType(vdrapi.DIDCommServiceType),
+			vdr.WithDefaultServiceEndpoint("http://agent.example.com")),
+		kms:    km,
+		crypto: cr,
+	}
+
+	svc, err := New(p)
+	require.NoError(t, err)
+
+	connections, err := connection.NewRecorder(p)
+	require.NoError(t, err)
+
+	return &agent{
+		svc:         svc,
+		provider:    p,
+		messenger:   p.messenger,
+		connections: connections,
+		vdr:         p.vdr,
+		kms:         km,
+		did:         createDID(t, p.vdr, km),
+	}
+}
+
+func createDID(t *testing.T, registry vdrapi.Registry, km kms.KeyManager) *did.Doc {
+	t.Helper()
+
+	doc := &did.Doc{Service: []did.Service{{}}}
+	require.NoError(t, createNewKeyAndVerificationMethod(doc, km))
+
+	docResolution, err := registry.Create(peer.DIDMethod, doc)
+	require.NoError(t, err)
+
+	return docResolution.DIDDocument
+}
+
+func recipientKey(t *testing.T, doc *did.Doc) string {
+	t.Helper()
+
+	require.NotEmpty(t, doc.VerificationMethod)
+
+	return base58.Encode(doc.VerificationMethod[0].Value)
+}
+
+type provider struct {
+	storage   storage.Provider
+	state     storage.Provider
+	messenger *messenger
+	vdr       vdrapi.Registry
+	kms       kms.KeyManager
+	crypto    crypto.Crypto
+	services  map[string]interface{}
+}
+
+func (p *provider) Messenger() service.Messenger                   { return p.messenger }
+func (p *provider) StorageProvider() storage.Provider              { return p.storage }
+func (p *provider) ProtocolStateStorageProvider() storage.Provider { return p.state }
+func (p *provider) VDRegistry() vdrapi.Registry                    { return p.vdr }
+func (p *provider) KMS() kms.KeyManager                            { return p.kms }
+func (p *provider) Crypto() crypto.Crypto                          { return p.crypto }
+
+func (p *provider) Service(id string) (interface{}, error) {
+	svc, ok := p.services[id]
+	if !ok {
+		return nil, errors.New("service not found")
+	}
+
+	return svc, nil
+}
+
+type sentMsg struct {
+	msg      service.DIDCommMsgMap
+	myDID    string
+	theirDID string
+}
+
+// messenger keeps the sent messages.
+type messenger struct {
+	service.Messenger
+	sent []sentMsg
+	err  error
+}
+
+func (m *messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.sent = append(m.sent, sentMsg{msg: roundTrip(msg), myDID: myDID, theirDID: theirDID})
+
+	return nil
+}
+
+func (m *messenger) ReplyToMsg(_, out service.DIDCommMsgMap, myDID, theirDID string) error {
+	m.sent = append(m.sent, sentMsg{msg: roundTrip(out), myDID: myDID, theirDID: theirDID})
+
+	return nil
+}
+
+// roundTrip simulates the transport, the message is received as a JSON object.
+func roundTrip(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+	b, err := msg.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+
+	received, err := service.ParseDIDCommMsgMap(b)
+	if err != nil {
+		panic(err)
+	}
+
+	return received
+}
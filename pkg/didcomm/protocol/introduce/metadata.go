@@ -21,6 +21,9 @@ const (
 	metaOOBMessage   = Introduce + "_oobmessage"
 	metaRecipients   = Introduce + "_recipients"
 	metaAttachment   = Introduce + "_attachment"
+	// the number of recipients of the proposals and the index of the recipient.
+	metaIntroducees    = Introduce + "_introducees"
+	metaRecipientIndex = Introduce + "_recipient_index"
 )
 
 // Opt describes option signature for the Continue function.
@@ -51,24 +54,32 @@ func WithPublicOOBInvitation(inv *outofband.Invitation, to *To) Opt {
 
 // WithRecipients is used when the introducer does not have a public invitation
 // but he is willing to introduce agents to each other.
+// The agent who sent the request is introduced to every recipient.
 // NOTE: Introducer can provide recipients only after receiving RequestMsgType.
 // USAGE: event.Continue(WithRecipients(to, recipient)).
-func WithRecipients(to *To, recipient *Recipient) Opt {
+func WithRecipients(to *To, recipient *Recipient, recipients ...*Recipient) Opt {
 	return func(m map[string]interface{}) {
-		m[metaRecipients] = []interface{}{
-			&Recipient{To: to}, recipient,
+		all := []interface{}{&Recipient{To: to}, recipient}
+
+		for _, r := range recipients {
+			all = append(all, r)
 		}
+
+		m[metaRecipients] = all
 	}
 }
 
 // WrapWithMetadataPIID wraps message with metadata.
 // The function is used by the introduce client to define that a few messages are related to each other.
-// e.g When two proposals are sent simultaneously piID helps the protocol to determine that messages are related.
+// e.g When proposals are sent simultaneously piID helps the protocol to determine that messages are related.
+// The recipient of the first message is introduced to the recipients of the other messages.
 func WrapWithMetadataPIID(msgMap ...service.DIDCommMsg) {
 	piID := uuid.New().String()
 
-	for _, msg := range msgMap {
+	for i, msg := range msgMap {
 		msg.Metadata()[metaPIID] = piID
+		msg.Metadata()[metaIntroducees] = len(msgMap)
+		msg.Metadata()[metaRecipientIndex] = i
 	}
 }
 
@@ -80,6 +91,18 @@ func WrapWithMetadataPublicOOBInvitation(msg service.DIDCommMsgMap, req *outofba
 	msg.Metadata()[metaSkipProposal] = true
 }
 
+// metadataInt returns the number saved in the metadata, the numbers are float64 once the metadata was stored.
+func metadataInt(msg service.DIDCommMsg, key string) (int, bool) {
+	switch v := msg.Metadata()[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+
+	return 0, false
+}
+
 func copyMetadata(from, to service.DIDCommMsg) {
 	for k, v := range from.Metadata() {
 		to.Metadata()[k] = v
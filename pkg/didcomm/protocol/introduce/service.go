@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -42,8 +43,24 @@ const (
 )
 
 const (
-	maxIntroducees         = 2
+	// RecipientStatePending the proposal was sent to the recipient, the response was not received yet.
+	RecipientStatePending = "pending"
+	// RecipientStateApproved the recipient approved the introduction.
+	RecipientStateApproved = "approved"
+	// RecipientStateDeclined the recipient declined the introduction.
+	RecipientStateDeclined = "declined"
+	// RecipientStateIntroduced the out-of-band invitation was delivered to or by the recipient.
+	RecipientStateIntroduced = "introduced"
+	// RecipientStateFailed the recipient approved the introduction, but it could not be completed
+	// e.g. neither the recipient nor the agent it is introduced to shared an out-of-band invitation.
+	RecipientStateFailed = "failed"
+)
+
+const (
+	// the proposals are sent to two recipients unless the introducer provides the number of recipients.
+	defaultIntroducees     = 2
 	participantsKey        = "participants_%s_%s"
+	recipientsKey          = "recipients_%s"
 	stateNameKey           = "state_name_"
 	transitionalPayloadKey = "transitionalPayload_%s"
 	metadataKey            = "metadata_%s"
@@ -70,6 +87,33 @@ type Recipient struct {
 	TheirDID string `json:"their_did,omitempty"`
 }
 
+// RecipientStatus keeps track of the introduction for a recipient of the proposals sent by the introducer.
+type RecipientStatus struct {
+	To       *To    `json:"to,omitempty"`
+	MyDID    string `json:"my_did,omitempty"`
+	TheirDID string `json:"their_did,omitempty"`
+	// State is one of pending, approved, declined, introduced or failed.
+	State string `json:"state"`
+}
+
+func (r *RecipientStatus) merge(update *RecipientStatus) {
+	if update.To != nil {
+		r.To = update.To
+	}
+
+	if update.MyDID != "" {
+		r.MyDID = update.MyDID
+	}
+
+	if update.TheirDID != "" {
+		r.TheirDID = update.TheirDID
+	}
+
+	if update.State != "" {
+		r.State = update.State
+	}
+}
+
 // Action contains helpful information about action.
 type Action struct {
 	// Protocol instance ID
@@ -77,6 +121,8 @@ type Action struct {
 	Msg      service.DIDCommMsgMap
 	MyDID    string
 	TheirDID string
+	// Recipients is the status of the introduction per recipient (introducer only).
+	Recipients []*RecipientStatus `json:",omitempty"`
 }
 
 // transitionalPayload keeps payload needed for Continue function to proceed with the action.
@@ -93,6 +139,8 @@ type metaData struct {
 	participants []*participant
 	rejected     bool
 	inbound      bool
+	// recipients keeps the updates of the recipients status by the index of the recipient
+	recipients   map[int]*RecipientStatus
 	saveMetadata func(msg service.DIDCommMsgMap, thID string) error
 	// err is used to determine whether callback was stopped
 	// e.g the user received an action event and executes Stop(err) function
@@ -100,14 +148,36 @@ type metaData struct {
 	err error
 }
 
+func (md *metaData) updateRecipient(index int, update *RecipientStatus) {
+	if md.recipients == nil {
+		md.recipients = map[int]*RecipientStatus{}
+	}
+
+	if md.recipients[index] == nil {
+		md.recipients[index] = &RecipientStatus{}
+	}
+
+	md.recipients[index].merge(update)
+}
+
+// introducees returns the number of recipients of the proposals.
+func (md *metaData) introducees() int {
+	if n, ok := metadataInt(md.Msg, metaIntroducees); ok {
+		return n
+	}
+
+	return defaultIntroducees
+}
+
 // Service for introduce protocol.
 type Service struct {
 	service.Action
 	service.Message
-	store     storage.Store
-	callbacks chan *metaData
-	oobEvent  chan service.StateMsg
-	messenger service.Messenger
+	store           storage.Store
+	callbacks       chan *metaData
+	oobEvent        chan service.StateMsg
+	messenger       service.Messenger
+	recipientsMutex sync.Mutex
 }
 
 // Provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context().
@@ -521,6 +591,11 @@ func (s *Service) Actions() ([]Action, error) {
 			return nil, fmt.Errorf("unmarshal: %w", errUnmarshal)
 		}
 
+		action.Recipients, err = s.RecipientStatuses(action.PIID)
+		if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+			return nil, fmt.Errorf("recipient statuses: %w", err)
+		}
+
 		actions = append(actions, action)
 
 		more, err = records.Next()
@@ -650,6 +725,10 @@ func (s *Service) handle(md *metaData) error {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
+	if err := s.saveRecipientStatuses(md.PIID, md.recipients); err != nil {
+		return fmt.Errorf("save recipient statuses: %w", err)
+	}
+
 	for _, action := range actions {
 		if err := action(); err != nil {
 			return err
@@ -659,6 +738,52 @@ func (s *Service) handle(md *metaData) error {
 	return nil
 }
 
+// RecipientStatuses returns the status of the introduction per recipient of the proposals (introducer only).
+// The recipients are ordered as the proposals were sent, the first recipient is introduced to the others.
+func (s *Service) RecipientStatuses(piID string) ([]*RecipientStatus, error) {
+	src, err := s.store.Get(fmt.Sprintf(recipientsKey, piID))
+	if err != nil {
+		return nil, fmt.Errorf("get recipient statuses: %w", err)
+	}
+
+	var statuses []*RecipientStatus
+
+	if err = json.Unmarshal(src, &statuses); err != nil {
+		return nil, fmt.Errorf("unmarshal recipient statuses: %w", err)
+	}
+
+	return statuses, nil
+}
+
+func (s *Service) saveRecipientStatuses(piID string, updates map[int]*RecipientStatus) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	s.recipientsMutex.Lock()
+	defer s.recipientsMutex.Unlock()
+
+	statuses, err := s.RecipientStatuses(piID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	for index, update := range updates {
+		for len(statuses) <= index {
+			statuses = append(statuses, &RecipientStatus{State: RecipientStatePending})
+		}
+
+		statuses[index].merge(update)
+	}
+
+	src, err := json.Marshal(statuses)
+	if err != nil {
+		return fmt.Errorf("marshal recipient statuses: %w", err)
+	}
+
+	return s.store.Put(fmt.Sprintf(recipientsKey, piID), src)
+}
+
 func contextOOBMessage(msg service.DIDCommMsg) map[string]interface{} {
 	var oobMsg map[string]interface{}
 
@@ -677,6 +802,8 @@ func contextOOBMessage(msg service.DIDCommMsg) map[string]interface{} {
 }
 
 type participant struct {
+	// Index of the recipient the proposal was sent to.
+	Index      int
 	OOBMessage map[string]interface{}
 	Approve    bool
 	Message    service.DIDCommMsgMap
@@ -709,7 +836,26 @@ func (s *Service) saveResponse(md *metaData) error {
 		return fmt.Errorf("threadID: %w", err)
 	}
 
+	index, ok := metadataInt(md.Msg, metaRecipientIndex)
+	if !ok {
+		// the index is unknown (e.g. the proposal with a public invitation), the responses are indexed by arrival
+		participants, errGet := s.getParticipants(md.PIID)
+		if errGet != nil {
+			return fmt.Errorf("get participants: %w", errGet)
+		}
+
+		index = len(participants)
+	}
+
+	state := RecipientStateDeclined
+	if r.Approve {
+		state = RecipientStateApproved
+	}
+
+	md.updateRecipient(index, &RecipientStatus{MyDID: md.MyDID, TheirDID: md.TheirDID, State: state})
+
 	err = s.saveParticipant(md.PIID, &participant{
+		Index:      index,
 		OOBMessage: r.OOBMessage,
 		Approve:    r.Approve,
 		Message:    md.Msg,
@@ -39,6 +39,8 @@ const (
 	Bob = "Bob"
 	// Bob always plays introducee (second) role.
 	Carol = "Carol"
+	// Dave plays introducee (third) role.
+	Dave = "Dave"
 )
 
 type props interface {
@@ -814,6 +816,216 @@ func TestService_ProposalStopIntroduceeSecond(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// checkRecipientStatuses waits until the introducer has persisted the expected recipient states.
+func checkRecipientStatuses(t *testing.T, svc *introduce.Service, piID chan string, expected ...string) {
+	t.Helper()
+
+	id := <-piID
+
+	require.Eventually(t, func() bool {
+		statuses, err := svc.RecipientStatuses(id)
+		if err != nil || len(statuses) != len(expected) {
+			return false
+		}
+
+		for i, status := range statuses {
+			if status.State != expected[i] {
+				return false
+			}
+		}
+
+		return true
+	}, time.Second, time.Millisecond*10)
+}
+
+// introducerPIID forwards the PIID of the first state message to the given channel.
+func introducerPIID(piID chan string, ce checkEvent) checkEvent {
+	var once bool
+
+	return func(msg service.StateMsg) {
+		if !once {
+			once = true
+
+			properties, ok := msg.Properties.(props)
+			if ok {
+				piID <- properties.PIID()
+			}
+		}
+
+		ce(msg)
+	}
+}
+
+// Bob received proposal from Alice.
+// Carol received proposal from Alice.
+// Dave received proposal from Alice.
+// Alice received response from Bob.
+// Alice received response from Carol.
+// Alice received response from Dave ( not approved ).
+// Carol received invitation from Alice.
+// Bob received ack from Alice.
+func TestService_ProposalMultipleRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transport := map[string]chan payload{
+		Alice: make(chan payload),
+		Bob:   make(chan payload),
+		Carol: make(chan payload),
+		Dave:  make(chan payload),
+	}
+
+	alice := agentSetup(t, Alice, ctrl, transport)
+
+	done := make(chan struct{}, len(transport)*2)
+	piID := make(chan string, 1)
+
+	handle(t, Alice, done, alice, introducerPIID(piID, checkStateMsg(t, Alice,
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"delivering", "delivering",
+		"confirming", "confirming",
+		"done", "done",
+	)), nil)
+
+	handle(t, Bob, done, agentSetup(t, Bob, ctrl, transport), checkStateMsg(t, Bob,
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"done", "done",
+	), checkDIDCommAction(t, Bob,
+		action{
+			Expected: introduce.ProposalMsgType,
+			Opt: introduce.WithOOBInvitation(&outofband.Invitation{
+				Type: outofband.InvitationMsgType,
+			}),
+		},
+	))
+
+	handle(t, Carol, done, agentSetup(t, Carol, ctrl, transport), checkStateMsg(t, Carol,
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"done", "done",
+	), checkDIDCommAction(t, Carol, action{Expected: introduce.ProposalMsgType}))
+
+	handle(t, Dave, done, agentSetup(t, Dave, ctrl, transport), checkStateMsg(t, Dave,
+		"deciding", "deciding",
+		"abandoning", "abandoning",
+		"done", "done",
+	), func(action service.DIDCommAction) {
+		action.Stop(errors.New("hmm... I don't wanna know him"))
+		runtime.Goexit()
+	})
+
+	proposal1 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Carol}, GoalCode: "p2p-messaging"})
+	proposal2 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Bob}, GoalCode: "p2p-messaging"})
+	proposal3 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Bob}, GoalCode: "p2p-messaging"})
+
+	introduce.WrapWithMetadataPIID(proposal1, proposal2, proposal3)
+
+	_, err := alice.HandleOutbound(proposal1, Alice, Bob)
+	require.NoError(t, err)
+
+	_, err = alice.HandleOutbound(proposal2, Alice, Carol)
+	require.NoError(t, err)
+
+	_, err = alice.HandleOutbound(proposal3, Alice, Dave)
+	require.NoError(t, err)
+
+	wait(t, done)
+
+	checkRecipientStatuses(t, alice, piID,
+		introduce.RecipientStateIntroduced, introduce.RecipientStateIntroduced, introduce.RecipientStateDeclined)
+}
+
+// Bob received proposal from Alice.
+// Carol received proposal from Alice.
+// Dave received proposal from Alice.
+// Alice received response from Bob.
+// Alice received response from Carol.
+// Alice received response from Dave.
+// Bob received invitation from Alice.
+// Dave received problem-report from Alice ( no oob message ).
+// Carol received ack from Alice.
+func TestService_ProposalMultipleRecipientsPartial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transport := map[string]chan payload{
+		Alice: make(chan payload),
+		Bob:   make(chan payload),
+		Carol: make(chan payload),
+		Dave:  make(chan payload),
+	}
+
+	alice := agentSetup(t, Alice, ctrl, transport)
+
+	done := make(chan struct{}, len(transport)*2)
+	piID := make(chan string, 1)
+
+	handle(t, Alice, done, alice, introducerPIID(piID, checkStateMsg(t, Alice,
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"arranging", "arranging",
+		"delivering", "delivering",
+		"confirming", "confirming",
+		"done", "done",
+	)), nil)
+
+	handle(t, Bob, done, agentSetup(t, Bob, ctrl, transport), checkStateMsg(t, Bob,
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"done", "done",
+	), checkDIDCommAction(t, Bob, action{Expected: introduce.ProposalMsgType}))
+
+	handle(t, Carol, done, agentSetup(t, Carol, ctrl, transport), checkStateMsg(t, Carol,
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"done", "done",
+	), checkDIDCommAction(t, Carol,
+		action{
+			Expected: introduce.ProposalMsgType,
+			Opt: introduce.WithOOBInvitation(&outofband.Invitation{
+				Type: outofband.InvitationMsgType,
+			}),
+		},
+	))
+
+	handle(t, Dave, done, agentSetup(t, Dave, ctrl, transport), checkStateMsg(t, Dave,
+		"deciding", "deciding",
+		"waiting", "waiting",
+		"abandoning", "abandoning",
+		"done", "done",
+	), checkDIDCommAction(t, Dave, action{Expected: introduce.ProposalMsgType},
+		action{Expected: introduce.ProblemReportMsgType}))
+
+	proposal1 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Carol}})
+	proposal2 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Bob}})
+	proposal3 := introduce.CreateProposal(&introduce.Recipient{To: &introduce.To{Name: Bob}})
+
+	introduce.WrapWithMetadataPIID(proposal1, proposal2, proposal3)
+
+	_, err := alice.HandleOutbound(proposal1, Alice, Bob)
+	require.NoError(t, err)
+
+	_, err = alice.HandleOutbound(proposal2, Alice, Carol)
+	require.NoError(t, err)
+
+	_, err = alice.HandleOutbound(proposal3, Alice, Dave)
+	require.NoError(t, err)
+
+	wait(t, done)
+
+	checkRecipientStatuses(t, alice, piID,
+		introduce.RecipientStateIntroduced, introduce.RecipientStateIntroduced, introduce.RecipientStateFailed)
+}
+
 // Carol received proposal from Alice.
 // Bob received proposal from Alice.
 // Alice received response from Bob.
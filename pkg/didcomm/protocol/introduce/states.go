@@ -146,9 +146,26 @@ func isApproved(md *metaData) bool {
 	return true
 }
 
-func hasOOBMessage(md *metaData) bool {
+// firstRecipient returns the participant who is introduced to the other participants.
+func firstRecipient(participants []*participant) *participant {
+	for _, p := range participants {
+		if p.Index == 0 {
+			return p
+		}
+	}
+
+	return participants[0]
+}
+
+// canIntroduce returns true if the first recipient and at least one of the other recipients approved the introduction.
+func canIntroduce(md *metaData) bool {
+	first := firstRecipient(md.participants)
+	if !first.Approve {
+		return false
+	}
+
 	for _, p := range md.participants {
-		if p.OOBMessage != nil {
+		if p != first && p.Approve {
 			return true
 		}
 	}
@@ -156,6 +173,45 @@ func hasOOBMessage(md *metaData) bool {
 	return false
 }
 
+// introduction delivers the out-of-band message of the inviter to the invitee.
+type introduction struct {
+	inviter *participant
+	invitee *participant
+}
+
+// introductions introduces the first recipient to every other recipient who approved the introduction.
+// The out-of-band message of the participant who responded first is delivered to the other one.
+// The recipients who cannot be introduced since neither of them shared an out-of-band message are returned as well.
+func introductions(md *metaData) ([]introduction, []*participant) {
+	var (
+		intros []introduction
+		failed []*participant
+		first  = firstRecipient(md.participants)
+	)
+
+	for _, p := range md.participants {
+		if p == first || !p.Approve {
+			continue
+		}
+
+		earlier, later := first, p
+		if p.CreatedAt.Before(first.CreatedAt) {
+			earlier, later = p, first
+		}
+
+		switch {
+		case earlier.OOBMessage != nil:
+			intros = append(intros, introduction{inviter: earlier, invitee: later})
+		case later.OOBMessage != nil:
+			intros = append(intros, introduction{inviter: later, invitee: earlier})
+		default:
+			failed = append(failed, p)
+		}
+	}
+
+	return intros, failed
+}
+
 func getMetaRecipients(md *metaData) []*Recipient {
 	_recipients, ok := md.Msg.Metadata()[metaRecipients].([]interface{})
 	if !ok {
@@ -188,11 +244,13 @@ func CreateProposal(r *Recipient) service.DIDCommMsgMap {
 	})
 }
 
-func sendProposals(messenger service.Messenger, md *metaData) error {
-	for _, recipient := range getMetaRecipients(md) {
+func sendProposals(messenger service.Messenger, md *metaData, recipients []*Recipient) error {
+	for i, recipient := range recipients {
 		proposal := CreateProposal(recipient)
 		proposal.Metadata()[metaPIID] = md.PIID
 		copyMetadata(md.Msg, proposal)
+		proposal.Metadata()[metaIntroducees] = len(recipients)
+		proposal.Metadata()[metaRecipientIndex] = i
 
 		var (
 			err  error
@@ -228,8 +286,21 @@ func sendProposals(messenger service.Messenger, md *metaData) error {
 
 func (s *arranging) ExecuteInbound(messenger service.Messenger, md *metaData) (state, stateAction, error) {
 	if md.Msg.Type() == RequestMsgType {
+		recipients := getMetaRecipients(md)
+
+		for i, recipient := range recipients {
+			update := &RecipientStatus{To: recipient.To, MyDID: recipient.MyDID, TheirDID: recipient.TheirDID}
+
+			// the first recipient is the agent who sent the request
+			if i == 0 {
+				update.MyDID, update.TheirDID = md.MyDID, md.TheirDID
+			}
+
+			md.updateRecipient(i, update)
+		}
+
 		return &noOp{}, func() error {
-			return sendProposals(messenger, md)
+			return sendProposals(messenger, md, recipients)
 		}, nil
 	}
 
@@ -241,12 +312,13 @@ func (s *arranging) ExecuteInbound(messenger service.Messenger, md *metaData) (s
 		return &delivering{}, zeroAction, nil
 	}
 
+	// waits for the responses of all recipients
 	count := len(md.participants)
-	if count != maxIntroducees || md.participants[count-1].TheirDID != md.TheirDID {
+	if count != md.introducees() || md.participants[count-1].TheirDID != md.TheirDID {
 		return &noOp{}, zeroAction, nil
 	}
 
-	if !isApproved(md) {
+	if !canIntroduce(md) {
 		return &abandoning{Code: codeNotApproved}, zeroAction, nil
 	}
 
@@ -254,6 +326,19 @@ func (s *arranging) ExecuteInbound(messenger service.Messenger, md *metaData) (s
 }
 
 func (s *arranging) ExecuteOutbound(messenger service.Messenger, md *metaData) (state, stateAction, error) {
+	proposal := Proposal{}
+	if err := md.Msg.Decode(&proposal); err != nil {
+		return nil, nil, fmt.Errorf("decode proposal: %w", err)
+	}
+
+	index, _ := metadataInt(md.Msg, metaRecipientIndex)
+	md.updateRecipient(index, &RecipientStatus{
+		To:       proposal.To,
+		MyDID:    md.MyDID,
+		TheirDID: md.TheirDID,
+		State:    RecipientStatePending,
+	})
+
 	return &noOp{}, func() error {
 		if md.Msg.ID() == "" {
 			if err := md.Msg.SetID(uuid.New().String()); err != nil {
@@ -287,6 +372,10 @@ func deliveringSkipInvitation(messenger service.Messenger, md *metaData) (state,
 		return nil, nil, err
 	}
 
+	for _, p := range md.participants {
+		md.updateRecipient(p.Index, &RecipientStatus{State: RecipientStateIntroduced})
+	}
+
 	return &done{}, func() error {
 		msg := contextOOBMessage(md.Msg)
 
@@ -299,32 +388,48 @@ func (s *delivering) ExecuteInbound(messenger service.Messenger, md *metaData) (
 		return deliveringSkipInvitation(messenger, md)
 	}
 
+	intros, failed := introductions(md)
+
 	// edge case: no one shared an oob message
-	if !hasOOBMessage(md) {
+	if len(intros) == 0 {
 		return &abandoning{Code: codeNoOOBMessage}, zeroAction, nil
 	}
 
-	var msg service.DIDCommMsgMap
-
-	var participants []*participant
+	for _, intro := range intros {
+		md.updateRecipient(intro.inviter.Index, &RecipientStatus{State: RecipientStateIntroduced})
+		md.updateRecipient(intro.invitee.Index, &RecipientStatus{State: RecipientStateIntroduced})
+	}
 
-	for _, participant := range md.participants {
-		if participant.OOBMessage != nil && msg == nil {
-			msg = participant.OOBMessage
-		} else {
-			participants = append(participants, participant)
-		}
+	for _, p := range failed {
+		md.updateRecipient(p.Index, &RecipientStatus{State: RecipientStateFailed})
 	}
 
 	return &confirming{}, func() error {
-		for _, p := range participants {
-			err := messenger.ReplyToNested(msg,
-				&service.NestedReplyOpts{ThreadID: p.ThreadID, MyDID: p.MyDID, TheirDID: p.TheirDID})
+		for _, intro := range intros {
+			err := messenger.ReplyToNested(service.DIDCommMsgMap(intro.inviter.OOBMessage).Clone(),
+				&service.NestedReplyOpts{
+					ThreadID: intro.invitee.ThreadID,
+					MyDID:    intro.invitee.MyDID,
+					TheirDID: intro.invitee.TheirDID,
+				})
 			if err != nil {
 				return fmt.Errorf("reply to nested: %w", err)
 			}
 		}
 
+		// the introduction of the other recipients succeeded, notifies the recipients who cannot be introduced
+		for _, p := range failed {
+			err := messenger.ReplyToNested(service.NewDIDCommMsgMap(model.ProblemReport{
+				Type: ProblemReportMsgType,
+				Description: model.Code{
+					Code: codeNoOOBMessage,
+				},
+			}), &service.NestedReplyOpts{ThreadID: p.ThreadID, MyDID: p.MyDID, TheirDID: p.TheirDID})
+			if err != nil {
+				return fmt.Errorf("send problem-report: %w", err)
+			}
+		}
+
 		return nil
 	}, nil
 }
@@ -345,23 +450,40 @@ func (s *confirming) CanTransitionTo(next state) bool {
 }
 
 func (s *confirming) ExecuteInbound(messenger service.Messenger, md *metaData) (state, stateAction, error) {
-	msgMap := service.NewDIDCommMsgMap(model.Ack{
-		Type: AckMsgType,
-	})
+	intros, _ := introductions(md)
 
-	var p *participant
+	// the participants whose out-of-band message was delivered
+	var inviters []*participant
 
-	for _, participant := range md.participants {
-		if participant.OOBMessage == nil {
-			continue
+	for _, intro := range intros {
+		if !containsParticipant(inviters, intro.inviter) {
+			inviters = append(inviters, intro.inviter)
 		}
+	}
 
-		p = participant
+	return &done{}, func() error {
+		for _, p := range inviters {
+			ack := service.NewDIDCommMsgMap(model.Ack{
+				Type: AckMsgType,
+			})
 
-		break
+			if err := messenger.ReplyToMsg(p.Message, ack, md.MyDID, p.TheirDID); err != nil {
+				return fmt.Errorf("send ack: %w", err)
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+func containsParticipant(participants []*participant, p *participant) bool {
+	for _, participant := range participants {
+		if participant == p {
+			return true
+		}
 	}
 
-	return &done{}, func() error { return messenger.ReplyToMsg(p.Message, msgMap, md.MyDID, p.TheirDID) }, nil
+	return false
 }
 
 func (s *confirming) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, stateAction, error) {
@@ -421,6 +543,12 @@ func (s *abandoning) ExecuteInbound(messenger service.Messenger, md *metaData) (
 		}}
 	}
 
+	for _, p := range md.participants {
+		if p.Approve {
+			md.updateRecipient(p.Index, &RecipientStatus{State: RecipientStateFailed})
+		}
+	}
+
 	return &done{}, func() error {
 		// notifies participants about error
 		for _, recipient := range md.participants {
@@ -475,6 +603,9 @@ func (s *deciding) ExecuteInbound(messenger service.Messenger, md *metaData) (st
 
 	return st, func() error {
 		msg := contextOOBMessage(md.Msg)
+		if msg != nil {
+			setInvitationGoal(msg, md.Msg)
+		}
 
 		var attch []*decorator.Attachment
 
@@ -502,6 +633,23 @@ func (s *deciding) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, sta
 	return nil, nil, errors.New("deciding: ExecuteOutbound function is not supposed to be used")
 }
 
+// setInvitationGoal sets the goal of the introduction proposed by the introducer to the out-of-band invitation
+// shared by the introducee unless the invitation has its own goal. The version of the invitation is kept.
+func setInvitationGoal(inv map[string]interface{}, proposalMsg service.DIDCommMsgMap) {
+	proposal := Proposal{}
+	if err := proposalMsg.Decode(&proposal); err != nil {
+		return
+	}
+
+	if inv["goal"] == nil && proposal.Goal != "" {
+		inv["goal"] = proposal.Goal
+	}
+
+	if inv["goal_code"] == nil && proposal.GoalCode != "" {
+		inv["goal_code"] = proposal.GoalCode
+	}
+}
+
 // waiting state.
 type waiting struct{}
 
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 )
 
@@ -411,33 +413,74 @@ func Test_sendProposals(t *testing.T) {
 	msg := service.NewDIDCommMsgMap(struct{}{})
 	require.NoError(t, msg.SetID(uuid.New().String()))
 
-	msg.Metadata()[metaRecipients] = map[string]int{}
-
 	require.NoError(t, sendProposals(messenger, &metaData{
 		transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
-	}))
+	}, nil))
 
-	msg.Metadata()[metaRecipients] = []interface{}{&Recipient{}}
 	require.Contains(t, fmt.Sprintf("%v", sendProposals(messenger, &metaData{
 		transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
 		saveMetadata:        func(_ service.DIDCommMsgMap, _ string) error { return nil },
-	})), errMsg)
+	}, []*Recipient{{}})), errMsg)
 
 	require.Contains(t, fmt.Sprintf("%v", sendProposals(messenger, &metaData{
 		transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
 		saveMetadata:        func(_ service.DIDCommMsgMap, _ string) error { return errors.New(errMsg) },
-	})), errMsg)
+	}, []*Recipient{{}})), errMsg)
 
 	msg = service.NewDIDCommMsgMap(struct{}{})
-	msg.Metadata()[metaRecipients] = []interface{}{&Recipient{}}
 	require.EqualError(t, sendProposals(messenger, &metaData{
 		transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
-	}), "get threadID: threadID not found")
+	}, []*Recipient{{}}), "get threadID: threadID not found")
 
 	msg = service.NewDIDCommMsgMap(struct{}{})
-	msg.Metadata()[metaRecipients] = []interface{}{&Recipient{MyDID: "my_did"}}
 	require.EqualError(t, sendProposals(messenger, &metaData{
 		transitionalPayload: transitionalPayload{Action: Action{Msg: msg}},
 		saveMetadata:        func(_ service.DIDCommMsgMap, _ string) error { return errors.New(errMsg) },
-	}), "save metadata: test error")
+	}, []*Recipient{{MyDID: "my_did"}}), "save metadata: test error")
+}
+
+func Test_introductions(t *testing.T) {
+	now := time.Now()
+	oob := map[string]interface{}{"@type": outofband.InvitationMsgType}
+
+	bob := &participant{Index: 0, Approve: true, CreatedAt: now.Add(time.Second)}
+	carol := &participant{Index: 1, Approve: true, OOBMessage: oob, CreatedAt: now}
+	dave := &participant{Index: 2, Approve: true, CreatedAt: now.Add(time.Second * 2)}
+	eve := &participant{Index: 3, Approve: false, OOBMessage: oob, CreatedAt: now}
+
+	md := &metaData{participants: []*participant{carol, bob, dave, eve}}
+	require.True(t, canIntroduce(md))
+
+	intros, failed := introductions(md)
+	require.Equal(t, []introduction{{inviter: carol, invitee: bob}}, intros)
+	require.Equal(t, []*participant{dave}, failed)
+
+	bob.OOBMessage = oob
+
+	intros, failed = introductions(md)
+	require.Equal(t, []introduction{{inviter: carol, invitee: bob}, {inviter: bob, invitee: dave}}, intros)
+	require.Empty(t, failed)
+
+	bob.Approve = false
+	require.False(t, canIntroduce(md))
+
+	require.False(t, canIntroduce(&metaData{participants: []*participant{{Approve: true}, {Index: 1}}}))
+}
+
+func Test_setInvitationGoal(t *testing.T) {
+	proposal := CreateProposal(&Recipient{Goal: "To issue a credential", GoalCode: "issue-vc"})
+
+	inv := map[string]interface{}{"@type": outofband.InvitationMsgType}
+	setInvitationGoal(inv, proposal)
+	require.Equal(t, map[string]interface{}{
+		"@type":     outofband.InvitationMsgType,
+		"goal":      "To issue a credential",
+		"goal_code": "issue-vc",
+	}, inv)
+
+	inv = map[string]interface{}{"@type": outofband.InvitationMsgTypeV11, "goal_code": "p2p-messaging"}
+	setInvitationGoal(inv, proposal)
+	require.Equal(t, outofband.InvitationMsgTypeV11, inv["@type"])
+	require.Equal(t, "p2p-messaging", inv["goal_code"])
+	require.Equal(t, "To issue a credential", inv["goal"])
 }
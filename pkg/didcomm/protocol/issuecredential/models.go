@@ -6,13 +6,18 @@ SPDX-License-Identifier: Apache-2.0
 
 package issuecredential
 
-import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
 
 // ProposeCredential is an optional message sent by the potential Holder to the Issuer
 // to initiate the protocol or in response to a offer-credential message when the Holder
 // wants some adjustments made to the credential data offered by Issuer.
 type ProposeCredential struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
 	// so the offer can be evaluated by human judgment.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300]
@@ -29,6 +34,8 @@ type ProposeCredential struct {
 }
 
 // Format contains the the value of the attachment @id and the verifiable credential format of the attachment.
+// Besides credential formats, the credential manifest, application and fulfillment formats are supported
+// (see the formats defined by the doc/cm package).
 type Format struct {
 	AttachID string `json:"attach_id,omitempty"`
 	Format   string `json:"format,omitempty"`
@@ -39,6 +46,12 @@ type Format struct {
 // TODO: Need to add ~payment_request and ~timing.expires_time decorators [Issue #1297].
 type OfferCredential struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// ReplacementID is an optional field to help coordinate credential replacement.
+	ReplacementID string `json:"replacement_id,omitempty"`
+	// MultipleAvailable is an optional field to indicate that multiple credentials are available.
+	MultipleAvailable string `json:"multiple_available,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
 	// so the offer can be evaluated by human judgment.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300].
@@ -61,6 +74,8 @@ type OfferCredential struct {
 // TODO: Need to add ~payment-receipt decorator [Issue #1298].
 type RequestCredential struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
 	// so the offer can be evaluated by human judgment.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300].
@@ -77,6 +92,10 @@ type RequestCredential struct {
 // TODO: Need to add ~please-ack decorator [Issue #1299].
 type IssueCredential struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// ReplacementID is an optional field to help coordinate credential replacement.
+	ReplacementID string `json:"replacement_id,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
 	// so the offer can be evaluated by human judgment.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300].
@@ -100,3 +119,346 @@ type Attribute struct {
 	MimeType string `json:"mime-type,omitempty"`
 	Value    string `json:"value,omitempty"`
 }
+
+// ProposeCredentialV3 is the issue-credential 3.0 (DIDComm V2) version of the ProposeCredential message.
+type ProposeCredentialV3 struct {
+	ID   string                  `json:"id,omitempty"`
+	Type string                  `json:"type,omitempty"`
+	Body ProposeCredentialV3Body `json:"body,omitempty"`
+	// Attachments is an array of attachments that further define the credential being proposed.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// ProposeCredentialV3Body represents the body of the ProposeCredentialV3 message.
+type ProposeCredentialV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is an optional field that provides human readable information about this proposal.
+	Comment string `json:"comment,omitempty"`
+	// CredentialPreview is an optional object that represents the credential data that the Prover wants to receive.
+	CredentialPreview *PreviewCredentialV3 `json:"credential_preview,omitempty"`
+}
+
+// OfferCredentialV3 is the issue-credential 3.0 (DIDComm V2) version of the OfferCredential message.
+type OfferCredentialV3 struct {
+	ID   string                `json:"id,omitempty"`
+	Type string                `json:"type,omitempty"`
+	Body OfferCredentialV3Body `json:"body,omitempty"`
+	// Attachments is a slice of attachments that further define the credential being offered.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// OfferCredentialV3Body represents the body of the OfferCredentialV3 message.
+type OfferCredentialV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is an optional field that provides human readable information about this Credential Offer.
+	Comment string `json:"comment,omitempty"`
+	// ReplacementID is an optional field to help coordinate credential replacement.
+	ReplacementID string `json:"replacement_id,omitempty"`
+	// MultipleAvailable is an optional field to indicate that multiple credentials are available.
+	MultipleAvailable string `json:"multiple_available,omitempty"`
+	// CredentialPreview is an object that represents the credential data that Issuer is willing to issue.
+	CredentialPreview *PreviewCredentialV3 `json:"credential_preview,omitempty"`
+}
+
+// RequestCredentialV3 is the issue-credential 3.0 (DIDComm V2) version of the RequestCredential message.
+type RequestCredentialV3 struct {
+	ID   string                  `json:"id,omitempty"`
+	Type string                  `json:"type,omitempty"`
+	Body RequestCredentialV3Body `json:"body,omitempty"`
+	// Attachments is a slice of attachments defining the requested formats for the credential.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// RequestCredentialV3Body represents the body of the RequestCredentialV3 message.
+type RequestCredentialV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is an optional field that provides human readable information about this request.
+	Comment string `json:"comment,omitempty"`
+}
+
+// IssueCredentialV3 is the issue-credential 3.0 (DIDComm V2) version of the IssueCredential message.
+type IssueCredentialV3 struct {
+	ID   string                `json:"id,omitempty"`
+	Type string                `json:"type,omitempty"`
+	Body IssueCredentialV3Body `json:"body,omitempty"`
+	// Attachments is a slice of attachments containing the issued credentials.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// IssueCredentialV3Body represents the body of the IssueCredentialV3 message.
+type IssueCredentialV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// ReplacementID is an optional field to help coordinate credential replacement.
+	ReplacementID string `json:"replacement_id,omitempty"`
+	// Comment is an optional field that provides human readable information about the issued credentials.
+	Comment string `json:"comment,omitempty"`
+}
+
+// PreviewCredentialV3 is the issue-credential 3.0 (DIDComm V2) version of the PreviewCredential.
+type PreviewCredentialV3 struct {
+	ID   string                  `json:"id,omitempty"`
+	Type string                  `json:"type,omitempty"`
+	Body PreviewCredentialV3Body `json:"body,omitempty"`
+}
+
+// PreviewCredentialV3Body represents the body of the PreviewCredentialV3.
+type PreviewCredentialV3Body struct {
+	Attributes []Attribute `json:"attributes,omitempty"`
+}
+
+// AsV3 converts the message to the issue-credential 3.0 (DIDComm V2) format.
+func (p *ProposeCredential) AsV3() *ProposeCredentialV3 {
+	return &ProposeCredentialV3{
+		Type: ProposeCredentialMsgTypeV3,
+		Body: ProposeCredentialV3Body{
+			GoalCode:          p.GoalCode,
+			Comment:           p.Comment,
+			CredentialPreview: previewAsV3(p.CredentialProposal),
+		},
+		Attachments: attachmentsAsV3(p.Formats, p.FiltersAttach),
+	}
+}
+
+// AsV2 converts the message to the issue-credential 2.0 format.
+func (p *ProposeCredentialV3) AsV2() *ProposeCredential {
+	formats, attachments := attachmentsAsV2(p.Attachments)
+
+	return &ProposeCredential{
+		Type:               ProposeCredentialMsgType,
+		GoalCode:           p.Body.GoalCode,
+		Comment:            p.Body.Comment,
+		CredentialProposal: previewAsV2(p.Body.CredentialPreview),
+		Formats:            formats,
+		FiltersAttach:      attachments,
+	}
+}
+
+// AsV3 converts the message to the issue-credential 3.0 (DIDComm V2) format.
+func (o *OfferCredential) AsV3() *OfferCredentialV3 {
+	return &OfferCredentialV3{
+		Type: OfferCredentialMsgTypeV3,
+		Body: OfferCredentialV3Body{
+			GoalCode:          o.GoalCode,
+			Comment:           o.Comment,
+			ReplacementID:     o.ReplacementID,
+			MultipleAvailable: o.MultipleAvailable,
+			CredentialPreview: previewAsV3(o.CredentialPreview),
+		},
+		Attachments: attachmentsAsV3(o.Formats, o.OffersAttach),
+	}
+}
+
+// AsV2 converts the message to the issue-credential 2.0 format.
+func (o *OfferCredentialV3) AsV2() *OfferCredential {
+	formats, attachments := attachmentsAsV2(o.Attachments)
+
+	return &OfferCredential{
+		Type:              OfferCredentialMsgType,
+		GoalCode:          o.Body.GoalCode,
+		Comment:           o.Body.Comment,
+		ReplacementID:     o.Body.ReplacementID,
+		MultipleAvailable: o.Body.MultipleAvailable,
+		CredentialPreview: previewAsV2(o.Body.CredentialPreview),
+		Formats:           formats,
+		OffersAttach:      attachments,
+	}
+}
+
+// AsV3 converts the message to the issue-credential 3.0 (DIDComm V2) format.
+func (r *RequestCredential) AsV3() *RequestCredentialV3 {
+	return &RequestCredentialV3{
+		Type:        RequestCredentialMsgTypeV3,
+		Body:        RequestCredentialV3Body{GoalCode: r.GoalCode, Comment: r.Comment},
+		Attachments: attachmentsAsV3(r.Formats, r.RequestsAttach),
+	}
+}
+
+// AsV2 converts the message to the issue-credential 2.0 format.
+func (r *RequestCredentialV3) AsV2() *RequestCredential {
+	formats, attachments := attachmentsAsV2(r.Attachments)
+
+	return &RequestCredential{
+		Type:           RequestCredentialMsgType,
+		GoalCode:       r.Body.GoalCode,
+		Comment:        r.Body.Comment,
+		Formats:        formats,
+		RequestsAttach: attachments,
+	}
+}
+
+// AsV3 converts the message to the issue-credential 3.0 (DIDComm V2) format.
+func (i *IssueCredential) AsV3() *IssueCredentialV3 {
+	return &IssueCredentialV3{
+		Type: IssueCredentialMsgTypeV3,
+		Body: IssueCredentialV3Body{
+			GoalCode:      i.GoalCode,
+			ReplacementID: i.ReplacementID,
+			Comment:       i.Comment,
+		},
+		Attachments: attachmentsAsV3(i.Formats, i.CredentialsAttach),
+	}
+}
+
+// AsV2 converts the message to the issue-credential 2.0 format.
+func (i *IssueCredentialV3) AsV2() *IssueCredential {
+	formats, attachments := attachmentsAsV2(i.Attachments)
+
+	return &IssueCredential{
+		Type:              IssueCredentialMsgType,
+		GoalCode:          i.Body.GoalCode,
+		ReplacementID:     i.Body.ReplacementID,
+		Comment:           i.Body.Comment,
+		Formats:           formats,
+		CredentialsAttach: attachments,
+	}
+}
+
+// DecodeProposeCredential decodes the propose-credential message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeProposeCredential(msg service.DIDCommMsg) (*ProposeCredential, error) {
+	if isV3(msg) {
+		v3 := &ProposeCredentialV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &ProposeCredential{}
+
+	return v2, msg.Decode(v2)
+}
+
+// DecodeOfferCredential decodes the offer-credential message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeOfferCredential(msg service.DIDCommMsg) (*OfferCredential, error) {
+	if isV3(msg) {
+		v3 := &OfferCredentialV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &OfferCredential{}
+
+	return v2, msg.Decode(v2)
+}
+
+// DecodeRequestCredential decodes the request-credential message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeRequestCredential(msg service.DIDCommMsg) (*RequestCredential, error) {
+	if isV3(msg) {
+		v3 := &RequestCredentialV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &RequestCredential{}
+
+	return v2, msg.Decode(v2)
+}
+
+// DecodeIssueCredential decodes the issue-credential message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeIssueCredential(msg service.DIDCommMsg) (*IssueCredential, error) {
+	if isV3(msg) {
+		v3 := &IssueCredentialV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &IssueCredential{}
+
+	return v2, msg.Decode(v2)
+}
+
+func previewAsV3(preview PreviewCredential) *PreviewCredentialV3 {
+	if len(preview.Attributes) == 0 {
+		return nil
+	}
+
+	return &PreviewCredentialV3{
+		Type: CredentialPreviewMsgTypeV3,
+		Body: PreviewCredentialV3Body{Attributes: preview.Attributes},
+	}
+}
+
+func previewAsV2(preview *PreviewCredentialV3) PreviewCredential {
+	if preview == nil {
+		return PreviewCredential{}
+	}
+
+	return PreviewCredential{
+		Type:       CredentialPreviewMsgType,
+		Attributes: preview.Body.Attributes,
+	}
+}
+
+// attachmentsAsV3 merges the formats into the DIDComm V2 attachments (format is a part of the attachment).
+func attachmentsAsV3(formats []Format, attachments []decorator.Attachment) []decorator.AttachmentV2 {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	attachFormats := make(map[string]string, len(formats))
+	for _, format := range formats {
+		attachFormats[format.AttachID] = format.Format
+	}
+
+	result := make([]decorator.AttachmentV2, len(attachments))
+	for i, a := range attachments {
+		result[i] = decorator.AttachmentV2{
+			ID:          a.ID,
+			Description: a.Description,
+			FileName:    a.FileName,
+			MediaType:   a.MimeType,
+			Format:      attachFormats[a.ID],
+			LastModTime: a.LastModTime,
+			ByteCount:   a.ByteCount,
+			Data:        a.Data,
+		}
+	}
+
+	return result
+}
+
+// attachmentsAsV2 splits the DIDComm V2 attachments into the formats and attachments.
+func attachmentsAsV2(attachments []decorator.AttachmentV2) ([]Format, []decorator.Attachment) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	var formats []Format
+
+	result := make([]decorator.Attachment, len(attachments))
+	for i, a := range attachments {
+		result[i] = decorator.Attachment{
+			ID:          a.ID,
+			Description: a.Description,
+			FileName:    a.FileName,
+			MimeType:    a.MediaType,
+			LastModTime: a.LastModTime,
+			ByteCount:   a.ByteCount,
+			Data:        a.Data,
+		}
+
+		if a.Format != "" {
+			formats = append(formats, Format{AttachID: a.ID, Format: a.Format})
+		}
+	}
+
+	return formats, result
+}
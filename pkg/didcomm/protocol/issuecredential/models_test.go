@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+func TestMessagesV3(t *testing.T) {
+	formats := []Format{{AttachID: "ID1", Format: "format"}}
+	attachments := []decorator.Attachment{
+		{ID: "ID1", MimeType: "application/json", Data: decorator.AttachmentData{Base64: "ZGF0YQ=="}},
+		{ID: "ID2"},
+	}
+	preview := PreviewCredential{
+		Type:       CredentialPreviewMsgType,
+		Attributes: []Attribute{{Name: "name", Value: "value"}},
+	}
+
+	t.Run("Propose credential", func(t *testing.T) {
+		propose := &ProposeCredential{
+			Type:               ProposeCredentialMsgType,
+			GoalCode:           "goal",
+			Comment:            "comment",
+			CredentialProposal: preview,
+			Formats:            formats,
+			FiltersAttach:      attachments,
+		}
+
+		v3 := propose.AsV3()
+		require.Equal(t, ProposeCredentialMsgTypeV3, v3.Type)
+		require.Equal(t, "goal", v3.Body.GoalCode)
+		require.Equal(t, CredentialPreviewMsgTypeV3, v3.Body.CredentialPreview.Type)
+		require.Equal(t, "format", v3.Attachments[0].Format)
+		require.Equal(t, "application/json", v3.Attachments[0].MediaType)
+
+		decoded, err := DecodeProposeCredential(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, propose, decoded)
+	})
+
+	t.Run("Offer credential", func(t *testing.T) {
+		offer := &OfferCredential{
+			Type:              OfferCredentialMsgType,
+			GoalCode:          "goal",
+			ReplacementID:     "replacement",
+			MultipleAvailable: "2",
+			CredentialPreview: preview,
+			Formats:           formats,
+			OffersAttach:      attachments,
+		}
+
+		v3 := offer.AsV3()
+		require.Equal(t, OfferCredentialMsgTypeV3, v3.Type)
+		require.Equal(t, "replacement", v3.Body.ReplacementID)
+		require.Equal(t, "2", v3.Body.MultipleAvailable)
+
+		decoded, err := DecodeOfferCredential(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, offer, decoded)
+
+		decoded, err = DecodeOfferCredential(service.NewDIDCommMsgMap(offer))
+		require.NoError(t, err)
+		require.Equal(t, offer, decoded)
+	})
+
+	t.Run("Request credential", func(t *testing.T) {
+		request := &RequestCredential{
+			Type:           RequestCredentialMsgType,
+			GoalCode:       "goal",
+			Formats:        formats,
+			RequestsAttach: attachments,
+		}
+
+		v3 := request.AsV3()
+		require.Equal(t, RequestCredentialMsgTypeV3, v3.Type)
+
+		decoded, err := DecodeRequestCredential(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, request, decoded)
+	})
+
+	t.Run("Issue credential", func(t *testing.T) {
+		issue := &IssueCredential{
+			Type:              IssueCredentialMsgType,
+			ReplacementID:     "replacement",
+			Formats:           formats,
+			CredentialsAttach: attachments,
+		}
+
+		v3 := issue.AsV3()
+		require.Equal(t, IssueCredentialMsgTypeV3, v3.Type)
+
+		decoded, err := DecodeIssueCredential(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, issue, decoded)
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		_, err := DecodeIssueCredential(service.DIDCommMsgMap{"type": IssueCredentialMsgTypeV3, "body": "invalid"})
+		require.Error(t, err)
+	})
+}
@@ -10,11 +10,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
@@ -37,6 +39,23 @@ const (
 	ProblemReportMsgType = Spec + "problem-report"
 	// CredentialPreviewMsgType defines the protocol credential-preview inner object type.
 	CredentialPreviewMsgType = Spec + "credential-preview"
+
+	// SpecV3 defines the protocol spec for DIDComm V2 agents.
+	SpecV3 = "https://didcomm.org/issue-credential/3.0/"
+	// ProposeCredentialMsgTypeV3 defines the protocol propose-credential message type (version 3.0).
+	ProposeCredentialMsgTypeV3 = SpecV3 + "propose-credential"
+	// OfferCredentialMsgTypeV3 defines the protocol offer-credential message type (version 3.0).
+	OfferCredentialMsgTypeV3 = SpecV3 + "offer-credential"
+	// RequestCredentialMsgTypeV3 defines the protocol request-credential message type (version 3.0).
+	RequestCredentialMsgTypeV3 = SpecV3 + "request-credential"
+	// IssueCredentialMsgTypeV3 defines the protocol issue-credential message type (version 3.0).
+	IssueCredentialMsgTypeV3 = SpecV3 + "issue-credential"
+	// AckMsgTypeV3 defines the protocol ack message type (version 3.0).
+	AckMsgTypeV3 = SpecV3 + "ack"
+	// ProblemReportMsgTypeV3 defines the protocol problem-report message type (version 3.0).
+	ProblemReportMsgTypeV3 = SpecV3 + "problem-report"
+	// CredentialPreviewMsgTypeV3 defines the protocol credential-preview inner object type (version 3.0).
+	CredentialPreviewMsgTypeV3 = SpecV3 + "credential-preview"
 )
 
 const (
@@ -51,6 +70,7 @@ var (
 		return nil
 	})
 	errProtocolStopped = errors.New("protocol was stopped")
+	errStateTimeout    = errors.New("state timeout")
 )
 
 // customError is a wrapper to determine custom error against internal error.
@@ -60,6 +80,7 @@ type customError struct{ error }
 type transitionalPayload struct {
 	Action
 	StateName string
+	CreatedAt time.Time
 }
 
 // metaData type to store data for internal usage.
@@ -180,6 +201,7 @@ type Service struct {
 	callbacks  chan *metaData
 	messenger  service.Messenger
 	middleware Handler
+	threads    *threads.Tracker
 }
 
 // New returns the issuecredential service.
@@ -189,7 +211,9 @@ func New(p Provider) (*Service, error) {
 		return nil, err
 	}
 
-	err = p.StorageProvider().SetStoreConfig(Name, storage.StoreConfiguration{TagNames: []string{transitionalPayloadKey}})
+	err = p.StorageProvider().SetStoreConfig(Name, storage.StoreConfiguration{
+		TagNames: []string{transitionalPayloadKey, stateNameKey, threads.CallbackKey},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store config: %w", err)
 	}
@@ -201,9 +225,25 @@ func New(p Provider) (*Service, error) {
 		middleware: initialHandler,
 	}
 
+	svc.threads = threads.New(store, stateNameKey, svc.abandonTimedOut, stateNameDone, stateNameAbandoning)
+
+	pending, err := svc.pendingCallbacks()
+	if err != nil {
+		return nil, fmt.Errorf("pending callbacks: %w", err)
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
+	// resumes the callbacks which were interrupted by the agent restart
+	if len(pending) > 0 {
+		go func() {
+			for _, md := range pending {
+				svc.callbacks <- md
+			}
+		}()
+	}
+
 	return svc, nil
 }
 
@@ -274,33 +314,33 @@ func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string)
 	return msg.ThreadID()
 }
 
-func (s *Service) getCurrentStateNameAndPIID(msg service.DIDCommMsg) (string, string, error) {
+func (s *Service) getCurrentThreadAndPIID(msg service.DIDCommMsg) (string, *Thread, error) {
 	piID, err := getPIID(msg)
 	if errors.Is(err, service.ErrThreadIDNotFound) {
 		piID = uuid.New().String()
 
-		return piID, stateNameStart, msg.SetID(piID)
+		return piID, &Thread{PIID: piID, StateName: stateNameStart}, msg.SetID(piID)
 	}
 
 	if err != nil {
-		return "", "", fmt.Errorf("piID: %w", err)
+		return "", nil, fmt.Errorf("piID: %w", err)
 	}
 
-	stateName, err := s.currentStateName(piID)
+	thread, err := s.currentThread(piID)
 	if err != nil {
-		return "", "", fmt.Errorf("currentStateName: %w", err)
+		return "", nil, fmt.Errorf("currentThread: %w", err)
 	}
 
-	return piID, stateName, nil
+	return piID, thread, nil
 }
 
 func (s *Service) doHandle(msg service.DIDCommMsg, outbound bool) (*metaData, error) {
-	piID, stateName, err := s.getCurrentStateNameAndPIID(msg)
+	piID, thread, err := s.getCurrentThreadAndPIID(msg)
 	if err != nil {
-		return nil, fmt.Errorf("getCurrentStateNameAndPIID: %w", err)
+		return nil, fmt.Errorf("getCurrentThreadAndPIID: %w", err)
 	}
 
-	current := stateFromName(stateName)
+	current := stateFromName(thread.StateName)
 
 	next, err := nextState(msg, outbound)
 	if err != nil {
@@ -314,6 +354,7 @@ func (s *Service) doHandle(msg service.DIDCommMsg, outbound bool) (*metaData, er
 	return &metaData{
 		transitionalPayload: transitionalPayload{
 			StateName: next.Name(),
+			CreatedAt: thread.CreatedAt,
 			Action: Action{
 				Msg:  msg.Clone(),
 				PIID: piID,
@@ -328,22 +369,30 @@ func (s *Service) doHandle(msg service.DIDCommMsg, outbound bool) (*metaData, er
 // startInternalListener listens to messages in go channel for callback messages from clients.
 func (s *Service) startInternalListener() {
 	for msg := range s.callbacks {
-		// if no error do handle
-		if msg.err == nil {
-			msg.err = s.handle(msg)
-		}
+		s.handleCallback(msg)
 
-		// no error - continue
-		if msg.err == nil {
-			continue
+		if err := s.deleteCallback(msg.PIID); err != nil {
+			logger.Errorf("delete callback: %s", err)
 		}
+	}
+}
 
-		logger.Errorf("abandoning: %s", msg.err)
-		msg.state = &abandoning{Code: codeInternalError}
+func (s *Service) handleCallback(msg *metaData) {
+	// if no error do handle
+	if msg.err == nil {
+		msg.err = s.handle(msg)
+	}
 
-		if err := s.handle(msg); err != nil {
-			logger.Errorf("listener handle: %s", err)
-		}
+	// no error - continue
+	if msg.err == nil {
+		return
+	}
+
+	logger.Errorf("abandoning: %s", msg.err)
+	msg.state = &abandoning{Code: abandonCode(msg.err)}
+
+	if err := s.handle(msg); err != nil {
+		logger.Errorf("listener handle: %s", err)
 	}
 }
 
@@ -376,7 +425,7 @@ func (s *Service) handle(md *metaData) error {
 		current = next
 	}
 
-	if err := s.saveStateName(md.PIID, stateName); err != nil {
+	if err := s.saveThread(md, stateName); err != nil {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
@@ -397,17 +446,54 @@ func getPIID(msg service.DIDCommMsg) (string, error) {
 	return msg.ThreadID()
 }
 
-func (s *Service) saveStateName(piID, stateName string) error {
-	return s.store.Put(stateNameKey+piID, []byte(stateName))
+func (s *Service) saveThread(md *metaData, stateName string) error {
+	now := time.Now()
+
+	if md.CreatedAt.IsZero() {
+		md.CreatedAt = now
+	}
+
+	thread := &Thread{
+		PIID:      md.PIID,
+		StateName: stateName,
+		MyDID:     md.MyDID,
+		TheirDID:  md.TheirDID,
+		Msg:       md.Msg,
+		CreatedAt: md.CreatedAt,
+		UpdatedAt: now,
+	}
+
+	src, err := json.Marshal(thread)
+	if err != nil {
+		return fmt.Errorf("marshal thread: %w", err)
+	}
+
+	if err = s.store.Put(stateNameKey+md.PIID, src, storage.Tag{Name: stateNameKey}); err != nil {
+		return err
+	}
+
+	s.threads.Track(thread)
+
+	return nil
 }
 
-func (s *Service) currentStateName(piID string) (string, error) {
+func (s *Service) currentThread(piID string) (*Thread, error) {
 	src, err := s.store.Get(stateNameKey + piID)
 	if errors.Is(err, storage.ErrDataNotFound) {
-		return stateNameStart, nil
+		return &Thread{PIID: piID, StateName: stateNameStart}, nil
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return string(src), err
+	thread := &Thread{}
+	if err = json.Unmarshal(src, thread); err != nil {
+		// the state was persisted by an earlier version as a plain state name
+		return &Thread{PIID: piID, StateName: string(src)}, nil
+	}
+
+	return thread, nil
 }
 
 // nolint: gocyclo
@@ -443,29 +529,29 @@ func stateFromName(name string) state {
 
 func nextState(msg service.DIDCommMsg, outbound bool) (state, error) {
 	switch msg.Type() {
-	case ProposeCredentialMsgType:
+	case ProposeCredentialMsgType, ProposeCredentialMsgTypeV3:
 		if outbound {
 			return &proposalSent{}, nil
 		}
 
 		return &proposalReceived{}, nil
-	case OfferCredentialMsgType:
+	case OfferCredentialMsgType, OfferCredentialMsgTypeV3:
 		if outbound {
 			return &offerSent{}, nil
 		}
 
 		return &offerReceived{}, nil
-	case RequestCredentialMsgType:
+	case RequestCredentialMsgType, RequestCredentialMsgTypeV3:
 		if outbound {
 			return &requestSent{}, nil
 		}
 
 		return &requestReceived{}, nil
-	case IssueCredentialMsgType:
+	case IssueCredentialMsgType, IssueCredentialMsgTypeV3:
 		return &credentialReceived{}, nil
-	case ProblemReportMsgType:
+	case ProblemReportMsgType, ProblemReportMsgTypeV3:
 		return &abandoning{}, nil
-	case AckMsgType:
+	case AckMsgType, AckMsgTypeV3:
 		return &done{}, nil
 	default:
 		return nil, fmt.Errorf("unrecognized msgType: %s", msg.Type())
@@ -483,11 +569,16 @@ func (s *Service) saveTransitionalPayload(id string, data transitionalPayload) e
 
 // canTriggerActionEvents checks if the incoming message can trigger an action event.
 func canTriggerActionEvents(msg service.DIDCommMsg) bool {
-	return msg.Type() == ProposeCredentialMsgType ||
-		msg.Type() == OfferCredentialMsgType ||
-		msg.Type() == IssueCredentialMsgType ||
-		msg.Type() == RequestCredentialMsgType ||
-		msg.Type() == ProblemReportMsgType
+	switch msg.Type() {
+	case ProposeCredentialMsgType, OfferCredentialMsgType, IssueCredentialMsgType,
+		RequestCredentialMsgType, ProblemReportMsgType:
+		return true
+	case ProposeCredentialMsgTypeV3, OfferCredentialMsgTypeV3, IssueCredentialMsgTypeV3,
+		RequestCredentialMsgTypeV3, ProblemReportMsgTypeV3:
+		return true
+	}
+
+	return false
 }
 
 func (s *Service) getTransitionalPayload(id string) (*transitionalPayload, error) {
@@ -606,6 +697,11 @@ func (s *Service) Actions() ([]Action, error) {
 }
 
 func (s *Service) processCallback(msg *metaData) {
+	// persists the callback data, that allows resuming it if the agent is restarted before it was handled.
+	if err := s.saveCallback(msg); err != nil {
+		logger.Errorf("save callback: %s", err)
+	}
+
 	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
 	// channel internally.
 	s.callbacks <- msg
@@ -690,6 +786,9 @@ func (s *Service) Accept(msgType string) bool {
 	case ProposeCredentialMsgType, OfferCredentialMsgType, RequestCredentialMsgType,
 		IssueCredentialMsgType, AckMsgType, ProblemReportMsgType:
 		return true
+	case ProposeCredentialMsgTypeV3, OfferCredentialMsgTypeV3, RequestCredentialMsgTypeV3,
+		IssueCredentialMsgTypeV3, AckMsgTypeV3, ProblemReportMsgTypeV3:
+		return true
 	}
 
 	return false
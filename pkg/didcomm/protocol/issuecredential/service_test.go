@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package issuecredential
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
@@ -151,6 +154,25 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, svc)
 	})
 
+	t.Run("Error pending callbacks", func(t *testing.T) {
+		const errMsg = "error"
+
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Query(threads.CallbackKey).Return(nil, errors.New(errMsg))
+
+		storeProvider := storageMocks.NewMockProvider(ctrl)
+		storeProvider.EXPECT().OpenStore(Name).Return(store, nil)
+		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
+
+		provider := issuecredentialMocks.NewMockProvider(ctrl)
+		provider.EXPECT().Messenger().Return(nil)
+		provider.EXPECT().StorageProvider().Return(storeProvider).Times(2)
+
+		svc, err := New(provider)
+		require.EqualError(t, err, "pending callbacks: failed to query the store: "+errMsg)
+		require.Nil(t, svc)
+	})
+
 	t.Run("Error open store", func(t *testing.T) {
 		const errMsg = "error"
 
@@ -174,6 +196,7 @@ func TestService_HandleInbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	expectCallbacks(store)
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
@@ -204,7 +227,7 @@ func TestService_HandleInbound(t *testing.T) {
 		msg := service.NewDIDCommMsgMap(struct{}{})
 		require.NoError(t, msg.SetID(uuid.New().String()))
 		_, err = svc.HandleInbound(msg, service.EmptyDIDCommContext())
-		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: getCurrentStateNameAndPIID: currentStateName: "+errMsg)
+		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: getCurrentThreadAndPIID: currentThread: "+errMsg)
 	})
 
 	t.Run("DB error (saveTransitionalPayload)", func(t *testing.T) {
@@ -253,8 +276,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "done", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -311,8 +334,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "offer-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "offer-sent", threadState(t, name))
 
 			return nil
 		})
@@ -469,8 +492,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "done", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -525,8 +548,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "proposal-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "proposal-sent", threadState(t, name))
 
 			return nil
 		})
@@ -581,8 +604,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "request-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "request-sent", threadState(t, name))
 
 			return nil
 		})
@@ -639,8 +662,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "request-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "request-sent", threadState(t, name))
 
 			return nil
 		})
@@ -679,6 +702,111 @@ func TestService_HandleInbound(t *testing.T) {
 		}
 	})
 
+	t.Run("Receive Offer Credential (v3)", func(t *testing.T) {
+		done := make(chan struct{})
+		attachment := []decorator.AttachmentV2{{ID: "ID1", Format: "format"}, {ID: "ID2"}}
+
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_, msg service.DIDCommMsgMap, _, _ string) error {
+				defer close(done)
+
+				r := &RequestCredentialV3{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, RequestCredentialMsgTypeV3, r.Type)
+				require.Equal(t, attachment, r.Attachments)
+
+				return nil
+			})
+
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Delete(gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "request-sent", threadState(t, name))
+
+			return nil
+		})
+
+		svc, err := New(provider)
+		require.NoError(t, err)
+
+		ch := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(ch))
+
+		msg := service.NewDIDCommMsgMap(OfferCredentialV3{
+			ID:          uuid.New().String(),
+			Type:        OfferCredentialMsgTypeV3,
+			Body:        OfferCredentialV3Body{GoalCode: "goal", MultipleAvailable: "2"},
+			Attachments: attachment,
+		})
+
+		_, err = svc.HandleInbound(msg, service.NewDIDCommContext(Alice, Bob, nil))
+		require.NoError(t, err)
+
+		action := <-ch
+
+		properties, ok := action.Properties.(*eventProps)
+		require.True(t, ok)
+		require.Equal(t, msg.ID(), properties.PIID())
+
+		action.Continue(nil)
+
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
+	t.Run("Receive Request Credential Stop (v3)", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				defer close(done)
+
+				r := &model.ProblemReportV2{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, codeRejectedError, r.Body.Code)
+				require.Equal(t, ProblemReportMsgTypeV3, r.Type)
+
+				return nil
+			})
+
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Delete(gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
+
+			return nil
+		})
+
+		svc, err := New(provider)
+		require.NoError(t, err)
+
+		ch := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(ch))
+
+		msg := service.NewDIDCommMsgMap(RequestCredentialV3{
+			ID:   uuid.New().String(),
+			Type: RequestCredentialMsgTypeV3,
+		})
+
+		_, err = svc.HandleInbound(msg, service.NewDIDCommContext(Alice, Bob, nil))
+		require.NoError(t, err)
+
+		(<-ch).Stop(nil)
+
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
 	t.Run("Receive Invitation Credential Stop", func(t *testing.T) {
 		done := make(chan struct{})
 
@@ -698,8 +826,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "done", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -754,8 +882,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "credential-issued", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "credential-issued", threadState(t, name))
 
 			return nil
 		})
@@ -799,10 +927,10 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return([]byte("request-sent"), nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			require.Equal(t, "done", string(name))
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -846,10 +974,10 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return([]byte("request-sent"), nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			require.Equal(t, "done", string(name))
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -904,8 +1032,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return([]byte("request-sent"), nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "done", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -986,8 +1114,8 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return([]byte("request-sent"), nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "done", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -1029,10 +1157,10 @@ func TestService_HandleInbound(t *testing.T) {
 		done := make(chan struct{})
 
 		store.EXPECT().Get(gomock.Any()).Return([]byte("credential-issued"), nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			require.Equal(t, "done", string(name))
+			require.Equal(t, "done", threadState(t, name))
 
 			return nil
 		})
@@ -1083,6 +1211,7 @@ func TestService_HandleOutbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	expectCallbacks(store)
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
@@ -1105,7 +1234,7 @@ func TestService_HandleOutbound(t *testing.T) {
 
 		piid, err := svc.HandleOutbound(msg, "", "")
 		require.Empty(t, piid)
-		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: getCurrentStateNameAndPIID: currentStateName: "+errMsg)
+		require.Contains(t, fmt.Sprintf("%v", err), "doHandle: getCurrentThreadAndPIID: currentThread: "+errMsg)
 	})
 
 	t.Run("Unrecognized msgType", func(t *testing.T) {
@@ -1122,8 +1251,8 @@ func TestService_HandleOutbound(t *testing.T) {
 	t.Run("Send Propose Credential", func(t *testing.T) {
 		done := make(chan struct{})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "proposal-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "proposal-sent", threadState(t, name))
 
 			return nil
 		})
@@ -1155,7 +1284,7 @@ func TestService_HandleOutbound(t *testing.T) {
 	})
 
 	t.Run("Send Propose Credential with error", func(t *testing.T) {
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 		svc, err := New(provider)
 		require.NoError(t, err)
@@ -1174,8 +1303,8 @@ func TestService_HandleOutbound(t *testing.T) {
 	t.Run("Send Offer Credential", func(t *testing.T) {
 		done := make(chan struct{})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "offer-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "offer-sent", threadState(t, name))
 
 			return nil
 		})
@@ -1207,7 +1336,7 @@ func TestService_HandleOutbound(t *testing.T) {
 	})
 
 	t.Run("Send Offer with error", func(t *testing.T) {
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 		svc, err := New(provider)
 		require.NoError(t, err)
@@ -1226,8 +1355,8 @@ func TestService_HandleOutbound(t *testing.T) {
 	t.Run("Send Invitation Credential", func(t *testing.T) {
 		done := make(chan struct{})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
-			require.Equal(t, "request-sent", string(name))
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte, _ ...storage.Tag) error {
+			require.Equal(t, "request-sent", threadState(t, name))
 
 			return nil
 		})
@@ -1259,7 +1388,7 @@ func TestService_HandleOutbound(t *testing.T) {
 	})
 
 	t.Run("Send Invitation with error", func(t *testing.T) {
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 		svc, err := New(provider)
 		require.NoError(t, err)
@@ -1284,6 +1413,7 @@ func TestService_ActionContinue(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return(nil, errors.New(errMsg))
 
 		storeProvider := storageMocks.NewMockProvider(ctrl)
@@ -1310,6 +1440,7 @@ func TestService_ActionContinue(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{}`), nil)
 		store.EXPECT().Delete(gomock.Any()).Return(errors.New(errMsg))
 
@@ -1339,6 +1470,7 @@ func TestService_ActionStop(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return(nil, errors.New(errMsg))
 
 		storeProvider := storageMocks.NewMockProvider(ctrl)
@@ -1365,6 +1497,7 @@ func TestService_ActionStop(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{}`), nil)
 		store.EXPECT().Delete(gomock.Any()).Return(errors.New(errMsg))
 
@@ -1472,6 +1605,12 @@ func TestService_Accept(t *testing.T) {
 	require.True(t, (*Service).Accept(nil, IssueCredentialMsgType))
 	require.True(t, (*Service).Accept(nil, AckMsgType))
 	require.True(t, (*Service).Accept(nil, ProblemReportMsgType))
+	require.True(t, (*Service).Accept(nil, ProposeCredentialMsgTypeV3))
+	require.True(t, (*Service).Accept(nil, OfferCredentialMsgTypeV3))
+	require.True(t, (*Service).Accept(nil, RequestCredentialMsgTypeV3))
+	require.True(t, (*Service).Accept(nil, IssueCredentialMsgTypeV3))
+	require.True(t, (*Service).Accept(nil, AckMsgTypeV3))
+	require.True(t, (*Service).Accept(nil, ProblemReportMsgTypeV3))
 	require.False(t, (*Service).Accept(nil, "unknown"))
 }
 
@@ -1492,5 +1631,53 @@ func TestService_canTriggerActionEvents(t *testing.T) {
 		Type: RequestCredentialMsgType,
 	})))
 
+	require.True(t, canTriggerActionEvents(service.NewDIDCommMsgMap(IssueCredentialV3{
+		Type: IssueCredentialMsgTypeV3,
+	})))
+
+	require.False(t, canTriggerActionEvents(service.NewDIDCommMsgMap(model.AckV2{
+		Type: AckMsgTypeV3,
+	})))
+
 	require.False(t, canTriggerActionEvents(service.NewDIDCommMsgMap(struct{}{})))
 }
+
+// expectCallbacks allows the service to persist, delete and resume the action callbacks.
+func expectCallbacks(store *storageMocks.MockStore) {
+	records, err := mem.NewProvider().OpenStore(Name)
+	if err != nil {
+		panic(err)
+	}
+
+	iterator, err := records.Query(threads.CallbackKey)
+	if err != nil {
+		panic(err)
+	}
+
+	store.EXPECT().Query(threads.CallbackKey).Return(iterator, nil).AnyTimes()
+	store.EXPECT().Put(keyPrefix(threads.CallbackKey), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	store.EXPECT().Delete(keyPrefix(threads.CallbackKey)).Return(nil).AnyTimes()
+}
+
+// keyPrefix matches the store keys starting with the given prefix.
+type keyPrefix string
+
+func (p keyPrefix) Matches(x interface{}) bool {
+	key, ok := x.(string)
+
+	return ok && strings.HasPrefix(key, string(p))
+}
+
+func (p keyPrefix) String() string {
+	return "has prefix " + string(p)
+}
+
+// threadState returns the state name of the persisted thread.
+func threadState(t *testing.T, src []byte) string {
+	t.Helper()
+
+	thread := &Thread{}
+	require.NoError(t, json.Unmarshal(src, thread))
+
+	return thread.StateName
+}
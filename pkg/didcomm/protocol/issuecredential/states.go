@@ -9,6 +9,7 @@ package issuecredential
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -37,6 +38,7 @@ const (
 const (
 	codeRejectedError = "rejected"
 	codeInternalError = "internal"
+	codeTimeoutError  = "timeout"
 )
 
 // state action for network call.
@@ -120,7 +122,7 @@ func (s *abandoning) CanTransitionTo(st state) bool {
 func (s *abandoning) ExecuteInbound(md *metaData) (state, stateAction, error) {
 	// if code is not provided it means we do not need to notify the another agent.
 	// if we received ProblemReport message no need to answer.
-	if s.Code == "" || md.Msg.Type() == ProblemReportMsgType {
+	if s.Code == "" || md.Msg.Type() == ProblemReportMsgType || md.Msg.Type() == ProblemReportMsgTypeV3 {
 		return &done{}, zeroAction, nil
 	}
 
@@ -136,11 +138,21 @@ func (s *abandoning) ExecuteInbound(md *metaData) (state, stateAction, error) {
 		return nil, nil, fmt.Errorf("threadID: %w", err)
 	}
 
+	report := service.NewDIDCommMsgMap(&model.ProblemReport{
+		Type:        ProblemReportMsgType,
+		Description: code,
+	})
+
+	if isV3(md.Msg) {
+		report = service.NewDIDCommMsgMap(&model.ProblemReportV2{
+			Type: ProblemReportMsgTypeV3,
+			Body: model.ProblemReportV2Body{Code: code.Code},
+		})
+	}
+
 	return &done{}, func(messenger service.Messenger) error {
-		return messenger.ReplyToNested(service.NewDIDCommMsgMap(&model.ProblemReport{
-			Type:        ProblemReportMsgType,
-			Description: code,
-		}), &service.NestedReplyOpts{ThreadID: thID, MyDID: md.MyDID, TheirDID: md.TheirDID})
+		return messenger.ReplyToNested(report,
+			&service.NestedReplyOpts{ThreadID: thID, MyDID: md.MyDID, TheirDID: md.TheirDID})
 	}, nil
 }
 
@@ -208,7 +220,13 @@ func (s *offerSent) ExecuteInbound(md *metaData) (state, stateAction, error) {
 	action := func(messenger service.Messenger) error {
 		// sets message type.
 		md.offerCredential.Type = OfferCredentialMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.offerCredential), md.MyDID, md.TheirDID)
+
+		offer := service.NewDIDCommMsgMap(md.offerCredential)
+		if isV3(md.Msg) {
+			offer = service.NewDIDCommMsgMap(md.offerCredential.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, offer, md.MyDID, md.TheirDID)
 	}
 
 	return &noOp{}, action, nil
@@ -216,9 +234,7 @@ func (s *offerSent) ExecuteInbound(md *metaData) (state, stateAction, error) {
 
 func (s *offerSent) ExecuteOutbound(md *metaData) (state, stateAction, error) {
 	// creates the state's action.
-	action := func(messenger service.Messenger) error {
-		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
-	}
+	action := forwardInitial(md)
 
 	return &noOp{}, action, nil
 }
@@ -243,7 +259,13 @@ func (s *requestReceived) ExecuteInbound(md *metaData) (state, stateAction, erro
 	action := func(messenger service.Messenger) error {
 		// sets message type
 		md.issueCredential.Type = IssueCredentialMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.issueCredential), md.MyDID, md.TheirDID)
+
+		credential := service.NewDIDCommMsgMap(md.issueCredential)
+		if isV3(md.Msg) {
+			credential = service.NewDIDCommMsgMap(md.issueCredential.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, credential, md.MyDID, md.TheirDID)
 	}
 
 	return &credentialIssued{}, action, nil
@@ -292,7 +314,13 @@ func (s *proposalSent) ExecuteInbound(md *metaData) (state, stateAction, error)
 	action := func(messenger service.Messenger) error {
 		// sets message type
 		md.proposeCredential.Type = ProposeCredentialMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.proposeCredential), md.MyDID, md.TheirDID)
+
+		proposal := service.NewDIDCommMsgMap(md.proposeCredential)
+		if isV3(md.Msg) {
+			proposal = service.NewDIDCommMsgMap(md.proposeCredential.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, proposal, md.MyDID, md.TheirDID)
 	}
 
 	return &noOp{}, action, nil
@@ -300,9 +328,7 @@ func (s *proposalSent) ExecuteInbound(md *metaData) (state, stateAction, error)
 
 func (s *proposalSent) ExecuteOutbound(md *metaData) (state, stateAction, error) {
 	// creates the state's action
-	action := func(messenger service.Messenger) error {
-		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
-	}
+	action := forwardInitial(md)
 
 	return &noOp{}, action, nil
 }
@@ -326,17 +352,25 @@ func (s *offerReceived) ExecuteInbound(md *metaData) (state, stateAction, error)
 		return &proposalSent{}, zeroAction, nil
 	}
 
-	offer := OfferCredential{}
-	if err := md.Msg.Decode(&offer); err != nil {
+	offer, err := DecodeOfferCredential(md.Msg)
+	if err != nil {
 		return nil, nil, fmt.Errorf("decode: %w", err)
 	}
 
+	request := &RequestCredential{
+		Type:           RequestCredentialMsgType,
+		Formats:        offer.Formats,
+		RequestsAttach: offer.OffersAttach,
+	}
+
+	msg := service.NewDIDCommMsgMap(request)
+	if isV3(md.Msg) {
+		msg = service.NewDIDCommMsgMap(request.AsV3())
+	}
+
 	// creates the state's action
 	action := func(messenger service.Messenger) error {
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(RequestCredential{
-			Type:           RequestCredentialMsgType,
-			RequestsAttach: offer.OffersAttach,
-		}), md.MyDID, md.TheirDID)
+		return messenger.ReplyToMsg(md.Msg, msg, md.MyDID, md.TheirDID)
 	}
 
 	return &requestSent{}, action, nil
@@ -363,9 +397,7 @@ func (s *requestSent) ExecuteInbound(_ *metaData) (state, stateAction, error) {
 
 func (s *requestSent) ExecuteOutbound(md *metaData) (state, stateAction, error) {
 	// creates the state's action
-	action := func(messenger service.Messenger) error {
-		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
-	}
+	action := forwardInitial(md)
 
 	return &noOp{}, action, nil
 }
@@ -384,6 +416,12 @@ func (s *credentialReceived) CanTransitionTo(st state) bool {
 func (s *credentialReceived) ExecuteInbound(md *metaData) (state, stateAction, error) {
 	// creates the state's action
 	action := func(messenger service.Messenger) error {
+		if isV3(md.Msg) {
+			return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(model.AckV2{
+				Type: AckMsgTypeV3,
+			}), md.MyDID, md.TheirDID)
+		}
+
 		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(model.Ack{
 			Type: AckMsgType,
 		}), md.MyDID, md.TheirDID)
@@ -395,3 +433,20 @@ func (s *credentialReceived) ExecuteInbound(md *metaData) (state, stateAction, e
 func (s *credentialReceived) ExecuteOutbound(_ *metaData) (state, stateAction, error) {
 	return nil, nil, fmt.Errorf("%s: ExecuteOutbound is not implemented yet", s.Name())
 }
+
+// isV3 checks whether the message belongs to the issue-credential 3.0 (DIDComm V2) protocol.
+func isV3(msg service.DIDCommMsg) bool {
+	return strings.HasPrefix(msg.Type(), SpecV3)
+}
+
+// forwardInitial sends the message starting the protocol instance.
+func forwardInitial(md *metaData) stateAction {
+	return func(messenger service.Messenger) error {
+		// the connectionless offer is delivered out-of-band by the client, e.g attached to an invitation
+		if service.IsConnectionless(md.Msg, md.TheirDID) {
+			return nil
+		}
+
+		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 )
 
@@ -508,6 +509,39 @@ func TestOfferReceived_ExecuteInbound(t *testing.T) {
 		require.NoError(t, action(messenger))
 	})
 
+	t.Run("request carries offer formats and attachments", func(t *testing.T) {
+		offer := OfferCredential{
+			Type:         OfferCredentialMsgType,
+			Formats:      []Format{{AttachID: "attach-1", Format: "aries/ld-proof-vc-detail@v1.0"}},
+			OffersAttach: []decorator.Attachment{{ID: "attach-1", Data: decorator.AttachmentData{JSON: "detail"}}},
+		}
+
+		followup, action, err := (&offerReceived{}).ExecuteInbound(&metaData{
+			transitionalPayload: transitionalPayload{
+				Action: Action{Msg: service.NewDIDCommMsgMap(offer)},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &requestSent{}, followup)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_, msg service.DIDCommMsgMap, _, _ string) error {
+				request := RequestCredential{}
+				require.NoError(t, msg.Decode(&request))
+				require.Equal(t, offer.Formats, request.Formats)
+				require.Len(t, request.RequestsAttach, 1)
+				require.Equal(t, "attach-1", request.RequestsAttach[0].ID)
+
+				return nil
+			})
+
+		require.NoError(t, action(messenger))
+	})
+
 	t.Run("Decode error", func(t *testing.T) {
 		followup, action, err := (&offerReceived{}).ExecuteInbound(&metaData{
 			transitionalPayload: transitionalPayload{
@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Thread describes a protocol instance and the state it is currently in.
+type Thread = threads.Thread
+
+// ThreadFilter narrows down the protocol instances returned by Threads.
+// Zero values are ignored, e.g an empty filter matches every protocol instance.
+type ThreadFilter = threads.Filter
+
+// callbackPayload keeps the continuation of an action until it is handled,
+// that allows resuming it if the agent was restarted in the meantime.
+type callbackPayload struct {
+	transitionalPayload
+	ProposeCredential *ProposeCredential `json:",omitempty"`
+	OfferCredential   *OfferCredential   `json:",omitempty"`
+	RequestCredential *RequestCredential `json:",omitempty"`
+	IssueCredential   *IssueCredential   `json:",omitempty"`
+	CredentialNames   []string           `json:",omitempty"`
+	Err               string             `json:",omitempty"`
+	Stopped           bool               `json:",omitempty"`
+	TimedOut          bool               `json:",omitempty"`
+}
+
+// Threads returns the protocol instances matching the given filter.
+func (s *Service) Threads(filter ThreadFilter) ([]Thread, error) {
+	return s.threads.Threads(filter)
+}
+
+// SetStateTimeouts sets the time a protocol instance may stay in the given state (e.g "request-sent").
+// When the time is over, the protocol instance is abandoned and a problem report is sent to the other agent.
+func (s *Service) SetStateTimeouts(timeouts map[string]time.Duration) {
+	s.threads.SetStateTimeouts(timeouts)
+}
+
+// Close stops abandoning the protocol instances which timed out.
+func (s *Service) Close() error {
+	return s.threads.Close()
+}
+
+// abandonTimedOut abandons the protocol instance which stayed in a state for too long.
+func (s *Service) abandonTimedOut(thread Thread) {
+	md := &metaData{
+		transitionalPayload: transitionalPayload{
+			StateName: thread.StateName,
+			CreatedAt: thread.CreatedAt,
+			Action: Action{
+				PIID:     thread.PIID,
+				Msg:      thread.Msg,
+				MyDID:    thread.MyDID,
+				TheirDID: thread.TheirDID,
+			},
+		},
+		state:      stateFromName(thread.StateName),
+		msgClone:   thread.Msg.Clone(),
+		inbound:    true,
+		properties: map[string]interface{}{},
+		err:        fmt.Errorf("%w: %s", errStateTimeout, thread.StateName),
+	}
+
+	// the check and the save are atomic, a callback of the protocol instance being processed is not overridden
+	claimed, err := s.threads.ClaimCallback(md.PIID, newCallbackPayload(md))
+	if err != nil {
+		logger.Errorf("claim callback: %s", err)
+
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	err = s.deleteTransitionalPayload(md.PIID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		logger.Errorf("delete transitional payload: %s", err)
+	}
+
+	s.callbacks <- md
+}
+
+func newCallbackPayload(md *metaData) *callbackPayload {
+	payload := &callbackPayload{
+		transitionalPayload: md.transitionalPayload,
+		ProposeCredential:   md.proposeCredential,
+		OfferCredential:     md.offerCredential,
+		RequestCredential:   md.requestCredential,
+		IssueCredential:     md.issueCredential,
+		CredentialNames:     md.credentialNames,
+	}
+
+	if md.err != nil {
+		payload.Err = md.err.Error()
+		payload.Stopped = errors.As(md.err, &customError{})
+		payload.TimedOut = errors.Is(md.err, errStateTimeout)
+	}
+
+	return payload
+}
+
+func (s *Service) saveCallback(md *metaData) error {
+	return s.threads.SaveCallback(md.PIID, newCallbackPayload(md))
+}
+
+func (s *Service) deleteCallback(piID string) error {
+	return s.threads.DeleteCallback(piID)
+}
+
+// pendingCallbacks returns the callbacks which were not handled before the agent was stopped.
+func (s *Service) pendingCallbacks() ([]*metaData, error) {
+	var callbacks []*metaData
+
+	err := s.threads.PendingCallbacks(func(value []byte) error {
+		var payload callbackPayload
+		if err := json.Unmarshal(value, &payload); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+
+		callbacks = append(callbacks, payload.metaData())
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return callbacks, nil
+}
+
+func (p *callbackPayload) metaData() *metaData {
+	md := &metaData{
+		transitionalPayload: p.transitionalPayload,
+		state:               stateFromName(p.StateName),
+		msgClone:            p.Msg.Clone(),
+		inbound:             true,
+		properties:          map[string]interface{}{},
+		proposeCredential:   p.ProposeCredential,
+		offerCredential:     p.OfferCredential,
+		requestCredential:   p.RequestCredential,
+		issueCredential:     p.IssueCredential,
+		credentialNames:     p.CredentialNames,
+	}
+
+	switch {
+	case p.Stopped:
+		md.err = customError{error: errors.New(p.Err)}
+	case p.TimedOut:
+		md.err = fmt.Errorf("%w: %s", errStateTimeout, p.StateName)
+	case p.Err != "":
+		md.err = errors.New(p.Err)
+	}
+
+	return md
+}
+
+// abandonCode returns the problem report code for the error the protocol instance was abandoned with.
+func abandonCode(err error) string {
+	if errors.Is(err, errStateTimeout) {
+		return codeTimeoutError
+	}
+
+	return codeInternalError
+}
@@ -0,0 +1,224 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	issuecredentialMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestService_Threads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+	messenger.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider()).AnyTimes()
+
+	svc, err := New(provider)
+	require.NoError(t, err)
+
+	_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(ProposeCredential{
+		Type: ProposeCredentialMsgType,
+	}), Alice, Bob)
+	require.NoError(t, err)
+
+	_, err = svc.HandleOutbound(service.NewDIDCommMsgMap(OfferCredential{
+		Type: OfferCredentialMsgTypeV3,
+	}), Bob, Alice)
+	require.NoError(t, err)
+
+	list, err := svc.Threads(ThreadFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	list, err = svc.Threads(ThreadFilter{StateNames: []string{stateNameOfferSent}})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, Bob, list[0].MyDID)
+	require.Equal(t, Alice, list[0].TheirDID)
+	require.Equal(t, OfferCredentialMsgTypeV3, list[0].Msg.Type())
+	require.False(t, list[0].CreatedAt.IsZero())
+
+}
+
+func TestService_SetStateTimeouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	done := make(chan struct{})
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+	messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+	messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+		Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+			defer close(done)
+
+			r := &model.ProblemReport{}
+			require.NoError(t, msg.Decode(r))
+			require.Equal(t, codeTimeoutError, r.Description.Code)
+			require.Equal(t, ProblemReportMsgType, r.Type)
+			require.Equal(t, Alice, opts.MyDID)
+			require.Equal(t, Bob, opts.TheirDID)
+
+			return nil
+		})
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider()).AnyTimes()
+
+	svc, err := New(provider)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, svc.Close()) }()
+
+	chState := make(chan service.StateMsg, 10)
+	require.NoError(t, svc.RegisterMsgEvent(chState))
+
+	msg := service.NewDIDCommMsgMap(RequestCredential{
+		Type: RequestCredentialMsgType,
+	})
+	require.NoError(t, msg.SetID(uuid.New().String()))
+
+	piid, err := svc.HandleOutbound(msg, Alice, Bob)
+	require.NoError(t, err)
+
+	svc.SetStateTimeouts(map[string]time.Duration{stateNameRequestSent: time.Millisecond})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	for {
+		select {
+		case st := <-chState:
+			if st.StateID != stateNameDone || st.Type != service.PostState {
+				continue
+			}
+
+			props := st.Properties.All()
+			require.Equal(t, piid, props[piidPropKey])
+			require.ErrorIs(t, props[errorPropKey].(error), errStateTimeout)
+
+			return
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestService_ResumeCallbacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storeProvider := mem.NewProvider()
+
+	store, err := storeProvider.OpenStore(Name)
+	require.NoError(t, err)
+
+	msg := service.NewDIDCommMsgMap(ProposeCredential{
+		Type: ProposeCredentialMsgType,
+	})
+	require.NoError(t, msg.SetID(uuid.New().String()))
+
+	src, err := json.Marshal(&callbackPayload{
+		transitionalPayload: transitionalPayload{
+			StateName: stateNameProposalReceived,
+			Action: Action{
+				PIID:     msg.ID(),
+				Msg:      msg,
+				MyDID:    Alice,
+				TheirDID: Bob,
+			},
+		},
+		OfferCredential: &OfferCredential{Comment: "resumed"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(threads.CallbackKey+msg.ID(), src, storage.Tag{Name: threads.CallbackKey}))
+
+	done := make(chan struct{})
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+	messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), Alice, Bob).
+		Do(func(_, msg service.DIDCommMsgMap, _, _ string) error {
+			defer close(done)
+
+			offer := &OfferCredential{}
+			require.NoError(t, msg.Decode(offer))
+			require.Equal(t, OfferCredentialMsgType, offer.Type)
+			require.Equal(t, "resumed", offer.Comment)
+
+			return nil
+		})
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger)
+	provider.EXPECT().StorageProvider().Return(storeProvider).AnyTimes()
+
+	svc, err := New(provider)
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	list, err := svc.Threads(ThreadFilter{StateNames: []string{stateNameOfferSent}})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, msg.ID(), list[0].PIID)
+}
+
+func TestCallbackPayload_metaData(t *testing.T) {
+	md := &metaData{
+		transitionalPayload: transitionalPayload{StateName: stateNameRequestSent},
+		err:                 customError{error: errProtocolStopped},
+	}
+
+	src, err := json.Marshal(&callbackPayload{
+		transitionalPayload: md.transitionalPayload,
+		Err:                 md.err.Error(),
+		Stopped:             true,
+	})
+	require.NoError(t, err)
+
+	payload := &callbackPayload{}
+	require.NoError(t, json.Unmarshal(src, payload))
+
+	restored := payload.metaData()
+	require.ErrorAs(t, restored.err, &customError{})
+	require.EqualError(t, restored.err, errProtocolStopped.Error())
+	require.Equal(t, stateNameRequestSent, restored.state.Name())
+
+	payload = &callbackPayload{TimedOut: true, Err: "timed out"}
+	require.ErrorIs(t, payload.metaData().err, errStateTimeout)
+	require.Equal(t, codeTimeoutError, abandonCode(payload.metaData().err))
+
+	payload = &callbackPayload{Err: "internal"}
+	require.EqualError(t, payload.metaData().err, "internal")
+	require.Equal(t, codeInternalError, abandonCode(payload.metaData().err))
+}
@@ -0,0 +1,389 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	// Ed25519Signature2018 ed25519 signature suite.
+	Ed25519Signature2018 = "Ed25519Signature2018"
+	// JSONWebSignature2020 json web signature suite.
+	JSONWebSignature2020 = "JsonWebSignature2020"
+
+	piidKey = "piid"
+)
+
+// nolint:gochecknoglobals
+var logger = log.New("aries-framework/issuecredential/middleware")
+
+// ErrManualApproval is returned by the ApprovalPolicy when the credential request can not be approved
+// automatically and has to be handled by the issuer manually.
+var ErrManualApproval = errors.New("manual approval required")
+
+// CredentialRequest contains the credential request to be approved by the ApprovalPolicy.
+type CredentialRequest struct {
+	PIID     string
+	MyDID    string
+	TheirDID string
+	Detail   *LDProofVCDetail
+}
+
+// ApprovalPolicy decides whether the credential requested by the holder can be issued automatically.
+// Returning nil approves the request, ErrManualApproval leaves the request for the issuer to handle manually,
+// any other error rejects the request and is sent to the holder as a problem report.
+type ApprovalPolicy func(request *CredentialRequest) error
+
+// ApproveAll is the ApprovalPolicy which approves all credential requests.
+func ApproveAll(*CredentialRequest) error {
+	return nil
+}
+
+// IssuerProvider contains dependencies for the IssueLDProofCredentials middleware function.
+type IssuerProvider interface {
+	VDRegistry() vdrapi.Registry
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+}
+
+// IssuerOpt represents option function for the IssueLDProofCredentials middleware.
+type IssuerOpt func(o *issuerOptions)
+
+type issuerOptions struct {
+	issuerDID          string
+	verificationMethod string
+	keyID              string
+	proofType          string
+}
+
+// WithIssuerDID sets the DID of the issuer, credentials are issued and signed by this DID.
+// If not provided, the DID of the issuer in the connection with the holder (myDID) is used.
+// The issuer of the requested credential is never trusted, requests for credentials of any other issuer are rejected.
+func WithIssuerDID(issuerDID string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.issuerDID = issuerDID
+	}
+}
+
+// WithVerificationMethod sets the verification method of the issuer DID used for signing credentials.
+// If not provided, the first assertion method of the resolved issuer DID document is used.
+func WithVerificationMethod(verificationMethod string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.verificationMethod = verificationMethod
+	}
+}
+
+// WithKeyID sets the KMS key ID of the signing key.
+// If not provided, the fragment of the verification method is used as the key ID.
+func WithKeyID(keyID string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.keyID = keyID
+	}
+}
+
+// WithProofType sets the signature suite used when the credential request does not specify the proof type,
+// supported types are Ed25519Signature2018 (default) and JsonWebSignature2020.
+func WithProofType(proofType string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.proofType = proofType
+	}
+}
+
+// IssueLDProofCredentials the helper function for the issue credential protocol which signs the credential
+// requested in 'aries/ld-proof-vc-detail@v1.0' format and attaches it to the issue credential message
+// in 'aries/ld-proof-vc@v1.0' format.
+//
+// The middleware is applied when the issuer accepts the request with an issue credential message
+// which has no credentials attached, for example issuecredential.WithIssueCredential(&IssueCredential{}).
+// Use AutoIssueCredentials to accept such requests automatically.
+func IssueLDProofCredentials(p IssuerProvider, opts ...IssuerOpt) issuecredential.Middleware {
+	options := &issuerOptions{proofType: Ed25519Signature2018}
+
+	for i := range opts {
+		opts[i](options)
+	}
+
+	issuer := &ldProofIssuer{vdr: p.VDRegistry(), km: p.KMS(), cr: p.Crypto(), opts: options}
+
+	return func(next issuecredential.Handler) issuecredential.Handler {
+		return issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+			if metadata.StateName() != stateNameRequestReceived {
+				return next.Handle(metadata)
+			}
+
+			msg := metadata.IssueCredential()
+			if msg == nil || len(msg.CredentialsAttach) > 0 {
+				return next.Handle(metadata)
+			}
+
+			request, err := issuecredential.DecodeRequestCredential(metadata.Message())
+			if err != nil {
+				return fmt.Errorf("decode: %w", err)
+			}
+
+			detail, err := getLDProofVCDetail(request.Formats, request.RequestsAttach)
+			if errors.Is(err, errAttachmentNotFound) {
+				return next.Handle(metadata)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			issuerDID := options.issuerDID
+			if issuerDID == "" {
+				// nolint: errcheck
+				issuerDID, _ = metadata.Properties()[myDIDKey].(string)
+			}
+
+			vc, err := issuer.issue(detail, issuerDID)
+			if err != nil {
+				return fmt.Errorf("issue credential: %w", err)
+			}
+
+			attachID := uuid.New().String()
+
+			msg.Formats = append(msg.Formats, issuecredential.Format{AttachID: attachID, Format: LDProofVCFormat})
+			msg.CredentialsAttach = append(msg.CredentialsAttach, decorator.Attachment{
+				ID:       attachID,
+				MimeType: mimeTypeApplicationLdJSON,
+				Data:     decorator.AttachmentData{JSON: vc},
+			})
+
+			return next.Handle(metadata)
+		})
+	}
+}
+
+// AutoIssueCredentials accepts the credential requests received on the given action event channel
+// which are in 'aries/ld-proof-vc-detail@v1.0' format and approved by the policy, the credentials are then
+// signed and sent to the holder by the IssueLDProofCredentials middleware.
+//
+// All other action events, including the requests requiring manual approval, are passed to the next channel.
+// If the next channel is nil, those actions remain pending and can be handled through the issue credential
+// client actions API.
+func AutoIssueCredentials(ch <-chan service.DIDCommAction, policy ApprovalPolicy, next chan<- service.DIDCommAction) {
+	for action := range ch {
+		err := approve(action, policy)
+		if err == nil {
+			action.Continue(issuecredential.WithIssueCredential(&issuecredential.IssueCredential{}))
+
+			continue
+		}
+
+		if !errors.Is(err, ErrManualApproval) && !errors.Is(err, errAttachmentNotFound) {
+			action.Stop(err)
+
+			continue
+		}
+
+		if next == nil {
+			logger.Debugf("no handler registered for issue credential action: %s", action.Message.Type())
+
+			continue
+		}
+
+		next <- action
+	}
+}
+
+func approve(action service.DIDCommAction, policy ApprovalPolicy) error {
+	if action.Message.Type() != issuecredential.RequestCredentialMsgType {
+		return errAttachmentNotFound
+	}
+
+	request := issuecredential.RequestCredential{}
+
+	err := action.Message.Decode(&request)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	detail, err := getLDProofVCDetail(request.Formats, request.RequestsAttach)
+	if err != nil {
+		return err
+	}
+
+	credentialRequest := &CredentialRequest{Detail: detail}
+
+	if action.Properties != nil {
+		properties := action.Properties.All()
+
+		// nolint: errcheck
+		credentialRequest.PIID, _ = properties[piidKey].(string)
+		// nolint: errcheck
+		credentialRequest.MyDID, _ = properties[myDIDKey].(string)
+		// nolint: errcheck
+		credentialRequest.TheirDID, _ = properties[theirDIDKey].(string)
+	}
+
+	return policy(credentialRequest)
+}
+
+type ldProofIssuer struct {
+	vdr  vdrapi.Registry
+	km   kms.KeyManager
+	cr   crypto.Crypto
+	opts *issuerOptions
+}
+
+func (i *ldProofIssuer) issue(detail *LDProofVCDetail, issuerDID string) (*verifiable.Credential, error) {
+	if issuerDID == "" {
+		return nil, errors.New("issuer DID is not provided")
+	}
+
+	vc, err := verifiable.ParseCredential(detail.Credential, verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+	if err != nil {
+		return nil, fmt.Errorf("parse credential: %w", err)
+	}
+
+	if len(vc.Proofs) > 0 {
+		return nil, errors.New("requested credential should not contain proof")
+	}
+
+	if vc.Issuer.ID != "" && vc.Issuer.ID != issuerDID {
+		return nil, fmt.Errorf("requested issuer '%s' does not match the issuer DID", vc.Issuer.ID)
+	}
+
+	vc.Issuer.ID = issuerDID
+
+	if vc.Issued == nil {
+		vc.Issued = util.NewTime(time.Now().UTC())
+	}
+
+	ldpContext, err := i.proofContext(issuerDID, detail.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vc.AddLinkedDataProof(ldpContext, jsonld.WithDocumentLoader(cm.CachingJSONLDLoader()))
+	if err != nil {
+		return nil, fmt.Errorf("add linked data proof: %w", err)
+	}
+
+	return vc, nil
+}
+
+func (i *ldProofIssuer) proofContext(issuerDID string,
+	options *LDProofVCDetailOptions) (*verifiable.LinkedDataProofContext, error) {
+	if options == nil {
+		options = &LDProofVCDetailOptions{}
+	}
+
+	proofType := options.ProofType
+	if proofType == "" {
+		proofType = i.opts.proofType
+	}
+
+	verificationMethod, err := i.verificationMethod(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := i.opts.keyID
+	if keyID == "" {
+		keyID = verificationMethod[strings.LastIndex(verificationMethod, "#")+1:]
+	}
+
+	kh, err := i.km.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get signing key: %w", err)
+	}
+
+	var signatureSuite signer.SignatureSuite
+
+	switch proofType {
+	case Ed25519Signature2018:
+		signatureSuite = ed25519signature2018.New(suite.WithSigner(&kmsSigner{kh: kh, cr: i.cr}))
+	case JSONWebSignature2020:
+		signatureSuite = jsonwebsignature2020.New(suite.WithSigner(&kmsSigner{kh: kh, cr: i.cr}))
+	default:
+		return nil, fmt.Errorf("unsupported proof type '%s'", proofType)
+	}
+
+	purpose := options.ProofPurpose
+	if purpose == "" {
+		purpose = defaultProofPurpose
+	}
+
+	ldpContext := &verifiable.LinkedDataProofContext{
+		SignatureType:           proofType,
+		Suite:                   signatureSuite,
+		SignatureRepresentation: verifiable.SignatureProofValue,
+		VerificationMethod:      verificationMethod,
+		Challenge:               options.Challenge,
+		Domain:                  options.Domain,
+		Purpose:                 purpose,
+	}
+
+	if options.Created != "" {
+		created, err := time.Parse(time.RFC3339, options.Created)
+		if err != nil {
+			return nil, fmt.Errorf("parse proof created time: %w", err)
+		}
+
+		ldpContext.Created = &created
+	}
+
+	return ldpContext, nil
+}
+
+func (i *ldProofIssuer) verificationMethod(issuerDID string) (string, error) {
+	if i.opts.verificationMethod != "" {
+		return i.opts.verificationMethod, nil
+	}
+
+	docResolution, err := i.vdr.Resolve(issuerDID)
+	if err != nil {
+		return "", fmt.Errorf("resolve issuer DID: %w", err)
+	}
+
+	methods := docResolution.DIDDocument.VerificationMethods(did.AssertionMethod)[did.AssertionMethod]
+	if len(methods) == 0 {
+		return "", fmt.Errorf("issuer DID '%s' has no assertion method", issuerDID)
+	}
+
+	vmID := methods[0].VerificationMethod.ID
+	if strings.HasPrefix(vmID, "#") {
+		vmID = issuerDID + vmID
+	}
+
+	return vmID, nil
+}
+
+type kmsSigner struct {
+	kh interface{}
+	cr crypto.Crypto
+}
+
+func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
+	return s.cr.Sign(data, s.kh)
+}
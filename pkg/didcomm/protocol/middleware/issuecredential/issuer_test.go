@@ -0,0 +1,455 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+const (
+	issuerDID        = "did:example:issuer"
+	sampleCredential = `{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"id": "http://example.edu/credentials/1872",
+		"type": ["VerifiableCredential"],
+		"issuer": "did:example:issuer",
+		"issuanceDate": "2010-01-01T19:23:24Z",
+		"credentialSubject": {"id": "did:example:holder"}
+	}`
+)
+
+func TestIssueLDProofCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider, keyID := newIssuerProvider(t)
+
+	next := issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+		return nil
+	})
+
+	t.Run("Ignores processing", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("state-name")
+		require.NoError(t, IssueLDProofCredentials(provider)(next).Handle(metadata))
+	})
+
+	t.Run("Credentials provided by the issuer", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{
+			CredentialsAttach: []decorator.Attachment{{Data: decorator.AttachmentData{JSON: sampleCredential}}},
+		})
+		require.NoError(t, IssueLDProofCredentials(provider)(next).Handle(metadata))
+	})
+
+	t.Run("No ld-proof-vc-detail attachment", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+			Type: issuecredential.RequestCredentialMsgType,
+		}))
+		require.NoError(t, IssueLDProofCredentials(provider)(next).Handle(metadata))
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(struct {
+			Formats int `json:"formats"`
+		}{Formats: 1}))
+
+		err := IssueLDProofCredentials(provider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		msg := &issuecredential.IssueCredential{}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(msg)
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+			Options: &LDProofVCDetailOptions{
+				ProofType: Ed25519Signature2018,
+				Created:   "2021-01-01T00:00:00Z",
+				Challenge: "challenge",
+				Domain:    "domain",
+			},
+		}))
+
+		require.NoError(t, IssueLDProofCredentials(provider, WithIssuerDID(issuerDID))(next).Handle(metadata))
+
+		require.Len(t, msg.Formats, 1)
+		require.Equal(t, LDProofVCFormat, msg.Formats[0].Format)
+		require.Len(t, msg.CredentialsAttach, 1)
+		require.Equal(t, msg.Formats[0].AttachID, msg.CredentialsAttach[0].ID)
+
+		vc := parseIssuedCredential(t, provider, msg.CredentialsAttach[0])
+		require.Equal(t, issuerDID, vc.Issuer.ID)
+		require.Len(t, vc.Proofs, 1)
+		require.Equal(t, Ed25519Signature2018, vc.Proofs[0]["type"])
+		require.Equal(t, issuerDID+"#"+keyID, vc.Proofs[0]["verificationMethod"])
+		require.Equal(t, "challenge", vc.Proofs[0]["challenge"])
+		require.Equal(t, "domain", vc.Proofs[0]["domain"])
+		require.Equal(t, defaultProofPurpose, vc.Proofs[0]["proofPurpose"])
+	})
+
+	t.Run("Success (explicit verification method and key)", func(t *testing.T) {
+		msg := &issuecredential.IssueCredential{}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(msg)
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: issuerDID})
+
+		require.NoError(t, IssueLDProofCredentials(provider,
+			WithProofType(Ed25519Signature2018),
+			WithVerificationMethod(issuerDID+"#key-1"),
+			WithKeyID(keyID),
+		)(next).Handle(metadata))
+
+		require.Len(t, msg.CredentialsAttach, 1)
+
+		vc := parseIssuedCredential(t, provider, msg.CredentialsAttach[0])
+		require.Len(t, vc.Proofs, 1)
+		require.Equal(t, Ed25519Signature2018, vc.Proofs[0]["type"])
+		require.Equal(t, issuerDID+"#key-1", vc.Proofs[0]["verificationMethod"])
+	})
+
+	t.Run("Issuer mismatch", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+
+		err := IssueLDProofCredentials(provider, WithIssuerDID("did:example:other"))(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the issuer DID")
+	})
+
+	t.Run("Issuer DID of the connection", func(t *testing.T) {
+		msg := &issuecredential.IssueCredential{}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(msg)
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: issuerDID})
+
+		require.NoError(t, IssueLDProofCredentials(provider)(next).Handle(metadata))
+		require.Len(t, msg.CredentialsAttach, 1)
+
+		vc := parseIssuedCredential(t, provider, msg.CredentialsAttach[0])
+		require.Equal(t, issuerDID, vc.Issuer.ID)
+		require.Equal(t, issuerDID+"#"+keyID, vc.Proofs[0]["verificationMethod"])
+	})
+
+	t.Run("Requested issuer does not match the connection", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: "did:example:other"})
+
+		err := IssueLDProofCredentials(provider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requested issuer 'did:example:issuer' does not match the issuer DID")
+	})
+
+	t.Run("No issuer DID", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{})
+
+		err := IssueLDProofCredentials(provider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issuer DID is not provided")
+	})
+
+	t.Run("Unsupported proof type", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+			Options:    &LDProofVCDetailOptions{ProofType: "BbsBlsSignature2020"},
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: issuerDID})
+
+		err := IssueLDProofCredentials(provider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported proof type 'BbsBlsSignature2020'")
+	})
+
+	t.Run("Invalid detail", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{}))
+
+		err := IssueLDProofCredentials(provider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential is missing")
+	})
+
+	t.Run("Resolve error", func(t *testing.T) {
+		failingProvider := &mockprovider.Provider{
+			KMSValue:    provider.KMSValue,
+			CryptoValue: provider.CryptoValue,
+			VDRegistryValue: &mockvdr.MockVDRegistry{
+				ResolveErr: errors.New("resolve error"),
+			},
+		}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().IssueCredential().Return(&issuecredential.IssueCredential{})
+		metadata.EXPECT().Message().Return(requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}))
+		metadata.EXPECT().Properties().Return(map[string]interface{}{myDIDKey: issuerDID})
+
+		err := IssueLDProofCredentials(failingProvider)(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve issuer DID")
+	})
+}
+
+func TestAutoIssueCredentials(t *testing.T) {
+	request := requestCredentialMsg(t, &LDProofVCDetail{Credential: json.RawMessage(sampleCredential)})
+
+	newAction := func(msg service.DIDCommMsg, continued chan interface{}, stopped chan error) service.DIDCommAction {
+		return service.DIDCommAction{
+			ProtocolName: issuecredential.Name,
+			Message:      msg,
+			Continue:     func(args interface{}) { continued <- args },
+			Stop:         func(err error) { stopped <- err },
+			Properties: &mockProperties{properties: map[string]interface{}{
+				piidKey: "piid", myDIDKey: "myDID", theirDIDKey: "theirDID",
+			}},
+		}
+	}
+
+	t.Run("Approved", func(t *testing.T) {
+		ch := make(chan service.DIDCommAction)
+		continued := make(chan interface{}, 1)
+
+		var received *CredentialRequest
+
+		go AutoIssueCredentials(ch, func(req *CredentialRequest) error {
+			received = req
+
+			return nil
+		}, nil)
+
+		ch <- newAction(request, continued, nil)
+		close(ch)
+
+		select {
+		case args := <-continued:
+			_, ok := args.(issuecredential.Opt)
+			require.True(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		require.Equal(t, "piid", received.PIID)
+		require.Equal(t, "myDID", received.MyDID)
+		require.Equal(t, "theirDID", received.TheirDID)
+		require.JSONEq(t, sampleCredential, string(received.Detail.Credential))
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		ch := make(chan service.DIDCommAction)
+		stopped := make(chan error, 1)
+
+		go AutoIssueCredentials(ch, func(*CredentialRequest) error {
+			return errors.New("untrusted holder")
+		}, nil)
+
+		ch <- newAction(request, nil, stopped)
+		close(ch)
+
+		select {
+		case err := <-stopped:
+			require.EqualError(t, err, "untrusted holder")
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	})
+
+	t.Run("Manual approval and other messages forwarded", func(t *testing.T) {
+		ch := make(chan service.DIDCommAction)
+		next := make(chan service.DIDCommAction, 2)
+
+		go AutoIssueCredentials(ch, func(*CredentialRequest) error {
+			return ErrManualApproval
+		}, next)
+
+		ch <- newAction(request, nil, nil)
+		ch <- newAction(service.NewDIDCommMsgMap(issuecredential.ProposeCredential{
+			Type: issuecredential.ProposeCredentialMsgType,
+		}), nil, nil)
+		close(ch)
+
+		for _, msgType := range []string{
+			issuecredential.RequestCredentialMsgType, issuecredential.ProposeCredentialMsgType,
+		} {
+			select {
+			case action := <-next:
+				require.Equal(t, msgType, action.Message.Type())
+			case <-time.After(time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	})
+
+	t.Run("Manual approval without next handler", func(t *testing.T) {
+		ch := make(chan service.DIDCommAction)
+		done := make(chan struct{})
+
+		go func() {
+			AutoIssueCredentials(ch, ApproveAll, nil)
+			close(done)
+		}()
+
+		ch <- newAction(service.NewDIDCommMsgMap(issuecredential.OfferCredential{
+			Type: issuecredential.OfferCredentialMsgType,
+		}), nil, nil)
+		close(ch)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	})
+}
+
+func newIssuerProvider(t *testing.T) (*mockprovider.Provider, string) {
+	t.Helper()
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	km, err := localkms.New("local-lock://custom/master/key/",
+		mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
+	require.NoError(t, err)
+
+	keyID, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.NoError(t, err)
+
+	vm := did.NewVerificationMethodFromBytes("#"+keyID, "Ed25519VerificationKey2018", issuerDID, pubKey)
+	keyVM := did.NewVerificationMethodFromBytes(issuerDID+"#key-1", "Ed25519VerificationKey2018", issuerDID, pubKey)
+
+	doc := did.BuildDoc(
+		did.WithVerificationMethod([]did.VerificationMethod{*vm, *keyVM}),
+		did.WithAssertion([]did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)}),
+	)
+	doc.ID = issuerDID
+
+	return &mockprovider.Provider{
+		KMSValue:    km,
+		CryptoValue: cr,
+		VDRegistryValue: &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				if didID != issuerDID {
+					return nil, vdrapi.ErrNotFound
+				}
+
+				return &did.DocResolution{DIDDocument: doc}, nil
+			},
+		},
+	}, keyID
+}
+
+func requestCredentialMsg(t *testing.T, detail *LDProofVCDetail) service.DIDCommMsgMap {
+	t.Helper()
+
+	raw, err := json.Marshal(detail)
+	require.NoError(t, err)
+
+	var data map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(raw, &data))
+
+	return service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+		Type: issuecredential.RequestCredentialMsgType,
+		Formats: []issuecredential.Format{{
+			AttachID: "detail",
+			Format:   LDProofVCDetailFormat,
+		}},
+		RequestsAttach: []decorator.Attachment{{
+			ID:   "detail",
+			Data: decorator.AttachmentData{JSON: data},
+		}},
+	})
+}
+
+func parseIssuedCredential(t *testing.T, provider *mockprovider.Provider,
+	attachment decorator.Attachment) *verifiable.Credential {
+	t.Helper()
+
+	raw, err := attachment.Data.Fetch()
+	require.NoError(t, err)
+
+	vc, err := verifiable.ParseCredential(raw,
+		verifiable.WithPublicKeyFetcher(verifiable.NewVDRKeyResolver(provider.VDRegistryValue).PublicKeyFetcher()),
+		verifiable.WithJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+	require.NoError(t, err)
+
+	return vc
+}
+
+type mockProperties struct {
+	properties map[string]interface{}
+}
+
+func (m *mockProperties) All() map[string]interface{} {
+	return m.properties
+}
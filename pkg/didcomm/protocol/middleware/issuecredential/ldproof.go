@@ -0,0 +1,351 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// LDProofVCDetailFormat is the attachment format of the credential offer or request containing the credential
+	// to be issued along with the proof options (Aries RFC 0593).
+	LDProofVCDetailFormat = "aries/ld-proof-vc-detail@v1.0"
+	// LDProofVCFormat is the attachment format of the issued credential (Aries RFC 0593).
+	LDProofVCFormat = "aries/ld-proof-vc@v1.0"
+
+	stateNameRequestSent      = "request-sent"
+	stateNameAbandoning       = "abandoning"
+	stateNameDone             = "done"
+	defaultProofPurpose       = "assertionMethod"
+	mimeTypeApplicationLdJSON = "application/ld+json"
+	ldProofVCDetailStoreName  = "issuecredential_ldproofvcdetail"
+)
+
+// ErrLDProofVCMismatch is returned when the issued credential does not match the requested credential detail.
+var ErrLDProofVCMismatch = errors.New("issued credential does not match the requested credential detail")
+
+// LDProofVCDetail is the content of the 'aries/ld-proof-vc-detail@v1.0' attachment.
+type LDProofVCDetail struct {
+	// Credential to be issued, without proof.
+	Credential json.RawMessage `json:"credential"`
+	// Options for the proof to be added to the credential.
+	Options *LDProofVCDetailOptions `json:"options,omitempty"`
+}
+
+// LDProofVCDetailOptions contains the proof options of the 'aries/ld-proof-vc-detail@v1.0' attachment.
+type LDProofVCDetailOptions struct {
+	ProofPurpose string `json:"proofPurpose,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Domain       string `json:"domain,omitempty"`
+	Challenge    string `json:"challenge,omitempty"`
+	ProofType    string `json:"proofType,omitempty"`
+}
+
+func getLDProofVCDetail(formats []issuecredential.Format,
+	attachments []decorator.Attachment) (*LDProofVCDetail, error) {
+	src, err := getAttachmentByFormat(formats, attachments, LDProofVCDetailFormat)
+	if err != nil {
+		if errors.Is(err, errAttachmentNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("get attachment by format: %w", err)
+	}
+
+	detail := &LDProofVCDetail{}
+
+	if err = json.Unmarshal(src, detail); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", LDProofVCDetailFormat, err)
+	}
+
+	if len(detail.Credential) == 0 || string(detail.Credential) == "null" {
+		return nil, fmt.Errorf("credential is missing in %s attachment", LDProofVCDetailFormat)
+	}
+
+	return detail, nil
+}
+
+// LDProofProvider contains dependencies for the ValidateLDProofCredentials middleware function.
+type LDProofProvider interface {
+	VDRegistry() vdrapi.Registry
+	StorageProvider() storage.Provider
+}
+
+// ValidateLDProofCredentials the helper function for the issue credential protocol which validates
+// the credentials received in 'aries/ld-proof-vc@v1.0' format against the 'aries/ld-proof-vc-detail@v1.0'
+// credential detail requested by the holder, see VerifyLDProofCredential.
+//
+// The requested credential detail is kept until the credential is received or the protocol instance ends,
+// so the middleware should be used by the holder along with SaveCredentials and placed before it.
+func ValidateLDProofCredentials(p LDProofProvider) issuecredential.Middleware {
+	vdr := p.VDRegistry()
+	store, storeErr := p.StorageProvider().OpenStore(ldProofVCDetailStoreName)
+
+	return func(next issuecredential.Handler) issuecredential.Handler {
+		return issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+			switch metadata.StateName() {
+			case stateNameRequestSent, stateNameCredentialReceived, stateNameAbandoning, stateNameDone:
+			default:
+				return next.Handle(metadata)
+			}
+
+			if storeErr != nil {
+				return fmt.Errorf("open store: %w", storeErr)
+			}
+
+			// nolint: errcheck
+			piid, _ := metadata.Properties()[piidKey].(string)
+
+			var err error
+
+			switch metadata.StateName() {
+			case stateNameRequestSent:
+				err = saveRequestedDetail(store, piid, metadata.Message())
+			case stateNameCredentialReceived:
+				err = validateIssuedCredentials(vdr, store, piid, metadata.Message())
+			default:
+				// the protocol instance ended, the requested detail is no longer needed.
+				deleteRequestedDetail(store, piid)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return next.Handle(metadata)
+		})
+	}
+}
+
+// saveRequestedDetail saves credential detail of the request being sent, the request is either sent by the holder
+// or created from the offer being accepted.
+func saveRequestedDetail(store storage.Store, piid string, msg service.DIDCommMsg) error {
+	var (
+		formats     []issuecredential.Format
+		attachments []decorator.Attachment
+	)
+
+	switch msg.Type() {
+	case issuecredential.OfferCredentialMsgType, issuecredential.OfferCredentialMsgTypeV3:
+		offer, err := issuecredential.DecodeOfferCredential(msg)
+		if err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+
+		formats, attachments = offer.Formats, offer.OffersAttach
+	case issuecredential.RequestCredentialMsgType, issuecredential.RequestCredentialMsgTypeV3:
+		request, err := issuecredential.DecodeRequestCredential(msg)
+		if err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+
+		formats, attachments = request.Formats, request.RequestsAttach
+	default:
+		return nil
+	}
+
+	detail, err := getLDProofVCDetail(formats, attachments)
+	if errors.Is(err, errAttachmentNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	src, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("marshal credential detail: %w", err)
+	}
+
+	if err = store.Put(piid, src); err != nil {
+		return fmt.Errorf("save credential detail: %w", err)
+	}
+
+	return nil
+}
+
+func validateIssuedCredentials(vdr vdrapi.Registry, store storage.Store, piid string, msg service.DIDCommMsg) error {
+	src, err := store.Get(piid)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get credential detail: %w", err)
+	}
+
+	detail := &LDProofVCDetail{}
+
+	if err = json.Unmarshal(src, detail); err != nil {
+		return fmt.Errorf("unmarshal credential detail: %w", err)
+	}
+
+	credential, err := issuecredential.DecodeIssueCredential(msg)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	for i := range credential.CredentialsAttach {
+		if getFormat(credential.Formats, credential.CredentialsAttach[i].ID) != LDProofVCFormat {
+			continue
+		}
+
+		rawVC, err := credential.CredentialsAttach[i].Data.Fetch()
+		if err != nil {
+			return fmt.Errorf("fetch: %w", err)
+		}
+
+		vc, err := verifiable.ParseCredential(rawVC,
+			verifiable.WithPublicKeyFetcher(verifiable.NewVDRKeyResolver(vdr).PublicKeyFetcher()),
+			verifiable.WithJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+		if err != nil {
+			return fmt.Errorf("parse credential: %w", err)
+		}
+
+		if err = VerifyLDProofCredential(detail, vc); err != nil {
+			return err
+		}
+	}
+
+	if err = store.Delete(piid); err != nil {
+		return fmt.Errorf("delete credential detail: %w", err)
+	}
+
+	return nil
+}
+
+// deleteRequestedDetail deletes credential detail requested in the ended protocol instance, failures are only logged
+// since the protocol instance has to end anyway.
+func deleteRequestedDetail(store storage.Store, piid string) {
+	err := store.Delete(piid)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		logger.Warnf("delete credential detail of protocol instance %s: %s", piid, err)
+	}
+}
+
+// VerifyLDProofCredential checks that the issued credential matches the requested credential detail:
+// the credential has all the requested contexts and types, and a proof of the requested proof type, purpose,
+// challenge and domain. Returns an error wrapping ErrLDProofVCMismatch if the credential does not match.
+func VerifyLDProofCredential(detail *LDProofVCDetail, vc *verifiable.Credential) error {
+	requested := struct {
+		Context interface{} `json:"@context"`
+		Type    interface{} `json:"type"`
+	}{}
+
+	if err := json.Unmarshal(detail.Credential, &requested); err != nil {
+		return fmt.Errorf("unmarshal requested credential: %w", err)
+	}
+
+	for _, ctx := range toStrings(requested.Context) {
+		if !contains(vc.Context, ctx) {
+			return fmt.Errorf("%w: context '%s' is missing", ErrLDProofVCMismatch, ctx)
+		}
+	}
+
+	for _, t := range toStrings(requested.Type) {
+		if !contains(vc.Types, t) {
+			return fmt.Errorf("%w: type '%s' is missing", ErrLDProofVCMismatch, t)
+		}
+	}
+
+	options := detail.Options
+	if options == nil {
+		options = &LDProofVCDetailOptions{}
+	}
+
+	for _, proof := range vc.Proofs {
+		if proofMatches(proof, options) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: no proof matching requested options", ErrLDProofVCMismatch)
+}
+
+func proofMatches(proof verifiable.Proof, options *LDProofVCDetailOptions) bool {
+	purpose := options.ProofPurpose
+	if purpose == "" {
+		purpose = defaultProofPurpose
+	}
+
+	expected := map[string]string{
+		"type":         options.ProofType,
+		"proofPurpose": purpose,
+		"challenge":    options.Challenge,
+		"domain":       options.Domain,
+	}
+
+	for key, value := range expected {
+		if value == "" {
+			continue
+		}
+
+		// nolint: errcheck
+		if actual, _ := proof[key].(string); actual != value {
+			return false
+		}
+	}
+
+	if options.Created == "" {
+		return true
+	}
+
+	requestedTime, err := time.Parse(time.RFC3339, options.Created)
+	if err != nil {
+		return false
+	}
+
+	// nolint: errcheck
+	created, _ := proof["created"].(string)
+
+	createdTime, err := time.Parse(time.RFC3339, created)
+
+	return err == nil && createdTime.Equal(requestedTime)
+}
+
+// toStrings returns string values of JSON-LD property which is either a string or an array,
+// objects (like embedded contexts) are skipped.
+func toStrings(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		var values []string
+
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,306 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/issuecredential"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestValidateLDProofCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider, _ := newIssuerProvider(t)
+	storeProvider := mockstorage.NewMockStoreProvider()
+	provider.StorageProviderValue = storeProvider
+
+	next := issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+		return nil
+	})
+
+	detail := &LDProofVCDetail{
+		Credential: json.RawMessage(sampleCredential),
+		Options:    &LDProofVCDetailOptions{ProofType: Ed25519Signature2018, Challenge: "challenge"},
+	}
+
+	newMetadata := func(state, piid string, msg service.DIDCommMsg) *mocks.MockMetadata {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(state).AnyTimes()
+		metadata.EXPECT().Properties().Return(map[string]interface{}{piidKey: piid}).AnyTimes()
+		metadata.EXPECT().Message().Return(msg).AnyTimes()
+
+		return metadata
+	}
+
+	t.Run("Ignores processing", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("state-name").AnyTimes()
+		require.NoError(t, ValidateLDProofCredentials(provider)(next).Handle(metadata))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, "piid-1",
+			requestCredentialMsg(t, detail))))
+
+		_, err := storeProvider.Store.Get("piid-1")
+		require.NoError(t, err)
+
+		issued := issueCredential(t, provider, requestCredentialMsg(t, detail))
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameCredentialReceived, "piid-1", issued)))
+
+		_, err = storeProvider.Store.Get("piid-1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("Success (request created from offer)", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		raw, err := json.Marshal(detail)
+		require.NoError(t, err)
+
+		offer := service.NewDIDCommMsgMap(issuecredential.OfferCredential{
+			Type:    issuecredential.OfferCredentialMsgType,
+			Formats: []issuecredential.Format{{AttachID: "detail", Format: LDProofVCDetailFormat}},
+			OffersAttach: []decorator.Attachment{{
+				ID:   "detail",
+				Data: decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString(raw)},
+			}},
+		})
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, "piid-2", offer)))
+
+		issued := issueCredential(t, provider, requestCredentialMsg(t, detail))
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameCredentialReceived, "piid-2", issued)))
+	})
+
+	t.Run("Credential does not match the request", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, "piid-3",
+			requestCredentialMsg(t, detail))))
+
+		issued := issueCredential(t, provider, requestCredentialMsg(t, &LDProofVCDetail{
+			Credential: detail.Credential,
+			Options:    &LDProofVCDetailOptions{ProofType: Ed25519Signature2018, Challenge: "other"},
+		}))
+
+		err := middleware.Handle(newMetadata(stateNameCredentialReceived, "piid-3", issued))
+		require.True(t, errors.Is(err, ErrLDProofVCMismatch))
+	})
+
+	t.Run("Requested detail deleted when protocol instance ends", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		for i, state := range []string{stateNameAbandoning, stateNameDone} {
+			piid := fmt.Sprintf("piid-ended-%d", i)
+
+			require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, piid,
+				requestCredentialMsg(t, detail))))
+
+			_, err := storeProvider.Store.Get(piid)
+			require.NoError(t, err)
+
+			require.NoError(t, middleware.Handle(newMetadata(state, piid, service.NewDIDCommMsgMap(
+				model.ProblemReport{Type: issuecredential.ProblemReportMsgType}))))
+
+			_, err = storeProvider.Store.Get(piid)
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			// nothing is stored for the protocol instance anymore.
+			require.NoError(t, middleware.Handle(newMetadata(state, piid, service.NewDIDCommMsgMap(
+				model.ProblemReport{Type: issuecredential.ProblemReportMsgType}))))
+		}
+	})
+
+	t.Run("No requested detail", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, "piid-4",
+			service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+				Type: issuecredential.RequestCredentialMsgType,
+			}))))
+
+		issued := issueCredential(t, provider, requestCredentialMsg(t, detail))
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameCredentialReceived, "piid-4", issued)))
+	})
+
+	t.Run("Invalid issued credential", func(t *testing.T) {
+		middleware := ValidateLDProofCredentials(provider)(next)
+
+		require.NoError(t, middleware.Handle(newMetadata(stateNameRequestSent, "piid-5",
+			requestCredentialMsg(t, detail))))
+
+		err := middleware.Handle(newMetadata(stateNameCredentialReceived, "piid-5",
+			service.NewDIDCommMsgMap(issuecredential.IssueCredential{
+				Type:    issuecredential.IssueCredentialMsgType,
+				Formats: []issuecredential.Format{{AttachID: "vc", Format: LDProofVCFormat}},
+				CredentialsAttach: []decorator.Attachment{{
+					ID: "vc", Data: decorator.AttachmentData{JSON: map[string]interface{}{"id": "invalid"}},
+				}},
+			})))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse credential")
+	})
+
+	t.Run("Invalid requested detail", func(t *testing.T) {
+		err := ValidateLDProofCredentials(provider)(next).Handle(newMetadata(stateNameRequestSent, "piid-6",
+			requestCredentialMsg(t, &LDProofVCDetail{})))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential is missing")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		failing := &mockprovider.Provider{
+			VDRegistryValue:      provider.VDRegistryValue,
+			StorageProviderValue: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")},
+		}
+
+		err := ValidateLDProofCredentials(failing)(next).Handle(newMetadata(stateNameRequestSent, "piid-7",
+			requestCredentialMsg(t, detail)))
+		require.EqualError(t, err, "open store: open error")
+
+		failing.StorageProviderValue = &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store:  map[string]mockstorage.DBEntry{},
+			ErrPut: errors.New("put error"),
+			ErrGet: errors.New("get error"),
+		}}
+
+		err = ValidateLDProofCredentials(failing)(next).Handle(newMetadata(stateNameRequestSent, "piid-7",
+			requestCredentialMsg(t, detail)))
+		require.EqualError(t, err, "save credential detail: put error")
+
+		err = ValidateLDProofCredentials(failing)(next).Handle(newMetadata(stateNameCredentialReceived, "piid-7",
+			service.NewDIDCommMsgMap(issuecredential.IssueCredential{Type: issuecredential.IssueCredentialMsgType})))
+		require.EqualError(t, err, "get credential detail: get error")
+	})
+}
+
+func TestVerifyLDProofCredential(t *testing.T) {
+	vc, err := verifiable.ParseCredential([]byte(sampleCredential), verifiable.WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	vc.Proofs = []verifiable.Proof{{
+		"type":         Ed25519Signature2018,
+		"proofPurpose": "assertionMethod",
+		"challenge":    "challenge",
+		"domain":       "domain",
+		"created":      "2021-01-01T00:00:00Z",
+	}}
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, VerifyLDProofCredential(&LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+			Options: &LDProofVCDetailOptions{
+				ProofType: Ed25519Signature2018,
+				Challenge: "challenge",
+				Domain:    "domain",
+				Created:   "2021-01-01T00:00:00Z",
+			},
+		}, vc))
+
+		require.NoError(t, VerifyLDProofCredential(&LDProofVCDetail{
+			Credential: json.RawMessage(sampleCredential),
+		}, vc))
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			credential string
+			options    *LDProofVCDetailOptions
+			err        string
+		}{{
+			name:       "context",
+			credential: `{"@context": ["https://www.w3.org/2018/credentials/v1", "https://example.com/v1"]}`,
+			err:        "context 'https://example.com/v1' is missing",
+		}, {
+			name:       "type",
+			credential: `{"type": ["VerifiableCredential", "UniversityDegreeCredential"]}`,
+			err:        "type 'UniversityDegreeCredential' is missing",
+		}, {
+			name:       "proof type",
+			credential: sampleCredential,
+			options:    &LDProofVCDetailOptions{ProofType: JSONWebSignature2020},
+			err:        "no proof matching requested options",
+		}, {
+			name:       "proof purpose",
+			credential: sampleCredential,
+			options:    &LDProofVCDetailOptions{ProofPurpose: "authentication"},
+			err:        "no proof matching requested options",
+		}, {
+			name:       "domain",
+			credential: sampleCredential,
+			options:    &LDProofVCDetailOptions{Domain: "other"},
+			err:        "no proof matching requested options",
+		}, {
+			name:       "created",
+			credential: sampleCredential,
+			options:    &LDProofVCDetailOptions{Created: "2022-01-01T00:00:00Z"},
+			err:        "no proof matching requested options",
+		}}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				err := VerifyLDProofCredential(&LDProofVCDetail{
+					Credential: json.RawMessage(tc.credential),
+					Options:    tc.options,
+				}, vc)
+				require.True(t, errors.Is(err, ErrLDProofVCMismatch))
+				require.Contains(t, err.Error(), tc.err)
+			})
+		}
+	})
+
+	t.Run("Invalid requested credential", func(t *testing.T) {
+		err := VerifyLDProofCredential(&LDProofVCDetail{Credential: json.RawMessage(`[]`)}, vc)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal requested credential")
+	})
+}
+
+// issueCredential returns the issue credential message with the credential issued for the given request.
+func issueCredential(t *testing.T, provider *mockprovider.Provider, request service.DIDCommMsg) service.DIDCommMsg {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := &issuecredential.IssueCredential{Type: issuecredential.IssueCredentialMsgType}
+
+	metadata := mocks.NewMockMetadata(ctrl)
+	metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+	metadata.EXPECT().IssueCredential().Return(msg)
+	metadata.EXPECT().Message().Return(request)
+
+	require.NoError(t, IssueLDProofCredentials(provider, WithIssuerDID(issuerDID))(issuecredential.HandlerFunc(
+		func(issuecredential.Metadata) error { return nil },
+	)).Handle(metadata))
+
+	return service.NewDIDCommMsgMap(msg)
+}
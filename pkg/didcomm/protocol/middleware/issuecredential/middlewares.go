@@ -14,6 +14,8 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	storeverifiable "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
@@ -21,9 +23,12 @@ import (
 
 const (
 	stateNameCredentialReceived = "credential-received"
+	stateNameRequestReceived    = "request-received"
 	myDIDKey                    = "myDID"
 	theirDIDKey                 = "theirDID"
 	namesKey                    = "names"
+	manifestIDKey               = "manifestID"
+	applicationIDKey            = "applicationID"
 )
 
 // Metadata is an alias to the original Metadata.
@@ -46,14 +51,12 @@ func SaveCredentials(p Provider) issuecredential.Middleware {
 				return next.Handle(metadata)
 			}
 
-			credential := issuecredential.IssueCredential{}
-
-			err := metadata.Message().Decode(&credential)
+			credential, err := issuecredential.DecodeIssueCredential(metadata.Message())
 			if err != nil {
 				return fmt.Errorf("decode: %w", err)
 			}
 
-			credentials, err := toVerifiableCredentials(vdr, credential.CredentialsAttach)
+			credentials, err := toVerifiableCredentials(vdr, credential.Formats, credential.CredentialsAttach)
 			if err != nil {
 				return fmt.Errorf("to verifiable credentials: %w", err)
 			}
@@ -105,7 +108,99 @@ func getName(idx int, id string, metadata issuecredential.Metadata) string {
 	return uuid.New().String()
 }
 
-func toVerifiableCredentials(v vdrapi.Registry, attachments []decorator.Attachment) ([]*verifiable.Credential, error) {
+// ManifestLookup returns the credential manifest (published by the issuer) by its ID.
+type ManifestLookup func(id string) (*cm.CredentialManifest, error)
+
+// ValidateCredentialApplication the helper function for the issue credential protocol which validates
+// the credential application (attached to the request credential message) against the credential manifest
+// of the issuer. The IDs of the manifest and of the application are set to the properties of the protocol.
+func ValidateCredentialApplication(p Provider, lookup ManifestLookup) issuecredential.Middleware {
+	vdr := p.VDRegistry()
+
+	return func(next issuecredential.Handler) issuecredential.Handler {
+		return issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+			if metadata.StateName() != stateNameRequestReceived {
+				return next.Handle(metadata)
+			}
+
+			request, err := issuecredential.DecodeRequestCredential(metadata.Message())
+			if err != nil {
+				return fmt.Errorf("decode: %w", err)
+			}
+
+			src, err := getAttachmentByFormat(request.Formats, request.RequestsAttach,
+				cm.CredentialApplicationAttachmentFormat)
+			if errors.Is(err, errAttachmentNotFound) {
+				return next.Handle(metadata)
+			}
+
+			if err != nil {
+				return fmt.Errorf("get attachment by format: %w", err)
+			}
+
+			vp, err := verifiable.ParsePresentation(src,
+				verifiable.WithPresPublicKeyFetcher(verifiable.NewVDRKeyResolver(vdr).PublicKeyFetcher()),
+				verifiable.WithPresJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+			if err != nil {
+				return fmt.Errorf("parse credential application: %w", err)
+			}
+
+			application, err := cm.ApplicationFromPresentation(vp)
+			if err != nil {
+				return err
+			}
+
+			manifest, err := lookup(application.ManifestID)
+			if err != nil {
+				return fmt.Errorf("lookup credential manifest: %w", err)
+			}
+
+			_, err = manifest.ValidateApplication(vp, presexch.WithCredentialOptions(
+				verifiable.WithPublicKeyFetcher(verifiable.NewVDRKeyResolver(vdr).PublicKeyFetcher()),
+				verifiable.WithJSONLDDocumentLoader(cm.CachingJSONLDLoader()),
+			))
+			if err != nil {
+				return fmt.Errorf("validate credential application: %w", err)
+			}
+
+			properties := metadata.Properties()
+			properties[manifestIDKey] = manifest.ID
+			properties[applicationIDKey] = application.ID
+
+			return next.Handle(metadata)
+		})
+	}
+}
+
+var errAttachmentNotFound = errors.New("not found")
+
+func getAttachmentByFormat(fms []issuecredential.Format, attachments []decorator.Attachment,
+	name string) ([]byte, error) {
+	for _, format := range fms {
+		if format.Format == name {
+			for i := range attachments {
+				if attachments[i].ID == format.AttachID {
+					return attachments[i].Data.Fetch()
+				}
+			}
+		}
+	}
+
+	return nil, errAttachmentNotFound
+}
+
+func getFormat(fms []issuecredential.Format, attachID string) string {
+	for _, format := range fms {
+		if format.AttachID == attachID {
+			return format.Format
+		}
+	}
+
+	return ""
+}
+
+func toVerifiableCredentials(v vdrapi.Registry, formats []issuecredential.Format,
+	attachments []decorator.Attachment) ([]*verifiable.Credential, error) {
 	var credentials []*verifiable.Credential
 
 	for i := range attachments {
@@ -114,6 +209,19 @@ func toVerifiableCredentials(v vdrapi.Registry, attachments []decorator.Attachme
 			return nil, fmt.Errorf("fetch: %w", err)
 		}
 
+		// credential fulfillment is the presentation containing the issued credentials.
+		if attachments[i].ID != "" &&
+			getFormat(formats, attachments[i].ID) == cm.CredentialFulfillmentAttachmentFormat {
+			fulfilled, err := fulfillmentCredentials(v, rawVC)
+			if err != nil {
+				return nil, fmt.Errorf("credential fulfillment: %w", err)
+			}
+
+			credentials = append(credentials, fulfilled...)
+
+			continue
+		}
+
 		vc, err := verifiable.ParseCredential(rawVC, verifiable.WithPublicKeyFetcher(
 			verifiable.NewVDRKeyResolver(v).PublicKeyFetcher(),
 		))
@@ -126,3 +234,36 @@ func toVerifiableCredentials(v vdrapi.Registry, attachments []decorator.Attachme
 
 	return credentials, nil
 }
+
+func fulfillmentCredentials(v vdrapi.Registry, rawVP []byte) ([]*verifiable.Credential, error) {
+	vp, err := verifiable.ParsePresentation(rawVP,
+		verifiable.WithPresPublicKeyFetcher(verifiable.NewVDRKeyResolver(v).PublicKeyFetcher()),
+		verifiable.WithPresJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+	if err != nil {
+		return nil, fmt.Errorf("parse presentation: %w", err)
+	}
+
+	if _, err = cm.FulfillmentFromPresentation(vp); err != nil {
+		return nil, err
+	}
+
+	marshalled, err := vp.MarshalledCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("marshalled credentials: %w", err)
+	}
+
+	var credentials []*verifiable.Credential
+
+	for _, raw := range marshalled {
+		vc, err := verifiable.ParseCredential(raw, verifiable.WithPublicKeyFetcher(
+			verifiable.NewVDRKeyResolver(v).PublicKeyFetcher(),
+		))
+		if err != nil {
+			return nil, fmt.Errorf("new credential: %w", err)
+		}
+
+		credentials = append(credentials, vc)
+	}
+
+	return credentials, nil
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/issuecredential"
@@ -312,4 +314,209 @@ func TestSaveCredentials(t *testing.T) {
 		require.NoError(t, SaveCredentials(provider)(next).Handle(metadata))
 		require.Equal(t, props["names"], []string{vcName})
 	})
+
+	t.Run("Success (credential fulfillment)", func(t *testing.T) {
+		props := map[string]interface{}{
+			myDIDKey:    myDIDKey,
+			theirDIDKey: theirDIDKey,
+		}
+
+		vp, err := newManifest().CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"degree": newBaseCredential(),
+		})
+		require.NoError(t, err)
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameCredentialReceived)
+		metadata.EXPECT().CredentialNames().Return([]string{})
+		metadata.EXPECT().Properties().Return(props)
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(issuecredential.IssueCredential{
+			Type: issuecredential.IssueCredentialMsgType,
+			Formats: []issuecredential.Format{{
+				AttachID: "fulfillment",
+				Format:   cm.CredentialFulfillmentAttachmentFormat,
+			}},
+			CredentialsAttach: []decorator.Attachment{
+				{ID: "fulfillment", Data: decorator.AttachmentData{JSON: vp}},
+			},
+		}))
+
+		verifiableStore := mockstore.NewMockStore(ctrl)
+		verifiableStore.EXPECT().SaveCredential("http://example.edu/credentials/1872", gomock.Any(),
+			gomock.Any(), gomock.Any()).Return(nil)
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().VDRegistry().Return(nil).AnyTimes()
+		provider.EXPECT().VerifiableStore().Return(verifiableStore)
+
+		require.NoError(t, SaveCredentials(provider)(next).Handle(metadata))
+		require.Equal(t, []string{"http://example.edu/credentials/1872"}, props["names"])
+	})
+
+	t.Run("Invalid credential fulfillment", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation(verifiable.WithCredentials(newBaseCredential()))
+		require.NoError(t, err)
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameCredentialReceived)
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(issuecredential.IssueCredential{
+			Type: issuecredential.IssueCredentialMsgType,
+			Formats: []issuecredential.Format{{
+				AttachID: "fulfillment",
+				Format:   cm.CredentialFulfillmentAttachmentFormat,
+			}},
+			CredentialsAttach: []decorator.Attachment{
+				{ID: "fulfillment", Data: decorator.AttachmentData{JSON: vp}},
+			},
+		}))
+
+		err = SaveCredentials(provider)(next).Handle(metadata)
+		require.EqualError(t, err, "to verifiable credentials: credential fulfillment: credential fulfillment: "+
+			"presentation must have json-ld type CredentialFulfillment")
+	})
+}
+
+func TestValidateCredentialApplication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().VDRegistry().Return(nil).AnyTimes()
+
+	next := issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+		return nil
+	})
+
+	manifest := newManifest()
+
+	lookup := func(id string) (*cm.CredentialManifest, error) {
+		if id != manifest.ID {
+			return nil, errors.New("not found")
+		}
+
+		return manifest, nil
+	}
+
+	requestMsg := func(application interface{}) service.DIDCommMsg {
+		return service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+			Type: issuecredential.RequestCredentialMsgType,
+			Formats: []issuecredential.Format{{
+				AttachID: "application",
+				Format:   cm.CredentialApplicationAttachmentFormat,
+			}},
+			RequestsAttach: []decorator.Attachment{
+				{ID: "application", Data: decorator.AttachmentData{JSON: application}},
+			},
+		})
+	}
+
+	t.Run("Ignores processing", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("state-name")
+		require.NoError(t, ValidateCredentialApplication(provider, lookup)(next).Handle(metadata))
+	})
+
+	t.Run("No credential application", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(issuecredential.RequestCredential{
+			Type: issuecredential.RequestCredentialMsgType,
+		}))
+
+		require.NoError(t, ValidateCredentialApplication(provider, lookup)(next).Handle(metadata))
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(service.DIDCommMsgMap{"@type": map[int]int{}})
+
+		err := ValidateCredentialApplication(provider, lookup)(next).Handle(metadata)
+		require.Contains(t, fmt.Sprintf("%v", err), "got unconvertible type")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		vp, err := manifest.CreateApplication([]*verifiable.Credential{newBaseCredential()})
+		require.NoError(t, err)
+
+		props := map[string]interface{}{}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(requestMsg(vp))
+		metadata.EXPECT().Properties().Return(props)
+
+		require.NoError(t, ValidateCredentialApplication(provider, lookup)(next).Handle(metadata))
+		require.Equal(t, manifest.ID, props[manifestIDKey])
+		require.NotEmpty(t, props[applicationIDKey])
+	})
+
+	t.Run("Invalid presentation", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(requestMsg(map[string]interface{}{}))
+
+		err := ValidateCredentialApplication(provider, lookup)(next).Handle(metadata)
+		require.Contains(t, fmt.Sprintf("%v", err), "parse credential application")
+	})
+
+	t.Run("Unknown manifest", func(t *testing.T) {
+		other := newManifest()
+		other.ID = "other"
+
+		vp, err := other.CreateApplication([]*verifiable.Credential{newBaseCredential()})
+		require.NoError(t, err)
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(requestMsg(vp))
+
+		err = ValidateCredentialApplication(provider, lookup)(next).Handle(metadata)
+		require.EqualError(t, err, "lookup credential manifest: not found")
+	})
+
+	t.Run("Application does not satisfy manifest", func(t *testing.T) {
+		noInputs := newManifest()
+		noInputs.PresentationDefinition = nil
+
+		vp, err := noInputs.CreateApplication(nil)
+		require.NoError(t, err)
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(requestMsg(vp))
+
+		err = ValidateCredentialApplication(provider, lookup)(next).Handle(metadata)
+		require.Contains(t, fmt.Sprintf("%v", err), "validate credential application")
+	})
+}
+
+func newBaseCredential() *verifiable.Credential {
+	return &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      "http://example.edu/credentials/1872",
+		Types:   []string{verifiable.VCType},
+		Subject: "did:example:ebfeb1f712ebc6f1c276e12ec21",
+		Issuer:  verifiable.Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+		Issued:  util.NewTime(time.Date(2010, time.January, 1, 19, 23, 24, 0, time.UTC)),
+		Schemas: []verifiable.TypedID{{ID: verifiable.ContextURI, Type: "JsonSchema"}},
+	}
+}
+
+func newManifest() *cm.CredentialManifest {
+	return &cm.CredentialManifest{
+		ID:     "manifest-id",
+		Issuer: cm.Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+		OutputDescriptors: []*cm.OutputDescriptor{{
+			ID:     "degree",
+			Schema: verifiable.VCType,
+		}},
+		PresentationDefinition: &presexch.PresentationDefinition{
+			ID: "definition-id",
+			InputDescriptors: []*presexch.InputDescriptor{{
+				ID:     "identity",
+				Schema: []*presexch.Schema{{URI: verifiable.ContextURI}},
+			}},
+		},
+	}
 }
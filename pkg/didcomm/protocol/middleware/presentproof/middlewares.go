@@ -65,8 +65,8 @@ func SavePresentation(p Provider) presentproof.Middleware {
 				return next.Handle(metadata)
 			}
 
-			presentation := presentproof.Presentation{}
-			if err := metadata.Message().Decode(&presentation); err != nil {
+			presentation, err := presentproof.DecodePresentation(metadata.Message())
+			if err != nil {
 				return fmt.Errorf("decode: %w", err)
 			}
 
@@ -182,8 +182,8 @@ func PresentationDefinition(p Provider, opts ...OptPD) presentproof.Middleware {
 				return next.Handle(metadata)
 			}
 
-			request := presentproof.RequestPresentation{}
-			if err := metadata.Message().Decode(&request); err != nil {
+			request, err := presentproof.DecodeRequestPresentation(metadata.Message())
+			if err != nil {
 				return fmt.Errorf("decode: %w", err)
 			}
 
@@ -0,0 +1,304 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// InvitationStateActive the invitation can be used.
+	InvitationStateActive = "active"
+	// InvitationStateUsed the invitation was used the maximum number of times.
+	InvitationStateUsed = "used"
+	// InvitationStateExpired the invitation expired.
+	InvitationStateExpired = "expired"
+	// InvitationStateRevoked the invitation was revoked.
+	InvitationStateRevoked = "revoked"
+
+	invitationRecordTag = "invitation_record"
+	invitationRecordKey = invitationRecordTag + "_%s"
+	invitationUseKey    = "invitation_use_%s_%s"
+
+	maxInvitationUpdateAttempts = 10
+)
+
+// ErrInvitationNotActive is returned when the invitation is used, expired or revoked.
+var ErrInvitationNotActive = errors.New("invitation is not active")
+
+// InvitationRecord keeps track of an invitation created by the agent.
+type InvitationRecord struct {
+	Invitation *Invitation `json:"invitation"`
+	// State is one of active, used, expired or revoked.
+	State string `json:"state"`
+	// MaxUses is the number of times the invitation can be used, zero means unlimited (multi-use invitation).
+	MaxUses   int        `json:"max_uses,omitempty"`
+	Uses      int        `json:"uses"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (r *InvitationRecord) updateState() {
+	switch {
+	case r.RevokedAt != nil:
+		r.State = InvitationStateRevoked
+	case r.ExpiresAt != nil && !time.Now().Before(*r.ExpiresAt):
+		r.State = InvitationStateExpired
+	case r.MaxUses > 0 && r.Uses >= r.MaxUses:
+		r.State = InvitationStateUsed
+	default:
+		r.State = InvitationStateActive
+	}
+}
+
+// InvitationOpt customizes the tracking of the saved invitation.
+type InvitationOpt func(*InvitationRecord)
+
+// WithMaxUses limits the number of times the invitation can be used, e.g 1 for a single-use invitation.
+func WithMaxUses(maxUses int) InvitationOpt {
+	return func(r *InvitationRecord) {
+		r.MaxUses = maxUses
+	}
+}
+
+// WithExpiry sets the time the invitation expires at.
+func WithExpiry(expiresAt time.Time) InvitationOpt {
+	return func(r *InvitationRecord) {
+		r.ExpiresAt = &expiresAt
+	}
+}
+
+// GetInvitationRecord returns the record of the invitation created by the agent.
+func (s *Service) GetInvitationRecord(invitationID string) (*InvitationRecord, error) {
+	src, err := s.invitationStore.Get(fmt.Sprintf(invitationRecordKey, invitationID))
+	if err != nil {
+		return nil, fmt.Errorf("get invitation record: %w", err)
+	}
+
+	record := &InvitationRecord{}
+
+	if err = json.Unmarshal(src, record); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation record: %w", err)
+	}
+
+	record.updateState()
+
+	return record, nil
+}
+
+// InvitationRecords returns the records of the invitations created by the agent.
+func (s *Service) InvitationRecords() ([]*InvitationRecord, error) {
+	iter, err := s.invitationStore.Query(invitationRecordTag)
+	if err != nil {
+		return nil, fmt.Errorf("query invitation records: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	var records []*InvitationRecord
+
+	more, err := iter.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next set of data from records: %w", err)
+	}
+
+	for more {
+		value, errValue := iter.Value()
+		if errValue != nil {
+			return nil, fmt.Errorf("failed to get value from records: %w", errValue)
+		}
+
+		record := &InvitationRecord{}
+		if errUnmarshal := json.Unmarshal(value, record); errUnmarshal != nil {
+			return nil, fmt.Errorf("unmarshal invitation record: %w", errUnmarshal)
+		}
+
+		record.updateState()
+
+		records = append(records, record)
+
+		var errNext error
+
+		more, errNext = iter.Next()
+		if errNext != nil {
+			return nil, fmt.Errorf("failed to get next set of data from records: %w", errNext)
+		}
+	}
+
+	return records, nil
+}
+
+// RevokeInvitation revokes the invitation, the connection requests referencing it are rejected.
+func (s *Service) RevokeInvitation(invitationID string) error {
+	return s.updateInvitationRecord(invitationID, func(record *InvitationRecord) error {
+		if record.RevokedAt == nil {
+			now := time.Now()
+			record.RevokedAt = &now
+		}
+
+		return nil
+	})
+}
+
+// checkInvitation rejects the requests referencing an invitation created by the agent which is no longer active,
+// the use is counted by useInvitation once the request is accepted.
+// The invitations which are not tracked (e.g. created by the didexchange client) are ignored.
+func (s *Service) checkInvitation(invitationID string) error {
+	record, err := s.GetInvitationRecord(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if record.State != InvitationStateActive {
+		return fmt.Errorf("invitation %s is %s: %w", invitationID, record.State, ErrInvitationNotActive)
+	}
+
+	return nil
+}
+
+// useInvitation counts a use of the invitation created by the agent once a request referencing it is accepted,
+// the use is identified by the given ID (e.g. the connection ID) and counted only once.
+// The invitations which are not tracked (e.g. created by the didexchange client) are ignored.
+func (s *Service) useInvitation(invitationID, useID string) error {
+	useKey := fmt.Sprintf(invitationUseKey, invitationID, useID)
+
+	_, err := s.invitationStore.Get(useKey)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("get invitation use: %w", err)
+	}
+
+	err = s.updateInvitationRecord(invitationID, func(record *InvitationRecord) error {
+		if record.State != InvitationStateActive {
+			return fmt.Errorf("invitation %s is %s: %w", invitationID, record.State, ErrInvitationNotActive)
+		}
+
+		record.Uses++
+
+		logger.Debugf("invitation %s used %d time(s)", invitationID, record.Uses)
+
+		return nil
+	})
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = s.invitationStore.Put(useKey, []byte(useID)); err != nil {
+		return fmt.Errorf("save invitation use: %w", err)
+	}
+
+	return nil
+}
+
+// updateInvitationRecord applies the update to the invitation record with a compare-and-set of the stored record.
+// The update is applied again to the latest record if the record was changed in the meantime,
+// e.g. by another agent instance sharing the store.
+func (s *Service) updateInvitationRecord(invitationID string, update func(*InvitationRecord) error) error {
+	key := fmt.Sprintf(invitationRecordKey, invitationID)
+
+	for i := 0; i < maxInvitationUpdateAttempts; i++ {
+		old, err := s.invitationStore.Get(key)
+		if err != nil {
+			return fmt.Errorf("get invitation record: %w", err)
+		}
+
+		record := &InvitationRecord{}
+
+		if err = json.Unmarshal(old, record); err != nil {
+			return fmt.Errorf("unmarshal invitation record: %w", err)
+		}
+
+		record.updateState()
+
+		if err = update(record); err != nil {
+			return err
+		}
+
+		record.updateState()
+
+		src, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("marshal invitation record: %w", err)
+		}
+
+		swapped, err := s.compareAndSet(key, old, src, storage.Tag{Name: invitationRecordTag})
+		if err != nil {
+			return fmt.Errorf("save invitation record: %w", err)
+		}
+
+		if swapped {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invitation record %s is being updated concurrently", invitationID)
+}
+
+// compareAndSetter is implemented by the stores able to replace a value only if it was not changed since it was read,
+// e.g. a database supporting conditional writes. That keeps the invitation records consistent across
+// the agent instances sharing the store.
+type compareAndSetter interface {
+	CompareAndSet(key string, old, value []byte, tags ...storage.Tag) (bool, error)
+}
+
+// compareAndSet replaces the value of the key if it is still the given old value, it returns false otherwise.
+// The stores which do not implement compareAndSetter are only guarded against the concurrent updates
+// of this agent instance.
+func (s *Service) compareAndSet(key string, old, value []byte, tags ...storage.Tag) (bool, error) {
+	if store, ok := s.invitationStore.(compareAndSetter); ok {
+		return store.CompareAndSet(key, old, value, tags...)
+	}
+
+	s.invitationsMutex.Lock()
+	defer s.invitationsMutex.Unlock()
+
+	current, err := s.invitationStore.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	if !bytes.Equal(current, old) {
+		return false, nil
+	}
+
+	return true, s.invitationStore.Put(key, value, tags...)
+}
+
+func (s *Service) saveInvitationRecord(record *InvitationRecord) error {
+	record.updateState()
+
+	src, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal invitation record: %w", err)
+	}
+
+	err = s.invitationStore.Put(fmt.Sprintf(invitationRecordKey, record.Invitation.ID), src,
+		storage.Tag{Name: invitationRecordTag})
+	if err != nil {
+		return fmt.Errorf("save invitation record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestInvitationRecords(t *testing.T) {
+	t.Run("single-use invitation", func(t *testing.T) {
+		s, didSvc := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithMaxUses(1)))
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateActive, record.State)
+		require.Equal(t, inv.ID, record.Invitation.ID)
+		require.False(t, record.CreatedAt.IsZero())
+
+		// the requests are validated on receipt, the use is counted once a request is accepted
+		require.NoError(t, didSvc.validate(inv.ID))
+		require.NoError(t, didSvc.validate(inv.ID))
+
+		record, err = s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, record.Uses)
+
+		require.NoError(t, didSvc.use(inv.ID, "first"))
+		// the same use is counted once
+		require.NoError(t, didSvc.use(inv.ID, "first"))
+
+		record, err = s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateUsed, record.State)
+		require.Equal(t, 1, record.Uses)
+
+		err = didSvc.validate(inv.ID)
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+
+		err = didSvc.use(inv.ID, "second")
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+	})
+
+	t.Run("multi-use invitation", func(t *testing.T) {
+		s, didSvc := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv))
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, didSvc.use(inv.ID, fmt.Sprint(i)))
+		}
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateActive, record.State)
+		require.Equal(t, 3, record.Uses)
+	})
+
+	t.Run("concurrent uses of a single-use invitation", func(t *testing.T) {
+		s, didSvc := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithMaxUses(1)))
+
+		const uses = 10
+
+		var (
+			wg   sync.WaitGroup
+			used int32
+		)
+
+		for i := 0; i < uses; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				if didSvc.use(inv.ID, fmt.Sprint(i)) == nil {
+					atomic.AddInt32(&used, 1)
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int32(1), used)
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, record.Uses)
+	})
+
+	t.Run("store with compare-and-set", func(t *testing.T) {
+		store := &casStore{Store: newStore(t)}
+
+		s, didSvc := newInvitationsService(t)
+		s.invitationStore = store
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithMaxUses(2)))
+
+		// another agent instance used the invitation after it was read
+		store.beforeSet = func() {
+			store.beforeSet = nil
+
+			require.NoError(t, didSvc.use(inv.ID, "other"))
+		}
+
+		require.NoError(t, didSvc.use(inv.ID, "mine"))
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateUsed, record.State)
+		require.Equal(t, 2, record.Uses)
+		require.Equal(t, 3, store.sets)
+
+		store.err = errors.New("test")
+
+		err = s.RevokeInvitation(inv.ID)
+		require.EqualError(t, err, "save invitation record: test")
+	})
+
+	t.Run("expired invitation", func(t *testing.T) {
+		s, didSvc := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithExpiry(time.Now().Add(-time.Second))))
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateExpired, record.State)
+
+		err = didSvc.validate(inv.ID)
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+
+		err = didSvc.use(inv.ID, "connection")
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+	})
+
+	t.Run("revoked invitation", func(t *testing.T) {
+		s, didSvc := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithExpiry(time.Now().Add(time.Hour))))
+		require.NoError(t, s.RevokeInvitation(inv.ID))
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateRevoked, record.State)
+		require.NotNil(t, record.RevokedAt)
+
+		err = didSvc.validate(inv.ID)
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+
+		err = didSvc.use(inv.ID, "connection")
+		require.True(t, errors.Is(err, ErrInvitationNotActive))
+
+		err = s.RevokeInvitation("unknown")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("untracked invitation", func(t *testing.T) {
+		_, didSvc := newInvitationsService(t)
+
+		require.NoError(t, didSvc.validate("did:example:public"))
+		require.NoError(t, didSvc.use("did:example:public", "connection"))
+	})
+
+	t.Run("list invitations", func(t *testing.T) {
+		s, _ := newInvitationsService(t)
+
+		records, err := s.InvitationRecords()
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		first, second := newInvitation(), newInvitation()
+		require.NoError(t, s.SaveInvitation(first))
+		require.NoError(t, s.SaveInvitation(second, WithMaxUses(1)))
+		require.NoError(t, s.RevokeInvitation(first.ID))
+
+		records, err = s.InvitationRecords()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		states := map[string]string{}
+		for _, record := range records {
+			states[record.Invitation.ID] = record.State
+		}
+
+		require.Equal(t, InvitationStateRevoked, states[first.ID])
+		require.Equal(t, InvitationStateActive, states[second.ID])
+	})
+}
+
+// validatingDIDExchangeSvc keeps the invitation validator and use handler set by the out-of-band service.
+type validatingDIDExchangeSvc struct {
+	*mockdidexchange.MockDIDExchangeSvc
+	validate func(string) error
+	use      func(string, string) error
+}
+
+func (v *validatingDIDExchangeSvc) SetInvitationValidator(validate func(string) error) {
+	v.validate = validate
+}
+
+func (v *validatingDIDExchangeSvc) SetInvitationUseHandler(use func(string, string) error) {
+	v.use = use
+}
+
+func newInvitationsService(t *testing.T) (*Service, *validatingDIDExchangeSvc) {
+	t.Helper()
+
+	didSvc := &validatingDIDExchangeSvc{MockDIDExchangeSvc: &mockdidexchange.MockDIDExchangeSvc{}}
+
+	provider := testProvider()
+	provider.StoreProvider = mem.NewProvider()
+	provider.ServiceMap[didexchange.DIDExchange] = didSvc
+
+	s := newAutoService(t, provider)
+	require.NotNil(t, didSvc.validate)
+	require.NotNil(t, didSvc.use)
+
+	return s, didSvc
+}
+
+func newStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	store, err := mem.NewProvider().OpenStore(Name)
+	require.NoError(t, err)
+
+	return store
+}
+
+// casStore is a store supporting compare-and-set.
+type casStore struct {
+	storage.Store
+	mu        sync.Mutex
+	beforeSet func()
+	sets      int
+	err       error
+}
+
+func (c *casStore) CompareAndSet(key string, old, value []byte, tags ...storage.Tag) (bool, error) {
+	if c.beforeSet != nil {
+		c.beforeSet()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return false, c.err
+	}
+
+	c.sets++
+
+	current, err := c.Store.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	if !bytes.Equal(current, old) {
+		return false, nil
+	}
+
+	return true, c.Store.Put(key, value, tags...)
+}
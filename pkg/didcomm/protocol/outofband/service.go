@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mitchellh/mapstructure"
@@ -29,10 +31,14 @@ const (
 	Name = "out-of-band"
 	// PIURI is the Out-of-Band protocol's protocol instance URI.
 	PIURI = "https://didcomm.org/out-of-band/1.0"
+	// PIURIV11 is the Out-of-Band 1.1 protocol's protocol instance URI.
+	PIURIV11 = "https://didcomm.org/out-of-band/1.1"
 	// oldPIURI is the old OOB protocol's protocol instance URI.
 	oldPIURI = "did:sov:BzCbsNYhMrjHiqZDTUASHg;spec/out-of-band/1.0"
 	// InvitationMsgType is the '@type' for the invitation message.
 	InvitationMsgType = PIURI + "/invitation"
+	// InvitationMsgTypeV11 is the '@type' for the Out-of-Band 1.1 invitation message.
+	InvitationMsgTypeV11 = PIURIV11 + "/invitation"
 	// OldInvitationMsgType is the `@type` for the old invitation message.
 	OldInvitationMsgType = oldPIURI + "/invitation"
 	// HandshakeReuseMsgType is the '@type' for the reuse message.
@@ -44,6 +50,9 @@ const (
 	callbackChannelSize = 10
 
 	contextKey = "context_%s"
+
+	jsonThread         = "~thread"
+	jsonParentThreadID = "pthid"
 )
 
 var logger = log.New(fmt.Sprintf("aries-framework/%s/service", Name))
@@ -64,6 +73,14 @@ type didExchSvc interface {
 	SaveInvitation(invitation *didexchange.OOBInvitation) error
 }
 
+// invitationValidator is implemented by the didexchange service,
+// the connection requests referencing the invitations are validated before being processed
+// and the uses of the invitations are counted once the requests are accepted.
+type invitationValidator interface {
+	SetInvitationValidator(func(invitationID string) error)
+	SetInvitationUseHandler(func(invitationID, connectionID string) error)
+}
+
 type connectionRecorder interface {
 	SaveInvitation(string, interface{}) error
 	GetConnectionRecord(string) (*connection.Record, error)
@@ -79,6 +96,8 @@ type Service struct {
 	didSvc                     didExchSvc
 	didEvents                  chan service.StateMsg
 	transientStore             storage.Store
+	invitationStore            storage.Store
+	invitationsMutex           sync.Mutex
 	connections                connectionRecorder
 	inboundHandler             func() service.InboundHandler
 	chooseAttachmentFunc       func(*attachmentHandlingState) (*decorator.Attachment, error)
@@ -158,6 +177,17 @@ func New(p Provider) (*Service, error) {
 		return nil, fmt.Errorf("failed to set transientStore config in protocol state transientStore: %w", err)
 	}
 
+	invitationStore, err := p.StorageProvider().OpenStore(Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the invitation store : %w", err)
+	}
+
+	err = p.StorageProvider().SetStoreConfig(Name,
+		storage.StoreConfiguration{TagNames: []string{invitationRecordTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set invitation store config : %w", err)
+	}
+
 	connectionRecorder, err := connection.NewRecorder(p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open a connection.Lookup : %w", err)
@@ -168,6 +198,7 @@ func New(p Provider) (*Service, error) {
 		didSvc:                     didSvc,
 		didEvents:                  make(chan service.StateMsg, callbackChannelSize),
 		transientStore:             store,
+		invitationStore:            invitationStore,
 		connections:                connectionRecorder,
 		inboundHandler:             p.InboundDIDCommMessageHandler(),
 		chooseAttachmentFunc:       chooseAttachment,
@@ -186,6 +217,12 @@ func New(p Provider) (*Service, error) {
 		return nil, fmt.Errorf("failed to register for didexchange protocol msgs : %w", err)
 	}
 
+	// the usage limit, expiry and revocation of the invitations are enforced on the connection requests
+	if validator, ok := didSvc.(invitationValidator); ok {
+		validator.SetInvitationValidator(s.checkInvitation)
+		validator.SetInvitationUseHandler(s.useInvitation)
+	}
+
 	go s.listenerFunc()
 
 	return s, nil
@@ -199,7 +236,8 @@ func (s *Service) Name() string {
 // Accept determines whether this service can handle the given type of message.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
-	case InvitationMsgType, HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType, OldInvitationMsgType:
+	case InvitationMsgType, InvitationMsgTypeV11, HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType,
+		OldInvitationMsgType:
 		return true
 	}
 
@@ -219,6 +257,14 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, didCommCtx service.DIDCo
 		return "", fmt.Errorf("no clients registered to handle action events for %s protocol", Name)
 	}
 
+	// the connection reuse is rejected early if the invitation is no longer active,
+	// the use is counted once the reuse is accepted
+	if msg.Type() == HandshakeReuseMsgType {
+		if err := s.checkInvitation(reusedInvitationID(msg)); err != nil {
+			return "", fmt.Errorf("invalid invitation for handshake reuse with @id=%s: %w", msg.ID(), err)
+		}
+	}
+
 	myContext, err := s.currentContext(msg, didCommCtx, nil)
 	if err != nil {
 		return "", fmt.Errorf("unable to load current context for msgID=%s: %w", msg.ID(), err)
@@ -248,6 +294,7 @@ func (s *Service) handleContext(ctx *context) error { // nolint:funlen
 		didSvc:                s.didSvc,
 		saveAttchStateFunc:    s.save,
 		dispatchAttachmntFunc: s.dispatchInvitationAttachment,
+		useInvitationFunc:     s.useInvitation,
 	}
 
 	var (
@@ -500,7 +547,7 @@ func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, err
 }
 
 func (s *Service) currentContext(msg service.DIDCommMsg, ctx service.DIDCommContext, opts Options) (*context, error) {
-	if msg.Type() == InvitationMsgType || msg.Type() == HandshakeReuseMsgType {
+	if isInvitation(msg.Type()) || msg.Type() == HandshakeReuseMsgType {
 		myContext := &context{
 			Action: Action{
 				PIID:         msg.ID(),
@@ -564,12 +611,27 @@ func (s *Service) AcceptInvitation(i *Invitation, options Options) (string, erro
 }
 
 // SaveInvitation created by the outofband client.
-func (s *Service) SaveInvitation(i *Invitation) error {
+// The invitation is tracked, by default it can be used an unlimited number of times and never expires.
+func (s *Service) SaveInvitation(i *Invitation, opts ...InvitationOpt) error {
 	target, err := chooseTarget(i.Services)
 	if err != nil {
 		return fmt.Errorf("failed to choose a target to connect against : %w", err)
 	}
 
+	record := &InvitationRecord{
+		Invitation: i,
+		CreatedAt:  time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(record)
+	}
+
+	err = s.saveInvitationRecord(record)
+	if err != nil {
+		return fmt.Errorf("failed to save oob invitation record : %w", err)
+	}
+
 	// TODO where should we save this invitation? - https://github.com/hyperledger/aries-framework-go/issues/1547
 	err = s.connections.SaveInvitation(i.ID+"-TODO", i)
 	if err != nil {
@@ -602,7 +664,7 @@ func listener(
 			select {
 			case c := <-callbacks:
 				switch c.msg.Type() {
-				case InvitationMsgType, HandshakeReuseMsgType, OldInvitationMsgType:
+				case InvitationMsgType, InvitationMsgTypeV11, HandshakeReuseMsgType, OldInvitationMsgType:
 					_, err := handleCallbackFunc(c)
 					if err != nil {
 						logutil.LogError(logger, Name, "handleCallback", err.Error(),
@@ -632,7 +694,7 @@ func listener(
 
 func (s *Service) handleCallback(c *callback) (string, error) {
 	switch c.msg.Type() {
-	case InvitationMsgType, OldInvitationMsgType:
+	case InvitationMsgType, InvitationMsgTypeV11, OldInvitationMsgType:
 		return s.handleInvitationCallback(c)
 	case HandshakeReuseMsgType:
 		return "", s.handleHandshakeReuseCallback(c)
@@ -703,6 +765,15 @@ func (s *Service) dispatchInvitationAttachment(invID, myDID, theirDID string) er
 		return fmt.Errorf("failed to extract DIDComm msg : %w", err)
 	}
 
+	if myDID == "" && theirDID == "" {
+		err = setServiceDecorator(msg, state.Invitation)
+		if err != nil {
+			return fmt.Errorf("failed to set service decorator : %w", err)
+		}
+
+		setParentThreadID(msg, invID)
+	}
+
 	state.Done = true
 
 	// Save state as Done before dispatching message because the out-of-band protocol
@@ -722,6 +793,86 @@ func (s *Service) dispatchInvitationAttachment(invID, myDID, theirDID string) er
 	return nil
 }
 
+// setServiceDecorator sets the ~service decorator of the request attached to the invitation without handshake
+// if the request does not carry it already. The inline service block of the invitation is used.
+func setServiceDecorator(msg service.DIDCommMsgMap, inv *Invitation) error {
+	if svc, err := msg.ServiceDecorator(); err != nil || svc != nil {
+		return err
+	}
+
+	target, err := chooseTarget(inv.Services)
+	if err != nil {
+		return fmt.Errorf("failed to choose a target : %w", err)
+	}
+
+	svc, ok := target.(*did.Service)
+	if !ok {
+		return fmt.Errorf("the request without handshake requires an inline service block, got %v", target)
+	}
+
+	msg.SetServiceDecorator(&decorator.Service{
+		RecipientKeys:   svc.RecipientKeys,
+		RoutingKeys:     svc.RoutingKeys,
+		ServiceEndpoint: svc.ServiceEndpoint,
+	})
+
+	return nil
+}
+
+// reusedInvitationID returns the ID of the invitation referenced by the handshake-reuse message,
+// the parent thread ID or the thread ID for the messages replying to the invitation.
+func reusedInvitationID(msg service.DIDCommMsg) string {
+	if pthID := msg.ParentThreadID(); pthID != "" {
+		return pthID
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return ""
+	}
+
+	return thID
+}
+
+// setParentThreadID references the invitation without handshake from the attached request,
+// the replies keep the reference and the inviter counts them as a use of the invitation.
+func setParentThreadID(msg service.DIDCommMsgMap, invitationID string) {
+	if msg.ParentThreadID() != "" {
+		return
+	}
+
+	if msg.IsDIDCommV2() {
+		msg[jsonParentThreadID] = invitationID
+
+		return
+	}
+
+	thread, ok := msg[jsonThread].(map[string]interface{})
+	if !ok {
+		thread = map[string]interface{}{}
+	}
+
+	thread[jsonParentThreadID] = invitationID
+	msg[jsonThread] = thread
+}
+
+// ValidateInbound counts the message received without a connection in reply to the request attached to
+// an invitation without handshake as a use of the invitation, once per thread.
+// The message is rejected if the invitation created by the agent is no longer active.
+func (s *Service) ValidateInbound(msg service.DIDCommMsgMap, ctx service.DIDCommContext) error {
+	invitationID := msg.ParentThreadID()
+	if invitationID == "" || !service.IsConnectionless(msg, ctx.TheirDID()) {
+		return nil
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("threadID: %w", err)
+	}
+
+	return s.useInvitation(invitationID, thID)
+}
+
 func (s *Service) save(state *attachmentHandlingState) error {
 	bytes, err := json.Marshal(state)
 	if err != nil {
@@ -773,7 +924,7 @@ func extractDIDCommMsgBytes(a *decorator.Attachment) ([]byte, error) {
 	return bytes, nil
 }
 
-func (s *Service) extractDIDCommMsg(state *attachmentHandlingState) (service.DIDCommMsg, error) {
+func (s *Service) extractDIDCommMsg(state *attachmentHandlingState) (service.DIDCommMsgMap, error) {
 	req, err := s.chooseAttachmentFunc(state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to select an attachment: %w", err)
@@ -793,7 +944,7 @@ func (s *Service) extractDIDCommMsg(state *attachmentHandlingState) (service.DID
 }
 
 func validateInvitationAcceptance(msg service.DIDCommMsg, opts Options) error {
-	if msg.Type() != InvitationMsgType {
+	if !isInvitation(msg.Type()) {
 		return nil
 	}
 
@@ -882,6 +1033,11 @@ func chooseTarget(svcs []interface{}) (interface{}, error) {
 	return nil, fmt.Errorf("invalid or no targets to choose from")
 }
 
+// isInvitation returns true if the message type is the invitation of the Out-of-Band 1.0 or 1.1 protocol.
+func isInvitation(msgType string) bool {
+	return msgType == InvitationMsgType || msgType == InvitationMsgTypeV11
+}
+
 func isTheEnd(s state) bool {
 	_, ok := s.(*stateDone)
 
@@ -104,6 +104,7 @@ func TestAccept(t *testing.T) {
 		s, err := New(testProvider())
 		require.NoError(t, err)
 		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/invitation"))
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.1/invitation"))
 	})
 	t.Run("rejects unsupported messages", func(t *testing.T) {
 		s, err := New(testProvider())
@@ -778,6 +779,161 @@ func TestHandleDIDEvent(t *testing.T) {
 	})
 }
 
+func TestDispatchInvitationAttachmentWithoutHandshake(t *testing.T) {
+	newConnectionlessInvitation := func(svc interface{}) *Invitation {
+		inv := newInvitation()
+		inv.Protocols = nil
+		inv.Services = []interface{}{svc}
+
+		return inv
+	}
+
+	inlineService := &did.Service{
+		ID:              uuid.New().String(),
+		Type:            "did-communication",
+		RecipientKeys:   []string{"did:key:recipient"},
+		RoutingKeys:     []string{"did:key:routing"},
+		ServiceEndpoint: "https://example.com",
+	}
+
+	t.Run("sets the service decorator from the inline service block", func(t *testing.T) {
+		var dispatched service.DIDCommMsg
+
+		provider := testProvider()
+		provider.InboundDIDCommMsgHandlerFunc = func() service.InboundHandler {
+			return &inboundMsgHandler{handleFunc: func(msg service.DIDCommMsg, ctx service.DIDCommContext) (string, error) {
+				require.Empty(t, ctx.MyDID())
+				require.Empty(t, ctx.TheirDID())
+				dispatched = msg
+
+				return "", nil
+			}}
+		}
+
+		inv := newConnectionlessInvitation(inlineService)
+		s := newAutoService(t, provider, withState(t, &attachmentHandlingState{ID: inv.ID, Invitation: inv}))
+
+		require.NoError(t, s.dispatchInvitationAttachment(inv.ID, "", ""))
+
+		msg, ok := dispatched.(service.DIDCommMsgMap)
+		require.True(t, ok)
+
+		svc, err := msg.ServiceDecorator()
+		require.NoError(t, err)
+		require.Equal(t, &decorator.Service{
+			RecipientKeys:   inlineService.RecipientKeys,
+			RoutingKeys:     inlineService.RoutingKeys,
+			ServiceEndpoint: inlineService.ServiceEndpoint,
+		}, svc)
+
+		// the replies reference the invitation
+		require.Equal(t, inv.ID, msg.ParentThreadID())
+	})
+
+	t.Run("keeps the service decorator of the request", func(t *testing.T) {
+		var dispatched service.DIDCommMsg
+
+		provider := testProvider()
+		provider.InboundDIDCommMsgHandlerFunc = func() service.InboundHandler {
+			return &inboundMsgHandler{handleFunc: func(msg service.DIDCommMsg, _ service.DIDCommContext) (string, error) {
+				dispatched = msg
+
+				return "", nil
+			}}
+		}
+
+		expected := &decorator.Service{
+			RecipientKeys:   []string{"did:key:request"},
+			ServiceEndpoint: "https://request.example.com",
+		}
+
+		request := service.DIDCommMsgMap{"@id": "123", "@type": "test-type"}
+		request.SetServiceDecorator(expected)
+
+		inv := newConnectionlessInvitation(inlineService)
+		inv.Requests[0].Data.JSON = request
+
+		s := newAutoService(t, provider, withState(t, &attachmentHandlingState{ID: inv.ID, Invitation: inv}))
+
+		require.NoError(t, s.dispatchInvitationAttachment(inv.ID, "", ""))
+
+		svc, err := dispatched.(service.DIDCommMsgMap).ServiceDecorator()
+		require.NoError(t, err)
+		require.Equal(t, expected, svc)
+	})
+
+	t.Run("error if the invitation has no inline service block", func(t *testing.T) {
+		inv := newConnectionlessInvitation(theirDID)
+		s := newAutoService(t, testProvider(), withState(t, &attachmentHandlingState{ID: inv.ID, Invitation: inv}))
+
+		err := s.dispatchInvitationAttachment(inv.ID, "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the request without handshake requires an inline service block")
+	})
+}
+
+func TestValidateInbound(t *testing.T) {
+	newReply := func(invitationID string) service.DIDCommMsgMap {
+		msg := service.DIDCommMsgMap{
+			"@id":     uuid.New().String(),
+			"@type":   "test-type",
+			"~thread": map[string]interface{}{"thid": "request", "pthid": invitationID},
+		}
+		msg.SetServiceDecorator(&decorator.Service{
+			RecipientKeys:   []string{"did:key:invitee"},
+			ServiceEndpoint: "https://invitee.example.com",
+		})
+
+		return msg
+	}
+
+	t.Run("counts the connectionless replies as a use of the invitation once per thread", func(t *testing.T) {
+		s, _ := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithMaxUses(1)))
+
+		require.NoError(t, s.ValidateInbound(newReply(inv.ID), service.EmptyDIDCommContext()))
+		require.NoError(t, s.ValidateInbound(newReply(inv.ID), service.EmptyDIDCommContext()))
+
+		record, err := s.GetInvitationRecord(inv.ID)
+		require.NoError(t, err)
+		require.Equal(t, InvitationStateUsed, record.State)
+		require.Equal(t, 1, record.Uses)
+
+		reply := newReply(inv.ID)
+		reply["~thread"] = map[string]interface{}{"thid": "another request", "pthid": inv.ID}
+
+		err = s.ValidateInbound(reply, service.EmptyDIDCommContext())
+		require.ErrorIs(t, err, ErrInvitationNotActive)
+	})
+
+	t.Run("ignores the messages exchanged over a connection", func(t *testing.T) {
+		s, _ := newInvitationsService(t)
+
+		inv := newInvitation()
+		require.NoError(t, s.SaveInvitation(inv, WithMaxUses(1)))
+		require.NoError(t, s.RevokeInvitation(inv.ID))
+
+		require.NoError(t, s.ValidateInbound(newReply(inv.ID), service.NewDIDCommContext(myDID, theirDID, nil)))
+		require.NoError(t, s.ValidateInbound(newReply(""), service.EmptyDIDCommContext()))
+	})
+}
+
+func TestHandleInbound_HandshakeReuse(t *testing.T) {
+	s, _ := newInvitationsService(t)
+
+	inv := newInvitation()
+	require.NoError(t, s.SaveInvitation(inv))
+	require.NoError(t, s.RevokeInvitation(inv.ID))
+
+	msg := service.NewDIDCommMsgMap(&HandshakeReuse{ID: uuid.New().String(), Type: HandshakeReuseMsgType})
+	msg["~thread"] = map[string]interface{}{"thid": inv.ID}
+
+	_, err := s.HandleInbound(msg, service.NewDIDCommContext(myDID, theirDID, nil))
+	require.ErrorIs(t, err, ErrInvitationNotActive)
+}
+
 func TestListener(t *testing.T) {
 	t.Run("invokes handleReqFunc", func(t *testing.T) {
 		invoked := make(chan struct{})
@@ -832,6 +988,23 @@ func TestAcceptInvitation(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, expected, result)
 	})
+	t.Run("returns connectionID for out-of-band 1.1 invitation", func(t *testing.T) {
+		expected := "123456"
+		provider := testProvider()
+		provider.ServiceMap = map[string]interface{}{
+			didexchange.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(_ *didexchange.OOBInvitation, _ []string) (string, error) {
+					return expected, nil
+				},
+			},
+		}
+		s := newAutoService(t, provider)
+		inv := newInvitation()
+		inv.Type = InvitationMsgTypeV11
+		result, err := s.AcceptInvitation(inv, &userOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
 	t.Run("wraps error from didexchange service", func(t *testing.T) {
 		expected := errors.New("test")
 		provider := testProvider()
@@ -852,11 +1025,21 @@ func TestAcceptInvitation(t *testing.T) {
 func TestSaveInvitation(t *testing.T) {
 	t.Run("saves invitation", func(t *testing.T) {
 		savedInStore := false
+		savedRecord := false
 		savedInDidSvc := false
 		expected := newInvitation()
 		provider := testProvider()
 		provider.StoreProvider = mockstore.NewCustomMockStoreProvider(&stubStore{
 			putFunc: func(k string, v []byte) error {
+				if k == fmt.Sprintf(invitationRecordKey, expected.ID) {
+					savedRecord = true
+					record := &InvitationRecord{}
+					require.NoError(t, json.Unmarshal(v, record))
+					require.Equal(t, expected, record.Invitation)
+					require.Equal(t, InvitationStateActive, record.State)
+					return nil
+				}
+
 				savedInStore = true
 				result := &Invitation{}
 				err := json.Unmarshal(v, result)
@@ -880,6 +1063,7 @@ func TestSaveInvitation(t *testing.T) {
 		err := s.SaveInvitation(expected)
 		require.NoError(t, err)
 		require.True(t, savedInStore)
+		require.True(t, savedRecord)
 		require.True(t, savedInDidSvc)
 	})
 	t.Run("wraps error from store", func(t *testing.T) {
@@ -41,6 +41,7 @@ type dependencies struct {
 	didSvc                didExchSvc
 	saveAttchStateFunc    func(*attachmentHandlingState) error
 	dispatchAttachmntFunc func(string, string, string) error
+	useInvitationFunc     func(string, string) error
 }
 
 // The outofband protocol's state.
@@ -68,7 +69,7 @@ func stateFromName(n string) (state, error) {
 
 func requiresApproval(msg service.DIDCommMsg) bool {
 	switch msg.Type() {
-	case InvitationMsgType, HandshakeReuseMsgType:
+	case InvitationMsgType, InvitationMsgTypeV11, HandshakeReuseMsgType:
 		return true
 	}
 
@@ -135,6 +136,13 @@ func (s *stateAwaitResponse) handleHandshakeReuse(ctx *context, deps *dependenci
 		)
 	}
 
+	// the reuse is accepted, it counts as a use of the invitation
+	invitationID := reusedInvitationID(ctx.Msg)
+
+	if err = deps.useInvitationFunc(invitationID, connID); err != nil {
+		return nil, nil, true, fmt.Errorf("failed to use invitation %s: %w", invitationID, err)
+	}
+
 	return &stateDone{}, func(m service.Messenger) error {
 		return m.ReplyToMsg(
 			ctx.Msg,
@@ -180,6 +188,10 @@ func (s *statePrepareResponse) Execute(ctx *context, deps *dependencies) (state,
 		return s.connectionReuse(ctx, deps)
 	}
 
+	if len(ctx.Invitation.Protocols) == 0 && len(ctx.Invitation.Requests) > 0 {
+		return s.withoutHandshake(ctx, deps)
+	}
+
 	logger.Debugf("creating new connection using context: %+v", ctx)
 
 	connID, err := deps.didSvc.RespondTo(ctx.DIDExchangeInv, ctx.RouterConnections)
@@ -268,6 +280,24 @@ func (s *statePrepareResponse) connectionReuse(ctx *context, deps *dependencies)
 	}, true, nil
 }
 
+// withoutHandshake handles the invitation without handshake protocols, the attached request is
+// exchanged without a connection using the ~service decorator.
+func (s *statePrepareResponse) withoutHandshake(ctx *context, deps *dependencies) (state, finisher, bool, error) {
+	logger.Debugf("handling invitation without handshake using context: %+v", ctx)
+
+	err := deps.saveAttchStateFunc(&attachmentHandlingState{
+		ID:         ctx.Invitation.ID,
+		Invitation: ctx.Invitation,
+	})
+	if err != nil {
+		return nil, nil, true, fmt.Errorf("failed to save attachment handling state: %w", err)
+	}
+
+	return &stateDone{}, func(service.Messenger) error {
+		return deps.dispatchAttachmntFunc(ctx.Invitation.ID, "", "")
+	}, false, nil
+}
+
 type stateDone struct{}
 
 func (s *stateDone) Name() string {
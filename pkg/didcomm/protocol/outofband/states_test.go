@@ -114,6 +114,40 @@ func TestStateAwaitResponse_Execute(t *testing.T) {
 			require.Error(t, err)
 			require.Contains(t, err.Error(), "unexpected state for connection")
 		})
+
+		t.Run("counts a use of the invitation", func(t *testing.T) {
+			pthid := uuid.New().String()
+			msg := service.NewDIDCommMsgMap(&HandshakeReuse{Type: HandshakeReuseMsgType})
+			msg["~thread"] = map[string]interface{}{"pthid": pthid}
+			ctx := &context{Inbound: true, Action: Action{Msg: msg}}
+
+			var used []string
+
+			deps := &dependencies{
+				connections: &mockConnRecorder{
+					getConnIDByDIDsVal: "connection",
+					getConnRecordVal:   &connection.Record{State: connectionRecordCompletedState},
+				},
+				useInvitationFunc: func(invitationID, useID string) error {
+					used = append(used, invitationID, useID)
+
+					return nil
+				},
+			}
+			s := &stateAwaitResponse{}
+
+			next, _, _, err := s.Execute(ctx, deps)
+			require.NoError(t, err)
+			require.IsType(t, &stateDone{}, next)
+			require.Equal(t, []string{pthid, "connection"}, used)
+
+			deps.useInvitationFunc = func(string, string) error {
+				return ErrInvitationNotActive
+			}
+
+			_, _, _, err = s.Execute(ctx, deps)
+			require.ErrorIs(t, err, ErrInvitationNotActive)
+		})
 	})
 }
 
@@ -122,6 +156,7 @@ func TestStatePrepareResponse_Execute(t *testing.T) {
 		t.Run("error while saving attachment handling state", func(t *testing.T) {
 			expected := errors.New("test")
 			ctx := &context{Invitation: &Invitation{
+				Protocols: []string{didexchange.PIURI},
 				Requests: []*decorator.Attachment{{
 					ID: uuid.New().String(),
 					Data: decorator.AttachmentData{
@@ -143,6 +178,72 @@ func TestStatePrepareResponse_Execute(t *testing.T) {
 		})
 	})
 
+	t.Run("without handshake", func(t *testing.T) {
+		t.Run("dispatches the attachment without a connection", func(t *testing.T) {
+			var saved *attachmentHandlingState
+
+			dispatched := false
+			inv := &Invitation{
+				ID: uuid.New().String(),
+				Requests: []*decorator.Attachment{{
+					ID: uuid.New().String(),
+					Data: decorator.AttachmentData{
+						JSON: map[string]interface{}{},
+					},
+				}},
+			}
+			deps := &dependencies{
+				saveAttchStateFunc: func(state *attachmentHandlingState) error {
+					saved = state
+
+					return nil
+				},
+				dispatchAttachmntFunc: func(invID, myDID, theirDID string) error {
+					require.Equal(t, inv.ID, invID)
+					require.Empty(t, myDID)
+					require.Empty(t, theirDID)
+					dispatched = true
+
+					return nil
+				},
+			}
+			s := &statePrepareResponse{}
+			ctx := &context{Invitation: inv}
+
+			next, finish, halt, err := s.Execute(ctx, deps)
+			require.NoError(t, err)
+			require.IsType(t, &stateDone{}, next)
+			require.False(t, halt)
+			require.Equal(t, inv, saved.Invitation)
+			require.Empty(t, saved.ConnectionID)
+
+			require.NoError(t, finish(&mockservice.MockMessenger{}))
+			require.True(t, dispatched)
+			require.Empty(t, ctx.ConnectionID)
+		})
+
+		t.Run("error while saving attachment handling state", func(t *testing.T) {
+			expected := errors.New("test")
+			ctx := &context{Invitation: &Invitation{
+				Requests: []*decorator.Attachment{{
+					ID: uuid.New().String(),
+					Data: decorator.AttachmentData{
+						JSON: map[string]interface{}{},
+					},
+				}},
+			}}
+			deps := &dependencies{
+				saveAttchStateFunc: func(*attachmentHandlingState) error {
+					return expected
+				},
+			}
+			s := &statePrepareResponse{}
+
+			_, _, _, err := s.Execute(ctx, deps)
+			require.ErrorIs(t, err, expected)
+		})
+	})
+
 	t.Run("connection reuse", func(t *testing.T) {
 		t.Run("advances to next state and sends handshake-reuse", func(t *testing.T) {
 			savedAttachmentState := false
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// InvitationURLParam is the query parameter of the invitation URL carrying the base64url encoded invitation.
+	InvitationURLParam = "oob"
+	// ShortURLPath is the path the shortened invitations are served at.
+	ShortURLPath = "/ssi"
+	// ShortURLParam is the query parameter of the shortened invitation URL carrying the invitation ID.
+	ShortURLParam = "id"
+)
+
+// EncodeInvitationURL encodes the invitation into the URL as described in the RFC:
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#standard-out-of-band-message-encoding
+func EncodeInvitationURL(baseURL string, inv *Invitation) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parse base url: %w", err)
+	}
+
+	invBytes, err := json.Marshal(inv)
+	if err != nil {
+		return "", fmt.Errorf("marshal invitation: %w", err)
+	}
+
+	query := u.Query()
+	query.Set(InvitationURLParam, base64.URLEncoding.EncodeToString(invBytes))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// DecodeInvitationURL decodes the invitation from the URL created by EncodeInvitationURL.
+func DecodeInvitationURL(invitationURL string) (*Invitation, error) {
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation url: %w", err)
+	}
+
+	encoded := u.Query().Get(InvitationURLParam)
+	if encoded == "" {
+		return nil, errors.New("invitation url has no oob parameter")
+	}
+
+	// padding is optional
+	invBytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode invitation: %w", err)
+	}
+
+	inv := &Invitation{}
+
+	if err = json.Unmarshal(invBytes, inv); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	return inv, nil
+}
+
+// ShortInvitationURL returns the URL the invitation is served at by the agent, see ShortURLPath.
+func ShortInvitationURL(baseURL, invitationID string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + ShortURLPath)
+	if err != nil {
+		return "", fmt.Errorf("parse base url: %w", err)
+	}
+
+	u.RawQuery = url.Values{ShortURLParam: []string{invitationID}}.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationURL(t *testing.T) {
+	t.Run("encode and decode", func(t *testing.T) {
+		expected := newInvitation()
+
+		invURL, err := EncodeInvitationURL("https://example.com/path?lang=en", expected)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(invURL, "https://example.com/path?"))
+
+		u, err := url.Parse(invURL)
+		require.NoError(t, err)
+		require.Equal(t, "en", u.Query().Get("lang"))
+
+		result, err := DecodeInvitationURL(invURL)
+		require.NoError(t, err)
+		require.Equal(t, expected.ID, result.ID)
+		require.Equal(t, expected.Label, result.Label)
+		require.Equal(t, expected.Services, result.Services)
+	})
+
+	t.Run("decode unpadded invitation", func(t *testing.T) {
+		invBytes, err := json.Marshal(newInvitation())
+		require.NoError(t, err)
+
+		result, err := DecodeInvitationURL("https://example.com?oob=" + base64.RawURLEncoding.EncodeToString(invBytes))
+		require.NoError(t, err)
+		require.Equal(t, InvitationMsgType, result.Type)
+	})
+
+	t.Run("invalid base url", func(t *testing.T) {
+		_, err := EncodeInvitationURL(":", newInvitation())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base url")
+	})
+
+	t.Run("decode errors", func(t *testing.T) {
+		_, err := DecodeInvitationURL(":")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse invitation url")
+
+		_, err = DecodeInvitationURL("https://example.com?c_i=abc")
+		require.EqualError(t, err, "invitation url has no oob parameter")
+
+		_, err = DecodeInvitationURL("https://example.com?oob=!!")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode invitation")
+
+		_, err = DecodeInvitationURL("https://example.com?oob=" + base64.URLEncoding.EncodeToString([]byte("[]")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+	})
+}
+
+func TestShortInvitationURL(t *testing.T) {
+	shortURL, err := ShortInvitationURL("https://example.com/", "123")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/ssi?id=123", shortURL)
+
+	_, err = ShortInvitationURL(":", "123")
+	require.Error(t, err)
+}
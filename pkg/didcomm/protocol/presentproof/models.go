@@ -6,13 +6,18 @@ SPDX-License-Identifier: Apache-2.0
 
 package presentproof
 
-import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
 
 // ProposePresentation is an optional message sent by the prover to the verifier to initiate a proof presentation
 // process, or in response to a request-presentation message when the prover wants to propose
 // using a different presentation format or request.
 type ProposePresentation struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
 	// Comment is a field that provides some human readable information about the proposed presentation.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300]
 	Comment string `json:"comment,omitempty"`
@@ -27,6 +32,8 @@ type ProposePresentation struct {
 // RequestPresentation describes values that need to be revealed and predicates that need to be fulfilled.
 type RequestPresentation struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
 	// Comment is a field that provides some human readable information about the proposed presentation.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300]
 	Comment string `json:"comment,omitempty"`
@@ -44,6 +51,8 @@ type RequestPresentation struct {
 // TODO: Add ~please_ack decorator support for the protocol [Issue #2047].
 type Presentation struct {
 	Type string `json:"@type,omitempty"`
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
 	// Comment is a field that provides some human readable information about the proposed presentation.
 	// TODO: Should follow DIDComm conventions for l10n. [Issue #1300].
 	Comment string `json:"comment,omitempty"`
@@ -59,3 +68,235 @@ type Format struct {
 	AttachID string `json:"attach_id,omitempty"`
 	Format   string `json:"format,omitempty"`
 }
+
+// ProposePresentationV3 is the present-proof 3.0 (DIDComm V2) version of the ProposePresentation message.
+type ProposePresentationV3 struct {
+	ID   string                    `json:"id,omitempty"`
+	Type string                    `json:"type,omitempty"`
+	Body ProposePresentationV3Body `json:"body,omitempty"`
+	// Attachments is an array of attachments that further define the presentation request being proposed.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// ProposePresentationV3Body represents the body of the ProposePresentationV3 message.
+type ProposePresentationV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is a field that provides some human readable information about the proposed presentation.
+	Comment string `json:"comment,omitempty"`
+}
+
+// RequestPresentationV3 is the present-proof 3.0 (DIDComm V2) version of the RequestPresentation message.
+type RequestPresentationV3 struct {
+	ID   string                    `json:"id,omitempty"`
+	Type string                    `json:"type,omitempty"`
+	Body RequestPresentationV3Body `json:"body,omitempty"`
+	// Attachments is an array of attachments containing the acceptable verifiable presentation requests.
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// RequestPresentationV3Body represents the body of the RequestPresentationV3 message.
+type RequestPresentationV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is a field that provides some human readable information about the requested presentation.
+	Comment string `json:"comment,omitempty"`
+	// WillConfirm is a field that defaults to "false" to indicate that the verifier will or will not
+	// send a post-presentation confirmation ack message.
+	WillConfirm bool `json:"will_confirm,omitempty"`
+}
+
+// PresentationV3 is the present-proof 3.0 (DIDComm V2) version of the Presentation message.
+type PresentationV3 struct {
+	ID   string             `json:"id,omitempty"`
+	Type string             `json:"type,omitempty"`
+	Body PresentationV3Body `json:"body,omitempty"`
+	// Attachments is an array of attachments containing the presentation in the requested format(s).
+	Attachments []decorator.AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// PresentationV3Body represents the body of the PresentationV3 message.
+type PresentationV3Body struct {
+	// GoalCode is an optional goal code to indicate the goal of the message sender.
+	GoalCode string `json:"goal_code,omitempty"`
+	// Comment is a field that provides some human readable information about the presentation.
+	Comment string `json:"comment,omitempty"`
+}
+
+// AsV3 converts the message to the present-proof 3.0 (DIDComm V2) format.
+func (p *ProposePresentation) AsV3() *ProposePresentationV3 {
+	return &ProposePresentationV3{
+		Type:        ProposePresentationMsgTypeV3,
+		Body:        ProposePresentationV3Body{GoalCode: p.GoalCode, Comment: p.Comment},
+		Attachments: attachmentsAsV3(p.Formats, p.ProposalsAttach),
+	}
+}
+
+// AsV2 converts the message to the present-proof 2.0 format.
+func (p *ProposePresentationV3) AsV2() *ProposePresentation {
+	formats, attachments := attachmentsAsV2(p.Attachments)
+
+	return &ProposePresentation{
+		Type:            ProposePresentationMsgType,
+		GoalCode:        p.Body.GoalCode,
+		Comment:         p.Body.Comment,
+		Formats:         formats,
+		ProposalsAttach: attachments,
+	}
+}
+
+// AsV3 converts the message to the present-proof 3.0 (DIDComm V2) format.
+func (r *RequestPresentation) AsV3() *RequestPresentationV3 {
+	return &RequestPresentationV3{
+		Type: RequestPresentationMsgTypeV3,
+		Body: RequestPresentationV3Body{
+			GoalCode:    r.GoalCode,
+			Comment:     r.Comment,
+			WillConfirm: r.WillConfirm,
+		},
+		Attachments: attachmentsAsV3(r.Formats, r.RequestPresentationsAttach),
+	}
+}
+
+// AsV2 converts the message to the present-proof 2.0 format.
+func (r *RequestPresentationV3) AsV2() *RequestPresentation {
+	formats, attachments := attachmentsAsV2(r.Attachments)
+
+	return &RequestPresentation{
+		Type:                       RequestPresentationMsgType,
+		GoalCode:                   r.Body.GoalCode,
+		Comment:                    r.Body.Comment,
+		WillConfirm:                r.Body.WillConfirm,
+		Formats:                    formats,
+		RequestPresentationsAttach: attachments,
+	}
+}
+
+// AsV3 converts the message to the present-proof 3.0 (DIDComm V2) format.
+func (p *Presentation) AsV3() *PresentationV3 {
+	return &PresentationV3{
+		Type:        PresentationMsgTypeV3,
+		Body:        PresentationV3Body{GoalCode: p.GoalCode, Comment: p.Comment},
+		Attachments: attachmentsAsV3(p.Formats, p.PresentationsAttach),
+	}
+}
+
+// AsV2 converts the message to the present-proof 2.0 format.
+func (p *PresentationV3) AsV2() *Presentation {
+	formats, attachments := attachmentsAsV2(p.Attachments)
+
+	return &Presentation{
+		Type:                PresentationMsgType,
+		GoalCode:            p.Body.GoalCode,
+		Comment:             p.Body.Comment,
+		Formats:             formats,
+		PresentationsAttach: attachments,
+	}
+}
+
+// DecodeProposePresentation decodes the propose-presentation message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeProposePresentation(msg service.DIDCommMsg) (*ProposePresentation, error) {
+	if isV3(msg) {
+		v3 := &ProposePresentationV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &ProposePresentation{}
+
+	return v2, msg.Decode(v2)
+}
+
+// DecodeRequestPresentation decodes the request-presentation message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodeRequestPresentation(msg service.DIDCommMsg) (*RequestPresentation, error) {
+	if isV3(msg) {
+		v3 := &RequestPresentationV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &RequestPresentation{}
+
+	return v2, msg.Decode(v2)
+}
+
+// DecodePresentation decodes the presentation message of both 2.0 and 3.0 versions.
+// The 3.0 message is converted to the 2.0 format.
+func DecodePresentation(msg service.DIDCommMsg) (*Presentation, error) {
+	if isV3(msg) {
+		v3 := &PresentationV3{}
+		if err := msg.Decode(v3); err != nil {
+			return nil, err
+		}
+
+		return v3.AsV2(), nil
+	}
+
+	v2 := &Presentation{}
+
+	return v2, msg.Decode(v2)
+}
+
+// attachmentsAsV3 merges the formats into the DIDComm V2 attachments (format is a part of the attachment).
+func attachmentsAsV3(formats []Format, attachments []decorator.Attachment) []decorator.AttachmentV2 {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	attachFormats := make(map[string]string, len(formats))
+	for _, format := range formats {
+		attachFormats[format.AttachID] = format.Format
+	}
+
+	result := make([]decorator.AttachmentV2, len(attachments))
+	for i, a := range attachments {
+		result[i] = decorator.AttachmentV2{
+			ID:          a.ID,
+			Description: a.Description,
+			FileName:    a.FileName,
+			MediaType:   a.MimeType,
+			Format:      attachFormats[a.ID],
+			LastModTime: a.LastModTime,
+			ByteCount:   a.ByteCount,
+			Data:        a.Data,
+		}
+	}
+
+	return result
+}
+
+// attachmentsAsV2 splits the DIDComm V2 attachments into the formats and attachments.
+func attachmentsAsV2(attachments []decorator.AttachmentV2) ([]Format, []decorator.Attachment) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	var formats []Format
+
+	result := make([]decorator.Attachment, len(attachments))
+	for i, a := range attachments {
+		result[i] = decorator.Attachment{
+			ID:          a.ID,
+			Description: a.Description,
+			FileName:    a.FileName,
+			MimeType:    a.MediaType,
+			LastModTime: a.LastModTime,
+			ByteCount:   a.ByteCount,
+			Data:        a.Data,
+		}
+
+		if a.Format != "" {
+			formats = append(formats, Format{AttachID: a.ID, Format: a.Format})
+		}
+	}
+
+	return formats, result
+}
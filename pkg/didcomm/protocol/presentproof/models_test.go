@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+func TestMessagesV3(t *testing.T) {
+	formats := []Format{{AttachID: "ID1", Format: "format"}}
+	attachments := []decorator.Attachment{
+		{ID: "ID1", MimeType: "application/json", Data: decorator.AttachmentData{Base64: "ZGF0YQ=="}},
+		{ID: "ID2"},
+	}
+
+	t.Run("Propose presentation", func(t *testing.T) {
+		propose := &ProposePresentation{
+			Type:            ProposePresentationMsgType,
+			GoalCode:        "goal",
+			Comment:         "comment",
+			Formats:         formats,
+			ProposalsAttach: attachments,
+		}
+
+		v3 := propose.AsV3()
+		require.Equal(t, ProposePresentationMsgTypeV3, v3.Type)
+		require.Equal(t, "goal", v3.Body.GoalCode)
+		require.Equal(t, "format", v3.Attachments[0].Format)
+		require.Equal(t, "application/json", v3.Attachments[0].MediaType)
+
+		decoded, err := DecodeProposePresentation(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, propose, decoded)
+	})
+
+	t.Run("Request presentation", func(t *testing.T) {
+		request := &RequestPresentation{
+			Type:                       RequestPresentationMsgType,
+			WillConfirm:                true,
+			Formats:                    formats,
+			RequestPresentationsAttach: attachments,
+		}
+
+		v3 := request.AsV3()
+		require.Equal(t, RequestPresentationMsgTypeV3, v3.Type)
+		require.True(t, v3.Body.WillConfirm)
+
+		decoded, err := DecodeRequestPresentation(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, request, decoded)
+
+		decoded, err = DecodeRequestPresentation(service.NewDIDCommMsgMap(request))
+		require.NoError(t, err)
+		require.Equal(t, request, decoded)
+	})
+
+	t.Run("Presentation", func(t *testing.T) {
+		presentation := &Presentation{
+			Type:                PresentationMsgType,
+			Comment:             "comment",
+			Formats:             formats,
+			PresentationsAttach: attachments,
+		}
+
+		v3 := presentation.AsV3()
+		require.Equal(t, PresentationMsgTypeV3, v3.Type)
+
+		decoded, err := DecodePresentation(service.NewDIDCommMsgMap(v3))
+		require.NoError(t, err)
+		require.Equal(t, presentation, decoded)
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		_, err := DecodePresentation(service.DIDCommMsgMap{"type": PresentationMsgTypeV3, "body": "invalid"})
+		require.Error(t, err)
+	})
+}
@@ -10,11 +10,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
@@ -36,6 +38,19 @@ const (
 	ProblemReportMsgType = Spec + "problem-report"
 	// PresentationPreviewMsgType defines the protocol presentation-preview inner object type.
 	PresentationPreviewMsgType = Spec + "presentation-preview"
+
+	// SpecV3 defines the protocol spec for DIDComm V2 agents.
+	SpecV3 = "https://didcomm.org/present-proof/3.0/"
+	// ProposePresentationMsgTypeV3 defines the protocol propose-presentation message type (version 3.0).
+	ProposePresentationMsgTypeV3 = SpecV3 + "propose-presentation"
+	// RequestPresentationMsgTypeV3 defines the protocol request-presentation message type (version 3.0).
+	RequestPresentationMsgTypeV3 = SpecV3 + "request-presentation"
+	// PresentationMsgTypeV3 defines the protocol presentation message type (version 3.0).
+	PresentationMsgTypeV3 = SpecV3 + "presentation"
+	// AckMsgTypeV3 defines the protocol ack message type (version 3.0).
+	AckMsgTypeV3 = SpecV3 + "ack"
+	// ProblemReportMsgTypeV3 defines the protocol problem-report message type (version 3.0).
+	ProblemReportMsgTypeV3 = SpecV3 + "problem-report"
 )
 
 const (
@@ -50,6 +65,7 @@ var (
 		return nil
 	})
 	errProtocolStopped = errors.New("protocol was stopped")
+	errStateTimeout    = errors.New("state timeout")
 )
 
 // customError is a wrapper to determine custom error against internal error.
@@ -60,6 +76,7 @@ type transitionalPayload struct {
 	Action
 	StateName   string
 	AckRequired bool
+	CreatedAt   time.Time
 }
 
 // metaData type to store data for internal usage.
@@ -185,6 +202,7 @@ type Service struct {
 	callbacks  chan *metaData
 	messenger  service.Messenger
 	middleware Handler
+	threads    *threads.Tracker
 }
 
 // New returns the presentproof service.
@@ -194,7 +212,9 @@ func New(p Provider) (*Service, error) {
 		return nil, err
 	}
 
-	err = p.StorageProvider().SetStoreConfig(Name, storage.StoreConfiguration{TagNames: []string{transitionalPayloadKey}})
+	err = p.StorageProvider().SetStoreConfig(Name, storage.StoreConfiguration{
+		TagNames: []string{transitionalPayloadKey, internalDataKey, threads.CallbackKey},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store configuration: %w", err)
 	}
@@ -206,9 +226,25 @@ func New(p Provider) (*Service, error) {
 		middleware: initialHandler,
 	}
 
+	svc.threads = threads.New(store, internalDataKey, svc.abandonTimedOut, stateNameDone, stateNameAbandoned)
+
+	pending, err := svc.pendingCallbacks()
+	if err != nil {
+		return nil, fmt.Errorf("pending callbacks: %w", err)
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
+	// resumes the callbacks which were interrupted by the agent restart
+	if len(pending) > 0 {
+		go func() {
+			for _, md := range pending {
+				svc.callbacks <- md
+			}
+		}()
+	}
+
 	return svc, nil
 }
 
@@ -275,7 +311,7 @@ func (s *Service) getCurrentInternalDataAndPIID(msg service.DIDCommMsg) (string,
 	if errors.Is(err, service.ErrThreadIDNotFound) {
 		piID = uuid.New().String()
 
-		return piID, &internalData{StateName: stateNameStart}, msg.SetID(piID)
+		return piID, &internalData{Thread: Thread{PIID: piID, StateName: stateNameStart}}, msg.SetID(piID)
 	}
 
 	if err != nil {
@@ -311,6 +347,7 @@ func (s *Service) doHandle(msg service.DIDCommMsgMap) (*metaData, error) {
 		transitionalPayload: transitionalPayload{
 			StateName:   next.Name(),
 			AckRequired: data.AckRequired,
+			CreatedAt:   data.CreatedAt,
 			Action: Action{
 				Msg:  msg,
 				PIID: piID,
@@ -325,23 +362,31 @@ func (s *Service) doHandle(msg service.DIDCommMsgMap) (*metaData, error) {
 // startInternalListener listens to messages in go channel for callback messages from clients.
 func (s *Service) startInternalListener() {
 	for msg := range s.callbacks {
-		// if no error do handle
-		if msg.err == nil {
-			msg.err = s.handle(msg)
-		}
+		s.handleCallback(msg)
 
-		// no error - continue
-		if msg.err == nil {
-			continue
+		if err := s.deleteCallback(msg.PIID); err != nil {
+			logger.Errorf("delete callback: %s", err)
 		}
+	}
+}
 
-		logger.Errorf("failed to handle msgID=%s : %s", msg.Msg.ID(), msg.err)
+func (s *Service) handleCallback(msg *metaData) {
+	// if no error do handle
+	if msg.err == nil {
+		msg.err = s.handle(msg)
+	}
 
-		msg.state = &abandoned{Code: codeInternalError}
+	// no error - continue
+	if msg.err == nil {
+		return
+	}
 
-		if err := s.handle(msg); err != nil {
-			logger.Errorf("listener handle: %s", err)
-		}
+	logger.Errorf("failed to handle msgID=%s : %s", msg.Msg.ID(), msg.err)
+
+	msg.state = &abandoned{Code: abandonCode(msg.err)}
+
+	if err := s.handle(msg); err != nil {
+		logger.Errorf("listener handle: %s", err)
 	}
 }
 
@@ -364,8 +409,7 @@ func (s *Service) handle(md *metaData) error {
 		}
 
 		// WARN: md.ackRequired is being modified by requestSent state
-		data := &internalData{StateName: current.Name(), AckRequired: md.AckRequired}
-		if err := s.saveInternalData(md.PIID, data); err != nil {
+		if err := s.saveInternalData(md, current.Name()); err != nil {
 			return fmt.Errorf("failed to persist state %s: %w", current.Name(), err)
 		}
 
@@ -388,23 +432,48 @@ func getPIID(msg service.DIDCommMsg) (string, error) {
 }
 
 type internalData struct {
+	Thread
 	AckRequired bool
-	StateName   string
 }
 
-func (s *Service) saveInternalData(piID string, data *internalData) error {
+func (s *Service) saveInternalData(md *metaData, stateName string) error {
+	now := time.Now()
+
+	if md.CreatedAt.IsZero() {
+		md.CreatedAt = now
+	}
+
+	data := &internalData{
+		Thread: Thread{
+			PIID:      md.PIID,
+			StateName: stateName,
+			MyDID:     md.MyDID,
+			TheirDID:  md.TheirDID,
+			Msg:       md.Msg,
+			CreatedAt: md.CreatedAt,
+			UpdatedAt: now,
+		},
+		AckRequired: md.AckRequired,
+	}
+
 	src, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	return s.store.Put(internalDataKey+piID, src)
+	if err = s.store.Put(internalDataKey+md.PIID, src, storage.Tag{Name: internalDataKey}); err != nil {
+		return err
+	}
+
+	s.threads.Track(&data.Thread)
+
+	return nil
 }
 
 func (s *Service) currentInternalData(piID string) (*internalData, error) {
 	src, err := s.store.Get(internalDataKey + piID)
 	if errors.Is(err, storage.ErrDataNotFound) {
-		return &internalData{StateName: stateNameStart}, nil
+		return &internalData{Thread: Thread{PIID: piID, StateName: stateNameStart}}, nil
 	}
 
 	if err != nil {
@@ -449,23 +518,23 @@ func nextState(msg service.DIDCommMsgMap) (state, error) {
 	canReply := canReplyTo(msg)
 
 	switch msg.Type() {
-	case RequestPresentationMsgType:
+	case RequestPresentationMsgType, RequestPresentationMsgTypeV3:
 		if canReply {
 			return &requestReceived{}, nil
 		}
 
 		return &requestSent{}, nil
-	case ProposePresentationMsgType:
+	case ProposePresentationMsgType, ProposePresentationMsgTypeV3:
 		if canReply {
 			return &proposalReceived{}, nil
 		}
 
 		return &proposalSent{}, nil
-	case PresentationMsgType:
+	case PresentationMsgType, PresentationMsgTypeV3:
 		return &presentationReceived{}, nil
-	case ProblemReportMsgType:
+	case ProblemReportMsgType, ProblemReportMsgTypeV3:
 		return &abandoned{}, nil
-	case AckMsgType:
+	case AckMsgType, AckMsgTypeV3:
 		return &done{}, nil
 	default:
 		return nil, fmt.Errorf("unrecognized msgType: %s", msg.Type())
@@ -483,10 +552,14 @@ func (s *Service) saveTransitionalPayload(id string, data transitionalPayload) e
 
 // canTriggerActionEvents checks if the incoming message can trigger an action event.
 func canTriggerActionEvents(msg service.DIDCommMsg) bool {
-	return msg.Type() == PresentationMsgType ||
-		msg.Type() == ProposePresentationMsgType ||
-		msg.Type() == RequestPresentationMsgType ||
-		msg.Type() == ProblemReportMsgType
+	switch msg.Type() {
+	case PresentationMsgType, ProposePresentationMsgType, RequestPresentationMsgType, ProblemReportMsgType:
+		return true
+	case PresentationMsgTypeV3, ProposePresentationMsgTypeV3, RequestPresentationMsgTypeV3, ProblemReportMsgTypeV3:
+		return true
+	}
+
+	return false
 }
 
 func (s *Service) getTransitionalPayload(id string) (*transitionalPayload, error) {
@@ -603,6 +676,11 @@ func (s *Service) ActionStop(piID string, cErr error) error {
 }
 
 func (s *Service) processCallback(msg *metaData) {
+	// persists the callback data, that allows resuming it if the agent is restarted before it was handled.
+	if err := s.saveCallback(msg); err != nil {
+		logger.Errorf("save callback: %s", err)
+	}
+
 	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
 	// channel internally.
 	s.callbacks <- msg
@@ -682,6 +760,9 @@ func (s *Service) Accept(msgType string) bool {
 	case ProposePresentationMsgType, RequestPresentationMsgType,
 		PresentationMsgType, AckMsgType, ProblemReportMsgType:
 		return true
+	case ProposePresentationMsgTypeV3, RequestPresentationMsgTypeV3,
+		PresentationMsgTypeV3, AckMsgTypeV3, ProblemReportMsgTypeV3:
+		return true
 	}
 
 	return false
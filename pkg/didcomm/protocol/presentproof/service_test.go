@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/threads"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	presentproofMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/presentproof"
@@ -38,8 +40,11 @@ func TestNew(t *testing.T) {
 	defer ctrl.Finish()
 
 	t.Run("Success", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
+
 		storeProvider := storageMocks.NewMockProvider(ctrl)
-		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
 		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
 
 		provider := presentproofMocks.NewMockProvider(ctrl)
@@ -51,6 +56,25 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, svc)
 	})
 
+	t.Run("Error pending callbacks", func(t *testing.T) {
+		const errMsg = "error"
+
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Query(threads.CallbackKey).Return(nil, errors.New(errMsg))
+
+		storeProvider := storageMocks.NewMockProvider(ctrl)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
+
+		provider := presentproofMocks.NewMockProvider(ctrl)
+		provider.EXPECT().Messenger().Return(nil)
+		provider.EXPECT().StorageProvider().Return(storeProvider).Times(2)
+
+		svc, err := New(provider)
+		require.EqualError(t, err, "pending callbacks: failed to query the store: "+errMsg)
+		require.Nil(t, svc)
+	})
+
 	t.Run("Error open store", func(t *testing.T) {
 		const errMsg = "error"
 
@@ -71,8 +95,11 @@ func TestService_Use(t *testing.T) {
 	defer ctrl.Finish()
 
 	t.Run("Success (one function)", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
+
 		storeProvider := storageMocks.NewMockProvider(ctrl)
-		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil).Times(1)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).Times(1)
 		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
 
 		provider := presentproofMocks.NewMockProvider(ctrl)
@@ -118,8 +145,11 @@ func TestService_Use(t *testing.T) {
 	})
 
 	t.Run("Success (two function)", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
+
 		storeProvider := storageMocks.NewMockProvider(ctrl)
-		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil).Times(1)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).Times(1)
 		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
 
 		provider := presentproofMocks.NewMockProvider(ctrl)
@@ -148,8 +178,11 @@ func TestService_Use(t *testing.T) {
 	})
 
 	t.Run("Failed", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
+
 		storeProvider := storageMocks.NewMockProvider(ctrl)
-		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil).Times(1)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).Times(1)
 		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
 
 		provider := presentproofMocks.NewMockProvider(ctrl)
@@ -179,6 +212,7 @@ func TestService_ActionContinue(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	expectCallbacks(store)
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil).AnyTimes()
 	storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil).AnyTimes()
@@ -217,6 +251,7 @@ func TestService_ActionStop(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return(nil, errors.New(errMsg))
 
 		storeProvider := storageMocks.NewMockProvider(ctrl)
@@ -241,6 +276,7 @@ func TestService_ActionStop(t *testing.T) {
 		const errMsg = "error"
 
 		store := storageMocks.NewMockStore(ctrl)
+		expectCallbacks(store)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{}`), nil)
 		store.EXPECT().Delete(gomock.Any()).Return(errors.New(errMsg))
 
@@ -268,6 +304,7 @@ func TestService_HandleInbound(t *testing.T) {
 	const errMsg = "error"
 
 	store := storageMocks.NewMockStore(ctrl)
+	expectCallbacks(store)
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(Name).Return(store, nil).AnyTimes()
@@ -368,10 +405,8 @@ func TestService_HandleInbound(t *testing.T) {
 
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "abandoned"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "abandoned", false)
 
 			return nil
 		})
@@ -423,18 +458,14 @@ func TestService_HandleInbound(t *testing.T) {
 
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "request-received"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "request-received", false)
 
 			return nil
 		})
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "presentation-sent"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "presentation-sent", false)
 
 			return nil
 		})
@@ -469,6 +500,68 @@ func TestService_HandleInbound(t *testing.T) {
 		}
 	})
 
+	t.Run("Receive Invitation Presentation (continue with presentation) v3", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_, msg service.DIDCommMsgMap, _, _ string) error {
+				defer close(done)
+
+				r := &PresentationV3{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, PresentationMsgTypeV3, r.Type)
+				require.Equal(t, "goal", r.Body.GoalCode)
+
+				return nil
+			})
+
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "request-received", false)
+
+			return nil
+		})
+		store.EXPECT().Delete(gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "presentation-sent", false)
+
+			return nil
+		})
+
+		svc, err := New(provider)
+		require.NoError(t, err)
+
+		ch := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(ch))
+
+		id := uuid.New().String()
+		msg := service.NewDIDCommMsgMap(RequestPresentationV3{
+			ID:   id,
+			Type: RequestPresentationMsgTypeV3,
+			Body: RequestPresentationV3Body{WillConfirm: true},
+		})
+		msg["thid"] = id
+
+		_, err = svc.HandleInbound(msg, service.NewDIDCommContext(Alice, Bob, nil))
+		require.NoError(t, err)
+
+		action := <-ch
+
+		properties, ok := action.Properties.(*eventProps)
+		require.True(t, ok)
+		require.Equal(t, id, properties.PIID())
+
+		action.Continue(WithPresentation(&Presentation{GoalCode: "goal"}))
+
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+
 	t.Run("Receive Invitation Presentation (continue with presentation) async", func(t *testing.T) {
 		done := make(chan struct{})
 
@@ -581,18 +674,14 @@ func TestService_HandleInbound(t *testing.T) {
 
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "request-received"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "request-received", false)
 
 			return nil
 		})
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "proposal-sent"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "proposal-sent", false)
 
 			return nil
 		})
@@ -642,18 +731,14 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "proposal-received"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "proposal-received", false)
 
 			return nil
 		})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "request-sent"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "request-sent", false)
 
 			return nil
 		})
@@ -690,18 +775,16 @@ func TestService_HandleInbound(t *testing.T) {
 	t.Run("Receive Problem Report (continue)", func(t *testing.T) {
 		done := make(chan struct{})
 
-		src, err := json.Marshal(&internalData{StateName: "request-sent"})
+		src, err := json.Marshal(&internalData{Thread: Thread{StateName: "request-sent"}})
 		require.NoError(t, err)
 
 		store.EXPECT().Get(gomock.Any()).Return(src, nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			src, err = json.Marshal(&internalData{StateName: "abandoned"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+			requireInternalData(t, data, "abandoned", false)
 
 			return nil
 		})
@@ -736,18 +819,16 @@ func TestService_HandleInbound(t *testing.T) {
 	t.Run("Receive Problem Report (stop)", func(t *testing.T) {
 		done := make(chan struct{})
 
-		src, err := json.Marshal(&internalData{StateName: "request-sent"})
+		src, err := json.Marshal(&internalData{Thread: Thread{StateName: "request-sent"}})
 		require.NoError(t, err)
 
 		store.EXPECT().Get(gomock.Any()).Return(src, nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			src, err = json.Marshal(&internalData{StateName: "abandoned"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+			requireInternalData(t, data, "abandoned", false)
 
 			return nil
 		})
@@ -798,18 +879,14 @@ func TestService_HandleInbound(t *testing.T) {
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "proposal-received"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "proposal-received", false)
 
 			return nil
 		})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "abandoned"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "abandoned", false)
 
 			return nil
 		})
@@ -855,26 +932,22 @@ func TestService_HandleInbound(t *testing.T) {
 				return nil
 			})
 
-		src, err := json.Marshal(&internalData{AckRequired: true, StateName: "request-sent"})
+		src, err := json.Marshal(&internalData{AckRequired: true, Thread: Thread{StateName: "request-sent"}})
 		require.NoError(t, err)
 
 		store.EXPECT().Get(gomock.Any()).Return(src, nil)
 		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		store.EXPECT().Delete(gomock.Any()).Return(nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err = json.Marshal(&internalData{AckRequired: true, StateName: "presentation-received"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "presentation-received", true)
 
 			return nil
 		})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			src, err = json.Marshal(&internalData{AckRequired: true, StateName: "done"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+			requireInternalData(t, data, "done", true)
 
 			return nil
 		})
@@ -920,16 +993,14 @@ func TestService_HandleInbound(t *testing.T) {
 	t.Run("Receive Ack", func(t *testing.T) {
 		done := make(chan struct{})
 
-		src, err := json.Marshal(&internalData{StateName: "presentation-sent"})
+		src, err := json.Marshal(&internalData{Thread: Thread{StateName: "presentation-sent"}})
 		require.NoError(t, err)
 
 		store.EXPECT().Get(gomock.Any()).Return(src, nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
 			defer close(done)
 
-			src, err = json.Marshal(&internalData{StateName: "done"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+			requireInternalData(t, data, "done", false)
 
 			return nil
 		})
@@ -954,10 +1025,8 @@ func TestService_HandleInbound(t *testing.T) {
 	t.Run("Send Invitation Presentation", func(t *testing.T) {
 		done := make(chan struct{})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "request-sent"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "request-sent", false)
 
 			return nil
 		})
@@ -1009,10 +1078,8 @@ func TestService_HandleInbound(t *testing.T) {
 	t.Run("Send Proposal", func(t *testing.T) {
 		done := make(chan struct{})
 
-		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte) error {
-			src, err := json.Marshal(&internalData{StateName: "proposal-sent"})
-			require.NoError(t, err)
-			require.Equal(t, src, data)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, data []byte, _ ...storage.Tag) error {
+			requireInternalData(t, data, "proposal-sent", false)
 
 			return nil
 		})
@@ -1046,7 +1113,7 @@ func TestService_HandleInbound(t *testing.T) {
 	})
 
 	t.Run("Send Proposal with error", func(t *testing.T) {
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 		svc, err := New(provider)
 		require.NoError(t, err)
@@ -1109,6 +1176,7 @@ func Test_getTransitionalPayload(t *testing.T) {
 	defer ctrl.Finish()
 
 	store := storageMocks.NewMockStore(ctrl)
+	expectCallbacks(store)
 
 	storeProvider := storageMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(Name).Return(store, nil).AnyTimes()
@@ -1165,3 +1233,43 @@ func Test_nextState(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, next)
 }
+
+// expectCallbacks allows the service to persist, delete and resume the action callbacks.
+func expectCallbacks(store *storageMocks.MockStore) {
+	records, err := mem.NewProvider().OpenStore(Name)
+	if err != nil {
+		panic(err)
+	}
+
+	iterator, err := records.Query(threads.CallbackKey)
+	if err != nil {
+		panic(err)
+	}
+
+	store.EXPECT().Query(threads.CallbackKey).Return(iterator, nil).AnyTimes()
+	store.EXPECT().Put(keyPrefix(threads.CallbackKey), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	store.EXPECT().Delete(keyPrefix(threads.CallbackKey)).Return(nil).AnyTimes()
+}
+
+// keyPrefix matches the store keys starting with the given prefix.
+type keyPrefix string
+
+func (p keyPrefix) Matches(x interface{}) bool {
+	key, ok := x.(string)
+
+	return ok && strings.HasPrefix(key, string(p))
+}
+
+func (p keyPrefix) String() string {
+	return "has prefix " + string(p)
+}
+
+// requireInternalData checks the state of the persisted internal data.
+func requireInternalData(t *testing.T, src []byte, stateName string, ackRequired bool) {
+	t.Helper()
+
+	data := &internalData{}
+	require.NoError(t, json.Unmarshal(src, data))
+	require.Equal(t, stateName, data.StateName)
+	require.Equal(t, ackRequired, data.AckRequired)
+}
@@ -9,6 +9,7 @@ package presentproof
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -35,9 +36,11 @@ const (
 const (
 	// error codes.
 	codeInternalError = "internal"
+	codeTimeoutError  = "timeout"
 	codeRejectedError = "rejected"
 
-	jsonThread = "~thread"
+	jsonThread   = "~thread"
+	jsonThreadID = "thid"
 )
 
 // state action for network call.
@@ -97,7 +100,7 @@ func (s *abandoned) CanTransitionTo(st state) bool {
 func (s *abandoned) Execute(md *metaData) (state, stateAction, error) {
 	// if code is not provided it means we do not need to notify the another agent.
 	// if we received ProblemReport message no need to answer.
-	if s.Code == "" || md.Msg.Type() == ProblemReportMsgType {
+	if s.Code == "" || md.Msg.Type() == ProblemReportMsgType || md.Msg.Type() == ProblemReportMsgTypeV3 {
 		return &noOp{}, zeroAction, nil
 	}
 
@@ -113,11 +116,21 @@ func (s *abandoned) Execute(md *metaData) (state, stateAction, error) {
 		return nil, nil, fmt.Errorf("threadID: %w", err)
 	}
 
+	report := service.NewDIDCommMsgMap(&model.ProblemReport{
+		Type:        ProblemReportMsgType,
+		Description: code,
+	})
+
+	if isV3(md.Msg) {
+		report = service.NewDIDCommMsgMap(&model.ProblemReportV2{
+			Type: ProblemReportMsgTypeV3,
+			Body: model.ProblemReportV2Body{Code: code.Code},
+		})
+	}
+
 	return &noOp{}, func(messenger service.Messenger) error {
-		return messenger.ReplyToNested(service.NewDIDCommMsgMap(&model.ProblemReport{
-			Type:        ProblemReportMsgType,
-			Description: code,
-		}), &service.NestedReplyOpts{ThreadID: thID, MyDID: md.MyDID, TheirDID: md.TheirDID})
+		return messenger.ReplyToNested(report,
+			&service.NestedReplyOpts{ThreadID: thID, MyDID: md.MyDID, TheirDID: md.TheirDID})
 	}, nil
 }
 
@@ -169,9 +182,8 @@ func (s *requestReceived) Execute(md *metaData) (state, stateAction, error) {
 		return &proposalSent{}, zeroAction, nil
 	}
 
-	var req *RequestPresentation
-
-	if err := md.Msg.Decode(&req); err != nil {
+	req, err := DecodeRequestPresentation(md.Msg)
+	if err != nil {
 		return nil, nil, err
 	}
 
@@ -193,15 +205,19 @@ func (s *requestSent) CanTransitionTo(st state) bool {
 
 func forwardInitial(md *metaData) stateAction {
 	return func(messenger service.Messenger) error {
+		// the connectionless request is delivered out-of-band by the client, e.g attached to an invitation
+		if service.IsConnectionless(md.Msg, md.TheirDID) {
+			return nil
+		}
+
 		return messenger.Send(md.Msg, md.MyDID, md.TheirDID)
 	}
 }
 
 func (s *requestSent) Execute(md *metaData) (state, stateAction, error) {
 	if !canReplyTo(md.Msg) {
-		var req *RequestPresentation
-
-		if err := md.Msg.Decode(&req); err != nil {
+		req, err := DecodeRequestPresentation(md.Msg)
+		if err != nil {
 			return nil, nil, err
 		}
 
@@ -218,7 +234,13 @@ func (s *requestSent) Execute(md *metaData) (state, stateAction, error) {
 
 	return &noOp{}, func(messenger service.Messenger) error {
 		md.request.Type = RequestPresentationMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.request), md.MyDID, md.TheirDID)
+
+		request := service.NewDIDCommMsgMap(md.request)
+		if isV3(md.Msg) {
+			request = service.NewDIDCommMsgMap(md.request.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, request, md.MyDID, md.TheirDID)
 	}, nil
 }
 
@@ -245,7 +267,13 @@ func (s *presentationSent) Execute(md *metaData) (state, stateAction, error) {
 	action := func(messenger service.Messenger) error {
 		// sets message type
 		md.presentation.Type = PresentationMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.presentation), md.MyDID, md.TheirDID)
+
+		presentation := service.NewDIDCommMsgMap(md.presentation)
+		if isV3(md.Msg) {
+			presentation = service.NewDIDCommMsgMap(md.presentation.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, presentation, md.MyDID, md.TheirDID)
 	}
 
 	if !s.WillConfirm {
@@ -274,6 +302,12 @@ func (s *presentationReceived) Execute(md *metaData) (state, stateAction, error)
 
 	// creates the state's action
 	action := func(messenger service.Messenger) error {
+		if isV3(md.Msg) {
+			return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(model.AckV2{
+				Type: AckMsgTypeV3,
+			}), md.MyDID, md.TheirDID)
+		}
+
 		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(model.Ack{
 			Type: AckMsgType,
 		}), md.MyDID, md.TheirDID)
@@ -295,7 +329,13 @@ func (s *proposalSent) CanTransitionTo(st state) bool {
 }
 
 func canReplyTo(msg service.DIDCommMsgMap) bool {
+	if msg.IsDIDCommV2() {
+		_, ok := msg[jsonThreadID]
+		return ok
+	}
+
 	_, ok := msg[jsonThread]
+
 	return ok
 }
 
@@ -310,7 +350,13 @@ func (s *proposalSent) Execute(md *metaData) (state, stateAction, error) {
 
 	return &noOp{}, func(messenger service.Messenger) error {
 		md.proposePresentation.Type = ProposePresentationMsgType
-		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.proposePresentation), md.MyDID, md.TheirDID)
+
+		proposal := service.NewDIDCommMsgMap(md.proposePresentation)
+		if isV3(md.Msg) {
+			proposal = service.NewDIDCommMsgMap(md.proposePresentation.AsV3())
+		}
+
+		return messenger.ReplyToMsg(md.Msg, proposal, md.MyDID, md.TheirDID)
 	}, nil
 }
 
@@ -329,3 +375,8 @@ func (s *proposalReceived) CanTransitionTo(st state) bool {
 func (s *proposalReceived) Execute(_ *metaData) (state, stateAction, error) {
 	return &requestSent{}, zeroAction, nil
 }
+
+// isV3 checks whether the message belongs to the present-proof 3.0 (DIDComm V2) protocol.
+func isV3(msg service.DIDCommMsg) bool {
+	return strings.HasPrefix(msg.Type(), SpecV3)
+}
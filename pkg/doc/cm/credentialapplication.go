@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+const (
+	// CredentialApplicationJSONLDContextIRI is the JSONLD context of credential applications.
+	CredentialApplicationJSONLDContextIRI = "https://identity.foundation/credential-manifest/application/v1"
+	// CredentialApplicationJSONLDType is the JSONLD type of credential applications.
+	CredentialApplicationJSONLDType = "CredentialApplication"
+
+	applicationProperty = "credential_application"
+)
+
+// CredentialApplication is sent by the holder to the issuer to apply for the credentials described
+// by the credential manifest. It is embedded in the verifiable presentation along with the presentation
+// submission satisfying the presentation definition of the manifest.
+type CredentialApplication struct {
+	// ID unique resource identifier.
+	ID string `json:"id,omitempty"`
+	// ManifestID links the application to the credential manifest.
+	ManifestID string `json:"manifest_id,omitempty"`
+	// Format is the claim format the holder wants the credentials to be issued in.
+	Format *presexch.Format `json:"format,omitempty"`
+}
+
+// CreateApplication creates the verifiable presentation with the credential application for the manifest.
+// If the manifest has the presentation definition, the presentation includes the credentials satisfying it
+// (see presexch.PresentationDefinition.CreateVP). The presentation is expected to be signed by the holder.
+func (m *CredentialManifest) CreateApplication(credentials []*verifiable.Credential,
+	opts ...verifiable.CredentialOpt) (*verifiable.Presentation, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		vp  *verifiable.Presentation
+		err error
+	)
+
+	if m.PresentationDefinition != nil {
+		vp, err = m.PresentationDefinition.CreateVP(credentials, opts...)
+	} else {
+		vp, err = verifiable.NewPresentation()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("create presentation: %w", err)
+	}
+
+	vp.Context = append(vp.Context, CredentialApplicationJSONLDContextIRI)
+	vp.Type = append(vp.Type, CredentialApplicationJSONLDType)
+
+	if vp.CustomFields == nil {
+		vp.CustomFields = verifiable.CustomFields{}
+	}
+
+	vp.CustomFields[applicationProperty] = &CredentialApplication{
+		ID:         uuid.New().String(),
+		ManifestID: m.ID,
+		Format:     m.Format,
+	}
+
+	return vp, nil
+}
+
+// ValidateApplication validates the credential application (sent by the holder) against the manifest
+// and returns the credentials submitted for the input descriptors of the presentation definition.
+func (m *CredentialManifest) ValidateApplication(vp *verifiable.Presentation,
+	opts ...presexch.MatchOption) (map[string]*verifiable.Credential, error) {
+	application, err := ApplicationFromPresentation(vp)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.ManifestID != m.ID {
+		return nil, fmt.Errorf("credential application: manifest id %q does not match %q",
+			application.ManifestID, m.ID)
+	}
+
+	if m.PresentationDefinition == nil {
+		return map[string]*verifiable.Credential{}, nil
+	}
+
+	matched, err := m.PresentationDefinition.Match(vp, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("credential application: %w", err)
+	}
+
+	return matched, nil
+}
+
+// ApplicationFromPresentation returns the credential application embedded in the verifiable presentation.
+func ApplicationFromPresentation(vp *verifiable.Presentation) (*CredentialApplication, error) {
+	if !contains(vp.Type, CredentialApplicationJSONLDType) {
+		return nil, fmt.Errorf("credential application: presentation must have json-ld type %s",
+			CredentialApplicationJSONLDType)
+	}
+
+	application := &CredentialApplication{}
+
+	if err := decodeCustomField(vp, applicationProperty, application); err != nil {
+		return nil, fmt.Errorf("credential application: %w", err)
+	}
+
+	if application.ManifestID == "" {
+		return nil, errors.New("credential application: manifest id is required")
+	}
+
+	return application, nil
+}
+
+func decodeCustomField(vp *verifiable.Presentation, name string, v interface{}) error {
+	field, ok := vp.CustomFields[name]
+	if !ok {
+		return fmt.Errorf("missing '%s' on verifiable presentation", name)
+	}
+
+	raw, err := json.Marshal(field)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", name, err)
+	}
+
+	if err = json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("unmarshal %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func contains(data []string, e string) bool {
+	for _, el := range data {
+		if el == e {
+			return true
+		}
+	}
+
+	return false
+}
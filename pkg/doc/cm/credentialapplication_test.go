@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+func TestCredentialManifest_CreateApplication(t *testing.T) {
+	loader := documentLoader(t)
+	matchOpts := presexch.WithCredentialOptions(
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithNoCustomSchemaCheck(),
+		verifiable.WithJSONLDDocumentLoader(loader),
+	)
+
+	passport := newCredential([]string{"Passport"}, map[string]interface{}{
+		"id":   "did:example:holder",
+		"name": "Jane Doe",
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		manifest := parseManifest(t)
+
+		vp, err := manifest.CreateApplication([]*verifiable.Credential{passport})
+		require.NoError(t, err)
+		require.Contains(t, vp.Context, CredentialApplicationJSONLDContextIRI)
+		require.Contains(t, vp.Type, CredentialApplicationJSONLDType)
+
+		received := toPresentation(t, vp, loader)
+
+		application, err := ApplicationFromPresentation(received)
+		require.NoError(t, err)
+		require.NotEmpty(t, application.ID)
+		require.Equal(t, manifest.ID, application.ManifestID)
+		require.Equal(t, manifest.Format, application.Format)
+
+		matched, err := manifest.ValidateApplication(received, matchOpts)
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		require.Equal(t, passport.ID, matched["passport_input"].ID)
+	})
+
+	t.Run("No presentation definition", func(t *testing.T) {
+		manifest := parseManifest(t)
+		manifest.PresentationDefinition = nil
+
+		vp, err := manifest.CreateApplication(nil)
+		require.NoError(t, err)
+		require.Empty(t, vp.Credentials())
+
+		matched, err := manifest.ValidateApplication(toPresentation(t, vp, loader))
+		require.NoError(t, err)
+		require.Empty(t, matched)
+	})
+
+	t.Run("Invalid manifest", func(t *testing.T) {
+		manifest := parseManifest(t)
+		manifest.ID = ""
+
+		vp, err := manifest.CreateApplication([]*verifiable.Credential{passport})
+		require.EqualError(t, err, "credential manifest: id is required")
+		require.Nil(t, vp)
+	})
+
+	t.Run("Credentials do not satisfy presentation definition", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateApplication([]*verifiable.Credential{
+			newCredential([]string{"Passport"}, map[string]interface{}{"id": "did:example:holder"}),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create presentation")
+		require.Nil(t, vp)
+	})
+
+	t.Run("Manifest ID does not match", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateApplication([]*verifiable.Credential{passport})
+		require.NoError(t, err)
+
+		manifest := parseManifest(t)
+		manifest.ID = "other"
+
+		matched, err := manifest.ValidateApplication(toPresentation(t, vp, loader), matchOpts)
+		require.EqualError(t, err,
+			`credential application: manifest id "WA-DL-CLASS-A" does not match "other"`)
+		require.Nil(t, matched)
+	})
+
+	t.Run("Submission does not satisfy presentation definition", func(t *testing.T) {
+		manifest := parseManifest(t)
+		manifest.PresentationDefinition = nil
+
+		vp, err := manifest.CreateApplication(nil)
+		require.NoError(t, err)
+
+		matched, err := parseManifest(t).ValidateApplication(toPresentation(t, vp, loader), matchOpts)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential application: input verifiable presentation must have json-ld context")
+		require.Nil(t, matched)
+	})
+}
+
+func TestApplicationFromPresentation(t *testing.T) {
+	t.Run("No application type", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		application, err := ApplicationFromPresentation(vp)
+		require.EqualError(t, err,
+			"credential application: presentation must have json-ld type CredentialApplication")
+		require.Nil(t, application)
+	})
+
+	t.Run("No application", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		vp.Type = append(vp.Type, CredentialApplicationJSONLDType)
+
+		application, err := ApplicationFromPresentation(vp)
+		require.EqualError(t, err,
+			"credential application: missing 'credential_application' on verifiable presentation")
+		require.Nil(t, application)
+	})
+
+	t.Run("Invalid application", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		vp.Type = append(vp.Type, CredentialApplicationJSONLDType)
+		vp.CustomFields = verifiable.CustomFields{"credential_application": "invalid"}
+
+		application, err := ApplicationFromPresentation(vp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential application: unmarshal credential_application")
+		require.Nil(t, application)
+	})
+
+	t.Run("No manifest ID", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		vp.Type = append(vp.Type, CredentialApplicationJSONLDType)
+		vp.CustomFields = verifiable.CustomFields{"credential_application": &CredentialApplication{ID: "id"}}
+
+		application, err := ApplicationFromPresentation(vp)
+		require.EqualError(t, err, "credential application: manifest id is required")
+		require.Nil(t, application)
+	})
+}
@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+const (
+	// CredentialFulfillmentJSONLDContextIRI is the JSONLD context of credential fulfillments.
+	CredentialFulfillmentJSONLDContextIRI = "https://identity.foundation/credential-manifest/fulfillment/v1"
+	// CredentialFulfillmentJSONLDType is the JSONLD type of credential fulfillments.
+	CredentialFulfillmentJSONLDType = "CredentialFulfillment"
+
+	fulfillmentProperty = "credential_fulfillment"
+)
+
+// CredentialFulfillment is sent by the issuer to the holder along with the issued credentials.
+// It is embedded in the verifiable presentation containing the credentials and maps the output descriptors
+// of the credential manifest to the credentials.
+type CredentialFulfillment struct {
+	// ID unique resource identifier.
+	ID string `json:"id,omitempty"`
+	// ManifestID links the fulfillment to the credential manifest.
+	ManifestID string `json:"manifest_id,omitempty"`
+	// ApplicationID links the fulfillment to the credential application.
+	ApplicationID string `json:"application_id,omitempty"`
+	// DescriptorMap maps the output descriptors to the credentials (the id of the mapping is the id of
+	// the output descriptor).
+	DescriptorMap []*presexch.InputDescriptorMapping `json:"descriptor_map,omitempty"`
+}
+
+// ResolvedDescriptor is the credential issued for the output descriptor of the credential manifest.
+type ResolvedDescriptor struct {
+	DescriptorID string                 `json:"descriptor_id,omitempty"`
+	Credential   *verifiable.Credential `json:"credential,omitempty"`
+	Display      *ResolvedDisplay       `json:"display,omitempty"`
+}
+
+// CreateFulfillment creates the verifiable presentation with the credential fulfillment for the credentials
+// issued in response to the credential application. The credentials are given by output descriptor ID.
+// The presentation is expected to be signed by the issuer.
+func (m *CredentialManifest) CreateFulfillment(applicationID string,
+	credentials map[string]*verifiable.Credential) (*verifiable.Presentation, error) {
+	if len(credentials) == 0 {
+		return nil, errors.New("credential fulfillment: credentials are required")
+	}
+
+	for id := range credentials {
+		if _, ok := m.OutputDescriptor(id); !ok {
+			return nil, fmt.Errorf("credential fulfillment: output descriptor %s not found", id)
+		}
+	}
+
+	var (
+		opts          []verifiable.CreatePresentationOpt
+		descriptorMap []*presexch.InputDescriptorMapping
+	)
+
+	// keeps the order of output descriptors of the manifest.
+	for _, descriptor := range m.OutputDescriptors {
+		vc, ok := credentials[descriptor.ID]
+		if !ok {
+			continue
+		}
+
+		format := presexch.FormatLDPVC
+
+		if vc.JWT != "" {
+			format = presexch.FormatJWTVC
+
+			opts = append(opts, verifiable.WithJWTCredentials(vc.JWT))
+		} else {
+			opts = append(opts, verifiable.WithCredentials(vc))
+		}
+
+		descriptorMap = append(descriptorMap, &presexch.InputDescriptorMapping{
+			ID:     descriptor.ID,
+			Format: format,
+			Path:   fmt.Sprintf("$.verifiableCredential[%d]", len(descriptorMap)),
+		})
+	}
+
+	vp, err := verifiable.NewPresentation(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create presentation: %w", err)
+	}
+
+	vp.Context = append(vp.Context, CredentialFulfillmentJSONLDContextIRI)
+	vp.Type = append(vp.Type, CredentialFulfillmentJSONLDType)
+	vp.CustomFields = verifiable.CustomFields{
+		fulfillmentProperty: &CredentialFulfillment{
+			ID:            uuid.New().String(),
+			ManifestID:    m.ID,
+			ApplicationID: applicationID,
+			DescriptorMap: descriptorMap,
+		},
+	}
+
+	return vp, nil
+}
+
+// ResolveFulfillment returns the credentials of the credential fulfillment (sent by the issuer)
+// with the values to be displayed to the holder resolved using the output descriptors of the manifest.
+// The options are used to parse the credentials of the presentation.
+func (m *CredentialManifest) ResolveFulfillment(vp *verifiable.Presentation,
+	opts ...verifiable.CredentialOpt) ([]*ResolvedDescriptor, error) {
+	fulfillment, err := FulfillmentFromPresentation(vp)
+	if err != nil {
+		return nil, err
+	}
+
+	if fulfillment.ManifestID != m.ID {
+		return nil, fmt.Errorf("credential fulfillment: manifest id %q does not match %q",
+			fulfillment.ManifestID, m.ID)
+	}
+
+	vpBytes, err := vp.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal vp: %w", err)
+	}
+
+	var typelessVP interface{}
+
+	if err = json.Unmarshal(vpBytes, &typelessVP); err != nil {
+		return nil, fmt.Errorf("unmarshal vp: %w", err)
+	}
+
+	builder := gval.Full(jsonpath.PlaceholderExtension())
+
+	result := make([]*ResolvedDescriptor, 0, len(fulfillment.DescriptorMap))
+
+	for _, mapping := range fulfillment.DescriptorMap {
+		descriptor, ok := m.OutputDescriptor(mapping.ID)
+		if !ok {
+			return nil, fmt.Errorf("credential fulfillment: output descriptor %s not found", mapping.ID)
+		}
+
+		vc, err := selectCredential(builder, typelessVP, mapping, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("credential fulfillment: output descriptor %s: %w", mapping.ID, err)
+		}
+
+		if !matchSchema(vc, descriptor.Schema) {
+			return nil, fmt.Errorf("credential fulfillment: output descriptor %s requires schema %s",
+				mapping.ID, descriptor.Schema)
+		}
+
+		display, err := descriptor.ResolveDisplay(vc)
+		if err != nil {
+			return nil, fmt.Errorf("credential fulfillment: output descriptor %s: %w", mapping.ID, err)
+		}
+
+		result = append(result, &ResolvedDescriptor{
+			DescriptorID: descriptor.ID,
+			Credential:   vc,
+			Display:      display,
+		})
+	}
+
+	return result, nil
+}
+
+// FulfillmentFromPresentation returns the credential fulfillment embedded in the verifiable presentation.
+func FulfillmentFromPresentation(vp *verifiable.Presentation) (*CredentialFulfillment, error) {
+	if !contains(vp.Type, CredentialFulfillmentJSONLDType) {
+		return nil, fmt.Errorf("credential fulfillment: presentation must have json-ld type %s",
+			CredentialFulfillmentJSONLDType)
+	}
+
+	fulfillment := &CredentialFulfillment{}
+
+	if err := decodeCustomField(vp, fulfillmentProperty, fulfillment); err != nil {
+		return nil, fmt.Errorf("credential fulfillment: %w", err)
+	}
+
+	if fulfillment.ManifestID == "" {
+		return nil, errors.New("credential fulfillment: manifest id is required")
+	}
+
+	return fulfillment, nil
+}
+
+func selectCredential(builder gval.Language, vp interface{}, mapping *presexch.InputDescriptorMapping,
+	opts ...verifiable.CredentialOpt) (*verifiable.Credential, error) {
+	jsonPath := mapping.Path
+	if mapping.PathNested != nil {
+		jsonPath = mapping.PathNested.Path
+	}
+
+	path, err := builder.NewEvaluable(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("build json path evaluator: %w", err)
+	}
+
+	cred, err := path(context.TODO(), vp)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate json path [%s]: %w", jsonPath, err)
+	}
+
+	var credBytes []byte
+
+	// credential in JWT form (jwt_vc) is selected as a string.
+	if jwt, ok := cred.(string); ok {
+		credBytes = []byte(jwt)
+	} else {
+		credBytes, err = json.Marshal(cred)
+		if err != nil {
+			return nil, fmt.Errorf("marshal credential: %w", err)
+		}
+	}
+
+	vc, err := verifiable.ParseCredential(credBytes, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse credential: %w", err)
+	}
+
+	return vc, nil
+}
+
+func matchSchema(vc *verifiable.Credential, schema string) bool {
+	if contains(vc.Types, schema) || contains(vc.Context, schema) {
+		return true
+	}
+
+	for _, s := range vc.Schemas {
+		if s.ID == schema {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+func TestCredentialManifest_CreateFulfillment(t *testing.T) {
+	loader := documentLoader(t)
+	parseOpts := []verifiable.CredentialOpt{
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithNoCustomSchemaCheck(),
+		verifiable.WithJSONLDDocumentLoader(loader),
+	}
+
+	license := newCredential([]string{"DriversLicense"}, map[string]interface{}{
+		"id":            "did:example:holder",
+		"licenseNumber": "123-456",
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		manifest := parseManifest(t)
+
+		vp, err := manifest.CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"driver_license_output": license,
+		})
+		require.NoError(t, err)
+		require.Contains(t, vp.Context, CredentialFulfillmentJSONLDContextIRI)
+		require.Contains(t, vp.Type, CredentialFulfillmentJSONLDType)
+
+		received := toPresentation(t, vp, loader)
+
+		fulfillment, err := FulfillmentFromPresentation(received)
+		require.NoError(t, err)
+		require.NotEmpty(t, fulfillment.ID)
+		require.Equal(t, manifest.ID, fulfillment.ManifestID)
+		require.Equal(t, "application-id", fulfillment.ApplicationID)
+		require.Equal(t, []*presexch.InputDescriptorMapping{{
+			ID:     "driver_license_output",
+			Format: presexch.FormatLDPVC,
+			Path:   "$.verifiableCredential[0]",
+		}}, fulfillment.DescriptorMap)
+
+		resolved, err := manifest.ResolveFulfillment(received, parseOpts...)
+		require.NoError(t, err)
+		require.Len(t, resolved, 1)
+		require.Equal(t, "driver_license_output", resolved[0].DescriptorID)
+		require.Equal(t, license.ID, resolved[0].Credential.ID)
+		require.Equal(t, &ResolvedDisplay{
+			Title:    "Washington State Driver License",
+			Subtitle: "Class A, Commercial",
+			Description: "License to operate a vehicle with a gross combined weight rating (GCWR) " +
+				"of 26,001 or more pounds.",
+			Properties: []*ResolvedProperty{{
+				Label:  "License number",
+				Value:  "123-456",
+				Schema: &Schema{Type: "string"},
+			}, {
+				Label:  "Date of birth",
+				Value:  "Not available",
+				Schema: &Schema{Type: "string", Format: "date"},
+			}},
+		}, resolved[0].Display)
+	})
+
+	t.Run("No credentials", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateFulfillment("application-id", nil)
+		require.EqualError(t, err, "credential fulfillment: credentials are required")
+		require.Nil(t, vp)
+	})
+
+	t.Run("Unknown output descriptor", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"unknown": license,
+		})
+		require.EqualError(t, err, "credential fulfillment: output descriptor unknown not found")
+		require.Nil(t, vp)
+	})
+
+	t.Run("Manifest ID does not match", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"driver_license_output": license,
+		})
+		require.NoError(t, err)
+
+		manifest := parseManifest(t)
+		manifest.ID = "other"
+
+		resolved, err := manifest.ResolveFulfillment(toPresentation(t, vp, loader), parseOpts...)
+		require.EqualError(t, err,
+			`credential fulfillment: manifest id "WA-DL-CLASS-A" does not match "other"`)
+		require.Nil(t, resolved)
+	})
+
+	t.Run("Credential does not match schema", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"driver_license_output": newCredential([]string{"Passport"}, map[string]interface{}{
+				"id": "did:example:holder",
+			}),
+		})
+		require.NoError(t, err)
+
+		resolved, err := parseManifest(t).ResolveFulfillment(toPresentation(t, vp, loader), parseOpts...)
+		require.EqualError(t, err,
+			"credential fulfillment: output descriptor driver_license_output requires schema DriversLicense")
+		require.Nil(t, resolved)
+	})
+
+	t.Run("Invalid path", func(t *testing.T) {
+		vp, err := parseManifest(t).CreateFulfillment("application-id", map[string]*verifiable.Credential{
+			"driver_license_output": license,
+		})
+		require.NoError(t, err)
+
+		vp.CustomFields["credential_fulfillment"].(*CredentialFulfillment).DescriptorMap[0].Path =
+			"$.verifiableCredential[1]"
+
+		resolved, err := parseManifest(t).ResolveFulfillment(toPresentation(t, vp, loader), parseOpts...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "credential fulfillment: output descriptor driver_license_output: "+
+			"evaluate json path")
+		require.Nil(t, resolved)
+	})
+}
+
+func TestFulfillmentFromPresentation(t *testing.T) {
+	t.Run("No fulfillment type", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		fulfillment, err := FulfillmentFromPresentation(vp)
+		require.EqualError(t, err,
+			"credential fulfillment: presentation must have json-ld type CredentialFulfillment")
+		require.Nil(t, fulfillment)
+	})
+
+	t.Run("No manifest ID", func(t *testing.T) {
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		vp.Type = append(vp.Type, CredentialFulfillmentJSONLDType)
+		vp.CustomFields = verifiable.CustomFields{"credential_fulfillment": &CredentialFulfillment{ID: "id"}}
+
+		fulfillment, err := FulfillmentFromPresentation(vp)
+		require.EqualError(t, err, "credential fulfillment: manifest id is required")
+		require.Nil(t, fulfillment)
+	})
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cm implements DIF Credential Manifest (https://identity.foundation/credential-manifest/).
+package cm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+)
+
+const (
+	// CredentialManifestAttachmentFormat is the attachment format of the credential manifest
+	// used by the issue credential protocol.
+	CredentialManifestAttachmentFormat = "dif/credential-manifest/manifest@v1.0"
+	// CredentialApplicationAttachmentFormat is the attachment format of the credential application
+	// used by the issue credential protocol.
+	CredentialApplicationAttachmentFormat = "dif/credential-manifest/application@v1.0"
+	// CredentialFulfillmentAttachmentFormat is the attachment format of the credential fulfillment
+	// used by the issue credential protocol.
+	CredentialFulfillmentAttachmentFormat = "dif/credential-manifest/fulfillment@v1.0"
+)
+
+// CredentialManifest describes the credentials an issuer is able to issue and the inputs it requires
+// from the holder in order to issue them.
+type CredentialManifest struct {
+	// ID unique resource identifier.
+	ID      string `json:"id,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Issuer describes the issuer of the credentials.
+	Issuer Issuer `json:"issuer,omitempty"`
+	// OutputDescriptors describe the credentials that are issued.
+	OutputDescriptors []*OutputDescriptor `json:"output_descriptors,omitempty"`
+	// Format is an object with one or more properties matching the registered Claim Format Designations
+	// (jwt_vc, ldp_vc, etc.) to inform the holder of the claim formats the issuer can issue.
+	Format *presexch.Format `json:"format,omitempty"`
+	// PresentationDefinition describes the inputs required by the issuer (optional).
+	PresentationDefinition *presexch.PresentationDefinition `json:"presentation_definition,omitempty"`
+}
+
+// Issuer describes the issuer of the credentials.
+type Issuer struct {
+	ID     string  `json:"id,omitempty"`
+	Name   string  `json:"name,omitempty"`
+	Styles *Styles `json:"styles,omitempty"`
+}
+
+// OutputDescriptor describes a credential issued by the issuer.
+type OutputDescriptor struct {
+	// ID unique (in the manifest) identifier of the output descriptor.
+	ID string `json:"id,omitempty"`
+	// Schema is the schema (e.g. credential type URI) of the issued credential.
+	Schema      string                 `json:"schema,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Display     *DataDisplayDescriptor `json:"display,omitempty"`
+	Styles      *Styles                `json:"styles,omitempty"`
+}
+
+// Styles describes how the issuer or the credential is rendered.
+type Styles struct {
+	Thumbnail  *ImageURIWithAltText `json:"thumbnail,omitempty"`
+	Hero       *ImageURIWithAltText `json:"hero,omitempty"`
+	Background *Color               `json:"background,omitempty"`
+	Text       *Color               `json:"text,omitempty"`
+}
+
+// ImageURIWithAltText is an image with alternative text.
+type ImageURIWithAltText struct {
+	URI string `json:"uri,omitempty"`
+	Alt string `json:"alt,omitempty"`
+}
+
+// Color is a color in RGB hex format (e.g. #000000).
+type Color struct {
+	Color string `json:"color,omitempty"`
+}
+
+// Validate checks the credential manifest.
+func (m *CredentialManifest) Validate() error {
+	if m.ID == "" {
+		return errors.New("credential manifest: id is required")
+	}
+
+	if m.Issuer.ID == "" {
+		return errors.New("credential manifest: issuer id is required")
+	}
+
+	if len(m.OutputDescriptors) == 0 {
+		return errors.New("credential manifest: at least one output descriptor is required")
+	}
+
+	ids := make(map[string]struct{})
+
+	for i, descriptor := range m.OutputDescriptors {
+		if err := descriptor.validate(); err != nil {
+			return fmt.Errorf("credential manifest: output descriptor[%d]: %w", i, err)
+		}
+
+		if _, ok := ids[descriptor.ID]; ok {
+			return fmt.Errorf("credential manifest: duplicate output descriptor id: %s", descriptor.ID)
+		}
+
+		ids[descriptor.ID] = struct{}{}
+	}
+
+	if m.PresentationDefinition != nil {
+		if err := m.PresentationDefinition.ValidateSchema(); err != nil {
+			return fmt.Errorf("credential manifest: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// OutputDescriptor returns the output descriptor with the given ID.
+func (m *CredentialManifest) OutputDescriptor(id string) (*OutputDescriptor, bool) {
+	for _, descriptor := range m.OutputDescriptors {
+		if descriptor.ID == id {
+			return descriptor, true
+		}
+	}
+
+	return nil, false
+}
+
+func (d *OutputDescriptor) validate() error {
+	if d.ID == "" {
+		return errors.New("id is required")
+	}
+
+	if d.Schema == "" {
+		return errors.New("schema is required")
+	}
+
+	if d.Display != nil {
+		if err := d.Display.validate(); err != nil {
+			return fmt.Errorf("display: %w", err)
+		}
+	}
+
+	return nil
+}
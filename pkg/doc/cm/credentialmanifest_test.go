@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	jld "github.com/hyperledger/aries-framework-go/pkg/doc/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+const driversLicenseContext = "https://example.org/drivers-license/v1"
+
+func TestCredentialManifest_Validate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		manifest := parseManifest(t)
+
+		require.NoError(t, manifest.Validate())
+
+		descriptor, ok := manifest.OutputDescriptor("driver_license_output")
+		require.True(t, ok)
+		require.Equal(t, "DriversLicense", descriptor.Schema)
+
+		_, ok = manifest.OutputDescriptor("unknown")
+		require.False(t, ok)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(m *CredentialManifest)
+		err    string
+	}{{
+		name:   "id is required",
+		modify: func(m *CredentialManifest) { m.ID = "" },
+		err:    "credential manifest: id is required",
+	}, {
+		name:   "issuer id is required",
+		modify: func(m *CredentialManifest) { m.Issuer.ID = "" },
+		err:    "credential manifest: issuer id is required",
+	}, {
+		name:   "output descriptors are required",
+		modify: func(m *CredentialManifest) { m.OutputDescriptors = nil },
+		err:    "credential manifest: at least one output descriptor is required",
+	}, {
+		name:   "output descriptor id is required",
+		modify: func(m *CredentialManifest) { m.OutputDescriptors[0].ID = "" },
+		err:    "credential manifest: output descriptor[0]: id is required",
+	}, {
+		name:   "output descriptor schema is required",
+		modify: func(m *CredentialManifest) { m.OutputDescriptors[0].Schema = "" },
+		err:    "credential manifest: output descriptor[0]: schema is required",
+	}, {
+		name: "duplicate output descriptor",
+		modify: func(m *CredentialManifest) {
+			m.OutputDescriptors = append(m.OutputDescriptors, m.OutputDescriptors[0])
+		},
+		err: "credential manifest: duplicate output descriptor id: driver_license_output",
+	}, {
+		name: "display mapping without text and path",
+		modify: func(m *CredentialManifest) {
+			m.OutputDescriptors[0].Display.Title = &DisplayMappingObject{}
+		},
+		err: "credential manifest: output descriptor[0]: display: title: text or path is required",
+	}, {
+		name: "display mapping with text and path",
+		modify: func(m *CredentialManifest) {
+			m.OutputDescriptors[0].Display.Subtitle.Text = "text"
+		},
+		err: "credential manifest: output descriptor[0]: display: subtitle: either text or path must be set",
+	}, {
+		name: "display mapping without schema",
+		modify: func(m *CredentialManifest) {
+			m.OutputDescriptors[0].Display.Properties[0].Schema = nil
+		},
+		err: "credential manifest: output descriptor[0]: display: properties[0]: schema is required for path",
+	}, {
+		name: "invalid presentation definition",
+		modify: func(m *CredentialManifest) {
+			m.PresentationDefinition.ID = ""
+		},
+		err: "credential manifest: presentation_definition: id is required",
+	}}
+
+	for _, test := range tests {
+		tc := test
+
+		t.Run(tc.name, func(t *testing.T) {
+			manifest := parseManifest(t)
+			tc.modify(manifest)
+
+			require.EqualError(t, manifest.Validate(), tc.err)
+		})
+	}
+}
+
+func parseManifest(t *testing.T) *CredentialManifest {
+	t.Helper()
+
+	src, err := ioutil.ReadFile("testdata/credential_manifest_drivers_license.json")
+	require.NoError(t, err)
+
+	manifest := &CredentialManifest{}
+	require.NoError(t, json.Unmarshal(src, manifest))
+
+	return manifest
+}
+
+func newCredential(types []string, subject map[string]interface{}) *verifiable.Credential {
+	return &verifiable.Credential{
+		Context: []string{verifiable.ContextURI, driversLicenseContext},
+		Types:   append([]string{verifiable.VCType}, types...),
+		ID:      "http://example.org/credentials/" + uuid.New().String(),
+		Issuer:  verifiable.Issuer{ID: "did:example:issuer"},
+		Schemas: []verifiable.TypedID{{
+			ID:   driversLicenseContext,
+			Type: "JsonSchemaValidator2018",
+		}},
+		Issued:  util.NewTime(time.Now()),
+		Subject: subject,
+	}
+}
+
+func documentLoader(t *testing.T) *jld.CachingDocumentLoader {
+	t.Helper()
+
+	const context = `{
+  "@context": {
+    "@version": 1.1,
+    "@protected": true,
+    "DriversLicense": "https://example.org/examples#DriversLicense",
+    "Passport": "https://example.org/examples#Passport",
+    "name": "http://schema.org/name",
+    "dob": "https://example.org/examples#dob",
+    "licenseNumber": "https://example.org/examples#licenseNumber"
+  }
+}`
+
+	reader, err := ld.DocumentFromReader(strings.NewReader(context))
+	require.NoError(t, err)
+
+	loader := CachingJSONLDLoader()
+	loader.AddDocument(driversLicenseContext, reader)
+
+	return loader
+}
+
+func toPresentation(t *testing.T, vp *verifiable.Presentation, loader ld.DocumentLoader) *verifiable.Presentation {
+	t.Helper()
+
+	src, err := json.Marshal(vp)
+	require.NoError(t, err)
+
+	result, err := verifiable.ParsePresentation(src,
+		verifiable.WithPresDisabledProofCheck(),
+		verifiable.WithPresJSONLDDocumentLoader(loader))
+	require.NoError(t, err)
+
+	return result
+}
@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PaesslerAG/jsonpath"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+// DataDisplayDescriptor describes how the issued credential is displayed to the holder.
+type DataDisplayDescriptor struct {
+	Title       *DisplayMappingObject          `json:"title,omitempty"`
+	Subtitle    *DisplayMappingObject          `json:"subtitle,omitempty"`
+	Description *DisplayMappingObject          `json:"description,omitempty"`
+	Properties  []*LabeledDisplayMappingObject `json:"properties,omitempty"`
+}
+
+// DisplayMappingObject maps the data of the credential to the displayed value.
+// Either Text (the constant value) or Path (with Schema) must be set.
+type DisplayMappingObject struct {
+	// Path is an array of JSONPath expressions evaluated against the credential,
+	// the first one resolved to a value is used.
+	Path   []string `json:"path,omitempty"`
+	Schema *Schema  `json:"schema,omitempty"`
+	// Fallback is used if none of the paths is resolved.
+	Fallback string `json:"fallback,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// LabeledDisplayMappingObject is the display mapping object of the credential property.
+type LabeledDisplayMappingObject struct {
+	DisplayMappingObject
+	Label string `json:"label,omitempty"`
+}
+
+// Schema describes the type of the displayed value.
+type Schema struct {
+	Type             string `json:"type,omitempty"`
+	Format           string `json:"format,omitempty"`
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+	ContentEncoding  string `json:"contentEncoding,omitempty"`
+}
+
+// ResolvedDisplay contains the values of the credential resolved using the data display descriptor.
+type ResolvedDisplay struct {
+	Title       interface{}         `json:"title,omitempty"`
+	Subtitle    interface{}         `json:"subtitle,omitempty"`
+	Description interface{}         `json:"description,omitempty"`
+	Properties  []*ResolvedProperty `json:"properties,omitempty"`
+}
+
+// ResolvedProperty is the resolved value of the credential property.
+type ResolvedProperty struct {
+	Label  string      `json:"label,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+	Schema *Schema     `json:"schema,omitempty"`
+}
+
+// ResolveDisplay resolves the values to be displayed for the given credential.
+func (d *OutputDescriptor) ResolveDisplay(vc *verifiable.Credential) (*ResolvedDisplay, error) {
+	if d.Display == nil {
+		return &ResolvedDisplay{}, nil
+	}
+
+	vcBytes, err := json.Marshal(vc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal credential: %w", err)
+	}
+
+	var vcMap map[string]interface{}
+
+	if err = json.Unmarshal(vcBytes, &vcMap); err != nil {
+		return nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	result := &ResolvedDisplay{
+		Title:       d.Display.Title.resolve(vcMap),
+		Subtitle:    d.Display.Subtitle.resolve(vcMap),
+		Description: d.Display.Description.resolve(vcMap),
+	}
+
+	for _, property := range d.Display.Properties {
+		result.Properties = append(result.Properties, &ResolvedProperty{
+			Label:  property.Label,
+			Value:  property.resolve(vcMap),
+			Schema: property.Schema,
+		})
+	}
+
+	return result, nil
+}
+
+func (d *DataDisplayDescriptor) validate() error {
+	for name, mapping := range map[string]*DisplayMappingObject{
+		"title":       d.Title,
+		"subtitle":    d.Subtitle,
+		"description": d.Description,
+	} {
+		if mapping == nil {
+			continue
+		}
+
+		if err := mapping.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	for i, property := range d.Properties {
+		if err := property.validate(); err != nil {
+			return fmt.Errorf("properties[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *DisplayMappingObject) validate() error {
+	if o.Text != "" {
+		if len(o.Path) != 0 {
+			return errors.New("either text or path must be set")
+		}
+
+		return nil
+	}
+
+	if len(o.Path) == 0 {
+		return errors.New("text or path is required")
+	}
+
+	if o.Schema == nil {
+		return errors.New("schema is required for path")
+	}
+
+	return nil
+}
+
+func (o *DisplayMappingObject) resolve(vc map[string]interface{}) interface{} {
+	if o == nil {
+		return nil
+	}
+
+	if o.Text != "" {
+		return o.Text
+	}
+
+	for _, path := range o.Path {
+		value, err := jsonpath.Get(path, vc)
+		if err == nil && value != nil {
+			return value
+		}
+	}
+
+	if o.Fallback != "" {
+		return o.Fallback
+	}
+
+	return nil
+}
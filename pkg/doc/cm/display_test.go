@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+)
+
+func TestOutputDescriptor_ResolveDisplay(t *testing.T) {
+	vc := newCredential([]string{"DriversLicense"}, map[string]interface{}{
+		"id":        "did:example:holder",
+		"birthDate": "1990-01-01",
+	})
+
+	t.Run("Resolves the first matched path", func(t *testing.T) {
+		descriptor, ok := parseManifest(t).OutputDescriptor("driver_license_output")
+		require.True(t, ok)
+
+		display, err := descriptor.ResolveDisplay(vc)
+		require.NoError(t, err)
+		require.Len(t, display.Properties, 2)
+		require.Nil(t, display.Properties[0].Value)
+		require.Equal(t, "1990-01-01", display.Properties[1].Value)
+	})
+
+	t.Run("No display", func(t *testing.T) {
+		display, err := (&OutputDescriptor{ID: "id", Schema: "DriversLicense"}).ResolveDisplay(vc)
+		require.NoError(t, err)
+		require.Equal(t, &ResolvedDisplay{}, display)
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cm
+
+import (
+	"strings"
+
+	"github.com/piprate/json-gold/ld"
+
+	jld "github.com/hyperledger/aries-framework-go/pkg/doc/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
+)
+
+// CredentialApplicationJSONLDContext is the JSON-LD context of the credential application.
+const CredentialApplicationJSONLDContext = `{
+  "@context": {
+    "@version": 1.1,
+    "CredentialApplication": {
+      "@id": "https://identity.foundation/credential-manifest/#credential-application",
+      "@context": {
+        "@version": 1.1,
+        "credential_application": {
+          "@id": "https://identity.foundation/credential-manifest/#credential-application",
+          "@type": "@json"
+        }
+      }
+    }
+  }
+}`
+
+// CredentialFulfillmentJSONLDContext is the JSON-LD context of the credential fulfillment.
+const CredentialFulfillmentJSONLDContext = `{
+  "@context": {
+    "@version": 1.1,
+    "CredentialFulfillment": {
+      "@id": "https://identity.foundation/credential-manifest/#credential-fulfillment",
+      "@context": {
+        "@version": 1.1,
+        "credential_fulfillment": {
+          "@id": "https://identity.foundation/credential-manifest/#credential-fulfillment",
+          "@type": "@json"
+        }
+      }
+    }
+  }
+}`
+
+// CachingJSONLDLoader creates JSON-LD CachingDocumentLoader with preloaded credential manifest
+// (and presentation exchange) JSON-LD contexts.
+func CachingJSONLDLoader() *jld.CachingDocumentLoader {
+	loader := presexch.CachingJSONLDLoader()
+
+	for iri, context := range map[string]string{
+		CredentialApplicationJSONLDContextIRI: CredentialApplicationJSONLDContext,
+		CredentialFulfillmentJSONLDContextIRI: CredentialFulfillmentJSONLDContext,
+	} {
+		reader, err := ld.DocumentFromReader(strings.NewReader(context))
+		if err != nil {
+			panic(err)
+		}
+
+		loader.AddDocument(iri, reader)
+	}
+
+	return loader
+}
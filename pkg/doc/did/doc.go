@@ -1039,7 +1039,7 @@ func (doc *Doc) VerifyProof(suites []verifier.SignatureSuite, jsonldOpts ...json
 		return err
 	}
 
-	v, err := verifier.New(&didKeyResolver{doc.VerificationMethod}, suites...)
+	v, err := verifier.New(&didKeyResolver{DocID: doc.ID, PubKeys: doc.VerificationMethod}, suites...)
 	if err != nil {
 		return fmt.Errorf("create verifier: %w", err)
 	}
@@ -1114,7 +1114,9 @@ func (doc *Doc) VerificationMethods(customVerificationRelationships ...Verificat
 var ErrProofNotFound = errors.New("proof not found")
 
 // didKeyResolver implements public key resolution for DID public keys.
+// The controller declared by a public key is self-asserted by the document, the document DID is reported instead.
 type didKeyResolver struct {
+	DocID   string
 	PubKeys []VerificationMethod
 }
 
@@ -1122,9 +1124,10 @@ func (r *didKeyResolver) Resolve(id string) (*verifier.PublicKey, error) {
 	for _, key := range r.PubKeys {
 		if key.ID == id {
 			return &verifier.PublicKey{
-				Type:  key.Type,
-				Value: key.Value,
-				JWK:   key.jsonWebKey,
+				Type:       key.Type,
+				Value:      key.Value,
+				JWK:        key.jsonWebKey,
+				Controller: r.DocID,
 			}, nil
 		}
 	}
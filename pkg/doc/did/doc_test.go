@@ -1337,16 +1337,19 @@ func TestDidKeyResolver_Resolve(t *testing.T) {
 
 	testKeyVal := []byte("pub key")
 	pubKeys := []VerificationMethod{{
-		ID:    "id",
-		Value: testKeyVal,
-		Type:  keyType,
+		ID:         "id",
+		Value:      testKeyVal,
+		Type:       keyType,
+		Controller: "did:example:foreign",
 	}}
 
 	// happy path - key found
-	keyResolver = didKeyResolver{PubKeys: pubKeys}
+	keyResolver = didKeyResolver{DocID: "did:example:doc", PubKeys: pubKeys}
 	key, err = keyResolver.Resolve("id")
 	require.NoError(t, err)
 	require.Equal(t, testKeyVal, key.Value)
+	// the controller declared by the key is self-asserted
+	require.Equal(t, "did:example:doc", key.Controller)
 }
 
 func TestBuildDoc(t *testing.T) {
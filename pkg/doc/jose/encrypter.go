@@ -52,7 +52,7 @@ type Encrypter interface {
 type JWEEncrypt struct {
 	recipientsKeys []*cryptoapi.PublicKey
 	skid           string
-	senderKH       *keyset.Handle
+	senderKH       interface{}
 	encAlg         EncAlg
 	encTyp         string
 	cty            string
@@ -61,7 +61,8 @@ type JWEEncrypt struct {
 
 // NewJWEEncrypt creates a new JWEEncrypt instance to build JWE with recipientsPubKeys
 // senderKID and senderKH are used for Authcrypt (to authenticate the sender), if not set JWEEncrypt assumes Anoncrypt.
-func NewJWEEncrypt(encAlg EncAlg, encType, cty, senderKID string, senderKH *keyset.Handle,
+// senderKH is the sender's private key handle returned by the KMS (a *keyset.Handle for localkms keys).
+func NewJWEEncrypt(encAlg EncAlg, encType, cty, senderKID string, senderKH interface{},
 	recipientsPubKeys []*cryptoapi.PublicKey, crypto cryptoapi.Crypto) (*JWEEncrypt, error) {
 	if len(recipientsPubKeys) == 0 {
 		return nil, fmt.Errorf("empty recipientsPubKeys list")
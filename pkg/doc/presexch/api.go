@@ -107,7 +107,13 @@ func (pd *PresentationDefinition) Match(vp *verifiable.Presentation, // nolint:g
 				descriptorMapProperty, mapping.ID)
 		}
 
-		vc, selectErr := selectByPath(builder, typelessVP, mapping.Path, opts)
+		jsonPath := mapping.Path
+		// the presentation is already decoded (e.g. from jwt_vp), so the credential is selected by the nested path.
+		if mapping.PathNested != nil {
+			jsonPath = mapping.PathNested.Path
+		}
+
+		vc, selectErr := selectByPath(builder, typelessVP, jsonPath, opts)
 		if selectErr != nil {
 			return nil, fmt.Errorf("failed to select vc from submission: %w", selectErr)
 		}
@@ -230,9 +236,16 @@ func selectByPath(builder gval.Language, vp interface{}, jsonPath string,
 		return nil, fmt.Errorf("failed to evaluate json path [%s]: %w", jsonPath, err)
 	}
 
-	credBits, err := json.Marshal(cred)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal credential: %w", err)
+	var credBits []byte
+
+	// credential in JWT form (jwt_vc) is selected as a string.
+	if jwt, ok := cred.(string); ok {
+		credBits = []byte(jwt)
+	} else {
+		credBits, err = json.Marshal(cred)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal credential: %w", err)
+		}
 	}
 
 	vc, err := verifiable.ParseCredential(credBits, options.CredentialOptions...)
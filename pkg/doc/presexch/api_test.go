@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
@@ -92,6 +93,63 @@ func TestPresentationDefinition_Match(t *testing.T) {
 		require.Equal(t, expected.ID, result.ID)
 	})
 
+	t.Run("match one JWT credential (path_nested)", func(t *testing.T) {
+		uri := randomURI()
+		contextLoader := jsonldContextLoader(t, uri)
+
+		claims, err := newVC([]string{uri}).JWTClaims(false)
+		require.NoError(t, err)
+
+		signer, err := signature.NewSigner(kms.ED25519Type)
+		require.NoError(t, err)
+
+		jws, err := claims.MarshalJWS(verifiable.EdDSA, signer, "did:example:123#key-1")
+		require.NoError(t, err)
+
+		expected, err := verifiable.ParseCredential([]byte(jws),
+			verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(contextLoader))
+		require.NoError(t, err)
+
+		defs := &PresentationDefinition{
+			InputDescriptors: []*InputDescriptor{{
+				ID: uuid.New().String(),
+				Schema: []*Schema{{
+					URI: uri,
+				}},
+			}},
+		}
+
+		vp, err := verifiable.NewPresentation(verifiable.WithJWTCredentials(jws))
+		require.NoError(t, err)
+
+		vp.Context = append(vp.Context, "https://identity.foundation/presentation-exchange/submission/v1")
+		vp.Type = append(vp.Type, "PresentationSubmission")
+		vp.CustomFields = map[string]interface{}{
+			"presentation_submission": toMap(t, &PresentationSubmission{DescriptorMap: []*InputDescriptorMapping{{
+				ID:     defs.InputDescriptors[0].ID,
+				Format: FormatJWTVP,
+				Path:   "$",
+				PathNested: &InputDescriptorMapping{
+					ID:     defs.InputDescriptors[0].ID,
+					Format: FormatJWTVC,
+					Path:   "$.verifiableCredential[0]",
+				},
+			}}}),
+		}
+
+		matched, err := defs.Match(vp, WithCredentialOptions(
+			verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(contextLoader),
+		))
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		result, ok := matched[defs.InputDescriptors[0].ID]
+		require.True(t, ok)
+		require.Equal(t, expected.ID, result.ID)
+		require.Equal(t, jws, result.JWT)
+	})
+
 	t.Run("error if vp does not have the right context", func(t *testing.T) {
 		uri := randomURI()
 		defs := &PresentationDefinition{
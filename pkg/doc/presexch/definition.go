@@ -8,6 +8,7 @@ package presexch
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -36,6 +37,23 @@ const (
 	Required Preference = "required"
 	// Preferred predicate`s value.
 	Preferred Preference = "preferred"
+	// Allowed status directive`s value.
+	Allowed Preference = "allowed"
+	// Disallowed status directive`s value.
+	Disallowed Preference = "disallowed"
+
+	// FormatJWT presentation exchange format.
+	FormatJWT = "jwt"
+	// FormatJWTVC presentation exchange format.
+	FormatJWTVC = "jwt_vc"
+	// FormatJWTVP presentation exchange format.
+	FormatJWTVP = "jwt_vp"
+	// FormatLDP presentation exchange format.
+	FormatLDP = "ldp"
+	// FormatLDPVC presentation exchange format.
+	FormatLDPVC = "ldp_vc"
+	// FormatLDPVP presentation exchange format.
+	FormatLDPVP = "ldp_vp"
 
 	tmpEnding = "tmp_unique_id_"
 )
@@ -45,7 +63,7 @@ var errPathNotApplicable = errors.New("path not applicable")
 type (
 	// Selection can be "all" or "pick".
 	Selection string
-	// Preference can be "required" or "preferred".
+	// Preference can be "required" or "preferred" (or "allowed" and "disallowed" for status directives).
 	Preference string
 	// StrOrInt type that defines string or integer.
 	StrOrInt interface{}
@@ -95,6 +113,8 @@ type PresentationDefinition struct {
 	// If not present, all inputs listed in the InputDescriptors array are required for submission.
 	SubmissionRequirements []*SubmissionRequirement `json:"submission_requirements,omitempty"`
 	InputDescriptors       []*InputDescriptor       `json:"input_descriptors,omitempty"`
+	// Frame is JSON-LD frame used for selective disclosure of the submitted credentials signed with BBS+.
+	Frame map[string]interface{} `json:"frame,omitempty"`
 }
 
 // SubmissionRequirement describes input that must be submitted via a Presentation Submission
@@ -138,9 +158,23 @@ type Constraints struct {
 	LimitDisclosure *Preference `json:"limit_disclosure,omitempty"`
 	SubjectIsIssuer *Preference `json:"subject_is_issuer,omitempty"`
 	IsHolder        []*Holder   `json:"is_holder,omitempty"`
+	SameSubject     []*Holder   `json:"same_subject,omitempty"`
+	Statuses        *Statuses   `json:"statuses,omitempty"`
 	Fields          []*Field    `json:"fields,omitempty"`
 }
 
+// Statuses describes Constraints`s statuses object.
+type Statuses struct {
+	Active    *StatusDirective `json:"active,omitempty"`
+	Suspended *StatusDirective `json:"suspended,omitempty"`
+	Revoked   *StatusDirective `json:"revoked,omitempty"`
+}
+
+// StatusDirective describes whether credentials of the given status are "required", "allowed" or "disallowed".
+type StatusDirective struct {
+	Directive Preference `json:"directive,omitempty"`
+}
+
 // Field describes Constraints`s Fields field.
 type Field struct {
 	Path      []string    `json:"path,omitempty"`
@@ -297,7 +331,87 @@ func makeRequirement(requirements []*SubmissionRequirement, descriptors []*Input
 }
 
 // CreateVP creates verifiable presentation.
+//
+// Credentials are filtered by the format of the definition (algorithms of jwt_vc and proof types of ldp_vc)
+// and by the constraints of the input descriptors; the status of credentials (statuses constraint) is checked
+// using verifiable.WithCredentialStatusChecker option. Credentials signed with BBS+ are selectively disclosed
+// using the frame of the definition, if any. Credentials parsed from JWT are presented in JWT form.
+//
+// If the definition requests JWT presentation (jwt_vp and not ldp_vp format), the descriptors of the presentation
+// submission have jwt_vp format; use CreateJWTVP to get the presentation signed as JWT.
 func (pd *PresentationDefinition) CreateVP(credentials []*verifiable.Credential,
+	opts ...verifiable.CredentialOpt) (*verifiable.Presentation, error) {
+	return pd.createVP(credentials, pd.presentationFormat(), opts...)
+}
+
+// JWTVPSigningContext contains the parameters used to sign JWT presentation.
+type JWTVPSigningContext struct {
+	// Holder is the holder of the presentation ("iss" claim).
+	Holder string
+	// Audience is the audience of the presentation ("aud" claim), e.g. DID of the verifier.
+	Audience []string
+	// KeyID is the ID of the holder key ("kid" header).
+	KeyID string
+	// Algorithm is the signature algorithm of the JWT.
+	Algorithm verifiable.JWSAlgorithm
+	// Signer signs the JWT.
+	Signer verifiable.Signer
+}
+
+// CreateJWTVP creates verifiable presentation in jwt_vp format signed as JWS.
+//
+// Credentials are selected as in CreateVP; the descriptors of the presentation submission have jwt_vp format
+// with jwt_vc or ldp_vc nested descriptors. The definition must accept jwt_vp format and the algorithm of
+// the signing context.
+func (pd *PresentationDefinition) CreateJWTVP(credentials []*verifiable.Credential, sctx *JWTVPSigningContext,
+	opts ...verifiable.CredentialOpt) (string, error) {
+	if sctx == nil || sctx.Signer == nil {
+		return "", errors.New("signing context with signer is required")
+	}
+
+	vpFormat := pd.jwtVPFormat()
+	if vpFormat == nil {
+		return "", errors.New("presentation definition does not accept jwt_vp format")
+	}
+
+	vp, err := pd.createVP(credentials, FormatJWTVP, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	vp.Holder = sctx.Holder
+
+	claims, err := vp.JWTClaims(sctx.Audience, false)
+	if err != nil {
+		return "", fmt.Errorf("create jwt claims of presentation: %w", err)
+	}
+
+	jws, err := claims.MarshalJWS(sctx.Algorithm, sctx.Signer, sctx.KeyID)
+	if err != nil {
+		return "", fmt.Errorf("sign jwt presentation: %w", err)
+	}
+
+	if !jwtAlgAllowed(jws, vpFormat) {
+		return "", errors.New("signature algorithm is not accepted by jwt_vp format of presentation definition")
+	}
+
+	return jws, nil
+}
+
+// jwtVPFormat returns the jwt_vp (or jwt) format accepted by the definition or nil if JWT presentation
+// is not accepted. Definition without format accepts any presentation format.
+func (pd *PresentationDefinition) jwtVPFormat() *JwtType {
+	switch {
+	case pd.Format == nil:
+		return &JwtType{}
+	case pd.Format.JwtVP != nil:
+		return pd.Format.JwtVP
+	default:
+		return pd.Format.Jwt
+	}
+}
+
+func (pd *PresentationDefinition) createVP(credentials []*verifiable.Credential, format string,
 	opts ...verifiable.CredentialOpt) (*verifiable.Presentation, error) {
 	if err := pd.ValidateSchema(); err != nil {
 		return nil, err
@@ -308,14 +422,26 @@ func (pd *PresentationDefinition) CreateVP(credentials []*verifiable.Credential,
 		return nil, err
 	}
 
-	result, err := applyRequirement(req, credentials, opts...)
+	filtered, err := pd.filterDescriptors(pd.filterFormat(credentials), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := applyRequirement(req, filtered)
 	if err != nil {
 		return nil, err
 	}
 
-	applicableCredentials, descriptors := merge(result)
+	if pd.Frame != nil {
+		result, err = applyFrame(pd.Frame, result, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	vp, err := verifiable.NewPresentation(verifiable.WithCredentials(applicableCredentials...))
+	applicableCredentials, descriptors := merge(format, result)
+
+	vp, err := verifiable.NewPresentation(presentationCredentials(applicableCredentials)...)
 	if err != nil {
 		return nil, err
 	}
@@ -334,15 +460,184 @@ func (pd *PresentationDefinition) CreateVP(credentials []*verifiable.Credential,
 	return vp, nil
 }
 
+// MatchedSubmissionRequirement contains the credentials matching a submission requirement
+// of the presentation definition.
+type MatchedSubmissionRequirement struct {
+	Name        string                          `json:"name,omitempty"`
+	Purpose     string                          `json:"purpose,omitempty"`
+	Rule        Selection                       `json:"rule,omitempty"`
+	Count       int                             `json:"count,omitempty"`
+	Min         int                             `json:"min,omitempty"`
+	Max         int                             `json:"max,omitempty"`
+	Descriptors []*MatchedInputDescriptor       `json:"descriptors,omitempty"`
+	Nested      []*MatchedSubmissionRequirement `json:"nested,omitempty"`
+}
+
+// MatchedInputDescriptor contains the credentials matching an input descriptor of the presentation definition.
+type MatchedInputDescriptor struct {
+	ID         string                   `json:"id,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	Purpose    string                   `json:"purpose,omitempty"`
+	MatchedVCs []*verifiable.Credential `json:"matched_vcs,omitempty"`
+}
+
+// Satisfied checks if the matched credentials are enough to fulfill the submission requirement.
+func (r *MatchedSubmissionRequirement) Satisfied() bool {
+	var matched int
+
+	for _, descriptor := range r.Descriptors {
+		if len(descriptor.MatchedVCs) != 0 {
+			matched++
+		}
+	}
+
+	for _, nested := range r.Nested {
+		if nested.Satisfied() {
+			matched++
+		}
+	}
+
+	req := &requirement{Count: r.Count, Min: r.Min, Max: r.Max}
+
+	if r.Rule == All {
+		req.Count = len(r.Descriptors) + len(r.Nested)
+	}
+
+	return matched > 0 && req.isLenApplicable(matched)
+}
+
+// MatchSubmissionRequirement returns the given (holder) credentials matching each input descriptor,
+// grouped by the submission requirements of the presentation definition. If the definition has no
+// submission requirements, a single requirement with all input descriptors is returned.
+// Credentials are returned as they are given (without limited disclosure being applied), so the holder
+// is able to choose among alternatives and to create presentation from the chosen ones using CreateVP.
+func (pd *PresentationDefinition) MatchSubmissionRequirement(credentials []*verifiable.Credential,
+	opts ...verifiable.CredentialOpt) ([]*MatchedSubmissionRequirement, error) {
+	if err := pd.ValidateSchema(); err != nil {
+		return nil, err
+	}
+
+	matchedVCs, err := pd.matchCredentials(credentials, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pd.SubmissionRequirements) == 0 {
+		descriptors := matchDescriptors(pd.InputDescriptors, matchedVCs)
+
+		return []*MatchedSubmissionRequirement{{
+			Rule:        All,
+			Count:       len(descriptors),
+			Descriptors: descriptors,
+		}}, nil
+	}
+
+	var result []*MatchedSubmissionRequirement
+
+	for _, sr := range pd.SubmissionRequirements {
+		matched, err := pd.matchSubmissionRequirement(sr, matchedVCs)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, matched)
+	}
+
+	return result, nil
+}
+
+func (pd *PresentationDefinition) matchSubmissionRequirement(sr *SubmissionRequirement,
+	matchedVCs map[string][]*verifiable.Credential) (*MatchedSubmissionRequirement, error) {
+	matched := &MatchedSubmissionRequirement{
+		Name:    sr.Name,
+		Purpose: sr.Purpose,
+		Rule:    sr.Rule,
+		Count:   sr.Count,
+		Min:     sr.Min,
+		Max:     sr.Max,
+	}
+
+	if sr.From != "" {
+		var inputDescriptors []*InputDescriptor
+
+		for _, descriptor := range pd.InputDescriptors {
+			if contains(descriptor.Group, sr.From) {
+				inputDescriptors = append(inputDescriptors, descriptor)
+			}
+		}
+
+		if len(inputDescriptors) == 0 {
+			return nil, fmt.Errorf("no descriptors for from: %s", sr.From)
+		}
+
+		matched.Descriptors = matchDescriptors(inputDescriptors, matchedVCs)
+
+		return matched, nil
+	}
+
+	for _, nestedSR := range sr.FromNested {
+		nested, err := pd.matchSubmissionRequirement(nestedSR, matchedVCs)
+		if err != nil {
+			return nil, err
+		}
+
+		matched.Nested = append(matched.Nested, nested)
+	}
+
+	return matched, nil
+}
+
+// matchCredentials returns the original credentials matching each input descriptor.
+func (pd *PresentationDefinition) matchCredentials(credentials []*verifiable.Credential,
+	opts ...verifiable.CredentialOpt) (map[string][]*verifiable.Credential, error) {
+	result := make(map[string][]*verifiable.Credential)
+
+	for _, descriptor := range pd.InputDescriptors {
+		for _, credential := range filterSchema(descriptor.Schema, pd.filterFormat(credentials)) {
+			// constraints are checked for each credential separately, as filterConstraints may
+			// return a new (limited) credential instead of the original one.
+			filtered, err := filterConstraints(descriptor.Constraints, []*verifiable.Credential{credential}, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("input descriptor %s: %w", descriptor.ID, err)
+			}
+
+			if len(filtered) != 0 {
+				result[descriptor.ID] = append(result[descriptor.ID], credential)
+			}
+		}
+	}
+
+	pd.filterSameSubject(result)
+
+	return result, nil
+}
+
+func matchDescriptors(descriptors []*InputDescriptor,
+	matchedVCs map[string][]*verifiable.Credential) []*MatchedInputDescriptor {
+	result := make([]*MatchedInputDescriptor, 0, len(descriptors))
+
+	for _, descriptor := range descriptors {
+		result = append(result, &MatchedInputDescriptor{
+			ID:         descriptor.ID,
+			Name:       descriptor.Name,
+			Purpose:    descriptor.Purpose,
+			MatchedVCs: matchedVCs[descriptor.ID],
+		})
+	}
+
+	return result
+}
+
 // ErrNoCredentials when any credentials do not satisfy requirements.
 var ErrNoCredentials = errors.New("credentials do not satisfy requirements")
 
-// nolint: gocyclo,funlen,gocognit
-func applyRequirement(req *requirement, creds []*verifiable.Credential,
+// filterDescriptors returns the credentials matching each input descriptor (by schema and constraints).
+// The same_subject constraints are applied across the input descriptors.
+func (pd *PresentationDefinition) filterDescriptors(creds []*verifiable.Credential,
 	opts ...verifiable.CredentialOpt) (map[string][]*verifiable.Credential, error) {
 	result := make(map[string][]*verifiable.Credential)
 
-	for _, descriptor := range req.InputDescriptors {
+	for _, descriptor := range pd.InputDescriptors {
 		filtered := filterSchema(descriptor.Schema, creds)
 
 		filtered, err := filterConstraints(descriptor.Constraints, filtered, opts...)
@@ -355,6 +650,119 @@ func applyRequirement(req *requirement, creds []*verifiable.Credential,
 		}
 	}
 
+	pd.filterSameSubject(result)
+
+	return result, nil
+}
+
+// filterSameSubject keeps the credentials having the same subject for the input descriptors
+// containing the fields referred by required same_subject constraints.
+func (pd *PresentationDefinition) filterSameSubject(result map[string][]*verifiable.Credential) {
+	for _, descriptor := range pd.InputDescriptors {
+		if descriptor.Constraints == nil {
+			continue
+		}
+
+		for _, sameSubject := range descriptor.Constraints.SameSubject {
+			if !sameSubject.Directive.isRequired() {
+				continue
+			}
+
+			descriptorIDs := pd.descriptorsWithFields(sameSubject.FieldID)
+
+			// fields of a single input descriptor are always fields of the same credential.
+			if len(descriptorIDs) < 2 { // nolint: gomnd
+				continue
+			}
+
+			subjects := commonSubjects(descriptorIDs, result)
+
+			for _, id := range descriptorIDs {
+				var filtered []*verifiable.Credential
+
+				for _, credential := range result[id] {
+					if hasAnySubject(credential, subjects) {
+						filtered = append(filtered, credential)
+					}
+				}
+
+				if len(filtered) == 0 {
+					delete(result, id)
+
+					continue
+				}
+
+				result[id] = filtered
+			}
+		}
+	}
+}
+
+func (pd *PresentationDefinition) descriptorsWithFields(fieldIDs []string) []string {
+	var result []string
+
+	for _, descriptor := range pd.InputDescriptors {
+		if descriptor.Constraints == nil {
+			continue
+		}
+
+		for _, field := range descriptor.Constraints.Fields {
+			if field.ID != "" && contains(fieldIDs, field.ID) {
+				result = append(result, descriptor.ID)
+
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func commonSubjects(descriptorIDs []string, result map[string][]*verifiable.Credential) map[string]struct{} {
+	var common map[string]struct{}
+
+	for _, id := range descriptorIDs {
+		subjects := make(map[string]struct{})
+
+		for _, credential := range result[id] {
+			for _, subjectID := range getSubjectIDs(credential.Subject) {
+				if subjectID == "" {
+					continue
+				}
+
+				if _, ok := common[subjectID]; ok || common == nil {
+					subjects[subjectID] = struct{}{}
+				}
+			}
+		}
+
+		common = subjects
+	}
+
+	return common
+}
+
+func hasAnySubject(credential *verifiable.Credential, subjects map[string]struct{}) bool {
+	for _, subjectID := range getSubjectIDs(credential.Subject) {
+		if _, ok := subjects[subjectID]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nolint: gocyclo
+func applyRequirement(req *requirement,
+	filtered map[string][]*verifiable.Credential) (map[string][]*verifiable.Credential, error) {
+	result := make(map[string][]*verifiable.Credential)
+
+	for _, descriptor := range req.InputDescriptors {
+		if creds, ok := filtered[descriptor.ID]; ok {
+			result[descriptor.ID] = creds
+		}
+	}
+
 	if len(req.InputDescriptors) != 0 {
 		if req.isLenApplicable(len(result)) {
 			return result, nil
@@ -369,7 +777,7 @@ func applyRequirement(req *requirement, creds []*verifiable.Credential,
 	set := map[string]map[string]string{}
 
 	for _, r := range req.Nested {
-		res, err := applyRequirement(r, creds, opts...)
+		res, err := applyRequirement(r, filtered)
 		if errors.Is(err, ErrNoCredentials) {
 			continue
 		}
@@ -512,6 +920,10 @@ func filterConstraints(constraints *Constraints, creds []*verifiable.Credential,
 			continue
 		}
 
+		if !constraints.Statuses.isApplicable(credential, opts...) {
+			continue
+		}
+
 		var applicable bool
 
 		credentialSrc, err := json.Marshal(credential)
@@ -585,6 +997,45 @@ func filterConstraints(constraints *Constraints, creds []*verifiable.Credential,
 	return result, nil
 }
 
+// isApplicable checks the status of the credential against the status directives.
+func (s *Statuses) isApplicable(credential *verifiable.Credential, opts ...verifiable.CredentialOpt) bool {
+	if s == nil {
+		return true
+	}
+
+	var directive *StatusDirective
+
+	err := verifiable.CheckCredentialStatus(credential, opts...)
+
+	switch {
+	case err == nil:
+		directive = s.Active
+	case errors.Is(err, verifiable.ErrCredentialSuspended):
+		directive = s.Suspended
+	case errors.Is(err, verifiable.ErrCredentialRevoked):
+		directive = s.Revoked
+	default:
+		// the status is unknown, so the credential is applicable only if no status is required or disallowed.
+		return !s.hasDirective(Required) && !s.hasDirective(Disallowed)
+	}
+
+	if directive != nil && directive.Directive == Disallowed {
+		return false
+	}
+
+	return !s.hasDirective(Required) || (directive != nil && directive.Directive == Required)
+}
+
+func (s *Statuses) hasDirective(p Preference) bool {
+	for _, directive := range []*StatusDirective{s.Active, s.Suspended, s.Revoked} {
+		if directive != nil && directive.Directive == p {
+			return true
+		}
+	}
+
+	return false
+}
+
 func toSubject(subject interface{}) interface{} {
 	sub, ok := subject.([]verifiable.Subject)
 	if ok && len(sub) == 1 {
@@ -831,7 +1282,140 @@ func getPath(keys []interface{}, set map[string]int) [2]string {
 	return [...]string{strings.Join(newPath, "."), strings.Join(originalPath, ".")}
 }
 
-func merge(setOfCredentials map[string][]*verifiable.Credential) ([]*verifiable.Credential, []*InputDescriptorMapping) {
+// presentationFormat returns the format of the presentation to be submitted.
+// The JWT presentation is used only if the verifier is not able to process the linked data one.
+func (pd *PresentationDefinition) presentationFormat() string {
+	if pd.Format == nil {
+		return FormatLDPVP
+	}
+
+	if (pd.Format.JwtVP != nil || pd.Format.Jwt != nil) && pd.Format.LdpVP == nil && pd.Format.Ldp == nil {
+		return FormatJWTVP
+	}
+
+	return FormatLDPVP
+}
+
+// filterFormat filters credentials by the claim formats (algorithms and proof types) the verifier can process.
+func (pd *PresentationDefinition) filterFormat(credentials []*verifiable.Credential) []*verifiable.Credential {
+	format := pd.Format
+	if format == nil || (format.Jwt == nil && format.JwtVC == nil && format.Ldp == nil && format.LdpVC == nil) {
+		return credentials
+	}
+
+	var result []*verifiable.Credential
+
+	for _, credential := range credentials {
+		if credential.JWT != "" {
+			if jwtAlgAllowed(credential.JWT, format.Jwt) || jwtAlgAllowed(credential.JWT, format.JwtVC) {
+				result = append(result, credential)
+			}
+
+			continue
+		}
+
+		if proofTypeAllowed(credential, format.Ldp) || proofTypeAllowed(credential, format.LdpVC) {
+			result = append(result, credential)
+		}
+	}
+
+	return result
+}
+
+func jwtAlgAllowed(jwt string, jwtType *JwtType) bool {
+	if jwtType == nil {
+		return false
+	}
+
+	if len(jwtType.Alg) == 0 {
+		return true
+	}
+
+	headers, err := base64.RawURLEncoding.DecodeString(strings.Split(jwt, ".")[0])
+	if err != nil {
+		return false
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+
+	if err = json.Unmarshal(headers, &header); err != nil {
+		return false
+	}
+
+	return contains(jwtType.Alg, header.Alg)
+}
+
+func proofTypeAllowed(credential *verifiable.Credential, ldpType *LdpType) bool {
+	if ldpType == nil {
+		return false
+	}
+
+	if len(ldpType.ProofType) == 0 {
+		return true
+	}
+
+	for _, proof := range credential.Proofs {
+		if proofType, ok := proof["type"].(string); ok && contains(ldpType.ProofType, proofType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyFrame selectively discloses the credentials signed with BBS+ using the frame of the presentation definition.
+func applyFrame(frame map[string]interface{}, setOfCredentials map[string][]*verifiable.Credential,
+	opts ...verifiable.CredentialOpt) (map[string][]*verifiable.Credential, error) {
+	derived := make(map[*verifiable.Credential]*verifiable.Credential)
+	result := make(map[string][]*verifiable.Credential, len(setOfCredentials))
+
+	for descriptorID, credentials := range setOfCredentials {
+		for _, credential := range credentials {
+			if !hasBBS(credential) {
+				result[descriptorID] = append(result[descriptorID], credential)
+
+				continue
+			}
+
+			if _, ok := derived[credential]; !ok {
+				vc, err := credential.GenerateBBSSelectiveDisclosure(frame, []byte(uuid.New().String()), opts...)
+				if err != nil {
+					return nil, fmt.Errorf("apply frame: %w", err)
+				}
+
+				vc.ID = tmpID(credential.ID)
+				derived[credential] = vc
+			}
+
+			result[descriptorID] = append(result[descriptorID], derived[credential])
+		}
+	}
+
+	return result, nil
+}
+
+// presentationCredentials returns the options to add the credentials to the presentation
+// (credentials parsed from JWT are added as JWT).
+func presentationCredentials(credentials []*verifiable.Credential) []verifiable.CreatePresentationOpt {
+	opts := make([]verifiable.CreatePresentationOpt, 0, len(credentials))
+
+	for _, credential := range credentials {
+		if credential.JWT != "" {
+			opts = append(opts, verifiable.WithJWTCredentials(credential.JWT))
+
+			continue
+		}
+
+		opts = append(opts, verifiable.WithCredentials(credential))
+	}
+
+	return opts
+}
+
+func merge(format string,
+	setOfCredentials map[string][]*verifiable.Credential) ([]*verifiable.Credential, []*InputDescriptorMapping) {
 	setOfCreds := make(map[string]int)
 	setOfDescriptors := make(map[string]struct{})
 
@@ -858,12 +1442,8 @@ func merge(setOfCredentials map[string][]*verifiable.Credential) ([]*verifiable.
 			}
 
 			if _, ok := setOfDescriptors[fmt.Sprintf("%s-%s", credential.ID, credential.ID)]; !ok {
-				descriptors = append(descriptors, &InputDescriptorMapping{
-					ID: descriptorID,
-					// TODO: what format should be here?
-					Format: "ldp_vp",
-					Path:   fmt.Sprintf("$.verifiableCredential[%d]", setOfCreds[credential.ID]),
-				})
+				descriptors = append(descriptors, descriptorMapping(format, descriptorID,
+					credential, setOfCreds[credential.ID]))
 			}
 		}
 	}
@@ -873,6 +1453,35 @@ func merge(setOfCredentials map[string][]*verifiable.Credential) ([]*verifiable.
 	return result, descriptors
 }
 
+func descriptorMapping(format, descriptorID string, credential *verifiable.Credential,
+	idx int) *InputDescriptorMapping {
+	path := fmt.Sprintf("$.verifiableCredential[%d]", idx)
+
+	if format != FormatJWTVP {
+		return &InputDescriptorMapping{
+			ID:     descriptorID,
+			Format: FormatLDPVP,
+			Path:   path,
+		}
+	}
+
+	vcFormat := FormatLDPVC
+	if credential.JWT != "" {
+		vcFormat = FormatJWTVC
+	}
+
+	return &InputDescriptorMapping{
+		ID:     descriptorID,
+		Format: FormatJWTVP,
+		Path:   "$",
+		PathNested: &InputDescriptorMapping{
+			ID:     descriptorID,
+			Format: vcFormat,
+			Path:   path,
+		},
+	}
+}
+
 type byID []*InputDescriptorMapping
 
 func (a byID) Len() int           { return len(a) }
@@ -31,7 +31,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/bbsblssignature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const errMsgSchema = "credentials do not satisfy requirements"
@@ -1568,6 +1570,561 @@ func TestPresentationDefinition_CreateVP(t *testing.T) {
 	})
 }
 
+func TestPresentationDefinition_CreateVPStatuses(t *testing.T) {
+	newCredential := func(status string) *verifiable.Credential {
+		vc := &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      uuid.New().String(),
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			Issuer:  verifiable.Issuer{ID: uuid.New().String()},
+			Subject: uuid.New().String(),
+		}
+
+		if status != "" {
+			vc.Status = &verifiable.TypedID{ID: "http://example.com/status/" + status, Type: "test"}
+		}
+
+		return vc
+	}
+
+	active := newCredential("active")
+	suspended := newCredential("suspended")
+	revoked := newCredential("revoked")
+	credentials := []*verifiable.Credential{active, suspended, revoked}
+
+	statusChecker := verifiable.WithCredentialStatusChecker(func(vc *verifiable.Credential) error {
+		switch vc.Status.ID {
+		case "http://example.com/status/suspended":
+			return verifiable.ErrCredentialSuspended
+		case "http://example.com/status/revoked":
+			return verifiable.ErrCredentialRevoked
+		default:
+			return nil
+		}
+	})
+
+	newPD := func(statuses *Statuses) *PresentationDefinition {
+		return &PresentationDefinition{
+			ID: uuid.New().String(),
+			InputDescriptors: []*InputDescriptor{{
+				ID: uuid.New().String(),
+				Schema: []*Schema{{
+					URI: schemaURI,
+				}},
+				Constraints: &Constraints{
+					Statuses: statuses,
+					Fields: []*Field{{
+						Path: []string{"$.id"},
+					}},
+				},
+			}},
+		}
+	}
+
+	t.Run("Active is required", func(t *testing.T) {
+		pd := newPD(&Statuses{Active: &StatusDirective{Directive: Required}})
+
+		vp, err := pd.CreateVP(credentials, statusChecker)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 1)
+		require.Equal(t, active.ID, vp.Credentials()[0].(*verifiable.Credential).ID)
+
+		checkSubmission(t, vp, pd)
+		checkVP(t, vp)
+	})
+
+	t.Run("Revoked is disallowed", func(t *testing.T) {
+		pd := newPD(&Statuses{
+			Active:    &StatusDirective{Directive: Allowed},
+			Revoked:   &StatusDirective{Directive: Disallowed},
+			Suspended: &StatusDirective{Directive: Allowed},
+		})
+
+		vp, err := pd.CreateVP(credentials, statusChecker)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 2)
+
+		checkSubmission(t, vp, pd)
+		checkVP(t, vp)
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		vp, err := newPD(&Statuses{Active: &StatusDirective{Directive: Required}}).CreateVP(credentials)
+		require.EqualError(t, err, errMsgSchema)
+		require.Nil(t, vp)
+
+		vp, err = newPD(&Statuses{Active: &StatusDirective{Directive: Allowed}}).CreateVP(credentials)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 3)
+	})
+
+	t.Run("No status", func(t *testing.T) {
+		vp, err := newPD(&Statuses{Active: &StatusDirective{Directive: Required}}).CreateVP(
+			[]*verifiable.Credential{newCredential("")})
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 1)
+	})
+}
+
+func TestPresentationDefinition_CreateVPSameSubject(t *testing.T) {
+	newCredential := func(subjectID string, customFields map[string]interface{}) *verifiable.Credential {
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      uuid.New().String(),
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			Issuer:       verifiable.Issuer{ID: uuid.New().String()},
+			Subject:      []verifiable.Subject{{ID: subjectID}},
+			CustomFields: customFields,
+		}
+	}
+
+	newPD := func(directive Preference) *PresentationDefinition {
+		return &PresentationDefinition{
+			ID: uuid.New().String(),
+			InputDescriptors: []*InputDescriptor{{
+				ID: "name",
+				Schema: []*Schema{{
+					URI: schemaURI,
+				}},
+				Constraints: &Constraints{
+					Fields: []*Field{{
+						ID:   "name_field",
+						Path: []string{"$.name"},
+					}},
+					SameSubject: []*Holder{{
+						FieldID:   []string{"name_field", "age_field"},
+						Directive: &directive,
+					}},
+				},
+			}, {
+				ID: "age",
+				Schema: []*Schema{{
+					URI: schemaURI,
+				}},
+				Constraints: &Constraints{
+					Fields: []*Field{{
+						ID:   "age_field",
+						Path: []string{"$.age"},
+					}},
+				},
+			}},
+		}
+	}
+
+	alice := uuid.New().String()
+	bob := uuid.New().String()
+
+	aliceName := newCredential(alice, map[string]interface{}{"name": "Alice"})
+	bobName := newCredential(bob, map[string]interface{}{"name": "Bob"})
+	aliceAge := newCredential(alice, map[string]interface{}{"age": 30})
+
+	t.Run("Same subject is required", func(t *testing.T) {
+		pd := newPD(Required)
+
+		vp, err := pd.CreateVP([]*verifiable.Credential{aliceName, bobName, aliceAge})
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 2)
+
+		for _, vc := range vp.Credentials() {
+			require.NotEqual(t, bobName.ID, vc.(*verifiable.Credential).ID)
+		}
+
+		checkSubmission(t, vp, pd)
+		checkVP(t, vp)
+	})
+
+	t.Run("Same subject is preferred", func(t *testing.T) {
+		vp, err := newPD(Preferred).CreateVP([]*verifiable.Credential{aliceName, bobName, aliceAge})
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 3)
+	})
+
+	t.Run("No credentials of the same subject", func(t *testing.T) {
+		vp, err := newPD(Required).CreateVP([]*verifiable.Credential{bobName, aliceAge})
+		require.EqualError(t, err, errMsgSchema)
+		require.Nil(t, vp)
+	})
+}
+
+func TestPresentationDefinition_CreateVPFormat(t *testing.T) {
+	const (
+		// {"alg":"EdDSA","typ":"JWT"}
+		eddsaJWT = "eyJhbGciOiJFZERTQSIsInR5cCI6IkpXVCJ9.e30.c2lnbmF0dXJl"
+		// {"alg":"ES256K","typ":"JWT"}
+		es256kJWT = "eyJhbGciOiJFUzI1NksiLCJ0eXAiOiJKV1QifQ.e30.c2lnbmF0dXJl"
+	)
+
+	newCredential := func(jwt, proofType string) *verifiable.Credential {
+		vc := &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      uuid.New().String(),
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			Issuer:  verifiable.Issuer{ID: uuid.New().String()},
+			Subject: uuid.New().String(),
+			JWT:     jwt,
+		}
+
+		if proofType != "" {
+			vc.Proofs = []verifiable.Proof{{"type": proofType}}
+		}
+
+		return vc
+	}
+
+	newPD := func(format *Format) *PresentationDefinition {
+		return &PresentationDefinition{
+			ID:     uuid.New().String(),
+			Format: format,
+			InputDescriptors: []*InputDescriptor{{
+				ID: uuid.New().String(),
+				Schema: []*Schema{{
+					URI: schemaURI,
+				}},
+			}},
+		}
+	}
+
+	eddsaVC := newCredential(eddsaJWT, "")
+	es256kVC := newCredential(es256kJWT, "")
+	ed25519VC := newCredential("", "Ed25519Signature2018")
+	bbsVC := newCredential("", "BbsBlsSignature2020")
+	credentials := []*verifiable.Credential{eddsaVC, es256kVC, ed25519VC, bbsVC}
+
+	t.Run("ldp_vc proof type", func(t *testing.T) {
+		pd := newPD(&Format{LdpVC: &LdpType{ProofType: []string{"Ed25519Signature2018"}}})
+
+		vp, err := pd.CreateVP(credentials)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 1)
+		require.Equal(t, ed25519VC.ID, vp.Credentials()[0].(*verifiable.Credential).ID)
+
+		ps, ok := vp.CustomFields["presentation_submission"].(*PresentationSubmission)
+		require.True(t, ok)
+		require.Len(t, ps.DescriptorMap, 1)
+		require.Equal(t, FormatLDPVP, ps.DescriptorMap[0].Format)
+		require.Nil(t, ps.DescriptorMap[0].PathNested)
+
+		checkSubmission(t, vp, pd)
+	})
+
+	t.Run("jwt_vc alg", func(t *testing.T) {
+		pd := newPD(&Format{
+			JwtVC: &JwtType{Alg: []string{"EdDSA"}},
+			LdpVP: &LdpType{ProofType: []string{"Ed25519Signature2018"}},
+		})
+
+		vp, err := pd.CreateVP(credentials)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{eddsaJWT}, vp.Credentials())
+
+		checkSubmission(t, vp, pd)
+	})
+
+	t.Run("jwt and ldp", func(t *testing.T) {
+		vp, err := newPD(&Format{
+			Jwt: &JwtType{Alg: []string{"EdDSA", "ES256K"}},
+			Ldp: &LdpType{ProofType: []string{"Ed25519Signature2018", "BbsBlsSignature2020"}},
+		}).CreateVP(credentials)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 4)
+	})
+
+	t.Run("No credential format", func(t *testing.T) {
+		vp, err := newPD(&Format{LdpVP: &LdpType{ProofType: []string{"Ed25519Signature2018"}}}).CreateVP(credentials)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 4)
+	})
+
+	t.Run("jwt_vp", func(t *testing.T) {
+		pd := newPD(&Format{
+			JwtVP: &JwtType{Alg: []string{"EdDSA"}},
+			JwtVC: &JwtType{Alg: []string{"EdDSA"}},
+			LdpVC: &LdpType{ProofType: []string{"BbsBlsSignature2020"}},
+		})
+
+		vp, err := pd.CreateVP(credentials)
+		require.NoError(t, err)
+		require.Len(t, vp.Credentials(), 2)
+
+		ps, ok := vp.CustomFields["presentation_submission"].(*PresentationSubmission)
+		require.True(t, ok)
+		require.Len(t, ps.DescriptorMap, 2)
+
+		formats := map[string]bool{}
+
+		for _, mapping := range ps.DescriptorMap {
+			require.Equal(t, FormatJWTVP, mapping.Format)
+			require.Equal(t, "$", mapping.Path)
+			require.NotNil(t, mapping.PathNested)
+			require.Equal(t, mapping.ID, mapping.PathNested.ID)
+			require.True(t, strings.HasPrefix(mapping.PathNested.Path, "$.verifiableCredential["))
+
+			formats[mapping.PathNested.Format] = true
+		}
+
+		require.Equal(t, map[string]bool{FormatJWTVC: true, FormatLDPVC: true}, formats)
+
+		checkSubmission(t, vp, pd)
+	})
+
+	t.Run("signed jwt_vp", func(t *testing.T) {
+		pd := newPD(&Format{
+			JwtVP: &JwtType{Alg: []string{"EdDSA"}},
+			JwtVC: &JwtType{Alg: []string{"EdDSA"}},
+			LdpVC: &LdpType{ProofType: []string{"BbsBlsSignature2020"}},
+		})
+
+		signer, err := signature.NewSigner(kms.ED25519Type)
+		require.NoError(t, err)
+
+		jwtVC := newCredential("", "")
+		jwtVC.Issued = util.NewTime(time.Now())
+
+		vcClaims, err := jwtVC.JWTClaims(false)
+		require.NoError(t, err)
+
+		jwtVC.JWT, err = vcClaims.MarshalJWS(verifiable.EdDSA, signer, "did:example:issuer#key-1")
+		require.NoError(t, err)
+
+		jws, err := pd.CreateJWTVP([]*verifiable.Credential{jwtVC, es256kVC, ed25519VC, bbsVC}, &JWTVPSigningContext{
+			Holder:    "did:example:holder",
+			Audience:  []string{"did:example:verifier"},
+			KeyID:     "did:example:holder#key-1",
+			Algorithm: verifiable.EdDSA,
+			Signer:    signer,
+		})
+		require.NoError(t, err)
+
+		vp, err := verifiable.ParsePresentation([]byte(jws),
+			verifiable.WithPresPublicKeyFetcher(verifiable.SingleKey(signer.PublicKeyBytes(), kms.ED25519)),
+			verifiable.WithPresJSONLDDocumentLoader(createTestJSONLDDocumentLoader()))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:holder", vp.Holder)
+		require.Len(t, vp.Credentials(), 2)
+
+		submission, err := json.Marshal(vp.CustomFields["presentation_submission"])
+		require.NoError(t, err)
+
+		ps := &PresentationSubmission{}
+		require.NoError(t, json.Unmarshal(submission, ps))
+		require.Equal(t, pd.ID, ps.DefinitionID)
+		require.Len(t, ps.DescriptorMap, 2)
+
+		for _, mapping := range ps.DescriptorMap {
+			require.Equal(t, FormatJWTVP, mapping.Format)
+			require.NotNil(t, mapping.PathNested)
+		}
+	})
+
+	t.Run("signed jwt_vp (definition without format)", func(t *testing.T) {
+		signer, err := signature.NewSigner(kms.ED25519Type)
+		require.NoError(t, err)
+
+		jws, err := newPD(nil).CreateJWTVP(credentials, &JWTVPSigningContext{
+			Algorithm: verifiable.EdDSA,
+			Signer:    signer,
+		})
+		require.NoError(t, err)
+		require.Len(t, strings.Split(jws, "."), 3)
+	})
+
+	t.Run("signed jwt_vp (errors)", func(t *testing.T) {
+		signer, err := signature.NewSigner(kms.ED25519Type)
+		require.NoError(t, err)
+
+		sctx := &JWTVPSigningContext{Algorithm: verifiable.EdDSA, Signer: signer}
+
+		_, err = newPD(&Format{JwtVP: &JwtType{Alg: []string{"EdDSA"}}}).CreateJWTVP(credentials, nil)
+		require.EqualError(t, err, "signing context with signer is required")
+
+		_, err = newPD(&Format{LdpVP: &LdpType{ProofType: []string{"Ed25519Signature2018"}}}).
+			CreateJWTVP(credentials, sctx)
+		require.EqualError(t, err, "presentation definition does not accept jwt_vp format")
+
+		_, err = newPD(&Format{JwtVP: &JwtType{Alg: []string{"ES256K"}}}).CreateJWTVP(credentials, sctx)
+		require.EqualError(t, err, "signature algorithm is not accepted by jwt_vp format of presentation definition")
+
+		_, err = newPD(&Format{JwtVP: &JwtType{Alg: []string{"EdDSA"}}}).CreateJWTVP(credentials, &JWTVPSigningContext{
+			Algorithm: verifiable.JWSAlgorithm(-1),
+			Signer:    signer,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign jwt presentation")
+	})
+
+	t.Run("Frame is not applied to credentials without BBS+ signature", func(t *testing.T) {
+		pd := newPD(&Format{LdpVC: &LdpType{ProofType: []string{"Ed25519Signature2018"}}})
+		pd.Frame = map[string]interface{}{
+			"@context": []interface{}{verifiable.ContextURI},
+			"type":     []interface{}{verifiable.VCType},
+		}
+
+		vp, err := pd.CreateVP(credentials)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{ed25519VC}, vp.Credentials())
+	})
+}
+
+func TestPresentationDefinition_MatchSubmissionRequirement(t *testing.T) {
+	newCredential := func(customFields map[string]interface{}) *verifiable.Credential {
+		issuerID := uuid.New().String()
+
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      uuid.New().String(),
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			CustomFields: customFields,
+			Issuer:       verifiable.Issuer{ID: issuerID},
+			Subject:      []verifiable.Subject{{ID: issuerID}},
+		}
+	}
+
+	ageDescriptor := func(group string, min, max int) *InputDescriptor {
+		return &InputDescriptor{
+			ID:    uuid.New().String(),
+			Group: []string{group},
+			Schema: []*Schema{{
+				URI: schemaURI,
+			}},
+			Constraints: &Constraints{
+				Fields: []*Field{{
+					Path: []string{"$.age"},
+					Filter: &Filter{
+						Type:    &intFilterType,
+						Minimum: min,
+						Maximum: max,
+					},
+				}},
+			},
+		}
+	}
+
+	child := newCredential(map[string]interface{}{"age": 10})
+	teenager := newCredential(map[string]interface{}{"age": 15})
+	adult1 := newCredential(map[string]interface{}{"age": 20})
+	adult2 := newCredential(map[string]interface{}{"age": 21})
+	credentials := []*verifiable.Credential{child, teenager, adult1, adult2}
+
+	t.Run("Checks schema", func(t *testing.T) {
+		pd := &PresentationDefinition{ID: uuid.New().String()}
+
+		matched, err := pd.MatchSubmissionRequirement(nil)
+
+		require.EqualError(t, err, "presentation_definition: input_descriptors is required")
+		require.Nil(t, matched)
+	})
+
+	t.Run("No submission requirements", func(t *testing.T) {
+		pd := &PresentationDefinition{
+			ID: uuid.New().String(),
+			InputDescriptors: []*InputDescriptor{
+				ageDescriptor("adult", 18, 23),
+				ageDescriptor("child", 3, 12),
+			},
+		}
+
+		matched, err := pd.MatchSubmissionRequirement(credentials)
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		require.True(t, matched[0].Satisfied())
+		require.Equal(t, All, matched[0].Rule)
+		require.Len(t, matched[0].Descriptors, 2)
+		require.Equal(t, pd.InputDescriptors[0].ID, matched[0].Descriptors[0].ID)
+		require.Equal(t, []*verifiable.Credential{adult1, adult2}, matched[0].Descriptors[0].MatchedVCs)
+		require.Equal(t, []*verifiable.Credential{child}, matched[0].Descriptors[1].MatchedVCs)
+
+		matched, err = pd.MatchSubmissionRequirement([]*verifiable.Credential{adult1})
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		require.False(t, matched[0].Satisfied())
+		require.Empty(t, matched[0].Descriptors[1].MatchedVCs)
+	})
+
+	t.Run("Nested submission requirements", func(t *testing.T) {
+		pd := &PresentationDefinition{
+			ID: uuid.New().String(),
+			SubmissionRequirements: []*SubmissionRequirement{
+				{
+					Name: "adults",
+					Rule: All,
+					From: "adult",
+				},
+				{
+					Name:  "minors",
+					Rule:  Pick,
+					Count: 1,
+					FromNested: []*SubmissionRequirement{
+						{Rule: All, From: "teenager"},
+						{Rule: All, From: "child"},
+						{Rule: All, From: "infant"},
+					},
+				},
+			},
+			InputDescriptors: []*InputDescriptor{
+				ageDescriptor("adult", 18, 23),
+				ageDescriptor("teenager", 13, 17),
+				ageDescriptor("child", 3, 12),
+				ageDescriptor("infant", 0, 2),
+			},
+		}
+
+		matched, err := pd.MatchSubmissionRequirement(credentials)
+		require.NoError(t, err)
+		require.Len(t, matched, 2)
+
+		require.Equal(t, "adults", matched[0].Name)
+		require.True(t, matched[0].Satisfied())
+		require.Len(t, matched[0].Descriptors, 1)
+		require.Len(t, matched[0].Descriptors[0].MatchedVCs, 2)
+
+		require.Equal(t, "minors", matched[1].Name)
+		require.Empty(t, matched[1].Descriptors)
+		require.Len(t, matched[1].Nested, 3)
+		require.Equal(t, []*verifiable.Credential{teenager}, matched[1].Nested[0].Descriptors[0].MatchedVCs)
+		require.Equal(t, []*verifiable.Credential{child}, matched[1].Nested[1].Descriptors[0].MatchedVCs)
+		require.False(t, matched[1].Nested[2].Satisfied())
+		// two of the nested requirements are satisfied while exactly one should be picked
+		require.False(t, matched[1].Satisfied())
+
+		matched, err = pd.MatchSubmissionRequirement([]*verifiable.Credential{adult1, child})
+		require.NoError(t, err)
+		require.True(t, matched[0].Satisfied())
+		require.True(t, matched[1].Satisfied())
+	})
+
+	t.Run("Invalid group", func(t *testing.T) {
+		pd := &PresentationDefinition{
+			ID: uuid.New().String(),
+			SubmissionRequirements: []*SubmissionRequirement{{
+				Rule:       Pick,
+				Count:      1,
+				FromNested: []*SubmissionRequirement{{Rule: All, From: "unknown"}},
+			}},
+			InputDescriptors: []*InputDescriptor{ageDescriptor("adult", 18, 23)},
+		}
+
+		matched, err := pd.MatchSubmissionRequirement(credentials)
+		require.EqualError(t, err, "no descriptors for from: unknown")
+		require.Nil(t, matched)
+	})
+}
+
 func checkSubmission(t *testing.T, vp *verifiable.Presentation, pd *PresentationDefinition) {
 	t.Helper()
 
@@ -460,6 +460,9 @@ const DefinitionJSONSchema = `
                "items":{
                   "$ref":"#/definitions/input_descriptors"
                }
+            },
+            "frame":{
+               "type":"object"
             }
          },
          "required":[
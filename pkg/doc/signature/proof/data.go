@@ -73,7 +73,7 @@ func CreateVerifyHash(suite signatureSuite, jsonldDoc, proofOptions map[string]i
 
 	proofOptionsDigest := suite.GetDigest(canonicalProofOptions)
 
-	canonicalDoc, err := prepareCanonicalDocument(suite, jsonldDoc, opts...)
+	canonicalDoc, err := prepareCanonicalDocument(suite, jsonldDoc, proofOptions, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,10 +114,13 @@ func prepareCanonicalProofOptions(suite signatureSuite, proofOptions map[string]
 	return suite.GetCanonicalDocument(proofOptionsCopy, opts...)
 }
 
-func prepareCanonicalDocument(suite signatureSuite, jsonldObject map[string]interface{},
+func prepareCanonicalDocument(suite signatureSuite, jsonldObject, proofOptions map[string]interface{},
 	opts ...jsonld.ProcessorOpts) ([]byte, error) {
-	// copy document object without proof
-	docCopy := GetCopyWithoutProof(jsonldObject)
+	// copy document object without proof (or with previous proof only in case of proof chain)
+	docCopy, err := getCopyForProof(jsonldObject, proofOptions)
+	if err != nil {
+		return nil, err
+	}
 
 	// build canonical document
 	return suite.GetCanonicalDocument(docCopy, opts...)
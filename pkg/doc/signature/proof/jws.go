@@ -90,7 +90,7 @@ func createVerifyJWS(suite signatureSuite, jsonldDoc map[string]interface{}, p *
 
 	proofOptionsDigest := suite.GetDigest(canonicalProofOptions)
 
-	canonicalDoc, err := prepareDocumentForJWS(suite, jsonldDoc, opts...)
+	canonicalDoc, err := prepareDocumentForJWS(suite, jsonldDoc, proofOptions, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -123,20 +123,21 @@ func prepareJWSProof(suite signatureSuite, proofOptions map[string]interface{},
 	return suite.GetCanonicalDocument(proofOptionsCopy, append(opts, jsonld.WithDocumentLoaderCache(jsonldCache))...)
 }
 
-func prepareDocumentForJWS(suite signatureSuite, jsonldObject map[string]interface{},
+func prepareDocumentForJWS(suite signatureSuite, jsonldObject, proofOptions map[string]interface{},
 	opts ...jsonld.ProcessorOpts) ([]byte, error) {
-	// copy document object without proof
-	doc := GetCopyWithoutProof(jsonldObject)
+	// copy document object without proof (or with previous proof only in case of proof chain)
+	doc, err := getCopyForProof(jsonldObject, proofOptions)
+	if err != nil {
+		return nil, err
+	}
 
 	if suite.CompactProof() {
 		opts = append(opts, jsonld.WithDocumentLoaderCache(jsonldCache))
 
-		docCompacted, err := getCompactedWithSecuritySchema(doc, opts...)
+		doc, err = getCompactedWithSecuritySchema(doc, opts...)
 		if err != nil {
 			return nil, err
 		}
-
-		doc = docCompacted
 	}
 
 	// build canonical document
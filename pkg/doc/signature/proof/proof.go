@@ -36,10 +36,15 @@ const (
 	jsonldChallenge = "challenge"
 	// jsonldCapabilityChain is a key for capabilityChain.
 	jsonldCapabilityChain = "capabilityChain"
+	// jsonldID is a key for proof ID.
+	jsonldID = "id"
+	// jsonldPreviousProof is a key for the ID of the proof this proof is chained to.
+	jsonldPreviousProof = "previousProof"
 )
 
 // Proof is cryptographic proof of the integrity of the DID Document.
 type Proof struct {
+	ID                      string
 	Type                    string
 	Created                 *util.TimeWithTrailingZeroMsec
 	Creator                 string
@@ -53,6 +58,8 @@ type Proof struct {
 	SignatureRepresentation SignatureRepresentation
 	// CapabilityChain must be an array. Each element is either a string or an object.
 	CapabilityChain []interface{}
+	// PreviousProof is an ID of the proof this proof is chained to (if any).
+	PreviousProof string
 }
 
 // NewProof creates new proof.
@@ -97,6 +104,7 @@ func NewProof(emap map[string]interface{}) (*Proof, error) {
 	}
 
 	return &Proof{
+		ID:                      stringEntry(emap[jsonldID]),
 		Type:                    stringEntry(emap[jsonldType]),
 		Created:                 timeValue,
 		Creator:                 stringEntry(emap[jsonldCreator]),
@@ -109,6 +117,7 @@ func NewProof(emap map[string]interface{}) (*Proof, error) {
 		Nonce:                   nonce,
 		Challenge:               stringEntry(emap[jsonldChallenge]),
 		CapabilityChain:         capabilityChain,
+		PreviousProof:           stringEntry(emap[jsonldPreviousProof]),
 	}, nil
 }
 
@@ -157,6 +166,10 @@ func (p *Proof) JSONLdObject() map[string]interface{} { // nolint:gocyclo
 	emap := make(map[string]interface{})
 	emap[jsonldType] = p.Type
 
+	if p.ID != "" {
+		emap[jsonldID] = p.ID
+	}
+
 	if p.Creator != "" {
 		emap[jsonldCreator] = p.Creator
 	}
@@ -197,6 +210,10 @@ func (p *Proof) JSONLdObject() map[string]interface{} { // nolint:gocyclo
 		emap[jsonldCapabilityChain] = p.CapabilityChain
 	}
 
+	if p.PreviousProof != "" {
+		emap[jsonldPreviousProof] = p.PreviousProof
+	}
+
 	return emap
 }
 
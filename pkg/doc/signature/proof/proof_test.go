@@ -251,6 +251,26 @@ func TestProof_JSONLdObject(t *testing.T) {
 			r.NotContains(result, "capabilityChain")
 		})
 	})
+
+	t.Run("proof chain", func(t *testing.T) {
+		p := &Proof{
+			ID:            "urn:uuid:proof-2",
+			Type:          "Ed25519Signature2018",
+			Created:       util.NewTime(created),
+			Creator:       "creator",
+			ProofValue:    proofValueBytes,
+			PreviousProof: "urn:uuid:proof-1",
+		}
+
+		result := p.JSONLdObject()
+		r.Equal("urn:uuid:proof-2", result["id"])
+		r.Equal("urn:uuid:proof-1", result["previousProof"])
+
+		parsed, err := NewProof(result)
+		r.NoError(err)
+		r.Equal(p.ID, parsed.ID)
+		r.Equal(p.PreviousProof, parsed.PreviousProof)
+	})
 }
 
 func TestProof_PublicKeyID(t *testing.T) {
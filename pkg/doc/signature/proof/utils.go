@@ -7,6 +7,7 @@ package proof
 
 import (
 	"errors"
+	"fmt"
 )
 
 const (
@@ -87,5 +88,45 @@ func GetCopyWithoutProof(jsonLdObject map[string]interface{}) map[string]interfa
 	return dest
 }
 
+// GetCopyWithPreviousProof gets copy of JSON LD Object where "proof" holds only the proof with the given ID.
+// It is used to build the document secured by a chained proof, i.e. the one which defines "previousProof".
+func GetCopyWithPreviousProof(jsonLdObject map[string]interface{}, previousProofID string) (map[string]interface{},
+	error) {
+	dest := GetCopyWithoutProof(jsonLdObject)
+	if dest == nil {
+		return nil, nil
+	}
+
+	if _, ok := jsonLdObject[jsonldProof]; !ok {
+		return nil, fmt.Errorf("previous proof %s: %w", previousProofID, ErrProofNotFound)
+	}
+
+	proofs, err := GetProofs(jsonLdObject)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range proofs {
+		if p.ID != "" && p.ID == previousProofID {
+			dest[jsonldProof] = p.JSONLdObject()
+
+			return dest, nil
+		}
+	}
+
+	return nil, fmt.Errorf("previous proof %s: %w", previousProofID, ErrProofNotFound)
+}
+
+// getCopyForProof gets copy of JSON LD Object secured by the given proof options. Proofs are removed
+// unless the proof is chained (defines "previousProof"), in which case the previous proof is kept.
+func getCopyForProof(jsonLdObject, proofOptions map[string]interface{}) (map[string]interface{}, error) {
+	previousProofID, ok := proofOptions[jsonldPreviousProof].(string)
+	if !ok || previousProofID == "" {
+		return GetCopyWithoutProof(jsonLdObject), nil
+	}
+
+	return GetCopyWithPreviousProof(jsonLdObject, previousProofID)
+}
+
 // ErrProofNotFound is returned when proof is not found.
 var ErrProofNotFound = errors.New("proof not found")
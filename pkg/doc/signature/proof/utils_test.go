@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package proof
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -67,6 +68,64 @@ func TestGetCopyWithoutProof(t *testing.T) {
 	require.True(t, reflect.DeepEqual(docCopy, getDefaultDoc()))
 }
 
+func TestGetCopyWithPreviousProof(t *testing.T) {
+	doc := map[string]interface{}{
+		"test": "test",
+		"proof": []interface{}{
+			map[string]interface{}{
+				"id":         "urn:uuid:proof-1",
+				"type":       "Ed25519Signature2018",
+				"creator":    "creator-1",
+				"created":    "2011-09-23T20:21:34Z",
+				"proofValue": "ABC",
+			},
+			map[string]interface{}{
+				"id":            "urn:uuid:proof-2",
+				"type":          "Ed25519Signature2018",
+				"creator":       "creator-2",
+				"created":       "2011-09-23T20:21:34Z",
+				"proofValue":    "ABC",
+				"previousProof": "urn:uuid:proof-1",
+			},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		docCopy, err := GetCopyWithPreviousProof(doc, "urn:uuid:proof-1")
+		require.NoError(t, err)
+
+		proofs, err := GetProofs(docCopy)
+		require.NoError(t, err)
+		require.Len(t, proofs, 1)
+		require.Equal(t, "urn:uuid:proof-1", proofs[0].ID)
+		require.Equal(t, "creator-1", proofs[0].Creator)
+
+		docCopy, err = getCopyForProof(doc, map[string]interface{}{"previousProof": "urn:uuid:proof-1"})
+		require.NoError(t, err)
+		require.Contains(t, docCopy, "proof")
+
+		docCopy, err = getCopyForProof(doc, map[string]interface{}{})
+		require.NoError(t, err)
+		require.NotContains(t, docCopy, "proof")
+	})
+
+	t.Run("previous proof not found", func(t *testing.T) {
+		_, err := GetCopyWithPreviousProof(doc, "urn:uuid:proof-3")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrProofNotFound))
+
+		_, err = GetCopyWithPreviousProof(getDefaultDoc(), "urn:uuid:proof-1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrProofNotFound))
+	})
+
+	t.Run("nil document", func(t *testing.T) {
+		docCopy, err := GetCopyWithPreviousProof(nil, "urn:uuid:proof-1")
+		require.NoError(t, err)
+		require.Nil(t, docCopy)
+	})
+}
+
 func TestAddSingleProof(t *testing.T) {
 	doc := map[string]interface{}{
 		"test": "test",
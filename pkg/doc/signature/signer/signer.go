@@ -54,6 +54,8 @@ type Context struct {
 	Challenge               string                        // optional
 	Purpose                 string                        // optional
 	CapabilityChain         []interface{}                 // optional
+	ID                      string                        // optional
+	PreviousProof           string                        // optional
 }
 
 // New returns new instance of document verifier.
@@ -102,6 +104,7 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 	}
 
 	p := &proof.Proof{
+		ID:                      context.ID,
 		Type:                    context.SignatureType,
 		SignatureRepresentation: context.SignatureRepresentation,
 		Creator:                 context.Creator,
@@ -112,6 +115,7 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 		Challenge:               context.Challenge,
 		ProofPurpose:            context.Purpose,
 		CapabilityChain:         context.CapabilityChain,
+		PreviousProof:           context.PreviousProof,
 	}
 
 	// TODO support custom proof purpose
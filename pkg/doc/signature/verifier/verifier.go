@@ -39,6 +39,9 @@ type PublicKey struct {
 	Type  string
 	Value []byte
 	JWK   *jose.JWK
+	// Controller is the controller (e.g. DID) of the verification method as defined by the DID document
+	// the key is resolved from. It is empty if the resolver does not know it.
+	Controller string
 }
 
 // keyResolver encapsulates key resolution.
@@ -86,38 +89,123 @@ func (dv *DocumentVerifier) verifyObject(jsonLdObject map[string]interface{}, op
 	}
 
 	for _, p := range proofs {
-		publicKeyID, err := p.PublicKeyID()
+		_, err = dv.verifyProof(jsonLdObject, p, opts...)
 		if err != nil {
 			return err
 		}
+	}
 
-		publicKey, err := dv.pkResolver.Resolve(publicKeyID)
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		suite, err := dv.getSignatureSuite(p.Type)
-		if err != nil {
-			return err
-		}
+// ProofResult holds the result of a single proof verification.
+type ProofResult struct {
+	Proof *proof.Proof
+	// Controller is the controller of the verification method of the proof as resolved by the key resolver.
+	Controller string
+	// Err is nil if the proof is valid.
+	Err error
+}
 
-		message, err := proof.CreateVerifyData(suite, jsonLdObject, p, opts...)
-		if err != nil {
-			return err
+// VerifyEach verifies every proof of the document independently and returns a result per proof
+// in the order the proofs are defined in the document. A chained proof is invalid if its previous proof
+// is invalid. An error is returned only if the document or its proofs cannot be parsed.
+func (dv *DocumentVerifier) VerifyEach(jsonLdDoc []byte, opts ...jsonld.ProcessorOpts) ([]ProofResult, error) {
+	var jsonLdObject map[string]interface{}
+
+	err := json.Unmarshal(jsonLdDoc, &jsonLdObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json ld document: %w", err)
+	}
+
+	proofs, err := proof.GetProofs(jsonLdObject)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ProofResult, len(proofs))
+
+	for i, p := range proofs {
+		results[i] = ProofResult{Proof: p}
+
+		publicKey, verifyErr := dv.verifyProof(jsonLdObject, p, opts...)
+		if publicKey != nil {
+			results[i].Controller = publicKey.Controller
 		}
 
-		signature, err := getProofVerifyValue(p)
-		if err != nil {
-			return err
+		results[i].Err = verifyErr
+	}
+
+	failChainedProofs(results)
+
+	return results, nil
+}
+
+// failChainedProofs invalidates the proofs chained to an invalid previous proof, directly or transitively.
+func failChainedProofs(results []ProofResult) {
+	proofIndexes := make(map[string]int)
+
+	for i := range results {
+		id := results[i].Proof.ID
+		if _, ok := proofIndexes[id]; id != "" && !ok {
+			proofIndexes[id] = i
 		}
+	}
 
-		err = suite.Verify(publicKey, message, signature)
-		if err != nil {
-			return err
+	for changed := true; changed; {
+		changed = false
+
+		for i := range results {
+			r := &results[i]
+
+			if r.Err != nil || r.Proof.PreviousProof == "" {
+				continue
+			}
+
+			previous, ok := proofIndexes[r.Proof.PreviousProof]
+			if !ok || results[previous].Err == nil {
+				continue
+			}
+
+			r.Err = fmt.Errorf("previous proof %s is invalid: %w", r.Proof.PreviousProof, results[previous].Err)
+			changed = true
 		}
 	}
+}
 
-	return nil
+// verifyProof verifies the proof and returns the public key it was verified with (if resolved).
+func (dv *DocumentVerifier) verifyProof(jsonLdObject map[string]interface{}, p *proof.Proof,
+	opts ...jsonld.ProcessorOpts) (*PublicKey, error) {
+	if p.PreviousProof != "" && p.PreviousProof == p.ID {
+		return nil, fmt.Errorf("proof %s refers to itself as previous proof", p.ID)
+	}
+
+	publicKeyID, err := p.PublicKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := dv.pkResolver.Resolve(publicKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	suite, err := dv.getSignatureSuite(p.Type)
+	if err != nil {
+		return publicKey, err
+	}
+
+	message, err := proof.CreateVerifyData(suite, jsonLdObject, p, opts...)
+	if err != nil {
+		return publicKey, err
+	}
+
+	signature, err := getProofVerifyValue(p)
+	if err != nil {
+		return publicKey, err
+	}
+
+	return publicKey, suite.Verify(publicKey, message, signature)
 }
 
 // getSignatureSuite returns signature suite based on signature type.
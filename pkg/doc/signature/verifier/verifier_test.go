@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -107,6 +108,103 @@ func TestVerify(t *testing.T) {
 	require.Nil(t, v)
 }
 
+func TestVerifyEach(t *testing.T) {
+	okKeyResolver := &testKeyResolver{
+		publicKey: &PublicKey{
+			Type:  kms.ED25519,
+			Value: []byte("signature"),
+		},
+	}
+
+	t.Run("result per proof", func(t *testing.T) {
+		v, err := New(okKeyResolver, &testSignatureSuite{accept: true})
+		require.NoError(t, err)
+
+		results, err := v.VerifyEach([]byte(chainedProofsDoc))
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		require.NoError(t, results[0].Err)
+		require.Equal(t, "urn:uuid:proof-1", results[0].Proof.ID)
+
+		require.NoError(t, results[1].Err)
+		require.Equal(t, "urn:uuid:proof-1", results[1].Proof.PreviousProof)
+
+		require.Error(t, results[2].Err)
+		require.Contains(t, results[2].Err.Error(), "previous proof urn:uuid:unknown")
+	})
+
+	t.Run("chained proof of invalid previous proof is invalid", func(t *testing.T) {
+		v, err := New(mapKeyResolver{
+			"did:example:789#key1": {Type: kms.ED25519, Value: []byte("signature"), Controller: "did:example:789"},
+		}, &testSignatureSuite{accept: true})
+		require.NoError(t, err)
+
+		results, err := v.VerifyEach([]byte(chainedProofsDoc))
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		require.EqualError(t, results[0].Err, "key did:example:123456#key1 is not found")
+		require.Empty(t, results[0].Controller)
+
+		require.EqualError(t, results[1].Err, "previous proof urn:uuid:proof-1 is invalid: "+
+			"key did:example:123456#key1 is not found")
+		require.Equal(t, "did:example:789", results[1].Controller)
+	})
+
+	t.Run("proof refers to itself", func(t *testing.T) {
+		v, err := New(okKeyResolver, &testSignatureSuite{accept: true})
+		require.NoError(t, err)
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(validDoc), &doc))
+
+		p, ok := doc["proof"].(map[string]interface{})
+		require.True(t, ok)
+
+		p["id"] = "urn:uuid:proof-1"
+		p["previousProof"] = "urn:uuid:proof-1"
+
+		docBytes, err := json.Marshal(doc)
+		require.NoError(t, err)
+
+		results, err := v.VerifyEach(docBytes)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualError(t, results[0].Err, "proof urn:uuid:proof-1 refers to itself as previous proof")
+
+		require.Error(t, v.Verify(docBytes))
+	})
+
+	t.Run("verification error", func(t *testing.T) {
+		v, err := New(okKeyResolver, &testSignatureSuite{
+			verifyError: errors.New("verify data error"),
+			accept:      true,
+		})
+		require.NoError(t, err)
+
+		results, err := v.VerifyEach([]byte(validDoc))
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualError(t, results[0].Err, "verify data error")
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		v, err := New(okKeyResolver, &testSignatureSuite{accept: true})
+		require.NoError(t, err)
+
+		results, err := v.VerifyEach([]byte("not json"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal json ld document")
+		require.Nil(t, results)
+
+		results, err = v.VerifyEach([]byte("{}"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "proof not found")
+		require.Nil(t, results)
+	})
+}
+
 func Test_getProofVerifyValue(t *testing.T) {
 	jwsSignature := base64.RawURLEncoding.EncodeToString([]byte("signature"))
 
@@ -143,6 +241,16 @@ func (r *testKeyResolver) Resolve(string) (*PublicKey, error) {
 	return r.publicKey, r.err
 }
 
+type mapKeyResolver map[string]*PublicKey
+
+func (r mapKeyResolver) Resolve(id string) (*PublicKey, error) {
+	if publicKey, ok := r[id]; ok {
+		return publicKey, nil
+	}
+
+	return nil, fmt.Errorf("key %s is not found", id)
+}
+
 const validDoc = `
 {
   "@context": [
@@ -167,6 +275,41 @@ const validDoc = `
 }
 `
 
+const chainedProofsDoc = `
+{
+  "@context": [
+    "https://w3id.org/did/v1"
+  ],
+  "id": "did:example:123456789abcdefghi",
+  "created": "2002-10-10T17:00:00Z",
+  "proof": [
+    {
+      "id": "urn:uuid:proof-1",
+      "type": "Ed25519Signature2018",
+      "verificationMethod": "did:example:123456#key1",
+      "created": "2011-09-23T20:21:34Z",
+      "proofValue": "ABC"
+    },
+    {
+      "id": "urn:uuid:proof-2",
+      "type": "Ed25519Signature2018",
+      "verificationMethod": "did:example:789#key1",
+      "created": "2011-09-23T20:21:34Z",
+      "proofValue": "ABC",
+      "previousProof": "urn:uuid:proof-1"
+    },
+    {
+      "id": "urn:uuid:proof-3",
+      "type": "Ed25519Signature2018",
+      "verificationMethod": "did:example:789#key1",
+      "created": "2011-09-23T20:21:34Z",
+      "proofValue": "ABC",
+      "previousProof": "urn:uuid:unknown"
+    }
+  ]
+}
+`
+
 type testSignatureSuite struct {
 	canonicalDocument      []byte
 	canonicalDocumentError error
@@ -14,6 +14,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -22,6 +23,7 @@ import (
 	"github.com/piprate/json-gold/ld"
 	"github.com/xeipuuv/gojsonschema"
 
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 )
@@ -94,9 +96,10 @@ func (r *VDRKeyResolver) resolvePublicKey(issuerDID, keyID string) (*verifier.Pu
 		for _, verification := range verifications {
 			if strings.Contains(verification.VerificationMethod.ID, keyID) {
 				return &verifier.PublicKey{
-					Type:  verification.VerificationMethod.Type,
-					Value: verification.VerificationMethod.Value,
-					JWK:   verification.VerificationMethod.JSONWebKey(),
+					Type:       verification.VerificationMethod.Type,
+					Value:      verification.VerificationMethod.Value,
+					JWK:        verification.VerificationMethod.JSONWebKey(),
+					Controller: r.resolveController(docResolution.DIDDocument, &verification.VerificationMethod),
 				}, nil
 			}
 		}
@@ -105,6 +108,33 @@ func (r *VDRKeyResolver) resolvePublicKey(issuerDID, keyID string) (*verifier.Pu
 	return nil, fmt.Errorf("public key with KID %s is not found for DID %s", keyID, issuerDID)
 }
 
+// resolveController returns the controller of the verification method found in doc.
+// The controller declared by the verification method is self-asserted by doc, it is trusted only if the DID document
+// of the declared controller lists the method as an assertion or authentication method. Otherwise, the DID of doc
+// is the controller.
+func (r *VDRKeyResolver) resolveController(doc *did.Doc, vm *did.VerificationMethod) string {
+	if vm.Controller == "" || vm.Controller == doc.ID {
+		return doc.ID
+	}
+
+	docResolution, err := r.vdr.Resolve(vm.Controller)
+	if err != nil || docResolution.DIDDocument.ID != vm.Controller {
+		return doc.ID
+	}
+
+	methods := docResolution.DIDDocument.VerificationMethods(did.AssertionMethod, did.Authentication)
+
+	for _, verifications := range methods {
+		for _, verification := range verifications {
+			if verification.VerificationMethod.ID == vm.ID && bytes.Equal(verification.VerificationMethod.Value, vm.Value) {
+				return vm.Controller
+			}
+		}
+	}
+
+	return doc.ID
+}
+
 // PublicKeyFetcher returns Public Key Fetcher via DID resolution mechanism.
 func (r *VDRKeyResolver) PublicKeyFetcher() PublicKeyFetcher {
 	return r.resolvePublicKey
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr"
 )
@@ -253,6 +254,8 @@ func TestDIDKeyResolver_Resolve(t *testing.T) {
 	r.Equal("Ed25519VerificationKey2018", pubKey.Type)
 	r.NotNil(pubKey.JWK)
 	r.Equal(pubKey.JWK.Algorithm, "EdDSA")
+	// the controller declared by the key is not confirmed by its DID document
+	r.Equal(didDoc.ID, pubKey.Controller)
 
 	authPubKey, err := resolver.PublicKeyFetcher()(didDoc.ID, authentication.VerificationMethod.ID)
 	r.NoError(err)
@@ -278,6 +281,77 @@ func TestDIDKeyResolver_Resolve(t *testing.T) {
 	r.Nil(pubKey)
 }
 
+func TestDIDKeyResolver_ForeignController(t *testing.T) {
+	const (
+		attackerDID = "did:attacker:123"
+		issuerDID   = "did:example:issuer"
+	)
+
+	// the attacker claims the key is controlled by the issuer
+	vm := did.VerificationMethod{
+		ID:         attackerDID + "#key-1",
+		Type:       "Ed25519VerificationKey2018",
+		Controller: issuerDID,
+		Value:      []byte("attacker key"),
+	}
+
+	attackerDoc := &did.Doc{
+		ID:                 attackerDID,
+		VerificationMethod: []did.VerificationMethod{vm},
+		AssertionMethod:    []did.Verification{{VerificationMethod: vm, Relationship: did.AssertionMethod}},
+	}
+
+	issuerVM := did.VerificationMethod{
+		ID:         issuerDID + "#key-1",
+		Type:       "Ed25519VerificationKey2018",
+		Controller: issuerDID,
+		Value:      []byte("issuer key"),
+	}
+
+	issuerDoc := &did.Doc{
+		ID:                 issuerDID,
+		VerificationMethod: []did.VerificationMethod{issuerVM},
+		AssertionMethod:    []did.Verification{{VerificationMethod: issuerVM, Relationship: did.AssertionMethod}},
+	}
+
+	docs := map[string]*did.Doc{attackerDID: attackerDoc, issuerDID: issuerDoc}
+
+	resolver := NewVDRKeyResolver(&mockvdr.MockVDRegistry{
+		ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			doc, ok := docs[didID]
+			if !ok {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &did.DocResolution{DIDDocument: doc}, nil
+		},
+	})
+
+	t.Run("controller does not list the method", func(t *testing.T) {
+		pubKey, err := resolver.PublicKeyFetcher()(attackerDID, vm.ID)
+		require.NoError(t, err)
+		require.Equal(t, attackerDID, pubKey.Controller)
+	})
+
+	t.Run("controller cannot be resolved", func(t *testing.T) {
+		delete(docs, issuerDID)
+		defer func() { docs[issuerDID] = issuerDoc }()
+
+		pubKey, err := resolver.PublicKeyFetcher()(attackerDID, vm.ID)
+		require.NoError(t, err)
+		require.Equal(t, attackerDID, pubKey.Controller)
+	})
+
+	t.Run("controller lists the method", func(t *testing.T) {
+		issuerDoc.AssertionMethod = append(issuerDoc.AssertionMethod,
+			did.Verification{VerificationMethod: vm, Relationship: did.AssertionMethod, Embedded: true})
+
+		pubKey, err := resolver.PublicKeyFetcher()(attackerDID, vm.ID)
+		require.NoError(t, err)
+		require.Equal(t, issuerDID, pubKey.Controller)
+	})
+}
+
 //nolint:lll
 func createDIDDoc() *did.Doc {
 	didDocJSON := `{
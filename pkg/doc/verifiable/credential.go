@@ -509,6 +509,9 @@ type Credential struct {
 	TermsOfUse     []TypedID
 	RefreshService []TypedID
 
+	// JWT is the serialized JWT (JWS) the credential was parsed from (empty for JSON-LD credential).
+	JWT string
+
 	CustomFields CustomFields
 }
 
@@ -574,6 +577,8 @@ type credentialOpts struct {
 	disabledProofCheck    bool
 	strictValidation      bool
 	ldpSuites             []verifier.SignatureSuite
+	proofPolicy           *ProofPolicy
+	statusChecker         CredentialStatusChecker
 
 	jsonldCredentialOpts
 }
@@ -813,6 +818,10 @@ func ParseCredential(vcData []byte, opts ...CredentialOpt) (*Credential, error)
 		return nil, err
 	}
 
+	if jwt.IsJWS(string(vcData)) {
+		vc.JWT = string(vcData)
+	}
+
 	return vc, nil
 }
 
@@ -1039,6 +1048,7 @@ func getEmbeddedProofCheckOpts(vcOpts *credentialOpts) *embeddedProofCheckOpts {
 		publicKeyFetcher:     vcOpts.publicKeyFetcher,
 		disabledProofCheck:   vcOpts.disabledProofCheck,
 		ldpSuites:            vcOpts.ldpSuites,
+		proofPolicy:          vcOpts.proofPolicy,
 		jsonldCredentialOpts: vcOpts.jsonldCredentialOpts,
 	}
 }
@@ -57,28 +57,32 @@ func TestParseCredentialFromJWS(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("Decoding credential from JWS", func(t *testing.T) {
-		vcFromJWT, err := parseTestCredential(
-			createEdDSAJWS(t, testCred, ed25519Signer, false),
-			WithPublicKeyFetcher(ed25519KeyFetcher))
+		vcJWS := createEdDSAJWS(t, testCred, ed25519Signer, false)
+
+		vcFromJWT, err := parseTestCredential(vcJWS, WithPublicKeyFetcher(ed25519KeyFetcher))
 
 		require.NoError(t, err)
+		require.Equal(t, string(vcJWS), vcFromJWT.JWT)
 
 		vc, err := parseTestCredential(testCred)
 		require.NoError(t, err)
 
+		vc.JWT = vcFromJWT.JWT
 		require.Equal(t, vc, vcFromJWT)
 	})
 
 	t.Run("Decoding credential from JWS with minimized fields of \"vc\" claim", func(t *testing.T) {
-		vcFromJWT, err := parseTestCredential(
-			createEdDSAJWS(t, testCred, ed25519Signer, true),
-			WithPublicKeyFetcher(ed25519KeyFetcher))
+		vcJWS := createEdDSAJWS(t, testCred, ed25519Signer, true)
+
+		vcFromJWT, err := parseTestCredential(vcJWS, WithPublicKeyFetcher(ed25519KeyFetcher))
 
 		require.NoError(t, err)
+		require.Equal(t, string(vcJWS), vcFromJWT.JWT)
 
 		vc, err := parseTestCredential(testCred)
 		require.NoError(t, err)
 
+		vc.JWT = vcFromJWT.JWT
 		require.Equal(t, vc, vcFromJWT)
 	})
 
@@ -140,6 +144,8 @@ func TestParseCredentialFromJWS_EdDSA(t *testing.T) {
 	require.NoError(t, err)
 
 	// unmarshalled credential must be the same as original one
+	require.Equal(t, string(vcJWSStr), vcFromJWS.JWT)
+	vc.JWT = vcFromJWS.JWT
 	require.Equal(t, vc, vcFromJWS)
 }
 
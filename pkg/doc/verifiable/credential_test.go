@@ -1857,6 +1857,9 @@ func TestParseUnverifiedCredential(t *testing.T) {
 			WithDisabledProofCheck())
 		require.NoError(t, err)
 		require.NotNil(t, vcUnverified)
+		require.Equal(t, jws, vcUnverified.JWT)
+
+		vc.JWT = jws
 		require.Equal(t, vc, vcUnverified)
 	})
 
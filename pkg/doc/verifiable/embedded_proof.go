@@ -51,6 +51,8 @@ type embeddedProofCheckOpts struct {
 
 	ldpSuites []verifier.SignatureSuite
 
+	proofPolicy *ProofPolicy
+
 	jsonldCredentialOpts
 }
 
@@ -59,30 +61,99 @@ func checkEmbeddedProof(docBytes []byte, opts *embeddedProofCheckOpts) ([]byte,
 		return docBytes, nil
 	}
 
+	if opts.proofPolicy != nil {
+		results, signerID, err := verifyEmbeddedProofs(docBytes, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if results == nil {
+			// do not make a check if there is no proof defined as proof presence is not mandatory
+			return docBytes, nil
+		}
+
+		err = opts.proofPolicy.check(results, signerID)
+		if err != nil {
+			return nil, fmt.Errorf("check embedded proof: %w", err)
+		}
+
+		return docBytes, nil
+	}
+
+	jsonldDoc, proofs, err := getEmbeddedProofs(docBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if proofs == nil {
+		// do not make a check if there is no proof defined as proof presence is not mandatory
+		return docBytes, nil
+	}
+
+	checkedDoc, ldpSuites, err := prepareEmbeddedProofCheck(jsonldDoc, docBytes, proofs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkLinkedDataProof(checkedDoc, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
+	if err != nil {
+		return nil, fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	return docBytes, nil
+}
+
+// verifyEmbeddedProofs verifies each embedded linked data proof independently. It returns nil results
+// if the document has no proofs, and the ID of the document signer (VC issuer or VP holder).
+func verifyEmbeddedProofs(docBytes []byte, opts *embeddedProofCheckOpts) ([]ProofCheckResult, string, error) {
+	jsonldDoc, proofs, err := getEmbeddedProofs(docBytes)
+	if err != nil || proofs == nil {
+		return nil, "", err
+	}
+
+	checkedDoc, ldpSuites, err := prepareEmbeddedProofCheck(jsonldDoc, docBytes, proofs, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, err := checkLinkedDataProofs(checkedDoc, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
+	if err != nil {
+		return nil, "", fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	return results, getDocumentSignerID(jsonldDoc), nil
+}
+
+func getEmbeddedProofs(docBytes []byte) (map[string]interface{}, []map[string]interface{}, error) {
 	var jsonldDoc map[string]interface{}
 
 	if err := json.Unmarshal(docBytes, &jsonldDoc); err != nil {
-		return nil, fmt.Errorf("embedded proof is not JSON: %w", err)
+		return nil, nil, fmt.Errorf("embedded proof is not JSON: %w", err)
 	}
 
 	proofElement, ok := jsonldDoc["proof"]
 	if !ok || proofElement == nil {
 		// do not make a check if there is no proof defined as proof presence is not mandatory
-		return docBytes, nil
+		return jsonldDoc, nil, nil
 	}
 
 	proofs, err := getProofs(proofElement)
 	if err != nil {
-		return nil, fmt.Errorf("check embedded proof: %w", err)
+		return nil, nil, fmt.Errorf("check embedded proof: %w", err)
 	}
 
+	return jsonldDoc, proofs, nil
+}
+
+func prepareEmbeddedProofCheck(jsonldDoc map[string]interface{}, docBytes []byte,
+	proofs []map[string]interface{}, opts *embeddedProofCheckOpts) ([]byte, []verifier.SignatureSuite, error) {
 	ldpSuites, err := getSuites(proofs, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if opts.publicKeyFetcher == nil {
-		return nil, errors.New("public key fetcher is not defined")
+		return nil, nil, errors.New("public key fetcher is not defined")
 	}
 
 	checkedDoc := docBytes
@@ -93,12 +164,19 @@ func checkEmbeddedProof(docBytes []byte, opts *embeddedProofCheckOpts) ([]byte,
 		checkedDoc, _ = json.Marshal(jsonldDoc) //nolint:errcheck
 	}
 
-	err = checkLinkedDataProof(checkedDoc, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
-	if err != nil {
-		return nil, fmt.Errorf("check embedded proof: %w", err)
+	return checkedDoc, ldpSuites, nil
+}
+
+// getDocumentSignerID returns the ID of VC issuer or VP holder.
+func getDocumentSignerID(jsonldDoc map[string]interface{}) string {
+	switch issuer := jsonldDoc["issuer"].(type) {
+	case string:
+		return issuer
+	case map[string]interface{}:
+		return safeStringValue(issuer["id"])
 	}
 
-	return docBytes, nil
+	return safeStringValue(jsonldDoc["holder"])
 }
 
 func getSuites(proofs []map[string]interface{}, opts *embeddedProofCheckOpts) ([]verifier.SignatureSuite, error) {
@@ -382,8 +382,7 @@ func ExampleCredential_AddLinkedDataProof() {
 	//}
 }
 
-//nolint:govet
-func ExampleCredential_AddLinkedDataProofMultiProofs() {
+func ExampleCredential_AddLinkedDataProof_multipleProofs() {
 	log.SetLevel("aries-framework/json-ld-processor", spi.ERROR)
 
 	vc, err := verifiable.ParseCredential([]byte(vcJSON),
@@ -64,6 +64,12 @@ type LinkedDataProofContext struct {
 	Purpose                 string                  // optional
 	// CapabilityChain must be an array. Each element is either a string or an object.
 	CapabilityChain []interface{}
+	// ProofID is an ID of the proof, it must be an absolute URI (e.g. "urn:uuid:...").
+	// It is required if the proof is going to be referenced as a previous proof of a proof chain.
+	ProofID string // optional
+	// PreviousProof is an ID of the existing proof the new proof is chained to. If defined, the new proof
+	// secures the document together with the previous proof, i.e. it endorses the previous proof.
+	PreviousProof string // optional
 }
 
 func checkLinkedDataProof(jsonldBytes []byte, suites []verifier.SignatureSuite,
@@ -83,6 +89,29 @@ func checkLinkedDataProof(jsonldBytes []byte, suites []verifier.SignatureSuite,
 	return nil
 }
 
+func checkLinkedDataProofs(jsonldBytes []byte, suites []verifier.SignatureSuite,
+	pubKeyFetcher PublicKeyFetcher, jsonldOpts *jsonldCredentialOpts) ([]ProofCheckResult, error) {
+	documentVerifier, err := verifier.New(&keyResolverAdapter{pubKeyFetcher}, suites...)
+	if err != nil {
+		return nil, fmt.Errorf("create new signature verifier: %w", err)
+	}
+
+	processorOpts := mapJSONLDProcessorOpts(jsonldOpts)
+
+	proofResults, err := documentVerifier.VerifyEach(jsonldBytes, processorOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("check linked data proofs: %w", err)
+	}
+
+	results := make([]ProofCheckResult, len(proofResults))
+
+	for i, r := range proofResults {
+		results[i] = newProofCheckResult(r.Proof, r.Controller, r.Err)
+	}
+
+	return results, nil
+}
+
 func mapJSONLDProcessorOpts(jsonldOpts *jsonldCredentialOpts) []jsonld.ProcessorOpts {
 	var processorOpts []jsonld.ProcessorOpts
 
@@ -141,5 +170,7 @@ func mapContext(context *LinkedDataProofContext) *signer.Context {
 		Domain:                  context.Domain,
 		Purpose:                 context.Purpose,
 		CapabilityChain:         context.CapabilityChain,
+		ID:                      context.ProofID,
+		PreviousProof:           context.PreviousProof,
 	}
 }
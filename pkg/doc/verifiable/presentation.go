@@ -341,6 +341,7 @@ type presentationOpts struct {
 	strictValidation   bool
 	requireVC          bool
 	requireProof       bool
+	proofPolicy        *ProofPolicy
 
 	jsonldCredentialOpts
 }
@@ -576,12 +577,7 @@ func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *ra
 		return vcDataFromJwt, rawCred, nil
 	}
 
-	embeddedProofCheckOpts := &embeddedProofCheckOpts{
-		publicKeyFetcher:     vpOpts.publicKeyFetcher,
-		disabledProofCheck:   vpOpts.disabledProofCheck,
-		ldpSuites:            vpOpts.ldpSuites,
-		jsonldCredentialOpts: vpOpts.jsonldCredentialOpts,
-	}
+	embeddedProofCheckOpts := getPresEmbeddedProofCheckOpts(vpOpts)
 
 	if jwt.IsJWTUnsecured(vpStr) {
 		rawBytes, rawPres, err := decodeVPFromUnsecuredJWT(vpStr)
@@ -626,6 +622,16 @@ func decodeVPFromJSON(vpData []byte) ([]byte, *rawPresentation, error) {
 	return vpData, raw, nil
 }
 
+func getPresEmbeddedProofCheckOpts(vpOpts *presentationOpts) *embeddedProofCheckOpts {
+	return &embeddedProofCheckOpts{
+		publicKeyFetcher:     vpOpts.publicKeyFetcher,
+		disabledProofCheck:   vpOpts.disabledProofCheck,
+		ldpSuites:            vpOpts.ldpSuites,
+		proofPolicy:          vpOpts.proofPolicy,
+		jsonldCredentialOpts: vpOpts.jsonldCredentialOpts,
+	}
+}
+
 func defaultPresentationOpts() *presentationOpts {
 	return &presentationOpts{}
 }
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
+)
+
+// ProofCheckMode defines how many of the embedded linked data proofs must be valid.
+type ProofCheckMode int
+
+const (
+	// AllProofsValid requires every embedded proof to be valid (default).
+	AllProofsValid ProofCheckMode = iota
+
+	// AnyProofValid requires at least one of the embedded proofs to be valid.
+	AnyProofValid
+)
+
+// ProofPolicy describes which embedded linked data proofs (a proof set or a proof chain)
+// of VC or VP must be valid and by whom they must be made.
+type ProofPolicy struct {
+	// Mode defines whether all or at least one of the proofs must be valid.
+	Mode ProofCheckMode
+
+	// RequiredControllers is a list of controllers (e.g. DIDs) of verification methods.
+	// Each of them must have made at least one valid proof.
+	RequiredControllers []string
+
+	// RequireSignerProof requires at least one valid proof made by VC issuer (or VP holder).
+	RequireSignerProof bool
+}
+
+// ProofCheckResult is the result of verification of a single embedded linked data proof.
+type ProofCheckResult struct {
+	ID                 string
+	Type               string
+	VerificationMethod string
+	// Controller is the controller of the verification method (e.g. DID) as resolved from the DID document
+	// by the public key fetcher. It is empty if the fetcher does not define it (e.g. SingleKey).
+	Controller    string
+	PreviousProof string
+	// Err is nil if the proof is valid.
+	Err error
+}
+
+// Valid checks if the proof is valid.
+func (r *ProofCheckResult) Valid() bool {
+	return r.Err == nil
+}
+
+func newProofCheckResult(p *proof.Proof, controller string, err error) ProofCheckResult {
+	result := ProofCheckResult{Controller: controller, Err: err}
+
+	if p == nil {
+		return result
+	}
+
+	result.ID = p.ID
+	result.Type = p.Type
+	result.PreviousProof = p.PreviousProof
+
+	if publicKeyID, pkErr := p.PublicKeyID(); pkErr == nil {
+		result.VerificationMethod = publicKeyID
+	}
+
+	return result
+}
+
+// WithProofPolicy defines the policy of embedded linked data proofs check of VC.
+// If not defined, all proofs must be valid.
+func WithProofPolicy(policy *ProofPolicy) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.proofPolicy = policy
+	}
+}
+
+// WithPresProofPolicy defines the policy of embedded linked data proofs check of VP.
+// If not defined, all proofs must be valid.
+func WithPresProofPolicy(policy *ProofPolicy) PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.proofPolicy = policy
+	}
+}
+
+// CheckCredentialProofs verifies each embedded linked data proof of VC in JSON-LD form independently
+// and returns a result per proof. An error is returned if the proofs cannot be verified or if
+// the results do not satisfy the proof policy (defined by WithProofPolicy option, all proofs must
+// be valid by default); the per proof results are returned in the latter case as well.
+func CheckCredentialProofs(vcData []byte, opts ...CredentialOpt) ([]ProofCheckResult, error) {
+	vcOpts := getCredentialOpts(opts)
+
+	return checkProofs(vcData, getEmbeddedProofCheckOpts(vcOpts))
+}
+
+// CheckPresentationProofs verifies each embedded linked data proof of VP in JSON-LD form independently
+// and returns a result per proof. An error is returned if the proofs cannot be verified or if
+// the results do not satisfy the proof policy (defined by WithPresProofPolicy option, all proofs must
+// be valid by default); the per proof results are returned in the latter case as well.
+func CheckPresentationProofs(vpData []byte, opts ...PresentationOpt) ([]ProofCheckResult, error) {
+	vpOpts := getPresentationOpts(opts)
+
+	return checkProofs(vpData, getPresEmbeddedProofCheckOpts(vpOpts))
+}
+
+func checkProofs(docData []byte, opts *embeddedProofCheckOpts) ([]ProofCheckResult, error) {
+	results, signerID, err := verifyEmbeddedProofs(docData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if results == nil {
+		return nil, errors.New("embedded proof is missing")
+	}
+
+	policy := opts.proofPolicy
+	if policy == nil {
+		policy = &ProofPolicy{}
+	}
+
+	return results, policy.check(results, signerID)
+}
+
+func (p *ProofPolicy) check(results []ProofCheckResult, signerID string) error {
+	var validCount int
+
+	validControllers := make(map[string]bool)
+
+	for i := range results {
+		if !results[i].Valid() {
+			if p.Mode == AllProofsValid {
+				return fmt.Errorf("proof %s is invalid: %w", proofName(&results[i], i), results[i].Err)
+			}
+
+			continue
+		}
+
+		validCount++
+
+		if results[i].Controller != "" {
+			validControllers[results[i].Controller] = true
+		}
+	}
+
+	if validCount == 0 {
+		return errors.New("no valid proof found")
+	}
+
+	if p.RequireSignerProof && !validControllers[signerID] {
+		return fmt.Errorf("no valid proof made by %s", signerID)
+	}
+
+	for _, controller := range p.RequiredControllers {
+		if !validControllers[controller] {
+			return fmt.Errorf("no valid proof made by required controller %s", controller)
+		}
+	}
+
+	return nil
+}
+
+func proofName(r *ProofCheckResult, i int) string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return fmt.Sprintf("#%d", i)
+}
@@ -0,0 +1,360 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	sigverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	issuerDID   = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+	endorserDID = "did:example:endorser"
+)
+
+func TestCredential_AddLinkedDataProof_ProofChain(t *testing.T) {
+	r := require.New(t)
+
+	vc, pubKeyFetcher, issuerSigner := createVCWithProofChain(t)
+	r.Len(vc.Proofs, 2)
+	r.Equal("urn:uuid:issuer-proof", vc.Proofs[0]["id"])
+	r.Equal("urn:uuid:issuer-proof", vc.Proofs[1]["previousProof"])
+
+	vcBytes, err := json.Marshal(vc)
+	r.NoError(err)
+
+	t.Run("parse credential with proof chain", func(t *testing.T) {
+		vcParsed, err := parseTestCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher))
+		require.NoError(t, err)
+		require.Equal(t, vc, vcParsed)
+	})
+
+	t.Run("per proof results", func(t *testing.T) {
+		results, err := CheckCredentialProofs(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.True(t, results[0].Valid())
+		require.Equal(t, "urn:uuid:issuer-proof", results[0].ID)
+		require.Equal(t, issuerDID, results[0].Controller)
+		require.Equal(t, issuerDID+"#key1", results[0].VerificationMethod)
+		require.Equal(t, "Ed25519Signature2018", results[0].Type)
+
+		require.True(t, results[1].Valid())
+		require.Equal(t, endorserDID, results[1].Controller)
+		require.Equal(t, "urn:uuid:issuer-proof", results[1].PreviousProof)
+	})
+
+	t.Run("chained proof is broken if previous proof is removed", func(t *testing.T) {
+		var vcMap map[string]interface{}
+		require.NoError(t, json.Unmarshal(vcBytes, &vcMap))
+
+		proofs, ok := vcMap["proof"].([]interface{})
+		require.True(t, ok)
+
+		vcMap["proof"] = proofs[1:]
+
+		tamperedBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		_, err = parseTestCredential(tamperedBytes, WithPublicKeyFetcher(pubKeyFetcher))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "previous proof urn:uuid:issuer-proof")
+	})
+
+	t.Run("chained proof is broken if previous proof is changed", func(t *testing.T) {
+		vcCopy, err := parseTestCredential(vcBytes, WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		// replace issuer proof with new one having the same ID
+		vcCopy.Proofs = vcCopy.Proofs[1:]
+
+		err = vcCopy.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			Suite:                   ed25519signature2018.New(suite.WithSigner(issuerSigner)),
+			SignatureRepresentation: SignatureProofValue,
+			VerificationMethod:      issuerDID + "#key1",
+			ProofID:                 "urn:uuid:issuer-proof",
+		}, jsonld.WithDocumentLoader(testDocumentLoader))
+		require.NoError(t, err)
+
+		tamperedBytes, err := json.Marshal(vcCopy)
+		require.NoError(t, err)
+
+		results, err := CheckCredentialProofs(tamperedBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.Error(t, err)
+		require.Len(t, results, 2)
+		require.False(t, results[0].Valid())
+		require.True(t, results[1].Valid())
+
+		_, err = parseTestCredential(tamperedBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithProofPolicy(&ProofPolicy{Mode: AnyProofValid, RequireSignerProof: true}))
+		require.NoError(t, err)
+
+		_, err = parseTestCredential(tamperedBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithProofPolicy(&ProofPolicy{Mode: AnyProofValid, RequiredControllers: []string{endorserDID}}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no valid proof made by required controller "+endorserDID)
+	})
+
+	t.Run("add chained proof with unknown previous proof", func(t *testing.T) {
+		vcCopy, err := parseTestCredential(vcBytes, WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		err = vcCopy.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			Suite:                   ed25519signature2018.New(suite.WithSigner(issuerSigner)),
+			SignatureRepresentation: SignatureProofValue,
+			VerificationMethod:      issuerDID + "#key1",
+			PreviousProof:           "urn:uuid:unknown",
+		}, jsonld.WithDocumentLoader(testDocumentLoader))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "previous proof urn:uuid:unknown")
+	})
+}
+
+func TestProofPolicy(t *testing.T) {
+	vc, pubKeyFetcher, _ := createVCWithProofChain(t)
+
+	vcBytes, err := json.Marshal(vc)
+	require.NoError(t, err)
+
+	// the key of endorser is not resolved, so the chained proof is invalid
+	issuerOnlyFetcher := func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+		if issuerID != issuerDID {
+			return nil, errors.New("unknown key")
+		}
+
+		return pubKeyFetcher(issuerID, keyID)
+	}
+
+	tests := []struct {
+		name   string
+		policy *ProofPolicy
+		err    string
+	}{
+		{
+			name:   "all proofs must be valid",
+			policy: &ProofPolicy{},
+			err:    "proof urn:uuid:endorser-proof is invalid: unknown key",
+		},
+		{
+			name:   "any proof valid",
+			policy: &ProofPolicy{Mode: AnyProofValid},
+		},
+		{
+			name:   "any proof valid, issuer proof required",
+			policy: &ProofPolicy{Mode: AnyProofValid, RequireSignerProof: true},
+		},
+		{
+			name:   "any proof valid, proof of issuer required",
+			policy: &ProofPolicy{Mode: AnyProofValid, RequiredControllers: []string{issuerDID}},
+		},
+		{
+			name:   "any proof valid, proof of endorser required",
+			policy: &ProofPolicy{Mode: AnyProofValid, RequiredControllers: []string{issuerDID, endorserDID}},
+			err:    "no valid proof made by required controller " + endorserDID,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseTestCredential(vcBytes, WithPublicKeyFetcher(issuerOnlyFetcher), WithProofPolicy(tc.policy))
+
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("chained proof of invalid previous proof is invalid", func(t *testing.T) {
+		// the key of issuer is not resolved, so the endorsement of the issuer proof is invalid too
+		endorserOnlyFetcher := func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+			if issuerID != endorserDID {
+				return nil, errors.New("unknown key")
+			}
+
+			return pubKeyFetcher(issuerID, keyID)
+		}
+
+		results, err := CheckCredentialProofs(vcBytes, WithPublicKeyFetcher(endorserOnlyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader),
+			WithProofPolicy(&ProofPolicy{Mode: AnyProofValid, RequiredControllers: []string{endorserDID}}))
+		require.EqualError(t, err, "no valid proof found")
+		require.Len(t, results, 2)
+		require.False(t, results[1].Valid())
+		require.Equal(t, endorserDID, results[1].Controller)
+		require.EqualError(t, results[1].Err, "previous proof urn:uuid:issuer-proof is invalid: unknown key")
+	})
+
+	t.Run("controller is not derived from verification method", func(t *testing.T) {
+		// the fetcher resolves the key of a verification method controlled by another DID
+		_, err := parseTestCredential(vcBytes,
+			WithPublicKeyFetcher(func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+				pubKey, err := pubKeyFetcher(issuerID, keyID)
+				if err != nil {
+					return nil, err
+				}
+
+				pubKey.Controller = "did:example:other"
+
+				return pubKey, nil
+			}),
+			WithProofPolicy(&ProofPolicy{RequireSignerProof: true}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no valid proof made by "+issuerDID)
+	})
+
+	t.Run("no valid proofs", func(t *testing.T) {
+		_, err := parseTestCredential(vcBytes,
+			WithPublicKeyFetcher(func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+				return nil, errors.New("unknown key")
+			}),
+			WithProofPolicy(&ProofPolicy{Mode: AnyProofValid}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no valid proof found")
+	})
+
+	t.Run("signer proof is missing", func(t *testing.T) {
+		policy := &ProofPolicy{RequireSignerProof: true}
+
+		err := policy.check([]ProofCheckResult{{Controller: endorserDID}}, issuerDID)
+		require.EqualError(t, err, "no valid proof made by "+issuerDID)
+	})
+
+	t.Run("no proofs", func(t *testing.T) {
+		_, err := CheckCredentialProofs([]byte(validCredential), WithPublicKeyFetcher(pubKeyFetcher))
+		require.EqualError(t, err, "embedded proof is missing")
+
+		_, err = parseTestCredential([]byte(validCredential),
+			WithProofPolicy(&ProofPolicy{}), WithPublicKeyFetcher(pubKeyFetcher))
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := CheckCredentialProofs([]byte("not json"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "embedded proof is not JSON")
+	})
+}
+
+func TestCheckPresentationProofs(t *testing.T) {
+	r := require.New(t)
+
+	vp, err := newTestPresentation([]byte(validPresentation))
+	r.NoError(err)
+
+	holderSigner, err := newCryptoSigner(kms.ED25519Type)
+	r.NoError(err)
+
+	err = vp.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(holderSigner)),
+		SignatureRepresentation: SignatureJWS,
+		VerificationMethod:      vp.Holder + "#key1",
+		ProofID:                 "urn:uuid:holder-proof",
+	}, jsonld.WithDocumentLoader(testDocumentLoader))
+	r.NoError(err)
+
+	vpBytes, err := json.Marshal(vp)
+	r.NoError(err)
+
+	holderKeyFetcher := func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+		return &sigverifier.PublicKey{Type: kms.ED25519, Value: holderSigner.PublicKeyBytes(), Controller: vp.Holder}, nil
+	}
+
+	results, err := CheckPresentationProofs(vpBytes,
+		WithPresPublicKeyFetcher(holderKeyFetcher),
+		WithPresJSONLDDocumentLoader(testDocumentLoader),
+		WithPresProofPolicy(&ProofPolicy{RequireSignerProof: true}))
+	r.NoError(err)
+	r.Len(results, 1)
+	r.True(results[0].Valid())
+	r.Equal(vp.Holder, results[0].Controller)
+
+	_, err = newTestPresentation(vpBytes,
+		WithPresPublicKeyFetcher(holderKeyFetcher),
+		WithPresProofPolicy(&ProofPolicy{RequiredControllers: []string{issuerDID}}))
+	r.Error(err)
+	r.Contains(err.Error(), "no valid proof made by required controller "+issuerDID)
+
+	// the controller of a key fetched by SingleKey is unknown
+	_, err = newTestPresentation(vpBytes,
+		WithPresPublicKeyFetcher(SingleKey(holderSigner.PublicKeyBytes(), kms.ED25519)),
+		WithPresProofPolicy(&ProofPolicy{RequireSignerProof: true}))
+	r.Error(err)
+	r.Contains(err.Error(), "no valid proof made by "+vp.Holder)
+}
+
+func createVCWithProofChain(t *testing.T) (*Credential, PublicKeyFetcher, signature.Signer) {
+	t.Helper()
+
+	vc, err := parseTestCredential([]byte(validCredential), WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	issuerSigner, err := newCryptoSigner(kms.ED25519Type)
+	require.NoError(t, err)
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(issuerSigner)),
+		SignatureRepresentation: SignatureProofValue,
+		VerificationMethod:      issuerDID + "#key1",
+		ProofID:                 "urn:uuid:issuer-proof",
+	}, jsonld.WithDocumentLoader(testDocumentLoader))
+	require.NoError(t, err)
+
+	endorserSigner, err := newCryptoSigner(kms.ED25519Type)
+	require.NoError(t, err)
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(endorserSigner)),
+		SignatureRepresentation: SignatureJWS,
+		VerificationMethod:      endorserDID + "#key1",
+		ProofID:                 "urn:uuid:endorser-proof",
+		PreviousProof:           "urn:uuid:issuer-proof",
+	}, jsonld.WithDocumentLoader(testDocumentLoader))
+	require.NoError(t, err)
+
+	return vc, func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+		switch issuerID {
+		case issuerDID:
+			return &sigverifier.PublicKey{
+				Type:       kms.ED25519,
+				Value:      issuerSigner.PublicKeyBytes(),
+				Controller: issuerDID,
+			}, nil
+		case endorserDID:
+			return &sigverifier.PublicKey{
+				Type:       kms.ED25519,
+				Value:      endorserSigner.PublicKeyBytes(),
+				Controller: endorserDID,
+			}, nil
+		}
+
+		return nil, errors.New("unknown key")
+	}, issuerSigner
+}
@@ -0,0 +1,567 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+)
+
+// VerificationCheckStatus is a status of the single verification check.
+type VerificationCheckStatus string
+
+const (
+	// CheckPassed means that the check was made and succeeded.
+	CheckPassed VerificationCheckStatus = "passed"
+
+	// CheckWarning means that the check was made and found an issue which does not make VC/VP invalid.
+	CheckWarning VerificationCheckStatus = "warning"
+
+	// CheckFailed means that the check was made and failed, VC/VP is not valid.
+	CheckFailed VerificationCheckStatus = "failed"
+
+	// CheckSkipped means that the check was not made (e.g. it is disabled by option or not applicable).
+	CheckSkipped VerificationCheckStatus = "skipped"
+)
+
+// Names of the verification checks.
+const (
+	// SyntaxCheck checks that VC/VP can be decoded.
+	SyntaxCheck = "syntax"
+
+	// SchemaCheck checks VC/VP against JSON Schema (or base context constraints).
+	SchemaCheck = "schema"
+
+	// JSONLDCheck checks that VC/VP is a valid JSON-LD document.
+	JSONLDCheck = "jsonld"
+
+	// ProofCheck checks a single proof of VC/VP (an embedded linked data proof or JWS).
+	ProofCheck = "proof"
+
+	// ProofPolicyCheck checks that the proofs satisfy the proof policy (see ProofPolicy).
+	ProofPolicyCheck = "proofPolicy"
+
+	// IssuanceDateCheck checks that VC is already issued.
+	IssuanceDateCheck = "issuanceDate"
+
+	// ExpirationDateCheck checks that VC is not expired.
+	ExpirationDateCheck = "expirationDate"
+
+	// StatusCheck checks the status (e.g. revocation) of VC.
+	StatusCheck = "status"
+
+	// HolderBindingCheck checks that VP is made by the holder and the holder is the subject of VCs.
+	HolderBindingCheck = "holderBinding"
+)
+
+// VerificationCheck is a result of the single verification check.
+type VerificationCheck struct {
+	Check  string                  `json:"check"`
+	Status VerificationCheckStatus `json:"status"`
+	// Target specifies the checked item if the check is made several times (e.g. proof ID).
+	Target  string `json:"target,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// VerificationReport is a structured report of VC or VP verification.
+type VerificationReport struct {
+	// Verified is true if none of the checks (including the checks of presentation's credentials) failed.
+	Verified bool                 `json:"verified"`
+	ID       string               `json:"id,omitempty"`
+	Checks   []*VerificationCheck `json:"checks"`
+	// Credentials holds reports of the credentials of the presentation.
+	Credentials []*VerificationReport `json:"credentials,omitempty"`
+}
+
+// Errors returns the failed checks of the report (not including the credentials of the presentation).
+func (r *VerificationReport) Errors() []*VerificationCheck {
+	return r.filter(CheckFailed)
+}
+
+// Warnings returns the checks with warnings (not including the credentials of the presentation).
+func (r *VerificationReport) Warnings() []*VerificationCheck {
+	return r.filter(CheckWarning)
+}
+
+// Err returns an error which combines the messages of all failed checks (including the checks of
+// presentation's credentials) or nil if the verification succeeded.
+func (r *VerificationReport) Err() error {
+	if r.Verified {
+		return nil
+	}
+
+	var msgs []string
+
+	for _, c := range r.Errors() {
+		msgs = append(msgs, c.String())
+	}
+
+	for i, cr := range r.Credentials {
+		if err := cr.Err(); err != nil {
+			msgs = append(msgs, fmt.Sprintf("credential #%d: %s", i, err.Error()))
+		}
+	}
+
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+func (c *VerificationCheck) String() string {
+	s := c.Check
+
+	if c.Target != "" {
+		s += " " + c.Target
+	}
+
+	if c.Message != "" {
+		s += ": " + c.Message
+	}
+
+	return s
+}
+
+func (r *VerificationReport) filter(status VerificationCheckStatus) []*VerificationCheck {
+	var checks []*VerificationCheck
+
+	for _, c := range r.Checks {
+		if c.Status == status {
+			checks = append(checks, c)
+		}
+	}
+
+	return checks
+}
+
+func (r *VerificationReport) add(check, target string, status VerificationCheckStatus, msg string) {
+	r.Checks = append(r.Checks, &VerificationCheck{
+		Check:   check,
+		Status:  status,
+		Target:  target,
+		Message: msg,
+	})
+}
+
+func (r *VerificationReport) addResult(check, target string, err error) {
+	if err != nil {
+		r.add(check, target, CheckFailed, err.Error())
+
+		return
+	}
+
+	r.add(check, target, CheckPassed, "")
+}
+
+func (r *VerificationReport) complete() *VerificationReport {
+	r.Verified = len(r.Errors()) == 0
+
+	for _, cr := range r.Credentials {
+		r.Verified = r.Verified && cr.Verified
+	}
+
+	return r
+}
+
+// Errors of VC status check.
+var (
+	// ErrCredentialSuspended is returned by CredentialStatusChecker for suspended VC.
+	ErrCredentialSuspended = errors.New("credential is suspended")
+
+	// ErrCredentialRevoked is returned by CredentialStatusChecker for revoked VC.
+	ErrCredentialRevoked = errors.New("credential is revoked")
+
+	// ErrCredentialStatusUnknown is returned by CheckCredentialStatus if VC has "credentialStatus"
+	// but no CredentialStatusChecker is defined.
+	ErrCredentialStatusUnknown = errors.New("credential status is unknown")
+)
+
+// CredentialStatusChecker checks the status (e.g. revocation) of VC defined by "credentialStatus" field.
+// It returns nil for active VC, ErrCredentialSuspended (or ErrCredentialRevoked) wrapped in the error
+// for suspended (or revoked) VC and any other error if the status cannot be checked.
+type CredentialStatusChecker func(vc *Credential) error
+
+// WithCredentialStatusChecker defines a checker of VC status used by VerifyCredential and CheckCredentialStatus.
+// If not defined, the status of VC having "credentialStatus" is reported as a warning.
+func WithCredentialStatusChecker(checker CredentialStatusChecker) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.statusChecker = checker
+	}
+}
+
+// CheckCredentialStatus checks the status of VC using the checker defined by WithCredentialStatusChecker option.
+// VC without "credentialStatus" is considered to be active (nil is returned). ErrCredentialStatusUnknown
+// is returned if VC has "credentialStatus" but the checker is not defined.
+func CheckCredentialStatus(vc *Credential, opts ...CredentialOpt) error {
+	if vc.Status == nil {
+		return nil
+	}
+
+	checker := getCredentialOpts(opts).statusChecker
+	if checker == nil {
+		return ErrCredentialStatusUnknown
+	}
+
+	return checker(vc)
+}
+
+// VerifyCredential verifies VC defined as JSON-LD or JWT and returns a structured verification report
+// with the individual checks: syntax, schema, JSON-LD validity, each proof, issuance and expiration dates
+// and status. The options are the same as used by ParseCredential.
+func VerifyCredential(vcData []byte, opts ...CredentialOpt) *VerificationReport {
+	return verifyCredential(vcData, getCredentialOpts(opts))
+}
+
+func verifyCredential(vcData []byte, vcOpts *credentialOpts) *VerificationReport {
+	r := &VerificationReport{}
+
+	vcStr := string(vcData)
+	isJWS := jwt.IsJWS(vcStr)
+
+	vcBytes, vc, err := decodeCredentialForReport(vcStr, isJWS)
+	if err != nil {
+		r.addResult(SyntaxCheck, "", err)
+
+		return r.complete()
+	}
+
+	r.ID = vc.ID
+	r.addResult(SyntaxCheck, "", nil)
+
+	r.addCredentialModelChecks(vc, vcBytes, vcOpts)
+
+	if isJWS {
+		r.addJWSProofCheck(func() error {
+			_, e := decodeCredJWS(vcStr, true, vcOpts.publicKeyFetcher)
+
+			return e
+		}, vcOpts.publicKeyFetcher, vcOpts.disabledProofCheck)
+	} else {
+		r.addEmbeddedProofChecks(vcBytes, getEmbeddedProofCheckOpts(vcOpts))
+	}
+
+	r.addDateChecks(vc, time.Now())
+	r.addStatusCheck(vc, vcOpts.statusChecker)
+
+	return r.complete()
+}
+
+func decodeCredentialForReport(vcStr string, isJWS bool) ([]byte, *Credential, error) {
+	var (
+		vcBytes []byte
+		err     error
+	)
+
+	switch {
+	case isJWS:
+		vcBytes, err = decodeCredJWS(vcStr, false, nil)
+	case jwt.IsJWTUnsecured(vcStr):
+		vcBytes, err = decodeCredJWTUnsecured(vcStr)
+	default:
+		vcBytes = []byte(vcStr)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode credential: %w", err)
+	}
+
+	var raw rawCredential
+
+	err = json.Unmarshal(vcBytes, &raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	vc, err := newCredential(&raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build credential: %w", err)
+	}
+
+	return vcBytes, vc, nil
+}
+
+func (r *VerificationReport) addCredentialModelChecks(vc *Credential, vcBytes []byte, vcOpts *credentialOpts) {
+	switch vcOpts.modelValidationMode {
+	case combinedValidation:
+		r.addResult(SchemaCheck, "", vc.validateJSONSchema(vcBytes, vcOpts))
+		r.addResult(JSONLDCheck, "", vc.validateJSONLD(vcBytes, vcOpts))
+	case jsonldValidation:
+		r.add(SchemaCheck, "", CheckSkipped, "")
+		r.addResult(JSONLDCheck, "", vc.validateJSONLD(vcBytes, vcOpts))
+	default:
+		r.addResult(SchemaCheck, "", validateCredential(vc, vcBytes, vcOpts))
+		r.add(JSONLDCheck, "", CheckSkipped, "")
+	}
+}
+
+func (r *VerificationReport) addJWSProofCheck(check func() error, fetcher PublicKeyFetcher, disabled bool) {
+	switch {
+	case disabled:
+		r.add(ProofCheck, "jws", CheckSkipped, "")
+	case fetcher == nil:
+		r.add(ProofCheck, "jws", CheckFailed, "public key fetcher is not defined")
+	default:
+		r.addResult(ProofCheck, "jws", check())
+	}
+}
+
+// addEmbeddedProofChecks adds a check per embedded linked data proof and returns the controllers
+// of verification methods of the valid proofs.
+func (r *VerificationReport) addEmbeddedProofChecks(docBytes []byte, opts *embeddedProofCheckOpts) []string {
+	if opts.disabledProofCheck {
+		r.add(ProofCheck, "", CheckSkipped, "")
+
+		return nil
+	}
+
+	results, signerID, err := verifyEmbeddedProofs(docBytes, opts)
+	if err != nil {
+		r.addResult(ProofCheck, "", err)
+
+		return nil
+	}
+
+	if results == nil {
+		r.add(ProofCheck, "", CheckFailed, "embedded proof is missing")
+
+		return nil
+	}
+
+	var signers []string
+
+	for i := range results {
+		target := results[i].VerificationMethod
+		if results[i].ID != "" {
+			target = results[i].ID
+		}
+
+		r.addResult(ProofCheck, target, results[i].Err)
+
+		if results[i].Valid() {
+			signers = append(signers, results[i].Controller)
+		}
+	}
+
+	policy := opts.proofPolicy
+	if policy == nil {
+		policy = &ProofPolicy{}
+	}
+
+	r.addResult(ProofPolicyCheck, "", policy.check(results, signerID))
+
+	return signers
+}
+
+func (r *VerificationReport) addDateChecks(vc *Credential, now time.Time) {
+	switch {
+	case vc.Issued == nil:
+		r.add(IssuanceDateCheck, "", CheckWarning, "issuance date is not defined")
+	case vc.Issued.After(now):
+		r.add(IssuanceDateCheck, "", CheckFailed,
+			fmt.Sprintf("credential is issued in the future (%s)", vc.Issued.Format(time.RFC3339)))
+	default:
+		r.add(IssuanceDateCheck, "", CheckPassed, "")
+	}
+
+	switch {
+	case vc.Expired == nil:
+		r.add(ExpirationDateCheck, "", CheckSkipped, "")
+	case vc.Expired.Before(now):
+		r.add(ExpirationDateCheck, "", CheckFailed,
+			fmt.Sprintf("credential expired at %s", vc.Expired.Format(time.RFC3339)))
+	default:
+		r.add(ExpirationDateCheck, "", CheckPassed, "")
+	}
+}
+
+func (r *VerificationReport) addStatusCheck(vc *Credential, checker CredentialStatusChecker) {
+	switch {
+	case vc.Status == nil:
+		r.add(StatusCheck, "", CheckSkipped, "")
+	case checker == nil:
+		r.add(StatusCheck, vc.Status.ID, CheckWarning,
+			fmt.Sprintf("status of type %s is not checked", vc.Status.Type))
+	default:
+		r.addResult(StatusCheck, vc.Status.ID, checker(vc))
+	}
+}
+
+// VerifyPresentation verifies VP defined as JSON-LD or JWT and returns a structured verification report
+// with the individual checks: syntax, schema, JSON-LD validity, each proof and holder binding. The report
+// includes the verification reports of VP credentials. The options are the same as used by ParsePresentation,
+// the credentials are verified using the same public key fetcher, signature suites and JSON-LD options.
+func VerifyPresentation(vpData []byte, opts ...PresentationOpt) *VerificationReport {
+	vpOpts := getPresentationOpts(opts)
+	r := &VerificationReport{}
+
+	vpStr := string(vpData)
+	isJWS := jwt.IsJWS(vpStr)
+
+	vpBytes, vpRaw, err := decodePresentationForReport(vpStr, isJWS)
+	if err != nil {
+		r.addResult(SyntaxCheck, "", err)
+
+		return r.complete()
+	}
+
+	r.ID = vpRaw.ID
+	r.addResult(SyntaxCheck, "", nil)
+
+	r.addResult(SchemaCheck, "", validateVPJSONSchema(vpBytes))
+	r.addResult(JSONLDCheck, "", validateVPJSONLD(vpBytes, vpOpts))
+
+	var signers []string
+
+	if isJWS {
+		r.addJWSProofCheck(func() error {
+			_, _, e := decodeVPFromJWS(vpStr, true, vpOpts.publicKeyFetcher)
+
+			return e
+		}, vpOpts.publicKeyFetcher, vpOpts.disabledProofCheck)
+	} else {
+		signers = r.addEmbeddedProofChecks(vpBytes, getPresEmbeddedProofCheckOpts(vpOpts))
+	}
+
+	vcOpts := mapOpts(vpOpts)
+	vcOpts.jsonldCredentialOpts = vpOpts.jsonldCredentialOpts
+	vcOpts.schemaLoader = newDefaultSchemaLoader()
+
+	var vcs []*Credential
+
+	for _, rawVC := range rawPresentationCredentials(vpRaw.Credential) {
+		vcReport := verifyCredential(rawVC, vcOpts)
+		r.Credentials = append(r.Credentials, vcReport)
+
+		if _, vc, e := decodeCredentialForReport(string(rawVC), jwt.IsJWS(string(rawVC))); e == nil {
+			vcs = append(vcs, vc)
+		}
+	}
+
+	r.addHolderBindingChecks(vpRaw.Holder, signers, vcs, isJWS)
+
+	return r.complete()
+}
+
+func decodePresentationForReport(vpStr string, isJWS bool) ([]byte, *rawPresentation, error) {
+	var (
+		vpBytes []byte
+		vpRaw   *rawPresentation
+		err     error
+	)
+
+	switch {
+	case isJWS:
+		vpBytes, vpRaw, err = decodeVPFromJWS(vpStr, false, nil)
+	case jwt.IsJWTUnsecured(vpStr):
+		vpBytes, vpRaw, err = decodeVPFromUnsecuredJWT(vpStr)
+	default:
+		vpBytes, vpRaw, err = decodeVPFromJSON([]byte(vpStr))
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode presentation: %w", err)
+	}
+
+	return vpBytes, vpRaw, nil
+}
+
+func rawPresentationCredentials(rawCred interface{}) [][]byte {
+	var creds []interface{}
+
+	switch cred := rawCred.(type) {
+	case []interface{}:
+		creds = cred
+	case nil:
+		return nil
+	default:
+		creds = []interface{}{cred}
+	}
+
+	rawCreds := make([][]byte, 0, len(creds))
+
+	for _, cred := range creds {
+		if sCred, ok := cred.(string); ok {
+			rawCreds = append(rawCreds, []byte(sCred))
+
+			continue
+		}
+
+		credBytes, err := json.Marshal(cred)
+		if err != nil {
+			continue
+		}
+
+		rawCreds = append(rawCreds, credBytes)
+	}
+
+	return rawCreds
+}
+
+func (r *VerificationReport) addHolderBindingChecks(holder string, signers []string, vcs []*Credential,
+	isJWS bool) {
+	if holder == "" {
+		r.add(HolderBindingCheck, "", CheckWarning, "presentation holder is not defined")
+
+		return
+	}
+
+	// JWT VP is bound to the holder by "iss" claim.
+	if !isJWS && len(signers) > 0 && !containsString(signers, holder) {
+		if containsString(signers, "") {
+			r.add(HolderBindingCheck, holder, CheckWarning, "controller of the presentation proof key is unknown")
+
+			return
+		}
+
+		r.add(HolderBindingCheck, holder, CheckFailed, "presentation is not signed by the holder")
+
+		return
+	}
+
+	for _, vc := range vcs {
+		subjectIDs := credentialSubjectIDs(vc)
+
+		if len(subjectIDs) > 0 && !containsString(subjectIDs, holder) {
+			r.add(HolderBindingCheck, vc.ID, CheckWarning,
+				fmt.Sprintf("holder %s is not a subject of the credential", holder))
+
+			return
+		}
+	}
+
+	r.add(HolderBindingCheck, holder, CheckPassed, "")
+}
+
+func credentialSubjectIDs(vc *Credential) []string {
+	var ids []string
+
+	switch subject := vc.Subject.(type) {
+	case []Subject:
+		for _, s := range subject {
+			if s.ID != "" {
+				ids = append(ids, s.ID)
+			}
+		}
+	default:
+		if id, err := SubjectID(subject); err == nil && id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
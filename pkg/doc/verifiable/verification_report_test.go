@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+func TestVerifyCredential(t *testing.T) {
+	vc, err := parseTestCredential([]byte(validCredential), WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	// make VC not expired
+	vc.Expired = util.NewTime(time.Now().Add(time.Hour))
+
+	signer, err := newCryptoSigner(kms.ED25519Type)
+	require.NoError(t, err)
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(signer)),
+		SignatureRepresentation: SignatureJWS,
+		VerificationMethod:      issuerDID + "#key1",
+	}, jsonld.WithDocumentLoader(testDocumentLoader))
+	require.NoError(t, err)
+
+	vcBytes, err := json.Marshal(vc)
+	require.NoError(t, err)
+
+	pubKeyFetcher := SingleKey(signer.PublicKeyBytes(), kms.ED25519)
+
+	t.Run("verified", func(t *testing.T) {
+		report := VerifyCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified, report.Err())
+		require.NoError(t, report.Err())
+		require.Equal(t, vc.ID, report.ID)
+		require.Empty(t, report.Errors())
+
+		requireCheck(t, report, SyntaxCheck, CheckPassed)
+		requireCheck(t, report, SchemaCheck, CheckPassed)
+		requireCheck(t, report, JSONLDCheck, CheckPassed)
+		requireCheck(t, report, ProofCheck, CheckPassed)
+		requireCheck(t, report, ProofPolicyCheck, CheckPassed)
+		requireCheck(t, report, IssuanceDateCheck, CheckPassed)
+		requireCheck(t, report, ExpirationDateCheck, CheckPassed)
+
+		// credential status is not checked without a status checker
+		requireCheck(t, report, StatusCheck, CheckWarning)
+		require.Len(t, report.Warnings(), 1)
+	})
+
+	t.Run("status checker", func(t *testing.T) {
+		report := VerifyCredential(vcBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader),
+			WithCredentialStatusChecker(func(vc *Credential) error {
+				return errors.New("revoked")
+			}))
+		require.False(t, report.Verified)
+		requireCheck(t, report, StatusCheck, CheckFailed)
+		require.EqualError(t, report.Err(), "status https://example.edu/status/24: revoked")
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		report := VerifyCredential(vcBytes, WithPublicKeyFetcher(SingleKey([]byte("invalid"), kms.ED25519)),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckFailed)
+		requireCheck(t, report, ProofPolicyCheck, CheckFailed)
+
+		// other checks are still made
+		requireCheck(t, report, JSONLDCheck, CheckPassed)
+		requireCheck(t, report, ExpirationDateCheck, CheckPassed)
+	})
+
+	t.Run("proof check disabled", func(t *testing.T) {
+		report := VerifyCredential(vcBytes, WithDisabledProofCheck(), WithJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckSkipped)
+	})
+
+	t.Run("credential without proof", func(t *testing.T) {
+		unsignedVC := *vc
+		unsignedVC.Proofs = nil
+
+		unsignedBytes, err := json.Marshal(&unsignedVC)
+		require.NoError(t, err)
+
+		report := VerifyCredential(unsignedBytes, WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckFailed)
+		requireCheck(t, report, ExpirationDateCheck, CheckPassed)
+		require.EqualError(t, report.Err(), "proof: embedded proof is missing")
+	})
+
+	t.Run("expired credential without proof", func(t *testing.T) {
+		report := VerifyCredential([]byte(validCredential), WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckFailed)
+		requireCheck(t, report, ExpirationDateCheck, CheckFailed)
+		require.Contains(t, report.Err().Error(), "expirationDate: credential expired at 2020-01-01T19:23:24Z")
+	})
+
+	t.Run("credential issued in the future", func(t *testing.T) {
+		vcCopy, err := parseTestCredential(vcBytes, WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		vcCopy.Issued = util.NewTime(time.Now().Add(time.Hour))
+		vcCopy.Proofs = nil
+
+		vcCopyBytes, err := json.Marshal(vcCopy)
+		require.NoError(t, err)
+
+		report := VerifyCredential(vcCopyBytes, WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, IssuanceDateCheck, CheckFailed)
+	})
+
+	t.Run("JSON-LD and schema validation", func(t *testing.T) {
+		var vcMap map[string]interface{}
+		require.NoError(t, json.Unmarshal(vcBytes, &vcMap))
+
+		vcMap["issuanceDate"] = "invalid date"
+		vcMap["unknownField"] = "value"
+
+		invalidBytes, err := json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		report := VerifyCredential(invalidBytes, WithDisabledProofCheck(),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, SyntaxCheck, CheckFailed)
+
+		delete(vcMap, "issuanceDate")
+
+		invalidBytes, err = json.Marshal(vcMap)
+		require.NoError(t, err)
+
+		report = VerifyCredential(invalidBytes, WithDisabledProofCheck(), WithStrictValidation(),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, SyntaxCheck, CheckPassed)
+		requireCheck(t, report, SchemaCheck, CheckFailed)
+		requireCheck(t, report, JSONLDCheck, CheckFailed)
+		requireCheck(t, report, IssuanceDateCheck, CheckWarning)
+
+		report = VerifyCredential(invalidBytes, WithDisabledProofCheck(), WithJSONLDValidation(),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		requireCheck(t, report, SchemaCheck, CheckSkipped)
+		requireCheck(t, report, JSONLDCheck, CheckPassed)
+
+		report = VerifyCredential(invalidBytes, WithDisabledProofCheck(), WithBaseContextValidation(),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		requireCheck(t, report, SchemaCheck, CheckFailed)
+		requireCheck(t, report, JSONLDCheck, CheckSkipped)
+	})
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		report := VerifyCredential([]byte("not a credential"))
+		require.False(t, report.Verified)
+		require.Len(t, report.Checks, 1)
+		requireCheck(t, report, SyntaxCheck, CheckFailed)
+	})
+
+	t.Run("JWS", func(t *testing.T) {
+		jwtClaims, err := vc.JWTClaims(false)
+		require.NoError(t, err)
+
+		jws, err := jwtClaims.MarshalJWS(EdDSA, signer, issuerDID+"#key1")
+		require.NoError(t, err)
+
+		report := VerifyCredential([]byte(jws), WithPublicKeyFetcher(pubKeyFetcher),
+			WithJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified, report.Err())
+		requireCheck(t, report, ProofCheck, CheckPassed)
+
+		report = VerifyCredential([]byte(jws), WithJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckFailed)
+
+		report = VerifyCredential([]byte(jws), WithDisabledProofCheck(), WithJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckSkipped)
+	})
+}
+
+func TestCheckCredentialStatus(t *testing.T) {
+	t.Run("no status", func(t *testing.T) {
+		require.NoError(t, CheckCredentialStatus(&Credential{}))
+	})
+
+	t.Run("no status checker", func(t *testing.T) {
+		err := CheckCredentialStatus(&Credential{Status: &TypedID{ID: "http://example.com/status/1"}})
+		require.True(t, errors.Is(err, ErrCredentialStatusUnknown))
+	})
+
+	t.Run("status checker", func(t *testing.T) {
+		vc := &Credential{Status: &TypedID{ID: "http://example.com/status/1"}}
+
+		err := CheckCredentialStatus(vc, WithCredentialStatusChecker(func(c *Credential) error {
+			require.Equal(t, vc, c)
+
+			return ErrCredentialRevoked
+		}))
+		require.True(t, errors.Is(err, ErrCredentialRevoked))
+
+		require.NoError(t, CheckCredentialStatus(vc, WithCredentialStatusChecker(func(*Credential) error {
+			return nil
+		})))
+	})
+}
+
+func TestVerifyPresentation(t *testing.T) {
+	holderSigner, err := newCryptoSigner(kms.ED25519Type)
+	require.NoError(t, err)
+
+	vc, err := parseTestCredential([]byte(validCredential), WithDisabledProofCheck())
+	require.NoError(t, err)
+
+	vc.Expired = nil
+	vc.Status = nil
+
+	err = vc.AddLinkedDataProof(&LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(holderSigner)),
+		SignatureRepresentation: SignatureJWS,
+		VerificationMethod:      issuerDID + "#key1",
+	}, jsonld.WithDocumentLoader(testDocumentLoader))
+	require.NoError(t, err)
+
+	subjectID, err := SubjectID(vc.Subject)
+	require.NoError(t, err)
+
+	createVP := func(t *testing.T, holder string) []byte {
+		t.Helper()
+
+		vp, err := NewPresentation(WithCredentials(vc))
+		require.NoError(t, err)
+
+		vp.ID = "urn:uuid:presentation"
+		vp.Holder = holder
+
+		err = vp.AddLinkedDataProof(&LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			Suite:                   ed25519signature2018.New(suite.WithSigner(holderSigner)),
+			SignatureRepresentation: SignatureJWS,
+			VerificationMethod:      subjectID + "#key1",
+		}, jsonld.WithDocumentLoader(testDocumentLoader))
+		require.NoError(t, err)
+
+		vpBytes, err := json.Marshal(vp)
+		require.NoError(t, err)
+
+		return vpBytes
+	}
+
+	// every DID controls the key of holder signer in its DID document
+	pubKeyFetcher := func(issuerID, keyID string) (*verifier.PublicKey, error) {
+		return &verifier.PublicKey{Type: kms.ED25519, Value: holderSigner.PublicKeyBytes(), Controller: issuerID}, nil
+	}
+
+	t.Run("verified", func(t *testing.T) {
+		report := VerifyPresentation(createVP(t, subjectID), WithPresPublicKeyFetcher(pubKeyFetcher),
+			WithPresJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified, report.Err())
+		require.Equal(t, "urn:uuid:presentation", report.ID)
+
+		requireCheck(t, report, SyntaxCheck, CheckPassed)
+		requireCheck(t, report, SchemaCheck, CheckPassed)
+		requireCheck(t, report, JSONLDCheck, CheckPassed)
+		requireCheck(t, report, ProofCheck, CheckPassed)
+		requireCheck(t, report, HolderBindingCheck, CheckPassed)
+
+		require.Len(t, report.Credentials, 1)
+		require.True(t, report.Credentials[0].Verified)
+		require.Equal(t, vc.ID, report.Credentials[0].ID)
+		requireCheck(t, report.Credentials[0], ProofCheck, CheckPassed)
+	})
+
+	t.Run("holder is not a subject", func(t *testing.T) {
+		report := VerifyPresentation(createVP(t, "did:example:other"), WithPresPublicKeyFetcher(pubKeyFetcher),
+			WithPresJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, HolderBindingCheck, CheckFailed)
+		require.Contains(t, report.Err().Error(), "presentation is not signed by the holder")
+	})
+
+	t.Run("controller of the proof key is unknown", func(t *testing.T) {
+		report := VerifyPresentation(createVP(t, subjectID),
+			WithPresPublicKeyFetcher(SingleKey(holderSigner.PublicKeyBytes(), kms.ED25519)),
+			WithPresJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified, report.Err())
+		requireCheck(t, report, HolderBindingCheck, CheckWarning)
+	})
+
+	t.Run("holder is not defined", func(t *testing.T) {
+		report := VerifyPresentation(createVP(t, ""), WithPresPublicKeyFetcher(pubKeyFetcher),
+			WithPresJSONLDDocumentLoader(testDocumentLoader))
+		require.True(t, report.Verified, report.Err())
+		requireCheck(t, report, HolderBindingCheck, CheckWarning)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		report := VerifyPresentation([]byte(validPresentation), WithPresPublicKeyFetcher(pubKeyFetcher),
+			WithPresJSONLDDocumentLoader(testDocumentLoader))
+		require.False(t, report.Verified)
+		requireCheck(t, report, ProofCheck, CheckFailed)
+		requireCheck(t, report, HolderBindingCheck, CheckPassed)
+		require.Len(t, report.Credentials, 1)
+		require.False(t, report.Credentials[0].Verified)
+		require.Contains(t, report.Err().Error(), "credential #0: proof: ")
+	})
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		report := VerifyPresentation([]byte("not a presentation"))
+		require.False(t, report.Verified)
+		requireCheck(t, report, SyntaxCheck, CheckFailed)
+	})
+}
+
+func requireCheck(t *testing.T, report *VerificationReport, check string, status VerificationCheckStatus) {
+	t.Helper()
+
+	for _, c := range report.Checks {
+		if c.Check == check {
+			require.Equal(t, status, c.Status, c.String())
+
+			return
+		}
+	}
+
+	require.Failf(t, "check not found", "check %s is not found in the report", check)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/authcrypt"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
@@ -65,9 +66,10 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	// - DIDExchange depends on Route
 	// - OutOfBand depends on DIDExchange
 	// - Introduce depends on OutOfBand
+	// - DIDRotate depends on Route
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
 		newMessagePickupSvc(), newRouteSvc(), newExchangeSvc(), newOutOfBandSvc(),
-		newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc())
+		newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc(), newDIDRotateSvc())
 
 	if frameworkOpts.secretLock == nil && frameworkOpts.kmsCreator == nil {
 		err = createDefSecretLock(frameworkOpts)
@@ -91,6 +93,12 @@ func newExchangeSvc() api.ProtocolSvcCreator {
 	}
 }
 
+func newDIDRotateSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return didrotate.New(prv)
+	}
+}
+
 func newIntroduceSvc() api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
 		return introduce.New(prv)
@@ -105,7 +113,10 @@ func newIssueCredentialSvc() api.ProtocolSvcCreator {
 		}
 
 		// sets default middleware to the service
-		service.Use(mdissuecredential.SaveCredentials(prv))
+		service.Use(
+			mdissuecredential.ValidateLDProofCredentials(prv),
+			mdissuecredential.SaveCredentials(prv),
+		)
 
 		return service, nil
 	}
@@ -8,6 +8,7 @@ package aries
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/google/uuid"
@@ -315,6 +316,14 @@ func (a *Aries) Messenger() service.Messenger {
 
 // Close frees resources being maintained by the framework.
 func (a *Aries) Close() error {
+	for _, svc := range a.services {
+		if closer, ok := svc.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				return fmt.Errorf("failed to close the %s service: %w", svc.Name(), err)
+			}
+		}
+	}
+
 	if a.storeProvider != nil {
 		err := a.storeProvider.Close()
 		if err != nil {
@@ -224,6 +224,21 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test protocol svc - close error", func(t *testing.T) {
+		newMockSvc := func(prv api.Provider) (dispatcher.ProtocolService, error) {
+			return &closingSvc{
+				MockDIDExchangeSvc: mockdidexchange.MockDIDExchangeSvc{ProtocolName: "mockProtocolSvc"},
+				closeErr:           errors.New("close service error"),
+			}, nil
+		}
+		aries, err := New(WithProtocols(newMockSvc), WithInboundTransport(&mockInboundTransport{}))
+		require.NoError(t, err)
+
+		err = aries.Close()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to close the mockProtocolSvc service: close service error")
+	})
+
 	t.Run("test new with protocol service", func(t *testing.T) {
 		mockSvcCreator := func(prv api.Provider) (dispatcher.ProtocolService, error) {
 			return &mockdidexchange.MockDIDExchangeSvc{
@@ -699,3 +714,12 @@ func (m *mockInboundTransport) Stop() error {
 func (m *mockInboundTransport) Endpoint() string {
 	return ""
 }
+
+type closingSvc struct {
+	mockdidexchange.MockDIDExchangeSvc
+	closeErr error
+}
+
+func (s *closingSvc) Close() error {
+	return s.closeErr
+}
@@ -184,6 +184,10 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 					if err != nil {
 						return fmt.Errorf("inbound message handler: %w", err)
 					}
+
+					if err = p.validateInbound(msg, myDID, theirDID); err != nil {
+						return fmt.Errorf("inbound message handler: %w", err)
+					}
 				}
 
 				_, err = svc.HandleInbound(msg, service.NewDIDCommContext(myDID, theirDID,
@@ -227,6 +231,24 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 	}
 }
 
+// inboundValidator is implemented by the protocol services validating the messages dispatched to the other services,
+// e.g. the out-of-band service rejects the replies to the invitations which are no longer active.
+type inboundValidator interface {
+	ValidateInbound(msg service.DIDCommMsgMap, ctx service.DIDCommContext) error
+}
+
+func (p *Provider) validateInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	for _, svc := range p.services {
+		if validator, ok := svc.(inboundValidator); ok {
+			if err := validator.ValidateInbound(msg, service.NewDIDCommContext(myDID, theirDID, nil)); err != nil {
+				return fmt.Errorf("%s: %w", svc.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (p *Provider) getDIDs(envelope *transport.Envelope) (string, string, error) {
 	myDID, err := p.didConnectionStore.GetDID(base58.Encode(envelope.ToKey))
 	if errors.Is(err, did.ErrNotFound) {
@@ -218,6 +218,38 @@ func TestNewProvider(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("inbound message handler: message rejected by an inbound validator", func(t *testing.T) {
+		connectionStore := didStoreMocks.NewMockConnectionStore(ctrl)
+		connectionStore.EXPECT().GetDID(gomock.Any()).Return("", did.ErrNotFound).Times(2)
+
+		handled := false
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			ProtocolName: "mockProtocolSvc",
+			AcceptFunc:   func(msgType string) bool { return true },
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				handled = true
+
+				return uuid.New().String(), nil
+			},
+		}, &validatingSvc{
+			MockDIDExchangeSvc: &mockdidexchange.MockDIDExchangeSvc{
+				ProtocolName: "validator",
+				AcceptFunc:   func(msgType string) bool { return false },
+			},
+			err: errors.New("invitation is not active"),
+		}), WithDIDConnectionStore(connectionStore))
+		require.NoError(t, err)
+
+		err = ctx.InboundMessageHandler()(&transport.Envelope{Message: []byte(`
+		{
+			"@id": "5678876542345",
+			"@type": "valid-message-type"
+		}`), FromKey: []byte("fromKey"), ToKey: []byte("toKey")})
+		require.EqualError(t, err, "inbound message handler: validator: invitation is not active")
+		require.False(t, handled)
+	})
+
 	t.Run("inbound message handler: failed to get my did", func(t *testing.T) {
 		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
 		messengerHandler.EXPECT().
@@ -592,3 +624,13 @@ func TestNewProvider(t *testing.T) {
 		require.Equal(t, frameworkID, prov.AriesFrameworkID())
 	})
 }
+
+// validatingSvc is a protocol service validating the inbound messages.
+type validatingSvc struct {
+	*mockdidexchange.MockDIDExchangeSvc
+	err error
+}
+
+func (v *validatingSvc) ValidateInbound(service.DIDCommMsgMap, service.DIDCommContext) error {
+	return v.err
+}
@@ -26,11 +26,6 @@ func Derive25519KEK(alg, apu, apv []byte, fromPrivKey, toPubKey *[chacha.KeySize
 		return nil, errors.New("invalid key")
 	}
 
-	const (
-		numBitsPerByte = 8
-		supPubInfoLen  = 4
-	)
-
 	// do ScalarMult of the sender's private key with the recipient key to get a derived Z point
 	// ( equivalent to derive an EC key )
 	z, err := curve25519.X25519(fromPrivKey[:], toPubKey[:])
@@ -38,12 +33,24 @@ func Derive25519KEK(alg, apu, apv []byte, fromPrivKey, toPubKey *[chacha.KeySize
 		return nil, err
 	}
 
+	// since we're using chacha20poly1305 keys, keySize is known
+	return DeriveKEK(alg, apu, apv, z, chacha.KeySize), nil
+}
+
+// DeriveKEK is a utility function that will derive a symmetric key (kek) of keySize bytes from the shared secret z of
+// an ECDH key agreement using the Concat KDF with SHA-256 as defined in
+// https://tools.ietf.org/html/rfc7518#section-4.6.2.
+func DeriveKEK(alg, apu, apv, z []byte, keySize int) []byte {
+	const (
+		numBitsPerByte = 8
+		supPubInfoLen  = 4
+	)
+
 	// inspired by: github.com/square/go-jose/v3@v3.0.0-20190722231519-723929d55157/cipher/ecdh_es.go
 	// -> DeriveECDHES() call
 	// suppPubInfo is the encoded length of the recipient shared key output size in bits
 	supPubInfo := make([]byte, supPubInfoLen)
-	// since we're using chacha20poly1305 keys, keySize is known
-	binary.BigEndian.PutUint32(supPubInfo, uint32(chacha.KeySize)*numBitsPerByte)
+	binary.BigEndian.PutUint32(supPubInfo, uint32(keySize)*numBitsPerByte)
 
 	// as per https://tools.ietf.org/html/rfc7518#section-4.6.2
 	// concatKDF requires info data to be length prefixed with BigEndian 32 bits type
@@ -60,15 +67,11 @@ func Derive25519KEK(alg, apu, apv []byte, fromPrivKey, toPubKey *[chacha.KeySize
 	reader := josecipher.NewConcatKDF(crypto.SHA256, z, algInfo, apuInfo, apvInfo, supPubInfo, []byte{})
 
 	// kek is the recipient specific encryption key used to encrypt the sharedSymKey
-	kek := make([]byte, chacha.KeySize)
+	kek := make([]byte, keySize)
 
-	// Read on the KDF will never fail
-	_, err = reader.Read(kek)
-	if err != nil {
-		return nil, err
-	}
+	_, _ = reader.Read(kek) // nolint:errcheck // ConcatKDF's Read() never returns an error
 
-	return kek, nil
+	return kek
 }
 
 // LengthPrefix array with a bigEndian uint32 value of array's length.
@@ -146,6 +146,21 @@ func (mr *MockProtocolServiceMockRecorder) HandleOutbound(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleOutbound", reflect.TypeOf((*MockProtocolService)(nil).HandleOutbound), arg0, arg1, arg2)
 }
 
+// RecipientStatuses mocks base method.
+func (m *MockProtocolService) RecipientStatuses(arg0 string) ([]*introduce.RecipientStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecipientStatuses", arg0)
+	ret0, _ := ret[0].([]*introduce.RecipientStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecipientStatuses indicates an expected call of RecipientStatuses.
+func (mr *MockProtocolServiceMockRecorder) RecipientStatuses(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecipientStatuses", reflect.TypeOf((*MockProtocolService)(nil).RecipientStatuses), arg0)
+}
+
 // RegisterActionEvent mocks base method.
 func (m *MockProtocolService) RegisterActionEvent(arg0 chan<- service.DIDCommAction) error {
 	m.ctrl.T.Helper()
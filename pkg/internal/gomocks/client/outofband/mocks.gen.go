@@ -160,6 +160,36 @@ func (mr *MockOobServiceMockRecorder) Actions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Actions", reflect.TypeOf((*MockOobService)(nil).Actions))
 }
 
+// GetInvitationRecord mocks base method.
+func (m *MockOobService) GetInvitationRecord(arg0 string) (*outofband.InvitationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationRecord", arg0)
+	ret0, _ := ret[0].(*outofband.InvitationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationRecord indicates an expected call of GetInvitationRecord.
+func (mr *MockOobServiceMockRecorder) GetInvitationRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationRecord", reflect.TypeOf((*MockOobService)(nil).GetInvitationRecord), arg0)
+}
+
+// InvitationRecords mocks base method.
+func (m *MockOobService) InvitationRecords() ([]*outofband.InvitationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvitationRecords")
+	ret0, _ := ret[0].([]*outofband.InvitationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvitationRecords indicates an expected call of InvitationRecords.
+func (mr *MockOobServiceMockRecorder) InvitationRecords() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvitationRecords", reflect.TypeOf((*MockOobService)(nil).InvitationRecords))
+}
+
 // RegisterActionEvent mocks base method.
 func (m *MockOobService) RegisterActionEvent(arg0 chan<- service.DIDCommAction) error {
 	m.ctrl.T.Helper()
@@ -188,18 +218,37 @@ func (mr *MockOobServiceMockRecorder) RegisterMsgEvent(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMsgEvent", reflect.TypeOf((*MockOobService)(nil).RegisterMsgEvent), arg0)
 }
 
+// RevokeInvitation mocks base method.
+func (m *MockOobService) RevokeInvitation(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeInvitation indicates an expected call of RevokeInvitation.
+func (mr *MockOobServiceMockRecorder) RevokeInvitation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvitation", reflect.TypeOf((*MockOobService)(nil).RevokeInvitation), arg0)
+}
+
 // SaveInvitation mocks base method.
-func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation) error {
+func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation, arg1 ...outofband.InvitationOpt) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveInvitation", arg0)
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveInvitation", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveInvitation indicates an expected call of SaveInvitation.
-func (mr *MockOobServiceMockRecorder) SaveInvitation(arg0 interface{}) *gomock.Call {
+func (mr *MockOobServiceMockRecorder) SaveInvitation(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInvitation", reflect.TypeOf((*MockOobService)(nil).SaveInvitation), arg0)
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInvitation", reflect.TypeOf((*MockOobService)(nil).SaveInvitation), varargs...)
 }
 
 // UnregisterActionEvent mocks base method.
@@ -66,6 +66,25 @@ type KeyManager interface {
 	//  - handle instance (to private key)
 	//  - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
 	ImportPrivateKey(privKey interface{}, kt KeyType, opts ...PrivateKeyOpts) (string, interface{}, error)
+	// List returns the metadata of the keys matching filter. A nil filter matches all keys.
+	// Returns:
+	//  - list of key metadata
+	//  - error if failure
+	List(filter *KeyFilter) ([]*KeyMetadata, error)
+	// Delete removes the key referenced by keyID and its metadata from the KMS storage.
+	// Returns:
+	//  - error if the key is not found or failed to be deleted
+	Delete(keyID string) error
+	// SetMetadata sets the label, purpose, owning DID and usage constraint of the key referenced by keyID.
+	// The key ID, key type and creation time are managed by the KMS and cannot be set.
+	// Returns:
+	//  - error if the key is not found or failed to store its metadata
+	SetMetadata(keyID string, metadata *KeyMetadata) error
+	// GetMetadata returns the metadata of the key referenced by keyID.
+	// Returns:
+	//  - key metadata
+	//  - error if the key is not found
+	GetMetadata(keyID string) (*KeyMetadata, error)
 }
 
 // Provider for KeyManager builder/constructor.
@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrKeyUsageNotAllowed is returned when a key is used for an operation its usage constraint does not allow.
+var ErrKeyUsageNotAllowed = errors.New("key usage not allowed")
+
+// KeyUsage represents the operations a key is restricted to.
+type KeyUsage string
+
+const (
+	// KeyUsageAny means the key is not restricted to any usage (default).
+	KeyUsageAny = KeyUsage("")
+	// KeyUsageSign restricts the key to signing, verifying and computing MACs.
+	KeyUsageSign = KeyUsage("sign")
+	// KeyUsageKeyAgreement restricts the key to key wrapping and unwrapping (ECDH).
+	KeyUsageKeyAgreement = KeyUsage("key_agreement")
+	// KeyUsageEncrypt restricts the key to encryption and decryption (AEAD).
+	KeyUsageEncrypt = KeyUsage("encrypt")
+)
+
+const (
+	// KeyPurposeForward is the purpose of the keys the DIDComm forward messages are packed with.
+	KeyPurposeForward = "didcomm-forward"
+	// KeyPurposeDIDExchange is the purpose of the keys of the DIDs created for the DID exchange.
+	KeyPurposeDIDExchange = "didexchange"
+	// KeyPurposeInvitation is the purpose of the keys of the DID exchange invitations.
+	KeyPurposeInvitation = "didexchange-invitation"
+)
+
+// KeyMetadata describes a key stored in the KMS.
+type KeyMetadata struct {
+	// KeyID is the ID of the key (managed by the KMS).
+	KeyID string `json:"keyID"`
+	// KeyType is the type of the key (managed by the KMS).
+	KeyType KeyType `json:"keyType,omitempty"`
+	// Created is the creation time of the key (managed by the KMS).
+	Created time.Time `json:"created"`
+	// Label is a human readable name of the key.
+	Label string `json:"label,omitempty"`
+	// Purpose describes why the key was created (eg. "didcomm-forward").
+	Purpose string `json:"purpose,omitempty"`
+	// DID is the DID owning the key.
+	DID string `json:"did,omitempty"`
+	// Usage restricts the crypto operations the key can be used for.
+	Usage KeyUsage `json:"usage,omitempty"`
+}
+
+// KeyFilter is used to filter the keys returned by KeyManager.List(). Empty fields match any key.
+type KeyFilter struct {
+	KeyType KeyType `json:"keyType,omitempty"`
+	Label   string  `json:"label,omitempty"`
+	Purpose string  `json:"purpose,omitempty"`
+	DID     string  `json:"did,omitempty"`
+	// CreatedBefore matches the keys created before the given time.
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+// Match returns true if the key metadata matches the filter.
+func (f *KeyFilter) Match(md *KeyMetadata) bool {
+	if f == nil {
+		return true
+	}
+
+	return (f.KeyType == "" || f.KeyType == md.KeyType) &&
+		(f.Label == "" || f.Label == md.Label) &&
+		(f.Purpose == "" || f.Purpose == md.Purpose) &&
+		(f.DID == "" || f.DID == md.DID) &&
+		(f.CreatedBefore == nil || md.Created.Before(*f.CreatedBefore))
+}
+
+// UsageConstrainedHandle is a key handle restricted to a key usage. It is returned by KeyManager.Get() for the keys
+// having a usage constraint set in their metadata.
+type UsageConstrainedHandle struct {
+	// Handle is the underlying key handle (eg. *keyset.Handle).
+	Handle interface{}
+	// Usage is the only usage allowed for Handle.
+	Usage KeyUsage
+}
+
+// ResolveKeyHandle returns the underlying key handle of kh for an operation requiring usage.
+// If kh is not a usage constrained handle, kh is returned as is.
+// Returns:
+//  - key handle to be used by the crypto primitives
+//  - ErrKeyUsageNotAllowed (wrapped) if the usage constraint of kh does not allow usage
+func ResolveKeyHandle(kh interface{}, usage KeyUsage) (interface{}, error) {
+	constrained, ok := kh.(*UsageConstrainedHandle)
+	if !ok {
+		return kh, nil
+	}
+
+	if constrained.Usage != KeyUsageAny && constrained.Usage != usage {
+		return nil, fmt.Errorf("%w: key is restricted to '%s', '%s' requested",
+			ErrKeyUsageNotAllowed, constrained.Usage, usage)
+	}
+
+	return constrained.Handle, nil
+}
+
+// TagKey sets the purpose and owning DID of the key referenced by keyID, keeping its other metadata.
+// The metadata is not updated if the key already has the given purpose and DID, which spares a round trip to
+// remote KMS servers.
+// Returns:
+//  - error if the key metadata failed to be fetched or updated
+func TagKey(km KeyManager, keyID, purpose, did string) error {
+	md, err := km.GetMetadata(keyID)
+	if err != nil {
+		return fmt.Errorf("tagKey: %w", err)
+	}
+
+	if md == nil {
+		md = &KeyMetadata{}
+	}
+
+	if md.Purpose == purpose && md.DID == did {
+		return nil
+	}
+
+	err = km.SetMetadata(keyID, &KeyMetadata{Label: md.Label, Purpose: purpose, DID: did, Usage: md.Usage})
+	if err != nil {
+		return fmt.Errorf("tagKey: %w", err)
+	}
+
+	return nil
+}
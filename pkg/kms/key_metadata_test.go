@@ -0,0 +1,64 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+)
+
+func TestTagKey(t *testing.T) {
+	t.Run("sets purpose and DID keeping label and usage", func(t *testing.T) {
+		km := &recordingKeyManager{KeyManager: mockkms.KeyManager{
+			GetMetadataValue: &kms.KeyMetadata{KeyID: "kid", Label: "label", Usage: kms.KeyUsageSign},
+		}}
+
+		require.NoError(t, kms.TagKey(km, "kid", kms.KeyPurposeDIDExchange, "did:example:123"))
+		require.Equal(t, []*kms.KeyMetadata{{
+			Label: "label", Purpose: kms.KeyPurposeDIDExchange, DID: "did:example:123", Usage: kms.KeyUsageSign,
+		}}, km.set)
+	})
+
+	t.Run("skips keys already tagged", func(t *testing.T) {
+		km := &recordingKeyManager{KeyManager: mockkms.KeyManager{
+			GetMetadataValue: &kms.KeyMetadata{KeyID: "kid", Purpose: kms.KeyPurposeForward},
+		}}
+
+		require.NoError(t, kms.TagKey(km, "kid", kms.KeyPurposeForward, ""))
+		require.Empty(t, km.set)
+	})
+
+	t.Run("get metadata failure", func(t *testing.T) {
+		km := &recordingKeyManager{KeyManager: mockkms.KeyManager{GetMetadataErr: errors.New("not supported")}}
+
+		require.EqualError(t, kms.TagKey(km, "kid", kms.KeyPurposeInvitation, ""), "tagKey: not supported")
+		require.Empty(t, km.set)
+	})
+
+	t.Run("set metadata failure", func(t *testing.T) {
+		km := &recordingKeyManager{KeyManager: mockkms.KeyManager{SetMetadataErr: errors.New("set error")}}
+
+		require.EqualError(t, kms.TagKey(km, "kid", kms.KeyPurposeInvitation, ""), "tagKey: set error")
+	})
+}
+
+// recordingKeyManager records the metadata set on the keys.
+type recordingKeyManager struct {
+	mockkms.KeyManager
+	set []*kms.KeyMetadata
+}
+
+func (k *recordingKeyManager) SetMetadata(keyID string, metadata *kms.KeyMetadata) error {
+	k.set = append(k.set, metadata)
+
+	return k.KeyManager.SetMetadata(keyID, metadata)
+}
@@ -0,0 +1,241 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/wrapper/prefix"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/kms/localkms")
+
+const (
+	// metadataKeyPrefix prefixes the key metadata entries stored next to the keysets (prefixed with
+	// prefix.StorageKIDPrefix) in the keystore.
+	metadataKeyPrefix = "m"
+	// metadataTagName tags the key metadata entries in order to list them.
+	metadataTagName = "kmsKeyMetadata"
+)
+
+// List returns the metadata of the keys matching filter. A nil filter matches all keys.
+// The metadata of a key created before the key metadata support was added contains only the key ID. Such a key is
+// listed once it is tagged in the keystore, ie. once it is fetched with Get() or re-encrypted by RotateMasterKey().
+// Returns:
+//  - list of key metadata sorted by creation time
+//  - error if failure
+func (l *LocalKMS) List(filter *kms.KeyFilter) ([]*kms.KeyMetadata, error) {
+	iter, err := l.metadataStore.Query(metadataTagName)
+	if err != nil {
+		return nil, fmt.Errorf("list: failed to query key metadata: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	var result []*kms.KeyMetadata
+
+	listed := map[string]bool{}
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("list: failed to get next key metadata: %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		src, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("list: failed to get key metadata: %w", err)
+		}
+
+		md := &kms.KeyMetadata{}
+		if err = json.Unmarshal(src, md); err != nil {
+			return nil, fmt.Errorf("list: failed to unmarshal key metadata: %w", err)
+		}
+
+		listed[md.KeyID] = true
+
+		if filter.Match(md) {
+			result = append(result, md)
+		}
+	}
+
+	// the keys without metadata
+	err = queryAll(l.metadataStore, keysetTagName, func(key string, _ []byte) error {
+		md := &kms.KeyMetadata{KeyID: strings.TrimPrefix(key, prefix.StorageKIDPrefix)}
+
+		if !listed[md.KeyID] && filter.Match(md) {
+			result = append(result, md)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list: failed to query keysets: %w", err)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created.Equal(result[j].Created) {
+			return result[i].KeyID < result[j].KeyID
+		}
+
+		return result[i].Created.Before(result[j].Created)
+	})
+
+	return result, nil
+}
+
+// Delete removes the key referenced by keyID and its metadata from the KMS storage.
+// Returns:
+//  - error if the key is not found or failed to be deleted
+func (l *LocalKMS) Delete(keyID string) error {
+	if _, err := l.store.Get(keyID); err != nil {
+		return fmt.Errorf("delete: failed to get key '%s': %w", keyID, err)
+	}
+
+	if err := l.store.Delete(keyID); err != nil {
+		return fmt.Errorf("delete: failed to delete key '%s': %w", keyID, err)
+	}
+
+	if err := l.metadataStore.Delete(metadataKeyPrefix + keyID); err != nil {
+		return fmt.Errorf("delete: failed to delete metadata of key '%s': %w", keyID, err)
+	}
+
+	return nil
+}
+
+// SetMetadata sets the label, purpose, owning DID and usage constraint of the key referenced by keyID.
+// The key ID, key type and creation time are managed by the KMS and cannot be set.
+// Returns:
+//  - error if the key is not found or failed to store its metadata
+func (l *LocalKMS) SetMetadata(keyID string, metadata *kms.KeyMetadata) error {
+	if metadata == nil {
+		return errors.New("setMetadata: metadata is nil")
+	}
+
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return fmt.Errorf("setMetadata: %w", err)
+	}
+
+	md.Label = metadata.Label
+	md.Purpose = metadata.Purpose
+	md.DID = metadata.DID
+	md.Usage = metadata.Usage
+
+	if err = l.saveMetadata(md); err != nil {
+		return fmt.Errorf("setMetadata: failed to store key metadata: %w", err)
+	}
+
+	return nil
+}
+
+// GetMetadata returns the metadata of the key referenced by keyID.
+// The metadata of a key created before the key metadata support was added contains only the key ID.
+// Returns:
+//  - key metadata
+//  - error if the key is not found
+func (l *LocalKMS) GetMetadata(keyID string) (*kms.KeyMetadata, error) {
+	md, err := l.getMetadata(keyID)
+	if err == nil {
+		return md, nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("getMetadata: failed to get metadata of key '%s': %w", keyID, err)
+	}
+
+	if _, err = l.store.Get(keyID); err != nil {
+		return nil, fmt.Errorf("getMetadata: failed to get key '%s': %w", keyID, err)
+	}
+
+	return &kms.KeyMetadata{KeyID: keyID}, nil
+}
+
+func (l *LocalKMS) getMetadata(keyID string) (*kms.KeyMetadata, error) {
+	src, err := l.metadataStore.Get(metadataKeyPrefix + keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &kms.KeyMetadata{}
+	if err = json.Unmarshal(src, md); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+func (l *LocalKMS) saveMetadata(md *kms.KeyMetadata) error {
+	src, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+
+	return l.metadataStore.Put(metadataKeyPrefix+md.KeyID, src, storage.Tag{Name: metadataTagName})
+}
+
+// rotateMetadata moves the metadata of the rotated key to the new key ID.
+// Returns:
+//  - metadata of the new key
+//  - error if failure
+func (l *LocalKMS) rotateMetadata(oldID, newID string, kt kms.KeyType) (*kms.KeyMetadata, error) {
+	md, err := l.getMetadata(oldID)
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+		md = &kms.KeyMetadata{}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get key metadata: %w", err)
+	default:
+		if err = l.metadataStore.Delete(metadataKeyPrefix + oldID); err != nil {
+			return nil, fmt.Errorf("failed to delete key metadata: %w", err)
+		}
+	}
+
+	md.KeyID = newID
+	md.KeyType = kt
+	md.Created = time.Now().UTC()
+
+	if err = l.saveMetadata(md); err != nil {
+		return nil, fmt.Errorf("failed to store key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+// registerKey tags the keyset created before the key metadata support was added and stores its metadata
+// (only the key ID is known) in order to list the key.
+func (l *LocalKMS) registerKey(keyID string) (*kms.KeyMetadata, error) {
+	ks, err := l.store.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key '%s': %w", keyID, err)
+	}
+
+	if err = l.store.Put(keyID, ks, storage.Tag{Name: keysetTagName}); err != nil {
+		return nil, fmt.Errorf("failed to tag key '%s': %w", keyID, err)
+	}
+
+	md := &kms.KeyMetadata{KeyID: keyID}
+
+	if err = l.saveMetadata(md); err != nil {
+		return nil, fmt.Errorf("failed to store key metadata: %w", err)
+	}
+
+	return md, nil
+}
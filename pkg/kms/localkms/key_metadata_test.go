@@ -0,0 +1,279 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/wrapper/prefix"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestLocalKMS_KeyMetadata(t *testing.T) {
+	storeDB := make(map[string]mockstorage.DBEntry)
+
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{Store: storeDB}),
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	before := time.Now().UTC()
+
+	signKID, _, err := kmsService.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	kwKID, _, err := kmsService.Create(kms.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	t.Run("get metadata of new key", func(t *testing.T) {
+		md, err := kmsService.GetMetadata(signKID)
+		require.NoError(t, err)
+		require.Equal(t, signKID, md.KeyID)
+		require.Equal(t, kms.ED25519Type, md.KeyType)
+		require.False(t, md.Created.Before(before))
+		require.Empty(t, md.Label)
+		require.Equal(t, kms.KeyUsageAny, md.Usage)
+	})
+
+	t.Run("set metadata", func(t *testing.T) {
+		err := kmsService.SetMetadata(kwKID, &kms.KeyMetadata{
+			KeyID:   "ignored",
+			KeyType: kms.AES128GCMType,
+			Label:   "didcomm",
+			Purpose: "key-agreement",
+			DID:     "did:example:alice",
+		})
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(kwKID)
+		require.NoError(t, err)
+		require.Equal(t, kwKID, md.KeyID)
+		require.Equal(t, kms.X25519ECDHKWType, md.KeyType)
+		require.Equal(t, "didcomm", md.Label)
+		require.Equal(t, "key-agreement", md.Purpose)
+		require.Equal(t, "did:example:alice", md.DID)
+
+		err = kmsService.SetMetadata(kwKID, nil)
+		require.EqualError(t, err, "setMetadata: metadata is nil")
+
+		err = kmsService.SetMetadata("unknown", &kms.KeyMetadata{Label: "unknown"})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("list keys", func(t *testing.T) {
+		keys, err := kmsService.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, signKID, keys[0].KeyID)
+		require.Equal(t, kwKID, keys[1].KeyID)
+
+		keys, err = kmsService.List(&kms.KeyFilter{DID: "did:example:alice"})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, kwKID, keys[0].KeyID)
+
+		keys, err = kmsService.List(&kms.KeyFilter{KeyType: kms.ED25519Type})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, signKID, keys[0].KeyID)
+
+		keys, err = kmsService.List(&kms.KeyFilter{CreatedBefore: &before})
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("get metadata of unknown key", func(t *testing.T) {
+		_, err := kmsService.GetMetadata("unknown")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("get metadata of key without metadata", func(t *testing.T) {
+		kid, _, err := kmsService.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		delete(storeDB, metadataKeyPrefix+kid)
+
+		md, err := kmsService.GetMetadata(kid)
+		require.NoError(t, err)
+		require.Equal(t, &kms.KeyMetadata{KeyID: kid}, md)
+
+		// the keyset is tagged, the key is listed
+		keys, err := kmsService.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 3)
+		require.Equal(t, &kms.KeyMetadata{KeyID: kid}, keys[0])
+
+		// the key created before the key metadata support was added is not tagged
+		storeDB[prefix.StorageKIDPrefix+kid] = mockstorage.DBEntry{Value: storeDB[prefix.StorageKIDPrefix+kid].Value}
+
+		keys, err = kmsService.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		kh, err := kmsService.Get(kid)
+		require.NoError(t, err)
+		require.IsType(t, &keyset.Handle{}, kh)
+
+		// the key is registered once it is fetched
+		keys, err = kmsService.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 3)
+		require.Equal(t, &kms.KeyMetadata{KeyID: kid}, keys[0])
+
+		require.NoError(t, kmsService.Delete(kid))
+	})
+
+	t.Run("rotate key keeps metadata", func(t *testing.T) {
+		newKID, _, err := kmsService.Rotate(kms.X25519ECDHKWType, kwKID)
+		require.NoError(t, err)
+
+		_, err = kmsService.GetMetadata(kwKID)
+		require.Error(t, err)
+
+		md, err := kmsService.GetMetadata(newKID)
+		require.NoError(t, err)
+		require.Equal(t, newKID, md.KeyID)
+		require.Equal(t, "didcomm", md.Label)
+		require.Equal(t, "did:example:alice", md.DID)
+
+		kwKID = newKID
+	})
+
+	t.Run("rotate key keeps usage constraint", func(t *testing.T) {
+		kid, _, err := kmsService.Create(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		err = kmsService.SetMetadata(kid, &kms.KeyMetadata{Usage: kms.KeyUsageSign})
+		require.NoError(t, err)
+
+		newKID, kh, err := kmsService.Rotate(kms.ECDSAP256TypeIEEEP1363, kid)
+		require.NoError(t, err)
+
+		constrained, ok := kh.(*kms.UsageConstrainedHandle)
+		require.True(t, ok)
+		require.Equal(t, kms.KeyUsageSign, constrained.Usage)
+		require.IsType(t, &keyset.Handle{}, constrained.Handle)
+
+		require.NoError(t, kmsService.Delete(newKID))
+	})
+
+	t.Run("import private key stores metadata", func(t *testing.T) {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		kid, _, err := kmsService.ImportPrivateKey(privKey, kms.ED25519Type)
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(kid)
+		require.NoError(t, err)
+		require.Equal(t, kms.ED25519Type, md.KeyType)
+
+		require.NoError(t, kmsService.Delete(kid))
+	})
+
+	t.Run("get key with usage constraint", func(t *testing.T) {
+		kh, err := kmsService.Get(signKID)
+		require.NoError(t, err)
+		require.IsType(t, &keyset.Handle{}, kh)
+
+		err = kmsService.SetMetadata(signKID, &kms.KeyMetadata{Usage: kms.KeyUsageSign})
+		require.NoError(t, err)
+
+		kh, err = kmsService.Get(signKID)
+		require.NoError(t, err)
+
+		constrained, ok := kh.(*kms.UsageConstrainedHandle)
+		require.True(t, ok)
+		require.Equal(t, kms.KeyUsageSign, constrained.Usage)
+		require.IsType(t, &keyset.Handle{}, constrained.Handle)
+
+		_, err = kms.ResolveKeyHandle(kh, kms.KeyUsageKeyAgreement)
+		require.True(t, errors.Is(err, kms.ErrKeyUsageNotAllowed))
+
+		resolved, err := kms.ResolveKeyHandle(kh, kms.KeyUsageSign)
+		require.NoError(t, err)
+		require.Equal(t, constrained.Handle, resolved)
+	})
+
+	t.Run("delete key", func(t *testing.T) {
+		require.NoError(t, kmsService.Delete(signKID))
+
+		_, err := kmsService.Get(signKID)
+		require.Error(t, err)
+
+		_, err = kmsService.GetMetadata(signKID)
+		require.Error(t, err)
+
+		keys, err := kmsService.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, kwKID, keys[0].KeyID)
+
+		err = kmsService.Delete(signKID)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
+
+func TestLocalKMS_KeyMetadata_Failure(t *testing.T) {
+	t.Run("list fails to query store", func(t *testing.T) {
+		kmsService, err := New(testMasterKeyURI, &mockProvider{
+			storage: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:    map[string]mockstorage.DBEntry{},
+				ErrQuery: errors.New("query error"),
+			}),
+			secretLock: &noop.NoLock{},
+		})
+		require.NoError(t, err)
+
+		_, err = kmsService.List(nil)
+		require.EqualError(t, err, "list: failed to query key metadata: query error")
+	})
+
+	t.Run("invalid stored metadata", func(t *testing.T) {
+		storeDB := map[string]mockstorage.DBEntry{}
+
+		kmsService, err := New(testMasterKeyURI, &mockProvider{
+			storage:    mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{Store: storeDB}),
+			secretLock: &noop.NoLock{},
+		})
+		require.NoError(t, err)
+
+		kid, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		storeDB[metadataKeyPrefix+kid] = mockstorage.DBEntry{
+			Value: []byte("{"),
+			Tags:  []storage.Tag{{Name: metadataTagName}},
+		}
+
+		_, err = kmsService.Get(kid)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get: failed to get key metadata")
+
+		_, err = kmsService.GetMetadata(kid)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key metadata")
+
+		_, err = kmsService.List(nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "list: failed to unmarshal key metadata")
+	})
+}
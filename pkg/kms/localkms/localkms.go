@@ -13,6 +13,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead"
@@ -54,16 +55,24 @@ type LocalKMS struct {
 	secretLock        secretlock.Service
 	primaryKeyURI     string
 	store             storage.Store
+	metadataStore     storage.Store
 	primaryKeyEnvAEAD *aead.KMSEnvelopeAEAD
 }
 
-func newKeyIDWrapperStore(provider storage.Provider, storePrefix string) (storage.Store, error) {
+// newKeyIDWrapperStore returns the keystore wrapped with the key ID prefix along with the keystore itself
+// (used for the key metadata).
+func newKeyIDWrapperStore(provider storage.Provider, storePrefix string) (storage.Store, storage.Store, error) {
 	s, err := provider.OpenStore(storePrefix + Namespace)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return prefix.NewPrefixStoreWrapper(s, prefix.StorageKIDPrefix)
+	store, err := prefix.NewPrefixStoreWrapper(s, prefix.StorageKIDPrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return store, s, nil
 }
 
 // New will create a new (local) KMS service.
@@ -73,23 +82,21 @@ func New(primaryKeyURI string, p kms.Provider) (*LocalKMS, error) {
 
 // NewWithPrefix will create a new (local) KMS service using a store name prefixed with storePrefix.
 func NewWithPrefix(primaryKeyURI string, p kms.Provider, storePrefix string) (*LocalKMS, error) {
-	store, err := newKeyIDWrapperStore(p.StorageProvider(), storePrefix)
+	store, metadataStore, err := newKeyIDWrapperStore(p.StorageProvider(), storePrefix)
 	if err != nil {
 		return nil, fmt.Errorf("new: failed to ceate local kms: %w", err)
 	}
 
 	secretLock := p.SecretLock()
 
-	kw, err := keywrapper.New(secretLock, primaryKeyURI)
+	keyEnvelopeAEAD, err := newKeyEnvelopeAEAD(secretLock, primaryKeyURI)
 	if err != nil {
-		return nil, fmt.Errorf("new: failed to create new keywrapper: %w", err)
+		return nil, fmt.Errorf("new: %w", err)
 	}
 
-	// create a KMSEnvelopeAEAD instance to wrap/unwrap keys managed by LocalKMS
-	keyEnvelopeAEAD := aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), kw)
-
 	return &LocalKMS{
 			store:             store,
+			metadataStore:     metadataStore,
 			secretLock:        secretLock,
 			primaryKeyURI:     primaryKeyURI,
 			primaryKeyEnvAEAD: keyEnvelopeAEAD,
@@ -97,6 +104,17 @@ func NewWithPrefix(primaryKeyURI string, p kms.Provider, storePrefix string) (*L
 		nil
 }
 
+// newKeyEnvelopeAEAD creates a KMSEnvelopeAEAD instance to wrap/unwrap the keys managed by LocalKMS with the primary
+// key referenced by primaryKeyURI in secretLock.
+func newKeyEnvelopeAEAD(secretLock secretlock.Service, primaryKeyURI string) (*aead.KMSEnvelopeAEAD, error) {
+	kw, err := keywrapper.New(secretLock, primaryKeyURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new keywrapper: %w", err)
+	}
+
+	return aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), kw), nil
+}
+
 // Create a new key/keyset/key handle for the type kt
 // Returns:
 //  - keyID of the handle
@@ -122,22 +140,46 @@ func (l *LocalKMS) Create(kt kms.KeyType) (string, interface{}, error) {
 		return "", nil, fmt.Errorf("create: failed to store keyset: %w", err)
 	}
 
+	err = l.saveMetadata(&kms.KeyMetadata{KeyID: keyID, KeyType: kt, Created: time.Now().UTC()})
+	if err != nil {
+		return "", nil, fmt.Errorf("create: failed to store key metadata: %w", err)
+	}
+
 	return keyID, kh, nil
 }
 
 // Get key handle for the given keyID
 // Returns:
-//  - handle instance (to private key)
+//  - handle instance (to private key), a *kms.UsageConstrainedHandle if the key has a usage constraint
 //  - error if failure
 func (l *LocalKMS) Get(keyID string) (interface{}, error) {
-	return l.getKeySet(keyID)
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return kh, err
+	}
+
+	md, err := l.getMetadata(keyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		// the key was created before the key metadata support was added, registers it in order to list it
+		md, err = l.registerKey(keyID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get: failed to get key metadata: %w", err)
+	}
+
+	if md.Usage != kms.KeyUsageAny {
+		return &kms.UsageConstrainedHandle{Handle: kh, Usage: md.Usage}, nil
+	}
+
+	return kh, nil
 }
 
 // Rotate a key referenced by keyID and return a new handle of a keyset including old key and
 // new key with type kt. It also returns the updated keyID as the first return value
 // Returns:
 //  - new KeyID
-//  - handle instance (to private key)
+//  - handle instance (to private key), a *kms.UsageConstrainedHandle if the key has a usage constraint
 //  - error if failure
 func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, error) {
 	kh, err := l.getKeySet(keyID)
@@ -172,6 +214,15 @@ func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, er
 		return "", nil, fmt.Errorf("rotate: failed to store keySet: %w", err)
 	}
 
+	md, err := l.rotateMetadata(keyID, newID, kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	if md.Usage != kms.KeyUsageAny {
+		return newID, &kms.UsageConstrainedHandle{Handle: updatedKH, Usage: md.Usage}, nil
+	}
+
 	return newID, updatedKH, nil
 }
 
@@ -390,16 +441,33 @@ func (l *LocalKMS) PubKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (interface
 //  - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
 func (l *LocalKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
 	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	var (
+		keyID string
+		kh    *keyset.Handle
+		err   error
+	)
+
 	switch pk := privKey.(type) {
 	case *ecdsa.PrivateKey:
-		return l.importECDSAKey(pk, kt, opts...)
+		keyID, kh, err = l.importECDSAKey(pk, kt, opts...)
 	case ed25519.PrivateKey:
-		return l.importEd25519Key(pk, kt, opts...)
+		keyID, kh, err = l.importEd25519Key(pk, kt, opts...)
 	case *bbs12381g2pub.PrivateKey:
-		return l.importBBSKey(pk, kt, opts...)
+		keyID, kh, err = l.importBBSKey(pk, kt, opts...)
 	default:
 		return "", nil, fmt.Errorf("import private key does not support this key type or key is public")
 	}
+
+	if err != nil {
+		return keyID, kh, err
+	}
+
+	err = l.saveMetadata(&kms.KeyMetadata{KeyID: keyID, KeyType: kt, Created: time.Now().UTC()})
+	if err != nil {
+		return "", nil, fmt.Errorf("import private key: failed to store key metadata: %w", err)
+	}
+
+	return keyID, kh, nil
 }
 
 func (l *LocalKMS) generateKID(kh *keyset.Handle, kt kms.KeyType) (string, error) {
@@ -60,7 +60,7 @@ func (l *storeWriter) Write(p []byte) (int, error) {
 		}
 	}
 
-	err = l.storage.Put(ksID, p)
+	err = l.storage.Put(ksID, p, storage.Tag{Name: keysetTagName})
 	if err != nil {
 		return 0, err
 	}
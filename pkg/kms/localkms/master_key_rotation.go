@@ -0,0 +1,206 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/store/wrapper/prefix"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// keysetTagName tags the keyset entries in the keystore in order to list them for a master key rotation.
+const keysetTagName = "kmsKeyset"
+
+// MasterKey references the primary key protecting the keysets stored by LocalKMS.
+type MasterKey struct {
+	// KeyURI is the primary key URI (starting with 'local-lock://') passed to the LocalKMS constructor.
+	KeyURI string
+	// SecretLock is the secret lock service of the KMS provider, eg a local secret lock created with the master key
+	// protected by a passphrase.
+	SecretLock secretlock.Service
+}
+
+// RotateMasterKey re-wraps all the keysets stored by LocalKMS in the store named storePrefix + Namespace of
+// storeProvider, from the oldKey master key to newKey. The master key is rotated by either changing the primary key
+// URI or the secret lock, eg with a new local master key protected by a new passphrase.
+// The LocalKMS instances using the store must not be used during the rotation, they must be recreated with newKey
+// once the rotation is done.
+// The rotation is resumable: if it is interrupted, calling RotateMasterKey again with the same arguments skips the
+// keysets already wrapped with newKey.
+// Keysets are found using their key metadata or the keyset tag set when they are stored. Keysets stored by older
+// versions of the KMS without key metadata are only rotated if their key IDs are listed in keyIDs.
+// Returns:
+//  - number of keysets re-wrapped with newKey
+//  - error if failure
+func RotateMasterKey(storeProvider storage.Provider, storePrefix string, oldKey, newKey *MasterKey,
+	keyIDs ...string) (int, error) {
+	if oldKey == nil || newKey == nil {
+		return 0, errors.New("rotateMasterKey: old and new master keys are required")
+	}
+
+	oldEnvAEAD, err := newKeyEnvelopeAEAD(oldKey.SecretLock, oldKey.KeyURI)
+	if err != nil {
+		return 0, fmt.Errorf("rotateMasterKey: old master key: %w", err)
+	}
+
+	newEnvAEAD, err := newKeyEnvelopeAEAD(newKey.SecretLock, newKey.KeyURI)
+	if err != nil {
+		return 0, fmt.Errorf("rotateMasterKey: new master key: %w", err)
+	}
+
+	store, metadataStore, err := newKeyIDWrapperStore(storeProvider, storePrefix)
+	if err != nil {
+		return 0, fmt.Errorf("rotateMasterKey: failed to open keystore: %w", err)
+	}
+
+	ids, err := listKeysetIDs(metadataStore, keyIDs)
+	if err != nil {
+		return 0, fmt.Errorf("rotateMasterKey: %w", err)
+	}
+
+	rotated := 0
+
+	for _, id := range ids {
+		ok, err := rewrapKeyset(store, id, oldEnvAEAD, newEnvAEAD)
+		if err != nil {
+			return rotated, fmt.Errorf("rotateMasterKey: keyset '%s': %w", id, err)
+		}
+
+		if ok {
+			rotated++
+		}
+	}
+
+	return rotated, nil
+}
+
+// listKeysetIDs returns the IDs of the tagged keysets and of the keys with metadata found in the keystore s, along
+// with keyIDs.
+func listKeysetIDs(s storage.Store, keyIDs []string) ([]string, error) {
+	var ids []string
+
+	found := map[string]bool{}
+
+	add := func(id string) {
+		if !found[id] {
+			found[id] = true
+
+			ids = append(ids, id)
+		}
+	}
+
+	err := queryAll(s, keysetTagName, func(key string, _ []byte) error {
+		add(strings.TrimPrefix(key, prefix.StorageKIDPrefix))
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keysets: %w", err)
+	}
+
+	err = queryAll(s, metadataTagName, func(_ string, value []byte) error {
+		md := &kms.KeyMetadata{}
+		if e := json.Unmarshal(value, md); e != nil {
+			return fmt.Errorf("failed to unmarshal key metadata: %w", e)
+		}
+
+		add(md.KeyID)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key metadata: %w", err)
+	}
+
+	for _, id := range keyIDs {
+		add(id)
+	}
+
+	return ids, nil
+}
+
+func queryAll(s storage.Store, tagName string, f func(key string, value []byte) error) error {
+	iter, err := s.Query(tagName)
+	if err != nil {
+		return err
+	}
+
+	defer storage.Close(iter, logger)
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+
+		key, err := iter.Key()
+		if err != nil {
+			return err
+		}
+
+		value, err := iter.Value()
+		if err != nil {
+			return err
+		}
+
+		if err = f(key, value); err != nil {
+			return err
+		}
+	}
+}
+
+// rewrapKeyset re-wraps the keyset id of store from oldEnvAEAD to newEnvAEAD. It returns false if the keyset is
+// already wrapped with newEnvAEAD (ie it was rotated by an interrupted rotation) or was deleted.
+func rewrapKeyset(store storage.Store, id string, oldEnvAEAD, newEnvAEAD *aead.KMSEnvelopeAEAD) (bool, error) {
+	data, err := store.Get(id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		// a deleted key may still have its metadata.
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to read keyset: %w", err)
+	}
+
+	_, err = keyset.Read(keyset.NewJSONReader(bytes.NewReader(data)), newEnvAEAD)
+	if err == nil {
+		return false, nil
+	}
+
+	kh, err := keyset.Read(keyset.NewJSONReader(bytes.NewReader(data)), oldEnvAEAD)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt keyset with the old master key: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = kh.Write(keyset.NewJSONWriter(buf), newEnvAEAD)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt keyset with the new master key: %w", err)
+	}
+
+	err = store.Put(id, buf.Bytes(), storage.Tag{Name: keysetTagName})
+	if err != nil {
+		return false, fmt.Errorf("failed to store keyset: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,212 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms/internal/keywrapper"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/argon2id"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestRotateMasterKey(t *testing.T) {
+	storeProvider := mockstorage.NewMockStoreProvider()
+	oldKey := &MasterKey{KeyURI: testMasterKeyURI, SecretLock: createMasterKeyAndSecretLock(t)}
+
+	oldKMS, err := New(oldKey.KeyURI, &mockProvider{storage: storeProvider, secretLock: oldKey.SecretLock})
+	require.NoError(t, err)
+
+	var keyIDs []string
+
+	for _, kt := range []kms.KeyType{kms.ED25519Type, kms.NISTP256ECDHKWType, kms.AES256GCMType} {
+		keyID, _, e := oldKMS.Create(kt)
+		require.NoError(t, e)
+
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	// a keyset stored by an older KMS version has neither key metadata nor keyset tag.
+	legacyKeyID, _, err := oldKMS.Create(kms.ECDSAP256TypeIEEEP1363)
+	require.NoError(t, err)
+
+	store := storeProvider.Store.Store
+	store["k"+legacyKeyID] = mockstorage.DBEntry{Value: store["k"+legacyKeyID].Value}
+	delete(store, metadataKeyPrefix+legacyKeyID)
+
+	// the new master key is protected by a new passphrase.
+	newKey := &MasterKey{
+		KeyURI:     keywrapper.LocalKeyURIPrefix + "new/key/uri",
+		SecretLock: createArgon2idSecretLock(t, "newPassphrase"),
+	}
+
+	t.Run("interrupted rotation resumes", func(t *testing.T) {
+		interrupted := &interruptingProvider{Provider: storeProvider, puts: 1}
+
+		rotated, err := RotateMasterKey(interrupted, "", oldKey, newKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to store keyset: interrupted")
+		require.Equal(t, 1, rotated)
+
+		rotated, err = RotateMasterKey(storeProvider, "", oldKey, newKey)
+		require.NoError(t, err)
+		require.Equal(t, 2, rotated)
+
+		// the legacy keyset is only rotated when its ID is listed.
+		rotated, err = RotateMasterKey(storeProvider, "", oldKey, newKey, legacyKeyID)
+		require.NoError(t, err)
+		require.Equal(t, 1, rotated)
+
+		rotated, err = RotateMasterKey(storeProvider, "", oldKey, newKey, legacyKeyID)
+		require.NoError(t, err)
+		require.Zero(t, rotated)
+	})
+
+	t.Run("keys are readable with the new master key only", func(t *testing.T) {
+		newKMS, err := New(newKey.KeyURI, &mockProvider{storage: storeProvider, secretLock: newKey.SecretLock})
+		require.NoError(t, err)
+
+		for _, keyID := range append(keyIDs, legacyKeyID) {
+			_, err = newKMS.Get(keyID)
+			require.NoError(t, err)
+
+			_, err = oldKMS.Get(keyID)
+			require.Error(t, err)
+		}
+
+		// keys created after the rotation are tagged, the legacy keyset is tagged by the rotation.
+		keyID, _, err := newKMS.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		for _, id := range []string{keyID, legacyKeyID} {
+			tags, err := storeProvider.Store.GetTags("k" + id)
+			require.NoError(t, err)
+			require.Equal(t, []storage.Tag{{Name: keysetTagName}}, tags)
+		}
+	})
+
+	t.Run("deleted keys are skipped", func(t *testing.T) {
+		delete(store, "k"+keyIDs[0])
+
+		// the metadata of the deleted keyset remains, the key created with the new master key is rotated.
+		rotated, err := RotateMasterKey(storeProvider, "", newKey, oldKey)
+		require.NoError(t, err)
+		require.Equal(t, 4, rotated)
+	})
+}
+
+func TestRotateMasterKey_Failure(t *testing.T) {
+	storeProvider := mockstorage.NewMockStoreProvider()
+	oldKey := &MasterKey{KeyURI: testMasterKeyURI, SecretLock: createMasterKeyAndSecretLock(t)}
+	newKey := &MasterKey{KeyURI: testMasterKeyURI, SecretLock: createArgon2idSecretLock(t, "newPassphrase")}
+
+	oldKMS, err := New(oldKey.KeyURI, &mockProvider{storage: storeProvider, secretLock: oldKey.SecretLock})
+	require.NoError(t, err)
+
+	keyID, _, err := oldKMS.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	t.Run("missing master key", func(t *testing.T) {
+		_, err := RotateMasterKey(storeProvider, "", nil, newKey)
+		require.EqualError(t, err, "rotateMasterKey: old and new master keys are required")
+	})
+
+	t.Run("invalid key URI", func(t *testing.T) {
+		_, err := RotateMasterKey(storeProvider, "", &MasterKey{KeyURI: "bad-prefix://key"}, newKey)
+		require.EqualError(t, err, "rotateMasterKey: old master key: failed to create new keywrapper: "+
+			"keyURI must start with local-lock://")
+
+		_, err = RotateMasterKey(storeProvider, "", oldKey, &MasterKey{KeyURI: "bad-prefix://key"})
+		require.EqualError(t, err, "rotateMasterKey: new master key: failed to create new keywrapper: "+
+			"keyURI must start with local-lock://")
+	})
+
+	t.Run("wrong old master key", func(t *testing.T) {
+		otherKey := &MasterKey{KeyURI: testMasterKeyURI, SecretLock: createArgon2idSecretLock(t, "otherPassphrase")}
+
+		rotated, err := RotateMasterKey(storeProvider, "", otherKey, newKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rotateMasterKey: keyset '"+keyID+
+			"': failed to decrypt keyset with the old master key")
+		require.Zero(t, rotated)
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		_, err := RotateMasterKey(&mockstorage.MockStoreProvider{
+			ErrOpenStoreHandle: errors.New("open error"),
+		}, "", oldKey, newKey)
+		require.EqualError(t, err, "rotateMasterKey: failed to open keystore: open error")
+
+		_, err = RotateMasterKey(mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:    map[string]mockstorage.DBEntry{},
+			ErrQuery: errors.New("query error"),
+		}), "", oldKey, newKey)
+		require.EqualError(t, err, "rotateMasterKey: failed to list keysets: query error")
+
+		_, err = RotateMasterKey(mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:  map[string]mockstorage.DBEntry{},
+			ErrGet: errors.New("get error"),
+		}), "", oldKey, newKey, keyID)
+		require.EqualError(t, err, "rotateMasterKey: keyset '"+keyID+"': failed to read keyset: get error")
+	})
+}
+
+func createArgon2idSecretLock(t *testing.T, passphrase string) secretlock.Service {
+	t.Helper()
+
+	masterLocker, err := argon2id.NewMasterLock(passphrase, &argon2id.Params{Time: 1, Memory: 64, Threads: 1})
+	require.NoError(t, err)
+
+	masterKeyEnc, err := masterLocker.Encrypt("", &secretlock.EncryptRequest{
+		Plaintext: string(random.GetRandomBytes(uint32(32))),
+	})
+	require.NoError(t, err)
+
+	s, err := local.NewService(bytes.NewReader([]byte(masterKeyEnc.Ciphertext)), masterLocker)
+	require.NoError(t, err)
+
+	return s
+}
+
+// interruptingProvider opens stores failing to store data after puts calls to Put().
+type interruptingProvider struct {
+	storage.Provider
+	puts int
+}
+
+func (p *interruptingProvider) OpenStore(name string) (storage.Store, error) {
+	s, err := p.Provider.OpenStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &interruptingStore{Store: s, provider: p}, nil
+}
+
+type interruptingStore struct {
+	storage.Store
+	provider *interruptingProvider
+}
+
+func (s *interruptingStore) Put(key string, value []byte, tags ...storage.Tag) error {
+	if s.provider.puts == 0 {
+		return errors.New("interrupted")
+	}
+
+	s.provider.puts--
+
+	return s.Store.Put(key, value, tags...)
+}
@@ -67,7 +67,7 @@ func TestImportKeySetInvalid(t *testing.T) {
 
 	store := storageGoMocks.NewMockStore(ctrl)
 	store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
-	store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	store.EXPECT().Get(gomock.Any()).Return(nil, fmt.Errorf("failed to get keyset"))
 
 	storeProvider := storageGoMocks.NewMockProvider(ctrl)
@@ -25,6 +25,12 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
+// PublicKeyBytesToHandle creates a Tink public key handle for pubKey of type kt marshalled in the format exported by
+// LocalKMS. It allows other KMS implementations to create the public key handles used by tinkcrypto.
+func PublicKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (*keyset.Handle, error) {
+	return publicKeyBytesToHandle(pubKey, kt)
+}
+
 func publicKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (*keyset.Handle, error) {
 	if len(pubKey) == 0 {
 		return nil, fmt.Errorf("pubKey is empty")
@@ -0,0 +1,248 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// KeyHandle references a key pair stored in the PKCS#11 token. The private key never leaves the token, it is only
+// used through the Sign and DeriveSharedSecret calls made by the pkg/crypto/pkcs11kms Crypto.
+type KeyHandle struct {
+	hsm     *HSMKMS
+	keyID   string
+	keyType kms.KeyType
+	spec    *keySpec
+	privObj pkcs11.ObjectHandle
+	pubObj  pkcs11.ObjectHandle
+	pubKey  interface{}
+}
+
+// KeyID returns the ID of the key.
+func (k *KeyHandle) KeyID() string {
+	return k.keyID
+}
+
+// KeyType returns the KMS key type of the key.
+func (k *KeyHandle) KeyType() kms.KeyType {
+	return k.keyType
+}
+
+// PublicKey returns the public key of the key pair: *ecdsa.PublicKey for NIST P curves, ed25519.PublicKey for Ed25519
+// and the raw X25519 public key as []byte.
+func (k *KeyHandle) PublicKey() interface{} {
+	return k.pubKey
+}
+
+// Sign signs msg with the private key in the token. ECDSA signatures are DER or IEEE-P1363 encoded depending on the
+// key type, with the same hash functions as the localkms keys of that type.
+func (k *KeyHandle) Sign(msg []byte) ([]byte, error) {
+	if k.spec.keyAgreement {
+		return nil, fmt.Errorf("sign: key type '%s' is not a signing key type", k.keyType)
+	}
+
+	if k.spec.curve == nil {
+		sig, err := k.hsm.sign(ckmEdDSA, k.privObj, msg)
+		if err != nil {
+			return nil, fmt.Errorf("sign: %w", err)
+		}
+
+		return sig, nil
+	}
+
+	h := signatureHash(k.keyType)()
+	h.Write(msg) // nolint:errcheck // hash.Hash Write() never returns an error
+
+	sig, err := k.hsm.sign(pkcs11.CKM_ECDSA, k.privObj, h.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	switch k.keyType {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER:
+		return ieeeP1363ToDER(sig)
+	default:
+		return sig, nil
+	}
+}
+
+// Verify verifies signature of msg with the public key of the key pair. The public key does not need the token, the
+// verification is done in software.
+func (k *KeyHandle) Verify(signature, msg []byte) error {
+	switch pubKey := k.pubKey.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pubKey, msg, signature) {
+			return errors.New("verify: invalid signature")
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		return verifyECDSA(k.keyType, pubKey, signature, msg)
+	default:
+		return fmt.Errorf("verify: key type '%s' is not a signing key type", k.keyType)
+	}
+}
+
+func verifyECDSA(kt kms.KeyType, pubKey *ecdsa.PublicKey, signature, msg []byte) error {
+	h := signatureHash(kt)()
+	h.Write(msg) // nolint:errcheck // hash.Hash Write() never returns an error
+
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER:
+		if !ecdsa.VerifyASN1(pubKey, h.Sum(nil), signature) {
+			return errors.New("verify: invalid signature")
+		}
+
+		return nil
+	}
+
+	size := coordinateSize(pubKey.Curve)
+	if len(signature) != 2*size {
+		return errors.New("verify: invalid signature size")
+	}
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+
+	if !ecdsa.Verify(pubKey, h.Sum(nil), r, s) {
+		return errors.New("verify: invalid signature")
+	}
+
+	return nil
+}
+
+// DeriveSharedSecret computes in the token the ECDH shared secret (Z) of the private key and peerPubKey.
+// 'peerPubKey' is an *ecdsa.PublicKey on the same NIST P curve as the key, or the raw X25519 public key as []byte.
+func (k *KeyHandle) DeriveSharedSecret(peerPubKey interface{}) ([]byte, error) {
+	if !k.spec.keyAgreement {
+		return nil, fmt.Errorf("deriveSharedSecret: key type '%s' is not a key agreement key type", k.keyType)
+	}
+
+	var point []byte
+
+	switch pk := peerPubKey.(type) {
+	case *ecdsa.PublicKey:
+		if k.spec.curve == nil || pk.Curve != k.spec.curve {
+			return nil, errors.New("deriveSharedSecret: peer key is not on the curve of the key")
+		}
+
+		point = elliptic.Marshal(pk.Curve, pk.X, pk.Y)
+	case []byte:
+		if k.spec.curve != nil || len(pk) != x25519KeySize {
+			return nil, errors.New("deriveSharedSecret: peer key is not an X25519 key")
+		}
+
+		point = pk
+	default:
+		return nil, fmt.Errorf("deriveSharedSecret: peer key type %T not supported", peerPubKey)
+	}
+
+	z, err := k.hsm.deriveSharedSecret(k.privObj, point, k.spec.sharedSecretSize())
+	if err != nil {
+		return nil, fmt.Errorf("deriveSharedSecret: %w", err)
+	}
+
+	return z, nil
+}
+
+func (h *HSMKMS) sign(mechanism uint, privObj pkcs11.ObjectHandle, data []byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err := h.ctx.SignInit(h.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, privObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize signature: %w", err)
+	}
+
+	sig, err := h.ctx.Sign(h.session, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return sig, nil
+}
+
+// deriveSharedSecret derives with ECDH a session secret key of size bytes and extracts its value.
+func (h *HSMKMS) deriveSharedSecret(privObj pkcs11.ObjectHandle, peerPoint []byte, size int) ([]byte, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, size),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	secretObj, err := h.ctx.DeriveKey(h.session, []*pkcs11.Mechanism{h.ecdhMechanism(peerPoint)}, privObj, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+
+	defer h.destroyObjects(secretObj)
+
+	attrs, err := h.ctx.GetAttributeValue(h.session, secretObj,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shared secret: %w", err)
+	}
+
+	return attrs[0].Value, nil
+}
+
+func ecdh1DeriveMechanism(peerPubKey []byte) *pkcs11.Mechanism {
+	return pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, pkcs11.NewECDH1DeriveParams(pkcs11.CKD_NULL, nil, peerPubKey))
+}
+
+// signatureHash returns the hash function of the ECDSA key type kt. It matches the verifier key handles created by
+// localkms.PublicKeyBytesToHandle().
+func signatureHash(kt kms.KeyType) func() hash.Hash {
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return sha256.New
+	default:
+		return sha512.New
+	}
+}
+
+// ieeeP1363ToDER converts a r||s ECDSA signature into an ASN.1 DER signature.
+func ieeeP1363ToDER(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, errors.New("sign: invalid ECDSA signature size")
+	}
+
+	half := len(sig) / 2
+
+	derSig, err := asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(sig[:half]),
+		S: new(big.Int).SetBytes(sig[half:]),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign: failed to marshal DER signature: %w", err)
+	}
+
+	return derSig, nil
+}
@@ -0,0 +1,193 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// metadataTagName tags the key metadata entries in order to list them.
+const metadataTagName = "kmsKeyMetadata"
+
+// List returns the metadata of the keys matching filter. A nil filter matches all keys.
+// Note: keys added to the token outside of the KMS are not listed.
+// Returns:
+//  - list of key metadata sorted by creation time
+//  - error if failure
+func (h *HSMKMS) List(filter *kms.KeyFilter) ([]*kms.KeyMetadata, error) {
+	iter, err := h.metadataStore.Query(metadataTagName)
+	if err != nil {
+		return nil, fmt.Errorf("list: failed to query key metadata: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	var result []*kms.KeyMetadata
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("list: failed to get next key metadata: %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		src, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("list: failed to get key metadata: %w", err)
+		}
+
+		md := &kms.KeyMetadata{}
+		if err = json.Unmarshal(src, md); err != nil {
+			return nil, fmt.Errorf("list: failed to unmarshal key metadata: %w", err)
+		}
+
+		if filter.Match(md) {
+			result = append(result, md)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created.Equal(result[j].Created) {
+			return result[i].KeyID < result[j].KeyID
+		}
+
+		return result[i].Created.Before(result[j].Created)
+	})
+
+	return result, nil
+}
+
+// Delete destroys the key pair referenced by keyID in the token and removes its metadata.
+// Returns:
+//  - error if the key is not found or failed to be deleted
+func (h *HSMKMS) Delete(keyID string) error {
+	kh, err := h.getKeyHandle(keyID)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err = h.destroyKeyPair(kh); err != nil {
+		return fmt.Errorf("delete: failed to destroy key '%s': %w", keyID, err)
+	}
+
+	err = h.metadataStore.Delete(keyID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete: failed to delete metadata of key '%s': %w", keyID, err)
+	}
+
+	return nil
+}
+
+// SetMetadata sets the label, purpose, owning DID and usage constraint of the key referenced by keyID.
+// The key ID, key type and creation time are managed by the KMS and cannot be set.
+// Returns:
+//  - error if the key is not found or failed to store its metadata
+func (h *HSMKMS) SetMetadata(keyID string, metadata *kms.KeyMetadata) error {
+	if metadata == nil {
+		return errors.New("setMetadata: metadata is nil")
+	}
+
+	md, err := h.GetMetadata(keyID)
+	if err != nil {
+		return fmt.Errorf("setMetadata: %w", err)
+	}
+
+	md.Label = metadata.Label
+	md.Purpose = metadata.Purpose
+	md.DID = metadata.DID
+	md.Usage = metadata.Usage
+
+	if err = h.saveMetadata(md); err != nil {
+		return fmt.Errorf("setMetadata: failed to store key metadata: %w", err)
+	}
+
+	return nil
+}
+
+// GetMetadata returns the metadata of the key referenced by keyID.
+// The metadata of a key added to the token outside of the KMS contains only the key ID and type.
+// Returns:
+//  - key metadata
+//  - error if the key is not found
+func (h *HSMKMS) GetMetadata(keyID string) (*kms.KeyMetadata, error) {
+	md, err := h.getMetadata(keyID)
+	if err == nil {
+		return md, nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("getMetadata: failed to get metadata of key '%s': %w", keyID, err)
+	}
+
+	kh, err := h.getKeyHandle(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("getMetadata: %w", err)
+	}
+
+	return &kms.KeyMetadata{KeyID: keyID, KeyType: kh.keyType}, nil
+}
+
+func (h *HSMKMS) getMetadata(keyID string) (*kms.KeyMetadata, error) {
+	src, err := h.metadataStore.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &kms.KeyMetadata{}
+	if err = json.Unmarshal(src, md); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+func (h *HSMKMS) saveMetadata(md *kms.KeyMetadata) error {
+	src, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+
+	return h.metadataStore.Put(md.KeyID, src, storage.Tag{Name: metadataTagName})
+}
+
+// rotateMetadata moves the metadata of the rotated key to the new key ID.
+func (h *HSMKMS) rotateMetadata(oldID, newID string, kt kms.KeyType) (*kms.KeyMetadata, error) {
+	md, err := h.getMetadata(oldID)
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+		md = &kms.KeyMetadata{}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get key metadata: %w", err)
+	default:
+		if err = h.metadataStore.Delete(oldID); err != nil {
+			return nil, fmt.Errorf("failed to delete key metadata: %w", err)
+		}
+	}
+
+	md.KeyID = newID
+	md.KeyType = kt
+	md.Created = time.Now().UTC()
+
+	if err = h.saveMetadata(md); err != nil {
+		return nil, fmt.Errorf("failed to store key metadata: %w", err)
+	}
+
+	return md, nil
+}
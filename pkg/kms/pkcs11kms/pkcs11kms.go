@@ -0,0 +1,663 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/jwkkid"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Namespace is the DB storage namespace of the key metadata.
+const Namespace = "pkcs11kmsdb"
+
+const tempKeyIDSize = 16
+
+var logger = log.New("aries-framework/kms/pkcs11kms")
+
+// package pkcs11kms is a KMS implementation of pkg/kms.KeyManager storing keys in a PKCS#11 token (eg an HSM or
+// SoftHSMv2). Private keys never leave the token, the key handles returned by this KMS must be used with the matching
+// Crypto implementation found in pkg/crypto/pkcs11kms.
+
+// Config holds the PKCS#11 settings of HSMKMS.
+type Config struct {
+	// Library is the path of the PKCS#11 module (eg /usr/lib/softhsm/libsofthsm2.so).
+	Library string
+	// TokenLabel is the label of the token storing the keys.
+	TokenLabel string
+	// PIN is the user PIN of the token.
+	PIN string
+}
+
+// HSMKMS implements kms.KeyManager to provide key management capabilities using a PKCS#11 token. Keys are
+// identified in the token by their CKA_ID (the key ID) and their CKA_LABEL (the kms.KeyType).
+// Supported key types are ECDSA (NIST P curves) and Ed25519 signing keys, and NIST P and X25519 ECDH key wrapping
+// keys. Ed25519 and X25519 keys require a PKCS#11 v3.0 token.
+type HSMKMS struct {
+	ctx           token
+	session       pkcs11.SessionHandle
+	metadataStore storage.Store
+	// mu serializes the calls to the PKCS#11 session.
+	mu            sync.Mutex
+	ecdhMechanism func(peerPubKey []byte) *pkcs11.Mechanism
+}
+
+// New creates a new HSMKMS logged into the PKCS#11 token configured in cfg. The key metadata is stored in the
+// storage provider of p.
+func New(cfg *Config, p kms.Provider) (*HSMKMS, error) {
+	if cfg == nil || cfg.Library == "" {
+		return nil, errors.New("new: missing PKCS#11 library")
+	}
+
+	ctx := pkcs11.New(cfg.Library)
+	if ctx == nil {
+		return nil, fmt.Errorf("new: failed to load PKCS#11 library '%s'", cfg.Library)
+	}
+
+	return newHSMKMS(ctx, cfg, p)
+}
+
+func newHSMKMS(ctx token, cfg *Config, p kms.Provider) (*HSMKMS, error) {
+	metadataStore, err := p.StorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("new: failed to open key metadata store: %w", err)
+	}
+
+	err = ctx.Initialize()
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		return nil, fmt.Errorf("new: failed to initialize PKCS#11 library: %w", err)
+	}
+
+	session, err := openSession(ctx, cfg)
+	if err != nil {
+		finalize(ctx)
+
+		return nil, fmt.Errorf("new: %w", err)
+	}
+
+	return &HSMKMS{
+		ctx:           ctx,
+		session:       session,
+		metadataStore: metadataStore,
+		ecdhMechanism: ecdh1DeriveMechanism,
+	}, nil
+}
+
+func openSession(ctx token, cfg *Config) (pkcs11.SessionHandle, error) {
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	if err != nil {
+		return 0, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open session: %w", err)
+	}
+
+	err = ctx.Login(session, pkcs11.CKU_USER, cfg.PIN)
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		if e := ctx.CloseSession(session); e != nil {
+			logger.Warnf("failed to close PKCS#11 session: %s", e)
+		}
+
+		return 0, fmt.Errorf("failed to login: %w", err)
+	}
+
+	return session, nil
+}
+
+func finalize(ctx token) {
+	if err := ctx.Finalize(); err != nil {
+		logger.Warnf("failed to finalize PKCS#11 library: %s", err)
+	}
+
+	ctx.Destroy()
+}
+
+// Close logs out of the token, closes the KMS session and unloads the PKCS#11 library.
+func (h *HSMKMS) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.ctx.Logout(h.session); err != nil {
+		logger.Warnf("failed to logout of PKCS#11 token: %s", err)
+	}
+
+	if err := h.ctx.CloseSession(h.session); err != nil {
+		return fmt.Errorf("close: failed to close session: %w", err)
+	}
+
+	finalize(h.ctx)
+
+	return nil
+}
+
+// Create a new key pair of type kt in the token
+// Returns:
+//  - keyID of the key
+//  - handle instance (*KeyHandle to the private key)
+//  - error if failure
+func (h *HSMKMS) Create(kt kms.KeyType) (string, interface{}, error) {
+	if kt == "" {
+		return "", nil, fmt.Errorf("failed to create new key, missing key type")
+	}
+
+	kh, err := h.generateKeyPair(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("create: %w", err)
+	}
+
+	err = h.saveMetadata(&kms.KeyMetadata{KeyID: kh.keyID, KeyType: kt, Created: time.Now().UTC()})
+	if err != nil {
+		return "", nil, fmt.Errorf("create: failed to store key metadata: %w", err)
+	}
+
+	return kh.keyID, kh, nil
+}
+
+// Get key handle for the given keyID
+// Returns:
+//  - handle instance (*KeyHandle to the private key), a *kms.UsageConstrainedHandle if the key has a usage
+//    constraint
+//  - error if failure, wrapping storage.ErrDataNotFound if the key is not in the token
+func (h *HSMKMS) Get(keyID string) (interface{}, error) {
+	kh, err := h.getKeyHandle(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	md, err := h.getMetadata(keyID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("get: failed to get key metadata: %w", err)
+	}
+
+	if md != nil && md.Usage != kms.KeyUsageAny {
+		return &kms.UsageConstrainedHandle{Handle: kh, Usage: md.Usage}, nil
+	}
+
+	return kh, nil
+}
+
+// Rotate replaces the key referenced by keyID with a new key of type kt. Since a PKCS#11 key pair cannot hold more
+// than one key, the old key is destroyed.
+// Returns:
+//  - new KeyID
+//  - handle instance (*KeyHandle to the new private key), a *kms.UsageConstrainedHandle if the key has a usage
+//    constraint
+//  - error if failure
+func (h *HSMKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, error) {
+	oldKH, err := h.getKeyHandle(keyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	kh, err := h.generateKeyPair(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	md, err := h.rotateMetadata(keyID, kh.keyID, kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: %w", err)
+	}
+
+	err = h.destroyKeyPair(oldKH)
+	if err != nil {
+		return "", nil, fmt.Errorf("rotate: failed to destroy key '%s': %w", keyID, err)
+	}
+
+	if md.Usage != kms.KeyUsageAny {
+		return kh.keyID, &kms.UsageConstrainedHandle{Handle: kh, Usage: md.Usage}, nil
+	}
+
+	return kh.keyID, kh, nil
+}
+
+// ExportPubKeyBytes will fetch a key referenced by id then gets its public key in raw bytes and returns it.
+// The key must be an asymmetric key.
+// Returns:
+//  - marshalled public key []byte
+//  - error if it fails to export the public key bytes
+func (h *HSMKMS) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	kh, err := h.getKeyHandle(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("exportPubKeyBytes: %w", err)
+	}
+
+	pubKeyBytes, err := marshalPublicKey(kh.keyType, kh.pubKey, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("exportPubKeyBytes: %w", err)
+	}
+
+	return pubKeyBytes, nil
+}
+
+// CreateAndExportPubKeyBytes will create a key of type kt and export its public key in raw bytes and returns it.
+// The key must be an asymmetric key.
+// Returns:
+//  - keyID of the new key
+//  - marshalled public key []byte
+//  - error if it fails to create or export the public key bytes
+func (h *HSMKMS) CreateAndExportPubKeyBytes(kt kms.KeyType) (string, []byte, error) {
+	keyID, _, err := h.Create(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("createAndExportPubKeyBytes: failed to create new key: %w", err)
+	}
+
+	pubKeyBytes, err := h.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return "", nil, fmt.Errorf("createAndExportPubKeyBytes: failed to export new public key bytes: %w", err)
+	}
+
+	return keyID, pubKeyBytes, nil
+}
+
+// PubKeyBytesToHandle will create and return a Tink key handle for pubKey of type kt. Public key operations do not
+// need the token, the returned handle can be used with the pkg/crypto/pkcs11kms Crypto to verify signatures.
+// Note: The key handle created is not stored in the KMS.
+func (h *HSMKMS) PubKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (interface{}, error) {
+	kh, err := localkms.PublicKeyBytesToHandle(pubKey, kt)
+	if err != nil {
+		return nil, fmt.Errorf("pubKeyBytesToHandle: %w", err)
+	}
+
+	return kh, nil
+}
+
+// ImportPrivateKey will import privKey into the token for the given keyType then returns the new key id and
+// the handle of the imported key.
+// 'privKey' possible types are: *ecdsa.PrivateKey and ed25519.PrivateKey
+// 'keyType' possible types are signing key types only (ECDSA keys or Ed25519)
+// 'opts' allows setting the keyID of the imported key using WithKeyID() option. If the ID is already used,
+// then an error is returned.
+// Returns:
+//  - keyID of the key
+//  - handle instance (*KeyHandle to the private key)
+//  - error if import failure (key empty, invalid, doesn't match keyType, unsupported keyType or storing key failed)
+func (h *HSMKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	spec, err := getKeySpec(kt)
+	if err != nil {
+		return "", nil, fmt.Errorf("importPrivateKey: %w", err)
+	}
+
+	if spec.keyAgreement {
+		return "", nil, fmt.Errorf("importPrivateKey: %w: '%s' is not a signing key type", errInvalidKeyType, kt)
+	}
+
+	value, pubKey, err := privateKeyValue(privKey, spec)
+	if err != nil {
+		return "", nil, fmt.Errorf("importPrivateKey: %w", err)
+	}
+
+	pOpts := kms.NewOpt()
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	keyID := pOpts.KsID()
+	if keyID == "" {
+		keyID, err = createKID(kt, pubKey)
+		if err != nil {
+			return "", nil, fmt.Errorf("importPrivateKey: %w", err)
+		}
+	}
+
+	kh, err := h.createKeyPair(keyID, kt, spec, value, pubKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("importPrivateKey: %w", err)
+	}
+
+	err = h.saveMetadata(&kms.KeyMetadata{KeyID: keyID, KeyType: kt, Created: time.Now().UTC()})
+	if err != nil {
+		return "", nil, fmt.Errorf("importPrivateKey: failed to store key metadata: %w", err)
+	}
+
+	return keyID, kh, nil
+}
+
+// privateKeyValue returns the CKA_VALUE of privKey along with its public key.
+func privateKeyValue(privKey interface{}, spec *keySpec) ([]byte, interface{}, error) {
+	switch k := privKey.(type) {
+	case *ecdsa.PrivateKey:
+		if k == nil || spec.curve == nil || k.Curve != spec.curve {
+			return nil, nil, errors.New("private key does not match key type")
+		}
+
+		value := make([]byte, coordinateSize(spec.curve))
+		k.D.FillBytes(value)
+
+		return value, &k.PublicKey, nil
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize || spec.keyType != ckkECEdwards {
+			return nil, nil, errors.New("private key does not match key type")
+		}
+
+		return k.Seed(), k.Public(), nil
+	default:
+		return nil, nil, fmt.Errorf("private key type %T not supported", privKey)
+	}
+}
+
+func (h *HSMKMS) generateKeyPair(kt kms.KeyType) (*KeyHandle, error) {
+	spec, err := getKeySpec(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	tempID := make([]byte, tempKeyIDSize)
+
+	_, err = rand.Read(tempID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate temporary key ID: %w", err)
+	}
+
+	pubTemplate, privTemplate, err := keyTemplates(kt, spec, tempID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pubObj, privObj, err := h.ctx.GenerateKeyPair(h.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(spec.mechanism, nil)}, pubTemplate, privTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	kh := &KeyHandle{hsm: h, keyType: kt, spec: spec, privObj: privObj, pubObj: pubObj}
+
+	// the key ID is the KID of the public key, which is only known once the key pair is generated.
+	err = h.setKeyID(kh)
+	if err != nil {
+		h.destroyObjects(pubObj, privObj)
+
+		return nil, err
+	}
+
+	return kh, nil
+}
+
+func (h *HSMKMS) setKeyID(kh *KeyHandle) error {
+	pubKey, err := h.readPublicKey(kh.spec, kh.pubObj)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := createKID(kh.keyType, pubKey)
+	if err != nil {
+		return err
+	}
+
+	idAttr := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID))}
+
+	for _, obj := range []pkcs11.ObjectHandle{kh.pubObj, kh.privObj} {
+		err = h.ctx.SetAttributeValue(h.session, obj, idAttr)
+		if err != nil {
+			return fmt.Errorf("failed to set key ID: %w", err)
+		}
+	}
+
+	kh.keyID = keyID
+	kh.pubKey = pubKey
+
+	return nil
+}
+
+func (h *HSMKMS) createKeyPair(keyID string, kt kms.KeyType, spec *keySpec, value []byte,
+	pubKey interface{}) (*KeyHandle, error) {
+	pubTemplate, privTemplate, err := keyTemplates(kt, spec, []byte(keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	point, err := marshalPoint(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ecPoint, err := asn1MarshalOctetString(point)
+	if err != nil {
+		return nil, err
+	}
+
+	ecParams, err := spec.ecParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EC params: %w", err)
+	}
+
+	pubTemplate = append(pubTemplate, pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, ecPoint))
+	// unlike C_GenerateKeyPair, C_CreateObject requires the curve of the private key.
+	privTemplate = append(privTemplate,
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, value))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err = h.findObject(pkcs11.CKO_PRIVATE_KEY, keyID)
+	if err == nil {
+		return nil, fmt.Errorf("key '%s' already exists", keyID)
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, err
+	}
+
+	privObj, err := h.ctx.CreateObject(h.session, privTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create private key object: %w", err)
+	}
+
+	pubObj, err := h.ctx.CreateObject(h.session, pubTemplate)
+	if err != nil {
+		h.destroyObjects(privObj)
+
+		return nil, fmt.Errorf("failed to create public key object: %w", err)
+	}
+
+	return &KeyHandle{
+		hsm:     h,
+		keyID:   keyID,
+		keyType: kt,
+		spec:    spec,
+		privObj: privObj,
+		pubObj:  pubObj,
+		pubKey:  pubKey,
+	}, nil
+}
+
+func keyTemplates(kt kms.KeyType, spec *keySpec, keyID []byte) ([]*pkcs11.Attribute, []*pkcs11.Attribute, error) {
+	ecParams, err := spec.ecParams()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal EC params: %w", err)
+	}
+
+	pubTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, spec.keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, keyID),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, string(kt)),
+	}
+
+	privTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, spec.keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, keyID),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, string(kt)),
+	}
+
+	if spec.keyAgreement {
+		privTemplate = append(privTemplate, pkcs11.NewAttribute(pkcs11.CKA_DERIVE, true))
+	} else {
+		pubTemplate = append(pubTemplate, pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true))
+		privTemplate = append(privTemplate, pkcs11.NewAttribute(pkcs11.CKA_SIGN, true))
+	}
+
+	return pubTemplate, privTemplate, nil
+}
+
+func (h *HSMKMS) getKeyHandle(keyID string) (*KeyHandle, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	privObj, err := h.findObject(pkcs11.CKO_PRIVATE_KEY, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubObj, err := h.findObject(pkcs11.CKO_PUBLIC_KEY, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := h.ctx.GetAttributeValue(h.session, privObj,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_LABEL, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key type of key '%s': %w", keyID, err)
+	}
+
+	kt := kms.KeyType(attrs[0].Value)
+
+	spec, err := getKeySpec(kt)
+	if err != nil {
+		return nil, fmt.Errorf("key '%s': %w", keyID, err)
+	}
+
+	pubKey, err := h.readPublicKey(spec, pubObj)
+	if err != nil {
+		return nil, fmt.Errorf("key '%s': %w", keyID, err)
+	}
+
+	return &KeyHandle{
+		hsm:     h,
+		keyID:   keyID,
+		keyType: kt,
+		spec:    spec,
+		privObj: privObj,
+		pubObj:  pubObj,
+		pubKey:  pubKey,
+	}, nil
+}
+
+// findObject returns the object of class with CKA_ID keyID. It must be called with h.mu locked.
+func (h *HSMKMS) findObject(class uint, keyID string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(keyID)),
+	}
+
+	err := h.ctx.FindObjectsInit(h.session, template)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find key '%s': %w", keyID, err)
+	}
+
+	objects, _, err := h.ctx.FindObjects(h.session, 1)
+
+	if e := h.ctx.FindObjectsFinal(h.session); e != nil && err == nil {
+		err = e
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to find key '%s': %w", keyID, err)
+	}
+
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("key '%s' not found: %w", keyID, storage.ErrDataNotFound)
+	}
+
+	return objects[0], nil
+}
+
+// readPublicKey reads the public key of pubObj. It must be called with h.mu locked.
+func (h *HSMKMS) readPublicKey(spec *keySpec, pubObj pkcs11.ObjectHandle) (interface{}, error) {
+	attrs, err := h.ctx.GetAttributeValue(h.session, pubObj,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	point, err := spec.decodeECPoint(attrs[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	return spec.publicKey(point)
+}
+
+func (h *HSMKMS) destroyKeyPair(kh *KeyHandle) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, obj := range []pkcs11.ObjectHandle{kh.privObj, kh.pubObj} {
+		if err := h.ctx.DestroyObject(h.session, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// destroyObjects destroys objects after a failed key creation. It must be called with h.mu locked.
+func (h *HSMKMS) destroyObjects(objects ...pkcs11.ObjectHandle) {
+	for _, obj := range objects {
+		if err := h.ctx.DestroyObject(h.session, obj); err != nil {
+			logger.Warnf("failed to destroy PKCS#11 object: %s", err)
+		}
+	}
+}
+
+func createKID(kt kms.KeyType, pubKey interface{}) (string, error) {
+	pubKeyBytes, err := marshalPublicKey(kt, pubKey, "")
+	if err != nil {
+		return "", err
+	}
+
+	keyID, err := jwkkid.CreateKID(pubKeyBytes, kt)
+	if err != nil {
+		return "", fmt.Errorf("failed to create key ID: %w", err)
+	}
+
+	return keyID, nil
+}
+
+// marshalPoint returns the uncompressed point of an EC public key or the raw Ed25519 and X25519 public keys.
+func marshalPoint(pubKey interface{}) ([]byte, error) {
+	switch k := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		return elliptic.Marshal(k.Curve, k.X, k.Y), nil
+	case ed25519.PublicKey:
+		return k, nil
+	case []byte:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("public key type %T not supported", pubKey)
+	}
+}
@@ -0,0 +1,587 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var testConfig = &Config{TokenLabel: "aries", PIN: "1234"}
+
+func TestNew(t *testing.T) {
+	t.Run("missing library", func(t *testing.T) {
+		_, err := New(&Config{}, newMockProvider())
+		require.EqualError(t, err, "new: missing PKCS#11 library")
+
+		_, err = New(nil, newMockProvider())
+		require.EqualError(t, err, "new: missing PKCS#11 library")
+	})
+
+	t.Run("invalid library", func(t *testing.T) {
+		_, err := New(&Config{Library: "/invalid/libpkcs11.so"}, newMockProvider())
+		require.EqualError(t, err, "new: failed to load PKCS#11 library '/invalid/libpkcs11.so'")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		hsm, err := newHSMKMS(newMockToken(), testConfig, newMockProvider())
+		require.NoError(t, err)
+		require.NoError(t, hsm.Close())
+	})
+
+	t.Run("fail to open metadata store", func(t *testing.T) {
+		p := newMockProvider()
+		p.storage.ErrOpenStoreHandle = errors.New("open error")
+
+		_, err := newHSMKMS(newMockToken(), testConfig, p)
+		require.EqualError(t, err, "new: failed to open key metadata store: open error")
+	})
+
+	t.Run("library already initialized", func(t *testing.T) {
+		token := newMockToken()
+		token.initErr = pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)
+
+		_, err := newHSMKMS(token, testConfig, newMockProvider())
+		require.NoError(t, err)
+	})
+
+	t.Run("fail to initialize library", func(t *testing.T) {
+		token := newMockToken()
+		token.initErr = pkcs11.Error(pkcs11.CKR_GENERAL_ERROR)
+
+		_, err := newHSMKMS(token, testConfig, newMockProvider())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new: failed to initialize PKCS#11 library")
+	})
+
+	t.Run("token not found", func(t *testing.T) {
+		_, err := newHSMKMS(newMockToken(), &Config{TokenLabel: "unknown"}, newMockProvider())
+		require.EqualError(t, err, "new: token 'unknown' not found")
+	})
+
+	t.Run("fail to get slots", func(t *testing.T) {
+		token := newMockToken()
+		token.slotErr = errors.New("slot error")
+
+		_, err := newHSMKMS(token, testConfig, newMockProvider())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "slot error")
+	})
+
+	t.Run("fail to open session", func(t *testing.T) {
+		token := newMockToken()
+		token.openErr = errors.New("session error")
+
+		_, err := newHSMKMS(token, testConfig, newMockProvider())
+		require.EqualError(t, err, "new: failed to open session: session error")
+	})
+
+	t.Run("invalid PIN", func(t *testing.T) {
+		_, err := newHSMKMS(newMockToken(), &Config{TokenLabel: "aries", PIN: "0000"}, newMockProvider())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, pkcs11.Error(pkcs11.CKR_PIN_INCORRECT)))
+	})
+
+	t.Run("fail to close session", func(t *testing.T) {
+		token := newMockToken()
+
+		hsm, err := newHSMKMS(token, testConfig, newMockProvider())
+		require.NoError(t, err)
+
+		token.closeErr = errors.New("close error")
+
+		require.EqualError(t, hsm.Close(), "close: failed to close session: close error")
+	})
+}
+
+func TestHSMKMS_Create(t *testing.T) {
+	hsm, _ := newTestHSMKMS(t)
+
+	keyTypes := []kms.KeyType{
+		kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER,
+		kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+		kms.ED25519Type,
+	}
+
+	for _, kt := range keyTypes {
+		kt := kt
+
+		t.Run("sign and verify with "+string(kt), func(t *testing.T) {
+			keyID, kh, err := hsm.Create(kt)
+			require.NoError(t, err)
+			require.NotEmpty(t, keyID)
+			require.Equal(t, keyID, kh.(*KeyHandle).KeyID())
+			require.Equal(t, kt, kh.(*KeyHandle).KeyType())
+
+			msg := []byte("lorem ipsum")
+
+			sig, err := kh.(*KeyHandle).Sign(msg)
+			require.NoError(t, err)
+			require.NoError(t, kh.(*KeyHandle).Verify(sig, msg))
+			require.Error(t, kh.(*KeyHandle).Verify(sig, []byte("other message")))
+
+			_, err = kh.(*KeyHandle).DeriveSharedSecret([]byte{})
+			require.Error(t, err)
+
+			// the exported public key is compatible with the localkms key handles verified by tinkcrypto.
+			pubKeyBytes, err := hsm.ExportPubKeyBytes(keyID)
+			require.NoError(t, err)
+
+			pubKH, err := hsm.PubKeyBytesToHandle(pubKeyBytes, kt)
+			require.NoError(t, err)
+
+			tc, err := tinkcrypto.New()
+			require.NoError(t, err)
+
+			require.NoError(t, tc.Verify(sig, msg, pubKH))
+
+			gotKH, err := hsm.Get(keyID)
+			require.NoError(t, err)
+			require.Equal(t, kh.(*KeyHandle).PublicKey(), gotKH.(*KeyHandle).PublicKey())
+		})
+	}
+
+	keyTypes = []kms.KeyType{
+		kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType, kms.X25519ECDHKWType,
+	}
+
+	for _, kt := range keyTypes {
+		kt := kt
+
+		t.Run("derive shared secret with "+string(kt), func(t *testing.T) {
+			keyID, pubKeyBytes, err := hsm.CreateAndExportPubKeyBytes(kt)
+			require.NoError(t, err)
+
+			pubKey := &cryptoapi.PublicKey{}
+			require.NoError(t, json.Unmarshal(pubKeyBytes, pubKey))
+			require.Equal(t, keyID, pubKey.KID)
+
+			kh, err := hsm.Get(keyID)
+			require.NoError(t, err)
+
+			_, err = kh.(*KeyHandle).Sign([]byte("msg"))
+			require.Error(t, err)
+
+			peerPubKey, expected := ecdhPeer(t, kh.(*KeyHandle).PublicKey())
+
+			z, err := kh.(*KeyHandle).DeriveSharedSecret(peerPubKey)
+			require.NoError(t, err)
+			require.Equal(t, expected, z)
+		})
+	}
+
+	t.Run("DER public key", func(t *testing.T) {
+		keyID, pubKeyBytes, err := hsm.CreateAndExportPubKeyBytes(kms.ECDSAP256TypeDER)
+		require.NoError(t, err)
+
+		pubKey, err := x509.ParsePKIXPublicKey(pubKeyBytes)
+		require.NoError(t, err)
+
+		kh, err := hsm.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, kh.(*KeyHandle).PublicKey(), pubKey)
+	})
+
+	t.Run("invalid key type", func(t *testing.T) {
+		_, _, err := hsm.Create("")
+		require.EqualError(t, err, "failed to create new key, missing key type")
+
+		_, _, err = hsm.Create(kms.AES128GCMType)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errInvalidKeyType))
+
+		_, _, err = hsm.CreateAndExportPubKeyBytes(kms.AES128GCMType)
+		require.Error(t, err)
+	})
+
+	t.Run("token errors", func(t *testing.T) {
+		hsm, token := newTestHSMKMS(t)
+
+		token.genErr = errors.New("generate error")
+
+		_, _, err := hsm.Create(kms.ED25519Type)
+		require.EqualError(t, err, "create: failed to generate key pair: generate error")
+
+		token.genErr = nil
+		token.setAttrErr = errors.New("set error")
+
+		_, _, err = hsm.Create(kms.ED25519Type)
+		require.EqualError(t, err, "create: failed to set key ID: set error")
+		require.Empty(t, token.objects)
+
+		token.setAttrErr = nil
+		token.invalidPoints = true
+
+		_, _, err = hsm.Create(kms.ECDSAP256TypeDER)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read public key")
+		require.Empty(t, token.objects)
+
+		token.invalidPoints = false
+
+		keyID, kh, err := hsm.Create(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		token.signErr = errors.New("sign error")
+
+		_, err = kh.(*KeyHandle).Sign([]byte("msg"))
+		require.EqualError(t, err, "sign: failed to initialize signature: sign error")
+
+		token.findErr = errors.New("find error")
+
+		_, err = hsm.Get(keyID)
+		require.EqualError(t, err, "get: failed to find key '"+keyID+"': find error")
+
+		_, err = hsm.ExportPubKeyBytes(keyID)
+		require.Error(t, err)
+
+		token.findErr = nil
+
+		keyID, kh, err = hsm.Create(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		token.deriveErr = errors.New("derive error")
+
+		_, err = kh.(*KeyHandle).DeriveSharedSecret(kh.(*KeyHandle).PublicKey())
+		require.EqualError(t, err, "deriveSharedSecret: failed to derive shared secret: derive error")
+
+		token.getAttrErr = errors.New("attribute error")
+
+		_, err = hsm.Get(keyID)
+		require.EqualError(t, err, "get: failed to get key type of key '"+keyID+"': attribute error")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		p := newMockProvider()
+		p.storage.Store.ErrPut = errors.New("put error")
+
+		hsm, err := newHSMKMS(newMockToken(), testConfig, p)
+		require.NoError(t, err)
+
+		_, _, err = hsm.Create(kms.ED25519Type)
+		require.EqualError(t, err, "create: failed to store key metadata: put error")
+	})
+}
+
+func TestHSMKMS_Get(t *testing.T) {
+	hsm, _ := newTestHSMKMS(t)
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := hsm.Get("unknown")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("usage constrained key", func(t *testing.T) {
+		keyID, _, err := hsm.Create(kms.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		require.NoError(t, hsm.SetMetadata(keyID, &kms.KeyMetadata{Usage: kms.KeyUsageKeyAgreement}))
+
+		kh, err := hsm.Get(keyID)
+		require.NoError(t, err)
+
+		constrained, ok := kh.(*kms.UsageConstrainedHandle)
+		require.True(t, ok)
+		require.Equal(t, kms.KeyUsageKeyAgreement, constrained.Usage)
+		require.Equal(t, keyID, constrained.Handle.(*KeyHandle).KeyID())
+	})
+}
+
+func TestHSMKMS_Rotate(t *testing.T) {
+	hsm, _ := newTestHSMKMS(t)
+
+	keyID, _, err := hsm.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	require.NoError(t, hsm.SetMetadata(keyID, &kms.KeyMetadata{Label: "signing"}))
+
+	newKeyID, kh, err := hsm.Rotate(kms.ECDSAP256TypeIEEEP1363, keyID)
+	require.NoError(t, err)
+	require.NotEqual(t, keyID, newKeyID)
+	require.Equal(t, kms.ECDSAP256TypeIEEEP1363, kh.(*KeyHandle).KeyType())
+
+	_, err = hsm.Get(keyID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	md, err := hsm.GetMetadata(newKeyID)
+	require.NoError(t, err)
+	require.Equal(t, "signing", md.Label)
+	require.Equal(t, kms.ECDSAP256TypeIEEEP1363, md.KeyType)
+
+	require.NoError(t, hsm.SetMetadata(newKeyID, &kms.KeyMetadata{Usage: kms.KeyUsageSign}))
+
+	newKeyID, kh, err = hsm.Rotate(kms.ECDSAP256TypeIEEEP1363, newKeyID)
+	require.NoError(t, err)
+
+	constrained, ok := kh.(*kms.UsageConstrainedHandle)
+	require.True(t, ok)
+	require.Equal(t, kms.KeyUsageSign, constrained.Usage)
+	require.Equal(t, newKeyID, constrained.Handle.(*KeyHandle).KeyID())
+
+	_, _, err = hsm.Rotate(kms.ED25519Type, "unknown")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	_, _, err = hsm.Rotate(kms.AES128GCMType, newKeyID)
+	require.True(t, errors.Is(err, errInvalidKeyType))
+}
+
+func TestHSMKMS_ImportPrivateKey(t *testing.T) {
+	hsm, token := newTestHSMKMS(t)
+
+	t.Run("import ECDSA key", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		keyID, kh, err := hsm.ImportPrivateKey(privKey, kms.ECDSAP384TypeDER)
+		require.NoError(t, err)
+		require.Equal(t, &privKey.PublicKey, kh.(*KeyHandle).PublicKey())
+
+		sig, err := kh.(*KeyHandle).Sign([]byte("msg"))
+		require.NoError(t, err)
+
+		gotKH, err := hsm.Get(keyID)
+		require.NoError(t, err)
+		require.NoError(t, gotKH.(*KeyHandle).Verify(sig, []byte("msg")))
+	})
+
+	t.Run("import Ed25519 key with key ID", func(t *testing.T) {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keyID, kh, err := hsm.ImportPrivateKey(privKey, kms.ED25519Type, kms.WithKeyID("ed25519"))
+		require.NoError(t, err)
+		require.Equal(t, "ed25519", keyID)
+
+		sig, err := kh.(*KeyHandle).Sign([]byte("msg"))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(privKey.Public().(ed25519.PublicKey), []byte("msg"), sig))
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.ED25519Type, kms.WithKeyID("ed25519"))
+		require.EqualError(t, err, "importPrivateKey: key 'ed25519' already exists")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.NISTP256ECDHKWType)
+		require.True(t, errors.Is(err, errInvalidKeyType))
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.ECDSAP384TypeDER)
+		require.EqualError(t, err, "importPrivateKey: private key does not match key type")
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.ED25519Type)
+		require.EqualError(t, err, "importPrivateKey: private key does not match key type")
+
+		_, _, err = hsm.ImportPrivateKey(ed25519.PrivateKey{}, kms.ED25519Type)
+		require.EqualError(t, err, "importPrivateKey: private key does not match key type")
+
+		_, _, err = hsm.ImportPrivateKey("key", kms.ED25519Type)
+		require.EqualError(t, err, "importPrivateKey: private key type string not supported")
+	})
+
+	t.Run("fail to create objects", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		token.createErr = errors.New("create error")
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.ECDSAP256TypeDER)
+		require.EqualError(t, err, "importPrivateKey: failed to create private key object: create error")
+
+		token.createErr = nil
+		token.createPubErr = true
+		objects := len(token.objects)
+
+		_, _, err = hsm.ImportPrivateKey(privKey, kms.ECDSAP256TypeDER)
+		require.EqualError(t, err, "importPrivateKey: failed to create public key object: create public key error")
+		require.Len(t, token.objects, objects)
+
+		token.createPubErr = false
+	})
+}
+
+func TestHSMKMS_Delete(t *testing.T) {
+	hsm, token := newTestHSMKMS(t)
+
+	keyID, _, err := hsm.Create(kms.ECDSAP521TypeIEEEP1363)
+	require.NoError(t, err)
+
+	require.NoError(t, hsm.Delete(keyID))
+	require.Empty(t, token.objects)
+
+	_, err = hsm.GetMetadata(keyID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	err = hsm.Delete(keyID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	keyID, _, err = hsm.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	token.destroyErr = errors.New("destroy error")
+
+	err = hsm.Delete(keyID)
+	require.EqualError(t, err, "delete: failed to destroy key '"+keyID+"': destroy error")
+}
+
+func TestHSMKMS_KeyMetadata(t *testing.T) {
+	hsm, token := newTestHSMKMS(t)
+
+	signKID, _, err := hsm.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	kwKID, _, err := hsm.Create(kms.X25519ECDHKWType)
+	require.NoError(t, err)
+
+	require.NoError(t, hsm.SetMetadata(kwKID, &kms.KeyMetadata{
+		KeyID:   "ignored",
+		Label:   "didcomm",
+		Purpose: "key-agreement",
+		DID:     "did:example:alice",
+	}))
+
+	md, err := hsm.GetMetadata(kwKID)
+	require.NoError(t, err)
+	require.Equal(t, kwKID, md.KeyID)
+	require.Equal(t, kms.X25519ECDHKWType, md.KeyType)
+	require.Equal(t, "didcomm", md.Label)
+	require.Equal(t, "did:example:alice", md.DID)
+
+	require.EqualError(t, hsm.SetMetadata(kwKID, nil), "setMetadata: metadata is nil")
+
+	keys, err := hsm.List(nil)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, signKID, keys[0].KeyID)
+
+	keys, err = hsm.List(&kms.KeyFilter{DID: "did:example:alice"})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, kwKID, keys[0].KeyID)
+
+	t.Run("key created outside of the KMS", func(t *testing.T) {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		spec, err := getKeySpec(kms.ED25519Type)
+		require.NoError(t, err)
+
+		value, pubKey, err := privateKeyValue(privKey, spec)
+		require.NoError(t, err)
+
+		_, err = hsm.createKeyPair("external", kms.ED25519Type, spec, value, pubKey)
+		require.NoError(t, err)
+
+		md, err := hsm.GetMetadata("external")
+		require.NoError(t, err)
+		require.Equal(t, &kms.KeyMetadata{KeyID: "external", KeyType: kms.ED25519Type}, md)
+
+		kh, err := hsm.Get("external")
+		require.NoError(t, err)
+		require.IsType(t, &KeyHandle{}, kh)
+
+		require.NoError(t, hsm.Delete("external"))
+		require.Len(t, token.objects, 4)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := hsm.GetMetadata("unknown")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		err = hsm.SetMetadata("unknown", &kms.KeyMetadata{})
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
+
+// ecdhPeer returns a new peer public key for pubKey and the expected ECDH shared secret of the key pair of pubKey.
+func ecdhPeer(t *testing.T, pubKey interface{}) (interface{}, []byte) {
+	t.Helper()
+
+	switch pk := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		peer, err := ecdsa.GenerateKey(pk.Curve, rand.Reader)
+		require.NoError(t, err)
+
+		x, _ := pk.Curve.ScalarMult(pk.X, pk.Y, peer.D.Bytes())
+		z := make([]byte, coordinateSize(pk.Curve))
+		x.FillBytes(z)
+
+		return &peer.PublicKey, z
+	case []byte:
+		peer := make([]byte, x25519KeySize)
+		_, err := rand.Read(peer)
+		require.NoError(t, err)
+
+		peerPubKey, err := curve25519.X25519(peer, curve25519.Basepoint)
+		require.NoError(t, err)
+
+		z, err := curve25519.X25519(peer, pk)
+		require.NoError(t, err)
+
+		return peerPubKey, z
+	default:
+		require.Failf(t, "unexpected public key type", "%T", pubKey)
+
+		return nil, nil
+	}
+}
+
+func newTestHSMKMS(t *testing.T) (*HSMKMS, *mockToken) {
+	t.Helper()
+
+	token := newMockToken()
+
+	hsm, err := newHSMKMS(token, testConfig, newMockProvider())
+	require.NoError(t, err)
+
+	hsm.ecdhMechanism = mockECDHMechanism
+
+	return hsm, token
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: &noop.NoLock{},
+	}
+}
+
+// mockProvider mocks a provider for KMS storage.
+type mockProvider struct {
+	storage    *mockstorage.MockStoreProvider
+	secretLock secretlock.Service
+}
+
+func (m *mockProvider) StorageProvider() storage.Provider {
+	return m.storage
+}
+
+func (m *mockProvider) SecretLock() secretlock.Service {
+	return m.secretLock
+}
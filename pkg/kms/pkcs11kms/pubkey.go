@@ -0,0 +1,84 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// marshalPublicKey marshals pubKey in the same format as localkms for the key type kt:
+//  - ECDSA DER key types: PKIX DER
+//  - ECDSA IEEE-P1363 key types: uncompressed EC point
+//  - Ed25519: raw public key
+//  - ECDH key wrapping types: JSON marshalled cryptoapi.PublicKey with KID set to kid.
+func marshalPublicKey(kt kms.KeyType, pubKey interface{}, kid string) ([]byte, error) {
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER:
+		return x509.MarshalPKIXPublicKey(pubKey)
+	case kms.NISTP256ECDHKWType, kms.NISTP384ECDHKWType, kms.NISTP521ECDHKWType:
+		ecPubKey, ok := pubKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("marshalPublicKey: public key is not an EC key")
+		}
+
+		return json.Marshal(&cryptoapi.PublicKey{
+			KID:   kid,
+			X:     ecPubKey.X.Bytes(),
+			Y:     ecPubKey.Y.Bytes(),
+			Curve: curveName(ecPubKey.Curve),
+			Type:  ecdhpb.KeyType_EC.String(),
+		})
+	case kms.X25519ECDHKWType:
+		x, ok := pubKey.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("marshalPublicKey: public key is not an X25519 key")
+		}
+
+		return json.Marshal(&cryptoapi.PublicKey{
+			KID:   kid,
+			X:     x,
+			Curve: commonpb.EllipticCurveType_CURVE25519.String(),
+			Type:  ecdhpb.KeyType_OKP.String(),
+		})
+	default:
+		return marshalPoint(pubKey)
+	}
+}
+
+func curveName(curve elliptic.Curve) string {
+	switch curve {
+	case elliptic.P384():
+		return commonpb.EllipticCurveType_NIST_P384.String()
+	case elliptic.P521():
+		return commonpb.EllipticCurveType_NIST_P521.String()
+	default:
+		return commonpb.EllipticCurveType_NIST_P256.String()
+	}
+}
+
+// asn1MarshalOctetString wraps a public key point into a DER OCTET STRING as expected by CKA_EC_POINT.
+func asn1MarshalOctetString(point []byte) ([]byte, error) {
+	ecPoint, err := asn1.Marshal(point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EC point: %w", err)
+	}
+
+	return ecPoint, nil
+}
@@ -0,0 +1,63 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// TestHSMKMS_SoftHSM runs against a real PKCS#11 token when the AFGO_PKCS11_LIBRARY, AFGO_PKCS11_TOKEN_LABEL and
+// AFGO_PKCS11_PIN environment variables are set, eg with SoftHSMv2:
+//  softhsm2-util --init-token --free --label aries --pin 1234 --so-pin 1234
+//  AFGO_PKCS11_LIBRARY=/usr/lib/softhsm/libsofthsm2.so AFGO_PKCS11_TOKEN_LABEL=aries AFGO_PKCS11_PIN=1234 go test
+func TestHSMKMS_SoftHSM(t *testing.T) {
+	cfg := &Config{
+		Library:    os.Getenv("AFGO_PKCS11_LIBRARY"),
+		TokenLabel: os.Getenv("AFGO_PKCS11_TOKEN_LABEL"),
+		PIN:        os.Getenv("AFGO_PKCS11_PIN"),
+	}
+
+	if cfg.Library == "" {
+		t.Skip("AFGO_PKCS11_LIBRARY is not set")
+	}
+
+	hsm, err := New(cfg, newMockProvider())
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, hsm.Close())
+	}()
+
+	for _, kt := range []kms.KeyType{kms.ECDSAP256TypeDER, kms.ECDSAP384TypeIEEEP1363} {
+		keyID, kh, err := hsm.Create(kt)
+		require.NoError(t, err)
+
+		sig, err := kh.(*KeyHandle).Sign([]byte("msg"))
+		require.NoError(t, err)
+		require.NoError(t, kh.(*KeyHandle).Verify(sig, []byte("msg")))
+
+		require.NoError(t, hsm.Delete(keyID))
+	}
+
+	keyID, kh, err := hsm.Create(kms.NISTP256ECDHKWType)
+	require.NoError(t, err)
+
+	peerPubKey, expected := ecdhPeer(t, kh.(*KeyHandle).PublicKey())
+
+	z, err := kh.(*KeyHandle).DeriveSharedSecret(peerPubKey)
+	require.NoError(t, err)
+	require.Equal(t, expected, z)
+
+	require.NoError(t, hsm.Delete(keyID))
+}
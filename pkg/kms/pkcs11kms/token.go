@@ -0,0 +1,202 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// PKCS#11 v3.0 constants not (yet) defined by github.com/miekg/pkcs11.
+const (
+	ckkECEdwards              = 0x00000040
+	ckkECMontgomery           = 0x00000041
+	ckmECEdwardsKeyPairGen    = 0x00001055
+	ckmECMontgomeryKeyPairGen = 0x00001056
+	ckmEdDSA                  = 0x00001057
+)
+
+const x25519KeySize = 32
+
+// token is the subset of the PKCS#11 API used by the KMS. It is implemented by *pkcs11.Ctx.
+type token interface {
+	Initialize() error
+	Finalize() error
+	Destroy()
+	GetSlotList(tokenPresent bool) ([]uint, error)
+	GetTokenInfo(slotID uint) (pkcs11.TokenInfo, error)
+	OpenSession(slotID uint, flags uint) (pkcs11.SessionHandle, error)
+	CloseSession(sh pkcs11.SessionHandle) error
+	Login(sh pkcs11.SessionHandle, userType uint, pin string) error
+	Logout(sh pkcs11.SessionHandle) error
+	GenerateKeyPair(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism,
+		public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error)
+	CreateObject(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) (pkcs11.ObjectHandle, error)
+	DestroyObject(sh pkcs11.SessionHandle, oh pkcs11.ObjectHandle) error
+	GetAttributeValue(sh pkcs11.SessionHandle, o pkcs11.ObjectHandle,
+		a []*pkcs11.Attribute) ([]*pkcs11.Attribute, error)
+	SetAttributeValue(sh pkcs11.SessionHandle, o pkcs11.ObjectHandle, a []*pkcs11.Attribute) error
+	FindObjectsInit(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
+	FindObjects(sh pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
+	FindObjectsFinal(sh pkcs11.SessionHandle) error
+	SignInit(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Sign(sh pkcs11.SessionHandle, message []byte) ([]byte, error)
+	DeriveKey(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, basekey pkcs11.ObjectHandle,
+		a []*pkcs11.Attribute) (pkcs11.ObjectHandle, error)
+}
+
+// curve OIDs used as CKA_EC_PARAMS.
+var (
+	oidNISTP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNISTP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNISTP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+	oidX25519   = asn1.ObjectIdentifier{1, 3, 101, 110}
+	oidEd25519  = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+var errInvalidKeyType = errors.New("key type is not supported")
+
+// keySpec describes how a kms.KeyType maps to a PKCS#11 key.
+type keySpec struct {
+	keyType   uint
+	mechanism uint
+	curveOID  asn1.ObjectIdentifier
+	// curve is nil for Edwards and Montgomery curves.
+	curve elliptic.Curve
+	// keyAgreement is set for ECDH key wrapping keys, signing keys otherwise.
+	keyAgreement bool
+}
+
+func getKeySpec(kt kms.KeyType) (*keySpec, error) {
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP256,
+			curve: elliptic.P256()}, nil
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP384,
+			curve: elliptic.P384()}, nil
+	case kms.ECDSAP521TypeDER, kms.ECDSAP521TypeIEEEP1363:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP521,
+			curve: elliptic.P521()}, nil
+	case kms.ED25519Type:
+		return &keySpec{keyType: ckkECEdwards, mechanism: ckmECEdwardsKeyPairGen, curveOID: oidEd25519}, nil
+	case kms.NISTP256ECDHKWType:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP256,
+			curve: elliptic.P256(), keyAgreement: true}, nil
+	case kms.NISTP384ECDHKWType:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP384,
+			curve: elliptic.P384(), keyAgreement: true}, nil
+	case kms.NISTP521ECDHKWType:
+		return &keySpec{keyType: pkcs11.CKK_EC, mechanism: pkcs11.CKM_EC_KEY_PAIR_GEN, curveOID: oidNISTP521,
+			curve: elliptic.P521(), keyAgreement: true}, nil
+	case kms.X25519ECDHKWType:
+		return &keySpec{keyType: ckkECMontgomery, mechanism: ckmECMontgomeryKeyPairGen, curveOID: oidX25519,
+			keyAgreement: true}, nil
+	default:
+		return nil, fmt.Errorf("%w: '%s'", errInvalidKeyType, kt)
+	}
+}
+
+// ecParams returns the DER encoded CKA_EC_PARAMS of the key spec.
+func (s *keySpec) ecParams() ([]byte, error) {
+	return asn1.Marshal(s.curveOID)
+}
+
+// sharedSecretSize is the size of the ECDH shared secret (Z) computed with this key.
+func (s *keySpec) sharedSecretSize() int {
+	if s.curve == nil {
+		return x25519KeySize
+	}
+
+	return coordinateSize(s.curve)
+}
+
+func coordinateSize(curve elliptic.Curve) int {
+	const bitsPerByte = 8
+
+	return (curve.Params().BitSize + bitsPerByte - 1) / bitsPerByte
+}
+
+// pointSize is the size of the public key point of this key (uncompressed for NIST P curves).
+func (s *keySpec) pointSize() int {
+	if s.curve == nil {
+		return x25519KeySize
+	}
+
+	return 1 + 2*coordinateSize(s.curve)
+}
+
+// decodeECPoint decodes a CKA_EC_POINT value. Tokens should return the point wrapped in a DER OCTET STRING, but some
+// return the raw point.
+func (s *keySpec) decodeECPoint(ecPoint []byte) ([]byte, error) {
+	if len(ecPoint) == s.pointSize() {
+		return ecPoint, nil
+	}
+
+	var point []byte
+
+	rest, err := asn1.Unmarshal(ecPoint, &point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal EC point: %w", err)
+	}
+
+	if len(rest) > 0 || len(point) != s.pointSize() {
+		return nil, errors.New("invalid EC point")
+	}
+
+	return point, nil
+}
+
+// publicKey converts the decoded EC point of this key into a Go public key: *ecdsa.PublicKey for NIST P curves,
+// ed25519.PublicKey for Ed25519 and the raw []byte key for X25519.
+func (s *keySpec) publicKey(point []byte) (interface{}, error) {
+	switch {
+	case s.curve != nil:
+		x, y := elliptic.Unmarshal(s.curve, point)
+		if x == nil {
+			return nil, errors.New("invalid EC public key")
+		}
+
+		return &ecdsa.PublicKey{Curve: s.curve, X: x, Y: y}, nil
+	case s.keyType == ckkECEdwards:
+		return ed25519.PublicKey(point), nil
+	default:
+		return point, nil
+	}
+}
+
+// findSlot returns the slot ID of the token labeled label.
+func findSlot(ctx token, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slot list: %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info of slot %d: %w", slot, err)
+		}
+
+		if strings.TrimSpace(info.Label) == label {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("token '%s' not found", label)
+}
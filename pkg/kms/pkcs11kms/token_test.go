@@ -0,0 +1,388 @@
+// +build cgo
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11kms
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/miekg/pkcs11"
+	"golang.org/x/crypto/curve25519"
+)
+
+// mockToken is an in-memory PKCS#11 token implementing the token interface with software keys.
+type mockToken struct {
+	label   string
+	pin     string
+	objects map[pkcs11.ObjectHandle]*mockObject
+	last    pkcs11.ObjectHandle
+	found   []pkcs11.ObjectHandle
+	signKey pkcs11.ObjectHandle
+
+	initErr       error
+	slotErr       error
+	tokenInfoErr  error
+	openErr       error
+	genErr        error
+	createErr     error
+	getAttrErr    error
+	setAttrErr    error
+	findErr       error
+	signErr       error
+	deriveErr     error
+	closeErr      error
+	destroyErr    error
+	createPubErr  bool
+	invalidPoints bool
+}
+
+type mockObject struct {
+	attrs   map[uint][]byte
+	privKey interface{}
+}
+
+func newMockToken() *mockToken {
+	return &mockToken{
+		label:   "aries",
+		pin:     "1234",
+		objects: map[pkcs11.ObjectHandle]*mockObject{},
+	}
+}
+
+func (m *mockToken) Initialize() error {
+	return m.initErr
+}
+
+func (m *mockToken) Finalize() error {
+	return nil
+}
+
+func (m *mockToken) Destroy() {}
+
+func (m *mockToken) GetSlotList(bool) ([]uint, error) {
+	return []uint{0, 1}, m.slotErr
+}
+
+func (m *mockToken) GetTokenInfo(slotID uint) (pkcs11.TokenInfo, error) {
+	if slotID == 0 {
+		return pkcs11.TokenInfo{Label: "other"}, m.tokenInfoErr
+	}
+
+	// token labels are blank padded.
+	return pkcs11.TokenInfo{Label: m.label + "   "}, m.tokenInfoErr
+}
+
+func (m *mockToken) OpenSession(uint, uint) (pkcs11.SessionHandle, error) {
+	return 1, m.openErr
+}
+
+func (m *mockToken) CloseSession(pkcs11.SessionHandle) error {
+	return m.closeErr
+}
+
+func (m *mockToken) Login(_ pkcs11.SessionHandle, _ uint, pin string) error {
+	if pin != m.pin {
+		return pkcs11.Error(pkcs11.CKR_PIN_INCORRECT)
+	}
+
+	return nil
+}
+
+func (m *mockToken) Logout(pkcs11.SessionHandle) error {
+	return nil
+}
+
+func (m *mockToken) GenerateKeyPair(_ pkcs11.SessionHandle, mech []*pkcs11.Mechanism,
+	public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error) {
+	if m.genErr != nil {
+		return 0, 0, m.genErr
+	}
+
+	pubObj := &mockObject{attrs: toMap(public)}
+
+	var (
+		privKey interface{}
+		point   []byte
+		err     error
+	)
+
+	switch mech[0].Mechanism {
+	case pkcs11.CKM_EC_KEY_PAIR_GEN:
+		var k *ecdsa.PrivateKey
+
+		k, err = ecdsa.GenerateKey(curveOf(pubObj.attrs[pkcs11.CKA_EC_PARAMS]), rand.Reader)
+		privKey, point = k, elliptic.Marshal(k.Curve, k.X, k.Y)
+	case ckmECEdwardsKeyPairGen:
+		var (
+			pub ed25519.PublicKey
+			k   ed25519.PrivateKey
+		)
+
+		pub, k, err = ed25519.GenerateKey(rand.Reader)
+		privKey, point = k, pub
+	case ckmECMontgomeryKeyPairGen:
+		k := make([]byte, x25519KeySize)
+
+		_, err = rand.Read(k)
+		if err == nil {
+			point, err = curve25519.X25519(k, curve25519.Basepoint)
+		}
+
+		privKey = k
+	default:
+		return 0, 0, pkcs11.Error(pkcs11.CKR_MECHANISM_INVALID)
+	}
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pubObj.attrs[pkcs11.CKA_EC_POINT] = m.ecPoint(point)
+
+	return m.add(pubObj), m.add(&mockObject{attrs: toMap(private), privKey: privKey}), nil
+}
+
+func (m *mockToken) ecPoint(point []byte) []byte {
+	if m.invalidPoints {
+		return []byte("invalid")
+	}
+
+	ecPoint, err := asn1.Marshal(point)
+	if err != nil {
+		panic(err)
+	}
+
+	return ecPoint
+}
+
+func (m *mockToken) CreateObject(_ pkcs11.SessionHandle, temp []*pkcs11.Attribute) (pkcs11.ObjectHandle, error) {
+	if m.createErr != nil {
+		return 0, m.createErr
+	}
+
+	obj := &mockObject{attrs: toMap(temp)}
+
+	if obj.attrs[pkcs11.CKA_EC_POINT] != nil && m.createPubErr {
+		return 0, errors.New("create public key error")
+	}
+
+	if value, ok := obj.attrs[pkcs11.CKA_VALUE]; ok {
+		curve := curveOf(obj.attrs[pkcs11.CKA_EC_PARAMS])
+		if curve == nil {
+			obj.privKey = ed25519.NewKeyFromSeed(value)
+		} else {
+			k := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(value)}
+			k.Curve = curve
+			k.X, k.Y = curve.ScalarBaseMult(value)
+			obj.privKey = k
+		}
+	}
+
+	return m.add(obj), nil
+}
+
+func (m *mockToken) DestroyObject(_ pkcs11.SessionHandle, oh pkcs11.ObjectHandle) error {
+	if m.destroyErr != nil {
+		return m.destroyErr
+	}
+
+	if _, ok := m.objects[oh]; !ok {
+		return pkcs11.Error(pkcs11.CKR_OBJECT_HANDLE_INVALID)
+	}
+
+	delete(m.objects, oh)
+
+	return nil
+}
+
+func (m *mockToken) GetAttributeValue(_ pkcs11.SessionHandle, o pkcs11.ObjectHandle,
+	a []*pkcs11.Attribute) ([]*pkcs11.Attribute, error) {
+	if m.getAttrErr != nil {
+		return nil, m.getAttrErr
+	}
+
+	obj, ok := m.objects[o]
+	if !ok {
+		return nil, pkcs11.Error(pkcs11.CKR_OBJECT_HANDLE_INVALID)
+	}
+
+	result := make([]*pkcs11.Attribute, len(a))
+
+	for i, attr := range a {
+		value, ok := obj.attrs[attr.Type]
+		if !ok {
+			return nil, pkcs11.Error(pkcs11.CKR_ATTRIBUTE_TYPE_INVALID)
+		}
+
+		result[i] = &pkcs11.Attribute{Type: attr.Type, Value: value}
+	}
+
+	return result, nil
+}
+
+func (m *mockToken) SetAttributeValue(_ pkcs11.SessionHandle, o pkcs11.ObjectHandle, a []*pkcs11.Attribute) error {
+	if m.setAttrErr != nil {
+		return m.setAttrErr
+	}
+
+	for _, attr := range a {
+		m.objects[o].attrs[attr.Type] = attr.Value
+	}
+
+	return nil
+}
+
+func (m *mockToken) FindObjectsInit(_ pkcs11.SessionHandle, temp []*pkcs11.Attribute) error {
+	m.found = nil
+
+	for handle, obj := range m.objects {
+		if obj.matches(temp) {
+			m.found = append(m.found, handle)
+		}
+	}
+
+	sort.Slice(m.found, func(i, j int) bool { return m.found[i] < m.found[j] })
+
+	return nil
+}
+
+func (m *mockToken) FindObjects(_ pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error) {
+	if len(m.found) > max {
+		return m.found[:max], false, m.findErr
+	}
+
+	return m.found, false, m.findErr
+}
+
+func (m *mockToken) FindObjectsFinal(pkcs11.SessionHandle) error {
+	m.found = nil
+
+	return nil
+}
+
+func (m *mockToken) SignInit(_ pkcs11.SessionHandle, _ []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error {
+	m.signKey = o
+
+	return m.signErr
+}
+
+func (m *mockToken) Sign(_ pkcs11.SessionHandle, message []byte) ([]byte, error) {
+	switch k := m.objects[m.signKey].privKey.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, message)
+		if err != nil {
+			return nil, err
+		}
+
+		size := coordinateSize(k.Curve)
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+
+		return sig, nil
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, message), nil
+	default:
+		return nil, pkcs11.Error(pkcs11.CKR_KEY_TYPE_INCONSISTENT)
+	}
+}
+
+// DeriveKey expects the mechanism parameter to be the peer public key as set by the mockECDHMechanism.
+func (m *mockToken) DeriveKey(_ pkcs11.SessionHandle, mech []*pkcs11.Mechanism, basekey pkcs11.ObjectHandle,
+	a []*pkcs11.Attribute) (pkcs11.ObjectHandle, error) {
+	if m.deriveErr != nil {
+		return 0, m.deriveErr
+	}
+
+	var z []byte
+
+	switch k := m.objects[basekey].privKey.(type) {
+	case *ecdsa.PrivateKey:
+		x, y := elliptic.Unmarshal(k.Curve, mech[0].Parameter)
+		if x == nil {
+			return 0, pkcs11.Error(pkcs11.CKR_MECHANISM_PARAM_INVALID)
+		}
+
+		zX, _ := k.Curve.ScalarMult(x, y, k.D.Bytes())
+		z = make([]byte, coordinateSize(k.Curve))
+		zX.FillBytes(z)
+	case []byte:
+		var err error
+
+		z, err = curve25519.X25519(k, mech[0].Parameter)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, pkcs11.Error(pkcs11.CKR_KEY_TYPE_INCONSISTENT)
+	}
+
+	attrs := toMap(a)
+	attrs[pkcs11.CKA_VALUE] = z
+
+	return m.add(&mockObject{attrs: attrs}), nil
+}
+
+func (m *mockToken) add(obj *mockObject) pkcs11.ObjectHandle {
+	m.last++
+	m.objects[m.last] = obj
+
+	return m.last
+}
+
+func (o *mockObject) matches(temp []*pkcs11.Attribute) bool {
+	for _, attr := range temp {
+		if !bytes.Equal(o.attrs[attr.Type], attr.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toMap(attrs []*pkcs11.Attribute) map[uint][]byte {
+	m := map[uint][]byte{}
+
+	for _, attr := range attrs {
+		m[attr.Type] = attr.Value
+	}
+
+	return m
+}
+
+// curveOf returns the NIST P curve of the CKA_EC_PARAMS ecParams or nil for other curves.
+func curveOf(ecParams []byte) elliptic.Curve {
+	var oid asn1.ObjectIdentifier
+
+	if _, err := asn1.Unmarshal(ecParams, &oid); err != nil {
+		return nil
+	}
+
+	switch {
+	case oid.Equal(oidNISTP256):
+		return elliptic.P256()
+	case oid.Equal(oidNISTP384):
+		return elliptic.P384()
+	case oid.Equal(oidNISTP521):
+		return elliptic.P521()
+	default:
+		return nil
+	}
+}
+
+func mockECDHMechanism(peerPubKey []byte) *pkcs11.Mechanism {
+	return pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, peerPubKey)
+}
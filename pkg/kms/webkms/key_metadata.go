@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+type listKeysResp struct {
+	Keys []*kms.KeyMetadata `json:"keys"`
+}
+
+// List remotely fetches the metadata of the keys of the keystore matching filter. A nil filter matches all keys.
+// Returns:
+//  - list of key metadata
+//  - error if failure
+func (r *RemoteKMS) List(filter *kms.KeyFilter) ([]*kms.KeyMetadata, error) {
+	destination := r.keystoreURL + "/keys"
+
+	if query := filterQuery(filter); query != "" {
+		destination += "?" + query
+	}
+
+	respBody, err := r.doKeyRequest(http.MethodGet, destination, nil, "List")
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp := &listKeysResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal response for List failed [%s, %w]", destination, err)
+	}
+
+	return httpResp.Keys, nil
+}
+
+// Delete remotely removes the key referenced by keyID from the keystore.
+// Returns:
+//  - error if the key is not found or failed to be deleted
+func (r *RemoteKMS) Delete(keyID string) error {
+	_, err := r.doKeyRequest(http.MethodDelete, r.buildKIDURL(keyID), nil, "Delete")
+
+	return err
+}
+
+// SetMetadata remotely sets the label, purpose, owning DID and usage constraint of the key referenced by keyID.
+// Returns:
+//  - error if the key is not found or failed to store its metadata
+func (r *RemoteKMS) SetMetadata(keyID string, metadata *kms.KeyMetadata) error {
+	destination := r.buildKIDURL(keyID) + "/metadata"
+
+	mReq, err := r.marshalFunc(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SetMetadata request [%s, %w]", destination, err)
+	}
+
+	_, err = r.doKeyRequest(http.MethodPost, destination, mReq, "SetMetadata")
+
+	return err
+}
+
+// GetMetadata remotely fetches the metadata of the key referenced by keyID.
+// Returns:
+//  - key metadata
+//  - error if the key is not found
+func (r *RemoteKMS) GetMetadata(keyID string) (*kms.KeyMetadata, error) {
+	destination := r.buildKIDURL(keyID) + "/metadata"
+
+	respBody, err := r.doKeyRequest(http.MethodGet, destination, nil, "GetMetadata")
+	if err != nil {
+		return nil, err
+	}
+
+	md := &kms.KeyMetadata{}
+
+	err = r.unmarshalFunc(respBody, md)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal response for GetMetadata failed [%s, %w]", destination, err)
+	}
+
+	return md, nil
+}
+
+// doKeyRequest sends a key request to the key server and returns the response body if the request succeeded.
+func (r *RemoteKMS) doKeyRequest(method, destination string, mReq []byte, action string) ([]byte, error) {
+	resp, err := r.doHTTPRequest(method, destination, mReq)
+	if err != nil {
+		return nil, fmt.Errorf("posting %s %s request failed [%s, %w]", method, action, destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, logger, action)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response for %s failed [%s, %w]", action, destination, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%s failed [%s, status %d]: %s", action, destination, resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
+
+func filterQuery(filter *kms.KeyFilter) string {
+	if filter == nil {
+		return ""
+	}
+
+	q := url.Values{}
+
+	setParam := func(name, value string) {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+
+	setParam("keyType", string(filter.KeyType))
+	setParam("label", filter.Label)
+	setParam("purpose", filter.Purpose)
+	setParam("did", filter.DID)
+
+	if filter.CreatedBefore != nil {
+		q.Set("createdBefore", filter.CreatedBefore.UTC().Format(time.RFC3339Nano))
+	}
+
+	return q.Encode()
+}
@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webkms
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+func TestRemoteKMS_KeyMetadata(t *testing.T) {
+	keysPath := "/kms/keystores/" + defaultKeyStoreID + "/keys"
+	keyPath := keysPath + "/" + defaultKID
+	metadata := &kms.KeyMetadata{}
+
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == keysPath:
+			if r.URL.Query().Get("label") != "" && r.URL.Query().Get("label") != metadata.Label {
+				_, _ = fmt.Fprint(w, `{"keys":[]}`)
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(&listKeysResp{Keys: []*kms.KeyMetadata{metadata}})
+		case r.Method == http.MethodDelete && r.URL.Path == keyPath:
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == keyPath+"/metadata":
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, metadata))
+		case r.Method == http.MethodGet && r.URL.Path == keyPath+"/metadata":
+			_ = json.NewEncoder(w).Encode(metadata)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, "key not found")
+		}
+	})
+
+	server := httptest.NewServer(hf)
+	defer server.Close()
+
+	client := server.Client()
+	defaultKeystoreURL := fmt.Sprintf("%s/%s", strings.ReplaceAll(KeystoreEndpoint,
+		"{serverEndpoint}", server.URL), defaultKeyStoreID)
+
+	remoteKMS := New(defaultKeystoreURL, client)
+
+	t.Run("set and get metadata", func(t *testing.T) {
+		err := remoteKMS.SetMetadata(defaultKID, &kms.KeyMetadata{
+			KeyID: defaultKID,
+			Label: "signing",
+			Usage: kms.KeyUsageSign,
+		})
+		require.NoError(t, err)
+
+		md, err := remoteKMS.GetMetadata(defaultKID)
+		require.NoError(t, err)
+		require.Equal(t, defaultKID, md.KeyID)
+		require.Equal(t, "signing", md.Label)
+		require.Equal(t, kms.KeyUsageSign, md.Usage)
+	})
+
+	t.Run("list keys", func(t *testing.T) {
+		keys, err := remoteKMS.List(nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, defaultKID, keys[0].KeyID)
+
+		createdBefore := time.Now()
+
+		keys, err = remoteKMS.List(&kms.KeyFilter{Label: "other", CreatedBefore: &createdBefore})
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("delete key", func(t *testing.T) {
+		err := remoteKMS.Delete(defaultKID)
+		require.NoError(t, err)
+
+		err = remoteKMS.Delete("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Delete failed")
+		require.Contains(t, err.Error(), "status 404")
+		require.Contains(t, err.Error(), "key not found")
+	})
+
+	t.Run("unknown key metadata", func(t *testing.T) {
+		_, err := remoteKMS.GetMetadata("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "GetMetadata failed")
+
+		err = remoteKMS.SetMetadata("unknown", &kms.KeyMetadata{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SetMetadata failed")
+	})
+
+	t.Run("marshal and unmarshal failures", func(t *testing.T) {
+		failingKMS := New(defaultKeystoreURL, client)
+		failingKMS.marshalFunc = failingMarshal
+		failingKMS.unmarshalFunc = failingUnmarshal
+
+		err := failingKMS.SetMetadata(defaultKID, &kms.KeyMetadata{})
+		require.Contains(t, err.Error(), "failed to marshal SetMetadata request")
+
+		_, err = failingKMS.GetMetadata(defaultKID)
+		require.Contains(t, err.Error(), "unmarshal response for GetMetadata failed")
+
+		_, err = failingKMS.List(nil)
+		require.Contains(t, err.Error(), "unmarshal response for List failed")
+	})
+
+	t.Run("http request failure", func(t *testing.T) {
+		badKMS := New("``#$%", client)
+
+		_, err := badKMS.List(nil)
+		require.Contains(t, err.Error(), "posting GET List request failed")
+
+		err = badKMS.Delete(defaultKID)
+		require.Contains(t, err.Error(), "posting DELETE Delete request failed")
+	})
+}
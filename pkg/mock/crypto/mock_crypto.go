@@ -50,6 +50,13 @@ type Crypto struct {
 	DeriveProofKey    []byte
 	DeriveProofFn     DeriveProofFunc
 	DeriveProofError  error
+	DeriveSecretValue []byte
+	DeriveSecretErr   error
+	SealEncValue      []byte
+	SealValue         []byte
+	SealErr           error
+	OpenValue         []byte
+	OpenErr           error
 }
 
 // Encrypt returns mocked values and a mocked error.
@@ -133,3 +140,20 @@ func (c *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, reve
 
 	return c.DeriveProofValue, c.DeriveProofError
 }
+
+// DeriveSharedSecret returns a mocked derived secret value and a mocked error.
+func (c *Crypto) DeriveSharedSecret(theirPubKey *cryptoapi.PublicKey, kh interface{},
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	return c.DeriveSecretValue, c.DeriveSecretErr
+}
+
+// Seal returns mocked encapsulated key and ciphertext values and a mocked error.
+func (c *Crypto) Seal(plaintext, aad []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.KeyAgreementOpts) ([]byte, []byte, error) {
+	return c.SealEncValue, c.SealValue, c.SealErr
+}
+
+// Open returns a mocked plaintext value and a mocked error.
+func (c *Crypto) Open(enc, ciphertext, aad []byte, kh interface{}, opts ...cryptoapi.KeyAgreementOpts) ([]byte, error) {
+	return c.OpenValue, c.OpenErr
+}
@@ -16,9 +16,12 @@ type MockOobService struct {
 	ActionContinueHandle        func(string, outofband.Options) error
 	ActionStopHandle            func(string, error) error
 	ActionsHandle               func() ([]outofband.Action, error)
+	GetInvitationRecordHandle   func(string) (*outofband.InvitationRecord, error)
+	InvitationRecordsHandle     func() ([]*outofband.InvitationRecord, error)
 	RegisterActionEventHandle   func(chan<- service.DIDCommAction) error
 	RegisterMsgEventHandle      func(chan<- service.StateMsg) error
-	SaveInvitationHandle        func(*outofband.Invitation) error
+	RevokeInvitationHandle      func(string) error
+	SaveInvitationHandle        func(*outofband.Invitation, ...outofband.InvitationOpt) error
 	UnregisterActionEventHandle func(chan<- service.DIDCommAction) error
 	UnregisterMsgEventHandle    func(chan<- service.StateMsg) error
 }
@@ -59,6 +62,24 @@ func (m *MockOobService) Actions() ([]outofband.Action, error) {
 	return []outofband.Action{}, nil
 }
 
+// GetInvitationRecord mock implementation.
+func (m *MockOobService) GetInvitationRecord(arg0 string) (*outofband.InvitationRecord, error) {
+	if m.GetInvitationRecordHandle != nil {
+		return m.GetInvitationRecordHandle(arg0)
+	}
+
+	return &outofband.InvitationRecord{}, nil
+}
+
+// InvitationRecords mock implementation.
+func (m *MockOobService) InvitationRecords() ([]*outofband.InvitationRecord, error) {
+	if m.InvitationRecordsHandle != nil {
+		return m.InvitationRecordsHandle()
+	}
+
+	return []*outofband.InvitationRecord{}, nil
+}
+
 // RegisterActionEvent mock implementation.
 func (m *MockOobService) RegisterActionEvent(arg0 chan<- service.DIDCommAction) error {
 	if m.RegisterActionEventHandle != nil {
@@ -77,10 +98,19 @@ func (m *MockOobService) RegisterMsgEvent(arg0 chan<- service.StateMsg) error {
 	return nil
 }
 
+// RevokeInvitation mock implementation.
+func (m *MockOobService) RevokeInvitation(arg0 string) error {
+	if m.RevokeInvitationHandle != nil {
+		return m.RevokeInvitationHandle(arg0)
+	}
+
+	return nil
+}
+
 // SaveInvitation mock implementation.
-func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation) error {
+func (m *MockOobService) SaveInvitation(arg0 *outofband.Invitation, arg1 ...outofband.InvitationOpt) error {
 	if m.SaveInvitationHandle != nil {
-		return m.SaveInvitationHandle(arg0)
+		return m.SaveInvitationHandle(arg0, arg1...)
 	}
 
 	return nil
@@ -39,6 +39,12 @@ type KeyManager struct {
 	ImportPrivateKeyErr      error
 	ImportPrivateKeyID       string
 	ImportPrivateKeyValue    *keyset.Handle
+	ListValue                []*kmsservice.KeyMetadata
+	ListErr                  error
+	DeleteErr                error
+	SetMetadataErr           error
+	GetMetadataValue         *kmsservice.KeyMetadata
+	GetMetadataErr           error
 }
 
 // Create a new mock ey/keyset/key handle for the type kt.
@@ -105,6 +111,34 @@ func (k *KeyManager) ImportPrivateKey(privKey interface{}, keyType kmsservice.Ke
 	return k.ImportPrivateKeyID, k.ImportPrivateKeyValue, nil
 }
 
+// List returns mocked key metadata.
+func (k *KeyManager) List(filter *kmsservice.KeyFilter) ([]*kmsservice.KeyMetadata, error) {
+	if k.ListErr != nil {
+		return nil, k.ListErr
+	}
+
+	return k.ListValue, nil
+}
+
+// Delete a mock key.
+func (k *KeyManager) Delete(keyID string) error {
+	return k.DeleteErr
+}
+
+// SetMetadata sets mock key metadata.
+func (k *KeyManager) SetMetadata(keyID string, metadata *kmsservice.KeyMetadata) error {
+	return k.SetMetadataErr
+}
+
+// GetMetadata returns mocked key metadata.
+func (k *KeyManager) GetMetadata(keyID string) (*kmsservice.KeyMetadata, error) {
+	if k.GetMetadataErr != nil {
+		return nil, k.GetMetadataErr
+	}
+
+	return k.GetMetadataValue, nil
+}
+
 func createMockKeyHandle(ks *tinkpb.Keyset) (*keyset.Handle, error) {
 	primaryKey := ks.Key[0]
 
@@ -142,9 +142,21 @@ func (s *MockStore) Get(k string) ([]byte, error) {
 	return entry.Value, s.ErrGet
 }
 
-// GetTags is not implemented.
+// GetTags fetches all tags associated with the given key.
 func (s *MockStore) GetTags(key string) ([]storage.Tag, error) {
-	panic("implement me")
+	if s.ErrGet != nil {
+		return nil, s.ErrGet
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	entry, ok := s.Store[key]
+	if !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return entry.Tags, nil
 }
 
 // GetBulk is not implemented.
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argon2id
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	cipherutil "github.com/hyperledger/aries-framework-go/pkg/secretlock/local/internal/cipher"
+)
+
+// package argon2id provides an Argon2id implementation of secretlock as a masterlock.
+// Argon2id is the memory-hard password hashing function specified in IETF RFC 9106 found at:
+// https://www.rfc-editor.org/rfc/rfc9106.html. Unlike the hkdf and pbkdf2 masterlocks, a random salt is generated for
+// each encryption and the Argon2id parameters are stored alongside the ciphertext in the PHC string format:
+//		$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<nonce+ciphertext>
+// so that a master key remains readable after the lock parameters are changed.
+
+const (
+	masterKeySize = 32
+	saltSize      = 16
+	algName       = "argon2id"
+	// the upper bounds of the parameters read from a ciphertext, they prevent a crafted ciphertext from exhausting
+	// the memory or the CPU. maxMemory (in KiB) bounds the memory allocated by each decryption to 256 MiB, four times
+	// the memory of the default parameters.
+	maxMemory  = 256 * 1024
+	maxTime    = 16
+	maxThreads = 16
+)
+
+// Params are the Argon2id parameters used to derive the key encrypting the master key.
+type Params struct {
+	// Time is the number of passes over the memory (at most 16).
+	Time uint32
+	// Memory is the size of the memory in KiB (at most 256 MiB).
+	Memory uint32
+	// Threads is the degree of parallelism (at most 16).
+	Threads uint8
+}
+
+// DefaultParams returns the second recommended option of RFC 9106 (section 4): 3 passes over 64 MiB of memory
+// with 4 threads.
+func DefaultParams() *Params {
+	return &Params{
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+}
+
+func (p *Params) validate() error {
+	if p.Time == 0 || p.Threads == 0 || p.Memory < 8*uint32(p.Threads) {
+		return errors.New("invalid argon2id parameters")
+	}
+
+	if p.Memory > maxMemory || p.Time > maxTime || p.Threads > maxThreads {
+		return fmt.Errorf("argon2id parameters exceed the maximum cost m=%d,t=%d,p=%d", maxMemory, maxTime, maxThreads)
+	}
+
+	return nil
+}
+
+type masterLockArgon2id struct {
+	passphrase []byte
+	params     *Params
+}
+
+// NewMasterLock is responsible for encrypting/decrypting a master key with a key derived from `passphrase` using
+// Argon2id with `params`. DefaultParams() are used if `params` is nil. The parameters only apply to Encrypt(),
+// Decrypt() uses the parameters found in the ciphertext.
+// The size of a master key passed to Encrypt() must be 32 bytes since the key will be used for AEAD operations.
+// This implementation must not be used directly in Aries framework. It should be passed in
+// as the second argument to local secret lock service constructor:
+// `local.NewService(masterKeyReader io.Reader, secLock secretlock.Service)`.
+func NewMasterLock(passphrase string, params *Params) (secretlock.Service, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is empty")
+	}
+
+	if params == nil {
+		params = DefaultParams()
+	}
+
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	return &masterLockArgon2id{
+		passphrase: []byte(passphrase),
+		params:     params,
+	}, nil
+}
+
+// Encrypt a master key in req
+//  (keyURI is used for remote locks, it is ignored by this implementation)
+func (m *masterLockArgon2id) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	if len(req.Plaintext) != masterKeySize {
+		return nil, fmt.Errorf("invalid key size")
+	}
+
+	salt := random.GetRandomBytes(saltSize)
+
+	aead, err := cipherutil.CreateAESCipher(m.deriveKey(salt, m.params))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	ct := aead.Seal(nil, nonce, []byte(req.Plaintext), []byte(req.AdditionalAuthenticatedData))
+	ct = append(nonce, ct...)
+
+	return &secretlock.EncryptResponse{
+		Ciphertext: fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", algName, argon2.Version,
+			m.params.Memory, m.params.Time, m.params.Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(ct)),
+	}, nil
+}
+
+// Decrypt a master key in req
+// (keyURI is used for remote locks, it is ignored by this implementation).
+func (m *masterLockArgon2id) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	params, salt, ct, err := parseCiphertext(req.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipherutil.CreateAESCipher(m.deriveKey(salt, params))
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+
+	// ensure ciphertext contains more than nonce+ciphertext (result from Encrypt())
+	if len(ct) <= nonceSize {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	pt, err := aead.Open(nil, ct[:nonceSize], ct[nonceSize:], []byte(req.AdditionalAuthenticatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
+}
+
+func (m *masterLockArgon2id) deriveKey(salt []byte, params *Params) []byte {
+	return argon2.IDKey(m.passphrase, salt, params.Time, params.Memory, params.Threads, masterKeySize)
+}
+
+func parseCiphertext(ciphertext string) (*Params, []byte, []byte, error) {
+	parts := strings.Split(ciphertext, "$")
+
+	const numParts = 6
+
+	if len(parts) != numParts || parts[0] != "" || parts[1] != algName {
+		return nil, nil, nil, errors.New("invalid argon2id ciphertext format")
+	}
+
+	var version int
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return nil, nil, nil, errors.New("unsupported argon2id version")
+	}
+
+	params := &Params{}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	if err = params.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	ct, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id ciphertext: %w", err)
+	}
+
+	return params, salt, ct, nil
+}
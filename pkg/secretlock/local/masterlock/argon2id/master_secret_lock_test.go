@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argon2id
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/internal/locktest"
+)
+
+// testParams are weak parameters speeding up the tests.
+var testParams = &Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestMasterLock(t *testing.T) {
+	ciphertext := locktest.VerifyMasterLock(t,
+		func(passphrase string) (secretlock.Service, error) {
+			return NewMasterLock(passphrase, testParams)
+		},
+		func(passphrase string) (secretlock.Service, error) {
+			return NewMasterLock(passphrase, &Params{Time: 2, Memory: 128, Threads: 2})
+		})
+	require.True(t, strings.HasPrefix(ciphertext, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	// try creating a lock with invalid parameters
+	mkLock, err := NewMasterLock("somepassphrase", &Params{Time: 1, Memory: 8, Threads: 2})
+	require.EqualError(t, err, "invalid argon2id parameters")
+	require.Empty(t, mkLock)
+
+	// try creating a lock with parameters exceeding the maximum cost
+	mkLock, err = NewMasterLock("somepassphrase", &Params{Time: 1, Memory: 512 * 1024, Threads: 1})
+	require.EqualError(t, err, "argon2id parameters exceed the maximum cost m=262144,t=16,p=16")
+	require.Empty(t, mkLock)
+
+	// default parameters
+	mkLock, err = NewMasterLock("somepassphrase", nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultParams(), mkLock.(*masterLockArgon2id).params)
+}
+
+func TestMasterLock_Decrypt_InvalidCiphertext(t *testing.T) {
+	mkLock, err := NewMasterLock("somepassphrase", testParams)
+	require.NoError(t, err)
+
+	locktest.VerifyDecryptErrors(t, mkLock, algName, "$argon2id$v=19$m=64,t=1,p=1",
+		locktest.DecryptErrorCase{
+			Name:       "other algorithm",
+			Ciphertext: "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$Y3Q",
+			Err:        "invalid argon2id ciphertext format",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "unsupported version",
+			Ciphertext: "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$Y3Q",
+			Err:        "unsupported argon2id version",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "invalid parameters format",
+			Ciphertext: "$argon2id$v=19$t=1$c2FsdA$Y3Q",
+			Err:        "invalid argon2id parameters: input does not match format",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "invalid parameters",
+			Ciphertext: "$argon2id$v=19$m=64,t=0,p=1$c2FsdA$Y3Q",
+			Err:        "invalid argon2id parameters",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "hostile memory",
+			Ciphertext: "$argon2id$v=19$m=2097152,t=1,p=1$c2FsdA$Y3Q",
+			Err:        "argon2id parameters exceed the maximum cost m=262144,t=16,p=16",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "hostile time",
+			Ciphertext: "$argon2id$v=19$m=64,t=4294967295,p=1$c2FsdA$Y3Q",
+			Err:        "argon2id parameters exceed the maximum cost m=262144,t=16,p=16",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "hostile threads",
+			Ciphertext: "$argon2id$v=19$m=8192,t=1,p=255$c2FsdA$Y3Q",
+			Err:        "argon2id parameters exceed the maximum cost m=262144,t=16,p=16",
+		},
+	)
+}
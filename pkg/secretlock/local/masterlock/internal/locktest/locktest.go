@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package locktest provides the test cases shared by the masterlocks storing a random salt and their KDF parameters
+// alongside the ciphertext in the PHC string format. Should only be used for tests.
+package locktest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+const masterKeySize = 32
+
+// LockCreator creates a masterlock from a passphrase.
+type LockCreator func(passphrase string) (secretlock.Service, error)
+
+// DecryptErrorCase is a ciphertext Decrypt() must fail with Err.
+type DecryptErrorCase struct {
+	Name       string
+	Ciphertext string
+	Err        string
+}
+
+// VerifyMasterLock verifies the masterlocks created by newLock encrypt and decrypt master keys, and that the
+// masterlocks created by newLockWithOtherParams decrypt them with the parameters found in the ciphertext.
+// It returns a ciphertext of newLock for the KDF specific checks.
+func VerifyMasterLock(t *testing.T, newLock, newLockWithOtherParams LockCreator) string {
+	t.Helper()
+
+	testKey := random.GetRandomBytes(masterKeySize)
+	goodPassphrase := "somepassphrase"
+
+	mkLock, err := newLock(goodPassphrase)
+	require.NoError(t, err)
+
+	encryptedMk, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+	require.NoError(t, err)
+
+	decryptedMk, err := mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk.Plaintext))
+
+	// a random salt is used for each encryption
+	encryptedMk2, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+	require.NoError(t, err)
+	require.NotEqual(t, encryptedMk.Ciphertext, encryptedMk2.Ciphertext)
+
+	// try encrypting a key with a size different than 32 bytes
+	badEncryptedMk, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: "BadKey"})
+	require.EqualError(t, err, "invalid key size")
+	require.Empty(t, badEncryptedMk)
+
+	// a lock with the same passphrase and different parameters decrypts with the parameters of the ciphertext
+	mkLock2, err := newLockWithOtherParams(goodPassphrase)
+	require.NoError(t, err)
+
+	decryptedMk2, err := mkLock2.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk2.Plaintext))
+
+	// try with a bad passphrase
+	mkLock2, err = newLock("badPassphrase")
+	require.NoError(t, err)
+
+	decryptedMk2, err = mkLock2.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.Error(t, err)
+	require.Empty(t, decryptedMk2)
+
+	// try with a different additional authenticated data
+	decryptedMk2, err = mkLock.Decrypt("", &secretlock.DecryptRequest{
+		Ciphertext:                  encryptedMk.Ciphertext,
+		AdditionalAuthenticatedData: "aad",
+	})
+	require.Error(t, err)
+	require.Empty(t, decryptedMk2)
+
+	// try creating a lock with an empty passphrase
+	mkLock2, err = newLock("")
+	require.EqualError(t, err, "passphrase is empty")
+	require.Empty(t, mkLock2)
+
+	return encryptedMk.Ciphertext
+}
+
+// VerifyDecryptErrors verifies mkLock fails to decrypt malformed ciphertexts of algorithm alg, whose PHC string
+// starts with header (eg. "$scrypt$ln=4,r=8,p=1"), as well as the KDF specific cases.
+func VerifyDecryptErrors(t *testing.T, mkLock secretlock.Service, alg, header string, cases ...DecryptErrorCase) {
+	t.Helper()
+
+	tests := append([]DecryptErrorCase{
+		{
+			Name:       "not a PHC string",
+			Ciphertext: "bad{}base64URLstring[]",
+			Err:        fmt.Sprintf("invalid %s ciphertext format", alg),
+		},
+		{
+			Name:       "invalid salt",
+			Ciphertext: header + "$[]$Y3Q",
+			Err:        fmt.Sprintf("invalid %s salt: illegal base64 data at input byte 0", alg),
+		},
+		{
+			Name:       "invalid ciphertext",
+			Ciphertext: header + "$c2FsdA$[]",
+			Err:        fmt.Sprintf("invalid %s ciphertext: illegal base64 data at input byte 0", alg),
+		},
+		{
+			Name:       "ciphertext too short",
+			Ciphertext: header + "$c2FsdA$Y3Q",
+			Err:        "invalid request",
+		},
+	}, cases...)
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.Name, func(t *testing.T) {
+			decryptedMk, err := mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: tc.Ciphertext})
+			require.EqualError(t, err, tc.Err)
+			require.Empty(t, decryptedMk)
+		})
+	}
+}
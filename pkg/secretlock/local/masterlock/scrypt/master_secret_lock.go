@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	cipherutil "github.com/hyperledger/aries-framework-go/pkg/secretlock/local/internal/cipher"
+)
+
+// package scrypt provides an scrypt implementation of secretlock as a masterlock.
+// scrypt is the memory-hard password-based key derivation function specified in IETF RFC 7914 found at:
+// https://tools.ietf.org/html/rfc7914. Unlike the hkdf and pbkdf2 masterlocks, a random salt is generated for each
+// encryption and the scrypt parameters are stored alongside the ciphertext in the PHC string format:
+//		$scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<nonce+ciphertext>
+// so that a master key remains readable after the lock parameters are changed.
+
+const (
+	masterKeySize = 32
+	saltSize      = 16
+	algName       = "scrypt"
+	// the upper bounds of the parameters read from a ciphertext, they prevent a crafted ciphertext from exhausting
+	// the memory or the CPU. scrypt uses 128*r*N bytes of memory, maxMemory bounds it to 1 GiB.
+	maxLogN   = 20
+	maxR      = 32
+	maxP      = 16
+	maxMemory = 1 << 30
+)
+
+// Params are the scrypt parameters used to derive the key encrypting the master key.
+type Params struct {
+	// LogN is the base 2 logarithm of the CPU/memory cost parameter N (at most 20).
+	LogN uint8
+	// R is the block size parameter (at most 32, 128*r*N must not exceed 1 GiB).
+	R int
+	// P is the parallelization parameter (at most 16).
+	P int
+}
+
+// DefaultParams returns the parameters recommended by golang.org/x/crypto/scrypt for interactive logins:
+// N=32768, r=8 and p=1.
+func DefaultParams() *Params {
+	return &Params{
+		LogN: 15,
+		R:    8,
+		P:    1,
+	}
+}
+
+func (p *Params) validate() error {
+	if p.LogN == 0 || p.R <= 0 || p.P <= 0 {
+		return errors.New("invalid scrypt parameters")
+	}
+
+	const blockSize = 128
+
+	if p.LogN > maxLogN || p.R > maxR || p.P > maxP || int64(blockSize*p.R)<<p.LogN > maxMemory {
+		return fmt.Errorf("scrypt parameters exceed the maximum cost ln=%d,r=%d,p=%d and %d bytes of memory",
+			maxLogN, maxR, maxP, maxMemory)
+	}
+
+	return nil
+}
+
+type masterLockScrypt struct {
+	passphrase []byte
+	params     *Params
+}
+
+// NewMasterLock is responsible for encrypting/decrypting a master key with a key derived from `passphrase` using
+// scrypt with `params`. DefaultParams() are used if `params` is nil. The parameters only apply to Encrypt(),
+// Decrypt() uses the parameters found in the ciphertext.
+// The size of a master key passed to Encrypt() must be 32 bytes since the key will be used for AEAD operations.
+// This implementation must not be used directly in Aries framework. It should be passed in
+// as the second argument to local secret lock service constructor:
+// `local.NewService(masterKeyReader io.Reader, secLock secretlock.Service)`.
+func NewMasterLock(passphrase string, params *Params) (secretlock.Service, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is empty")
+	}
+
+	if params == nil {
+		params = DefaultParams()
+	}
+
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	return &masterLockScrypt{
+		passphrase: []byte(passphrase),
+		params:     params,
+	}, nil
+}
+
+// Encrypt a master key in req
+//  (keyURI is used for remote locks, it is ignored by this implementation)
+func (m *masterLockScrypt) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	if len(req.Plaintext) != masterKeySize {
+		return nil, fmt.Errorf("invalid key size")
+	}
+
+	salt := random.GetRandomBytes(saltSize)
+
+	key, err := m.deriveKey(salt, m.params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipherutil.CreateAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	ct := aead.Seal(nil, nonce, []byte(req.Plaintext), []byte(req.AdditionalAuthenticatedData))
+	ct = append(nonce, ct...)
+
+	return &secretlock.EncryptResponse{
+		Ciphertext: fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s", algName, m.params.LogN, m.params.R, m.params.P,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(ct)),
+	}, nil
+}
+
+// Decrypt a master key in req
+// (keyURI is used for remote locks, it is ignored by this implementation).
+func (m *masterLockScrypt) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	params, salt, ct, err := parseCiphertext(req.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := m.deriveKey(salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipherutil.CreateAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+
+	// ensure ciphertext contains more than nonce+ciphertext (result from Encrypt())
+	if len(ct) <= nonceSize {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	pt, err := aead.Open(nil, ct[:nonceSize], ct[nonceSize:], []byte(req.AdditionalAuthenticatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
+}
+
+func (m *masterLockScrypt) deriveKey(salt []byte, params *Params) ([]byte, error) {
+	key, err := scrypt.Key(m.passphrase, salt, 1<<params.LogN, params.R, params.P, masterKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive scrypt key: %w", err)
+	}
+
+	return key, nil
+}
+
+func parseCiphertext(ciphertext string) (*Params, []byte, []byte, error) {
+	parts := strings.Split(ciphertext, "$")
+
+	const numParts = 5
+
+	if len(parts) != numParts || parts[0] != "" || parts[1] != algName {
+		return nil, nil, nil, errors.New("invalid scrypt ciphertext format")
+	}
+
+	params := &Params{}
+
+	_, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.LogN, &params.R, &params.P)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid scrypt parameters: %w", err)
+	}
+
+	if err = params.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid scrypt salt: %w", err)
+	}
+
+	ct, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid scrypt ciphertext: %w", err)
+	}
+
+	return params, salt, ct, nil
+}
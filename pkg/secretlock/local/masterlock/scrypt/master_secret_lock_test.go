@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrypt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/internal/locktest"
+)
+
+const maxCostErr = "scrypt parameters exceed the maximum cost ln=20,r=32,p=16 and 1073741824 bytes of memory"
+
+// testParams are weak parameters speeding up the tests.
+var testParams = &Params{LogN: 4, R: 8, P: 1}
+
+func TestMasterLock(t *testing.T) {
+	ciphertext := locktest.VerifyMasterLock(t,
+		func(passphrase string) (secretlock.Service, error) {
+			return NewMasterLock(passphrase, testParams)
+		},
+		func(passphrase string) (secretlock.Service, error) {
+			return NewMasterLock(passphrase, &Params{LogN: 5, R: 4, P: 2})
+		})
+	require.True(t, strings.HasPrefix(ciphertext, "$scrypt$ln=4,r=8,p=1$"))
+
+	// try creating a lock with invalid parameters
+	mkLock, err := NewMasterLock("somepassphrase", &Params{LogN: 0, R: 8, P: 1})
+	require.EqualError(t, err, "invalid scrypt parameters")
+	require.Empty(t, mkLock)
+
+	// try creating a lock with parameters exceeding the maximum cost
+	mkLock, err = NewMasterLock("somepassphrase", &Params{LogN: 31, R: 8, P: 1})
+	require.EqualError(t, err, maxCostErr)
+	require.Empty(t, mkLock)
+
+	// default parameters
+	mkLock, err = NewMasterLock("somepassphrase", nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultParams(), mkLock.(*masterLockScrypt).params)
+}
+
+func TestMasterLock_Decrypt_InvalidCiphertext(t *testing.T) {
+	mkLock, err := NewMasterLock("somepassphrase", testParams)
+	require.NoError(t, err)
+
+	locktest.VerifyDecryptErrors(t, mkLock, algName, "$scrypt$ln=4,r=8,p=1",
+		locktest.DecryptErrorCase{
+			Name:       "other algorithm",
+			Ciphertext: "$argon2id$ln=4,r=8,p=1$c2FsdA$Y3Q",
+			Err:        "invalid scrypt ciphertext format",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "invalid parameters format",
+			Ciphertext: "$scrypt$r=8$c2FsdA$Y3Q",
+			Err:        "invalid scrypt parameters: input does not match format",
+		},
+		locktest.DecryptErrorCase{
+			Name:       "invalid parameters",
+			Ciphertext: "$scrypt$ln=0,r=8,p=1$c2FsdA$Y3Q",
+			Err:        "invalid scrypt parameters",
+		},
+		locktest.DecryptErrorCase{Name: "hostile N", Ciphertext: "$scrypt$ln=30,r=1,p=1$c2FsdA$Y3Q", Err: maxCostErr},
+		locktest.DecryptErrorCase{Name: "hostile r", Ciphertext: "$scrypt$ln=4,r=1073741823,p=1$c2FsdA$Y3Q", Err: maxCostErr},
+		locktest.DecryptErrorCase{Name: "hostile p", Ciphertext: "$scrypt$ln=4,r=8,p=1073741823$c2FsdA$Y3Q", Err: maxCostErr},
+		locktest.DecryptErrorCase{Name: "hostile memory", Ciphertext: "$scrypt$ln=20,r=16,p=1$c2FsdA$Y3Q", Err: maxCostErr},
+	)
+}
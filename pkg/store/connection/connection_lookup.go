@@ -14,6 +14,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
@@ -28,12 +30,28 @@ const (
 	invKeyPrefix        = "inv"
 	eventDataKeyPrefix  = "connevent"
 	didConnMapKeyPrefix = "didconn"
+	metadataKeyPrefix   = "connmd"
+	metadataKeyTag      = "connmdkey"
+	stateTag            = "connqstate"
+	myDIDTag            = "connqmydid"
+	theirDIDTag         = "connqtheirdid"
+	invitationIDTag     = "connqinv"
+	parentThreadIDTag   = "connqpthid"
+	createdAtTag        = "connqcreated"
+	updatedAtTag        = "connqupdated"
+	lastMessageAtTag    = "connqlastmsg"
+	queryTagsKey        = "connquerytags"
 	keySeparator        = "_"
 	stateIDEmptyErr     = "stateID can't be empty"
 )
 
 var logger = log.New("aries-framework/store/connection")
 
+// queryTagNames are the tags of the connection records used by Lookup.QueryConnections.
+var queryTagNames = []string{ // nolint: gochecknoglobals
+	stateTag, myDIDTag, theirDIDTag, invitationIDTag, parentThreadIDTag, createdAtTag, updatedAtTag, lastMessageAtTag,
+}
+
 // KeyPrefix is prefix builder for storage keys.
 type KeyPrefix func(...string) string
 
@@ -59,6 +77,39 @@ type Record struct {
 	Implicit        bool
 	Namespace       string
 	MediaTypes      []string
+	// Metadata holds the labels attached to the connection by the user (e.g customer ID, trust level).
+	Metadata map[string]string `json:",omitempty"`
+	// CreatedAt is the time the connection record was saved for the first time.
+	CreatedAt time.Time
+	// UpdatedAt is the time the connection record was saved for the last time.
+	UpdatedAt time.Time
+	// LastMessageAt is the time the last message was exchanged over the connection.
+	LastMessageAt time.Time
+}
+
+// DIDCommVersion represents the version of the DIDComm messages used by the connection.
+type DIDCommVersion string
+
+const (
+	// DIDCommV1 is the DIDComm V1 (Aries RFC) messages version.
+	DIDCommV1 DIDCommVersion = "v1"
+	// DIDCommV2 is the DIDComm V2 (DIF DIDComm spec) messages version.
+	DIDCommV2 DIDCommVersion = "v2"
+
+	mediaTypeV2EncryptedEnvelope = "application/didcomm-encrypted+json"
+	mediaTypeV2Plaintext         = "application/didcomm-plain+json"
+)
+
+// DIDCommVersion returns the DIDComm version of the connection based on the accepted media types.
+// DIDComm V2 envelopes carrying a V1 payload (Aries RFC 0587) are treated as DIDComm V1.
+func (r *Record) DIDCommVersion() DIDCommVersion {
+	for _, mediaType := range r.MediaTypes {
+		if mediaType == mediaTypeV2EncryptedEnvelope || mediaType == mediaTypeV2Plaintext {
+			return DIDCommV2
+		}
+	}
+
+	return DIDCommV1
 }
 
 // NewLookup returns new connection lookup instance.
@@ -69,7 +120,9 @@ func NewLookup(p provider) (*Lookup, error) {
 		return nil, fmt.Errorf("failed to open permanent store to create new connection recorder: %w", err)
 	}
 
-	err = p.StorageProvider().SetStoreConfig(Namespace, storage.StoreConfiguration{TagNames: []string{connIDKeyPrefix}})
+	err = p.StorageProvider().SetStoreConfig(Namespace,
+		storage.StoreConfiguration{TagNames: append([]string{connIDKeyPrefix, metadataKeyPrefix, metadataKeyTag},
+			queryTagNames...)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store config in permanent store: %w", err)
 	}
@@ -80,7 +133,7 @@ func NewLookup(p provider) (*Lookup, error) {
 	}
 
 	err = p.ProtocolStateStorageProvider().SetStoreConfig(Namespace,
-		storage.StoreConfiguration{TagNames: []string{connIDKeyPrefix, connStateKeyPrefix}})
+		storage.StoreConfiguration{TagNames: append([]string{connIDKeyPrefix, connStateKeyPrefix}, queryTagNames...)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store config in protocol state store: %w", err)
 	}
@@ -92,6 +145,7 @@ func NewLookup(p provider) (*Lookup, error) {
 type Lookup struct {
 	protocolStateStore storage.Store
 	store              storage.Store
+	tagOnce            sync.Once
 }
 
 // GetConnectionRecord return connection record based on the connection ID.
@@ -198,6 +252,23 @@ func (c *Lookup) GetConnectionIDByDIDs(myDID, theirDID string) (string, error) {
 	return string(connectionIDBytes), nil
 }
 
+// GetDIDCommVersionByDIDs returns DIDComm version of the connection between given DIDs,
+// DIDCommV1 is returned if there is no such connection. Protocol clients use it to select the protocol version
+// (e.g issue-credential 2.0 or 3.0) corresponding to the connection.
+func (c *Lookup) GetDIDCommVersionByDIDs(myDID, theirDID string) DIDCommVersion {
+	connID, err := c.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return DIDCommV1
+	}
+
+	record, err := c.GetConnectionRecord(connID)
+	if err != nil {
+		return DIDCommV1
+	}
+
+	return record.DIDCommVersion()
+}
+
 // GetInvitation finds and parses stored invitation to target type.
 // TODO should avoid using target of type `interface{}` [Issue #1030].
 func (c *Lookup) GetInvitation(id string, target interface{}) error {
@@ -320,6 +391,13 @@ func getEventDataKeyPrefix() KeyPrefix {
 	}
 }
 
+// getMetadataKeyPrefix key prefix for saving the connection metadata index entries.
+func getMetadataKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, metadataKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
 // getDIDConnMapKeyPrefix key prefix for saving mapping between DID and ConnectionID.
 func getDIDConnMapKeyPrefix() KeyPrefix {
 	return func(key ...string) string {
@@ -421,3 +421,31 @@ func (p *mockProvider) StorageProvider() storage.Provider {
 
 	return mockstorage.NewMockStoreProvider()
 }
+
+func TestGetDIDCommVersionByDIDs(t *testing.T) {
+	recorder, err := NewRecorder(&protocol.MockProvider{})
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&Record{
+		ThreadID:     threadIDValue,
+		ConnectionID: sampleConnID,
+		State:        StateNameCompleted,
+		MyDID:        "did:example:my",
+		TheirDID:     "did:example:their",
+		MediaTypes:   []string{"application/didcomm-plain+json"},
+	}))
+
+	require.Equal(t, DIDCommV2, recorder.GetDIDCommVersionByDIDs("did:example:my", "did:example:their"))
+	require.Equal(t, DIDCommV1, recorder.GetDIDCommVersionByDIDs("did:example:my", "did:example:other"))
+}
+
+func TestRecord_DIDCommVersion(t *testing.T) {
+	require.Equal(t, DIDCommV1, (&Record{}).DIDCommVersion())
+	require.Equal(t, DIDCommV1, (&Record{MediaTypes: []string{"application/didcomm-enc-env"}}).DIDCommVersion())
+	require.Equal(t, DIDCommV1, (&Record{
+		MediaTypes: []string{"application/didcomm-encrypted+json;cty=application/json;flavor=didcomm-msg"},
+	}).DIDCommVersion())
+	require.Equal(t, DIDCommV2, (&Record{
+		MediaTypes: []string{"application/didcomm-enc-env", "application/didcomm-encrypted+json"},
+	}).DIDCommVersion())
+}
@@ -0,0 +1,458 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Sort fields supported by QueryParams.
+const (
+	// SortByCreatedAt sorts the connection records by the creation time.
+	SortByCreatedAt = "created_at"
+	// SortByUpdatedAt sorts the connection records by the last update time.
+	SortByUpdatedAt = "updated_at"
+	// SortByLastMessageAt sorts the connection records by the time of the last message.
+	SortByLastMessageAt = "last_message_at"
+)
+
+// QueryParams narrows down and orders the connection records returned by Lookup.QueryConnections.
+// Empty fields are ignored.
+type QueryParams struct {
+	State          string
+	MyDID          string
+	TheirDID       string
+	InvitationID   string
+	ParentThreadID string
+	// Metadata entries the connection must have, an empty value matches any value of the key.
+	Metadata map[string]string
+	// SortBy is one of SortByCreatedAt, SortByUpdatedAt or SortByLastMessageAt.
+	SortBy     string
+	Descending bool
+	// Offset is the number of the records (after sorting) to skip.
+	Offset int
+	// Limit is the maximum number of the records to return (no limit if zero).
+	Limit int
+}
+
+// QueryConnections returns the connection records matching the given criteria.
+// The criteria, the sort order and the pagination are resolved through the tags of the connection records
+// (and the metadata index), only the records of the requested page are loaded from the store.
+func (c *Lookup) QueryConnections(params *QueryParams) ([]*Record, error) {
+	if params.Offset < 0 || params.Limit < 0 {
+		return nil, errors.New("offset and limit cannot be negative")
+	}
+
+	c.tagOnce.Do(func() {
+		if err := c.tagRecords(); err != nil {
+			logger.Warnf("failed to tag connection records: %s", err)
+		}
+	})
+
+	sortTag, err := sortTagName(params.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids map[string]struct{}
+
+	if len(params.Metadata) > 0 {
+		ids, err = c.queryByMetadata(params.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			return nil, nil
+		}
+	}
+
+	filters := params.tagFilters()
+
+	expr := getConnectionKeyPrefix()("")
+	if len(filters) > 0 {
+		expr = filters[0].Name + ":" + filters[0].Value
+	}
+
+	candidates, err := c.queryTagged(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*tagged
+
+	for _, candidate := range candidates {
+		if _, ok := ids[candidate.connectionID]; ids != nil && !ok {
+			continue
+		}
+
+		if candidate.match(filters) {
+			result = append(result, candidate)
+		}
+	}
+
+	if sortTag != "" {
+		sort.SliceStable(result, func(i, j int) bool {
+			if params.Descending {
+				return result[j].tags[sortTag] < result[i].tags[sortTag]
+			}
+
+			return result[i].tags[sortTag] < result[j].tags[sortTag]
+		})
+	}
+
+	return c.page(result, params.Offset, params.Limit)
+}
+
+// tagRecords tags the connection records saved before the records were tagged for QueryConnections.
+func (c *Lookup) tagRecords() error {
+	_, err := c.store.Get(queryTagsKey)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	for _, store := range []storage.Store{c.store, c.protocolStateStore} {
+		if err = tagStoreRecords(store); err != nil {
+			return err
+		}
+	}
+
+	return c.store.Put(queryTagsKey, []byte("true"))
+}
+
+func tagStoreRecords(store storage.Store) error {
+	itr, err := store.Query(getConnectionKeyPrefix()(""))
+	if err != nil {
+		return err
+	}
+
+	defer storage.Close(itr, logger)
+
+	var untagged []storage.Operation
+
+	more, err := itr.Next()
+	if err != nil {
+		return err
+	}
+
+	for more {
+		tags, err := itr.Tags()
+		if err != nil {
+			return err
+		}
+
+		if !hasTag(tags, createdAtTag) {
+			op := storage.Operation{Tags: tags}
+
+			if op.Key, err = itr.Key(); err != nil {
+				return err
+			}
+
+			if op.Value, err = itr.Value(); err != nil {
+				return err
+			}
+
+			untagged = append(untagged, op)
+		}
+
+		more, err = itr.Next()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, op := range untagged {
+		var record Record
+		if err = json.Unmarshal(op.Value, &record); err != nil {
+			return fmt.Errorf("unmarshal connection record: %w", err)
+		}
+
+		if err = store.Put(op.Key, op.Value, append(op.Tags, queryTags(&record)...)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasTag(tags []storage.Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// page loads the connection records of the requested page.
+func (c *Lookup) page(candidates []*tagged, offset, limit int) ([]*Record, error) {
+	if offset >= len(candidates) {
+		return nil, nil
+	}
+
+	candidates = candidates[offset:]
+
+	if limit > 0 && limit < len(candidates) {
+		candidates = candidates[:limit]
+	}
+
+	records := make([]*Record, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		record, err := c.GetConnectionRecord(candidate.connectionID)
+		if err != nil {
+			return nil, fmt.Errorf("get connection record: %w", err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// tagFilters returns the tags the connection records must have, the values are hashed
+// as the tag values cannot contain ':' characters.
+func (p *QueryParams) tagFilters() []storage.Tag {
+	var filters []storage.Tag
+
+	// the most selective criteria come first, the first one is used to query the store
+	for _, f := range []struct{ name, value string }{
+		{theirDIDTag, p.TheirDID},
+		{myDIDTag, p.MyDID},
+		{invitationIDTag, p.InvitationID},
+		{parentThreadIDTag, p.ParentThreadID},
+		{stateTag, p.State},
+	} {
+		if f.value != "" {
+			filters = append(filters, storage.Tag{Name: f.name, Value: tagHash(f.value)})
+		}
+	}
+
+	return filters
+}
+
+func sortTagName(sortBy string) (string, error) {
+	switch sortBy {
+	case "":
+		return "", nil
+	case SortByCreatedAt:
+		return createdAtTag, nil
+	case SortByUpdatedAt:
+		return updatedAtTag, nil
+	case SortByLastMessageAt:
+		return lastMessageAtTag, nil
+	default:
+		return "", fmt.Errorf("unsupported sort field %q", sortBy)
+	}
+}
+
+// queryTags returns the tags of the connection record used by QueryConnections.
+func queryTags(r *Record) []storage.Tag {
+	tags := []storage.Tag{
+		{Name: createdAtTag, Value: sortableTime(r.CreatedAt)},
+		{Name: updatedAtTag, Value: sortableTime(r.UpdatedAt)},
+		{Name: lastMessageAtTag, Value: sortableTime(r.LastMessageAt)},
+	}
+
+	for _, t := range []struct{ name, value string }{
+		{stateTag, r.State},
+		{myDIDTag, r.MyDID},
+		{theirDIDTag, r.TheirDID},
+		{invitationIDTag, r.InvitationID},
+		{parentThreadIDTag, r.ParentThreadID},
+	} {
+		if t.value != "" {
+			tags = append(tags, storage.Tag{Name: t.name, Value: tagHash(t.value)})
+		}
+	}
+
+	return tags
+}
+
+// sortableTime formats the time so that the lexical order of the values is the chronological one.
+func sortableTime(t time.Time) string {
+	if t.IsZero() || t.Before(time.Unix(0, 0)) {
+		return fmt.Sprintf("%020d", 0)
+	}
+
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
+
+// tagged is a connection record found by its tags.
+type tagged struct {
+	connectionID string
+	tags         map[string]string
+}
+
+func (t *tagged) match(filters []storage.Tag) bool {
+	for _, f := range filters {
+		if t.tags[f.Name] != f.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// queryTagged returns the connection records (their tags only) matching the expression,
+// the records of the permanent store take precedence over the ones of the protocol state store.
+func (c *Lookup) queryTagged(expr string) ([]*tagged, error) {
+	result, err := queryTaggedStore(c.store, expr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("query permanent store: %w", err)
+	}
+
+	found := make(map[string]struct{}, len(result))
+
+	for _, t := range result {
+		found[t.connectionID] = struct{}{}
+	}
+
+	protocolState, err := queryTaggedStore(c.protocolStateStore, expr, found)
+	if err != nil {
+		return nil, fmt.Errorf("query protocol state store: %w", err)
+	}
+
+	return append(result, protocolState...), nil
+}
+
+func queryTaggedStore(store storage.Store, expr string, skip map[string]struct{}) ([]*tagged, error) {
+	itr, err := store.Query(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer storage.Close(itr, logger)
+
+	var result []*tagged
+
+	more, err := itr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next set of data from iterator: %w", err)
+	}
+
+	for more {
+		key, err := itr.Key()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get key from iterator: %w", err)
+		}
+
+		connectionID := strings.TrimPrefix(key, getConnectionKeyPrefix()(""))
+
+		if _, ok := skip[connectionID]; !ok && strings.HasPrefix(key, getConnectionKeyPrefix()("")) {
+			tags, err := itr.Tags()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tags from iterator: %w", err)
+			}
+
+			t := &tagged{connectionID: connectionID, tags: make(map[string]string, len(tags))}
+
+			for _, tag := range tags {
+				t.tags[tag.Name] = tag.Value
+			}
+
+			result = append(result, t)
+		}
+
+		more, err = itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next set of data from iterator: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// queryByMetadata returns the IDs of the connections which have all the given metadata entries.
+func (c *Lookup) queryByMetadata(metadata map[string]string) (map[string]struct{}, error) {
+	var ids map[string]struct{}
+
+	for k, v := range metadata {
+		expr := metadataKeyTag + ":" + tagHash(k)
+		if v != "" {
+			expr = metadataKeyPrefix + ":" + tagHash(k, v)
+		}
+
+		found, err := c.connectionIDs(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if ids == nil {
+			ids = found
+
+			continue
+		}
+
+		for id := range ids {
+			if _, ok := found[id]; !ok {
+				delete(ids, id)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// connectionIDs returns the IDs of the connections having the metadata index entries matching the expression.
+func (c *Lookup) connectionIDs(expr string) (map[string]struct{}, error) {
+	itr, err := c.store.Query(expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metadata index: %w", err)
+	}
+
+	defer storage.Close(itr, logger)
+
+	ids := make(map[string]struct{})
+
+	more, err := itr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next set of data from metadata index iterator: %w", err)
+	}
+
+	for more {
+		value, err := itr.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value from iterator: %w", err)
+		}
+
+		ids[string(value)] = struct{}{}
+
+		more, err = itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next set of data from metadata index iterator: %w", err)
+		}
+	}
+
+	return ids, nil
+}
+
+// tagHash is used as the tag value of the metadata index entries and the query tags of the connection records,
+// the tag values cannot contain ':' characters so the values are hashed.
+func tagHash(values ...string) string {
+	h := sha256.New()
+
+	for _, v := range values {
+		// length-prefix to keep ("a", "bc") and ("ab", "c") apart
+		h.Write([]byte(fmt.Sprintf("%d:%s", len(v), v))) // nolint: errcheck
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func newQueryRecorder(t *testing.T) *Recorder {
+	t.Helper()
+
+	recorder, err := NewRecorder(&protocol.MockProvider{
+		StoreProvider:              mem.NewProvider(),
+		ProtocolStateStoreProvider: mem.NewProvider(),
+	})
+	require.NoError(t, err)
+
+	for i, md := range []map[string]string{
+		{"label": "alice", "group": "a"},
+		{"label": "bob", "group": "b"},
+		{"label": "carol", "group": "a"},
+		{"group": "c"},
+	} {
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{
+			ConnectionID:  fmt.Sprintf("conn-%d", i),
+			ThreadID:      fmt.Sprintf("thread-%d", i),
+			State:         StateNameCompleted,
+			MyDID:         fmt.Sprintf("did:example:my-%d", i),
+			TheirDID:      fmt.Sprintf("did:example:their-%d", i),
+			Metadata:      md,
+			CreatedAt:     time.Date(2021, 1, 1+i, 0, 0, 0, 0, time.UTC),
+			LastMessageAt: time.Date(2021, 2, 10-i, 0, 0, 0, 0, time.UTC),
+		}))
+	}
+
+	return recorder
+}
+
+func connectionIDsOf(records []*Record) []string {
+	var ids []string
+
+	for _, r := range records {
+		ids = append(ids, r.ConnectionID)
+	}
+
+	return ids
+}
+
+func TestLookup_QueryConnections(t *testing.T) {
+	t.Run("all connections sorted by creation time", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		records, err := recorder.QueryConnections(&QueryParams{SortBy: SortByCreatedAt})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0", "conn-1", "conn-2", "conn-3"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{SortBy: SortByCreatedAt, Descending: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-3", "conn-2", "conn-1", "conn-0"}, connectionIDsOf(records))
+	})
+
+	t.Run("sort by last message time and paginate", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		records, err := recorder.QueryConnections(&QueryParams{SortBy: SortByLastMessageAt, Offset: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-2", "conn-1"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{SortBy: SortByLastMessageAt, Offset: 4})
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("query by metadata", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		records, err := recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"group": "a"},
+			SortBy:   SortByCreatedAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0", "conn-2"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"group": "a", "label": "carol"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-2"}, connectionIDsOf(records))
+
+		// an empty value matches any value of the key
+		records, err = recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"label": ""},
+			SortBy:   SortByCreatedAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0", "conn-1", "conn-2"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"label": "dave"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("query by metadata and DIDs", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		records, err := recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"group": "a"},
+			TheirDID: "did:example:their-2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-2"}, connectionIDsOf(records))
+	})
+
+	t.Run("metadata changes are reindexed", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		require.NoError(t, recorder.SaveConnectionMetadata("conn-0", map[string]string{"group": "b"}))
+
+		records, err := recorder.QueryConnections(&QueryParams{Metadata: map[string]string{"label": "alice"}})
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		records, err = recorder.QueryConnections(&QueryParams{
+			Metadata: map[string]string{"group": "b"},
+			SortBy:   SortByCreatedAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0", "conn-1"}, connectionIDsOf(records))
+
+		require.NoError(t, recorder.RemoveConnection("conn-1"))
+
+		records, err = recorder.QueryConnections(&QueryParams{Metadata: map[string]string{"group": "b"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0"}, connectionIDsOf(records))
+	})
+
+	t.Run("query by tags", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{
+			ConnectionID: "conn-4",
+			ThreadID:     "thread-4",
+			State:        "requested",
+			MyDID:        "did:example:my-0",
+			InvitationID: "invitation",
+		}))
+
+		records, err := recorder.QueryConnections(&QueryParams{MyDID: "did:example:my-0", SortBy: SortByCreatedAt})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0", "conn-4"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{MyDID: "did:example:my-0", State: StateNameCompleted})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-0"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{InvitationID: "invitation"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-4"}, connectionIDsOf(records))
+
+		records, err = recorder.QueryConnections(&QueryParams{State: "requested", TheirDID: "did:example:their-0"})
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("records saved before they were tagged", func(t *testing.T) {
+		storeProvider := mem.NewProvider()
+		protocolStateStoreProvider := mem.NewProvider()
+
+		store, err := storeProvider.OpenStore(Namespace)
+		require.NoError(t, err)
+
+		record := &Record{ConnectionID: "legacy", State: StateNameCompleted, TheirDID: "did:example:legacy"}
+		require.NoError(t, marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID), record, store, storage.Tag{
+			Name:  getConnectionKeyPrefix()(""),
+			Value: getConnectionKeyPrefix()(record.ConnectionID),
+		}))
+
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider:              storeProvider,
+			ProtocolStateStoreProvider: protocolStateStoreProvider,
+		})
+		require.NoError(t, err)
+
+		records, err := recorder.QueryConnections(&QueryParams{TheirDID: "did:example:legacy"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"legacy"}, connectionIDsOf(records))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		_, err := recorder.QueryConnections(&QueryParams{Offset: -1})
+		require.EqualError(t, err, "offset and limit cannot be negative")
+
+		_, err = recorder.QueryConnections(&QueryParams{SortBy: "label"})
+		require.EqualError(t, err, `unsupported sort field "label"`)
+	})
+
+	t.Run("metadata index query error", func(t *testing.T) {
+		storeProvider := mockstorage.NewMockStoreProvider()
+
+		recorder, err := NewRecorder(&protocol.MockProvider{StoreProvider: storeProvider})
+		require.NoError(t, err)
+
+		storeProvider.Store.ErrQuery = fmt.Errorf(sampleErrMsg)
+
+		_, err = recorder.QueryConnections(&QueryParams{Metadata: map[string]string{"group": "a"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to query metadata index")
+	})
+}
+
+func TestRecorder_SaveConnectionMetadata(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		before, err := recorder.GetConnectionRecord("conn-0")
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionMetadata("conn-0", nil))
+
+		after, err := recorder.GetConnectionRecord("conn-0")
+		require.NoError(t, err)
+		require.Empty(t, after.Metadata)
+		require.Equal(t, before.CreatedAt, after.CreatedAt)
+		require.Equal(t, before.LastMessageAt, after.LastMessageAt)
+		require.False(t, after.UpdatedAt.Before(before.UpdatedAt))
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		err := recorder.SaveConnectionMetadata("conn-0", map[string]string{"": "value"})
+		require.EqualError(t, err, "metadata key cannot be empty")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		recorder := newQueryRecorder(t)
+
+		err := recorder.SaveConnectionMetadata("unknown", map[string]string{"label": "value"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+}
+
+func TestRecorder_UpdateLastMessageTime(t *testing.T) {
+	recorder := newQueryRecorder(t)
+
+	before, err := recorder.GetConnectionRecord("conn-0")
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, recorder.UpdateLastMessageTime("did:example:my-0", "did:example:their-0", now))
+
+	after, err := recorder.GetConnectionRecord("conn-0")
+	require.NoError(t, err)
+	require.True(t, now.Equal(after.LastMessageAt))
+	require.Equal(t, before.UpdatedAt, after.UpdatedAt)
+
+	// the updates are throttled
+	require.NoError(t, recorder.UpdateLastMessageTime("did:example:my-0", "did:example:their-0",
+		now.Add(time.Second)))
+
+	after, err = recorder.GetConnectionRecord("conn-0")
+	require.NoError(t, err)
+	require.True(t, now.Equal(after.LastMessageAt))
+
+	later := now.Add(lastMessageTimeResolution)
+	require.NoError(t, recorder.UpdateLastMessageTime("did:example:my-0", "did:example:their-0", later))
+
+	after, err = recorder.GetConnectionRecord("conn-0")
+	require.NoError(t, err)
+	require.True(t, later.Equal(after.LastMessageAt))
+
+	require.Error(t, recorder.UpdateLastMessageTime("did:example:my-0", "did:example:unknown", now))
+}
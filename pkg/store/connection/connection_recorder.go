@@ -14,6 +14,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
@@ -28,6 +31,10 @@ const (
 	//  will need to be figured with verification key
 	TheirNSPrefix    = "their"
 	errMsgInvalidKey = "invalid key"
+
+	// lastMessageTimeResolution is the minimal interval between two updates of the last message time
+	// of a connection, the time is not persisted on every message.
+	lastMessageTimeResolution = time.Minute
 )
 
 // NewRecorder returns new connection recorder.
@@ -39,12 +46,14 @@ func NewRecorder(p provider) (*Recorder, error) {
 		return nil, fmt.Errorf("failed to create new connection recorder : %w", err)
 	}
 
-	return &Recorder{lookup}, nil
+	return &Recorder{Lookup: lookup, lastMessages: map[string]time.Time{}}, nil
 }
 
 // Recorder is read-write connection store.
 type Recorder struct {
 	*Lookup
+	lastMessages   map[string]time.Time
+	lastMessagesMu sync.Mutex
 }
 
 // SaveInvitation saves invitation in permanent store for given key.
@@ -58,12 +67,159 @@ func (c *Recorder) SaveInvitation(id string, invitation interface{}) error {
 }
 
 // SaveConnectionRecord saves given connection records in underlying store.
-func (c *Recorder) SaveConnectionRecord(record *Record) error {
+// The update time is set to the current time. The stored record is read only for the records which were not loaded
+// from the store (zero update time): the creation time and the metadata are kept from the stored record
+// if they are not set and the latest of the last message times is kept.
+// The metadata of a loaded record is changed by SaveConnectionMetadata.
+func (c *Recorder) SaveConnectionRecord(rec *Record) error {
+	if !rec.UpdatedAt.IsZero() {
+		rec.UpdatedAt = time.Now().UTC()
+
+		return c.saveRecord(rec)
+	}
+
+	var oldMetadata map[string]string
+
+	existing, err := c.GetConnectionRecord(rec.ConnectionID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		logger.Warnf("get stored connection record: connectionid=%s err=%s", rec.ConnectionID, err)
+	}
+
+	if existing != nil {
+		oldMetadata = existing.Metadata
+
+		if rec.CreatedAt.IsZero() {
+			rec.CreatedAt = existing.CreatedAt
+		}
+
+		if rec.Metadata == nil {
+			rec.Metadata = existing.Metadata
+		}
+
+		if existing.LastMessageAt.After(rec.LastMessageAt) {
+			rec.LastMessageAt = existing.LastMessageAt
+		}
+	}
+
+	return c.saveWithMetadata(rec, oldMetadata)
+}
+
+func (c *Recorder) saveWithMetadata(rec *Record, oldMetadata map[string]string) error {
+	rec.UpdatedAt = time.Now().UTC()
+
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = rec.UpdatedAt
+	}
+
+	if err := c.saveRecord(rec); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(oldMetadata, rec.Metadata) {
+		if err := c.indexMetadata(rec.ConnectionID, oldMetadata, rec.Metadata); err != nil {
+			return fmt.Errorf("index connection metadata: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SaveConnectionMetadata replaces the metadata of the connection, the metadata is indexed
+// and can be used to query the connections (see Lookup.QueryConnections).
+func (c *Recorder) SaveConnectionMetadata(connectionID string, metadata map[string]string) error {
+	for k := range metadata {
+		if k == "" {
+			return errors.New("metadata key cannot be empty")
+		}
+	}
+
+	record, err := c.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	oldMetadata := record.Metadata
+	record.Metadata = metadata
+
+	return c.saveWithMetadata(record, oldMetadata)
+}
+
+// UpdateLastMessageTime sets the time of the last message exchanged over the completed connection between the DIDs.
+// The time is persisted at most once per minute for the connection, the later updates are skipped.
+func (c *Recorder) UpdateLastMessageTime(myDID, theirDID string, t time.Time) error {
+	key := myDID + keySeparator + theirDID
+
+	c.lastMessagesMu.Lock()
+	last, ok := c.lastMessages[key]
+
+	if ok && t.Sub(last) < lastMessageTimeResolution {
+		c.lastMessagesMu.Unlock()
+
+		return nil
+	}
+
+	c.lastMessages[key] = t
+	c.lastMessagesMu.Unlock()
+
+	err := c.updateLastMessageTime(myDID, theirDID, t)
+	if err != nil {
+		c.lastMessagesMu.Lock()
+		delete(c.lastMessages, key)
+		c.lastMessagesMu.Unlock()
+	}
+
+	return err
+}
+
+func (c *Recorder) updateLastMessageTime(myDID, theirDID string, t time.Time) error {
+	connectionID, err := c.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return err
+	}
+
+	var record Record
+
+	if err = getAndUnmarshal(getConnectionKeyPrefix()(connectionID), &record, c.store); err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	record.LastMessageAt = t.UTC()
+
+	// the update time is not changed, the record itself stays the same
+	return c.saveRecord(&record)
+}
+
+// UpdateConnectionDIDs replaces the DIDs of the completed connection, e.g. when one of the parties rotates its DID.
+// The connection can be looked up by the new DIDs only.
+func (c *Recorder) UpdateConnectionDIDs(connectionID, myDID, theirDID string) error {
+	record, err := c.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	if record.State != StateNameCompleted {
+		return fmt.Errorf("connection is not completed: connectionid=%s state=%s", connectionID, record.State)
+	}
+
+	err = c.store.Delete(getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID))
+	if err != nil {
+		return fmt.Errorf("delete did and connection map: %w", err)
+	}
+
+	record.MyDID = myDID
+	record.TheirDID = theirDID
+
+	return c.SaveConnectionRecord(record)
+}
+
+func (c *Recorder) saveRecord(record *Record) error {
+	tags := append([]storage.Tag{{
+		Name:  getConnectionKeyPrefix()(""),
+		Value: getConnectionKeyPrefix()(record.ConnectionID),
+	}}, queryTags(record)...)
+
 	if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
-		record, c.protocolStateStore, storage.Tag{
-			Name:  getConnectionKeyPrefix()(""),
-			Value: getConnectionKeyPrefix()(record.ConnectionID),
-		}); err != nil {
+		record, c.protocolStateStore, tags...); err != nil {
 		return fmt.Errorf("save connection record in protocol state store: %w", err)
 	}
 
@@ -80,10 +236,7 @@ func (c *Recorder) SaveConnectionRecord(record *Record) error {
 
 	if record.State == StateNameCompleted {
 		if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
-			record, c.store, storage.Tag{
-				Name:  getConnectionKeyPrefix()(""),
-				Value: getConnectionKeyPrefix()(record.ConnectionID),
-			}); err != nil {
+			record, c.store, tags...); err != nil {
 			return fmt.Errorf("save connection record in permanent store: %w", err)
 		}
 
@@ -97,6 +250,31 @@ func (c *Recorder) SaveConnectionRecord(record *Record) error {
 	return nil
 }
 
+// indexMetadata replaces the metadata index entries of the connection.
+func (c *Recorder) indexMetadata(connectionID string, oldMetadata, metadata map[string]string) error {
+	for k := range oldMetadata {
+		if _, ok := metadata[k]; ok {
+			continue
+		}
+
+		if err := c.store.Delete(getMetadataKeyPrefix()(connectionID, tagHash(k))); err != nil {
+			return fmt.Errorf("delete metadata index entry: %w", err)
+		}
+	}
+
+	for k, v := range metadata {
+		err := c.store.Put(getMetadataKeyPrefix()(connectionID, tagHash(k)), []byte(connectionID),
+			storage.Tag{Name: metadataKeyTag, Value: tagHash(k)},
+			storage.Tag{Name: metadataKeyPrefix, Value: tagHash(k, v)},
+		)
+		if err != nil {
+			return fmt.Errorf("save metadata index entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // SaveConnectionRecordWithMappings saves newly created connection record against the connection id in the store
 // and it creates mapping from namespaced ThreadID to connection ID.
 func (c *Recorder) SaveConnectionRecordWithMappings(record *Record) error {
@@ -172,6 +350,11 @@ func (c *Recorder) RemoveConnection(connectionID string) error {
 			connectionID, err)
 	}
 
+	err = c.indexMetadata(connectionID, record.Metadata, nil)
+	if err != nil {
+		return fmt.Errorf("unable to delete connection metadata index: connectionid=%s err=%w", connectionID, err)
+	}
+
 	// remove namespace, threadID and connection ID mapping from protocol state store
 	err = removeMappings(c, record)
 	if err != nil {
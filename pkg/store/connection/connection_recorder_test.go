@@ -10,6 +10,7 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -797,3 +798,54 @@ type mockInvitation struct {
 	Type            string            `json:"@type,omitempty"`
 	Thread          *decorator.Thread `json:"~thread,omitempty"`
 }
+
+func TestConnectionRecorder_UpdateConnectionDIDs(t *testing.T) {
+	t.Run("update connection DIDs", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{
+			ConnectionID: sampleConnID,
+			ThreadID:     threadIDValue,
+			State:        StateNameCompleted,
+			MyDID:        "did:example:my",
+			TheirDID:     "did:example:their",
+		}))
+
+		require.NoError(t, recorder.UpdateConnectionDIDs(sampleConnID, "did:example:my-new", "did:example:their"))
+
+		record, err := recorder.GetConnectionRecord(sampleConnID)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:my-new", record.MyDID)
+
+		connID, err := recorder.GetConnectionIDByDIDs("did:example:my-new", "did:example:their")
+		require.NoError(t, err)
+		require.Equal(t, sampleConnID, connID)
+
+		_, err = recorder.GetConnectionIDByDIDs("did:example:my", "did:example:their")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{
+			ConnectionID: sampleConnID,
+			ThreadID:     threadIDValue,
+			State:        stateNameInvited,
+		}))
+
+		err = recorder.UpdateConnectionDIDs(sampleConnID, "did:example:my-new", "did:example:their")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection is not completed")
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.UpdateConnectionDIDs(sampleConnID, "did:example:my-new", "did:example:their")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
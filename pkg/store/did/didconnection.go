@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 
@@ -41,6 +42,8 @@ type ConnectionStoreImpl struct {
 
 type didRecord struct {
 	DID string `json:"did,omitempty"`
+	// Expires is set for the keys of a rotated DID, the keys resolve to the new DID until then.
+	Expires *time.Time `json:"expires,omitempty"`
 	// TODO add type below to distinguish Legacy vs new Packer
 	// envelopeType string
 }
@@ -62,10 +65,12 @@ func NewConnectionStore(ctx connectionProvider) (*ConnectionStoreImpl, error) {
 
 // saveDID saves a DID, indexed using the given public key.
 func (c *ConnectionStoreImpl) saveDID(did, key string) error {
-	data := didRecord{
+	return c.saveRecord(key, &didRecord{
 		DID: did,
-	}
+	})
+}
 
+func (c *ConnectionStoreImpl) saveRecord(key string, data *didRecord) error {
 	bytes, err := json.Marshal(data)
 	if err != nil {
 		return err
@@ -88,6 +93,31 @@ func (c *ConnectionStoreImpl) SaveDID(did string, keys ...string) error {
 
 // SaveDIDFromDoc saves a map from a did doc's keys to the did.
 func (c *ConnectionStoreImpl) SaveDIDFromDoc(doc *diddoc.Doc) error {
+	return c.SaveDID(doc.ID, docKeys(doc)...)
+}
+
+// SaveRotatedDID saves a map from the new did doc's keys to the new did, the keys of the old did
+// are mapped to the new did as well until the grace period ends.
+func (c *ConnectionStoreImpl) SaveRotatedDID(oldDID string, newDoc *diddoc.Doc, gracePeriod time.Duration) error {
+	docResolution, err := c.vdr.Resolve(oldDID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve old did: %w", err)
+	}
+
+	expires := time.Now().Add(gracePeriod).UTC()
+
+	for _, key := range docKeys(docResolution.DIDDocument) {
+		err = c.saveRecord(key, &didRecord{DID: newDoc.ID, Expires: &expires})
+		if err != nil {
+			return fmt.Errorf("saving rotated DID in did map: %w", err)
+		}
+	}
+
+	return c.SaveDIDFromDoc(newDoc)
+}
+
+// docKeys returns the keys of the did doc used to look up the did.
+func docKeys(doc *diddoc.Doc) []string {
 	var keys []string
 	for i := range doc.VerificationMethod {
 		// TODO fix hardcode base58 https://github.com/hyperledger/aries-framework-go/issues/1207
@@ -109,7 +139,7 @@ func (c *ConnectionStoreImpl) SaveDIDFromDoc(doc *diddoc.Doc) error {
 		keys = append(keys, svc.RecipientKeys...)
 	}
 
-	return c.SaveDID(doc.ID, keys...)
+	return keys
 }
 
 // SaveDIDByResolving resolves a DID using the VDR then saves the map from keys -> did
@@ -141,5 +171,10 @@ func (c *ConnectionStoreImpl) GetDID(key string) (string, error) {
 		return "", err
 	}
 
+	// the grace period of the rotated DID is over
+	if record.Expires != nil && time.Now().After(*record.Expires) {
+		return "", ErrNotFound
+	}
+
 	return record.DID, nil
 }
@@ -9,9 +9,12 @@ package did
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
+	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
@@ -113,6 +116,44 @@ func TestBaseConnectionStore(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("SaveRotatedDID", func(t *testing.T) {
+		oldDoc := mockdiddoc.GetMockDIDDoc(t)
+		oldKey := base58.Encode(oldDoc.VerificationMethod[0].Value)
+		newDoc := &diddoc.Doc{
+			ID:                 "did:example:rotated",
+			VerificationMethod: []diddoc.VerificationMethod{{Value: []byte("new-key")}},
+		}
+
+		cs, err := NewConnectionStore(&prov)
+		require.NoError(t, err)
+
+		require.NoError(t, cs.SaveRotatedDID(oldDoc.ID, newDoc, time.Hour))
+
+		didVal, err := cs.GetDID(oldKey)
+		require.NoError(t, err)
+		require.Equal(t, newDoc.ID, didVal)
+
+		didVal, err = cs.GetDID(base58.Encode([]byte("new-key")))
+		require.NoError(t, err)
+		require.Equal(t, newDoc.ID, didVal)
+
+		// the grace period is over
+		require.NoError(t, cs.SaveRotatedDID(oldDoc.ID, newDoc, -time.Second))
+
+		_, err = cs.GetDID(oldKey)
+		require.EqualError(t, err, ErrNotFound.Error())
+
+		cs, err = NewConnectionStore(&ctx{
+			store: mockstorage.NewMockStoreProvider(),
+			vdr:   &mockvdr.MockVDRegistry{ResolveErr: fmt.Errorf("resolve error")},
+		})
+		require.NoError(t, err)
+
+		err = cs.SaveRotatedDID(oldDoc.ID, newDoc, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve error")
+	})
+
 	t.Run("SaveDIDByResolving error", func(t *testing.T) {
 		prov := ctx{
 			store: mockstorage.NewMockStoreProvider(),
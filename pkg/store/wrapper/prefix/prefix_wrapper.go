@@ -34,13 +34,13 @@ type StorePrefixWrapper struct {
 	prefix string
 }
 
-// Put stores v with k ID by prefixing it with IDPrefix.
+// Put stores v and its tags with k ID by prefixing it with IDPrefix.
 func (b *StorePrefixWrapper) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k != "" {
 		k = b.prefix + k
 	}
 
-	return b.store.Put(k, v)
+	return b.store.Put(k, v, tags...)
 }
 
 // Get fetches the record based on k by first prefixing it with IDPrefix.
@@ -82,6 +82,14 @@ func TestStorePrefixWrapper(t *testing.T) {
 		err = store.Put("", data)
 		require.EqualError(t, err, "key cannot be empty")
 
+		// tags are stored with the prefixed key
+		err = store.Put(key, data, storage.Tag{Name: "tagName"})
+		require.NoError(t, err)
+
+		tags, err := memStore.GetTags("testPrefix" + key)
+		require.NoError(t, err)
+		require.Equal(t, []storage.Tag{{Name: "tagName"}}, tags)
+
 		err = prov.Close()
 		require.NoError(t, err)
 	})
@@ -8,6 +8,7 @@ package wallet
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -48,6 +49,10 @@ const (
 	Key ContentType = "key"
 )
 
+// collectionTag is name of the storage tag which maps wallet contents to collections,
+// tag value is made of content type and encoded collection ID.
+const collectionTag = "collectionID"
+
 // IsValid checks if underlying content type is supported.
 func (ct ContentType) IsValid() error {
 	switch ct {
@@ -91,6 +96,7 @@ func newContentStore(p storage.Provider, pr *profile) (*contentStore, error) {
 
 	err = p.SetStoreConfig(pr.ID, storage.StoreConfiguration{TagNames: []string{
 		Collection.Name(), Credential.Name(), Connection.Name(), DIDResolutionResponse.Name(), Connection.Name(), Key.Name(),
+		collectionTag,
 	}})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store config for user '%s' : %w", pr.User, err)
@@ -103,24 +109,37 @@ func newContentStore(p storage.Provider, pr *profile) (*contentStore, error) {
 // if content document id is missing from content, then system generated id will be used as key for storage.
 // returns error if content with same ID already exists in store.
 // For replacing already existing content, use 'Remove() + Add()'.
-func (cs *contentStore) Save(auth string, ct ContentType, content []byte) error {
+func (cs *contentStore) Save(auth string, ct ContentType, content []byte, options ...AddContentOptions) error {
+	opts := &addContentOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
 	switch ct {
-	case Collection, Metadata, Connection, Credential:
-		key, err := getContentID(content)
+	case Collection, Metadata, Connection, Credential, DIDResolutionResponse:
+		key, err := getContentKey(ct, content)
 		if err != nil {
 			return err
 		}
 
-		return cs.safeSave(getContentKeyPrefix(ct, key), content, storage.Tag{Name: ct.Name()})
-	case DIDResolutionResponse:
-		// verify did resolution result before storing and also use DID ID as content key
-		docRes, err := did.ParseDocumentResolution(content)
-		if err != nil {
-			return fmt.Errorf("invalid DID resolution response model: %w", err)
+		tags := []storage.Tag{{Name: ct.Name()}}
+
+		for _, collectionID := range opts.collectionIDs {
+			_, err = cs.store.Get(getContentKeyPrefix(Collection, collectionID))
+			if err != nil {
+				return fmt.Errorf("failed to find collection with ID '%s' : %w", collectionID, err)
+			}
+
+			tags = append(tags, storage.Tag{Name: collectionTag, Value: getCollectionTagValue(ct, collectionID)})
 		}
 
-		return cs.safeSave(getContentKeyPrefix(ct, docRes.DIDDocument.ID), content, storage.Tag{Name: ct.Name()})
+		return cs.safeSave(key, content, tags...)
 	case Key:
+		if len(opts.collectionIDs) > 0 {
+			return errors.New("keys can not be added to collections")
+		}
+
 		// never save keys in store, just import them into kms
 		var key keyContent
 
@@ -136,6 +155,23 @@ func (cs *contentStore) Save(auth string, ct ContentType, content []byte) error
 	}
 }
 
+// Contains checks if content with same type and id as given content already exists in store.
+func (cs *contentStore) Contains(ct ContentType, content []byte) (bool, error) {
+	key, err := getContentKey(ct, content)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = cs.store.Get(key)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // safeSave saves given content to store by given key but returns error if content with given key already exists.
 func (cs *contentStore) safeSave(key string, content []byte, tags ...storage.Tag) error {
 	_, err := cs.store.Get(key)
@@ -167,10 +203,121 @@ func saveKey(auth string, key *keyContent) error {
 }
 
 // Remove to remove wallet content from wallet contents store.
+// Removing a collection removes membership of all its contents, but not the contents themselves.
 func (cs *contentStore) Remove(ct ContentType, key string) error {
+	if ct == Collection {
+		if err := cs.removeCollectionMembers(key); err != nil {
+			return fmt.Errorf("failed to remove collection members: %w", err)
+		}
+	}
+
 	return cs.store.Delete(getContentKeyPrefix(ct, key))
 }
 
+// AddToCollection adds existing wallet content with given type and ID to given collections,
+// collections should already exist in wallet. Keys can not be added to collections.
+func (cs *contentStore) AddToCollection(ct ContentType, contentID string, collectionIDs ...string) error {
+	switch ct {
+	case Collection, Metadata, Connection, Credential, DIDResolutionResponse:
+	default:
+		return fmt.Errorf("contents of type '%s' can not be added to collections", ct)
+	}
+
+	key := getContentKeyPrefix(ct, contentID)
+
+	content, err := cs.store.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to find content with ID '%s' : %w", contentID, err)
+	}
+
+	tags, err := cs.store.GetTags(key)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		if tag.Name == collectionTag {
+			existing[tag.Value] = struct{}{}
+		}
+	}
+
+	for _, collectionID := range collectionIDs {
+		_, err = cs.store.Get(getContentKeyPrefix(Collection, collectionID))
+		if err != nil {
+			return fmt.Errorf("failed to find collection with ID '%s' : %w", collectionID, err)
+		}
+
+		value := getCollectionTagValue(ct, collectionID)
+		if _, ok := existing[value]; ok {
+			continue
+		}
+
+		existing[value] = struct{}{}
+
+		tags = append(tags, storage.Tag{Name: collectionTag, Value: value})
+	}
+
+	return cs.store.Put(key, content, tags...)
+}
+
+// removeCollectionMembers removes collection mapping tags of given collection from all wallet contents.
+func (cs *contentStore) removeCollectionMembers(collectionID string) error {
+	for _, ct := range []ContentType{Collection, Credential, DIDResolutionResponse, Metadata, Connection} {
+		members, err := cs.query(collectionTag + ":" + getCollectionTagValue(ct, collectionID))
+		if err != nil {
+			return err
+		}
+
+		for key, content := range members {
+			tags, err := cs.store.GetTags(key)
+			if err != nil {
+				return err
+			}
+
+			remaining := make([]storage.Tag, 0, len(tags))
+
+			for _, tag := range tags {
+				if tag.Name != collectionTag || tag.Value != getCollectionTagValue(ct, collectionID) {
+					remaining = append(remaining, tag)
+				}
+			}
+
+			if err = cs.store.Put(key, content, remaining...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Collections returns IDs of the collections to which content with given storage key belongs.
+func (cs *contentStore) Collections(key string) ([]string, error) {
+	tags, err := cs.store.GetTags(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionIDs []string
+
+	for _, tag := range tags {
+		if tag.Name != collectionTag {
+			continue
+		}
+
+		collectionID, err := parseCollectionTagValue(tag.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		collectionIDs = append(collectionIDs, collectionID)
+	}
+
+	return collectionIDs, nil
+}
+
 // Get to get wallet content from wallet contents store.
 func (cs *contentStore) Get(ct ContentType, key string) ([]byte, error) {
 	return cs.store.Get(getContentKeyPrefix(ct, key))
@@ -178,8 +325,23 @@ func (cs *contentStore) Get(ct ContentType, key string) ([]byte, error) {
 
 // GetAll returns all wallet contents of give type.
 // returns empty result when no data found.
-func (cs *contentStore) GetAll(ct ContentType) (map[string]json.RawMessage, error) {
-	iter, err := cs.store.Query(ct.Name())
+func (cs *contentStore) GetAll(ct ContentType, options ...GetAllContentsOptions) (map[string]json.RawMessage, error) {
+	opts := &getAllContentsOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.collectionID != "" {
+		return cs.query(collectionTag + ":" + getCollectionTagValue(ct, opts.collectionID))
+	}
+
+	return cs.query(ct.Name())
+}
+
+// query returns all wallet contents matching given query expression.
+func (cs *contentStore) query(expression string) (map[string]json.RawMessage, error) {
+	iter, err := cs.store.Query(expression)
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +374,26 @@ func (cs *contentStore) GetAll(ct ContentType) (map[string]json.RawMessage, erro
 	return result, nil
 }
 
+// getContentKey returns storage key of given content by wallet content type.
+func getContentKey(ct ContentType, content []byte) (string, error) {
+	if ct == DIDResolutionResponse {
+		// verify did resolution result before storing and also use DID ID as content key
+		docRes, err := did.ParseDocumentResolution(content)
+		if err != nil {
+			return "", fmt.Errorf("invalid DID resolution response model: %w", err)
+		}
+
+		return getContentKeyPrefix(ct, docRes.DIDDocument.ID), nil
+	}
+
+	key, err := getContentID(content)
+	if err != nil {
+		return "", err
+	}
+
+	return getContentKeyPrefix(ct, key), nil
+}
+
 func getContentID(content []byte) (string, error) {
 	var cid contentID
 	if err := json.Unmarshal(content, &cid); err != nil {
@@ -233,6 +415,27 @@ func getContentKeyPrefix(ct ContentType, key string) string {
 	return fmt.Sprintf("%s_%s", ct, key)
 }
 
+// getCollectionTagValue returns value of collection mapping tag by wallet content type and collection ID.
+// collection ID is encoded since tag values can not contain query expression separator.
+func getCollectionTagValue(ct ContentType, collectionID string) string {
+	return fmt.Sprintf("%s_%s", ct, base64.RawURLEncoding.EncodeToString([]byte(collectionID)))
+}
+
+// parseCollectionTagValue returns collection ID from value of collection mapping tag.
+func parseCollectionTagValue(value string) (string, error) {
+	parts := strings.SplitN(value, "_", 2) //nolint: gomnd
+	if len(parts) != 2 {                   //nolint: gomnd
+		return "", fmt.Errorf("invalid collection mapping '%s'", value)
+	}
+
+	collectionID, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid collection mapping '%s' : %w", value, err)
+	}
+
+	return string(collectionID), nil
+}
+
 // newContentBasedVDR returns new wallet content store based VDR.
 func newContentBasedVDR(v vdr.Registry, c *contentStore) *walletVDR {
 	return &walletVDR{Registry: v, contents: c}
@@ -186,7 +186,8 @@ func TestContentStores(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, contentStore)
 		require.EqualValues(t, sp.config.TagNames,
-			[]string{"collection", "credential", "connection", "didResolutionResponse", "connection", "key"})
+			[]string{"collection", "credential", "connection", "didResolutionResponse", "connection", "key",
+				"collectionID"})
 	})
 
 	t.Run("create new content store - failure", func(t *testing.T) {
@@ -563,6 +564,132 @@ func TestContentStore_GetAll(t *testing.T) {
 	})
 }
 
+func TestContentStore_Collections(t *testing.T) {
+	const (
+		collectionFmt = `{"@context": ["https://w3id.org/wallet/v1"], "id": "%s", "type": "Collection"}`
+		metadataFmt   = `{"@context": ["https://w3id.org/wallet/v1"], "id": "%s", "type": "Metadata"}`
+		collectionID1 = "did:example:collection:relying-party"
+		collectionID2 = "urn:uuid:3ed0b1b6-25d1-4a57-ba46-a22a8a0c6e72"
+	)
+
+	t.Run("add contents to collections - success", func(t *testing.T) {
+		contentStore, err := newContentStore(getMockStorageProvider(), &profile{ID: uuid.New().String()})
+		require.NoError(t, err)
+
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Collection, []byte(fmt.Sprintf(collectionFmt, collectionID1))))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Collection, []byte(fmt.Sprintf(collectionFmt, collectionID2))))
+
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-1")),
+			AddByCollection(collectionID1)))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-2")),
+			AddByCollection(collectionID1, collectionID2)))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-3"))))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Credential, []byte(fmt.Sprintf(metadataFmt, "vc-1")),
+			AddByCollection(collectionID2)))
+
+		all, err := contentStore.GetAll(Metadata)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+
+		members, err := contentStore.GetAll(Metadata, WithCollection(collectionID1))
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		require.Contains(t, members, getContentKeyPrefix(Metadata, "meta-1"))
+		require.Contains(t, members, getContentKeyPrefix(Metadata, "meta-2"))
+
+		members, err = contentStore.GetAll(Metadata, WithCollection(collectionID2))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Contains(t, members, getContentKeyPrefix(Metadata, "meta-2"))
+
+		members, err = contentStore.GetAll(Credential, WithCollection(collectionID2))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+
+		collections, err := contentStore.Collections(getContentKeyPrefix(Metadata, "meta-2"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{collectionID1, collectionID2}, collections)
+
+		// removing collection removes membership only.
+		require.NoError(t, contentStore.Remove(Collection, collectionID1))
+
+		members, err = contentStore.GetAll(Metadata, WithCollection(collectionID1))
+		require.NoError(t, err)
+		require.Empty(t, members)
+
+		collections, err = contentStore.Collections(getContentKeyPrefix(Metadata, "meta-2"))
+		require.NoError(t, err)
+		require.Equal(t, []string{collectionID2}, collections)
+
+		all, err = contentStore.GetAll(Metadata)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+	})
+
+	t.Run("add existing contents to collections - success", func(t *testing.T) {
+		contentStore, err := newContentStore(getMockStorageProvider(), &profile{ID: uuid.New().String()})
+		require.NoError(t, err)
+
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Collection, []byte(fmt.Sprintf(collectionFmt, collectionID1))))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Collection, []byte(fmt.Sprintf(collectionFmt, collectionID2))))
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-1")),
+			AddByCollection(collectionID1)))
+
+		require.NoError(t, contentStore.AddToCollection(Metadata, "meta-1", collectionID1, collectionID2))
+
+		collections, err := contentStore.Collections(getContentKeyPrefix(Metadata, "meta-1"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{collectionID1, collectionID2}, collections)
+
+		members, err := contentStore.GetAll(Metadata, WithCollection(collectionID2))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.JSONEq(t, fmt.Sprintf(metadataFmt, "meta-1"), string(members[getContentKeyPrefix(Metadata, "meta-1")]))
+
+		// content type remains.
+		all, err := contentStore.GetAll(Metadata)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+	})
+
+	t.Run("add existing contents to collections - failure", func(t *testing.T) {
+		contentStore, err := newContentStore(getMockStorageProvider(), &profile{ID: uuid.New().String()})
+		require.NoError(t, err)
+
+		require.NoError(t, contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-1"))))
+
+		err = contentStore.AddToCollection(Metadata, "meta-1", collectionID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find collection")
+
+		err = contentStore.AddToCollection(Metadata, "meta-2", collectionID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find content with ID 'meta-2'")
+
+		err = contentStore.AddToCollection(Key, "key-1", collectionID1)
+		require.EqualError(t, err, "contents of type 'key' can not be added to collections")
+	})
+
+	t.Run("add contents to collections - failure", func(t *testing.T) {
+		contentStore, err := newContentStore(getMockStorageProvider(), &profile{ID: uuid.New().String()})
+		require.NoError(t, err)
+
+		err = contentStore.Save(sampleFakeTkn, Metadata, []byte(fmt.Sprintf(metadataFmt, "meta-1")),
+			AddByCollection(collectionID1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find collection")
+
+		err = contentStore.Save(sampleFakeTkn, Key, []byte(sampleKeyContentBase58Valid), AddByCollection(collectionID1))
+		require.EqualError(t, err, "keys can not be added to collections")
+
+		_, err = parseCollectionTagValue("invalid")
+		require.Error(t, err)
+
+		_, err = parseCollectionTagValue("metadata_!!")
+		require.Error(t, err)
+	})
+}
+
 func TestContentDIDResolver(t *testing.T) {
 	t.Run("create new content store - success", func(t *testing.T) {
 		sp := getMockStorageProvider()
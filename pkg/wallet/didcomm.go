@@ -0,0 +1,635 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/client/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/client/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	didexchangeSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	issuecredentialSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	outofbandSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	presentproofSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/cm"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+var logger = log.New("aries-framework/wallet")
+
+// miscellaneous constants for DIDComm features of the wallet.
+const (
+	msgEventBufferSize = 10
+	defaultWaitTimeout = 2 * time.Minute
+
+	connectionContentType = "Connection"
+
+	// attachment formats of presentation exchange (https://identity.foundation/presentation-exchange).
+	peDefinitionFormat = "dif/presentation-exchange/definitions@v1.0"
+	peSubmissionFormat = "dif/presentation-exchange/submission@v1.0"
+
+	mimeTypeApplicationLdJSON = "application/ld+json"
+)
+
+// connectionContent is the wallet content model of a DIDComm connection.
+type connectionContent struct {
+	Context      []string `json:"@context"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	Name         string   `json:"name,omitempty"`
+	MyDID        string   `json:"myDID"`
+	TheirDID     string   `json:"theirDID"`
+	InvitationID string   `json:"invitationID,omitempty"`
+	ThreadID     string   `json:"threadID,omitempty"`
+	State        string   `json:"state"`
+}
+
+// presentationExchangePayload is the payload of a request presentation attachment
+// in presentation exchange definition format.
+type presentationExchangePayload struct {
+	PresentationDefinition json.RawMessage `json:"presentation_definition"`
+}
+
+// Connect accepts out-of-band invitation and performs DID exchange with the inviter.
+// Once connection is completed, connection is saved to wallet as a 'connection' content.
+//
+//	Args:
+//		- authToken: authorization for performing operation.
+//		- invitation: out-of-band invitation from the other agent.
+//		- options: options for accepting invitation and connecting to the other agent.
+//
+// Returns:
+//		- ID of the connection established.
+//		- error if operation fails.
+func (c *Wallet) Connect(authToken string, invitation *outofband.Invitation,
+	options ...ConnectOptions) (string, error) {
+	record, err := c.connect(authToken, invitation, options...)
+	if err != nil {
+		return "", err
+	}
+
+	return record.ConnectionID, nil
+}
+
+// ProposePresentation accepts out-of-band invitation from a verifier, connects to the verifier and sends
+// propose presentation message. Waits for request presentation message from the verifier and runs wallet
+// query with the presentation definition(s) attached to the request.
+// Wallet receives present proof action events while waiting, so no other consumer of those action events should be
+// registered.
+//
+//	Args:
+//		- authToken: authorization for performing operation.
+//		- invitation: out-of-band invitation from the verifier.
+//		- options: options for connecting to the verifier and for waiting for the request presentation.
+//
+// Returns:
+//		- request presentation received along with matching candidates from wallet.
+//		- error if operation fails.
+func (c *Wallet) ProposePresentation(authToken string, invitation *outofband.Invitation,
+	options ...InitiateInteractionOption) (*PresentationRequest, error) {
+	opts := &initiateInteractionOpts{timeout: defaultWaitTimeout}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	record, err := c.connect(authToken, invitation, opts.connectOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	didCommCtx, err := c.didCommContext()
+	if err != nil {
+		return nil, err
+	}
+
+	ppClient, err := presentproof.New(didCommCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create present proof client: %w", err)
+	}
+
+	actions, unregister, err := registerActionEvents(ppClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register for present proof actions: %w", err)
+	}
+
+	defer unregister()
+
+	thID, err := ppClient.SendProposePresentation(&presentproof.ProposePresentation{}, record.MyDID, record.TheirDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to propose presentation from wallet: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	msg, err := waitForAction(ctx, actions, thID, presentproofSvc.RequestPresentationMsgType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get request presentation: %w", err)
+	}
+
+	request := &presentproof.RequestPresentation{}
+
+	if err = msg.Decode(request); err != nil {
+		return nil, fmt.Errorf("failed to decode request presentation: %w", err)
+	}
+
+	candidates, err := c.queryRequestedPresentations(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresentationRequest{
+		ThreadID:     thID,
+		ConnectionID: record.ConnectionID,
+		Request:      request,
+		Candidates:   candidates,
+	}, nil
+}
+
+// PresentProof sends given presentation as a response to the request presentation message of given thread.
+// Presentation is typically one of the candidates returned by `ProposePresentation()` signed by `Prove()`.
+//
+//	Args:
+//		- authToken: authorization for performing operation.
+//		- thID: thread ID of the present proof interaction.
+//		- presentation: presentation to be sent.
+//
+// Returns:
+//		- error if operation fails.
+func (c *Wallet) PresentProof(authToken, thID string, presentation *verifiable.Presentation) error {
+	if err := c.Authorize(authToken, SignScope); err != nil {
+		return err
+	}
+
+	if presentation == nil {
+		return errors.New("presentation is mandatory")
+	}
+
+	didCommCtx, err := c.didCommContext()
+	if err != nil {
+		return err
+	}
+
+	ppClient, err := presentproof.New(didCommCtx)
+	if err != nil {
+		return fmt.Errorf("failed to create present proof client: %w", err)
+	}
+
+	attachID := uuid.New().String()
+
+	err = ppClient.AcceptRequestPresentation(thID, &presentproof.Presentation{
+		Formats: []presentproofSvc.Format{{AttachID: attachID, Format: peSubmissionFormat}},
+		PresentationsAttach: []decorator.Attachment{{
+			ID:       attachID,
+			MimeType: mimeTypeApplicationLdJSON,
+			Data:     decorator.AttachmentData{JSON: presentation},
+		}},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to present proof from wallet: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptCredentialOffer accepts credential offer of given thread, waits for the credential to be issued
+// and saves the issued credential(s) to wallet content store.
+// Wallet receives issue credential action events while waiting, so no other consumer of those action events should
+// be registered.
+//
+//	Args:
+//		- authToken: authorization for performing operation.
+//		- thID: thread ID of the issue credential interaction.
+//		- options: options for saving credentials received and for waiting for the credential to be issued.
+//
+// Returns:
+//		- credentials received and saved in wallet.
+//		- error if operation fails.
+func (c *Wallet) AcceptCredentialOffer(authToken, thID string,
+	options ...ConcludeInteractionOptions) ([]*verifiable.Credential, error) {
+	if err := c.Authorize(authToken, SignScope); err != nil {
+		return nil, err
+	}
+
+	opts := &concludeInteractionOpts{timeout: defaultWaitTimeout}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	didCommCtx, err := c.didCommContext()
+	if err != nil {
+		return nil, err
+	}
+
+	icClient, err := issuecredential.New(didCommCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue credential client: %w", err)
+	}
+
+	if err = findCredentialOffer(icClient, thID); err != nil {
+		return nil, fmt.Errorf("failed to find credential offer: %w", err)
+	}
+
+	actions, unregister, err := registerActionEvents(icClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register for issue credential actions: %w", err)
+	}
+
+	defer unregister()
+
+	if err = icClient.AcceptOffer(thID); err != nil {
+		return nil, fmt.Errorf("failed to accept credential offer: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	msg, err := waitForAction(ctx, actions, thID, issuecredentialSvc.IssueCredentialMsgType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issued credential: %w", err)
+	}
+
+	issued := &issuecredential.IssueCredential{}
+
+	if err = msg.Decode(issued); err != nil {
+		return nil, fmt.Errorf("failed to decode issue credential message: %w", err)
+	}
+
+	credentials, err := c.readIssuedCredentials(issued)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(credentials))
+
+	for i, credential := range credentials {
+		raw, e := credential.MarshalJSON()
+		if e != nil {
+			return nil, fmt.Errorf("failed to marshal credential issued: %w", e)
+		}
+
+		if e = c.Add(authToken, Credential, raw, AddByCollection(opts.collectionIDs...)); e != nil {
+			return nil, fmt.Errorf("failed to save credential issued: %w", e)
+		}
+
+		names[i] = credential.ID
+	}
+
+	if err = icClient.AcceptCredential(thID, names...); err != nil {
+		return nil, fmt.Errorf("failed to accept credential: %w", err)
+	}
+
+	return credentials, nil
+}
+
+// connect accepts out-of-band invitation, waits for DID exchange to complete and saves the connection to wallet.
+func (c *Wallet) connect(authToken string, invitation *outofband.Invitation,
+	options ...ConnectOptions) (*didexchange.Connection, error) {
+	if err := c.Authorize(authToken, ReadScope); err != nil {
+		return nil, err
+	}
+
+	if invitation == nil {
+		return nil, errors.New("invitation is mandatory")
+	}
+
+	opts := &connectOpts{timeout: defaultWaitTimeout}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	didCommCtx, err := c.didCommContext()
+	if err != nil {
+		return nil, err
+	}
+
+	didexchangeClient, err := didexchange.New(didCommCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create did-exchange client: %w", err)
+	}
+
+	oobClient, err := outofband.New(didCommCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create out-of-band client: %w", err)
+	}
+
+	statusCh := make(chan service.StateMsg, msgEventBufferSize)
+
+	if err = didexchangeClient.RegisterMsgEvent(statusCh); err != nil {
+		return nil, fmt.Errorf("failed to register for did-exchange events: %w", err)
+	}
+
+	defer func() {
+		if e := didexchangeClient.UnregisterMsgEvent(statusCh); e != nil {
+			logger.Warnf("failed to unregister from did-exchange events: %s", e)
+		}
+	}()
+
+	// existing connection is reused without did-exchange if inviter accepts reuse of the connection.
+	reuseCh := make(chan service.StateMsg, msgEventBufferSize)
+
+	if err = oobClient.RegisterMsgEvent(reuseCh); err != nil {
+		return nil, fmt.Errorf("failed to register for out-of-band events: %w", err)
+	}
+
+	defer func() {
+		if e := oobClient.UnregisterMsgEvent(reuseCh); e != nil {
+			logger.Warnf("failed to unregister from out-of-band events: %s", e)
+		}
+	}()
+
+	var oobOpts []outofband.MessageOption
+
+	if len(opts.routerConnections) > 0 {
+		oobOpts = append(oobOpts, outofband.WithRouterConnections(opts.routerConnections...))
+	}
+
+	if opts.reuseDID != "" {
+		oobOpts = append(oobOpts, outofband.ReuseConnection(opts.reuseDID))
+	} else if opts.reuseAnyConnection {
+		oobOpts = append(oobOpts, outofband.ReuseAnyConnection())
+	}
+
+	connID, err := oobClient.AcceptInvitation(invitation, opts.myLabel, oobOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept out-of-band invitation: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	if err = waitForConnect(ctx, statusCh, reuseCh, connID); err != nil {
+		return nil, fmt.Errorf("wallet connect failed: %w", err)
+	}
+
+	record, err := didexchangeClient.GetConnection(connID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection record: %w", err)
+	}
+
+	content, err := json.Marshal(&connectionContent{
+		Context:      []string{walletContext},
+		ID:           record.ConnectionID,
+		Type:         connectionContentType,
+		Name:         record.TheirLabel,
+		MyDID:        record.MyDID,
+		TheirDID:     record.TheirDID,
+		InvitationID: record.InvitationID,
+		ThreadID:     record.ThreadID,
+		State:        record.State,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal connection content: %w", err)
+	}
+
+	if err = c.contents.Save(authToken, Connection, content); err != nil {
+		return nil, fmt.Errorf("failed to save connection to wallet: %w", err)
+	}
+
+	return record, nil
+}
+
+// queryRequestedPresentations runs presentation exchange query for each presentation definition
+// attached to the request presentation and returns matching presentations.
+func (c *Wallet) queryRequestedPresentations(request *presentproof.RequestPresentation) ([]*verifiable.Presentation,
+	error) {
+	var definitions []json.RawMessage
+
+	for _, format := range request.Formats {
+		if format.Format != peDefinitionFormat {
+			continue
+		}
+
+		for i := range request.RequestPresentationsAttach {
+			if request.RequestPresentationsAttach[i].ID != format.AttachID {
+				continue
+			}
+
+			src, err := request.RequestPresentationsAttach[i].Data.Fetch()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read presentation definition attachment: %w", err)
+			}
+
+			payload := &presentationExchangePayload{}
+
+			if err = json.Unmarshal(src, payload); err != nil {
+				return nil, fmt.Errorf("failed to read presentation definition: %w", err)
+			}
+
+			if len(payload.PresentationDefinition) == 0 {
+				return nil, errors.New("presentation definition attachment is missing 'presentation_definition'")
+			}
+
+			definitions = append(definitions, payload.PresentationDefinition)
+		}
+	}
+
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := c.Query(&QueryParams{Type: PresentationExchange.Name(), Query: definitions})
+	if errors.Is(err, ErrQueryNoResultFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallet for requested presentation: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// readIssuedCredentials reads credentials attached to issue credential message.
+func (c *Wallet) readIssuedCredentials(issued *issuecredential.IssueCredential) ([]*verifiable.Credential, error) {
+	fetcher := verifiable.NewVDRKeyResolver(c.walletVDR).PublicKeyFetcher()
+
+	var credentials []*verifiable.Credential
+
+	for i := range issued.CredentialsAttach {
+		raw, err := issued.CredentialsAttach[i].Data.Fetch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read issued credential attachment: %w", err)
+		}
+
+		// credential fulfillment is the presentation containing the issued credentials.
+		if getAttachmentFormat(issued.Formats, issued.CredentialsAttach[i].ID) == cm.CredentialFulfillmentAttachmentFormat {
+			vp, e := verifiable.ParsePresentation(raw, verifiable.WithPresPublicKeyFetcher(fetcher),
+				verifiable.WithPresJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+			if e != nil {
+				return nil, fmt.Errorf("failed to parse credential fulfillment: %w", e)
+			}
+
+			vcs, e := parseCredentialsFromPresentation(vp)
+			if e != nil {
+				return nil, e
+			}
+
+			credentials = append(credentials, vcs...)
+
+			continue
+		}
+
+		vc, err := verifiable.ParseCredential(raw, verifiable.WithPublicKeyFetcher(fetcher))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse issued credential: %w", err)
+		}
+
+		credentials = append(credentials, vc)
+	}
+
+	if len(credentials) == 0 {
+		return nil, errors.New("no credentials found in issue credential message")
+	}
+
+	return credentials, nil
+}
+
+func parseCredentialsFromPresentation(vp *verifiable.Presentation) ([]*verifiable.Credential, error) {
+	marshalled, err := vp.MarshalledCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials from presentation: %w", err)
+	}
+
+	credentials := make([]*verifiable.Credential, len(marshalled))
+
+	for i, raw := range marshalled {
+		// proofs of credentials are already checked while parsing the presentation.
+		credentials[i], err = verifiable.ParseCredential(raw, verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(cm.CachingJSONLDLoader()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse credential: %w", err)
+		}
+	}
+
+	return credentials, nil
+}
+
+func getAttachmentFormat(formats []issuecredentialSvc.Format, attachID string) string {
+	for _, format := range formats {
+		if format.AttachID == attachID {
+			return format.Format
+		}
+	}
+
+	return ""
+}
+
+// waitForConnect waits for the did-exchange state of given connection to be 'completed'
+// or for the reuse of given existing connection to be accepted by the inviter.
+func waitForConnect(ctx context.Context, didStateMsgs, oobStateMsgs chan service.StateMsg, connID string) error {
+	for {
+		select {
+		case msg := <-didStateMsgs:
+			if msg.Type != service.PostState || msg.StateID != didexchangeSvc.StateIDCompleted {
+				continue
+			}
+
+			event, ok := msg.Properties.(didexchangeSvc.Event)
+			if ok && event.ConnectionID() == connID {
+				return nil
+			}
+		case msg := <-oobStateMsgs:
+			if msg.Type != service.PostState || msg.Msg == nil ||
+				msg.Msg.Type() != outofbandSvc.HandshakeReuseAcceptedMsgType {
+				continue
+			}
+
+			event, ok := msg.Properties.(connectionEvent)
+			if ok && event.ConnectionID() == connID {
+				return nil
+			}
+		case <-ctx.Done():
+			return errors.New("time out waiting for did-exchange state 'completed'")
+		}
+	}
+}
+
+// connectionEvent is the event properties of a protocol event related to a connection.
+type connectionEvent interface {
+	ConnectionID() string
+}
+
+// didCommContext returns framework context of the wallet if it supports DIDComm features.
+func (c *Wallet) didCommContext() (didCommProvider, error) {
+	ctx, ok := c.ctx.(didCommProvider)
+	if !ok {
+		return nil, errors.New("wallet provider does not support DIDComm features")
+	}
+
+	return ctx, nil
+}
+
+// actionEventClient is a DIDComm protocol client publishing action events.
+type actionEventClient interface {
+	RegisterActionEvent(ch chan<- service.DIDCommAction) error
+	UnregisterActionEvent(ch chan<- service.DIDCommAction) error
+}
+
+// registerActionEvents registers for action events of given protocol client and returns function to unregister.
+// Actions received by the wallet remain pending, so that they can be continued later by their thread ID.
+func registerActionEvents(client actionEventClient) (chan service.DIDCommAction, func(), error) {
+	actions := make(chan service.DIDCommAction, msgEventBufferSize)
+
+	if err := client.RegisterActionEvent(actions); err != nil {
+		return nil, nil, err
+	}
+
+	return actions, func() {
+		if e := client.UnregisterActionEvent(actions); e != nil {
+			logger.Warnf("failed to unregister from action events: %s", e)
+		}
+	}, nil
+}
+
+// findCredentialOffer checks if credential offer of given thread is pending.
+func findCredentialOffer(icClient *issuecredential.Client, thID string) error {
+	pending, err := icClient.Actions()
+	if err != nil {
+		return fmt.Errorf("failed to get pending actions: %w", err)
+	}
+
+	for _, action := range pending {
+		if action.PIID == thID && action.Msg.Type() == issuecredentialSvc.OfferCredentialMsgType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no pending credential offer found for thread '%s'", thID)
+}
+
+// waitForAction waits for action event of given thread and message type.
+func waitForAction(ctx context.Context, actions chan service.DIDCommAction, thID,
+	msgType string) (service.DIDCommMsgMap, error) {
+	for {
+		select {
+		case action := <-actions:
+			props, ok := action.Properties.(interface{ PIID() string })
+			if !ok || props.PIID() != thID || action.Message.Type() != msgType {
+				continue
+			}
+
+			return action.Message.Clone(), nil
+		case <-ctx.Done():
+			return nil, fmt.Errorf("time out waiting for '%s'", msgType)
+		}
+	}
+}
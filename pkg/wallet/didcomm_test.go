@@ -0,0 +1,662 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	issuecredentialsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	presentproofsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	issuecredentialmocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/issuecredential"
+	presentproofmocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/presentproof"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mockmediator "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockoutofband "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/outofband"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	sampleConnID   = "sample-conn-01"
+	sampleMyDID    = "did:example:mydid"
+	sampleTheirDID = "did:example:theirdid"
+	sampleThreadID = "sample-thread-01"
+
+	sampleIssuedVC = `{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"id": "http://example.edu/credentials/didcomm-01",
+		"type": ["VerifiableCredential"],
+		"issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+		"issuanceDate": "2010-01-01T19:23:24Z",
+		"credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"}
+	}`
+
+	samplePresentationDefinition = `{
+		"id": "c1b88ce1-8460-4baf-8f16-4759a2f055fd",
+		"input_descriptors": [{
+			"id": "type",
+			"name": "type",
+			"purpose": "We can only interact with specific status information for Verifiable Credentials",
+			"schema": [{"uri": "https://www.w3.org/2018/credentials#VerifiableCredential"}],
+			"constraints": {"fields": [{"path": ["$.credentialSubject.degree.type"], "filter": {"type": "string"}}]}
+		}]
+	}`
+)
+
+func TestWallet_Connect(t *testing.T) {
+	t.Run("test connect - success", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{}, WithMyLabel("sample-label"),
+			WithConnectTimeout(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, sampleConnID, connID)
+
+		content, err := walletInstance.Get(Connection, sampleConnID)
+		require.NoError(t, err)
+
+		var conn connectionContent
+		require.NoError(t, json.Unmarshal(content, &conn))
+		require.Equal(t, sampleMyDID, conn.MyDID)
+		require.Equal(t, sampleTheirDID, conn.TheirDID)
+		require.Equal(t, didexchange.StateIDCompleted, conn.State)
+	})
+
+	t.Run("test connect - invalid auth", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, _ := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(sampleFakeTkn, &outofband.Invitation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, connID)
+	})
+
+	t.Run("test connect - missing invitation", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, nil)
+		require.EqualError(t, err, "invitation is mandatory")
+		require.Empty(t, connID)
+	})
+
+	t.Run("test connect - accept invitation failure", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockctx.ServiceMap[outofbandsvc.Name] = &mockoutofband.MockOobService{
+			AcceptInvitationHandle: func(*outofbandsvc.Invitation, outofbandsvc.Options) (string, error) {
+				return "", errors.New("sample error")
+			},
+		}
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to accept out-of-band invitation")
+		require.Empty(t, connID)
+	})
+
+	t.Run("test connect - timeout", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockctx.ServiceMap[outofbandsvc.Name] = &mockoutofband.MockOobService{
+			AcceptInvitationHandle: func(*outofbandsvc.Invitation, outofbandsvc.Options) (string, error) {
+				return sampleConnID, nil
+			},
+		}
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{}, WithConnectTimeout(10*time.Millisecond))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "time out waiting for did-exchange state 'completed'")
+		require.Empty(t, connID)
+	})
+
+	t.Run("test connect - existing connection reused", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		oobSvc := &mockOobSvc{}
+		oobSvc.AcceptInvitationHandle = func(*outofbandsvc.Invitation, outofbandsvc.Options) (string, error) {
+			oobSvc.reuseAccepted(sampleConnID)
+
+			return sampleConnID, nil
+		}
+		mockctx.ServiceMap[outofbandsvc.Name] = oobSvc
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{}, WithConnectTimeout(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, sampleConnID, connID)
+	})
+
+	t.Run("test connect - provider without DIDComm support", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		createSampleProfile(t, mockctx)
+
+		walletInstance, err := New(sampleUserID, &basicProvider{mockctx})
+		require.NoError(t, err)
+
+		token, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer walletInstance.Close()
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{})
+		require.EqualError(t, err, "wallet provider does not support DIDComm features")
+		require.Empty(t, connID)
+	})
+
+	t.Run("test connect - missing services", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockctx.ServiceMap = map[string]interface{}{}
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		connID, err := walletInstance.Connect(token, &outofband.Invitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create did-exchange client")
+		require.Empty(t, connID)
+	})
+}
+
+func TestWallet_ProposePresentation(t *testing.T) {
+	t.Run("test propose presentation - success without presentation definition", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		expectPresentProofAction(mockPresentProof, sampleThreadID, &presentproofsvc.RequestPresentation{
+			Type:    presentproofsvc.RequestPresentationMsgType,
+			Comment: "sample request",
+		})
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{},
+			WithInitiateConnectOptions(WithConnectTimeout(time.Second)), WithInitiateTimeout(time.Second))
+		require.NoError(t, err)
+		require.NotEmpty(t, request)
+		require.Equal(t, sampleThreadID, request.ThreadID)
+		require.Equal(t, sampleConnID, request.ConnectionID)
+		require.Equal(t, "sample request", request.Request.Comment)
+		require.Empty(t, request.Candidates)
+	})
+
+	t.Run("test propose presentation - no matching credentials", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		expectPresentProofAction(mockPresentProof, sampleThreadID, samplePERequest(t, samplePresentationDefinition))
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{})
+		require.NoError(t, err)
+		require.NotEmpty(t, request)
+		require.Len(t, request.Request.RequestPresentationsAttach, 1)
+		require.Empty(t, request.Candidates)
+	})
+
+	t.Run("test propose presentation - invalid presentation definition", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		expectPresentProofAction(mockPresentProof, sampleThreadID, samplePERequest(t, ""))
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "presentation definition attachment is missing")
+		require.Empty(t, request)
+	})
+
+	t.Run("test propose presentation - send propose failure", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		mockPresentProof.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		mockPresentProof.EXPECT().UnregisterActionEvent(gomock.Any()).Return(nil)
+		mockPresentProof.EXPECT().HandleInbound(gomock.Any(), gomock.Any()).
+			Return("", errors.New("sample error"))
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to propose presentation from wallet")
+		require.Empty(t, request)
+	})
+
+	t.Run("test propose presentation - timeout waiting for request", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		// request presentation of another thread is not the one wallet is waiting for.
+		expectPresentProofAction(mockPresentProof, "other-thread", &presentproofsvc.RequestPresentation{
+			Type: presentproofsvc.RequestPresentationMsgType,
+		})
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{},
+			WithInitiateTimeout(10*time.Millisecond))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get request presentation")
+		require.Empty(t, request)
+	})
+
+	t.Run("test propose presentation - action events already registered", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		mockPresentProof.EXPECT().RegisterActionEvent(gomock.Any()).Return(service.ErrChannelRegistered)
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(token, &outofband.Invitation{})
+		require.True(t, errors.Is(err, service.ErrChannelRegistered))
+		require.Contains(t, err.Error(), "failed to register for present proof actions")
+		require.Empty(t, request)
+	})
+
+	t.Run("test propose presentation - invalid auth", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, _ := openDIDCommWallet(t, mockctx)
+
+		request, err := walletInstance.ProposePresentation(sampleFakeTkn, &outofband.Invitation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, request)
+	})
+}
+
+func TestWallet_PresentProof(t *testing.T) {
+	t.Run("test present proof - success", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		mockPresentProof.EXPECT().ActionContinue(sampleThreadID, gomock.Any()).Return(nil)
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		require.NoError(t, walletInstance.PresentProof(token, sampleThreadID, vp))
+	})
+
+	t.Run("test present proof - failure", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockPresentProof := presentproofmocks.NewMockProtocolService(gomock.NewController(t))
+		mockPresentProof.EXPECT().ActionContinue(sampleThreadID, gomock.Any()).Return(errors.New("sample error"))
+		mockctx.ServiceMap[presentproofsvc.Name] = mockPresentProof
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		vp, err := verifiable.NewPresentation()
+		require.NoError(t, err)
+
+		err = walletInstance.PresentProof(token, sampleThreadID, vp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to present proof from wallet")
+	})
+
+	t.Run("test present proof - missing presentation", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		require.EqualError(t, walletInstance.PresentProof(token, sampleThreadID, nil), "presentation is mandatory")
+	})
+
+	t.Run("test present proof - invalid auth", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, _ := openDIDCommWallet(t, mockctx)
+
+		err := walletInstance.PresentProof(sampleFakeTkn, sampleThreadID, &verifiable.Presentation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+}
+
+func TestWallet_AcceptCredentialOffer(t *testing.T) {
+	offer := issuecredentialsvc.Action{
+		PIID: sampleThreadID,
+		Msg: service.NewDIDCommMsgMap(&issuecredentialsvc.OfferCredential{
+			Type: issuecredentialsvc.OfferCredentialMsgType,
+		}),
+	}
+
+	issued := issuecredentialsvc.Action{
+		PIID: sampleThreadID,
+		Msg: service.NewDIDCommMsgMap(&issuecredentialsvc.IssueCredential{
+			Type: issuecredentialsvc.IssueCredentialMsgType,
+			CredentialsAttach: []decorator.Attachment{
+				{ID: uuid.New().String(), Data: decorator.AttachmentData{JSON: json.RawMessage(sampleIssuedVC)}},
+			},
+		}),
+	}
+
+	t.Run("test accept credential offer - success", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockIssueCredential := issuecredentialmocks.NewMockProtocolService(gomock.NewController(t))
+		expectIssueCredentialAction(mockIssueCredential, offer, issued)
+		mockIssueCredential.EXPECT().ActionContinue(sampleThreadID, gomock.Any()).Return(nil)
+		mockctx.ServiceMap[issuecredentialsvc.Name] = mockIssueCredential
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		require.NoError(t, walletInstance.Add(token, Collection,
+			[]byte(`{"id": "urn:uuid:collection-didcomm", "type": "Collection"}`)))
+
+		credentials, err := walletInstance.AcceptCredentialOffer(token, sampleThreadID,
+			WithSaveToCollection("urn:uuid:collection-didcomm"), WithConcludeTimeout(time.Second))
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		require.Equal(t, "http://example.edu/credentials/didcomm-01", credentials[0].ID)
+
+		saved, err := walletInstance.GetAll(Credential, WithCollection("urn:uuid:collection-didcomm"))
+		require.NoError(t, err)
+		require.Len(t, saved, 1)
+	})
+
+	t.Run("test accept credential offer - no pending offer", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockIssueCredential := issuecredentialmocks.NewMockProtocolService(gomock.NewController(t))
+		mockIssueCredential.EXPECT().Actions().Return(nil, nil)
+		mockctx.ServiceMap[issuecredentialsvc.Name] = mockIssueCredential
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		credentials, err := walletInstance.AcceptCredentialOffer(token, sampleThreadID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find credential offer")
+		require.Empty(t, credentials)
+	})
+
+	t.Run("test accept credential offer - accept offer failure", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		mockIssueCredential := issuecredentialmocks.NewMockProtocolService(gomock.NewController(t))
+		mockIssueCredential.EXPECT().Actions().Return([]issuecredentialsvc.Action{offer}, nil)
+		mockIssueCredential.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
+		mockIssueCredential.EXPECT().UnregisterActionEvent(gomock.Any()).Return(nil)
+		mockIssueCredential.EXPECT().ActionContinue(sampleThreadID, gomock.Any()).Return(errors.New("sample error"))
+		mockctx.ServiceMap[issuecredentialsvc.Name] = mockIssueCredential
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		credentials, err := walletInstance.AcceptCredentialOffer(token, sampleThreadID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to accept credential offer")
+		require.Empty(t, credentials)
+	})
+
+	t.Run("test accept credential offer - invalid credential issued", func(t *testing.T) {
+		invalid := issuecredentialsvc.Action{
+			PIID: sampleThreadID,
+			Msg: service.NewDIDCommMsgMap(&issuecredentialsvc.IssueCredential{
+				Type: issuecredentialsvc.IssueCredentialMsgType,
+				CredentialsAttach: []decorator.Attachment{
+					{ID: uuid.New().String(), Data: decorator.AttachmentData{JSON: map[string]interface{}{}}},
+				},
+			}),
+		}
+
+		mockctx := newDIDCommMockProvider(t)
+		mockIssueCredential := issuecredentialmocks.NewMockProtocolService(gomock.NewController(t))
+		expectIssueCredentialAction(mockIssueCredential, offer, invalid)
+		mockctx.ServiceMap[issuecredentialsvc.Name] = mockIssueCredential
+
+		walletInstance, token := openDIDCommWallet(t, mockctx)
+
+		credentials, err := walletInstance.AcceptCredentialOffer(token, sampleThreadID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse issued credential")
+		require.Empty(t, credentials)
+	})
+
+	t.Run("test accept credential offer - invalid auth", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, _ := openDIDCommWallet(t, mockctx)
+
+		credentials, err := walletInstance.AcceptCredentialOffer(sampleFakeTkn, sampleThreadID)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, credentials)
+	})
+
+	t.Run("test accept credential offer - token of another wallet user", func(t *testing.T) {
+		mockctx := newDIDCommMockProvider(t)
+		walletInstance, _ := openDIDCommWallet(t, mockctx)
+
+		defer walletInstance.Close()
+
+		const otherUserID = "sample-user02"
+
+		require.NoError(t, CreateProfile(otherUserID, mockctx, WithPassphrase(samplePassPhrase)))
+
+		otherWallet, err := New(otherUserID, mockctx)
+		require.NoError(t, err)
+
+		otherToken, err := otherWallet.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer otherWallet.Close()
+
+		credentials, err := walletInstance.AcceptCredentialOffer(otherToken, sampleThreadID)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, credentials)
+
+		err = walletInstance.PresentProof(otherToken, sampleThreadID, &verifiable.Presentation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		connID, err := walletInstance.Connect(otherToken, &outofband.Invitation{})
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, connID)
+	})
+}
+
+// actionEventProps is the event properties of a protocol action.
+type actionEventProps struct {
+	piid string
+}
+
+func (p *actionEventProps) PIID() string {
+	return p.piid
+}
+
+func (p *actionEventProps) All() map[string]interface{} {
+	return map[string]interface{}{"piid": p.piid}
+}
+
+// expectPresentProofAction makes present proof service mock publish request presentation action event
+// of given thread once proposal is sent.
+func expectPresentProofAction(mockPresentProof *presentproofmocks.MockProtocolService, piid string,
+	request interface{}) {
+	var actions chan<- service.DIDCommAction
+
+	mockPresentProof.EXPECT().RegisterActionEvent(gomock.Any()).DoAndReturn(
+		func(ch chan<- service.DIDCommAction) error {
+			actions = ch
+
+			return nil
+		})
+	mockPresentProof.EXPECT().UnregisterActionEvent(gomock.Any()).Return(nil)
+	mockPresentProof.EXPECT().HandleInbound(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(service.DIDCommMsg, service.DIDCommContext) (string, error) {
+			actions <- service.DIDCommAction{
+				Message:    service.NewDIDCommMsgMap(request),
+				Properties: &actionEventProps{piid: piid},
+			}
+
+			return sampleThreadID, nil
+		})
+}
+
+// expectIssueCredentialAction makes issue credential service mock find given pending offer and publish
+// given issued credential action event once offer is accepted.
+func expectIssueCredentialAction(mockIssueCredential *issuecredentialmocks.MockProtocolService,
+	offer, issued issuecredentialsvc.Action) {
+	var actions chan<- service.DIDCommAction
+
+	gomock.InOrder(
+		mockIssueCredential.EXPECT().Actions().Return([]issuecredentialsvc.Action{offer}, nil),
+		mockIssueCredential.EXPECT().RegisterActionEvent(gomock.Any()).DoAndReturn(
+			func(ch chan<- service.DIDCommAction) error {
+				actions = ch
+
+				return nil
+			}),
+		mockIssueCredential.EXPECT().ActionContinue(sampleThreadID, gomock.Any()).DoAndReturn(
+			func(string, issuecredentialsvc.Opt) error {
+				actions <- service.DIDCommAction{Message: issued.Msg, Properties: &actionEventProps{piid: issued.PIID}}
+
+				return nil
+			}),
+	)
+	mockIssueCredential.EXPECT().UnregisterActionEvent(gomock.Any()).Return(nil)
+}
+
+// mockDIDExchangeSvc is did-exchange service mock which completes connection as soon as invitation is accepted.
+type mockDIDExchangeSvc struct {
+	mockdidexchange.MockDIDExchangeSvc
+	msgCh chan<- service.StateMsg
+}
+
+func (m *mockDIDExchangeSvc) RegisterMsgEvent(ch chan<- service.StateMsg) error {
+	m.msgCh = ch
+
+	return nil
+}
+
+func (m *mockDIDExchangeSvc) complete(connID string) {
+	m.msgCh <- service.StateMsg{
+		Type:       service.PostState,
+		StateID:    didexchange.StateIDCompleted,
+		Properties: &mockdidexchange.MockEventProperties{ConnID: connID},
+	}
+}
+
+// mockOobSvc is out-of-band service mock which can notify acceptance of a reused connection.
+type mockOobSvc struct {
+	mockoutofband.MockOobService
+	msgCh chan<- service.StateMsg
+}
+
+func (m *mockOobSvc) RegisterMsgEvent(ch chan<- service.StateMsg) error {
+	m.msgCh = ch
+
+	return nil
+}
+
+func (m *mockOobSvc) reuseAccepted(connID string) {
+	m.msgCh <- service.StateMsg{
+		Type:    service.PostState,
+		StateID: "done",
+		Msg: service.NewDIDCommMsgMap(&outofbandsvc.HandshakeReuseAccepted{
+			Type: outofbandsvc.HandshakeReuseAcceptedMsgType,
+		}),
+		Properties: &mockdidexchange.MockEventProperties{ConnID: connID},
+	}
+}
+
+// basicProvider is wallet provider without DIDComm features.
+type basicProvider struct {
+	p *mockprovider.Provider
+}
+
+func (b *basicProvider) StorageProvider() storage.Provider {
+	return b.p.StorageProvider()
+}
+
+func (b *basicProvider) VDRegistry() vdrapi.Registry {
+	return b.p.VDRegistry()
+}
+
+func (b *basicProvider) Crypto() crypto.Crypto {
+	return b.p.Crypto()
+}
+
+func newDIDCommMockProvider(t *testing.T) *mockprovider.Provider {
+	t.Helper()
+
+	mockctx := newMockProvider()
+	mockctx.ProtocolStateStorageProviderValue = mockstorage.NewMockStoreProvider()
+
+	recorder, err := connection.NewRecorder(mockctx)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: sampleConnID,
+		State:        didexchange.StateIDCompleted,
+		MyDID:        sampleMyDID,
+		TheirDID:     sampleTheirDID,
+		TheirLabel:   "sample-verifier",
+	}))
+
+	didexchangeSvc := &mockDIDExchangeSvc{}
+
+	mockctx.ServiceMap = map[string]interface{}{
+		didexchange.DIDExchange: didexchangeSvc,
+		mediator.Coordination:   &mockmediator.MockMediatorSvc{},
+		outofbandsvc.Name: &mockoutofband.MockOobService{
+			AcceptInvitationHandle: func(*outofbandsvc.Invitation, outofbandsvc.Options) (string, error) {
+				didexchangeSvc.complete(sampleConnID)
+
+				return sampleConnID, nil
+			},
+		},
+	}
+
+	return mockctx
+}
+
+func openDIDCommWallet(t *testing.T, mockctx *mockprovider.Provider) (*Wallet, string) {
+	t.Helper()
+
+	createSampleProfile(t, mockctx)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NoError(t, err)
+
+	token, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { walletInstance.Close() })
+
+	return walletInstance, token
+}
+
+func samplePERequest(t *testing.T, definition string) *presentproofsvc.RequestPresentation {
+	t.Helper()
+
+	payload := map[string]interface{}{"challenge": uuid.New().String()}
+	if definition != "" {
+		payload["presentation_definition"] = json.RawMessage(definition)
+	}
+
+	attachID := uuid.New().String()
+
+	return &presentproofsvc.RequestPresentation{
+		Type:    presentproofsvc.RequestPresentationMsgType,
+		Formats: []presentproofsvc.Format{{AttachID: attachID, Format: peDefinitionFormat}},
+		RequestPresentationsAttach: []decorator.Attachment{
+			{ID: attachID, MimeType: mimeTypeApplicationLdJSON, Data: decorator.AttachmentData{JSON: payload}},
+		},
+	}
+}
@@ -0,0 +1,649 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/uuid"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// encrypted wallet data model constants.
+const (
+	credentialsContext = "https://www.w3.org/2018/credentials/v1"
+	walletContext      = "https://w3id.org/wallet/v1"
+
+	verifiableCredentialType = "VerifiableCredential"
+	encryptedWalletType      = "EncryptedWallet"
+	universalWalletType      = "UniversalWallet2020"
+)
+
+// encrypted wallet JWE constants.
+const (
+	// lockAlgPassphrase is JWE 'alg' of content encryption key wrapped by key derived from passphrase
+	// (RFC 7518, section 4.8), salt and iteration count are found in 'p2s' and 'p2c' headers.
+	lockAlgPassphrase = "PBES2-HS512+A256KW"
+	// lockAlgSecretLock is JWE 'alg' of content encryption key wrapped by secret lock service.
+	lockAlgSecretLock = "SecretLock"
+	// encAlg is JWE 'enc' of exported wallet contents.
+	encAlg = "A256GCM"
+
+	exportKeyURI = localKeyURIPrefix + "wallet-export"
+
+	cekSize  = 32
+	saltSize = 32
+
+	// PBKDF2 iteration count used for locking wallet contents by passphrase and the bounds of iteration count
+	// accepted while unlocking them.
+	pbes2Count    = 210000
+	minPBES2Count = 1000
+	maxPBES2Count = 10000000
+
+	pbes2SaltHeader  = "p2s"
+	pbes2CountHeader = "p2c"
+
+	// store key of key encryption key ID used to encrypt key contents to be exported.
+	keyBackupKEKStoreKey = "wallet_key_backup_kek"
+	// suffix of the name of the store keeping encrypted key backups of a wallet profile.
+	keyBackupStoreSuffix = "_keybackup"
+)
+
+// unlockedWallet is unlocked universal wallet data model which gets encrypted while exporting wallet.
+// https://w3c-ccg.github.io/universal-wallet-interop-spec/#unlocked-wallet
+type unlockedWallet struct {
+	Context  []string         `json:"@context"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Contents []*walletContent `json:"contents"`
+}
+
+// walletContent is single wallet content along with its content type and collections it belongs to.
+type walletContent struct {
+	ContentType ContentType     `json:"contentType"`
+	Content     json.RawMessage `json:"content"`
+	Collections []string        `json:"collections,omitempty"`
+}
+
+// encryptedWallet is locked universal wallet data model returned by wallet export.
+// https://w3c-ccg.github.io/universal-wallet-interop-spec/#locked-wallet
+type encryptedWallet struct {
+	Context           []string                `json:"@context"`
+	ID                string                  `json:"id"`
+	Type              []string                `json:"type"`
+	IssuanceDate      string                  `json:"issuanceDate,omitempty"`
+	CredentialSubject encryptedWalletContents `json:"credentialSubject"`
+}
+
+type encryptedWalletContents struct {
+	ID                      string          `json:"id"`
+	EncryptedWalletContents json.RawMessage `json:"encryptedWalletContents"`
+}
+
+// keyBackupContent is key content encrypted by key encryption key of the wallet.
+type keyBackupContent struct {
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// exportContents exports all wallet contents as encrypted wallet.
+func (c *Wallet) exportContents(auth string, opts *exportOpts) (json.RawMessage, error) {
+	// exported wallet contains all wallet contents including keys, so wallet has to be unlocked
+	// with admin scope even if there are no keys to export.
+	keyManager, err := keyManager().authorizeUser(auth, c.userID, AdminScope)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := &unlockedWallet{
+		Context: []string{walletContext},
+		ID:      "urn:uuid:" + uuid.New().String(),
+		Type:    universalWalletType,
+	}
+
+	for _, ct := range []ContentType{Collection, Credential, DIDResolutionResponse, Metadata, Connection} {
+		contents, err := c.contents.GetAll(ct)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wallet contents of type '%s': %w", ct, err)
+		}
+
+		for key, content := range contents {
+			collections, err := c.contents.Collections(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read collections of wallet content: %w", err)
+			}
+
+			unlocked.Contents = append(unlocked.Contents, &walletContent{
+				ContentType: ct, Content: content, Collections: collections,
+			})
+		}
+	}
+
+	keys, err := c.readKeyBackups(keyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		unlocked.Contents = append(unlocked.Contents, &walletContent{ContentType: Key, Content: key})
+	}
+
+	plaintext, err := json.Marshal(unlocked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal wallet contents: %w", err)
+	}
+
+	jwe, err := lockContents(plaintext, &opts.lockOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock wallet contents: %w", err)
+	}
+
+	return json.Marshal(&encryptedWallet{
+		Context:      []string{credentialsContext, walletContext},
+		ID:           "urn:uuid:" + uuid.New().String(),
+		Type:         []string{verifiableCredentialType, encryptedWalletType},
+		IssuanceDate: time.Now().UTC().Format(time.RFC3339),
+		CredentialSubject: encryptedWalletContents{
+			ID:                      unlocked.ID,
+			EncryptedWalletContents: json.RawMessage(jwe),
+		},
+	})
+}
+
+// importContents imports all contents of given encrypted wallet.
+func (c *Wallet) importContents(auth string, raw json.RawMessage, opts *importOpts) error {
+	// imported wallet may contain keys and may replace existing contents, so wallet has to be unlocked
+	// by its own user with admin scope.
+	if _, err := keyManager().authorizeUser(auth, c.userID, AdminScope); err != nil {
+		return err
+	}
+
+	var locked encryptedWallet
+
+	if err := json.Unmarshal(raw, &locked); err != nil {
+		return fmt.Errorf("failed to read encrypted wallet: %w", err)
+	}
+
+	if !containsType(locked.Type, encryptedWalletType) {
+		return fmt.Errorf("invalid encrypted wallet, type '%s' is required", encryptedWalletType)
+	}
+
+	plaintext, err := unlockContents(string(locked.CredentialSubject.EncryptedWalletContents), &opts.lockOpts)
+	if err != nil {
+		return fmt.Errorf("failed to unlock wallet contents: %w", err)
+	}
+
+	var unlocked unlockedWallet
+
+	if err = json.Unmarshal(plaintext, &unlocked); err != nil {
+		return fmt.Errorf("failed to read wallet contents: %w", err)
+	}
+
+	conflicts, err := c.validateImport(unlocked.Contents, opts)
+	if err != nil {
+		return err
+	}
+
+	for i, content := range unlocked.Contents {
+		switch {
+		case !conflicts[i]:
+			err = c.Add(auth, content.ContentType, content.Content, AddByCollection(content.Collections...))
+		case opts.conflict == ImportConflictSkip || content.ContentType == Key:
+			continue
+		default:
+			err = c.replaceContent(auth, content)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to import wallet content of type '%s': %w", content.ContentType, err)
+		}
+	}
+
+	return nil
+}
+
+// validateImport validates contents to be imported and returns which of them already exist in this wallet,
+// contents are validated first so that nothing gets imported when import has to fail.
+func (c *Wallet) validateImport(contents []*walletContent, opts *importOpts) ([]bool, error) {
+	conflicts := make([]bool, len(contents))
+	collections := make(map[string]bool)
+
+	for _, content := range contents {
+		if content.ContentType == Collection {
+			id, err := getContentID(content.Content)
+			if err != nil {
+				return nil, err
+			}
+
+			collections[id] = true
+		}
+	}
+
+	for i, content := range contents {
+		err := content.ContentType.IsValid()
+		if err != nil {
+			return nil, err
+		}
+
+		if content.ContentType == Key && len(content.Collections) > 0 {
+			return nil, errors.New("keys can not be added to collections")
+		}
+
+		for _, collectionID := range content.Collections {
+			if collections[collectionID] {
+				continue
+			}
+
+			if _, err = c.contents.store.Get(getContentKeyPrefix(Collection, collectionID)); err != nil {
+				return nil, fmt.Errorf("failed to find collection with ID '%s' : %w", collectionID, err)
+			}
+		}
+
+		conflicts[i], err = c.containsContent(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wallet content of type '%s': %w", content.ContentType, err)
+		}
+
+		if conflicts[i] && opts.conflict == ImportConflictFail {
+			return nil, fmt.Errorf("content of type '%s' already exists in this wallet", content.ContentType)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// replaceContent replaces existing wallet content by given content, existing content is restored
+// if given content can not be added.
+func (c *Wallet) replaceContent(auth string, content *walletContent) error {
+	key, err := getContentKey(content.ContentType, content.Content)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.contents.store.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to read wallet content to be replaced: %w", err)
+	}
+
+	tags, err := c.contents.store.GetTags(key)
+	if err != nil {
+		return fmt.Errorf("failed to read wallet content to be replaced: %w", err)
+	}
+
+	if err = c.contents.store.Delete(key); err != nil {
+		return fmt.Errorf("failed to replace wallet content: %w", err)
+	}
+
+	err = c.Add(auth, content.ContentType, content.Content, AddByCollection(content.Collections...))
+	if err != nil {
+		if e := c.contents.store.Put(key, existing, tags...); e != nil {
+			return fmt.Errorf("%w, failed to restore replaced wallet content: %v", err, e)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Wallet) containsContent(content *walletContent) (bool, error) {
+	if content.ContentType != Key {
+		return c.contents.Contains(content.ContentType, content.Content)
+	}
+
+	id, err := getContentID(content.Content)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.keyBackups.Get(id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// newKeyBackupStore opens store of encrypted key backups of given profile.
+func newKeyBackupStore(p storage.Provider, pr *profile) (storage.Store, error) {
+	name := pr.ID + keyBackupStoreSuffix
+
+	store, err := p.OpenStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.SetStoreConfig(name, storage.StoreConfiguration{TagNames: []string{Key.Name()}}); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// backupKey saves key content encrypted by key encryption key of the wallet key manager, so that key can be exported.
+// Key backups are kept in a store of their own and never in wallet contents store.
+func (c *Wallet) backupKey(auth string, content []byte) error {
+	keyManager, err := keyManager().authorizeUser(auth, c.userID, AdminScope)
+	if err != nil {
+		return err
+	}
+
+	kh, err := c.keyBackupKEK(keyManager, true)
+	if err != nil {
+		return err
+	}
+
+	cipherText, nonce, err := c.walletCrypto.Encrypt(content, []byte(c.profile.ID), kh)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key content: %w", err)
+	}
+
+	backup, err := json.Marshal(&keyBackupContent{Nonce: nonce, Ciphertext: cipherText})
+	if err != nil {
+		return fmt.Errorf("failed to marshal key content: %w", err)
+	}
+
+	id, err := getContentID(content)
+	if err != nil {
+		return err
+	}
+
+	return c.keyBackups.Put(id, backup, storage.Tag{Name: Key.Name()})
+}
+
+// readKeyBackups returns all decrypted key contents of the wallet.
+func (c *Wallet) readKeyBackups(keyManager kms.KeyManager) ([]json.RawMessage, error) {
+	iter, err := c.keyBackups.Query(Key.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet keys: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	var backups [][]byte
+
+	for {
+		ok, e := iter.Next()
+		if e != nil {
+			return nil, fmt.Errorf("failed to read wallet keys: %w", e)
+		}
+
+		if !ok {
+			break
+		}
+
+		raw, e := iter.Value()
+		if e != nil {
+			return nil, fmt.Errorf("failed to read wallet keys: %w", e)
+		}
+
+		backups = append(backups, raw)
+	}
+
+	if len(backups) == 0 {
+		return nil, nil
+	}
+
+	kh, err := c.keyBackupKEK(keyManager, false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]json.RawMessage, 0, len(backups))
+
+	for _, raw := range backups {
+		var backup keyBackupContent
+
+		if err = json.Unmarshal(raw, &backup); err != nil {
+			return nil, fmt.Errorf("failed to read key content: %w", err)
+		}
+
+		key, err := c.walletCrypto.Decrypt(backup.Ciphertext, []byte(c.profile.ID), backup.Nonce, kh)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt key content: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// keyBackupKEK returns key handle of key encryption key used for key contents to be exported,
+// creates one if not found and 'create' is true.
+func (c *Wallet) keyBackupKEK(keyManager kms.KeyManager, create bool) (interface{}, error) {
+	kid, err := c.keyBackups.Get(keyBackupKEKStoreKey)
+	if err == nil {
+		kh, e := keyManager.Get(string(kid))
+		if e != nil {
+			return nil, fmt.Errorf("failed to get key encryption key: %w", e)
+		}
+
+		return kh, nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) || !create {
+		return nil, fmt.Errorf("failed to get key encryption key ID: %w", err)
+	}
+
+	keyID, kh, err := keyManager.Create(kms.AES256GCMType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key encryption key: %w", err)
+	}
+
+	if err = c.keyBackups.Put(keyBackupKEKStoreKey, []byte(keyID)); err != nil {
+		return nil, fmt.Errorf("failed to save key encryption key ID: %w", err)
+	}
+
+	return kh, nil
+}
+
+// lockContents encrypts given contents into JWE (JSON serialization) by using random content encryption key
+// wrapped by lock from given options.
+func lockContents(plaintext []byte, opts *lockOpts) (string, error) {
+	headers := jose.Headers{
+		jose.HeaderEncryption:  encAlg,
+		jose.HeaderContentType: "application/json",
+		jose.HeaderKeyID:       exportKeyURI,
+	}
+
+	cek := random.GetRandomBytes(cekSize)
+
+	var encryptedKey string
+
+	switch {
+	case opts.passphrase != "":
+		salt := random.GetRandomBytes(saltSize)
+
+		headers[jose.HeaderAlgorithm] = lockAlgPassphrase
+		headers[pbes2SaltHeader] = base64.RawURLEncoding.EncodeToString(salt)
+		headers[pbes2CountHeader] = pbes2Count
+
+		wrapped, err := pbes2Wrap(opts.passphrase, salt, pbes2Count, cek)
+		if err != nil {
+			return "", fmt.Errorf("failed to wrap content encryption key: %w", err)
+		}
+
+		encryptedKey = string(wrapped)
+	case opts.secretLockSvc != nil:
+		headers[jose.HeaderAlgorithm] = lockAlgSecretLock
+
+		wrapped, err := opts.secretLockSvc.Encrypt(exportKeyURI, &secretlock.EncryptRequest{Plaintext: string(cek)})
+		if err != nil {
+			return "", fmt.Errorf("failed to wrap content encryption key: %w", err)
+		}
+
+		encryptedKey = wrapped.Ciphertext
+	default:
+		return "", errors.New("passphrase or secret lock service is required to lock wallet contents")
+	}
+
+	protectedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+
+	iv := random.GetRandomBytes(uint32(gcm.NonceSize()))
+	aad := base64.RawURLEncoding.EncodeToString(protectedHeaders)
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(aad))
+	tagOffset := len(sealed) - gcm.Overhead()
+
+	jwe := &jose.JSONWebEncryption{
+		ProtectedHeaders: headers,
+		Recipients:       []*jose.Recipient{{EncryptedKey: encryptedKey}},
+		IV:               string(iv),
+		Ciphertext:       string(sealed[:tagOffset]),
+		Tag:              string(sealed[tagOffset:]),
+	}
+
+	return jwe.FullSerialize(json.Marshal)
+}
+
+// unlockContents decrypts contents of JWE created by 'lockContents()'.
+func unlockContents(serialized string, opts *lockOpts) ([]byte, error) {
+	jwe, err := jose.Deserialize(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted wallet contents: %w", err)
+	}
+
+	if len(jwe.Recipients) != 1 {
+		return nil, errors.New("encrypted wallet contents must have exactly one recipient")
+	}
+
+	if enc, _ := jwe.ProtectedHeaders.Encryption(); enc != encAlg { //nolint: errcheck
+		return nil, fmt.Errorf("unsupported content encryption '%s'", enc)
+	}
+
+	var cek []byte
+
+	alg, _ := jwe.ProtectedHeaders.Algorithm()
+
+	switch {
+	case alg == lockAlgPassphrase && opts.passphrase != "":
+		salt, count, e := pbes2Params(jwe.ProtectedHeaders)
+		if e != nil {
+			return nil, e
+		}
+
+		cek, err = pbes2Unwrap(opts.passphrase, salt, count, []byte(jwe.Recipients[0].EncryptedKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap content encryption key: %w", err)
+		}
+	case alg == lockAlgSecretLock && opts.secretLockSvc != nil:
+		unwrapped, e := opts.secretLockSvc.Decrypt(exportKeyURI,
+			&secretlock.DecryptRequest{Ciphertext: jwe.Recipients[0].EncryptedKey})
+		if e != nil {
+			return nil, fmt.Errorf("failed to unwrap content encryption key: %w", e)
+		}
+
+		cek = []byte(unwrapped.Plaintext)
+	default:
+		return nil, fmt.Errorf("missing or unsupported lock option for key wrapping algorithm '%s'", alg)
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jwe.IV) != gcm.NonceSize() {
+		return nil, errors.New("invalid initialization vector")
+	}
+
+	plaintext, err := gcm.Open(nil, []byte(jwe.IV), []byte(jwe.Ciphertext+jwe.Tag), []byte(jwe.OrigProtectedHders))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt wallet contents: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// pbes2Params reads PBES2 salt input and iteration count from given JWE headers.
+func pbes2Params(headers jose.Headers) ([]byte, int, error) {
+	b64Salt, _ := headers[pbes2SaltHeader].(string) //nolint: errcheck
+
+	salt, err := base64.RawURLEncoding.DecodeString(b64Salt)
+	if err != nil || len(salt) < 8 { // nolint: gomnd // RFC 7518 requires salt input of at least 8 octets.
+		return nil, 0, errors.New("invalid PBES2 salt input")
+	}
+
+	// numbers are unmarshalled as float64 into JWE headers.
+	count, ok := headers[pbes2CountHeader].(float64)
+	if !ok || count < minPBES2Count || count > maxPBES2Count || count != float64(int(count)) {
+		return nil, 0, fmt.Errorf("invalid PBES2 count, it has to be between %d and %d", minPBES2Count, maxPBES2Count)
+	}
+
+	return salt, int(count), nil
+}
+
+// pbes2KEK derives PBES2-HS512+A256KW key encryption key from given passphrase (RFC 7518, section 4.8.1.1).
+func pbes2KEK(passphrase string, saltInput []byte, count int) (cipher.Block, error) {
+	salt := make([]byte, 0, len(lockAlgPassphrase)+1+len(saltInput))
+	salt = append(salt, lockAlgPassphrase...)
+	salt = append(salt, 0)
+	salt = append(salt, saltInput...)
+
+	return aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, count, cekSize, sha512.New))
+}
+
+func pbes2Wrap(passphrase string, saltInput []byte, count int, cek []byte) ([]byte, error) {
+	block, err := pbes2KEK(passphrase, saltInput, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return josecipher.KeyWrap(block, cek)
+}
+
+func pbes2Unwrap(passphrase string, saltInput []byte, count int, encryptedKey []byte) ([]byte, error) {
+	block, err := pbes2KEK(passphrase, saltInput, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return josecipher.KeyUnwrap(block, encryptedKey)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content encryption key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func containsType(types []string, t string) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
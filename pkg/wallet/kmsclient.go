@@ -10,17 +10,20 @@ import (
 	"bytes"
 	"crypto/ed25519"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/bluele/gcache"
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/google/uuid"
+	cryptohkdf "golang.org/x/crypto/hkdf"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
@@ -43,6 +46,12 @@ const (
 
 	// number of sections in verification method.
 	vmSectionCount = 2
+
+	// size of the master key locked by wallet profile for local kms.
+	masterKeySize = 32
+
+	// size of the salt used for deriving session lock from session token.
+	sessionSaltSize = 32
 )
 
 // supported key types for import key base58 (all constants defined in lower case).
@@ -78,18 +87,66 @@ var (
 
 func keyManager() *walletKeyManager {
 	kmsStoreOnce.Do(func() {
-		walletKMSInstance = &walletKeyManager{
-			gstore: gcache.New(0).Build(),
-		}
+		walletKMSInstance = newWalletKeyManager(newMemSessionStore())
 	})
 
 	return walletKMSInstance
 }
 
-// walletKeyManager manages key manager instances in cache.
-// underlying gcache is threasafe, no need of locks.
+func newWalletKeyManager(store SessionStore) *walletKeyManager {
+	return &walletKeyManager{
+		store:          store,
+		keyManagers:    make(map[string]kms.KeyManager),
+		secrets:        make(map[string]*keyManagerSecret),
+		storeProviders: make(map[string]storage.Provider),
+		notifiers:      make(map[string][]Notifier),
+		timers:         make(map[string]*time.Timer),
+	}
+}
+
+// walletKeyManager manages wallet unlock sessions and key manager instances of wallet users.
+// Sessions are kept in session store by digest of their tokens, whereas key managers are kept in memory.
+// Each session carries the key manager secret locked by a key derived from its token, so that key manager of the user
+// can be recreated by any wallet instance sharing the session store. Tokens themselves are never stored.
 type walletKeyManager struct {
-	gstore gcache.Cache
+	store          SessionStore
+	keyManagers    map[string]kms.KeyManager
+	secrets        map[string]*keyManagerSecret
+	storeProviders map[string]storage.Provider
+	notifiers      map[string][]Notifier
+	timers         map[string]*time.Timer
+	mu             sync.Mutex
+}
+
+// keyManagerSecret is the secret required for creating key manager of a wallet user.
+type keyManagerSecret struct {
+	// master key of local kms.
+	MasterKey []byte `json:"masterKey,omitempty"`
+
+	// key server URL and authorization token of remote kms.
+	KeyServerURL string `json:"keyServerURL,omitempty"`
+	AuthToken    string `json:"authToken,omitempty"`
+}
+
+// lockNotification is lock event to be published once session is ended.
+type lockNotification struct {
+	notifiers []Notifier
+	event     *LockEvent
+}
+
+func (k *walletKeyManager) setStore(store SessionStore) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.store = store
+}
+
+// setStoreProvider sets storage provider to be used for recreating key manager of given user from a session.
+func (k *walletKeyManager) setStoreProvider(user string, storeProvider storage.Provider) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.storeProviders[user] = storeProvider
 }
 
 func (k *walletKeyManager) createKeyManager(profileInfo *profile,
@@ -98,88 +155,457 @@ func (k *walletKeyManager) createKeyManager(profileInfo *profile,
 		return "", fmt.Errorf("invalid wallet profile")
 	}
 
-	// get user from cache if token already exists
-	token, _ := k.getKeyMangerToken(profileInfo.User) //nolint: errcheck
-	if token != "" {
-		return "", ErrAlreadyUnlocked
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// wallet is already unlocked if there are active sessions for the user.
+	sessions, err := k.activeSessions(profileInfo.User)
+	if err != nil {
+		return "", err
 	}
 
-	var err error
+	if len(sessions) > 0 && k.keyManagers[profileInfo.User] != nil {
+		return "", ErrAlreadyUnlocked
+	}
 
-	var keyManager kms.KeyManager
+	secret := &keyManagerSecret{KeyServerURL: profileInfo.KeyServerURL, AuthToken: opts.authToken}
 
-	// create key manager
+	// unlock master key of local kms.
 	if profileInfo.MasterLockCipher != "" {
-		// local kms
-		keyManager, err = createLocalKeyManager(profileInfo.User, opts.passphrase,
-			profileInfo.MasterLockCipher, opts.secretLockSvc, storeProvider)
+		secret, err = unlockMasterKey(opts.passphrase, profileInfo.MasterLockCipher, opts.secretLockSvc)
 		if err != nil {
 			return "", fmt.Errorf("failed to create local key manager: %w", err)
 		}
-	} else {
-		// remote kms
-		keyManager = createRemoteKeyManager(opts.authToken, profileInfo.KeyServerURL)
+	}
+
+	keyManager, err := secret.keyManager(profileInfo.User, storeProvider)
+	if err != nil {
+		return "", fmt.Errorf("failed to create local key manager: %w", err)
 	}
 
 	// generate token
-	token = uuid.New().String()
+	token := uuid.New().String()
+
+	// save key manager along with admin session for the user.
+	k.secrets[profileInfo.User] = secret
+	k.storeProviders[profileInfo.User] = storeProvider
 
-	// save key manager
-	err = k.saveKeyManger(profileInfo.User, token, keyManager, opts.tokenExpiry)
+	err = k.save(profileInfo.User, token, keyManager, opts.tokenExpiry)
 	if err != nil {
+		delete(k.secrets, profileInfo.User)
+
 		return "", fmt.Errorf("failed to persist local key manager: %w", err)
 	}
 
+	if opts.notifier != nil {
+		k.notifiers[profileInfo.User] = append(k.notifiers[profileInfo.User], opts.notifier)
+	}
+
 	return token, nil
 }
 
-// TODO refresh expiry on each access.
-func (k *walletKeyManager) saveKeyManger(user, key string, manager kms.KeyManager, expiration time.Duration) error {
-	if expiration == 0 {
-		expiration = defaultCacheExpiry
+func (k *walletKeyManager) saveKeyManger(user, token string, manager kms.KeyManager, expiration time.Duration) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.save(user, token, manager, expiration)
+}
+
+// save saves key manager of given user and creates admin session for given token,
+// should be called only by holding lock.
+func (k *walletKeyManager) save(user, token string, manager kms.KeyManager, expiration time.Duration) error {
+	if err := k.newSession(user, token, AdminScope, expiration); err != nil {
+		return err
 	}
 
-	err := k.gstore.SetWithExpire(user, key, expiration)
+	k.keyManagers[user] = manager
+
+	return nil
+}
+
+// getKeyManger returns key manager for given token irrespective of token scope.
+func (k *walletKeyManager) getKeyManger(token string) (kms.KeyManager, error) {
+	return k.authorize(token, ReadScope)
+}
+
+// authorize returns key manager for given token if token scope allows operations of given scope.
+func (k *walletKeyManager) authorize(token string, scope TokenScope) (kms.KeyManager, error) {
+	k.mu.Lock()
+
+	session, notification, err := k.getSession(SessionID(token))
 	if err != nil {
-		return err
+		k.mu.Unlock()
+		notification.publish()
+
+		return nil, err
+	}
+
+	keyManager, err := k.restore(session, token)
+
+	k.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if keyManager == nil {
+		return nil, ErrWalletLocked
+	}
+
+	if !session.Scope.permits(scope) {
+		return nil, fmt.Errorf("'%s' scope is required for this operation: %w", scope, ErrInsufficientScope)
 	}
 
-	return k.gstore.SetWithExpire(key, manager, expiration)
+	return keyManager, nil
 }
 
-func (k *walletKeyManager) getKeyManger(key string) (kms.KeyManager, error) {
-	val, err := k.gstore.Get(key)
+// authorizeUser is like authorize, but also checks if given token is a token of given wallet user.
+func (k *walletKeyManager) authorizeUser(token, userID string, scope TokenScope) (kms.KeyManager, error) {
+	k.mu.Lock()
+
+	session, notification, err := k.getSession(SessionID(token))
+
+	k.mu.Unlock()
+	notification.publish()
+
 	if err != nil {
 		return nil, err
 	}
 
-	return val.(kms.KeyManager), nil
+	if session.UserID != userID {
+		return nil, ErrWalletLocked
+	}
+
+	return k.authorize(token, scope)
 }
 
-func (k *walletKeyManager) getKeyMangerToken(user string) (string, error) {
-	val, err := k.gstore.Get(user)
+// getKeyManager returns key manager of unlocked wallet if given token is allowed to perform operations of given scope.
+func getKeyManager(auth string, scope TokenScope) (kms.KeyManager, error) {
+	return keyManager().authorize(auth, scope)
+}
+
+// createSession creates a new session with given scope for the user of given admin token.
+func (k *walletKeyManager) createSession(authToken string, scope TokenScope, expiry time.Duration) (string, error) {
+	if err := scope.validate(); err != nil {
+		return "", err
+	}
+
+	session, err := k.adminSession(authToken)
 	if err != nil {
 		return "", err
 	}
 
-	return val.(string), nil
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	token := uuid.New().String()
+
+	if err = k.newSession(session.UserID, token, scope, expiry); err != nil {
+		return "", fmt.Errorf("failed to persist wallet session: %w", err)
+	}
+
+	return token, nil
 }
 
+// listSessions returns all active sessions for the user of given admin token.
+func (k *walletKeyManager) listSessions(authToken string) ([]*Session, error) {
+	session, err := k.adminSession(authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	sessions, err := k.activeSessions(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// key manager secrets are never revealed.
+	for _, s := range sessions {
+		s.Secret = ""
+		s.Salt = nil
+	}
+
+	return sessions, nil
+}
+
+// revokeSession ends session of given token, any token can revoke itself whereas revoking other tokens of
+// the same user requires admin token.
+func (k *walletKeyManager) revokeSession(authToken, token string) error {
+	id := SessionID(token)
+
+	if authToken != token {
+		session, err := k.adminSession(authToken)
+		if err != nil {
+			return err
+		}
+
+		k.mu.Lock()
+
+		if target, e := k.store.Get(id); e != nil || target.UserID != session.UserID {
+			k.mu.Unlock()
+
+			return ErrSessionNotFound
+		}
+	} else {
+		k.mu.Lock()
+	}
+
+	session, notification, err := k.getSession(id)
+	if err != nil {
+		k.mu.Unlock()
+		notification.publish()
+
+		return err
+	}
+
+	notification, err = k.endSession(session, LockReasonRevoked)
+
+	k.mu.Unlock()
+	notification.publish()
+
+	return err
+}
+
+// removeKeyManager ends all sessions of given user and locks the wallet.
+// returns false if there are no active sessions for the user.
 func (k *walletKeyManager) removeKeyManager(user string) bool {
-	token, _ := k.getKeyMangerToken(user) //nolint: errcheck
-	if token != "" {
-		return k.gstore.Remove(token) && k.gstore.Remove(user)
+	k.mu.Lock()
+
+	sessions, err := k.activeSessions(user)
+	if err != nil {
+		k.mu.Unlock()
+		logger.Warnf("failed to get wallet sessions: %s", err)
+
+		return false
+	}
+
+	var notifications []*lockNotification
+
+	for _, session := range sessions {
+		notification, e := k.endSession(session, LockReasonClosed)
+		if e != nil {
+			logger.Warnf("failed to end wallet session: %s", e)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	delete(k.keyManagers, user)
+	delete(k.secrets, user)
+	delete(k.notifiers, user)
+
+	k.mu.Unlock()
+
+	for _, notification := range notifications {
+		notification.publish()
+	}
+
+	return len(sessions) > 0
+}
+
+// adminSession returns session of given token if it has admin scope.
+func (k *walletKeyManager) adminSession(token string) (*Session, error) {
+	k.mu.Lock()
+
+	session, notification, err := k.getSession(SessionID(token))
+
+	k.mu.Unlock()
+	notification.publish()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.Scope.permits(AdminScope) {
+		return nil, fmt.Errorf("'%s' scope is required for this operation: %w", AdminScope, ErrInsufficientScope)
 	}
 
-	return false
+	return session, nil
+}
+
+// expire ends session of given ID if expired, called once session expiry timer is fired.
+func (k *walletKeyManager) expire(id string) {
+	k.mu.Lock()
+
+	_, notification, err := k.getSession(id)
+
+	k.mu.Unlock()
+
+	if err != nil && !errors.Is(err, ErrWalletLocked) {
+		logger.Warnf("failed to expire wallet session: %s", err)
+	}
+
+	notification.publish()
+}
+
+// newSession creates a new session, should be called only by holding lock.
+func (k *walletKeyManager) newSession(user, token string, scope TokenScope, expiry time.Duration) error {
+	if expiry == 0 {
+		expiry = defaultCacheExpiry
+	}
+
+	session := &Session{
+		ID:        SessionID(token),
+		UserID:    user,
+		Scope:     scope,
+		ExpiresAt: time.Now().Add(expiry),
+	}
+
+	if secret, ok := k.secrets[user]; ok {
+		session.Salt = random.GetRandomBytes(sessionSaltSize)
+
+		lockedSecret, err := secret.lock(token, session.Salt)
+		if err != nil {
+			return err
+		}
+
+		session.Secret = lockedSecret
+	}
+
+	if err := k.store.Put(session); err != nil {
+		return err
+	}
+
+	id := session.ID
+	k.timers[id] = time.AfterFunc(expiry, func() { k.expire(id) })
+
+	return nil
+}
+
+// getSession returns active session of given ID and ends the session if expired,
+// should be called only by holding lock.
+func (k *walletKeyManager) getSession(id string) (*Session, *lockNotification, error) {
+	session, err := k.store.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, nil, ErrWalletLocked
+		}
+
+		return nil, nil, fmt.Errorf("failed to get wallet session: %w", err)
+	}
+
+	if time.Now().Before(session.ExpiresAt) {
+		return session, nil, nil
+	}
+
+	notification, err := k.endSession(session, LockReasonExpired)
+	if err != nil {
+		return nil, notification, err
+	}
+
+	return nil, notification, ErrWalletLocked
+}
+
+// activeSessions returns all unexpired sessions of given user, should be called only by holding lock.
+func (k *walletKeyManager) activeSessions(user string) ([]*Session, error) {
+	sessions, err := k.store.List(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet sessions: %w", err)
+	}
+
+	var active []*Session
+
+	for _, session := range sessions {
+		if time.Now().Before(session.ExpiresAt) {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// endSession removes given session and locks the wallet if there are no more active sessions for the user,
+// should be called only by holding lock.
+func (k *walletKeyManager) endSession(session *Session, reason string) (*lockNotification, error) {
+	if err := k.store.Delete(session.ID); err != nil {
+		return nil, fmt.Errorf("failed to remove wallet session: %w", err)
+	}
+
+	if timer, ok := k.timers[session.ID]; ok {
+		timer.Stop()
+		delete(k.timers, session.ID)
+	}
+
+	remaining, err := k.activeSessions(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	notification := &lockNotification{
+		notifiers: k.notifiers[session.UserID],
+		event: &LockEvent{
+			UserID:    session.UserID,
+			SessionID: session.ID,
+			Scope:     session.Scope,
+			Reason:    reason,
+			Locked:    len(remaining) == 0,
+		},
+	}
+
+	if len(remaining) == 0 {
+		delete(k.keyManagers, session.UserID)
+		delete(k.secrets, session.UserID)
+		delete(k.notifiers, session.UserID)
+	}
+
+	return notification, nil
+}
+
+// restore returns key manager of the user of given session, key manager is recreated from the secret of the session
+// unlocked by given session token if the session was created by another wallet instance sharing the session store.
+// should be called only by holding lock.
+func (k *walletKeyManager) restore(session *Session, token string) (kms.KeyManager, error) {
+	if keyManager, ok := k.keyManagers[session.UserID]; ok {
+		return keyManager, nil
+	}
+
+	storeProvider, ok := k.storeProviders[session.UserID]
+	if !ok || session.Secret == "" {
+		return nil, ErrWalletLocked
+	}
+
+	secret, err := unlockSecret(token, session.Salt, session.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock wallet session: %w", err)
+	}
+
+	keyManager, err := secret.keyManager(session.UserID, storeProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key manager from wallet session: %w", err)
+	}
+
+	k.keyManagers[session.UserID] = keyManager
+	k.secrets[session.UserID] = secret
+
+	return keyManager, nil
+}
+
+// publish publishes lock event to notifiers, should be called without holding lock.
+func (n *lockNotification) publish() {
+	if n == nil {
+		return
+	}
+
+	publishLockEvent(n.notifiers, n.event)
 }
 
 // createMasterLock creates master lock from secret lock service provided.
 func createMasterLock(secretLockSvc secretlock.Service) (string, error) {
-	masterKeyContent := random.GetRandomBytes(uint32(32)) //nolint: gomnd
+	return lockMasterKey(secretLockSvc, nil)
+}
+
+// lockMasterKey creates master lock of given master key from secret lock service provided,
+// a new master key is created if masterKey is empty.
+func lockMasterKey(secretLockSvc secretlock.Service, masterKey []byte) (string, error) {
+	if len(masterKey) == 0 {
+		masterKey = random.GetRandomBytes(uint32(masterKeySize))
+	}
 
 	masterLockEnc, err := secretLockSvc.Encrypt(localKeyURIPrefix, &secretlock.EncryptRequest{
-		Plaintext: string(masterKeyContent),
+		Plaintext: string(masterKey),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create master lock from secret lock service provided: %w", err)
@@ -201,9 +627,9 @@ func (k kmsProvider) SecretLock() secretlock.Service {
 	return k.secretLock
 }
 
-// createLocalKeyManager creates and returns local KMS instance.
-func createLocalKeyManager(user, passphrase, masterLockCipher string,
-	masterLocker secretlock.Service, storeProvider storage.Provider) (*localkms.LocalKMS, error) {
+// unlockMasterKey returns secret of local KMS by unlocking master key of given master lock cipher.
+func unlockMasterKey(passphrase, masterLockCipher string,
+	masterLocker secretlock.Service) (*keyManagerSecret, error) {
 	var err error
 	if passphrase != "" {
 		masterLocker, err = getDefaultSecretLock(passphrase)
@@ -212,7 +638,87 @@ func createLocalKeyManager(user, passphrase, masterLockCipher string,
 		}
 	}
 
-	secretLockSvc, err := local.NewService(bytes.NewBufferString(masterLockCipher), masterLocker)
+	if masterLocker == nil {
+		return nil, errors.New("passphrase or secret lock service is required to unlock wallet")
+	}
+
+	masterKey, err := masterLocker.Decrypt("", &secretlock.DecryptRequest{Ciphertext: masterLockCipher})
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyManagerSecret{MasterKey: []byte(masterKey.Plaintext)}, nil
+}
+
+// keyManager creates local KMS instance if secret has master key, or remote KMS instance otherwise.
+func (s *keyManagerSecret) keyManager(user string, storeProvider storage.Provider) (kms.KeyManager, error) {
+	if len(s.MasterKey) == 0 {
+		return createRemoteKeyManager(s.AuthToken, s.KeyServerURL), nil
+	}
+
+	return createLocalKeyManager(user, s.MasterKey, storeProvider)
+}
+
+// lock encrypts this secret with lock derived from given session token and salt.
+func (s *keyManagerSecret) lock(token string, salt []byte) (string, error) {
+	secretBytes, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal key manager secret: %w", err)
+	}
+
+	sessionLock, err := getSessionLock(token, salt)
+	if err != nil {
+		return "", err
+	}
+
+	locked, err := sessionLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(secretBytes)})
+	if err != nil {
+		return "", fmt.Errorf("failed to lock key manager secret: %w", err)
+	}
+
+	return locked.Ciphertext, nil
+}
+
+// unlockSecret decrypts key manager secret locked by given session token and salt.
+func unlockSecret(token string, salt []byte, lockedSecret string) (*keyManagerSecret, error) {
+	sessionLock, err := getSessionLock(token, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := sessionLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: lockedSecret})
+	if err != nil {
+		return nil, err
+	}
+
+	var secret keyManagerSecret
+
+	err = json.Unmarshal([]byte(unlocked.Plaintext), &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// getSessionLock returns secret lock service keyed by HKDF of given session token and salt,
+// session tokens are random values, hence not stretched like passphrases.
+func getSessionLock(token string, salt []byte) (secretlock.Service, error) {
+	sessionKey := make([]byte, sha256.Size)
+
+	_, err := io.ReadFull(cryptohkdf.New(sha256.New, []byte(token), salt, nil), sessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive session key: %w", err)
+	}
+
+	return local.NewService(bytes.NewBufferString(base64.URLEncoding.EncodeToString(sessionKey)), nil)
+}
+
+// createLocalKeyManager creates and returns local KMS instance using given master key.
+func createLocalKeyManager(user string, masterKey []byte,
+	storeProvider storage.Provider) (*localkms.LocalKMS, error) {
+	secretLockSvc, err := local.NewService(
+		bytes.NewBufferString(base64.URLEncoding.EncodeToString(masterKey)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -244,13 +750,9 @@ type kmsSigner struct {
 }
 
 func newKMSSigner(authToken string, c crypto.Crypto, opts *ProofOptions) (*kmsSigner, error) {
-	keyManager, err := keyManager().getKeyManger(authToken)
+	keyManager, err := getKeyManager(authToken, SignScope)
 	if err != nil {
-		if errors.Is(err, gcache.KeyNotFoundError) {
-			return nil, ErrWalletLocked
-		}
-
-		return nil, fmt.Errorf("failed to get key manager: %w", err)
+		return nil, err
 	}
 
 	vmSplit := strings.Split(opts.VerificationMethod, "#")
@@ -296,13 +798,9 @@ func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
 // importKeyJWK imports private key jwk found in key contents,
 // supported curve types - Ed25519, P-256, BLS12381G2.
 func importKeyJWK(auth string, key *keyContent) error {
-	keyManager, err := keyManager().getKeyManger(auth)
+	keyManager, err := getKeyManager(auth, AdminScope)
 	if err != nil {
-		if errors.Is(err, gcache.KeyNotFoundError) {
-			return ErrWalletLocked
-		}
-
-		return fmt.Errorf("failed to get key manager: %w", err)
+		return err
 	}
 
 	var jwk jose.JWK
@@ -326,13 +824,9 @@ func importKeyJWK(auth string, key *keyContent) error {
 // importKeyBase58 imports private key base58 found in key contents,
 // supported types - Ed25519Signature2018, Bls12381G1Key2020.
 func importKeyBase58(auth string, key *keyContent) error {
-	keyManager, err := keyManager().getKeyManger(auth)
+	keyManager, err := getKeyManager(auth, AdminScope)
 	if err != nil {
-		if errors.Is(err, gcache.KeyNotFoundError) {
-			return ErrWalletLocked
-		}
-
-		return fmt.Errorf("failed to get key manager: %w", err)
+		return err
 	}
 
 	switch strings.ToLower(key.KeyType) {
@@ -23,7 +23,6 @@ import (
 const (
 	samplePassPhrase    = "fakepassphrase"
 	sampleRemoteKMSAuth = "sample-auth-token"
-	keyNotFoundErr      = "Key not found."
 	sampleKeyMgrErr     = "sample-keymgr-err"
 )
 
@@ -124,7 +123,7 @@ func TestKeyManager(t *testing.T) {
 		kmgr, err := keyManager().getKeyManger(tkn)
 		require.Empty(t, kmgr)
 		require.Error(t, err)
-		require.EqualError(t, err, keyNotFoundErr)
+		require.True(t, errors.Is(err, ErrWalletLocked))
 	})
 
 	t.Run("create key manager for localkms - secret lock service missmatch", func(t *testing.T) {
@@ -155,7 +154,7 @@ func TestKeyManager(t *testing.T) {
 		kmgr, err := keyManager().getKeyManger(tkn)
 		require.Empty(t, kmgr)
 		require.Error(t, err)
-		require.EqualError(t, err, keyNotFoundErr)
+		require.True(t, errors.Is(err, ErrWalletLocked))
 	})
 
 	t.Run("create key manager for remotekms", func(t *testing.T) {
@@ -201,7 +200,7 @@ func TestKeyManager(t *testing.T) {
 		kmgr, err := keyManager().getKeyManger(tkn)
 		require.Empty(t, kmgr)
 		require.Error(t, err)
-		require.EqualError(t, err, keyNotFoundErr)
+		require.True(t, errors.Is(err, ErrWalletLocked))
 	})
 
 	t.Run("test remove key manager", func(t *testing.T) {
@@ -236,7 +235,7 @@ func TestKeyManager(t *testing.T) {
 		kmgr, err = keyManager().getKeyManger(tkn)
 		require.Empty(t, kmgr)
 		require.Error(t, err)
-		require.EqualError(t, err, keyNotFoundErr)
+		require.True(t, errors.Is(err, ErrWalletLocked))
 
 		// try again to create
 		tkn, err = keyManager().createKeyManager(profileInfo, mockstorage.NewMockStoreProvider(),
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 )
 
@@ -22,6 +23,10 @@ type QueryParams struct {
 
 	// Query can contain one or more credential queries.
 	Query []json.RawMessage `json:"credentialQuery"`
+
+	// CollectionID limits the query to credentials of given collection.
+	// Optional, by default all wallet credentials are queried.
+	CollectionID string `json:"collectionID,omitempty"`
 }
 
 // ProofOptions model
@@ -92,3 +97,20 @@ type TrustedIssuerDefinition struct {
 	Issuer   string `json:"issuer"`
 	Required bool   `json:"required"`
 }
+
+// PresentationRequest contains request presentation message received from a verifier
+// along with presentations of wallet credentials matching the presentation definition(s) requested.
+type PresentationRequest struct {
+	// ThreadID of the present proof interaction, to be used for presenting proof to the verifier.
+	ThreadID string `json:"threadID"`
+
+	// ConnectionID of the connection established with the verifier.
+	ConnectionID string `json:"connectionID"`
+
+	// Request is the request presentation message received from the verifier.
+	Request *presentproof.RequestPresentation `json:"request"`
+
+	// Candidates are presentations containing wallet credentials matching the requested presentation definitions.
+	// Empty if no presentation definition was requested or no matching credentials found in wallet.
+	Candidates []*verifiable.Presentation `json:"candidates,omitempty"`
+}
@@ -22,6 +22,9 @@ type kmsOpts struct {
 
 	// remote(web) kms options
 	keyServerURL string
+
+	// master key recovered from recovery shares, locked by local kms options instead of a new master key.
+	masterKey []byte
 }
 
 // ProfileKeyManagerOptions is option for verifiable credential wallet key manager.
@@ -49,6 +52,13 @@ func WithKeyServerURL(url string) ProfileKeyManagerOptions {
 	}
 }
 
+// withRecoveredMasterKey option to keep the master key recovered from recovery shares while updating profile.
+func withRecoveredMasterKey(masterKey []byte) ProfileKeyManagerOptions {
+	return func(opts *kmsOpts) {
+		opts.masterKey = masterKey
+	}
+}
+
 // unlockOpts contains options for unlocking VC wallet client.
 type unlockOpts struct {
 	// local kms options
@@ -60,6 +70,9 @@ type unlockOpts struct {
 
 	// expiry
 	tokenExpiry time.Duration
+
+	// notifier for wallet lock events
+	notifier Notifier
 }
 
 // UnlockOptions is option for unlocking verifiable credential wallet key manager.
@@ -100,6 +113,15 @@ func WithUnlockExpiry(tokenExpiry time.Duration) UnlockOptions {
 	}
 }
 
+// WithUnlockNotifier option for supplying notifier to which wallet lock events will be published.
+// Lock event will be published with topic 'vcwallet_lock' whenever a session of the wallet user is expired,
+// revoked or closed until the wallet gets locked.
+func WithUnlockNotifier(notifier Notifier) UnlockOptions {
+	return func(opts *unlockOpts) {
+		opts.notifier = notifier
+	}
+}
+
 // proveOpts contains options for proving credentials.
 type proveOpts struct {
 	// IDs of credentials already saved in wallet.
@@ -212,3 +234,224 @@ func FromCredential(cred *verifiable.Credential) CredentialToDerive {
 		opts.credential = cred
 	}
 }
+
+// lockOpts contains options for locking wallet contents being exported or imported.
+type lockOpts struct {
+	passphrase    string
+	secretLockSvc secretlock.Service
+}
+
+// exportOpts contains options for exporting wallet contents.
+type exportOpts struct {
+	lockOpts
+}
+
+// ExportOptions is option for exporting wallet contents as an encrypted wallet.
+type ExportOptions func(opts *exportOpts)
+
+// WithExportPassphrase option for supplying passphrase from which key to lock exported wallet will be derived.
+// This option takes precedence when provided along with other options.
+func WithExportPassphrase(passphrase string) ExportOptions {
+	return func(opts *exportOpts) {
+		opts.passphrase = passphrase
+	}
+}
+
+// WithExportSecretLockService option for supplying secret lock service to lock exported wallet.
+// This option will be ignored when supplied with 'WithExportPassphrase' option.
+func WithExportSecretLockService(svc secretlock.Service) ExportOptions {
+	return func(opts *exportOpts) {
+		opts.secretLockSvc = svc
+	}
+}
+
+// ImportConflict decides how contents already present in wallet are handled during import.
+type ImportConflict int
+
+const (
+	// ImportConflictFail fails import without importing any content if any of the contents already exists in wallet.
+	ImportConflictFail ImportConflict = iota
+	// ImportConflictSkip keeps contents already present in wallet and imports only new contents.
+	ImportConflictSkip
+	// ImportConflictReplace replaces contents already present in wallet with imported contents.
+	// Keys already present in wallet are never replaced.
+	ImportConflictReplace
+)
+
+// importOpts contains options for importing wallet contents.
+type importOpts struct {
+	lockOpts
+	conflict ImportConflict
+}
+
+// ImportOptions is option for importing encrypted wallet contents.
+type ImportOptions func(opts *importOpts)
+
+// WithImportPassphrase option for supplying passphrase used while exporting wallet.
+// This option takes precedence when provided along with other options.
+func WithImportPassphrase(passphrase string) ImportOptions {
+	return func(opts *importOpts) {
+		opts.passphrase = passphrase
+	}
+}
+
+// WithImportSecretLockService option for supplying secret lock service used while exporting wallet.
+// This option will be ignored when supplied with 'WithImportPassphrase' option.
+func WithImportSecretLockService(svc secretlock.Service) ImportOptions {
+	return func(opts *importOpts) {
+		opts.secretLockSvc = svc
+	}
+}
+
+// WithImportConflict option for deciding how contents already present in wallet are handled,
+// by default import fails if any of the contents already exists.
+func WithImportConflict(conflict ImportConflict) ImportOptions {
+	return func(opts *importOpts) {
+		opts.conflict = conflict
+	}
+}
+
+// addContentOpts contains options for adding contents to wallet.
+type addContentOpts struct {
+	// IDs of the collections to which content belongs.
+	collectionIDs []string
+}
+
+// AddContentOptions is option for adding contents to wallet.
+type AddContentOptions func(opts *addContentOpts)
+
+// AddByCollection option for adding content to given collections, collections should already exist in wallet.
+// Keys can not be added to collections.
+func AddByCollection(collectionIDs ...string) AddContentOptions {
+	return func(opts *addContentOpts) {
+		opts.collectionIDs = append(opts.collectionIDs, collectionIDs...)
+	}
+}
+
+// getAllContentsOpts contains options for listing wallet contents.
+type getAllContentsOpts struct {
+	// ID of the collection by which contents to be filtered.
+	collectionID string
+}
+
+// GetAllContentsOptions is option for listing wallet contents.
+type GetAllContentsOptions func(opts *getAllContentsOpts)
+
+// WithCollection option for listing only contents which belong to given collection.
+func WithCollection(collectionID string) GetAllContentsOptions {
+	return func(opts *getAllContentsOpts) {
+		opts.collectionID = collectionID
+	}
+}
+
+// connectOpts contains options for connecting to other DIDComm agents.
+type connectOpts struct {
+	// label to be shared with the other agent during the subsequent did-exchange.
+	myLabel string
+
+	// router connections to be used to establish connection.
+	routerConnections []string
+
+	// DID to be used when reusing a connection.
+	reuseDID string
+
+	// to use any recognized DID in the services array for a reusable connection.
+	reuseAnyConnection bool
+
+	// timeout duration to wait for connection status to be 'completed'.
+	timeout time.Duration
+}
+
+// ConnectOptions is option for accepting out-of-band invitation and to perform DID exchange.
+type ConnectOptions func(opts *connectOpts)
+
+// WithMyLabel option for providing label to be shared with the other agent during the subsequent did-exchange.
+func WithMyLabel(label string) ConnectOptions {
+	return func(opts *connectOpts) {
+		opts.myLabel = label
+	}
+}
+
+// WithRouterConnections option for providing router connections to be used.
+func WithRouterConnections(conns ...string) ConnectOptions {
+	return func(opts *connectOpts) {
+		opts.routerConnections = conns
+	}
+}
+
+// WithReuseDID option for providing DID to be used when reusing a connection.
+func WithReuseDID(id string) ConnectOptions {
+	return func(opts *connectOpts) {
+		opts.reuseDID = id
+	}
+}
+
+// WithReuseAnyConnection option to use any recognized DID in the services array for a reusable connection.
+func WithReuseAnyConnection(reuse bool) ConnectOptions {
+	return func(opts *connectOpts) {
+		opts.reuseAnyConnection = reuse
+	}
+}
+
+// WithConnectTimeout option providing connection timeout.
+// Optional, by default wallet waits for 2 minutes for connection to be completed.
+func WithConnectTimeout(timeout time.Duration) ConnectOptions {
+	return func(opts *connectOpts) {
+		opts.timeout = timeout
+	}
+}
+
+// initiateInteractionOpts contains options for proposing presentation/credential to other DIDComm agents.
+type initiateInteractionOpts struct {
+	// options for establishing connection with the other agent.
+	connectOpts []ConnectOptions
+
+	// timeout duration to wait for response from the other agent.
+	timeout time.Duration
+}
+
+// InitiateInteractionOption is option for initiating a DIDComm interaction with other agents from wallet.
+type InitiateInteractionOption func(opts *initiateInteractionOpts)
+
+// WithInitiateConnectOptions options for establishing connection with the other agent
+// before initiating the interaction.
+func WithInitiateConnectOptions(options ...ConnectOptions) InitiateInteractionOption {
+	return func(opts *initiateInteractionOpts) {
+		opts.connectOpts = append(opts.connectOpts, options...)
+	}
+}
+
+// WithInitiateTimeout option providing timeout to wait for response from the other agent.
+// Optional, by default wallet waits for 2 minutes for the response.
+func WithInitiateTimeout(timeout time.Duration) InitiateInteractionOption {
+	return func(opts *initiateInteractionOpts) {
+		opts.timeout = timeout
+	}
+}
+
+// concludeInteractionOpts contains options for concluding a DIDComm interaction with other agents.
+type concludeInteractionOpts struct {
+	// IDs of the collections to which credentials received should be added.
+	collectionIDs []string
+
+	// timeout duration to wait for response from the other agent.
+	timeout time.Duration
+}
+
+// ConcludeInteractionOptions is option for concluding a DIDComm interaction with other agents from wallet.
+type ConcludeInteractionOptions func(opts *concludeInteractionOpts)
+
+// WithSaveToCollection option for adding credentials received to given wallet collections.
+func WithSaveToCollection(collectionIDs ...string) ConcludeInteractionOptions {
+	return func(opts *concludeInteractionOpts) {
+		opts.collectionIDs = append(opts.collectionIDs, collectionIDs...)
+	}
+}
+
+// WithConcludeTimeout option providing timeout to wait for response from the other agent.
+// Optional, by default wallet waits for 2 minutes for the response.
+func WithConcludeTimeout(timeout time.Duration) ConcludeInteractionOptions {
+	return func(opts *concludeInteractionOpts) {
+		opts.timeout = timeout
+	}
+}
@@ -45,7 +45,7 @@ type profile struct {
 func createProfile(user, passphrase string, secretLockSvc secretlock.Service, keyServerURL string) (*profile, error) {
 	profile := &profile{User: user, ID: uuid.New().String()}
 
-	err := profile.setKMSOptions(passphrase, secretLockSvc, keyServerURL)
+	err := profile.setKMSOptions(passphrase, secretLockSvc, keyServerURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +53,10 @@ func createProfile(user, passphrase string, secretLockSvc secretlock.Service, ke
 	return profile, nil
 }
 
-func (pr *profile) setKMSOptions(passphrase string, secretLockSvc secretlock.Service, keyServerURL string) error {
+// setKMSOptions sets key manager options of the profile, localkms options lock masterKey if provided or a new
+// master key otherwise.
+func (pr *profile) setKMSOptions(passphrase string, secretLockSvc secretlock.Service, keyServerURL string,
+	masterKey []byte) error {
 	pr.resetKMSOptions()
 
 	var err error
@@ -66,13 +69,13 @@ func (pr *profile) setKMSOptions(passphrase string, secretLockSvc secretlock.Ser
 			return err
 		}
 
-		pr.MasterLockCipher, err = createMasterLock(secretLockSvc)
+		pr.MasterLockCipher, err = lockMasterKey(secretLockSvc, masterKey)
 		if err != nil {
 			return err
 		}
 	case secretLockSvc != nil:
 		// localkms with secret lock service
-		pr.MasterLockCipher, err = createMasterLock(secretLockSvc)
+		pr.MasterLockCipher, err = lockMasterKey(secretLockSvc, masterKey)
 		if err != nil {
 			return err
 		}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/presexch"
@@ -63,6 +64,8 @@ func GetQueryType(name string) (QueryType, error) {
 type Query struct {
 	publicKeyFetcher verifiable.PublicKeyFetcher
 	params           []*QueryParams
+	// storage keys of the credentials by collection ID.
+	collections map[string]map[string]struct{}
 }
 
 // NewQuery returns new wallet query instance.
@@ -70,6 +73,49 @@ func NewQuery(pkFetcher verifiable.PublicKeyFetcher, queries ...*QueryParams) *Q
 	return &Query{publicKeyFetcher: pkFetcher, params: queries}
 }
 
+// addCollection registers credentials of the collection to be used for queries limited to the collection.
+func (q *Query) addCollection(collectionID string, members map[string]json.RawMessage) {
+	if q.collections == nil {
+		q.collections = make(map[string]map[string]struct{})
+	}
+
+	keys := make(map[string]struct{}, len(members))
+
+	for key := range members {
+		keys[key] = struct{}{}
+	}
+
+	q.collections[collectionID] = keys
+}
+
+// scopeCredentials returns credentials to be used for given query params, ordered by their storage keys.
+func (q *Query) scopeCredentials(param *QueryParams, vcs map[string]*verifiable.Credential) []*verifiable.Credential {
+	members := q.collections[param.CollectionID]
+
+	keys := make([]string, 0, len(vcs))
+
+	for key := range vcs {
+		if param.CollectionID != "" {
+			// credentials of collections which are not registered to the query are never used.
+			if _, ok := members[key]; !ok {
+				continue
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]*verifiable.Credential, len(keys))
+
+	for i, key := range keys {
+		result[i] = vcs[key]
+	}
+
+	return result
+}
+
 // PerformQuery performs credential query on given credentials.
 // nolint:gocyclo
 func (q *Query) PerformQuery(credentials map[string]json.RawMessage) ([]*verifiable.Presentation, error) {
@@ -93,7 +139,9 @@ func (q *Query) PerformQuery(credentials map[string]json.RawMessage) ([]*verifia
 			return nil, err
 		}
 
-		credentials, err := q.getCredentials(qType, vcs, param.Query...)
+		scoped := q.scopeCredentials(param, vcs)
+
+		credentials, err := q.getCredentials(qType, scoped, param.Query...)
 		if err != nil {
 			return nil, err
 		}
@@ -102,7 +150,7 @@ func (q *Query) PerformQuery(credentials map[string]json.RawMessage) ([]*verifia
 			credResults[cred] = struct{}{}
 		}
 
-		presentations, err := q.getPresentation(qType, vcs, param.Query...)
+		presentations, err := q.getPresentation(qType, scoped, param.Query...)
 		if err != nil {
 			return nil, err
 		}
@@ -126,6 +174,52 @@ func (q *Query) PerformQuery(credentials map[string]json.RawMessage) ([]*verifia
 	return results, nil
 }
 
+// QueryMatch is a result of 'PresentationExchange' query matching: the candidate credentials of each input
+// descriptor of the presentation definition grouped by its submission requirements.
+type QueryMatch struct {
+	// DefinitionID is the ID of the presentation definition.
+	DefinitionID string `json:"definitionID"`
+
+	// Requirements contains the candidate credentials of each input descriptor grouped by submission requirements.
+	Requirements []*presexch.MatchedSubmissionRequirement `json:"requirements"`
+}
+
+// PerformMatch matches given credentials against the presentation definitions of 'PresentationExchange' queries
+// and returns a result per presentation definition, even if its submission requirements are not satisfied.
+func (q *Query) PerformMatch(credentials map[string]json.RawMessage) ([]*QueryMatch, error) {
+	vcs, err := parseCredentialContents(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*QueryMatch
+
+	for _, param := range q.params {
+		qType, err := GetQueryType(param.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if qType != PresentationExchange {
+			return nil, fmt.Errorf("unsupported query type '%s' for matching, supported type - %s",
+				param.Type, PresentationExchange.Name())
+		}
+
+		scoped := q.scopeCredentials(param, vcs)
+
+		for _, def := range param.Query {
+			match, err := matchByPresentationExchange(scoped, def)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, match)
+		}
+	}
+
+	return results, nil
+}
+
 // getCredentials runs given query and returns query result as credentials.
 func (q *Query) getCredentials(qType QueryType, vcs []*verifiable.Credential, query ...json.RawMessage) ([]*verifiable.Credential, error) { // nolint: lll
 	switch qType {
@@ -269,16 +363,16 @@ func preparePresentation(credentials map[*verifiable.Credential]struct{}) (*veri
 // show credentials as verified. If a wallet implementation chooses to show credentials as 'verified' it
 // may to call 'wallet.Verify()' for each credential being presented.
 // (More details can be found in issue #2677).
-func parseCredentialContents(raws map[string]json.RawMessage) ([]*verifiable.Credential, error) {
-	var result []*verifiable.Credential
+func parseCredentialContents(raws map[string]json.RawMessage) (map[string]*verifiable.Credential, error) {
+	result := make(map[string]*verifiable.Credential, len(raws))
 
-	for _, raw := range raws {
+	for key, raw := range raws {
 		vc, err := verifiable.ParseCredential(raw, verifiable.WithDisabledProofCheck())
 		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, vc)
+		result[key] = vc
 	}
 
 	return result, nil
@@ -398,7 +492,16 @@ func queryByPresentationExchange(vcs []*verifiable.Credential, defs ...json.RawM
 			return nil, err
 		}
 
-		result, err := presDefinition.CreateVP(vcs, verifiable.WithDisabledProofCheck())
+		candidates, err := matchPresentationDefinition(&presDefinition, vcs)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		result, err := presDefinition.CreateVP(candidates, verifiable.WithDisabledProofCheck())
 
 		if errors.Is(err, presexch.ErrNoCredentials) {
 			continue
@@ -414,6 +517,66 @@ func queryByPresentationExchange(vcs []*verifiable.Credential, defs ...json.RawM
 	return results, nil
 }
 
+// matchPresentationDefinition returns credentials matching any of the input descriptors of the presentation
+// definition, or nil if the submission requirements cannot be satisfied by given credentials.
+func matchPresentationDefinition(pd *presexch.PresentationDefinition,
+	vcs []*verifiable.Credential) ([]*verifiable.Credential, error) {
+	requirements, err := pd.MatchSubmissionRequirement(vcs, verifiable.WithDisabledProofCheck())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[*verifiable.Credential]struct{})
+
+	for _, req := range requirements {
+		if !req.Satisfied() {
+			return nil, nil
+		}
+
+		collectMatchedCredentials(req, candidates)
+	}
+
+	// keep the order of the wallet credentials.
+	var result []*verifiable.Credential
+
+	for _, vc := range vcs {
+		if _, ok := candidates[vc]; ok {
+			result = append(result, vc)
+		}
+	}
+
+	return result, nil
+}
+
+func collectMatchedCredentials(req *presexch.MatchedSubmissionRequirement, result map[*verifiable.Credential]struct{}) {
+	for _, descriptor := range req.Descriptors {
+		for _, vc := range descriptor.MatchedVCs {
+			result[vc] = struct{}{}
+		}
+	}
+
+	for _, nested := range req.Nested {
+		collectMatchedCredentials(nested, result)
+	}
+}
+
+// matchByPresentationExchange matches credentials against given presentation definition.
+func matchByPresentationExchange(vcs []*verifiable.Credential, def json.RawMessage) (*QueryMatch, error) {
+	var presDefinition presexch.PresentationDefinition
+
+	err := json.Unmarshal(def, &presDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse presentation definition: %w", err)
+	}
+
+	requirements, err := presDefinition.MatchSubmissionRequirement(vcs, verifiable.WithDisabledProofCheck())
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryMatch{DefinitionID: presDefinition.ID, Requirements: requirements}, nil
+}
+
 // didAuth prepares presentation for DID authorization.
 func didAuth() ([]*verifiable.Presentation, error) {
 	presentation, err := verifiable.NewPresentation()
@@ -1122,6 +1122,76 @@ func TestQueryByFrame(t *testing.T) {
 	})
 }
 
+func TestMatchPresentationDefinition(t *testing.T) {
+	newVC := func(fields map[string]interface{}) *verifiable.Credential {
+		return &verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      uuid.New().String(),
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			CustomFields: fields,
+		}
+	}
+
+	descriptor := func(id, path string) *presexch.InputDescriptor {
+		return &presexch.InputDescriptor{
+			ID:     id,
+			Group:  []string{"A"},
+			Schema: []*presexch.Schema{{URI: schemaURI}},
+			Constraints: &presexch.Constraints{
+				Fields: []*presexch.Field{{Path: []string{path}}},
+			},
+		}
+	}
+
+	vc1 := newVC(map[string]interface{}{"first_name": "Jesse"})
+	vc2 := newVC(map[string]interface{}{"last_name": "Doe"})
+	vc3 := newVC(map[string]interface{}{"age": 30})
+
+	t.Run("requirements satisfied", func(t *testing.T) {
+		pd := &presexch.PresentationDefinition{
+			ID: uuid.New().String(),
+			InputDescriptors: []*presexch.InputDescriptor{
+				descriptor("first", "$.first_name"),
+				descriptor("last", "$.last_name"),
+			},
+		}
+
+		result, err := matchPresentationDefinition(pd, []*verifiable.Credential{vc1, vc2, vc3})
+		require.NoError(t, err)
+		require.Equal(t, []*verifiable.Credential{vc1, vc2}, result)
+	})
+
+	t.Run("requirements not satisfied", func(t *testing.T) {
+		pd := &presexch.PresentationDefinition{
+			ID: uuid.New().String(),
+			SubmissionRequirements: []*presexch.SubmissionRequirement{{
+				Rule: presexch.All,
+				From: "A",
+			}},
+			InputDescriptors: []*presexch.InputDescriptor{
+				descriptor("first", "$.first_name"),
+				descriptor("middle", "$.middle_name"),
+			},
+		}
+
+		result, err := matchPresentationDefinition(pd, []*verifiable.Credential{vc1, vc2, vc3})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("invalid definition", func(t *testing.T) {
+		result, err := matchPresentationDefinition(&presexch.PresentationDefinition{},
+			[]*verifiable.Credential{vc1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "input_descriptors is required")
+		require.Empty(t, result)
+	})
+}
+
 func TestUtilFunctions(t *testing.T) {
 	require.True(t, isEmpty(""))
 	require.True(t, isEmpty([]string{}))
@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/shamir"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+// wallet recovery constants.
+const (
+	// RecoveryShareMsgType is the DIDComm message type delivering a wallet recovery share to a guardian.
+	RecoveryShareMsgType = "https://didcomm.org/wallet-recovery/1.0/share"
+
+	recoveryShareVersion = 1
+)
+
+// recoveryShare is a Shamir share of the master key of a wallet profile, serialized as a printable string.
+type recoveryShare struct {
+	Version   int    `json:"v"`
+	ProfileID string `json:"profile"`
+	Threshold int    `json:"threshold"`
+	// Digest is the SHA-256 digest of the master key, used to verify the recovered master key.
+	Digest []byte `json:"digest"`
+	Share  []byte `json:"share"`
+}
+
+// RecoveryShareMsg is the DIDComm message delivering a wallet recovery share to a guardian.
+type RecoveryShareMsg struct {
+	ID    string `json:"@id"`
+	Type  string `json:"@type"`
+	Share string `json:"share"`
+}
+
+// CreateRecoveryShares splits the master key of this wallet profile into recovery shares, any threshold number of which
+// can recover the profile with a new passphrase or secret lock service using 'RecoverProfile()'.
+// Shares can be handed over to guardians as printable strings or as DIDComm messages (see 'NewRecoveryShareMessage()').
+// Only supported for wallet profiles using local kms.
+//
+//	Args:
+//		- number of shares to be created (up to 255).
+//		- threshold number of shares required to recover the profile (at least 2).
+//		- unlock options (passphrase or secret lock service of the profile) for accessing the master key.
+//
+// Returns:
+//		- recovery shares.
+//		- error if operation fails.
+func (c *Wallet) CreateRecoveryShares(shares, threshold int, options ...UnlockOptions) ([]string, error) {
+	if c.profile.MasterLockCipher == "" {
+		return nil, errors.New("recovery shares are only supported for wallet profiles using local kms")
+	}
+
+	opts := &unlockOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	masterLocker := opts.secretLockSvc
+
+	if opts.passphrase != "" {
+		var err error
+
+		masterLocker, err = getDefaultSecretLock(opts.passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if masterLocker == nil {
+		return nil, errors.New("passphrase or secret lock service is required to create recovery shares")
+	}
+
+	masterKey, err := masterLocker.Decrypt("", &secretlock.DecryptRequest{Ciphertext: c.profile.MasterLockCipher})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock wallet master key: %w", err)
+	}
+
+	parts, err := shamir.Split([]byte(masterKey.Plaintext), shares, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split wallet master key: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(masterKey.Plaintext))
+	result := make([]string, len(parts))
+
+	for i, part := range parts {
+		shareBytes, e := json.Marshal(&recoveryShare{
+			Version:   recoveryShareVersion,
+			ProfileID: c.profile.ID,
+			Threshold: threshold,
+			Digest:    digest[:],
+			Share:     part,
+		})
+		if e != nil {
+			return nil, fmt.Errorf("failed to marshal recovery share: %w", e)
+		}
+
+		result[i] = base64.RawURLEncoding.EncodeToString(shareBytes)
+	}
+
+	return result, nil
+}
+
+// RecoverProfile recovers the master key of the wallet profile of given user from recovery shares created by
+// 'Wallet.CreateRecoveryShares()' and updates the profile to lock the recovered master key with the new passphrase
+// or secret lock service provided, keys of the profile remain accessible.
+// Any active session of the profile remains active until it expires or is revoked.
+//
+//	Args:
+//		- ID of the wallet user.
+//		- provider.
+//		- threshold number of recovery shares of the profile.
+//		- new passphrase ('WithPassphrase()') or secret lock service ('WithSecretLockService()') of the profile.
+//
+// Returns:
+//		- error if operation fails.
+func RecoverProfile(userID string, ctx provider, shares []string, options ...ProfileKeyManagerOptions) error {
+	opts := &kmsOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.passphrase == "" && opts.secretLockSvc == nil {
+		return errors.New("passphrase or secret lock service is required to recover wallet profile")
+	}
+
+	store, err := newProfileStore(ctx.StorageProvider())
+	if err != nil {
+		return fmt.Errorf("failed to get store to fetch VC wallet profile info: %w", err)
+	}
+
+	profile, err := store.get(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get VC wallet profile: %w", err)
+	}
+
+	masterKey, err := combineRecoveryShares(profile.ID, shares)
+	if err != nil {
+		return fmt.Errorf("failed to recover wallet master key: %w", err)
+	}
+
+	return UpdateProfile(userID, ctx, append(options, withRecoveredMasterKey(masterKey))...)
+}
+
+// NewRecoveryShareMessage creates a DIDComm message delivering given recovery share to a guardian,
+// which can be sent to a guardian connection using the messaging client.
+func NewRecoveryShareMessage(share string) service.DIDCommMsgMap {
+	return service.NewDIDCommMsgMap(&RecoveryShareMsg{
+		ID:    uuid.New().String(),
+		Type:  RecoveryShareMsgType,
+		Share: share,
+	})
+}
+
+// ReadRecoveryShareMessage reads the recovery share delivered by given DIDComm message.
+func ReadRecoveryShareMessage(msg service.DIDCommMsg) (string, error) {
+	if msg.Type() != RecoveryShareMsgType {
+		return "", fmt.Errorf("invalid recovery share message type: %s", msg.Type())
+	}
+
+	shareMsg := &RecoveryShareMsg{}
+
+	err := msg.Decode(shareMsg)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode recovery share message: %w", err)
+	}
+
+	if shareMsg.Share == "" {
+		return "", errors.New("recovery share message has no share")
+	}
+
+	return shareMsg.Share, nil
+}
+
+// combineRecoveryShares recovers the master key of the profile from recovery shares.
+func combineRecoveryShares(profileID string, shares []string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no recovery shares provided")
+	}
+
+	parts := make([][]byte, len(shares))
+
+	var first *recoveryShare
+
+	for i, s := range shares {
+		share, err := parseRecoveryShare(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if share.ProfileID != profileID {
+			return nil, errors.New("recovery share does not belong to wallet profile")
+		}
+
+		if first == nil {
+			first = share
+		} else if share.Threshold != first.Threshold || subtle.ConstantTimeCompare(share.Digest, first.Digest) != 1 {
+			return nil, errors.New("recovery shares do not belong to the same master key")
+		}
+
+		parts[i] = share.Share
+	}
+
+	if len(parts) < first.Threshold {
+		return nil, fmt.Errorf("not enough recovery shares: %d provided, %d required", len(parts), first.Threshold)
+	}
+
+	masterKey, err := shamir.Combine(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(masterKey)
+	if subtle.ConstantTimeCompare(digest[:], first.Digest) != 1 {
+		return nil, errors.New("recovered master key does not match recovery shares")
+	}
+
+	return masterKey, nil
+}
+
+func parseRecoveryShare(s string) (*recoveryShare, error) {
+	shareBytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery share: %w", err)
+	}
+
+	share := &recoveryShare{}
+
+	err = json.Unmarshal(shareBytes, share)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery share: %w", err)
+	}
+
+	if share.Version != recoveryShareVersion {
+		return nil, fmt.Errorf("unsupported recovery share version: %d", share.Version)
+	}
+
+	return share, nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/hkdf"
+)
+
+const sampleNewPassphrase = "sample-new-passphrase"
+
+func TestWallet_RecoverProfile(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NoError(t, err)
+
+	// create a key to be recovered.
+	tkn, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	km, err := keyManager().getKeyManger(tkn)
+	require.NoError(t, err)
+
+	keyID, _, err := km.Create(kms.ED25519Type)
+	require.NoError(t, err)
+	require.True(t, walletInstance.Close())
+
+	shares, err := walletInstance.CreateRecoveryShares(5, 3, WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	t.Run("recover profile with new passphrase", func(t *testing.T) {
+		err = RecoverProfile(sampleUserID, mockctx, []string{shares[4], shares[1], shares[2]},
+			WithPassphrase(sampleNewPassphrase))
+		require.NoError(t, err)
+
+		recovered, err := New(sampleUserID, mockctx)
+		require.NoError(t, err)
+		require.Equal(t, walletInstance.profile.ID, recovered.profile.ID)
+
+		_, err = recovered.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.Error(t, err)
+
+		tkn, err := recovered.Open(WithUnlockByPassphrase(sampleNewPassphrase))
+		require.NoError(t, err)
+
+		defer recovered.Close()
+
+		km, err := keyManager().getKeyManger(tkn)
+		require.NoError(t, err)
+
+		_, err = km.Get(keyID)
+		require.NoError(t, err)
+	})
+
+	t.Run("shares are delivered as DIDComm messages", func(t *testing.T) {
+		msg := NewRecoveryShareMessage(shares[0])
+		require.Equal(t, RecoveryShareMsgType, msg.Type())
+		require.NotEmpty(t, msg.ID())
+
+		msgBytes, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		received, err := service.ParseDIDCommMsgMap(msgBytes)
+		require.NoError(t, err)
+
+		share, err := ReadRecoveryShareMessage(received)
+		require.NoError(t, err)
+		require.Equal(t, shares[0], share)
+
+		_, err = ReadRecoveryShareMessage(service.NewDIDCommMsgMap(&RecoveryShareMsg{Type: "other"}))
+		require.EqualError(t, err, "invalid recovery share message type: other")
+
+		_, err = ReadRecoveryShareMessage(service.NewDIDCommMsgMap(&RecoveryShareMsg{Type: RecoveryShareMsgType}))
+		require.EqualError(t, err, "recovery share message has no share")
+	})
+}
+
+func TestWallet_CreateRecoveryShares(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NoError(t, err)
+
+	t.Run("with secret lock service", func(t *testing.T) {
+		mockctx := newMockProvider()
+
+		secretLockSvc, err := hkdf.NewMasterLock(samplePassPhrase, sha256.New, nil)
+		require.NoError(t, err)
+
+		err = CreateProfile(sampleUserID, mockctx, WithSecretLockService(secretLockSvc))
+		require.NoError(t, err)
+
+		wallet, err := New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		shares, err := wallet.CreateRecoveryShares(2, 2, WithUnlockBySecretLockService(secretLockSvc))
+		require.NoError(t, err)
+		require.Len(t, shares, 2)
+
+		err = RecoverProfile(sampleUserID, mockctx, shares, WithPassphrase(sampleNewPassphrase))
+		require.NoError(t, err)
+
+		_, err = wallet.Open(WithUnlockByPassphrase(sampleNewPassphrase))
+		require.Error(t, err, "wallet instance keeps the profile it was created with")
+
+		wallet, err = New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		_, err = wallet.Open(WithUnlockByPassphrase(sampleNewPassphrase))
+		require.NoError(t, err)
+		require.True(t, wallet.Close())
+	})
+
+	t.Run("remote kms profile", func(t *testing.T) {
+		mockctx := newMockProvider()
+
+		err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+		require.NoError(t, err)
+
+		wallet, err := New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		_, err = wallet.CreateRecoveryShares(3, 2, WithUnlockByAuthorizationToken(sampleFakeTkn))
+		require.EqualError(t, err, "recovery shares are only supported for wallet profiles using local kms")
+	})
+
+	t.Run("missing unlock options", func(t *testing.T) {
+		_, err := walletInstance.CreateRecoveryShares(3, 2)
+		require.EqualError(t, err, "passphrase or secret lock service is required to create recovery shares")
+	})
+
+	t.Run("invalid passphrase", func(t *testing.T) {
+		_, err := walletInstance.CreateRecoveryShares(3, 2, WithUnlockByPassphrase("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unlock wallet master key")
+
+		_, err = walletInstance.CreateRecoveryShares(3, 2, WithUnlockBySecretLockService(
+			&secretlock.MockSecretLock{ErrDecrypt: errors.New(sampleWalletErr)}))
+		require.EqualError(t, err, "failed to unlock wallet master key: "+sampleWalletErr)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		_, err := walletInstance.CreateRecoveryShares(2, 3, WithUnlockByPassphrase(samplePassPhrase))
+		require.EqualError(t, err, "failed to split wallet master key: parts cannot be less than threshold")
+	})
+}
+
+func TestRecoverProfile_Failure(t *testing.T) {
+	mockctx := newMockProvider()
+	createSampleProfile(t, mockctx)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NoError(t, err)
+
+	shares, err := walletInstance.CreateRecoveryShares(3, 2, WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	otherShares, err := walletInstance.CreateRecoveryShares(3, 2, WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	t.Run("missing profile options", func(t *testing.T) {
+		err := RecoverProfile(sampleUserID, mockctx, shares)
+		require.EqualError(t, err, "passphrase or secret lock service is required to recover wallet profile")
+
+		err = RecoverProfile(sampleUserID, mockctx, shares, WithKeyServerURL(sampleKeyServerURL))
+		require.EqualError(t, err, "passphrase or secret lock service is required to recover wallet profile")
+	})
+
+	t.Run("profile not found", func(t *testing.T) {
+		err := RecoverProfile("unknown-user", mockctx, shares, WithPassphrase(sampleNewPassphrase))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrProfileNotFound))
+	})
+
+	t.Run("invalid shares", func(t *testing.T) {
+		invalidVersion := base64.RawURLEncoding.EncodeToString([]byte(`{"v":2}`))
+
+		tests := []struct {
+			name   string
+			shares []string
+			err    string
+		}{
+			{
+				name: "no shares",
+				err:  "no recovery shares provided",
+			},
+			{
+				name:   "not enough shares",
+				shares: shares[:1],
+				err:    "not enough recovery shares: 1 provided, 2 required",
+			},
+			{
+				name:   "invalid encoding",
+				shares: []string{shares[0], "invalid{}"},
+				err:    "invalid recovery share: illegal base64 data at input byte 7",
+			},
+			{
+				name:   "invalid share",
+				shares: []string{base64.RawURLEncoding.EncodeToString([]byte("invalid"))},
+				err:    "invalid recovery share: invalid character 'i' looking for beginning of value",
+			},
+			{
+				name:   "unsupported version",
+				shares: []string{invalidVersion},
+				err:    "unsupported recovery share version: 2",
+			},
+			{
+				name:   "shares of different splits",
+				shares: []string{shares[0], otherShares[1]},
+				err:    "recovered master key does not match recovery shares",
+			},
+			{
+				name:   "duplicate shares",
+				shares: []string{shares[0], shares[0]},
+				err:    "invalid or duplicate part",
+			},
+		}
+
+		for _, tt := range tests {
+			tc := tt
+			t.Run(tc.name, func(t *testing.T) {
+				err := RecoverProfile(sampleUserID, mockctx, tc.shares, WithPassphrase(sampleNewPassphrase))
+				require.EqualError(t, err, "failed to recover wallet master key: "+tc.err)
+			})
+		}
+	})
+
+	t.Run("shares of another profile", func(t *testing.T) {
+		mockctx := newMockProvider()
+		createSampleProfile(t, mockctx)
+
+		err := RecoverProfile(sampleUserID, mockctx, shares, WithPassphrase(sampleNewPassphrase))
+		require.EqualError(t, err, "failed to recover wallet master key: "+
+			"recovery share does not belong to wallet profile")
+	})
+
+	t.Run("shares of another master key", func(t *testing.T) {
+		share := &recoveryShare{}
+		shareBytes, err := base64.RawURLEncoding.DecodeString(shares[1])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(shareBytes, share))
+
+		share.Digest = []byte("other digest")
+		shareBytes, err = json.Marshal(share)
+		require.NoError(t, err)
+
+		err = RecoverProfile(sampleUserID, mockctx, []string{shares[0], base64.RawURLEncoding.EncodeToString(shareBytes)},
+			WithPassphrase(sampleNewPassphrase))
+		require.EqualError(t, err, "failed to recover wallet master key: "+
+			"recovery shares do not belong to the same master key")
+	})
+}
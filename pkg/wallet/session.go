@@ -0,0 +1,232 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenScope is scope of a wallet unlock token, which decides wallet operations allowed for the token.
+type TokenScope string
+
+const (
+	// ReadScope allows wallet operations which do not use wallet keys,
+	// for example connecting to other agents or reading presentation requests.
+	ReadScope TokenScope = "read"
+	// SignScope allows signing operations like issue, prove and DIDComm interactions using wallet keys
+	// along with all read operations.
+	SignScope TokenScope = "sign"
+	// AdminScope allows all wallet operations including key imports, export/import and session management.
+	AdminScope TokenScope = "admin"
+)
+
+// supported reasons for wallet lock events.
+const (
+	// LockReasonExpired when token expired.
+	LockReasonExpired = "expired"
+	// LockReasonRevoked when token revoked.
+	LockReasonRevoked = "revoked"
+	// LockReasonClosed when wallet closed.
+	LockReasonClosed = "closed"
+)
+
+// LockEventTopic is the topic of wallet lock events published through notifier supplied
+// by 'WithUnlockNotifier()' option.
+const LockEventTopic = "vcwallet_lock"
+
+// nolint: gochecknoglobals
+var scopeLevels = map[TokenScope]int{ReadScope: 1, SignScope: 2, AdminScope: 3}
+
+// errors.
+var (
+	// ErrSessionNotFound when wallet unlock session is not found in session store.
+	ErrSessionNotFound = errors.New("wallet session not found")
+
+	// ErrInsufficientScope when wallet operation is attempted with a token not having enough scope.
+	ErrInsufficientScope = errors.New("insufficient wallet token scope")
+)
+
+// permits returns true if this scope allows operations of given scope.
+func (s TokenScope) permits(scope TokenScope) bool {
+	return scopeLevels[s] >= scopeLevels[scope]
+}
+
+// validate returns error if scope is not supported.
+func (s TokenScope) validate() error {
+	if _, ok := scopeLevels[s]; !ok {
+		return fmt.Errorf("unsupported token scope '%s', supported scopes are %s, %s, %s",
+			s, ReadScope, SignScope, AdminScope)
+	}
+
+	return nil
+}
+
+// Session is a wallet unlock session.
+type Session struct {
+	// ID of this session, which is the digest of the session token returned by 'SessionID()'.
+	// Session tokens are never stored.
+	ID string `json:"id"`
+
+	// ID of the wallet user.
+	UserID string `json:"userID"`
+
+	// Scope of the session token.
+	Scope TokenScope `json:"scope"`
+
+	// Time after which this session is expired.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Secret of the wallet key manager locked by a key derived from the session token and salt, lets wallet instances
+	// sharing the session store unlock key manager of the user for this session.
+	Secret string `json:"secret,omitempty"`
+
+	// Salt used for deriving the key locking the secret from the session token.
+	Salt []byte `json:"salt,omitempty"`
+}
+
+// LockEvent is published through notifier whenever a wallet session ends.
+type LockEvent struct {
+	// ID of the wallet user.
+	UserID string `json:"userID"`
+
+	// ID of the session ended, see 'SessionID()'.
+	SessionID string `json:"sessionID"`
+
+	// Scope of the session ended.
+	Scope TokenScope `json:"scope"`
+
+	// Reason for the session end, one of 'expired', 'revoked' or 'closed'.
+	Reason string `json:"reason"`
+
+	// Locked is true if wallet got locked since there are no more active sessions for the user.
+	Locked bool `json:"locked"`
+}
+
+// Notifier publishes wallet events.
+type Notifier interface {
+	Notify(topic string, message []byte) error
+}
+
+// SessionStore stores wallet unlock sessions.
+//
+// Unlocked key managers are kept in memory of the wallet instance, sessions carry the key manager secret
+// locked by a key derived from the session token. Sessions are stored by their IDs and never contain the tokens,
+// so that reading the store does not reveal the tokens or the secrets. A session store shared by multiple wallet
+// instances lets any of those instances serve the tokens issued by others and makes revocation and expiry of tokens
+// effective across all instances.
+type SessionStore interface {
+	// Put saves given session.
+	Put(session *Session) error
+
+	// Get returns session of given ID, returns ErrSessionNotFound if not found.
+	Get(id string) (*Session, error)
+
+	// Delete removes session of given ID.
+	Delete(id string) error
+
+	// List returns all sessions of given wallet user.
+	List(userID string) ([]*Session, error)
+}
+
+// SessionID returns ID of the session of given token.
+func SessionID(token string) string {
+	digest := sha256.Sum256([]byte(token))
+
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// SetSessionStore sets session store to be used by wallet instances for managing unlock sessions.
+// Should be set before opening any wallet, sessions from previous store will not be carried to the new store.
+// By default wallet uses an in-memory session store.
+func SetSessionStore(store SessionStore) {
+	keyManager().setStore(store)
+}
+
+// memSessionStore is in-memory session store.
+type memSessionStore struct {
+	sessions map[string]*Session
+	mu       sync.RWMutex
+}
+
+func newMemSessionStore() *memSessionStore {
+	return &memSessionStore{sessions: make(map[string]*Session)}
+}
+
+func (m *memSessionStore) Put(session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := *session
+	m.sessions[session.ID] = &s
+
+	return nil
+}
+
+func (m *memSessionStore) Get(id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	s := *session
+
+	return &s, nil
+}
+
+func (m *memSessionStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+
+	return nil
+}
+
+func (m *memSessionStore) List(userID string) ([]*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sessions []*Session
+
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			s := *session
+			sessions = append(sessions, &s)
+		}
+	}
+
+	return sessions, nil
+}
+
+// publishLockEvent publishes lock event to given notifiers.
+func publishLockEvent(notifiers []Notifier, event *LockEvent) {
+	if len(notifiers) == 0 {
+		return
+	}
+
+	msg, err := json.Marshal(event)
+	if err != nil {
+		logger.Warnf("failed to marshal wallet lock event: %s", err)
+
+		return
+	}
+
+	for _, notifier := range notifiers {
+		if e := notifier.Notify(LockEventTopic, msg); e != nil {
+			logger.Warnf("failed to publish wallet lock event: %s", e)
+		}
+	}
+}
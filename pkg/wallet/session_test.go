@@ -0,0 +1,416 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+)
+
+func TestWallet_Sessions(t *testing.T) {
+	t.Run("test scoped sessions - success", func(t *testing.T) {
+		walletInstance, token := openSessionTestWallet(t)
+
+		readToken, err := walletInstance.CreateSession(token, ReadScope, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, readToken)
+
+		signToken, err := walletInstance.CreateSession(token, SignScope, time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, signToken)
+
+		sessions, err := walletInstance.Sessions(token)
+		require.NoError(t, err)
+		require.Len(t, sessions, 3)
+
+		scopes := map[string]TokenScope{}
+		for _, session := range sessions {
+			require.Equal(t, walletInstance.userID, session.UserID)
+			scopes[session.ID] = session.Scope
+		}
+
+		require.Equal(t, map[string]TokenScope{
+			SessionID(token): AdminScope, SessionID(readToken): ReadScope, SessionID(signToken): SignScope,
+		}, scopes)
+
+		// read token can't be used for signing or importing keys.
+		_, err = getKeyManager(readToken, ReadScope)
+		require.NoError(t, err)
+
+		require.NoError(t, walletInstance.Authorize(readToken, ReadScope))
+		require.True(t, errors.Is(walletInstance.Authorize(readToken, AdminScope), ErrInsufficientScope))
+		require.True(t, errors.Is(walletInstance.Authorize("invalid", ReadScope), ErrWalletLocked))
+
+		otherWallet := &Wallet{userID: uuid.New().String()}
+		require.True(t, errors.Is(otherWallet.Authorize(readToken, ReadScope), ErrWalletLocked))
+
+		_, err = getKeyManager(readToken, SignScope)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		err = walletInstance.Add(readToken, Key, []byte(sampleKeyContentBase58Valid))
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		// sign token can sign, but can't import keys or manage sessions.
+		_, err = getKeyManager(signToken, SignScope)
+		require.NoError(t, err)
+
+		err = walletInstance.Add(signToken, Key, []byte(sampleKeyContentBase58Valid))
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		_, err = walletInstance.CreateSession(signToken, ReadScope, 0)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		_, err = walletInstance.Sessions(readToken)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		// admin token can do all.
+		require.NoError(t, walletInstance.Add(token, Key, []byte(sampleKeyContentBase58Valid)))
+	})
+
+	t.Run("test create session - failures", func(t *testing.T) {
+		walletInstance, token := openSessionTestWallet(t)
+
+		newToken, err := walletInstance.CreateSession(token, "invalid", 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported token scope 'invalid'")
+		require.Empty(t, newToken)
+
+		newToken, err = walletInstance.CreateSession(sampleFakeTkn, ReadScope, 0)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, newToken)
+
+		sessions, err := walletInstance.Sessions(sampleFakeTkn)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+		require.Empty(t, sessions)
+	})
+
+	t.Run("test revoke session", func(t *testing.T) {
+		notifier := &mockNotifier{}
+		walletInstance, token := openSessionTestWallet(t, WithUnlockNotifier(notifier))
+
+		readToken, err := walletInstance.CreateSession(token, ReadScope, 0)
+		require.NoError(t, err)
+
+		signToken, err := walletInstance.CreateSession(token, SignScope, 0)
+		require.NoError(t, err)
+
+		// non admin tokens can revoke only themselves.
+		err = walletInstance.RevokeSession(readToken, signToken)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		require.NoError(t, walletInstance.RevokeSession(readToken, readToken))
+
+		_, err = getKeyManager(readToken, ReadScope)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		// admin can revoke other tokens of the wallet.
+		require.EqualError(t, walletInstance.RevokeSession(token, uuid.New().String()), ErrSessionNotFound.Error())
+		require.NoError(t, walletInstance.RevokeSession(token, signToken))
+
+		// wallet is still unlocked by admin token.
+		_, err = getKeyManager(token, AdminScope)
+		require.NoError(t, err)
+
+		require.NoError(t, walletInstance.RevokeSession(token, token))
+
+		_, err = getKeyManager(token, ReadScope)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		require.False(t, walletInstance.Close())
+
+		events := notifier.lockEvents(t)
+		require.Len(t, events, 3)
+		require.Equal(t, &LockEvent{
+			UserID: walletInstance.userID, SessionID: SessionID(readToken), Scope: ReadScope, Reason: LockReasonRevoked,
+		}, events[0])
+		require.Equal(t, SessionID(signToken), events[1].SessionID)
+		require.False(t, events[1].Locked)
+		require.Equal(t, &LockEvent{
+			UserID: walletInstance.userID, SessionID: SessionID(token), Scope: AdminScope, Reason: LockReasonRevoked,
+			Locked: true,
+		}, events[2])
+	})
+
+	t.Run("test session expiry notification", func(t *testing.T) {
+		notifier := &mockNotifier{}
+		walletInstance, token := openSessionTestWallet(t, WithUnlockNotifier(notifier),
+			WithUnlockExpiry(50*time.Millisecond))
+
+		readToken, err := walletInstance.CreateSession(token, ReadScope, 10*time.Millisecond)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return len(notifier.lockEvents(t)) == 2
+		}, time.Second, 10*time.Millisecond)
+
+		events := notifier.lockEvents(t)
+		require.Equal(t, &LockEvent{
+			UserID: walletInstance.userID, SessionID: SessionID(readToken), Scope: ReadScope, Reason: LockReasonExpired,
+		}, events[0])
+		require.Equal(t, &LockEvent{
+			UserID: walletInstance.userID, SessionID: SessionID(token), Scope: AdminScope, Reason: LockReasonExpired,
+			Locked: true,
+		}, events[1])
+
+		_, err = getKeyManager(token, ReadScope)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		// wallet can be opened again once locked.
+		token, err = walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+	})
+
+	t.Run("test close notification", func(t *testing.T) {
+		notifier := &mockNotifier{}
+		walletInstance, token := openSessionTestWallet(t, WithUnlockNotifier(notifier))
+
+		_, err := walletInstance.CreateSession(token, SignScope, 0)
+		require.NoError(t, err)
+
+		require.True(t, walletInstance.Close())
+
+		events := notifier.lockEvents(t)
+		require.Len(t, events, 2)
+
+		for _, event := range events {
+			require.Equal(t, LockReasonClosed, event.Reason)
+		}
+
+		require.True(t, events[0].Locked || events[1].Locked)
+	})
+
+	t.Run("test sessions shared by wallet instances", func(t *testing.T) {
+		wallet1, token := openSessionTestWallet(t)
+
+		wallet2, err := New(wallet1.userID, wallet1.ctx)
+		require.NoError(t, err)
+
+		token2, err := wallet2.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.True(t, errors.Is(err, ErrAlreadyUnlocked))
+		require.Empty(t, token2)
+
+		readToken, err := wallet2.CreateSession(token, ReadScope, 0)
+		require.NoError(t, err)
+
+		sessions, err := wallet1.Sessions(token)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		require.NoError(t, wallet1.RevokeSession(token, readToken))
+
+		sessions, err = wallet2.Sessions(token)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+	})
+
+	t.Run("test scoped tokens served by instances sharing session store", func(t *testing.T) {
+		walletInstance, token := openSessionTestWallet(t)
+
+		require.NoError(t, walletInstance.Add(token, Key, []byte(sampleKeyContentBase58Valid)))
+
+		signToken, err := walletInstance.CreateSession(token, SignScope, 0)
+		require.NoError(t, err)
+
+		sessions, err := walletInstance.Sessions(token)
+		require.NoError(t, err)
+
+		for _, session := range sessions {
+			require.Empty(t, session.Secret)
+		}
+
+		// wallet instance of another process sharing the session store.
+		other := newWalletKeyManager(keyManager().store)
+
+		_, err = other.authorize(signToken, SignScope)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		other.setStoreProvider(walletInstance.userID, walletInstance.storeProvider)
+
+		signKeyManager, err := other.authorize(signToken, SignScope)
+		require.NoError(t, err)
+
+		_, err = signKeyManager.Get("key-1")
+		require.NoError(t, err)
+
+		_, err = other.authorize(signToken, AdminScope)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+
+		// tokens are never stored, session is stored by digest of the token.
+		_, err = keyManager().store.Get(signToken)
+		require.True(t, errors.Is(err, ErrSessionNotFound))
+
+		session, err := keyManager().store.Get(SessionID(signToken))
+		require.NoError(t, err)
+		require.NotEmpty(t, session.Secret)
+		require.Len(t, session.Salt, sessionSaltSize)
+
+		raw, err := json.Marshal(session)
+		require.NoError(t, err)
+		require.NotContains(t, string(raw), signToken)
+
+		// secret of a session can't be unlocked by the session ID or by other tokens.
+		_, err = unlockSecret(session.ID, session.Salt, session.Secret)
+		require.Error(t, err)
+
+		otherToken := uuid.New().String()
+		session.ID = SessionID(otherToken)
+		require.NoError(t, keyManager().store.Put(session))
+
+		another := newWalletKeyManager(keyManager().store)
+		another.setStoreProvider(walletInstance.userID, walletInstance.storeProvider)
+
+		_, err = another.authorize(otherToken, ReadScope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unlock wallet session")
+
+		// revocation is effective across instances.
+		require.NoError(t, walletInstance.RevokeSession(token, signToken))
+
+		_, err = other.authorize(signToken, ReadScope)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+}
+
+func TestSetSessionStore(t *testing.T) {
+	store := &mockSessionStore{memSessionStore: newMemSessionStore()}
+
+	SetSessionStore(store)
+	defer SetSessionStore(newMemSessionStore())
+
+	walletInstance, token := openSessionTestWallet(t)
+
+	session, err := store.Get(SessionID(token))
+	require.NoError(t, err)
+	require.Equal(t, AdminScope, session.Scope)
+
+	t.Run("test session store errors", func(t *testing.T) {
+		store.getErr = errors.New(sampleWalletErr)
+		defer func() { store.getErr = nil }()
+
+		_, err := getKeyManager(token, ReadScope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), sampleWalletErr)
+	})
+
+	t.Run("test session store put error", func(t *testing.T) {
+		store.putErr = errors.New(sampleWalletErr)
+		defer func() { store.putErr = nil }()
+
+		newToken, err := walletInstance.CreateSession(token, ReadScope, 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to persist wallet session")
+		require.Empty(t, newToken)
+	})
+
+	t.Run("test session store list error", func(t *testing.T) {
+		store.listErr = errors.New(sampleWalletErr)
+		defer func() { store.listErr = nil }()
+
+		sessions, err := walletInstance.Sessions(token)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), sampleWalletErr)
+		require.Empty(t, sessions)
+
+		require.False(t, walletInstance.Close())
+	})
+
+	require.True(t, walletInstance.Close())
+}
+
+func openSessionTestWallet(t *testing.T, options ...UnlockOptions) (*Wallet, string) {
+	t.Helper()
+
+	sampleCrypto, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	mockctx := newMockProvider()
+	mockctx.CryptoValue = sampleCrypto
+
+	userID := uuid.New().String()
+	require.NoError(t, CreateProfile(userID, mockctx, WithPassphrase(samplePassPhrase)))
+
+	walletInstance, err := New(userID, mockctx)
+	require.NoError(t, err)
+
+	token, err := walletInstance.Open(append([]UnlockOptions{WithUnlockByPassphrase(samplePassPhrase)}, options...)...)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	t.Cleanup(func() { walletInstance.Close() })
+
+	return walletInstance, token
+}
+
+type mockNotifier struct {
+	messages [][]byte
+	mu       sync.Mutex
+}
+
+func (n *mockNotifier) Notify(topic string, message []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if topic == LockEventTopic {
+		n.messages = append(n.messages, message)
+	}
+
+	return nil
+}
+
+func (n *mockNotifier) lockEvents(t *testing.T) []*LockEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	events := make([]*LockEvent, len(n.messages))
+
+	for i, msg := range n.messages {
+		events[i] = &LockEvent{}
+		require.NoError(t, json.Unmarshal(msg, events[i]))
+	}
+
+	return events
+}
+
+type mockSessionStore struct {
+	*memSessionStore
+	getErr  error
+	putErr  error
+	listErr error
+}
+
+func (m *mockSessionStore) Put(session *Session) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+
+	return m.memSessionStore.Put(session)
+}
+
+func (m *mockSessionStore) Get(id string) (*Session, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	return m.memSessionStore.Get(id)
+}
+
+func (m *mockSessionStore) List(userID string) ([]*Session, error) {
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+
+	return m.memSessionStore.List(userID)
+}
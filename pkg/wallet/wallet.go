@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/piprate/json-gold/ld"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
@@ -62,6 +64,15 @@ type provider interface {
 	Crypto() crypto.Crypto
 }
 
+// didCommProvider is optionally implemented by wallet provider to enable DIDComm features of the wallet.
+type didCommProvider interface {
+	provider
+	Service(id string) (interface{}, error)
+	KMS() kms.KeyManager
+	ServiceEndpoint() string
+	ProtocolStateStorageProvider() storage.Provider
+}
+
 type provable interface {
 	AddLinkedDataProof(context *verifiable.LinkedDataProofContext, jsonldOpts ...jsonld.ProcessorOpts) error
 }
@@ -77,6 +88,9 @@ type Wallet struct {
 	// wallet content store
 	contents *contentStore
 
+	// store of encrypted key backups, used for exporting wallet keys
+	keyBackups storage.Store
+
 	// crypto for wallet
 	walletCrypto crypto.Crypto
 
@@ -85,6 +99,9 @@ type Wallet struct {
 
 	// wallet VDR
 	walletVDR *walletVDR
+
+	// framework context, DIDComm features of the wallet are supported if it implements didCommProvider.
+	ctx provider
 }
 
 // New returns new verifiable credential wallet for given user.
@@ -107,13 +124,23 @@ func New(userID string, ctx provider) (*Wallet, error) {
 		return nil, fmt.Errorf("failed to get wallet content store: %w", err)
 	}
 
+	keyBackups, err := newKeyBackupStore(ctx.StorageProvider(), profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet key backup store: %w", err)
+	}
+
+	// lets this instance serve tokens issued by other wallet instances sharing the session store.
+	keyManager().setStoreProvider(userID, ctx.StorageProvider())
+
 	return &Wallet{
 		userID:        userID,
 		profile:       profile,
 		storeProvider: ctx.StorageProvider(),
 		walletCrypto:  ctx.Crypto(),
 		contents:      contents,
+		keyBackups:    keyBackups,
 		walletVDR:     newContentBasedVDR(ctx.VDRegistry(), contents),
+		ctx:           ctx,
 	}, nil
 }
 
@@ -152,7 +179,7 @@ func createOrUpdate(userID string, ctx provider, update bool, options ...Profile
 			return fmt.Errorf("failed to update wallet user profile: %w", err)
 		}
 
-		err = profile.setKMSOptions(opts.passphrase, opts.secretLockSvc, opts.keyServerURL)
+		err = profile.setKMSOptions(opts.passphrase, opts.secretLockSvc, opts.keyServerURL, opts.masterKey)
 		if err != nil {
 			return fmt.Errorf("failed to update wallet user profile KMS options: %w", err)
 		}
@@ -188,17 +215,58 @@ func (c *Wallet) Open(options ...UnlockOptions) (string, error) {
 	return keyManager().createKeyManager(c.profile, c.storeProvider, opts)
 }
 
-// Close expires token issued to this VC wallet.
+// Close expires all tokens issued to this VC wallet and locks the wallet.
 // returns false if token is not found or already expired for this wallet user.
 func (c *Wallet) Close() bool {
 	return keyManager().removeKeyManager(c.userID)
 }
 
+// CreateSession creates a new unlock session with given scope for this wallet.
+// Returned token can be handed over to a party which needs restricted access to wallet features.
+//
+//	Args:
+//		- authToken: admin token of this wallet, typically the one returned by 'Open()'.
+//		- scope: scope of the new token (read, sign or admin).
+//		- expiry: time duration after which new token will expire, default 10 minutes.
+//
+//	Returns new token, expired or revoked independently of other tokens of the wallet.
+func (c *Wallet) CreateSession(authToken string, scope TokenScope, expiry time.Duration) (string, error) {
+	return keyManager().createSession(authToken, scope, expiry)
+}
+
+// Sessions returns all active unlock sessions of this wallet.
+//
+//	Args:
+//		- authToken: admin token of this wallet.
+func (c *Wallet) Sessions(authToken string) ([]*Session, error) {
+	return keyManager().listSessions(authToken)
+}
+
+// RevokeSession revokes given token of this wallet, wallet gets locked once all tokens are revoked or expired.
+// Any token can revoke itself, admin token is required to revoke other tokens.
+//
+//	Args:
+//		- authToken: token of this wallet authorizing revocation.
+//		- token: token to be revoked.
+func (c *Wallet) RevokeSession(authToken, token string) error {
+	return keyManager().revokeSession(authToken, token)
+}
+
+// Authorize checks if given token is an active unlock token of this wallet which allows operations of given scope.
+// Returns ErrWalletLocked if token is invalid or expired and ErrInsufficientScope if token scope is not sufficient.
+func (c *Wallet) Authorize(authToken string, scope TokenScope) error {
+	_, err := keyManager().authorizeUser(authToken, c.userID, scope)
+
+	return err
+}
+
 // Export produces a serialized exported wallet representation.
-// Only ciphertext wallet contents can be exported.
+// All wallet contents are exported as a Universal Wallet 'EncryptedWallet' locked by key derived from passphrase
+// or by secret lock service supplied in options.
 //
 //	Args:
-//		- auth: token to be used to lock the wallet before exporting.
+//		- auth: authorization token for wallet key manager, required for exporting keys.
+//		- options for locking exported wallet.
 //
 //	Returns exported locked wallet.
 //
@@ -208,18 +276,26 @@ func (c *Wallet) Close() bool {
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#DIDResolutionResponse
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-func (c *Wallet) Export(auth string) (json.RawMessage, error) {
-	// TODO to be added #2433
-	return nil, fmt.Errorf("to be implemented")
+func (c *Wallet) Export(auth string, options ...ExportOptions) (json.RawMessage, error) {
+	opts := &exportOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	return c.exportContents(auth, opts)
 }
 
 // Import Takes a serialized exported wallet representation as input
-// and imports all contents into wallet.
+// and imports all contents into wallet. Keys are imported into wallet's key manager.
+// By default, import fails if any of the contents already exists in wallet (see 'WithImportConflict()').
 //
 //	Args:
+//		- auth: authorization token for wallet key manager, required for importing keys.
 //		- contents: wallet content to be imported.
-//		- auth: token used while exporting the wallet.
+//		- options for unlocking imported wallet, should match options used while exporting the wallet.
 //
 // Supported data models:
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
@@ -229,9 +305,14 @@ func (c *Wallet) Export(auth string) (json.RawMessage, error) {
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-func (c *Wallet) Import(auth string, contents json.RawMessage) error {
-	// TODO to be added #2433
-	return fmt.Errorf("to be implemented")
+func (c *Wallet) Import(auth string, contents json.RawMessage, options ...ImportOptions) error {
+	opts := &importOpts{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	return c.importContents(auth, contents, opts)
 }
 
 // Add adds given data model to wallet contents store.
@@ -244,9 +325,27 @@ func (c *Wallet) Import(auth string, contents json.RawMessage) error {
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Key
 //
-// TODO: (#2433) support for correlation between wallet contents (ex: credentials to a profile/collection).
-func (c *Wallet) Add(authToken string, contentType ContentType, content json.RawMessage) error {
-	return c.contents.Save(authToken, contentType, content)
+// Contents can be added to one or more collections by using 'AddByCollection()' option.
+func (c *Wallet) Add(authToken string, contentType ContentType, content json.RawMessage,
+	options ...AddContentOptions) error {
+	if contentType == Key {
+		// keys are imported into key manager of this wallet user only.
+		if err := c.Authorize(authToken, AdminScope); err != nil {
+			return err
+		}
+	}
+
+	err := c.contents.Save(authToken, contentType, content, options...)
+	if err != nil {
+		return err
+	}
+
+	if contentType == Key {
+		// keys are never stored in wallet contents, keep an encrypted copy for exporting wallet.
+		return c.backupKey(authToken, content)
+	}
+
+	return nil
 }
 
 // Remove removes wallet content by content ID.
@@ -262,6 +361,20 @@ func (c *Wallet) Remove(contentType ContentType, contentID string) error {
 	return c.contents.Remove(contentType, contentID)
 }
 
+// AddToCollection adds an existing wallet content to given collections, collections should already exist in wallet.
+// Content remains member of the collections to which it already belongs.
+//
+// Supported data models:
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Credential
+// 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#DIDResolutionResponse
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
+//	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
+//
+func (c *Wallet) AddToCollection(contentType ContentType, contentID string, collectionIDs ...string) error {
+	return c.contents.AddToCollection(contentType, contentID, collectionIDs...)
+}
+
 // Get fetches a wallet content by content ID.
 //
 // Supported data models:
@@ -277,6 +390,7 @@ func (c *Wallet) Get(contentType ContentType, contentID string) (json.RawMessage
 
 // GetAll fetches all wallet contents of given type.
 // Returns map of key value from content store for given content type.
+// Contents of a collection can be listed by using 'WithCollection()' option.
 //
 // Supported data models:
 // 	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#Collection
@@ -285,13 +399,15 @@ func (c *Wallet) Get(contentType ContentType, contentID string) (json.RawMessage
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#meta-data
 //	- https://w3c-ccg.github.io/universal-wallet-interop-spec/#connection
 //
-func (c *Wallet) GetAll(contentType ContentType) (map[string]json.RawMessage, error) {
-	return c.contents.GetAll(contentType)
+func (c *Wallet) GetAll(contentType ContentType, options ...GetAllContentsOptions) (map[string]json.RawMessage, error) {
+	return c.contents.GetAll(contentType, options...)
 }
 
 // Query runs query against wallet credential contents and returns presentation containing credential results.
 //
 // This function may return multiple presentations as query result based on combination of query types used.
+// Query can be limited to credentials of a collection by using 'QueryParams.CollectionID'.
+// Use QueryMatch to get all the candidate credentials of 'PresentationExchange' queries.
 //
 // https://w3c-ccg.github.io/universal-wallet-interop-spec/#query
 //
@@ -301,14 +417,92 @@ func (c *Wallet) GetAll(contentType ContentType) (map[string]json.RawMessage, er
 // 	- https://w3c-ccg.github.io/vp-request-spec/#query-by-example
 //
 func (c *Wallet) Query(params ...*QueryParams) ([]*verifiable.Presentation, error) {
-	vcContents, err := c.contents.GetAll(Credential)
+	query, vcContents, err := c.prepareQuery(params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.PerformQuery(vcContents)
+}
+
+// QueryMatch runs 'PresentationExchange' queries against wallet credential contents and returns the candidate
+// credentials of each input descriptor of the presentation definitions, grouped by their submission requirements.
+//
+// Unlike Query, which creates presentations from the credentials chosen by the wallet, the result can be used
+// to let user choose among alternative credentials before creating a presentation using Prove.
+// Query can be limited to credentials of a collection by using 'QueryParams.CollectionID'.
+//
+// https://identity.foundation/presentation-exchange/#submission-requirements
+//
+func (c *Wallet) QueryMatch(params ...*QueryParams) ([]*QueryMatch, error) {
+	query, vcContents, err := c.prepareQuery(params...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query credentials: %w", err)
+		return nil, err
 	}
 
+	return query.PerformMatch(vcContents)
+}
+
+// prepareQuery returns query for given params along with the credentials to be queried,
+// only credentials of the collections are read if all queries are limited to collections.
+func (c *Wallet) prepareQuery(params ...*QueryParams) (*Query, map[string]json.RawMessage, error) {
 	query := NewQuery(verifiable.NewVDRKeyResolver(c.walletVDR).PublicKeyFetcher(), params...)
+	vcContents := make(map[string]json.RawMessage)
+	unlimited := len(params) == 0
 
-	return query.PerformQuery(vcContents)
+	for _, param := range params {
+		if param.CollectionID == "" {
+			unlimited = true
+
+			continue
+		}
+
+		members, err := c.contents.GetAll(Credential, WithCollection(param.CollectionID))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query credentials of collection '%s': %w", param.CollectionID, err)
+		}
+
+		query.addCollection(param.CollectionID, members)
+
+		for key, vc := range members {
+			vcContents[key] = vc
+		}
+	}
+
+	if unlimited {
+		all, err := c.contents.GetAll(Credential)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query credentials: %w", err)
+		}
+
+		vcContents = all
+	}
+
+	return query, vcContents, nil
+}
+
+// MatchSubmissionRequirement matches wallet credentials against given presentation definition and returns
+// the candidate credentials for each input descriptor, grouped by the submission requirements of the definition.
+//
+// It is a shortcut of QueryMatch for a single presentation definition.
+//
+// https://identity.foundation/presentation-exchange/#submission-requirements
+//
+func (c *Wallet) MatchSubmissionRequirement(
+	presentationDefinition json.RawMessage) ([]*presexch.MatchedSubmissionRequirement, error) {
+	matches, err := c.QueryMatch(&QueryParams{
+		Type:  PresentationExchange.Name(),
+		Query: []json.RawMessage{presentationDefinition},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return []*presexch.MatchedSubmissionRequirement{}, nil
+	}
+
+	return matches[0].Requirements, nil
 }
 
 // Issue adds proof to a Verifiable Credential.
@@ -399,6 +593,38 @@ func (c *Wallet) Verify(options VerificationOption) (bool, error) {
 	}
 }
 
+// VerificationReport verifies a Verifiable Credential or Verifiable Presentation and returns the result
+// of each verification check (syntax, schema, proofs, dates, status, holder binding).
+//
+//	Args:
+//		- verification option for sending different models (stored credential ID, raw credential, raw presentation).
+//
+// Returns: verification report, and an error if verification couldn't be performed.
+func (c *Wallet) VerificationReport(options VerificationOption) (*verifiable.VerificationReport, error) {
+	requestOpts := &verifyOpts{}
+
+	options(requestOpts)
+
+	keyFetcher := verifiable.NewVDRKeyResolver(c.walletVDR).PublicKeyFetcher()
+
+	switch {
+	case requestOpts.credentialID != "":
+		raw, err := c.contents.Get(Credential, requestOpts.credentialID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential: %w", err)
+		}
+
+		return verifiable.VerifyCredential(raw, verifiable.WithPublicKeyFetcher(keyFetcher)), nil
+	case len(requestOpts.rawCredential) > 0:
+		return verifiable.VerifyCredential(requestOpts.rawCredential, verifiable.WithPublicKeyFetcher(keyFetcher)), nil
+	case len(requestOpts.rawPresentation) > 0:
+		return verifiable.VerifyPresentation(requestOpts.rawPresentation,
+			verifiable.WithPresPublicKeyFetcher(keyFetcher)), nil
+	default:
+		return nil, fmt.Errorf("invalid verify request")
+	}
+}
+
 // Derive derives a credential and returns response credential.
 //
 //	Args:
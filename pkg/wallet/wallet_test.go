@@ -19,6 +19,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
+	gojose "github.com/square/go-jose/v3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
@@ -35,6 +36,7 @@ import (
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/pbkdf2"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/key"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
@@ -258,6 +260,21 @@ func TestCreate(t *testing.T) {
 		require.Empty(t, wallet)
 		require.Contains(t, err.Error(), "failed to get wallet content store:")
 	})
+
+	t.Run("test create new wallet failure - create key backup store error", func(t *testing.T) {
+		mockctx := newMockProvider()
+		createSampleProfile(t, mockctx)
+
+		wallet, err := New(sampleUserID, mockctx)
+		require.NoError(t, err)
+
+		mockctx.StorageProviderValue.(*mockstorage.MockStoreProvider).FailNamespace = wallet.profile.ID + "_keybackup"
+
+		wallet, err = New(sampleUserID, mockctx)
+		require.Error(t, err)
+		require.Empty(t, wallet)
+		require.Contains(t, err.Error(), "failed to get wallet key backup store:")
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -490,32 +507,344 @@ func TestWallet_OpenClose(t *testing.T) {
 }
 
 func TestWallet_Export(t *testing.T) {
-	mockctx := newMockProvider()
-	err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+	sampleCrypto, err := tinkcrypto.New()
 	require.NoError(t, err)
 
+	mockctx := newMockProvider()
+	mockctx.CryptoValue = sampleCrypto
+
+	createSampleProfile(t, mockctx)
+
 	walletInstance, err := New(sampleUserID, mockctx)
 	require.NotEmpty(t, walletInstance)
 	require.NoError(t, err)
 
-	result, err := walletInstance.Export("")
-	require.Empty(t, result)
-	require.Error(t, err)
-	require.EqualError(t, err, toBeImplementedErr)
+	t.Run("export wallet without keys - success", func(t *testing.T) {
+		require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential, []byte(sampleUDCVC)))
+
+		defer func() {
+			require.NoError(t, walletInstance.Remove(Credential, "http://example.edu/credentials/1872"))
+		}()
+
+		tkn, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer walletInstance.Close()
+
+		result, err := walletInstance.Export(tkn, WithExportPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+		require.NotEmpty(t, result)
+
+		var exported encryptedWallet
+		require.NoError(t, json.Unmarshal(result, &exported))
+		require.Equal(t, []string{verifiableCredentialType, encryptedWalletType}, exported.Type)
+		require.NotEmpty(t, exported.CredentialSubject.EncryptedWalletContents)
+		require.NotContains(t, string(result), "http://example.edu/credentials/1872")
+
+		// exported wallet contents are standard PBES2 JWE.
+		jwe, err := gojose.ParseEncrypted(string(exported.CredentialSubject.EncryptedWalletContents))
+		require.NoError(t, err)
+		require.Equal(t, "PBES2-HS512+A256KW", jwe.Header.Algorithm)
+
+		plaintext, err := jwe.Decrypt([]byte(samplePassPhrase))
+		require.NoError(t, err)
+		require.Contains(t, string(plaintext), "http://example.edu/credentials/1872")
+	})
+
+	t.Run("export wallet without keys using invalid token - failure", func(t *testing.T) {
+		result, err := walletInstance.Export("", WithExportPassphrase(samplePassPhrase))
+		require.Empty(t, result)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		result, err = walletInstance.Export(sampleFakeTkn, WithExportPassphrase(samplePassPhrase))
+		require.Empty(t, result)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+
+	t.Run("export wallet with token of insufficient scope - failure", func(t *testing.T) {
+		tkn, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer walletInstance.Close()
+
+		readToken, err := walletInstance.CreateSession(tkn, ReadScope, 0)
+		require.NoError(t, err)
+
+		result, err := walletInstance.Export(readToken, WithExportPassphrase(samplePassPhrase))
+		require.Empty(t, result)
+		require.True(t, errors.Is(err, ErrInsufficientScope))
+	})
+
+	t.Run("export wallet without lock options - failure", func(t *testing.T) {
+		tkn, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer walletInstance.Close()
+
+		result, err := walletInstance.Export(tkn)
+		require.Empty(t, result)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "passphrase or secret lock service is required")
+	})
+
+	t.Run("export wallet with keys from locked wallet - failure", func(t *testing.T) {
+		tkn, err := walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+		require.NoError(t, walletInstance.Add(tkn, Key, []byte(sampleKeyContentBase58Valid)))
+		require.True(t, walletInstance.Close())
+
+		result, err := walletInstance.Export(sampleFakeTkn, WithExportPassphrase(samplePassPhrase))
+		require.Empty(t, result)
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
 }
 
 func TestWallet_Import(t *testing.T) {
+	sampleCrypto, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	const (
+		sampleMetadata   = `{"id": "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002", "type": "Metadata"}`
+		sampleCollection = `{"id": "urn:uuid:collection-1", "type": "Collection"}`
+	)
+
+	// wallet to be exported.
 	mockctx := newMockProvider()
-	err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+	mockctx.CryptoValue = sampleCrypto
+
+	createSampleProfile(t, mockctx)
+
+	walletForExport, err := New(sampleUserID, mockctx)
+	require.NoError(t, err)
+
+	tkn, err := walletForExport.Open(WithUnlockByPassphrase(samplePassPhrase))
 	require.NoError(t, err)
 
+	require.NoError(t, walletForExport.Add(tkn, Credential, []byte(sampleUDCVC)))
+	require.NoError(t, walletForExport.Add(tkn, Collection, []byte(sampleCollection)))
+	require.NoError(t, walletForExport.Add(tkn, Metadata, []byte(sampleMetadata), AddByCollection("urn:uuid:collection-1")))
+	require.NoError(t, walletForExport.Add(tkn, DIDResolutionResponse, []byte(sampleDocResolutionResponse)))
+	require.NoError(t, walletForExport.Add(tkn, Key, []byte(sampleKeyContentBase58Valid)))
+
+	byPassphrase, err := walletForExport.Export(tkn, WithExportPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	bySecretLock, err := walletForExport.Export(tkn, WithExportSecretLockService(&noop.NoLock{}))
+	require.NoError(t, err)
+
+	require.True(t, walletForExport.Close())
+
+	// wallet to import into.
+	mockctx = newMockProvider()
+	mockctx.CryptoValue = sampleCrypto
+
+	createSampleProfile(t, mockctx)
+
 	walletInstance, err := New(sampleUserID, mockctx)
-	require.NotEmpty(t, walletInstance)
 	require.NoError(t, err)
 
-	err = walletInstance.Import("", nil)
-	require.Error(t, err)
-	require.EqualError(t, err, toBeImplementedErr)
+	tkn, err = walletInstance.Open(WithUnlockByPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	defer walletInstance.Close()
+
+	t.Run("import with wrong passphrase - failure", func(t *testing.T) {
+		err := walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase+"wrong"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unlock wallet contents")
+	})
+
+	t.Run("import without matching lock options - failure", func(t *testing.T) {
+		err := walletInstance.Import(tkn, byPassphrase, WithImportSecretLockService(&secretlock.MockSecretLock{}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing or unsupported lock option")
+	})
+
+	t.Run("import wallet with hostile PBES2 parameters - failure", func(t *testing.T) {
+		for _, count := range []interface{}{1, 100000000, "1000", 2000.5} {
+			var locked encryptedWallet
+			require.NoError(t, json.Unmarshal(byPassphrase, &locked))
+
+			var jwe map[string]interface{}
+			require.NoError(t, json.Unmarshal(locked.CredentialSubject.EncryptedWalletContents, &jwe))
+
+			headers, err := base64.RawURLEncoding.DecodeString(jwe["protected"].(string))
+			require.NoError(t, err)
+
+			var protected map[string]interface{}
+			require.NoError(t, json.Unmarshal(headers, &protected))
+
+			protected["p2c"] = count
+
+			headers, err = json.Marshal(protected)
+			require.NoError(t, err)
+
+			jwe["protected"] = base64.RawURLEncoding.EncodeToString(headers)
+
+			locked.CredentialSubject.EncryptedWalletContents, err = json.Marshal(jwe)
+			require.NoError(t, err)
+
+			raw, err := json.Marshal(&locked)
+			require.NoError(t, err)
+
+			err = walletInstance.Import(tkn, raw, WithImportPassphrase(samplePassPhrase))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid PBES2 count")
+		}
+	})
+
+	t.Run("import invalid wallet - failure", func(t *testing.T) {
+		err := walletInstance.Import(tkn, []byte(sampleUDCVC), WithImportPassphrase(samplePassPhrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "type 'EncryptedWallet' is required")
+
+		err = walletInstance.Import(tkn, []byte("--"), WithImportPassphrase(samplePassPhrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read encrypted wallet")
+	})
+
+	t.Run("import into locked wallet - failure", func(t *testing.T) {
+		err := walletInstance.Import(sampleFakeTkn, byPassphrase, WithImportPassphrase(samplePassPhrase))
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+
+	t.Run("import wallet - success", func(t *testing.T) {
+		require.NoError(t, walletInstance.Remove(Credential, "http://example.edu/credentials/1872"))
+		require.NoError(t, walletInstance.Remove(Metadata, "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002"))
+
+		require.NoError(t, walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase)))
+
+		vc, err := walletInstance.Get(Credential, "http://example.edu/credentials/1872")
+		require.NoError(t, err)
+		require.JSONEq(t, sampleUDCVC, string(vc))
+
+		metadata, err := walletInstance.Get(Metadata, "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002")
+		require.NoError(t, err)
+		require.JSONEq(t, sampleMetadata, string(metadata))
+
+		members, err := walletInstance.GetAll(Metadata, WithCollection("urn:uuid:collection-1"))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+
+		docs, err := walletInstance.GetAll(DIDResolutionResponse)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+
+		keyManager, err := keyManager().getKeyManger(tkn)
+		require.NoError(t, err)
+
+		kh, err := keyManager.Get("key-1")
+		require.NoError(t, err)
+		require.NotEmpty(t, kh)
+
+		// imported keys can be exported again.
+		keys, err := walletInstance.readKeyBackups(keyManager)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.JSONEq(t, sampleKeyContentBase58Valid, string(keys[0]))
+	})
+
+	t.Run("import existing contents - conflicts", func(t *testing.T) {
+		err := walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists in this wallet")
+
+		require.NoError(t, walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase),
+			WithImportConflict(ImportConflictSkip)))
+
+		require.NoError(t, walletInstance.Remove(Metadata, "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002"))
+		require.NoError(t, walletInstance.Add(tkn, Metadata,
+			[]byte(`{"id": "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002", "type": "Changed"}`)))
+
+		require.NoError(t, walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase),
+			WithImportConflict(ImportConflictReplace)))
+
+		metadata, err := walletInstance.Get(Metadata, "urn:uuid:2905324a-9524-11ea-bb37-0242ac130002")
+		require.NoError(t, err)
+		require.JSONEq(t, sampleMetadata, string(metadata))
+	})
+
+	t.Run("import with token of another wallet user - failure", func(t *testing.T) {
+		const otherUserID = "sample-user02"
+
+		require.NoError(t, CreateProfile(otherUserID, mockctx, WithPassphrase(samplePassPhrase)))
+
+		otherWallet, err := New(otherUserID, mockctx)
+		require.NoError(t, err)
+
+		otherTkn, err := otherWallet.Open(WithUnlockByPassphrase(samplePassPhrase))
+		require.NoError(t, err)
+
+		defer otherWallet.Close()
+
+		err = walletInstance.Import(otherTkn, byPassphrase, WithImportPassphrase(samplePassPhrase),
+			WithImportConflict(ImportConflictSkip))
+		require.True(t, errors.Is(err, ErrWalletLocked))
+
+		err = walletInstance.Add(otherTkn, Key, []byte(sampleKeyContentBase58Valid))
+		require.True(t, errors.Is(err, ErrWalletLocked))
+	})
+
+	t.Run("import contents of unknown collection - failure", func(t *testing.T) {
+		const sampleCredentialID = "http://example.edu/credentials/1872"
+
+		require.NoError(t, walletInstance.Remove(Credential, sampleCredentialID))
+
+		plaintext, err := json.Marshal(&unlockedWallet{
+			Context: []string{walletContext},
+			ID:      "urn:uuid:" + uuid.New().String(),
+			Type:    universalWalletType,
+			Contents: []*walletContent{{
+				ContentType: Credential, Content: json.RawMessage(sampleUDCVC),
+				Collections: []string{"urn:uuid:unknown-collection"},
+			}},
+		})
+		require.NoError(t, err)
+
+		jwe, err := lockContents(plaintext, &lockOpts{passphrase: samplePassPhrase})
+		require.NoError(t, err)
+
+		raw, err := json.Marshal(&encryptedWallet{
+			Type:              []string{verifiableCredentialType, encryptedWalletType},
+			CredentialSubject: encryptedWalletContents{EncryptedWalletContents: json.RawMessage(jwe)},
+		})
+		require.NoError(t, err)
+
+		err = walletInstance.Import(tkn, raw, WithImportPassphrase(samplePassPhrase))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find collection with ID 'urn:uuid:unknown-collection'")
+
+		_, err = walletInstance.Get(Credential, sampleCredentialID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		require.NoError(t, walletInstance.Add(tkn, Credential, []byte(sampleUDCVC)))
+	})
+
+	t.Run("replace existing contents - failure restores existing content", func(t *testing.T) {
+		contentStore := walletInstance.contents.store
+		walletInstance.contents.store = &failingPutStore{Store: contentStore, failures: 1}
+
+		defer func() { walletInstance.contents.store = contentStore }()
+
+		err := walletInstance.Import(tkn, byPassphrase, WithImportPassphrase(samplePassPhrase),
+			WithImportConflict(ImportConflictReplace))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to import wallet content of type 'collection'")
+
+		collection, err := walletInstance.Get(Collection, "urn:uuid:collection-1")
+		require.NoError(t, err)
+		require.JSONEq(t, sampleCollection, string(collection))
+
+		members, err := walletInstance.GetAll(Metadata, WithCollection("urn:uuid:collection-1"))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+	})
+
+	t.Run("import wallet locked by secret lock service - success", func(t *testing.T) {
+		err := walletInstance.Import(tkn, bySecretLock, WithImportConflict(ImportConflictReplace),
+			WithImportSecretLockService(&noop.NoLock{}))
+		require.NoError(t, err)
+	})
 }
 
 func TestWallet_Add(t *testing.T) {
@@ -803,6 +1132,340 @@ func TestWallet_Query(t *testing.T) {
 	})
 }
 
+func TestWallet_QueryByCollection(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+	require.NoError(t, err)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, walletInstance)
+	require.NoError(t, err)
+
+	const (
+		collectionID  = "did:example:collection:relying-party"
+		collectionFmt = `{"@context": ["https://w3id.org/wallet/v1"], "id": "%s", "type": "Collection"}`
+	)
+
+	require.NoError(t, walletInstance.Add(sampleFakeTkn, Collection, []byte(fmt.Sprintf(collectionFmt, collectionID))))
+
+	addCredential := func(id string, options ...AddContentOptions) {
+		vc, err := (&verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      id,
+			Issued:  util.NewTime(time.Now()),
+			Issuer:  verifiable.Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+			Subject: "did:example:ebfeb1f712ebc6f1c276e12ec21",
+		}).MarshalJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential, vc, options...))
+	}
+
+	addCredential("http://example.edu/credentials/1001", AddByCollection(collectionID))
+	addCredential("http://example.edu/credentials/1002")
+
+	members, err := walletInstance.GetAll(Credential, WithCollection(collectionID))
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	query := []byte(fmt.Sprintf(`{"example": {"@context": [%q], "type": [%q]}}`, verifiable.ContextURI,
+		verifiable.VCType))
+
+	t.Run("query all credentials", func(t *testing.T) {
+		results, err := walletInstance.Query(&QueryParams{Type: "QueryByExample", Query: []json.RawMessage{query}})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Len(t, results[0].Credentials(), 2)
+	})
+
+	t.Run("query credentials of collection", func(t *testing.T) {
+		results, err := walletInstance.Query(&QueryParams{
+			Type: "QueryByExample", Query: []json.RawMessage{query}, CollectionID: collectionID,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Len(t, results[0].Credentials(), 1)
+		require.Equal(t, "http://example.edu/credentials/1001", results[0].Credentials()[0].(*verifiable.Credential).ID)
+	})
+
+	t.Run("query credentials of empty collection", func(t *testing.T) {
+		results, err := walletInstance.Query(&QueryParams{
+			Type: "QueryByExample", Query: []json.RawMessage{query}, CollectionID: "did:example:unknown",
+		})
+		require.True(t, errors.Is(err, ErrQueryNoResultFound))
+		require.Empty(t, results)
+	})
+
+	t.Run("query credentials of collection after adding existing credential", func(t *testing.T) {
+		const otherCollectionID = "did:example:collection:other"
+
+		require.NoError(t, walletInstance.Add(sampleFakeTkn, Collection,
+			[]byte(fmt.Sprintf(collectionFmt, otherCollectionID))))
+		require.NoError(t, walletInstance.AddToCollection(Credential, "http://example.edu/credentials/1002",
+			otherCollectionID))
+
+		results, err := walletInstance.Query(&QueryParams{
+			Type: "QueryByExample", Query: []json.RawMessage{query}, CollectionID: otherCollectionID,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Len(t, results[0].Credentials(), 1)
+		require.Equal(t, "http://example.edu/credentials/1002", results[0].Credentials()[0].(*verifiable.Credential).ID)
+
+		err = walletInstance.AddToCollection(Credential, "http://example.edu/credentials/1002", "did:example:unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find collection")
+	})
+
+	t.Run("query of unregistered collection is empty", func(t *testing.T) {
+		vcs, err := walletInstance.GetAll(Credential)
+		require.NoError(t, err)
+
+		results, err := NewQuery(nil, &QueryParams{
+			Type: "QueryByExample", Query: []json.RawMessage{query}, CollectionID: collectionID,
+		}).PerformQuery(vcs)
+		require.True(t, errors.Is(err, ErrQueryNoResultFound))
+		require.Empty(t, results)
+	})
+}
+
+func TestWallet_MatchSubmissionRequirement(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+	require.NoError(t, err)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, walletInstance)
+	require.NoError(t, err)
+
+	createVC := func(id string, fields map[string]interface{}) []byte {
+		vcBytes, e := (&verifiable.Credential{
+			Context: []string{verifiable.ContextURI},
+			Types:   []string{verifiable.VCType},
+			ID:      id,
+			Schemas: []verifiable.TypedID{{
+				ID:   schemaURI,
+				Type: "JsonSchemaValidator2018",
+			}},
+			CustomFields: fields,
+			Issued: &util.TimeWithTrailingZeroMsec{
+				Time: time.Now(),
+			},
+			Issuer: verifiable.Issuer{
+				ID: "did:example:76e12ec712ebc6f1c221ebfeb1f",
+			},
+			Subject: uuid.New().String(),
+		}).MarshalJSON()
+		require.NoError(t, e)
+
+		return vcBytes
+	}
+
+	require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential,
+		createVC("http://example.edu/credentials/9997", map[string]interface{}{"first_name": "Jesse"})))
+	require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential,
+		createVC("http://example.edu/credentials/9998", map[string]interface{}{"first_name": "Jane"})))
+	require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential,
+		createVC("http://example.edu/credentials/9999", map[string]interface{}{"last_name": "Doe"})))
+
+	pd := &presexch.PresentationDefinition{
+		ID: uuid.New().String(),
+		SubmissionRequirements: []*presexch.SubmissionRequirement{{
+			Name: "names",
+			Rule: presexch.Pick,
+			Min:  1,
+			From: "A",
+		}},
+		InputDescriptors: []*presexch.InputDescriptor{{
+			ID:    "first-name",
+			Group: []string{"A"},
+			Schema: []*presexch.Schema{{
+				URI: schemaURI,
+			}},
+			Constraints: &presexch.Constraints{
+				Fields: []*presexch.Field{{
+					Path: []string{"$.first_name"},
+				}},
+			},
+		}, {
+			ID:    "middle-name",
+			Group: []string{"A"},
+			Schema: []*presexch.Schema{{
+				URI: schemaURI,
+			}},
+			Constraints: &presexch.Constraints{
+				Fields: []*presexch.Field{{
+					Path: []string{"$.middle_name"},
+				}},
+			},
+		}},
+	}
+
+	pdJSON, err := json.Marshal(pd)
+	require.NoError(t, err)
+
+	t.Run("test match submission requirement - success", func(t *testing.T) {
+		requirements, err := walletInstance.MatchSubmissionRequirement(pdJSON)
+		require.NoError(t, err)
+		require.Len(t, requirements, 1)
+		require.Equal(t, "names", requirements[0].Name)
+		require.True(t, requirements[0].Satisfied())
+		require.Len(t, requirements[0].Descriptors, 2)
+
+		require.Equal(t, "first-name", requirements[0].Descriptors[0].ID)
+		require.Len(t, requirements[0].Descriptors[0].MatchedVCs, 2)
+
+		ids := []string{
+			requirements[0].Descriptors[0].MatchedVCs[0].ID,
+			requirements[0].Descriptors[0].MatchedVCs[1].ID,
+		}
+		require.Contains(t, ids, "http://example.edu/credentials/9997")
+		require.Contains(t, ids, "http://example.edu/credentials/9998")
+
+		require.Equal(t, "middle-name", requirements[0].Descriptors[1].ID)
+		require.Empty(t, requirements[0].Descriptors[1].MatchedVCs)
+	})
+
+	t.Run("test match submission requirement - invalid definition", func(t *testing.T) {
+		requirements, err := walletInstance.MatchSubmissionRequirement([]byte("---"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse presentation definition")
+		require.Empty(t, requirements)
+
+		requirements, err = walletInstance.MatchSubmissionRequirement([]byte("{}"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "input_descriptors is required")
+		require.Empty(t, requirements)
+	})
+
+	t.Run("test match submission requirement - query error", func(t *testing.T) {
+		mockctxInvalid := newMockProvider()
+		sp := getMockStorageProvider()
+
+		sp.MockStoreProvider.Store.ErrQuery = errors.New(sampleContenttErr)
+		mockctxInvalid.StorageProviderValue = sp
+
+		err := CreateProfile(sampleUserID, mockctxInvalid, WithKeyServerURL(sampleKeyServerURL))
+		require.NoError(t, err)
+
+		walletInstanceInvalid, err := New(sampleUserID, mockctxInvalid)
+		require.NoError(t, err)
+
+		requirements, err := walletInstanceInvalid.MatchSubmissionRequirement(pdJSON)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to query credentials")
+		require.Empty(t, requirements)
+	})
+}
+
+func TestWallet_QueryMatch(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, WithKeyServerURL(sampleKeyServerURL))
+	require.NoError(t, err)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, walletInstance)
+	require.NoError(t, err)
+
+	const collectionID = "did:example:collection:relying-party"
+
+	require.NoError(t, walletInstance.Add(sampleFakeTkn, Collection, []byte(fmt.Sprintf(
+		`{"@context": ["https://w3id.org/wallet/v1"], "id": "%s", "type": "Collection"}`, collectionID))))
+
+	addCredential := func(id, firstName string, options ...AddContentOptions) {
+		vcBytes, e := (&verifiable.Credential{
+			Context:      []string{verifiable.ContextURI},
+			Types:        []string{verifiable.VCType},
+			ID:           id,
+			Schemas:      []verifiable.TypedID{{ID: schemaURI, Type: "JsonSchemaValidator2018"}},
+			CustomFields: map[string]interface{}{"first_name": firstName},
+			Issued:       util.NewTime(time.Now()),
+			Issuer:       verifiable.Issuer{ID: "did:example:76e12ec712ebc6f1c221ebfeb1f"},
+			Subject:      uuid.New().String(),
+		}).MarshalJSON()
+		require.NoError(t, e)
+
+		require.NoError(t, walletInstance.Add(sampleFakeTkn, Credential, vcBytes, options...))
+	}
+
+	addCredential("http://example.edu/credentials/9997", "Jesse", AddByCollection(collectionID))
+	addCredential("http://example.edu/credentials/9998", "Jane")
+
+	pdJSON, err := json.Marshal(&presexch.PresentationDefinition{
+		ID: "first-name-definition",
+		InputDescriptors: []*presexch.InputDescriptor{{
+			ID:          "first-name",
+			Schema:      []*presexch.Schema{{URI: schemaURI}},
+			Constraints: &presexch.Constraints{Fields: []*presexch.Field{{Path: []string{"$.first_name"}}}},
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("test query match - success", func(t *testing.T) {
+		matches, err := walletInstance.QueryMatch(&QueryParams{
+			Type:  "PresentationExchange",
+			Query: []json.RawMessage{pdJSON},
+		}, &QueryParams{
+			Type:         "PresentationExchange",
+			Query:        []json.RawMessage{pdJSON},
+			CollectionID: collectionID,
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+
+		require.Equal(t, "first-name-definition", matches[0].DefinitionID)
+		require.Len(t, matches[0].Requirements, 1)
+		require.True(t, matches[0].Requirements[0].Satisfied())
+		require.Len(t, matches[0].Requirements[0].Descriptors, 1)
+		require.Equal(t, "first-name", matches[0].Requirements[0].Descriptors[0].ID)
+		require.Len(t, matches[0].Requirements[0].Descriptors[0].MatchedVCs, 2)
+
+		// the second query is limited to the credentials of the collection.
+		require.Len(t, matches[1].Requirements[0].Descriptors[0].MatchedVCs, 1)
+		require.Equal(t, "http://example.edu/credentials/9997",
+			matches[1].Requirements[0].Descriptors[0].MatchedVCs[0].ID)
+	})
+
+	t.Run("test query match - not satisfied", func(t *testing.T) {
+		matches, err := walletInstance.QueryMatch(&QueryParams{
+			Type:         "PresentationExchange",
+			Query:        []json.RawMessage{pdJSON},
+			CollectionID: "did:example:unknown",
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.False(t, matches[0].Requirements[0].Satisfied())
+		require.Empty(t, matches[0].Requirements[0].Descriptors[0].MatchedVCs)
+	})
+
+	t.Run("test query match - failure", func(t *testing.T) {
+		matches, err := walletInstance.QueryMatch(&QueryParams{
+			Type:  "QueryByExample",
+			Query: []json.RawMessage{pdJSON},
+		})
+		require.EqualError(t, err, "unsupported query type 'QueryByExample' for matching, "+
+			"supported type - PresentationExchange")
+		require.Empty(t, matches)
+
+		matches, err = walletInstance.QueryMatch(&QueryParams{Type: "invalid"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported query type")
+		require.Empty(t, matches)
+
+		matches, err = walletInstance.QueryMatch(&QueryParams{
+			Type:  "PresentationExchange",
+			Query: []json.RawMessage{[]byte("---")},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse presentation definition")
+		require.Empty(t, matches)
+	})
+}
+
 func TestWallet_Issue(t *testing.T) {
 	customVDR := &mockvdr.MockVDRegistry{
 		ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
@@ -1705,6 +2368,48 @@ func TestWallet_Verify(t *testing.T) {
 	})
 }
 
+func TestWallet_VerificationReport(t *testing.T) {
+	mockctx := newMockProvider()
+
+	err := CreateProfile(sampleUserID, mockctx, WithPassphrase(samplePassPhrase))
+	require.NoError(t, err)
+
+	walletInstance, err := New(sampleUserID, mockctx)
+	require.NotEmpty(t, walletInstance)
+	require.NoError(t, err)
+
+	t.Run("Test VC wallet verification report - malformed credential", func(t *testing.T) {
+		report, err := walletInstance.VerificationReport(WithRawCredentialToVerify([]byte(`{"id":`)))
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.False(t, report.Verified)
+		require.Error(t, report.Err())
+		require.Equal(t, verifiable.SyntaxCheck, report.Checks[0].Check)
+	})
+
+	t.Run("Test VC wallet verification report - malformed presentation", func(t *testing.T) {
+		report, err := walletInstance.VerificationReport(WithRawPresentationToVerify([]byte(`{"id":`)))
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.False(t, report.Verified)
+		require.Equal(t, verifiable.SyntaxCheck, report.Checks[0].Check)
+	})
+
+	t.Run("Test VC wallet verification report - invalid credential ID", func(t *testing.T) {
+		report, err := walletInstance.VerificationReport(WithStoredCredentialToVerify("invalid-ID"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get credential")
+		require.Nil(t, report)
+	})
+
+	t.Run("Test VC wallet verification report - invalid request", func(t *testing.T) {
+		report, err := walletInstance.VerificationReport(WithStoredCredentialToVerify(""))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid verify request")
+		require.Nil(t, report)
+	})
+}
+
 func TestWallet_Derive(t *testing.T) {
 	customVDR := &mockvdr.MockVDRegistry{
 		ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
@@ -1887,6 +2592,22 @@ func TestWallet_Derive(t *testing.T) {
 	})
 }
 
+// failingPutStore fails given number of Put calls.
+type failingPutStore struct {
+	storage.Store
+	failures int
+}
+
+func (s *failingPutStore) Put(key string, value []byte, tags ...storage.Tag) error {
+	if s.failures > 0 {
+		s.failures--
+
+		return errors.New("put error")
+	}
+
+	return s.Store.Put(key, value, tags...)
+}
+
 func newMockProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{StorageProviderValue: mockstorage.NewMockStoreProvider()}
 }